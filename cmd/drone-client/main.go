@@ -4,6 +4,7 @@ import (
 	"context"
 	"embed"
 	"flag"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -17,8 +18,10 @@ import (
 	"github.com/the-hive/internal/drone"
 	"github.com/the-hive/internal/drone/events"
 	"github.com/the-hive/internal/drone/heartbeat"
+	"github.com/the-hive/internal/drone/notify"
 	"github.com/the-hive/internal/drone/watcher"
 	"github.com/the-hive/internal/drone/web"
+	"github.com/the-hive/internal/parser"
 	wsclient "github.com/the-hive/internal/drone/websocket"
 )
 
@@ -35,6 +38,10 @@ var (
 func main() {
 	flag.Parse()
 
+	// Keep recent log lines so the web UI can serve them at /api/logs
+	logBuffer := events.NewLogBuffer(events.DefaultLogLines)
+	log.SetOutput(io.MultiWriter(os.Stderr, logBuffer))
+
 	// Load configuration
 	config, err := drone.LoadConfig(*configPath)
 	if err != nil {
@@ -66,6 +73,7 @@ func main() {
 
 	// Create event broadcaster for SSE
 	eventBroadcaster := events.NewBroadcaster()
+	eventBroadcaster.SetBufferSize(config.EventBufferSize)
 
 	// Get config directory for database
 	configDir := ""
@@ -85,6 +93,18 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to initialize watcher manager: %v", err)
 	}
+	watcherMgr.SetDetectMoves(config.DetectMoves)
+	watcherMgr.SetMaxWatchedDirs(config.MaxWatchedDirs)
+	watcherMgr.SetMaxFileSize(config.MaxFileSize)
+	watcherMgr.SetPolling(config.PollPaths, config.PollInterval)
+	watcherMgr.SetChunker(parser.NewChunkerWithOptions(parser.ChunkOptions{
+		MaxTokens:       config.Chunking.MaxTokens,
+		OverlapTokens:   config.Chunking.OverlapTokens,
+		SplitOnSentence: config.Chunking.SplitOnSentence,
+	}))
+	if err := watcherMgr.SetChunkStrategies(config.Chunking.Strategies); err != nil {
+		log.Fatalf("Invalid chunking.strategies: %v", err)
+	}
 
 	// Start file watcher
 	if err := watcherMgr.Start(ctx); err != nil {
@@ -95,6 +115,12 @@ func main() {
 		watcherMgr.Stop() // This will close the database
 	}()
 
+	// Desktop notifications for rule matches are capped per minute; the UI still gets every one
+	osNotifier := notify.NewThrottler(notify.NotifierFunc(func(title, message string) error {
+		return beeep.Alert(title, message, "")
+	}), config.OSNotificationsPerMinute)
+	defer osNotifier.Stop()
+
 	// Initialize WebSocket client if server address is configured
 	var wsClient *wsclient.Client
 	if config.Server.Address != "" {
@@ -117,7 +143,7 @@ func main() {
 			if notification.Type == "ALERT" {
 				title := "The Hive - Rule Match"
 				message := notification.Message
-				if err := beeep.Alert(title, message, ""); err != nil {
+				if err := osNotifier.Notify(title, message); err != nil {
 					log.Printf("Failed to send OS notification: %v", err)
 				}
 			}
@@ -153,6 +179,8 @@ func main() {
 
 	// Initialize web server
 	webServer := web.NewServer(config, watcherMgr, eventBroadcaster, uiFiles)
+	webServer.SetOSNotifier(osNotifier)
+	webServer.SetLogBuffer(logBuffer)
 
 	// Start web server
 	httpServer := &http.Server{
@@ -13,6 +13,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -23,11 +24,15 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/the-hive/internal/ai"
 	"github.com/the-hive/internal/config"
 	"github.com/the-hive/internal/database"
 	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/grpclimits"
 	"github.com/the-hive/internal/jobs"
 	"github.com/the-hive/internal/logger"
+	"github.com/the-hive/internal/pii"
+	"github.com/the-hive/internal/processor"
 	"github.com/the-hive/internal/proto"
 	"github.com/the-hive/internal/queue"
 	"github.com/the-hive/internal/rules"
@@ -44,6 +49,7 @@ var (
 	templateDir = flag.String("template-dir", "./internal/server/templates", "Template directory")
 	staticDir   = flag.String("static-dir", "./frontend/static", "Static assets directory")
 	workerCount = flag.Int("worker-count", 5, "Number of background workers")
+	selfTest    = flag.Bool("selftest", false, "Ingest and search a known document on a temporary database, then exit (nonzero on failure)")
 )
 
 func main() {
@@ -130,24 +136,32 @@ func main() {
 
 	flag.Parse()
 
-	db, err := sql.Open("sqlite3", *dbPath)
-	if err != nil {
-		logger.Fatalf("failed to open sqlite database: %v", err)
+	if *selfTest {
+		if err := runSelfTest(context.Background(), logger.Printf); err != nil {
+			logger.Printf("Self-test FAILED: %v", err)
+			fmt.Fprintf(os.Stderr, "self-test failed: %v\n", err)
+			os.Exit(1)
+		}
+		logger.Printf("Self-test passed")
+		fmt.Println("self-test passed")
+		return
 	}
-	defer db.Close()
 
-	// Enable WAL mode for concurrent read/write access
-	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
-		logger.Fatalf("failed to enable WAL mode: %v", err)
+	// All stores share one handle; pragmas are applied per connection via the DSN
+	dbOptions := database.DefaultSQLiteOptions()
+	if v := os.Getenv("DB_MAX_OPEN_CONNS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			dbOptions.MaxOpenConns = n
+		} else {
+			logger.Printf("Invalid DB_MAX_OPEN_CONNS %q, using default %d", v, dbOptions.MaxOpenConns)
+		}
 	}
-	logger.Printf("SQLite WAL mode enabled")
-
-	// Set busy timeout to prevent indefinite hanging (10 seconds)
-	if _, err := db.Exec("PRAGMA busy_timeout=10000"); err != nil {
-		logger.Fatalf("failed to set busy timeout: %v", err)
+	db, err := database.OpenSQLite(*dbPath, dbOptions)
+	if err != nil {
+		logger.Fatalf("failed to open sqlite database: %v", err)
 	}
-	logger.Printf("SQLite busy timeout set to 10000ms")
-	logger.Printf("SQLite busy timeout set to 5000ms")
+	defer db.Close()
+	logger.Printf("SQLite opened (WAL mode, busy timeout %s, max %d connections)", dbOptions.BusyTimeout, dbOptions.MaxOpenConns)
 
 	if err := initDatabase(db); err != nil {
 		logger.Fatalf("failed to initialize schema: %v", err)
@@ -182,6 +196,14 @@ func main() {
 	if err != nil {
 		logger.Fatalf("failed to initialize rules store: %v", err)
 	}
+	// RULES_CACHE_TTL reloads cached rules periodically, for rule changes made by other server instances
+	if ttlStr := os.Getenv("RULES_CACHE_TTL"); ttlStr != "" {
+		if ttl, err := time.ParseDuration(ttlStr); err == nil && ttl >= 0 {
+			ruleStore.SetCacheTTL(ttl)
+		} else {
+			logger.Printf("Invalid RULES_CACHE_TTL %q, caching rules until they change", ttlStr)
+		}
+	}
 
 	// Initialize system metadata store (for install_date, license_key, etc.)
 	metadataStore, err := database.NewSystemMetadataStore(db)
@@ -221,6 +243,18 @@ func main() {
 	}
 	logger.Printf("Chat store initialized")
 
+	// Initialize chat retention store (per-org retention policies and pinned sessions)
+	chatRetentionStore, err := database.NewChatRetentionStore(db)
+	if err != nil {
+		logger.Fatalf("failed to initialize chat retention store: %v", err)
+	}
+
+	// Initialize chat feedback store (thumbs up/down on assistant answers)
+	chatFeedbackStore, err := database.NewChatFeedbackStore(db)
+	if err != nil {
+		logger.Fatalf("failed to initialize chat feedback store: %v", err)
+	}
+
 	// Initialize usage store (for token usage tracking)
 	usageStore, err := database.NewUsageStore(db)
 	if err != nil {
@@ -235,6 +269,77 @@ func main() {
 	}
 	logger.Printf("Custom domain store initialized")
 
+	// Initialize saved search store (for org-scoped saved queries)
+	savedSearchStore, err := database.NewSavedSearchStore(db)
+	if err != nil {
+		logger.Fatalf("failed to initialize saved search store: %v", err)
+	}
+	logger.Printf("Saved search store initialized")
+
+	// Initialize PII policy store (per-org off/alert/mask setting)
+	piiPolicyStore, err := database.NewPIIPolicyStore(db)
+	if err != nil {
+		logger.Fatalf("failed to initialize PII policy store: %v", err)
+	}
+
+	// Initialize password flag store (users who must change a temporary password)
+	passwordFlagStore, err := database.NewPasswordFlagStore(db)
+	if err != nil {
+		logger.Fatalf("failed to initialize password flag store: %v", err)
+	}
+	server.SetPasswordFlagStore(passwordFlagStore)
+
+	// Initialize invite store (pending invites redeemed via a one-time link)
+	inviteStore, err := database.NewInviteStore(db)
+	if err != nil {
+		logger.Fatalf("failed to initialize invite store: %v", err)
+	}
+
+	// DEFAULT_USER_ROLE is assigned to users created or invited without a role (admin, editor or viewer)
+	if roleStr := os.Getenv("DEFAULT_USER_ROLE"); roleStr != "" {
+		if err := server.SetDefaultUserRole(database.UserRole(roleStr)); err != nil {
+			log.Printf("Invalid DEFAULT_USER_ROLE %q, using default %s", roleStr, database.RoleViewer)
+		}
+	}
+
+	// Initialize analysis sampling store (per-org 1-in-N / filetype / metadata sampling for AI analysis)
+	samplingStore, err := database.NewAnalysisSamplingStore(db)
+	if err != nil {
+		logger.Fatalf("failed to initialize analysis sampling store: %v", err)
+	}
+
+	// Initialize grounding policy store (per-org context-only answers and answer verification)
+	groundingStore, err := database.NewGroundingPolicyStore(db)
+	if err != nil {
+		logger.Fatalf("failed to initialize grounding policy store: %v", err)
+	}
+
+	// Initialize document summary store (ingested documents with optional AI summaries)
+	documentSummaryStore, err := database.NewDocumentSummaryStore(db)
+	if err != nil {
+		logger.Fatalf("failed to initialize document summary store: %v", err)
+	}
+	documentSummarizer := server.NewDocumentSummarizer(documentSummaryStore)
+	// Summaries cost a model call per document, so they're opt-in via DOCUMENT_SUMMARIES_ENABLED
+	if enabled, _ := strconv.ParseBool(os.Getenv("DOCUMENT_SUMMARIES_ENABLED")); enabled {
+		if os.Getenv("OPENAI_API_KEY") != "" {
+			documentSummarizer.SetGenerator(func(ctx context.Context, systemPrompt, prompt string) (string, error) {
+				summary, _, err := ai.Complete(ctx, systemPrompt, prompt, 300)
+				return summary, err
+			})
+			logger.Printf("Document summaries enabled")
+		} else {
+			logger.Warnf("DOCUMENT_SUMMARIES_ENABLED is set but OPENAI_API_KEY is not, document summaries disabled")
+		}
+	}
+	if minCharsStr := os.Getenv("DOCUMENT_SUMMARY_MIN_CHARS"); minCharsStr != "" {
+		if minChars, err := strconv.Atoi(minCharsStr); err == nil && minChars >= 0 {
+			documentSummarizer.SetMinChars(minChars)
+		} else {
+			logger.Warnf("Invalid DOCUMENT_SUMMARY_MIN_CHARS %q, using default %d", minCharsStr, server.DefaultSummaryMinChars)
+		}
+	}
+
 	// Bootstrap default admin user if no users exist (assign to default org)
 	adminCreated, err := userStore.BootstrapAdmin(defaultOrg.ID)
 	if err != nil {
@@ -252,8 +357,19 @@ func main() {
 		logger.Printf("User store initialized (existing users found)")
 	}
 
+	// Initialize embedder (after .env is loaded)
+	embedder := initEmbedder()
+
 	// Connect to Qdrant via gRPC (optional - will use mock if unavailable)
 	var vectorDB vectordb.VectorDB
+	qdrantConfig, err := qdrantConfigFromEnv()
+	if err != nil {
+		logger.Fatalf("invalid Qdrant configuration: %v", err)
+	}
+	if os.Getenv("QDRANT_DIMENSION") == "" {
+		// A new collection is sized for the embedder's vectors
+		qdrantConfig.DefaultDimension = embedder.Dimension()
+	}
 	qdrantConn, err := grpc.Dial("localhost:6334", grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		log.Printf("warning: failed to connect to Qdrant: %v, using mock vector DB", err)
@@ -264,19 +380,48 @@ func main() {
 		// Create Qdrant client (kept for compatibility, but vectordb uses connection directly)
 		_ = qdrant.NewQdrantClient(qdrantConn)
 
-		var vdbErr error
-		vectorDB, vdbErr = vectordb.NewQdrantVectorDB(qdrantConn)
+		qdrantDB, vdbErr := vectordb.NewQdrantVectorDB(qdrantConn, qdrantConfig)
 		if vdbErr != nil {
 			log.Printf("warning: failed to init vector db: %v, using mock vector DB", vdbErr)
 			log.Printf("UI-only mode: Search functionality will be disabled")
 			vectorDB = vectordb.NewMockVectorDB()
 		} else {
-			log.Printf("Connected to Qdrant successfully")
+			log.Printf("Connected to Qdrant successfully (collection %s, %s distance)", qdrantConfig.CollectionName, qdrantConfig.Distance)
+			// Vectors of another size would be rejected on every ingest; stop before anything is half-stored
+			logger.Printf("Embedder dimension %d, Qdrant collection %s dimension %d", embedder.Dimension(), qdrantDB.CollectionName(), qdrantDB.Dimension())
+			if embedder.Dimension() != qdrantDB.Dimension() {
+				logger.Fatalf("embedder produces %d-dimension vectors but Qdrant collection %s stores %d; switch back to the original embedder or set QDRANT_COLLECTION to a new collection (then re-ingest)",
+					embedder.Dimension(), qdrantDB.CollectionName(), qdrantDB.Dimension())
+			}
+			if qdrantDB.RequiresOrganization() {
+				log.Printf("Strict organization mode: searches without an organization ID will be rejected")
+			}
+			// PAYLOAD_CONTENT_PREVIEW_CHARS keeps chunk content only in SQLite, with a short preview in the payload
+			if previewChars := vectordb.PayloadPreviewCharsFromEnv(); previewChars > 0 {
+				qdrantDB.SetContentStore(server.NewChunkContentStore(db), previewChars)
+				log.Printf("Chunk content stored in SQLite only; payloads keep a %d-character preview", previewChars)
+			}
+			vectorDB = qdrantDB
 		}
 	}
 
-	// Initialize embedder (after .env is loaded)
-	embedder := initEmbedder()
+	// EMBED_MAX_INPUT_TOKENS truncates over-long embedding inputs (estimated tokens); truncated chunks are flagged
+	if tokensStr := os.Getenv("EMBED_MAX_INPUT_TOKENS"); tokensStr != "" {
+		if tokens, err := strconv.Atoi(tokensStr); err == nil && tokens > 0 {
+			embeddings.SetMaxInputTokens(tokens)
+		} else {
+			log.Printf("Invalid EMBED_MAX_INPUT_TOKENS %q, using default %d", tokensStr, embeddings.DefaultMaxInputTokens)
+		}
+	}
+
+	// AI_MAX_CONCURRENT_CALLS bounds provider calls in flight across analyst, tagger and chat (0 = unlimited)
+	if callsStr := os.Getenv("AI_MAX_CONCURRENT_CALLS"); callsStr != "" {
+		if calls, err := strconv.Atoi(callsStr); err == nil && calls >= 0 {
+			ai.SetMaxConcurrentCalls(calls)
+		} else {
+			log.Printf("Invalid AI_MAX_CONCURRENT_CALLS %q, using default %d", callsStr, ai.DefaultMaxConcurrentCalls)
+		}
+	}
 
 	// Initialize Redis and job queue
 	ctx := context.Background()
@@ -286,23 +431,114 @@ func main() {
 	}
 	redisClient, err := config.NewRedisClient(ctx)
 	if err != nil {
-		logger.Warnf("failed to connect to Redis at %s: %v, job queue will not be available", redisURL, err)
+		logger.Warnf("failed to connect to Redis at %s: %v", redisURL, err)
 		redisClient = nil
 	} else {
 		logger.Printf("Connected to Redis at %s", redisURL)
 	}
 
+	// Initialize job status store (state and progress of long-running admin jobs)
+	jobStatusStore, err := database.NewJobStatusStore(db)
+	if err != nil {
+		logger.Fatalf("failed to initialize job status store: %v", err)
+	}
+
+	// Re-chunks an organization's documents with the current CHUNK_SIZE/CHUNK_OVERLAP (runs on the job queue)
+	rechunker := jobs.NewRechunker(db, vectorDB, embedder, processor.NewChunkerFromEnv())
+	rechunker.SetStatusTracker(jobStatusStore)
+
+	// Chat answers are reused until an ingest, purge or rechunk changes the organization's documents
+	answerCache := chatAnswerCache()
+	if answerCache != nil {
+		rechunker.SetChangeHook(answerCache.Invalidate)
+	}
+
+	// Initialize tagging worker pool; TAG_VOCABULARY (comma-separated) restricts the tags it may assign
+	// Dropped, failed and unknown jobs from every pool are logged as JSON, counted and (once the
+	// rule event store exists) stored as rule events
+	jobFailures := worker.NewJobFailureRecorder()
+
+	taggerPool := worker.NewTaggerPool(2) // 2 workers for tagging
+	taggerPool.SetFailureRecorder(jobFailures)
+	if vocabulary := os.Getenv("TAG_VOCABULARY"); vocabulary != "" {
+		taggerPool.SetVocabulary(strings.Split(vocabulary, ","))
+		logger.Printf("Tag vocabulary: %v", taggerPool.Vocabulary())
+	}
+	taggerPool.Start()
+	defer taggerPool.Stop()
+
+	// Repairs mismatches between SQLite chunks and Qdrant points (runs on the job queue)
+	reconciler := jobs.NewReconciler(db, vectorDB, embedder)
+	reconciler.SetStatusTracker(jobStatusStore)
+	if answerCache != nil {
+		reconciler.SetChangeHook(answerCache.Invalidate)
+	}
+
+	// Re-tags an organization's documents with the current tag vocabulary (runs on the job queue)
+	retagger := jobs.NewRetagger(db, vectorDB, taggerPool)
+	retagger.SetStatusTracker(jobStatusStore)
+
+	// Checkpoints the WAL and vacuums the SQLite file when it is idle and fragmented
+	dbMaintainer := jobs.NewSQLiteMaintainer(db, *dbPath)
+	checkpointInterval, _ := time.ParseDuration(os.Getenv("DB_CHECKPOINT_INTERVAL"))
+	vacuumInterval, _ := time.ParseDuration(os.Getenv("DB_VACUUM_INTERVAL"))
+	dbMaintainer.SetIntervals(checkpointInterval, vacuumInterval)
+	maintenanceCtx, maintenanceCancel := context.WithCancel(ctx)
+	defer maintenanceCancel()
+	dbMaintainer.Start(maintenanceCtx)
+
+	// Deletes unpinned chat sessions past each organization's retention (CHAT_RETENTION_INTERVAL, default daily)
+	chatPruner := jobs.NewChatRetentionPruner(chatRetentionStore)
+	chatRetentionInterval, _ := time.ParseDuration(os.Getenv("CHAT_RETENTION_INTERVAL"))
+	chatPruner.SetInterval(chatRetentionInterval)
+	chatPruner.Start(maintenanceCtx)
+
 	var jobQueue queue.Queue
 	var workerCancel context.CancelFunc
-	if redisClient != nil {
+	var workersDone <-chan struct{} // Closed once workers have finished their in-flight jobs
+	var webhookIngester *server.WebhookIngester // Set once the hive service exists
+	var reconnectingQueue *queue.ReconnectingQueue
+	// REDIS_RECONNECT_INTERVAL (default 30s) is how often the job queue retries Redis when it isn't up
+	// at startup; "0" keeps the old behavior of running without a queue until restarted
+	reconnectInterval := queue.DefaultReconnectInterval
+	if v := os.Getenv("REDIS_RECONNECT_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			logger.Fatalf("invalid REDIS_RECONNECT_INTERVAL %q", v)
+		}
+		reconnectInterval = d
+	}
+	if redisClient != nil || reconnectInterval > 0 {
 		queueKey := os.Getenv("JOB_QUEUE_KEY")
 		if queueKey == "" {
 			queueKey = "jobs:default"
 		}
-		jobQueue, err = queue.NewRedisQueue(redisClient, queueKey)
-		if err != nil {
-			logger.Fatalf("failed to create job queue: %v", err)
+		connectQueue := func(ctx context.Context) (*queue.RedisQueue, error) {
+			client := redisClient
+			if client == nil {
+				var err error
+				if client, err = config.NewRedisClient(ctx); err != nil {
+					return nil, err
+				}
+			}
+			redisQueue, err := queue.NewRedisQueue(client, queueKey)
+			if err != nil {
+				return nil, err
+			}
+			// Requeue jobs a previous run dequeued but never finished
+			if _, err := redisQueue.RecoverInFlight(ctx); err != nil {
+				logger.Errorf("failed to recover unfinished jobs: %v", err)
+			}
+			return redisQueue, nil
 		}
+		reconnectingQueue = queue.NewReconnectingQueue(connectQueue, reconnectInterval)
+		if !reconnectingQueue.Start(ctx) {
+			if reconnectInterval == 0 {
+				logger.Fatalf("failed to create job queue")
+			}
+			logger.Warnf("job queue unavailable, retrying Redis every %s; background workers start once it connects", reconnectInterval)
+		}
+		jobQueue = reconnectingQueue
 
 		// Start background workers
 		workerCtx, cancel := context.WithCancel(ctx)
@@ -313,22 +549,67 @@ func main() {
 			switch job.Type {
 			case jobs.JobTypeRecalcIssuePriority:
 				return jobs.HandleRecalcIssuePriority(ctx, job)
+			case jobs.JobTypeRechunkOrganization:
+				return rechunker.Handle(ctx, job)
+			case jobs.JobTypeRetagOrganization:
+				return retagger.Handle(ctx, job)
+			case jobs.JobTypeReconcileOrganization:
+				return reconciler.Handle(ctx, job)
+			case server.JobTypeWebhookIngest:
+				return webhookIngester.Handle(ctx, job)
 			default:
-				logger.Printf("unknown job type: %s", job.Type)
-				return nil
+				return fmt.Errorf("%w: %s", worker.ErrUnknownJobType, job.Type)
 			}
 		}
-
+		// Failing jobs are retried with backoff (JOB_MAX_ATTEMPTS, JOB_RETRY_BASE_DELAY), then pushed onto
+		// the jobs:dead list and recorded
+		handler = worker.RecordFailures(worker.RetryJobs(handler, jobQueue, worker.RetryPolicyFromEnv()), jobFailures)
+
+		// JOB_ORDERING=keyed runs jobs with the same key (e.g. the same issue or org) serially
+		keyedWorkers := os.Getenv("JOB_ORDERING") == "keyed"
+		done := make(chan struct{})
+		workersDone = done
 		go func() {
-			logger.Printf("Starting %d background workers", *workerCount)
-			if err := worker.StartWorkers(workerCtx, jobQueue, handler, *workerCount); err != nil {
+			defer close(done)
+			select {
+			case <-reconnectingQueue.Ready():
+			case <-workerCtx.Done():
+				return
+			}
+			logger.Printf("Starting %d background workers (keyed ordering: %v)", *workerCount, keyedWorkers)
+			var err error
+			if keyedWorkers {
+				err = worker.StartKeyedWorkers(workerCtx, jobQueue, handler, *workerCount, worker.JobKey)
+			} else {
+				err = worker.StartWorkers(workerCtx, jobQueue, handler, *workerCount)
+			}
+			if err != nil {
 				logger.Errorf("worker error: %v", err)
 			}
 		}()
 	}
 
+	// RECONCILE_INTERVAL queues a reconcile job for every organization on that schedule (requires Redis)
+	if reconcileInterval, _ := time.ParseDuration(os.Getenv("RECONCILE_INTERVAL")); reconcileInterval > 0 {
+		if jobQueue != nil {
+			reconciler.Start(maintenanceCtx, jobQueue, reconcileInterval)
+			logger.Printf("Reconciling chunks with vectors every %s", reconcileInterval)
+		} else {
+			logger.Printf("warning: RECONCILE_INTERVAL is set but the job queue is unavailable (Redis required)")
+		}
+	}
+
 	// Initialize WebSocket manager (before hiveService so we can pass it)
 	wsManager := server.NewWebSocketManager(redisClient)
+	wsManager.SetEventLogger(eventLogger) // Records notifications that miss Redis
+	if redisClient == nil && reconnectingQueue != nil {
+		// Redis was down at startup: mailboxes use the queue's client once it connects
+		wsManager.SetRedisClientSource(reconnectingQueue.Client)
+	}
+	if maxConns, err := strconv.Atoi(os.Getenv("WS_MAX_CONNECTIONS_PER_ORG")); err == nil && maxConns > 0 {
+		wsManager.SetMaxConnectionsPerOrg(maxConns)
+		logger.Printf("WebSocket connections limited to %d per organization", maxConns)
+	}
 
 	// Initialize rule match store
 	ruleMatchStore, err := database.NewRuleMatchStore(db)
@@ -341,22 +622,123 @@ func main() {
 	if err != nil {
 		logger.Fatalf("failed to initialize rule event store: %v", err)
 	}
+	retagger.SetEventStore(ruleEventStore)
+	jobFailures.SetEventStore(ruleEventStore)
+
+	// Initialize notification routing (per-org severity -> feed/websocket/email/slack channels)
+	notificationRoutingStore, err := database.NewNotificationRoutingStore(db)
+	if err != nil {
+		logger.Fatalf("failed to initialize notification routing store: %v", err)
+	}
+	notificationRouter := server.NewNotificationRouter(notificationRoutingStore, &notificationAdapter{wm: wsManager})
+	notificationRouter.SetFeed(eventLogger)
+	if mailer, err := server.NewSMTPMailerFromEnv(); err != nil {
+		logger.Printf("Email notifications disabled: %v", err)
+	} else if mailer != nil {
+		notificationRouter.SetMailer(mailer)
+	}
 
 	// Initialize analyst worker pool
-	notificationAdapterImpl := &notificationAdapter{wm: wsManager}
-	analystPool := worker.NewAnalystPool(ruleStore, notificationAdapterImpl, graphStore, vectorDB, embedder, ruleMatchStore, ruleEventStore, 3)
+	analystPool := worker.NewAnalystPool(ruleStore, notificationRouter, graphStore, vectorDB, embedder, ruleMatchStore, ruleEventStore, 3)
+	analystPool.SetAuditLogger(auditLogStore)
+	analystPool.SetFailureRecorder(jobFailures)
+	analystPool.SetContradictionAlerts(worker.ContradictionAlertConfig{
+		Enabled:    os.Getenv("CONTRADICTION_ALERTS_ENABLED") == "true",
+		WebhookURL: os.Getenv("CONTRADICTION_WEBHOOK_URL"),
+	})
+	// SENSITIVE_KEYWORDS (comma-separated) replaces the default rule template's keywords as the keyword rules
+	// seeded for each organization; "none" disables seeding
+	if keywords := os.Getenv("SENSITIVE_KEYWORDS"); keywords != "" {
+		var seeded []string
+		if keywords != "none" {
+			for _, keyword := range strings.Split(keywords, ",") {
+				if keyword = strings.TrimSpace(keyword); keyword != "" {
+					seeded = append(seeded, keyword)
+				}
+			}
+		}
+		analystPool.SetSensitiveKeywords(seeded)
+	}
+	// ANALYST_CACHE_TTL reuses rule answers for identical content (default 24h, "0" disables)
+	if ttlStr := os.Getenv("ANALYST_CACHE_TTL"); ttlStr != "" {
+		if ttl, err := time.ParseDuration(ttlStr); err == nil && ttl >= 0 {
+			analystPool.SetAnalysisCacheTTL(ttl)
+		} else {
+			log.Printf("Invalid ANALYST_CACHE_TTL %q, using default %s", ttlStr, worker.DefaultAnalysisCacheTTL)
+		}
+	}
+	// ANALYST_CROSS_DOC_TARGETS and ANALYST_CROSS_DOC_MIN_SCORE limit which documents cross-document rules compare against
+	crossDocTargets := worker.DefaultCrossDocTargets
+	if targetsStr := os.Getenv("ANALYST_CROSS_DOC_TARGETS"); targetsStr != "" {
+		if targets, err := strconv.Atoi(targetsStr); err == nil && targets > 0 {
+			crossDocTargets = targets
+		} else {
+			log.Printf("Invalid ANALYST_CROSS_DOC_TARGETS %q, using default %d", targetsStr, worker.DefaultCrossDocTargets)
+		}
+	}
+	var crossDocMinScore float64
+	if scoreStr := os.Getenv("ANALYST_CROSS_DOC_MIN_SCORE"); scoreStr != "" {
+		if score, err := strconv.ParseFloat(scoreStr, 32); err == nil && score >= 0 {
+			crossDocMinScore = score
+		} else {
+			log.Printf("Invalid ANALYST_CROSS_DOC_MIN_SCORE %q, comparing every match", scoreStr)
+		}
+	}
+	analystPool.SetCrossDocScope(crossDocTargets, float32(crossDocMinScore))
+	// ANALYST_MAX_CHARS and ANALYST_MAX_SEGMENTS bound the prompt size and the AI calls per rule for large documents
+	maxAnalysisChars := worker.DefaultMaxAnalysisChars
+	if charsStr := os.Getenv("ANALYST_MAX_CHARS"); charsStr != "" {
+		if chars, err := strconv.Atoi(charsStr); err == nil && chars > 0 {
+			maxAnalysisChars = chars
+		} else {
+			log.Printf("Invalid ANALYST_MAX_CHARS %q, using default %d", charsStr, worker.DefaultMaxAnalysisChars)
+		}
+	}
+	maxAnalysisSegments := worker.DefaultMaxAnalysisSegments
+	if segmentsStr := os.Getenv("ANALYST_MAX_SEGMENTS"); segmentsStr != "" {
+		if segments, err := strconv.Atoi(segmentsStr); err == nil && segments > 0 {
+			maxAnalysisSegments = segments
+		} else {
+			log.Printf("Invalid ANALYST_MAX_SEGMENTS %q, using default %d", segmentsStr, worker.DefaultMaxAnalysisSegments)
+		}
+	}
+	analystPool.SetAnalysisLimits(maxAnalysisChars, maxAnalysisSegments)
+	// ANALYST_ENQUEUE_TIMEOUT is how long ingest waits for room in a full analyst queue before the job is dropped
+	if timeoutStr := os.Getenv("ANALYST_ENQUEUE_TIMEOUT"); timeoutStr != "" {
+		if timeout, err := time.ParseDuration(timeoutStr); err == nil && timeout >= 0 {
+			analystPool.SetEnqueueTimeout(timeout)
+		} else {
+			log.Printf("Invalid ANALYST_ENQUEUE_TIMEOUT %q, using default %s", timeoutStr, worker.DefaultAnalystEnqueueTimeout)
+		}
+	}
 	analystPool.Start()
 	defer analystPool.Stop()
 
-	// Initialize tagging worker pool
-	taggerPool := worker.NewTaggerPool(2) // 2 workers for tagging
-	taggerPool.Start()
-	defer taggerPool.Stop()
 
-	grpcServer := grpc.NewServer()
+	// Chunks larger than gRPC's 4MB default are accepted up to GRPC_MAX_MESSAGE_BYTES (default 16MB)
+	grpcServer := grpc.NewServer(grpclimits.ServerOptions(grpclimits.MaxMessageSize())...)
 	hiveService := server.NewHiveService(db, vectorDB, embedder)
 	hiveService.SetWebSocketManager(wsManager)
 	hiveService.SetAnalystPool(analystPool)
+
+	// PII scanning at ingest; PII_MODE is the default for orgs without a stored policy
+	piiDefaultMode, err := pii.ParseMode(os.Getenv("PII_MODE"))
+	if err != nil {
+		logger.Warnf("%v, PII scanning disabled by default", err)
+	}
+	piiGuard := server.NewPIIGuard(pii.NewScannerFromEnv(), piiPolicyStore, piiDefaultMode, ruleEventStore, wsManager)
+	hiveService.SetPIIGuard(piiGuard)
+	analysisSampler := server.NewAnalysisSampler(samplingStore, ruleEventStore)
+	hiveService.SetAnalysisSampler(analysisSampler)
+	hiveService.SetDocumentSummarizer(documentSummarizer)
+	hiveService.SetMaxTopK(searchMaxTopK())
+	hiveService.SetAnswerCache(answerCache)
+	hiveService.SetAuditLogStore(auditLogStore)
+	// INGEST_MAX_CONCURRENT_PER_ORG bounds each organization's concurrent ingests across HTTP, gRPC and webhooks
+	ingestLimiter := server.NewIngestLimiterFromEnv()
+	hiveService.SetIngestLimiter(ingestLimiter)
+	webhookIngester = server.NewWebhookIngester(hiveService)
+	webhookIngester.SetChunkerRegistry(chunkerRegistry())
 	proto.RegisterHiveServer(grpcServer, hiveService)
 
 	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", *grpcPort))
@@ -371,9 +753,18 @@ func main() {
 		}
 	}()
 
+	// Readiness probe: SQLite and the vector database always, and the job queue when there is one. The queue
+	// is required when Redis is configured (REDIS_URL or REDIS_ADDR); otherwise it is only reported.
+	readinessChecks := []server.ReadinessCheck{server.DatabaseReadinessCheck(db), server.VectorDBReadinessCheck(vectorDB)}
+	if reconnectingQueue != nil {
+		queueCheck := server.JobQueueReadinessCheck(reconnectingQueue)
+		queueCheck.Optional = os.Getenv("REDIS_URL") == "" && os.Getenv("REDIS_ADDR") == ""
+		readinessChecks = append(readinessChecks, queueCheck)
+	}
+
 	httpServer := &http.Server{
 		Addr:    fmt.Sprintf(":%d", *httpPort),
-		Handler: routes(db, vectorDB, embedder, jobQueue, wsManager, analystPool, taggerPool, ruleStore, eventLogger, graphStore, apiKeyStore, auditLogStore, metadataStore, ruleMatchStore, ruleEventStore, userStore, chatStore, orgStore, usageStore, domainStore, *templateDir, *staticDir),
+		Handler: routes(db, vectorDB, embedder, jobQueue, wsManager, analystPool, taggerPool, ruleStore, eventLogger, graphStore, apiKeyStore, auditLogStore, metadataStore, ruleMatchStore, ruleEventStore, userStore, chatStore, orgStore, usageStore, domainStore, savedSearchStore, rechunker, retagger, reconciler, piiPolicyStore, piiGuard, passwordFlagStore, samplingStore, analysisSampler, dbMaintainer, chatFeedbackStore, groundingStore, documentSummarizer, inviteStore, jobStatusStore, chatRetentionStore, answerCache, notificationRouter, jobFailures, readinessChecks, ingestLimiter, *templateDir, *staticDir),
 	}
 
 	go func() {
@@ -383,7 +774,7 @@ func main() {
 		}
 	}()
 
-	waitForShutdown(grpcServer, httpServer, workerCancel)
+	waitForShutdown(grpcServer, httpServer, workerCancel, workersDone)
 }
 
 // initEmbedder initializes the embedder after .env is loaded
@@ -405,16 +796,131 @@ func initEmbedder() embeddings.Embedder {
 		"model":     os.Getenv("EMBEDDER_MODEL"),
 		"base_url":  os.Getenv("OLLAMA_BASE_URL"),
 		"dimension": os.Getenv("EMBEDDER_DIMENSION"),
+		"seed":      os.Getenv("EMBEDDER_MOCK_SEED"), // mock only: deterministic token-hashing vectors
 	}
 
-	embedder, err := embeddings.NewEmbedder(embedderType, embedderConfig)
+	// EMBEDDER_FALLBACKS (comma-separated "type" or "type:model", e.g. "ollama:nomic-embed-text") are tried in
+	// order when the primary fails; each must embed with the primary's model family
+	embedderTypes := []string{embedderType}
+	for _, fallback := range strings.Split(os.Getenv("EMBEDDER_FALLBACKS"), ",") {
+		if fallback = strings.TrimSpace(fallback); fallback != "" {
+			embedderTypes = append(embedderTypes, fallback)
+		}
+	}
+
+	embedder, err := embeddings.NewEmbedderChain(embedderTypes, embedderConfig)
 	if err != nil {
 		logger.Fatalf("failed to initialize embedder: %v", err)
 	}
-	logger.Printf("Initialized embedder: %s (dimension: %d)", embedderType, embedder.Dimension())
+	if chain, ok := embedder.(*embeddings.FallbackEmbedder); ok {
+		logger.Printf("Initialized embedder chain: %s (dimension: %d)", strings.Join(chain.Providers(), " -> "), embedder.Dimension())
+	} else {
+		logger.Printf("Initialized embedder: %s (dimension: %d)", embedderType, embedder.Dimension())
+	}
 	return embedder
 }
 
+// qdrantConfigFromEnv reads the Qdrant collection settings from QDRANT_COLLECTION, QDRANT_DISTANCE and
+// QDRANT_DIMENSION, failing on an unknown distance or invalid dimension rather than creating the wrong collection
+func qdrantConfigFromEnv() (vectordb.QdrantConfig, error) {
+	cfg := vectordb.DefaultQdrantConfig()
+	if name := strings.TrimSpace(os.Getenv("QDRANT_COLLECTION")); name != "" {
+		cfg.CollectionName = name
+	}
+	if distance := os.Getenv("QDRANT_DISTANCE"); distance != "" {
+		if _, err := vectordb.ParseDistance(distance); err != nil {
+			return cfg, fmt.Errorf("QDRANT_DISTANCE: %w", err)
+		}
+		cfg.Distance = strings.ToLower(strings.TrimSpace(distance))
+	}
+	if v := os.Getenv("QDRANT_DIMENSION"); v != "" {
+		dim, err := strconv.Atoi(v)
+		if err != nil || dim <= 0 {
+			return cfg, fmt.Errorf("QDRANT_DIMENSION must be a positive integer, got %q", v)
+		}
+		cfg.DefaultDimension = dim
+	}
+	return cfg, nil
+}
+
+// chunkerRegistry returns the ingest chunking strategies by file type: the defaults with CHUNK_STRATEGIES
+// overrides (e.g. ".txt=markdown,.log=prose"), around the CHUNK_SIZE/CHUNK_OVERLAP chunker used for prose
+func chunkerRegistry() *processor.ChunkerRegistry {
+	chunkers, err := processor.NewChunkerRegistryFromEnv(processor.NewChunkerFromEnv())
+	if err != nil {
+		logger.Fatalf("invalid CHUNK_STRATEGIES: %v", err)
+	}
+	return chunkers
+}
+
+// searchMaxTopK returns the most results one search may request (SEARCH_MAX_TOP_K, default server.DefaultMaxSearchTopK)
+func searchMaxTopK() int {
+	if v := os.Getenv("SEARCH_MAX_TOP_K"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+		log.Printf("Invalid SEARCH_MAX_TOP_K %q, using default %d", v, server.DefaultMaxSearchTopK)
+	}
+	return server.DefaultMaxSearchTopK
+}
+
+// chatAnswerCache returns the chat answer cache if CHAT_ANSWER_CACHE_TTL enables it (e.g. "1h"), otherwise nil
+func chatAnswerCache() *server.ChatAnswerCache {
+	ttlStr := os.Getenv("CHAT_ANSWER_CACHE_TTL")
+	if ttlStr == "" {
+		return nil
+	}
+	ttl, err := time.ParseDuration(ttlStr)
+	if err != nil || ttl < 0 {
+		log.Printf("Invalid CHAT_ANSWER_CACHE_TTL %q, chat answer cache disabled", ttlStr)
+		return nil
+	}
+	if ttl == 0 {
+		return nil
+	}
+	return server.NewChatAnswerCache(ttl)
+}
+
+// ingestEmbedder returns an embedder that batches the chunk embeddings of concurrent ingests if
+// EMBED_BATCH_WINDOW enables it (e.g. "20ms", at most 250ms), with up to EMBED_BATCH_SIZE texts per call; otherwise nil
+func ingestEmbedder(embedder embeddings.Embedder) embeddings.Embedder {
+	windowStr := os.Getenv("EMBED_BATCH_WINDOW")
+	if windowStr == "" {
+		return nil
+	}
+	window, err := time.ParseDuration(windowStr)
+	if err != nil || window <= 0 {
+		log.Printf("Invalid EMBED_BATCH_WINDOW %q, embedding batching disabled", windowStr)
+		return nil
+	}
+	size := embeddings.DefaultBatchSize
+	if sizeStr := os.Getenv("EMBED_BATCH_SIZE"); sizeStr != "" {
+		if n, err := strconv.Atoi(sizeStr); err == nil && n > 0 {
+			size = n
+		} else {
+			log.Printf("Invalid EMBED_BATCH_SIZE %q, using default %d", sizeStr, embeddings.DefaultBatchSize)
+		}
+	}
+	if window > embeddings.MaxBatchWindow || size > embeddings.MaxBatchSize {
+		log.Printf("Embedding batching is capped at a %s window and %d texts per call", embeddings.MaxBatchWindow, embeddings.MaxBatchSize)
+	}
+	return embeddings.NewBatchingEmbedder(embedder, window, size)
+}
+
+// webhookSources returns the external systems allowed to push documents (WEBHOOK_SOURCES, a JSON array)
+func webhookSources() []server.WebhookSource {
+	raw := os.Getenv("WEBHOOK_SOURCES")
+	if raw == "" {
+		return nil
+	}
+	sources, err := server.ParseWebhookSources(raw)
+	if err != nil {
+		log.Printf("Ignoring WEBHOOK_SOURCES: %v", err)
+		return nil
+	}
+	return sources
+}
+
 // writeEnvVar writes a key-value pair to the .env file
 func writeEnvVar(key, value string) error {
 	envPath := ".env"
@@ -470,49 +976,26 @@ func initDatabase(db *sql.DB) error {
 		return err
 	}
 	
-	// Migration: Add organization_id column if it doesn't exist
-	rows, err := db.Query("PRAGMA table_info(chunks)")
-	if err == nil {
-		defer rows.Close()
-		hasOrgID := false
-		for rows.Next() {
-			var cid int
-			var name, dataType string
-			var notNull, pk int
-			var defaultValue interface{}
-			if err := rows.Scan(&cid, &name, &dataType, &notNull, &defaultValue, &pk); err == nil {
-				if name == "organization_id" {
-					hasOrgID = true
-					break
-				}
-			}
-		}
-		if !hasOrgID {
-			log.Printf("[MIGRATION] Adding organization_id column to chunks table")
-			_, err = db.Exec("ALTER TABLE chunks ADD COLUMN organization_id TEXT")
-			if err != nil {
-				log.Printf("Warning: Failed to add organization_id to chunks: %v", err)
-			} else {
-				log.Printf("[MIGRATION] Successfully added organization_id column to chunks")
-				// Create index after adding column
-				_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_chunks_organization_id ON chunks(organization_id)")
-				if err != nil {
-					log.Printf("Warning: Failed to create organization_id index on chunks: %v", err)
-				}
-			}
-		} else {
-			// Column exists, ensure index exists
-			_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_chunks_organization_id ON chunks(organization_id)")
-			if err != nil {
-				log.Printf("Warning: Failed to create organization_id index on chunks: %v", err)
+	_, err = database.RunMigrations(db, "chunks", chunkMigrations)
+	return err
+}
+
+// chunkMigrations are the versioned schema changes for the documents and chunks tables
+var chunkMigrations = []database.Migration{
+	{
+		Version:     1,
+		Description: "add organization_id to chunks",
+		Up: func(tx *sql.Tx) error {
+			if err := database.AddColumnIfMissing(tx, "chunks", "organization_id", "TEXT"); err != nil {
+				return err
 			}
-		}
-	}
-	
-	return nil
+			_, err := tx.Exec("CREATE INDEX IF NOT EXISTS idx_chunks_organization_id ON chunks(organization_id)")
+			return err
+		},
+	},
 }
 
-func routes(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder, jobQueue queue.Queue, wsManager *server.WebSocketManager, analystPool *worker.AnalystPool, taggerPool *worker.TaggerPool, ruleStore *rules.Store, eventLogger *database.EventLogger, graphStore *database.GraphStore, apiKeyStore *database.APIKeyStore, auditLogStore *database.AuditLogStore, metadataStore *database.SystemMetadataStore, ruleMatchStore *database.RuleMatchStore, ruleEventStore *database.RuleEventStore, userStore *database.UserStore, chatStore *database.ChatStore, orgStore *database.OrganizationStore, usageStore *database.UsageStore, domainStore *database.CustomDomainStore, templateDir, staticDir string) http.Handler {
+func routes(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder, jobQueue queue.Queue, wsManager *server.WebSocketManager, analystPool *worker.AnalystPool, taggerPool *worker.TaggerPool, ruleStore *rules.Store, eventLogger *database.EventLogger, graphStore *database.GraphStore, apiKeyStore *database.APIKeyStore, auditLogStore *database.AuditLogStore, metadataStore *database.SystemMetadataStore, ruleMatchStore *database.RuleMatchStore, ruleEventStore *database.RuleEventStore, userStore *database.UserStore, chatStore *database.ChatStore, orgStore *database.OrganizationStore, usageStore *database.UsageStore, domainStore *database.CustomDomainStore, savedSearchStore *database.SavedSearchStore, rechunker *jobs.Rechunker, retagger *jobs.Retagger, reconciler *jobs.Reconciler, piiPolicyStore *database.PIIPolicyStore, piiGuard *server.PIIGuard, passwordFlagStore *database.PasswordFlagStore, samplingStore *database.AnalysisSamplingStore, analysisSampler *server.AnalysisSampler, dbMaintainer *jobs.SQLiteMaintainer, chatFeedbackStore *database.ChatFeedbackStore, groundingStore *database.GroundingPolicyStore, documentSummarizer *server.DocumentSummarizer, inviteStore *database.InviteStore, jobStatusStore *database.JobStatusStore, chatRetentionStore *database.ChatRetentionStore, answerCache *server.ChatAnswerCache, notificationRouter *server.NotificationRouter, jobFailures *worker.JobFailureRecorder, readinessChecks []server.ReadinessCheck, ingestLimiter *server.IngestLimiter, templateDir, staticDir string) http.Handler {
 	_ = db
 	_ = vectorDB
 	mux := http.NewServeMux()
@@ -532,7 +1015,11 @@ func routes(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder
 	// Use new licensing middleware from middleware package
 	licensingMiddleware := middleware.LicenseMiddleware(metadataStore)
 	// Authentication middleware
-	requireLogin := middleware.RequireLogin(userStore)
+	// Users with a temporary password can only change it until they do
+	requirePasswordChanged := server.RequirePasswordChanged(passwordFlagStore)
+	requireLogin := func(next http.Handler) http.Handler {
+		return middleware.RequireLogin(userStore)(requirePasswordChanged(next))
+	}
 	requireAdmin := middleware.RequireRole(database.RoleAdmin)
 	requireSuperAdmin := middleware.RequireSuperAdmin()
 	
@@ -541,14 +1028,77 @@ func routes(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder
 
 	// Create handlers with dependencies
 	ingestHandler := server.NewIngestHandler(vectorDB, wsManager, analystPool, taggerPool, eventLogger, auditLogStore)
+	ingestHandler.SetPIIGuard(piiGuard)
+	ingestHandler.SetAnalysisSampler(analysisSampler)
+	ingestHandler.SetDocumentSummarizer(documentSummarizer)
+	ingestHandler.SetAnswerCache(answerCache)
+	ingestHandler.SetChunkerRegistry(chunkerRegistry())
+	if batcher := ingestEmbedder(embedder); batcher != nil {
+		ingestHandler.SetEmbedder(batcher)
+	}
 	searchHandler := server.NewSearchHandler(vectorDB, embedder, auditLogStore)
+	searchHandler.SetSummaryStore(documentSummarizer.Store())
 	chatHandler := server.NewChatHandler(vectorDB, embedder, auditLogStore, chatStore, orgStore, usageStore)
+
+	// Reject stop-word-only and very short queries before they reach the embedder
+	queryValidator := server.NewQueryValidatorFromEnv()
+	searchHandler.SetQueryValidator(queryValidator)
+	chatHandler.SetQueryValidator(queryValidator)
+
+	// SEARCH_MAX_TOP_K caps top_k for search, saved searches and chat retrieval
+	maxTopK := searchMaxTopK()
+	searchHandler.SetMaxTopK(maxTopK)
+	chatHandler.SetMaxTopK(maxTopK)
+
+	// CHAT_MMR_LAMBDA trades relevance against diversity when picking chat context (1 = plain top-K)
+	if lambdaStr := os.Getenv("CHAT_MMR_LAMBDA"); lambdaStr != "" {
+		if lambda, err := strconv.ParseFloat(lambdaStr, 64); err == nil && lambda >= 0 && lambda <= 1 {
+			chatHandler.SetMMRLambda(lambda)
+		} else {
+			log.Printf("Invalid CHAT_MMR_LAMBDA %q (expected 0-1), using default %.1f", lambdaStr, vectordb.DefaultMMRLambda)
+		}
+	}
+
+	// Answers come from the AI provider (ai.Complete by default); grounding is applied per organization
+	chatHandler.SetGroundingPolicies(groundingStore)
+	chatHandler.SetAnswerCache(answerCache)
+	chatHandler.SetHistoryLimiter(chatRetentionStore)
+	// CHAT_TIMEOUT bounds embedding, search and generation for one chat request (e.g. "45s")
+	if timeoutStr := os.Getenv("CHAT_TIMEOUT"); timeoutStr != "" {
+		if timeout, err := time.ParseDuration(timeoutStr); err == nil && timeout > 0 {
+			chatHandler.SetTimeout(timeout)
+		} else {
+			log.Printf("Invalid CHAT_TIMEOUT %q, using default %s", timeoutStr, server.DefaultChatTimeout)
+		}
+	}
 	purgeHandler := server.NewPurgeHandler(vectorDB, db, auditLogStore)
+	purgeHandler.SetOrganizationStore(orgStore)
+	purgeHandler.SetAnswerCache(answerCache)
+
+	// TEMPLATE_RELOAD serves templates from -template-dir so edits can go live:
+	// "manual" reloads via the admin endpoint, "always" re-parses on every render (dev only)
+	templates := server.DefaultTemplates()
+	if mode := os.Getenv("TEMPLATE_RELOAD"); mode != "" && mode != "off" {
+		if mode != "manual" && mode != "always" {
+			log.Printf("Invalid TEMPLATE_RELOAD %q (expected off, manual or always), using embedded templates", mode)
+		} else if err := templates.UseTemplateDir(templateDir); err != nil {
+			log.Printf("Cannot load templates from %s, using embedded templates: %v", templateDir, err)
+		} else {
+			templates.SetReloadOnRender(mode == "always")
+			log.Printf("Serving templates from %s (reload mode: %s)", templateDir, mode)
+		}
+	}
 
 	// Domain validation endpoint (public - called by Caddy for SSL certificate validation)
 	mux.HandleFunc("/api/v1/infra/check-domain", func(w http.ResponseWriter, r *http.Request) {
 		server.HandleCheckDomain(w, r, domainStore)
 	})
+
+	// Domain preflight (admin) - diagnoses DNS, existing mappings and reachability before cert issuance
+	domainPreflight := server.NewDomainPreflight(nil, domainStore, server.ServerIPsFromEnv())
+	mux.Handle("/api/v1/admin/domains/preflight", requireLogin(requireAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandleDomainPreflight(w, r, domainPreflight)
+	}))))
 	
 	// Login page (public - no auth required)
 	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
@@ -571,6 +1121,9 @@ func routes(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder
 		server.HandleMe(w, r, userStore)
 	})))
 
+	// Unknown API paths get a JSON 404 instead of falling through to the web interface
+	mux.HandleFunc("/api/", server.HandleAPINotFound)
+
 	// Web interface handlers (protected - require login)
 	mux.Handle("/", requireLogin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		server.HandleWeb(w, r, metadataStore, orgStore)
@@ -616,17 +1169,23 @@ func routes(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder
 	// Ingest requires client API key authentication (for drone clients)
 	// Note: For drone clients, organization_id should come from the API key's client association
 	// For now, we'll extract it from the user context if available
-	mux.Handle("/api/v1/ingest", licensingMiddleware(authMiddleware(http.HandlerFunc(ingestHandler.HandleIngest))))
-	// Search requires login, tenant, and licensing check
-	mux.Handle("/api/v1/search", requireLogin(requireTenant(licensingMiddleware(http.HandlerFunc(searchHandler.HandleSearch)))))
-	// Chat/Q&A requires login, tenant, and licensing check
-	mux.Handle("/api/v1/chat", requireLogin(requireTenant(licensingMiddleware(http.HandlerFunc(chatHandler.HandleChat)))))
+	// Search, chat and ingest responses advertise the tenant's remaining rate limit and daily quota
+	rateLimiter := server.NewTenantRateLimiterFromEnv()
+	// Each organization's concurrent ingests are bounded (429 once its queue is full)
+	mux.Handle("/api/v1/ingest", licensingMiddleware(authMiddleware(rateLimiter.Headers(ingestLimiter.Middleware(http.HandlerFunc(ingestHandler.HandleIngest))))))
+	// Chunk preview: how ingest would split content with the current chunk settings (nothing is stored)
+	mux.Handle("/api/v1/chunk/preview", authMiddleware(http.HandlerFunc(ingestHandler.HandleChunkPreview)))
+	// Search requires login and tenant (or an API key, scoped to the key's organization) and a licensing check
+	loginOrAPIKey := server.APIKeyOrSession(apiKeyStore, func(next http.Handler) http.Handler { return requireLogin(requireTenant(next)) })
+	mux.Handle("/api/v1/search", loginOrAPIKey(rateLimiter.Headers(licensingMiddleware(http.HandlerFunc(searchHandler.HandleSearch)))))
+	// Chat/Q&A has the same checks; API-key chats aren't saved as sessions
+	mux.Handle("/api/v1/chat", loginOrAPIKey(rateLimiter.Headers(licensingMiddleware(http.HandlerFunc(chatHandler.HandleChat)))))
 	
 	// Chat session management endpoints (require login and tenant)
 	// Note: Register the more specific route first (with trailing slash) to match /sessions/{id}/messages
 	mux.Handle("/api/v1/chat/sessions/", requireLogin(requireTenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/messages") {
-			server.HandleGetSessionMessages(w, r, chatStore)
+			server.HandleGetSessionMessages(w, r, chatStore, chatRetentionStore)
 		} else if r.Method == http.MethodDelete {
 			server.HandleDeleteSession(w, r, chatStore)
 		} else {
@@ -639,11 +1198,70 @@ func routes(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder
 			server.HandleGetSessions(w, r, chatStore)
 		} else if r.Method == http.MethodPost {
 			server.HandleCreateSession(w, r, chatStore)
+		} else if r.Method == http.MethodDelete {
+			server.HandleBulkDeleteChatSessions(w, r, chatRetentionStore)
 		} else {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	}))))
+	// Pinned sessions are kept by chat retention and bulk deletes
+	mux.Handle("/api/v1/chat/sessions/pinned", requireLogin(requireTenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandlePinnedChatSessions(w, r, chatRetentionStore)
+	}))))
+	mux.Handle("/api/v1/chat/sessions/{id}/pin", requireLogin(requireTenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandleChatSessionPin(w, r, chatRetentionStore)
+	}))))
+	// Chat answer feedback (users rate their own assistant messages; admins see the org report)
+	mux.Handle("/api/v1/chat/messages/{id}/feedback", requireLogin(requireTenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandleChatMessageFeedback(w, r, chatStore, chatFeedbackStore)
+	}))))
+	mux.Handle("/api/v1/chat/feedback/report", requireLogin(requireAdmin(requireTenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandleChatFeedbackReport(w, r, chatFeedbackStore)
+	})))))
 	
+	// Saved search endpoints (require login and tenant; scoped by org and owner)
+	savedSearchHandler := server.NewSavedSearchHandler(savedSearchStore, searchHandler)
+	// Transient compliance check of pasted content against the organization's rules (nothing is stored)
+	analyzeHandler := server.NewAnalyzeHandler(analystPool)
+	mux.Handle("/api/v1/analyze", requireLogin(requireTenant(http.HandlerFunc(analyzeHandler.HandleAnalyze))))
+	mux.Handle("/api/v1/saved-searches", requireLogin(requireTenant(http.HandlerFunc(savedSearchHandler.HandleSavedSearches))))
+	mux.Handle("/api/v1/saved-searches/{id}", requireLogin(requireTenant(http.HandlerFunc(savedSearchHandler.HandleSavedSearch))))
+	mux.Handle("/api/v1/saved-searches/{id}/execute", requireLogin(requireTenant(licensingMiddleware(http.HandlerFunc(savedSearchHandler.HandleExecuteSavedSearch)))))
+
+	// PII policy for the caller's organization (require admin and tenant)
+	mux.Handle("/api/v1/settings/pii", requireLogin(requireAdmin(requireTenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandlePIIPolicy(w, r, piiPolicyStore, piiGuard, auditLogStore)
+	})))))
+	// Analysis sampling policy (admin) - limits which documents get AI rule analysis
+	mux.Handle("/api/v1/settings/analysis-sampling", requireLogin(requireAdmin(requireTenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandleAnalysisSampling(w, r, samplingStore, auditLogStore)
+	})))))
+	// Document listing with AI-generated summaries
+	mux.Handle("/api/v1/documents", requireLogin(requireTenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandleListDocuments(w, r, documentSummarizer.Store())
+	}))))
+	// Search within one document's chunks (rate limited and licensed like /api/v1/search)
+	mux.Handle("/api/v1/documents/{id}/search", requireLogin(requireTenant(rateLimiter.Headers(licensingMiddleware(http.HandlerFunc(searchHandler.HandleDocumentSearch))))))
+	// Everything recorded about one document (audit logs, rule events and matches), oldest first
+	mux.Handle("/api/v1/documents/{id}/audit", requireLogin(requireTenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandleDocumentAudit(w, r, documentSummarizer.Store(), auditLogStore, ruleEventStore, ruleMatchStore)
+	}))))
+
+	// Chat retention policy (admin) - unpinned sessions older than retention_days are deleted daily
+	mux.Handle("/api/v1/settings/chat-retention", requireLogin(requireAdmin(requireTenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandleChatRetentionPolicy(w, r, chatRetentionStore, auditLogStore)
+	})))))
+
+	// Answer grounding policy (admin) - context-only chat answers and optional answer verification
+	mux.Handle("/api/v1/settings/grounding", requireLogin(requireAdmin(requireTenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandleGroundingPolicy(w, r, groundingStore, auditLogStore)
+	})))))
+
+	// Notification routing (admin) - which channels each alert severity is delivered on
+	mux.Handle("/api/v1/settings/notification-routing", requireLogin(requireAdmin(requireTenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notificationRouter.HandleNotificationRouting(w, r, auditLogStore)
+	})))))
+
 	// Configuration endpoints
 	// GET: require login (any authenticated user can view config)
 	// POST: require super admin (only super admins can modify infrastructure settings)
@@ -652,7 +1270,9 @@ func routes(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder
 			server.HandleGetConfig(w, r)
 		} else if r.Method == http.MethodPost {
 			// POST requires super admin
-			requireSuperAdmin(http.HandlerFunc(server.HandleSaveConfig)).ServeHTTP(w, r)
+			requireSuperAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				server.HandleSaveConfig(w, r, auditLogStore)
+			})).ServeHTTP(w, r)
 		} else {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
@@ -664,35 +1284,80 @@ func routes(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder
 		server.HandleClientShutdown(w, r, apiKeyStore)
 	})
 
+	// Notification delivery stats (require admin) - surfaces notifications that missed Redis
+	mux.Handle("/api/v1/notifications/stats", requireLogin(requireAdmin(http.HandlerFunc(wsManager.HandleNotificationStats))))
+
 	// Stats endpoint (require login)
 	mux.Handle("/api/v1/stats", requireLogin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		server.HandleStats(w, r, vectorDB, db)
+		server.HandleStats(w, r, vectorDB, db, analystPool)
 	})))
 
 	// Purge endpoint (requires admin, login, and licensing check)
 	// IMPORTANT: requireLogin must wrap requireAdmin so user is set in context first
 	mux.Handle("/api/v1/purge", requireLogin(requireAdmin(licensingMiddleware(http.HandlerFunc(purgeHandler.HandlePurge)))))
+	// Preview counts what the same purge request would delete and returns the confirmation it needs
+	mux.Handle("/api/v1/purge/preview", requireLogin(requireAdmin(http.HandlerFunc(purgeHandler.HandlePurgePreview))))
 
 	// Super Admin endpoints (require super admin role)
 	mux.Handle("/api/v1/admin/organizations", requireLogin(requireSuperAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodGet {
 			server.HandleListOrganizations(w, r, orgStore, userStore, usageStore)
 		} else if r.Method == http.MethodPost {
-			server.HandleCreateOrganization(w, r, orgStore, userStore)
+			server.HandleCreateOrganization(w, r, orgStore, userStore, ruleStore)
 		} else {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	}))))
 	mux.Handle("/api/v1/admin/organizations/", requireLogin(requireSuperAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPatch {
-			server.HandleUpdateOrganization(w, r, orgStore)
+			server.HandleUpdateOrganization(w, r, orgStore, auditLogStore)
 		} else {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	}))))
+	// Template of rules seeded into each new organization (super admin)
+	mux.Handle("/api/v1/admin/default-rules", requireLogin(requireSuperAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandleDefaultRules(w, r, ruleStore, auditLogStore)
+	}))))
 	mux.Handle("/api/v1/admin/login-as/{orgId}", requireLogin(requireSuperAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		server.HandleLoginAs(w, r, orgStore, userStore, metadataStore)
 	}))))
+	// Re-chunk endpoints (require admin; super admins may target another organization)
+	rechunkHandler := server.NewRechunkHandler(rechunker, jobQueue, auditLogStore)
+	mux.Handle("/api/v1/admin/rechunk", requireLogin(requireAdmin(http.HandlerFunc(rechunkHandler.HandleRechunk))))
+	mux.Handle("/api/v1/admin/rechunk/{id}", requireLogin(requireAdmin(http.HandlerFunc(rechunkHandler.HandleRechunkStatus))))
+
+	// Bulk re-tagging with the current tag vocabulary (admin)
+	retagHandler := server.NewRetagHandler(retagger, jobQueue, auditLogStore)
+	mux.Handle("/api/v1/admin/retag", requireLogin(requireAdmin(http.HandlerFunc(retagHandler.HandleRetag))))
+	mux.Handle("/api/v1/admin/retag/{id}", requireLogin(requireAdmin(http.HandlerFunc(retagHandler.HandleRetagStatus))))
+
+	// Reconcile SQLite chunks with Qdrant points (admin; super admins may target another organization)
+	reconcileHandler := server.NewReconcileHandler(reconciler, jobQueue, auditLogStore)
+	mux.Handle("/api/v1/admin/reconcile", requireLogin(requireAdmin(http.HandlerFunc(reconcileHandler.HandleReconcile))))
+	mux.Handle("/api/v1/admin/reconcile/{id}", requireLogin(requireAdmin(http.HandlerFunc(reconcileHandler.HandleReconcileStatus))))
+
+	// Inbound webhooks from external systems (authenticated by each source's HMAC signature, not a login)
+	webhookReceiver := server.NewWebhookReceiver(webhookSources(), jobQueue, auditLogStore)
+	mux.HandleFunc("/api/v1/webhooks/ingest/{source}", webhookReceiver.HandleWebhook)
+	jobsHandler := server.NewJobsHandler(jobStatusStore)
+	mux.Handle("/api/v1/admin/jobs", requireLogin(requireAdmin(http.HandlerFunc(jobsHandler.HandleListActiveJobs))))
+	mux.Handle("/api/v1/admin/jobs/{id}", requireLogin(requireAdmin(http.HandlerFunc(jobsHandler.HandleGetJob))))
+	mux.Handle("/api/v1/admin/job-failures", requireLogin(requireSuperAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandleJobFailures(w, r, jobFailures)
+	}))))
+	mux.Handle("/api/v1/admin/dead-jobs", requireLogin(requireSuperAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandleDeadLetters(w, r, jobQueue)
+	}))))
+	mux.Handle("/api/v1/admin/vector-indexes", requireLogin(requireSuperAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandleRebuildPayloadIndexes(w, r, vectorDB)
+	}))))
+	// SQLite maintenance (super admin) - WAL checkpoint now, VACUUM with ?vacuum=true
+	dbMaintenanceHandler := server.NewDBMaintenanceHandler(dbMaintainer, auditLogStore)
+	mux.Handle("/api/v1/admin/templates/reload", requireLogin(requireSuperAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandleReloadTemplates(w, r, templates)
+	}))))
+	mux.Handle("/api/v1/admin/db-maintenance", requireLogin(requireSuperAdmin(http.HandlerFunc(dbMaintenanceHandler.HandleDBMaintenance))))
 
 	// WebSocket endpoint (protected - auth happens in HandleWebSocket)
 	// Note: WebSocket auth is handled via query parameter or header
@@ -700,18 +1365,29 @@ func routes(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder
 		wsManager.HandleWebSocket(w, r)
 	})))
 
-	// Rules API endpoints (require login)
+	// Rules API endpoints (require login); adding a rule suggests similar existing rules
+	ruleSuggester := server.NewRuleSuggester(ruleStore, embedder)
+	if thresholdStr := os.Getenv("RELATED_RULE_THRESHOLD"); thresholdStr != "" {
+		if threshold, err := strconv.ParseFloat(thresholdStr, 64); err == nil && threshold > 0 && threshold <= 1 {
+			ruleSuggester.SetThreshold(threshold)
+		} else {
+			log.Printf("Invalid RELATED_RULE_THRESHOLD %q (expected 0-1), using default %.2f", thresholdStr, server.DefaultRelatedRuleThreshold)
+		}
+	}
 	mux.Handle("/api/v1/rules", requireLogin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodGet {
 			server.HandleGetRules(w, r, ruleStore)
 		} else if r.Method == http.MethodPost {
-			server.HandleAddRule(w, r, ruleStore)
+			server.HandleAddRule(w, r, ruleStore, ruleSuggester)
 		} else {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	})))
+	mux.Handle("/api/v1/rules/{id}", requireLogin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandleGetRule(w, r, ruleStore)
+	})))
 	mux.Handle("/api/v1/rules/add", requireLogin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		server.HandleAddRule(w, r, ruleStore)
+		server.HandleAddRule(w, r, ruleStore, ruleSuggester)
 	})))
 	mux.Handle("/api/v1/rules/update", requireLogin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		server.HandleUpdateRule(w, r, ruleStore)
@@ -719,6 +1395,9 @@ func routes(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder
 	mux.Handle("/api/v1/rules/delete", requireLogin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		server.HandleDeleteRule(w, r, ruleStore)
 	})))
+	// Dry run of a draft rule against the organization's recent documents; nothing is stored
+	ruleCorpusTester := server.NewRuleCorpusTester(db, vectorDB, analystPool)
+	mux.Handle("/api/v1/rules/test-corpus", requireLogin(requireTenant(http.HandlerFunc(ruleCorpusTester.HandleTestCorpus))))
 
 	// Rule matches API endpoint (require login)
 	mux.Handle("/api/v1/rule-matches", requireLogin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -767,7 +1446,7 @@ func routes(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder
 		if r.Method == http.MethodGet {
 			server.HandleGetSystemContext(w, r, orgStore)
 		} else if r.Method == http.MethodPost {
-			server.HandleSaveSystemContext(w, r, orgStore)
+			server.HandleSaveSystemContext(w, r, orgStore, auditLogStore)
 		} else {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
@@ -779,7 +1458,7 @@ func routes(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder
 		if r.Method == http.MethodGet {
 			server.HandleGetTenantOpenAIKey(w, r, orgStore)
 		} else if r.Method == http.MethodPost {
-			server.HandleUpdateTenantOpenAIKey(w, r, orgStore)
+			server.HandleUpdateTenantOpenAIKey(w, r, orgStore, auditLogStore)
 		} else {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
@@ -788,9 +1467,15 @@ func routes(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder
 	// Health endpoint (public - no auth required, but tracks API keys if provided)
 	server.SetHealthAPIKeyStore(apiKeyStore)
 	mux.HandleFunc("/api/v1/health", server.HandleHealth)
+	mux.HandleFunc("/api/v1/version", server.HandleVersion(embedder, vectorDB))
+
+	mux.HandleFunc("/api/v1/ready", server.HandleReady(readinessChecks...))
 
 	// User management endpoints (require admin)
 	// IMPORTANT: requireLogin must wrap requireAdmin so user is set in context first
+	mux.Handle("/api/v1/users/import", requireLogin(requireAdmin(requireTenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.HandleImportUsers(w, r, userStore)
+	})))))
 	mux.Handle("/api/v1/users", requireLogin(requireAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodGet {
 			server.HandleListUsers(w, r, userStore)
@@ -800,6 +1485,19 @@ func routes(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	}))))
+	// Invite-based onboarding: admins invite by email and role, invitees set their own password
+	inviteHandler := server.NewInviteHandler(inviteStore, userStore, auditLogStore)
+	// INVITE_TTL sets how long invite links stay valid (e.g. "72h")
+	if ttlStr := os.Getenv("INVITE_TTL"); ttlStr != "" {
+		if ttl, err := time.ParseDuration(ttlStr); err == nil && ttl > 0 {
+			inviteHandler.SetTTL(ttl)
+		} else {
+			log.Printf("Invalid INVITE_TTL %q, using default %s", ttlStr, database.DefaultInviteTTL)
+		}
+	}
+	mux.Handle("/api/v1/invites", requireLogin(requireAdmin(requireTenant(http.HandlerFunc(inviteHandler.HandleInvites)))))
+	mux.Handle("/api/v1/invites/{id}", requireLogin(requireAdmin(requireTenant(http.HandlerFunc(inviteHandler.HandleRevokeInvite)))))
+	mux.HandleFunc("/api/v1/invites/accept", inviteHandler.HandleAcceptInvite)
 	// Self-service password change endpoint
 	mux.Handle("/api/v1/users/current/password", requireLogin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		server.HandleUpdateCurrentUserPassword(w, r, userStore)
@@ -869,7 +1567,10 @@ func routes(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder
 	return trafficLogger(resolveTenantFromDomain(mux))
 }
 
-func waitForShutdown(grpcServer *grpc.Server, httpServer *http.Server, workerCancel context.CancelFunc) {
+// defaultWorkerShutdownTimeout is how long shutdown waits for jobs in flight unless WORKER_SHUTDOWN_TIMEOUT is set
+const defaultWorkerShutdownTimeout = 30 * time.Second
+
+func waitForShutdown(grpcServer *grpc.Server, httpServer *http.Server, workerCancel context.CancelFunc, workersDone <-chan struct{}) {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 	<-stop
@@ -879,10 +1580,23 @@ func waitForShutdown(grpcServer *grpc.Server, httpServer *http.Server, workerCan
 
 	logger.Println("Shutting down servers...")
 
-	// Stop workers
+	// Stop workers taking new jobs and let them finish the ones in flight
 	if workerCancel != nil {
 		workerCancel()
 	}
+	if workersDone != nil {
+		timeout, _ := time.ParseDuration(os.Getenv("WORKER_SHUTDOWN_TIMEOUT"))
+		if timeout <= 0 {
+			timeout = defaultWorkerShutdownTimeout
+		}
+		select {
+		case <-workersDone:
+			logger.Println("Background workers finished their jobs")
+		case <-time.After(timeout):
+			// Unfinished jobs stay in the processing list and are requeued on the next start
+			logger.Printf("warning: background workers still busy after %s; unfinished jobs will be retried on restart", timeout)
+		}
+	}
 
 	grpcServer.GracefulStop()
 	if err := httpServer.Shutdown(ctx); err != nil {
@@ -0,0 +1,111 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/processor"
+	"github.com/the-hive/internal/proto"
+	"github.com/the-hive/internal/server"
+	"github.com/the-hive/internal/vectordb"
+)
+
+const (
+	selfTestOrgID      = "selftest-org"
+	selfTestDocumentID = "selftest-handbook.txt"
+	selfTestQuery      = "How many vacation days do employees get?"
+	selfTestAnswer     = "25 vacation days"
+)
+
+// selfTestDocument is ingested by the self-test; only one chunk answers selfTestQuery
+var selfTestDocument = []string{
+	"Office hours: the building opens at 7am and closes at 8pm on weekdays. Visitors sign in at reception.",
+	"Vacation policy: full-time employees get 25 vacation days per year. Vacation days are approved by your manager.",
+	"Expense reports: submit receipts within 30 days. Travel must be booked through the company portal.",
+}
+
+// runSelfTest ingests a known document into a temporary database and in-memory vector store with a
+// mock embedder, then searches it and checks the right chunk comes back. It needs no network access,
+// so operators can run `hive-server -selftest` as a post-deploy smoke test.
+func runSelfTest(ctx context.Context, logf func(format string, args ...interface{})) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	dir, err := os.MkdirTemp("", "hive-selftest-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := database.OpenSQLite(filepath.Join(dir, "selftest.db"), database.DefaultSQLiteOptions())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+	if err := initDatabase(db); err != nil {
+		return fmt.Errorf("failed to initialize schema: %w", err)
+	}
+	logf("[SELFTEST] Database initialized")
+
+	vectorDB := vectordb.NewMemoryVectorDB()
+	embedder := embeddings.NewSeededMockEmbedder(256, 1)
+	hiveService := server.NewHiveService(db, vectorDB, embedder)
+
+	chunks, err := processor.NewChunkerWithSettings(120, 0).ChunkText(strings.Join(selfTestDocument, "\n\n"))
+	if err != nil {
+		return fmt.Errorf("failed to chunk document: %w", err)
+	}
+	for i, content := range chunks {
+		status, err := hiveService.Ingest(ctx, &proto.Chunk{
+			Id:         fmt.Sprintf("%s-%d", selfTestDocumentID, i),
+			DocumentId: selfTestDocumentID,
+			Content:    content,
+			Metadata: map[string]string{
+				"organization_id": selfTestOrgID,
+				"filename":        selfTestDocumentID,
+				"chunk_index":     strconv.Itoa(i),
+				"total_chunks":    strconv.Itoa(len(chunks)),
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("ingest of chunk %d failed: %w", i, err)
+		}
+		if !status.Success {
+			return fmt.Errorf("ingest of chunk %d failed: %s", i, status.Message)
+		}
+	}
+
+	var stored int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM chunks WHERE document_id = ?", selfTestDocumentID).Scan(&stored); err != nil {
+		return fmt.Errorf("failed to count stored chunks: %w", err)
+	}
+	if stored != len(chunks) {
+		return fmt.Errorf("expected %d chunks in SQLite, found %d", len(chunks), stored)
+	}
+	if points, _ := vectorDB.GetPointCount(ctx); points != len(chunks) {
+		return fmt.Errorf("expected %d vectors, found %d", len(chunks), points)
+	}
+	logf("[SELFTEST] Ingested %d chunk(s)", len(chunks))
+
+	results, err := server.NewSearchHandler(vectorDB, embedder, nil).Search(ctx, selfTestQuery, 3, selfTestOrgID)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("search returned no results")
+	}
+	if results[0].DocumentID != selfTestDocumentID || !strings.Contains(results[0].Content, selfTestAnswer) {
+		return fmt.Errorf("expected the top result to contain %q, got %q from %s", selfTestAnswer, results[0].Content, results[0].DocumentID)
+	}
+	logf("[SELFTEST] Search returned the expected chunk (score %.3f)", results[0].Score)
+	return nil
+}
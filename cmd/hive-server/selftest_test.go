@@ -0,0 +1,14 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRunSelfTest(t *testing.T) {
+	if err := runSelfTest(context.Background(), t.Logf); err != nil {
+		t.Fatalf("Self-test failed: %v", err)
+	}
+}
@@ -10,6 +10,9 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/the-hive/internal/httpclient"
 )
 
 var (
@@ -177,7 +180,7 @@ The system is deployed using Kubernetes with auto-scaling enabled. Each service
 
 	fmt.Printf("📥 Downloading sample PDF from %s...\n", pdfURL)
 
-	resp, err := http.Get(pdfURL)
+	resp, err := httpclient.New(60 * time.Second).Get(pdfURL)
 	if err != nil {
 		log.Printf("Warning: Failed to download PDF: %v", err)
 		log.Printf("You can manually add a PDF file to %s for testing", *outputDir)
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/the-hive/internal/httpclient"
+)
+
+// Complete sends a free-form prompt with a system prompt to the chat API and returns the reply
+// Returns the reply and usage information
+func Complete(ctx context.Context, systemPrompt, prompt string, maxTokens int) (string, *Usage, error) {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return "", nil, fmt.Errorf("OPENAI_API_KEY not set")
+	}
+
+	model := os.Getenv("OPENAI_CHAT_MODEL")
+	if model == "" {
+		model = "gpt-3.5-turbo"
+	}
+	if maxTokens <= 0 {
+		maxTokens = 500
+	}
+
+	payload := map[string]interface{}{
+		"model": model,
+		"messages": []map[string]string{
+			{
+				"role":    "system",
+				"content": systemPrompt,
+			},
+			{
+				"role":    "user",
+				"content": prompt,
+			},
+		},
+		"max_tokens":  maxTokens,
+		"temperature": 0.1, // Low temperature keeps answers close to the provided context
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return "", nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	// Queue behind the global concurrency limit so bursts don't trip the provider's rate limit
+	release, err := acquireCallSlot(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer release()
+
+	client := httpclient.New(60 * time.Second)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", nil, fmt.Errorf("OpenAI API error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+		Model string `json:"model"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", nil, err
+	}
+
+	if len(result.Choices) == 0 {
+		return "", nil, fmt.Errorf("no response from OpenAI")
+	}
+
+	usage := &Usage{
+		InputTokens:  result.Usage.PromptTokens,
+		OutputTokens: result.Usage.CompletionTokens,
+		Model:        result.Model,
+	}
+	if usage.Model == "" {
+		usage.Model = model
+	}
+
+	return strings.TrimSpace(result.Choices[0].Message.Content), usage, nil
+}
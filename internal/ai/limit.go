@@ -0,0 +1,55 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultMaxConcurrentCalls bounds concurrent provider calls across the analyst, tagger and chat
+const DefaultMaxConcurrentCalls = 4
+
+var (
+	callSlotsMu sync.RWMutex
+	callSlots   = make(chan struct{}, DefaultMaxConcurrentCalls)
+)
+
+// SetMaxConcurrentCalls sets how many provider calls may be in flight at once across the process.
+// Calls over the limit queue until a slot frees up. Zero or less removes the limit.
+func SetMaxConcurrentCalls(n int) {
+	callSlotsMu.Lock()
+	defer callSlotsMu.Unlock()
+	if n <= 0 {
+		callSlots = nil
+		return
+	}
+	callSlots = make(chan struct{}, n)
+}
+
+// MaxConcurrentCalls returns the current limit (0 when unlimited)
+func MaxConcurrentCalls() int {
+	callSlotsMu.RLock()
+	defer callSlotsMu.RUnlock()
+	return cap(callSlots)
+}
+
+// acquireCallSlot waits for a free provider call slot and returns the function that releases it.
+// It fails only if ctx is done while waiting.
+func acquireCallSlot(ctx context.Context) (func(), error) {
+	callSlotsMu.RLock()
+	slots := callSlots
+	callSlotsMu.RUnlock()
+	if slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case slots <- struct{}{}:
+		// Release into the channel the slot came from, even if the limit changed meanwhile
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for an AI call slot: %w", ctx.Err())
+	}
+}
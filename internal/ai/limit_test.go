@@ -0,0 +1,82 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package ai
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAcquireCallSlot_BoundsConcurrentCalls(t *testing.T) {
+	SetMaxConcurrentCalls(3)
+	defer SetMaxConcurrentCalls(DefaultMaxConcurrentCalls)
+
+	var inFlight, peak, completed int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := acquireCallSlot(context.Background())
+			if err != nil {
+				t.Errorf("acquireCallSlot failed: %v", err)
+				return
+			}
+			defer release()
+
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+			atomic.AddInt32(&completed, 1)
+		}()
+	}
+	wg.Wait()
+
+	if peak > 3 {
+		t.Errorf("Expected at most 3 concurrent calls, saw %d", peak)
+	}
+	// Calls over the limit queue rather than fail
+	if completed != 20 {
+		t.Errorf("Expected all 20 calls to complete, got %d", completed)
+	}
+}
+
+func TestAcquireCallSlot_GivesUpWhenContextEnds(t *testing.T) {
+	SetMaxConcurrentCalls(1)
+	defer SetMaxConcurrentCalls(DefaultMaxConcurrentCalls)
+
+	release, err := acquireCallSlot(context.Background())
+	if err != nil {
+		t.Fatalf("acquireCallSlot failed: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := acquireCallSlot(ctx); err == nil {
+		t.Error("Expected waiting for a slot to fail once the context is done")
+	}
+}
+
+func TestSetMaxConcurrentCalls_ZeroIsUnlimited(t *testing.T) {
+	SetMaxConcurrentCalls(0)
+	defer SetMaxConcurrentCalls(DefaultMaxConcurrentCalls)
+
+	if got := MaxConcurrentCalls(); got != 0 {
+		t.Errorf("Expected no limit, got %d", got)
+	}
+	for i := 0; i < 50; i++ {
+		if _, err := acquireCallSlot(context.Background()); err != nil {
+			t.Fatalf("acquireCallSlot failed without a limit: %v", err)
+		}
+	}
+}
@@ -11,6 +11,12 @@ import (
 	"github.com/the-hive/internal/embeddings"
 )
 
+// EmbeddingBatchSize is the most texts GenerateEmbeddings sends to OpenAI in one request
+const EmbeddingBatchSize = 64
+
+// dummyEmbeddingDimensions is the size of the placeholder vectors returned without an API key
+const dummyEmbeddingDimensions = 1536
+
 // GenerateEmbedding generates an embedding for the given text
 // Returns a dummy vector (all zeros) if OPENAI_API_KEY is not set
 func GenerateEmbedding(text string) ([]float32, error) {
@@ -18,10 +24,60 @@ func GenerateEmbedding(text string) ([]float32, error) {
 	if apiKey == "" {
 		log.Printf("warning: OPENAI_API_KEY not set, returning dummy vector")
 		// Return dummy vector of 1536 dimensions (all zeros)
-		return make([]float32, 1536), nil
+		return make([]float32, dummyEmbeddingDimensions), nil
+	}
+
+	embedder, err := newOpenAIEmbedder(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	vector, err := embedder.EmbedText(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate embedding: %w", err)
+	}
+
+	return vector, nil
+}
+
+// GenerateEmbeddings generates embeddings for the texts, in order, sending up to EmbeddingBatchSize
+// texts per request. Returns dummy vectors (all zeros) if OPENAI_API_KEY is not set
+func GenerateEmbeddings(texts []string) ([][]float32, error) {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		log.Printf("warning: OPENAI_API_KEY not set, returning %d dummy vectors", len(texts))
+		vectors := make([][]float32, len(texts))
+		for i := range vectors {
+			vectors[i] = make([]float32, dummyEmbeddingDimensions)
+		}
+		return vectors, nil
 	}
 
-	// Use the existing embeddings package
+	embedder, err := newOpenAIEmbedder(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += EmbeddingBatchSize {
+		end := min(start+EmbeddingBatchSize, len(texts))
+		batch, err := embedder.EmbedBatch(ctx, texts[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate embeddings: %w", err)
+		}
+		if len(batch) != end-start {
+			return nil, fmt.Errorf("failed to generate embeddings: got %d vectors for %d texts", len(batch), end-start)
+		}
+		vectors = append(vectors, batch...)
+	}
+
+	return vectors, nil
+}
+
+// newOpenAIEmbedder creates the OpenAI embedder, using OPENAI_MODEL if set
+func newOpenAIEmbedder(apiKey string) (embeddings.Embedder, error) {
 	embedderConfig := map[string]string{
 		"api_key": apiKey,
 		"model":   os.Getenv("OPENAI_MODEL"),
@@ -34,12 +90,5 @@ func GenerateEmbedding(text string) ([]float32, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create embedder: %w", err)
 	}
-
-	ctx := context.Background()
-	vector, err := embedder.EmbedText(ctx, text)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate embedding: %w", err)
-	}
-
-	return vector, nil
+	return embedder, nil
 }
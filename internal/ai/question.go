@@ -12,6 +12,8 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/the-hive/internal/httpclient"
 )
 
 // AskQuestion asks a yes/no question and returns YES or NO
@@ -67,7 +69,14 @@ func AskQuestion(ctx context.Context, prompt string) (string, *Usage, error) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+apiKey)
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	// Queue behind the global concurrency limit so bursts don't trip the provider's rate limit
+	release, err := acquireCallSlot(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer release()
+
+	client := httpclient.New(30 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", nil, err
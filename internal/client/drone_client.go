@@ -2,23 +2,80 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
+	"unicode/utf8"
 
-	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
 
+	"github.com/the-hive/internal/docid"
+	"github.com/the-hive/internal/grpclimits"
 	"github.com/the-hive/internal/proto"
 )
 
+// ErrChunkTooLarge is returned when a chunk doesn't fit in one gRPC message
+var ErrChunkTooLarge = errors.New("chunk exceeds the gRPC message size limit")
+
+// ErrServerBusy is returned when the server is running as many of the organization's ingests as it allows;
+// the chunk can be sent again later
+var ErrServerBusy = errors.New("server is busy with other ingests for this organization")
+
 // DroneClient wraps the generated gRPC client to expose higher-level helpers.
 type DroneClient struct {
-	client proto.HiveClient
+	client         proto.HiveClient
+	maxMessageSize int // Must match the limit the connection was dialed with
 }
 
 // NewDroneClient creates a new DroneClient instance.
 func NewDroneClient(client proto.HiveClient) *DroneClient {
-	return &DroneClient{client: client}
+	return &DroneClient{client: client, maxMessageSize: grpclimits.DefaultMaxMessageSize}
+}
+
+// SetMaxMessageSize sets the largest message the connection accepts (see grpclimits.DialOptions)
+func (c *DroneClient) SetMaxMessageSize(maxMessageSize int) {
+	if maxMessageSize > 0 {
+		c.maxMessageSize = maxMessageSize
+	}
+}
+
+// MaxChunkContent returns the most chunk text that fits in one message
+func (c *DroneClient) MaxChunkContent() int {
+	return grpclimits.MaxChunkContent(c.maxMessageSize)
+}
+
+// SplitOversizedChunks splits chunks longer than maxBytes, preferring to break at whitespace and
+// never splitting a UTF-8 character
+func SplitOversizedChunks(chunks []string, maxBytes int) []string {
+	if maxBytes <= 0 {
+		return chunks
+	}
+	result := make([]string, 0, len(chunks))
+	for _, chunk := range chunks {
+		for len(chunk) > maxBytes {
+			cut := maxBytes
+			for cut > 0 && !utf8.RuneStart(chunk[cut]) {
+				cut--
+			}
+			if space := strings.LastIndexAny(chunk[:cut], " \n\t"); space > cut/2 {
+				cut = space + 1
+			}
+			if cut == 0 {
+				cut = maxBytes
+			}
+			result = append(result, chunk[:cut])
+			chunk = chunk[cut:]
+		}
+		if chunk != "" {
+			result = append(result, chunk)
+		}
+	}
+	return result
 }
 
 // IngestChunk sends a chunk to the Hive server.
@@ -31,14 +88,9 @@ func (c *DroneClient) IngestChunk(ctx context.Context, documentID, content strin
 
 	log.Printf("[INFO] Uploading file to server: %s (chunk %d/%s)...", filename, chunkIndex, metadata["ingest_type"])
 
-	// Create a deterministic UUID based on the file path and chunk index
+	// Create a deterministic UUID based on the document and chunk index
 	// This ensures if we re-ingest the same file, we update the existing vectors (Idempotency)
-	filePath := metadata["file_path"]
-	if filePath == "" {
-		filePath = documentID
-	}
-	seed := fmt.Sprintf("%s-%d", filePath, chunkIndex)
-	pointID := uuid.NewSHA1(uuid.NameSpaceURL, []byte(seed)).String()
+	pointID := docid.PointID(documentID, chunkIndex)
 
 	chunk := &proto.Chunk{
 		Id:         pointID, // Pure UUID string - no concatenation
@@ -48,21 +100,53 @@ func (c *DroneClient) IngestChunk(ctx context.Context, documentID, content strin
 		Metadata:   metadata,
 	}
 
+	if size := protobuf.Size(chunk); size > c.maxMessageSize {
+		return fmt.Errorf("%w: chunk %d of %s is %d bytes, over the %d-byte limit (raise %s on the server and drone)",
+			ErrChunkTooLarge, chunkIndex, filename, size, c.maxMessageSize, grpclimits.EnvMaxMessageSize)
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	status, err := c.client.Ingest(ctx, chunk)
+	result, err := c.client.Ingest(ctx, chunk)
+	// ResourceExhausted is also how the server says the organization has too many ingests running
+	if status.Code(err) == codes.ResourceExhausted && !strings.Contains(status.Convert(err).Message(), "larger than max") {
+		return fmt.Errorf("%w: chunk %d of %s: %v", ErrServerBusy, chunkIndex, filename, err)
+	}
+	if status.Code(err) == codes.ResourceExhausted {
+		return fmt.Errorf("%w: the server rejected chunk %d of %s (%v); its %s may be lower than this drone's",
+			ErrChunkTooLarge, chunkIndex, filename, err, grpclimits.EnvMaxMessageSize)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to ingest chunk: %w", err)
 	}
-	if !status.Success {
-		return fmt.Errorf("ingestion failed: %s", status.Message)
+	if !result.Success {
+		return fmt.Errorf("ingestion failed: %s", result.Message)
+	}
+	return nil
+}
+
+// DeleteDocument asks the Hive to remove a document and all of its chunks, e.g. after its file was deleted
+func (c *DroneClient) DeleteDocument(ctx context.Context, documentID string, metadata map[string]string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result, err := c.client.DeleteDocument(ctx, &proto.DeleteDocumentRequest{
+		DocumentId: documentID,
+		Metadata:   metadata,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("document deletion failed: %s", result.Message)
 	}
 	return nil
 }
 
-// Query performs a semantic search against the Hive.
-func (c *DroneClient) Query(ctx context.Context, query string, topK int32) (*proto.Result, error) {
+// Query performs a semantic search against the Hive. A non-empty organizationID is sent in the
+// gRPC metadata so only that organization's chunks are returned.
+func (c *DroneClient) Query(ctx context.Context, query string, topK int32, organizationID string) (*proto.Result, error) {
 	request := &proto.Search{
 		Query:       query,
 		TopK:        topK,
@@ -71,6 +155,9 @@ func (c *DroneClient) Query(ctx context.Context, query string, topK int32) (*pro
 
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
+	if organizationID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "organization_id", organizationID)
+	}
 
 	result, err := c.client.Query(ctx, request)
 	if err != nil {
@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/the-hive/internal/grpclimits"
+	"github.com/the-hive/internal/proto"
+)
+
+// recordingHive accepts chunks and remembers their sizes
+type recordingHive struct {
+	proto.UnimplementedHiveServer
+	received []int
+	busy     bool // Reject chunks as the ingest limiter does
+}
+
+func (h *recordingHive) Ingest(ctx context.Context, chunk *proto.Chunk) (*proto.Status, error) {
+	if h.busy {
+		return nil, status.Error(codes.ResourceExhausted, "too many concurrent ingests for this organization")
+	}
+	h.received = append(h.received, len(chunk.Content))
+	return &proto.Status{Success: true}, nil
+}
+
+// startHive serves a recordingHive in-process with the given server message limit
+func startHive(t *testing.T, serverLimit, clientLimit int) (*recordingHive, *DroneClient) {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	hive := &recordingHive{}
+	grpcServer := grpc.NewServer(grpclimits.ServerOptions(serverLimit)...)
+	proto.RegisterHiveServer(grpcServer, hive)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	dialOptions := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+	}, grpclimits.DialOptions(clientLimit)...)
+	conn, err := grpc.NewClient("passthrough:///bufnet", dialOptions...)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	droneClient := NewDroneClient(proto.NewHiveClient(conn))
+	droneClient.SetMaxMessageSize(clientLimit)
+	return hive, droneClient
+}
+
+func TestIngestChunk_NearLimitChunkSucceeds(t *testing.T) {
+	const limit = 6 << 20 // Above gRPC's 4MB default
+	hive, droneClient := startHive(t, limit, limit)
+	metadata := map[string]string{"filename": "big.txt", "file_path": "/data/big.txt"}
+
+	content := strings.Repeat("a", droneClient.MaxChunkContent())
+	if err := droneClient.IngestChunk(context.Background(), "doc-1", content, 0, metadata); err != nil {
+		t.Fatalf("Expected a near-limit chunk to be ingested, got %v", err)
+	}
+	if len(hive.received) != 1 || hive.received[0] != len(content) {
+		t.Fatalf("Expected the server to receive the whole chunk, got %v", hive.received)
+	}
+
+	// A chunk over the limit fails with a clear error instead of a transport failure
+	err := droneClient.IngestChunk(context.Background(), "doc-1", strings.Repeat("a", limit+1), 1, metadata)
+	if !errors.Is(err, ErrChunkTooLarge) || !strings.Contains(err.Error(), "big.txt") {
+		t.Errorf("Expected ErrChunkTooLarge naming the file, got %v", err)
+	}
+
+	// Splitting makes every piece fit
+	pieces := SplitOversizedChunks([]string{strings.Repeat("word ", limit/4)}, droneClient.MaxChunkContent())
+	for i, piece := range pieces {
+		if err := droneClient.IngestChunk(context.Background(), "doc-2", piece, i, metadata); err != nil {
+			t.Fatalf("Expected split piece %d to be ingested, got %v", i, err)
+		}
+	}
+	if len(pieces) < 2 {
+		t.Errorf("Expected the oversized chunk to be split, got %d piece(s)", len(pieces))
+	}
+}
+
+func TestIngestChunk_ServerLimitRejectionIsClear(t *testing.T) {
+	_, droneClient := startHive(t, 1<<20, 4<<20)
+	err := droneClient.IngestChunk(context.Background(), "doc-1", strings.Repeat("a", 2<<20), 0, map[string]string{"filename": "big.txt"})
+	if !errors.Is(err, ErrChunkTooLarge) {
+		t.Errorf("Expected the server's rejection to be reported as ErrChunkTooLarge, got %v", err)
+	}
+}
+
+func TestIngestChunk_BusyServerIsNotTooLarge(t *testing.T) {
+	hive, droneClient := startHive(t, 1<<20, 1<<20)
+	hive.busy = true
+	err := droneClient.IngestChunk(context.Background(), "doc-1", "small", 0, map[string]string{"filename": "small.txt"})
+	if !errors.Is(err, ErrServerBusy) || errors.Is(err, ErrChunkTooLarge) {
+		t.Errorf("Expected a busy server to be reported as ErrServerBusy, got %v", err)
+	}
+}
+
+func TestSplitOversizedChunks_KeepsCharactersWhole(t *testing.T) {
+	chunk := strings.Repeat("é", 10) // 2 bytes each
+	pieces := SplitOversizedChunks([]string{chunk, "short"}, 5)
+	if strings.Join(pieces, "") != chunk+"short" {
+		t.Fatalf("Expected the pieces to reassemble the input, got %q", pieces)
+	}
+	for _, piece := range pieces {
+		if len(piece) > 5 || !strings.HasPrefix(piece, "é") && piece != "short" {
+			t.Errorf("Unexpected piece %q", piece)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AnalysisSamplingPolicy limits which ingested documents are sent for AI rule analysis
+type AnalysisSamplingPolicy struct {
+	OrganizationID string            `json:"organization_id"`
+	EveryN         int               `json:"every_n"`             // Analyze 1 in N documents (1 = all)
+	FileTypes      []string          `json:"filetypes,omitempty"` // Only analyze these file types (empty = any)
+	Metadata       map[string]string `json:"metadata,omitempty"`  // Only analyze documents whose metadata matches all pairs
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// AnalysisSamplingStore manages per-organization analysis sampling policies
+type AnalysisSamplingStore struct {
+	db *sql.DB
+}
+
+// NewAnalysisSamplingStore creates a new analysis sampling store
+func NewAnalysisSamplingStore(db *sql.DB) (*AnalysisSamplingStore, error) {
+	store := &AnalysisSamplingStore{db: db}
+	if err := store.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize analysis sampling schema: %w", err)
+	}
+	return store, nil
+}
+
+// initSchema creates the analysis_sampling_policies table if it doesn't exist
+func (s *AnalysisSamplingStore) initSchema() error {
+	const schema = `
+	CREATE TABLE IF NOT EXISTS analysis_sampling_policies (
+		organization_id TEXT PRIMARY KEY,
+		every_n INTEGER NOT NULL DEFAULT 1,
+		filetypes TEXT NOT NULL DEFAULT '',
+		metadata TEXT NOT NULL DEFAULT '{}',
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	`
+	_, err := s.db.Exec(schema)
+	return err
+}
+
+// GetPolicy returns the organization's sampling policy, or nil if none is set
+func (s *AnalysisSamplingStore) GetPolicy(organizationID string) (*AnalysisSamplingPolicy, error) {
+	var policy AnalysisSamplingPolicy
+	var fileTypes, metadata string
+	err := s.db.QueryRow(
+		"SELECT organization_id, every_n, filetypes, metadata, updated_at FROM analysis_sampling_policies WHERE organization_id = ?",
+		organizationID,
+	).Scan(&policy.OrganizationID, &policy.EveryN, &fileTypes, &metadata, &policy.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis sampling policy: %w", err)
+	}
+
+	if fileTypes != "" {
+		policy.FileTypes = strings.Split(fileTypes, ",")
+	}
+	if err := json.Unmarshal([]byte(metadata), &policy.Metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse analysis sampling metadata: %w", err)
+	}
+	return &policy, nil
+}
+
+// SetPolicy creates or replaces the organization's sampling policy
+func (s *AnalysisSamplingStore) SetPolicy(policy AnalysisSamplingPolicy) error {
+	if policy.EveryN < 1 {
+		return fmt.Errorf("every_n must be at least 1")
+	}
+	metadata, err := json.Marshal(policy.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode analysis sampling metadata: %w", err)
+	}
+	if policy.Metadata == nil {
+		metadata = []byte("{}")
+	}
+
+	_, err = s.db.Exec(
+		"INSERT INTO analysis_sampling_policies (organization_id, every_n, filetypes, metadata, updated_at) VALUES (?, ?, ?, ?, ?) ON CONFLICT(organization_id) DO UPDATE SET every_n = excluded.every_n, filetypes = excluded.filetypes, metadata = excluded.metadata, updated_at = excluded.updated_at",
+		policy.OrganizationID,
+		policy.EveryN,
+		strings.Join(policy.FileTypes, ","),
+		string(metadata),
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set analysis sampling policy: %w", err)
+	}
+	return nil
+}
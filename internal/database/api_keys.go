@@ -13,12 +13,13 @@ import (
 
 // APIKey represents an API key in the database
 type APIKey struct {
-	Key        string     `json:"key"`
-	ClientName string     `json:"client_name"`
-	IsActive   bool       `json:"is_active"`
-	CreatedAt  time.Time  `json:"created_at"`
-	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
-	IsOnline   bool       `json:"is_online"` // Computed field: true if last_seen_at is within last 30 seconds
+	Key            string     `json:"key"`
+	ClientName     string     `json:"client_name"`
+	OrganizationID string     `json:"organization_id,omitempty"` // Tenant whose data the key may ingest and query
+	IsActive       bool       `json:"is_active"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastSeenAt     *time.Time `json:"last_seen_at,omitempty"`
+	IsOnline       bool       `json:"is_online"` // Computed field: true if last_seen_at is within last 30 seconds
 }
 
 // APIKeyStore manages API keys
@@ -52,77 +53,54 @@ func (s *APIKeyStore) initSchema() error {
 		return fmt.Errorf("failed to create base table: %w", err)
 	}
 	
-	// Step 2: Check existing columns using PRAGMA (CRITICAL: before any index creation)
-	rows, err := s.db.Query("PRAGMA table_info(api_keys)")
-	if err != nil {
-		return fmt.Errorf("failed to query table info: %w", err)
-	}
-	defer rows.Close()
-	
-	columns := make(map[string]bool)
-	for rows.Next() {
-		var cid int
-		var name, dataType string
-		var notNull, pk int
-		var defaultValue interface{}
-		
-		if err := rows.Scan(&cid, &name, &dataType, &notNull, &defaultValue, &pk); err != nil {
-			return fmt.Errorf("failed to scan table info: %w", err)
-		}
-		columns[name] = true
-	}
-	
-	// Step 3: Add last_seen_at column if it doesn't exist (MIGRATION)
-	needsMigration := !columns["last_seen_at"]
-	if needsMigration {
-		log.Printf("[MIGRATION] Adding last_seen_at column to api_keys table")
-		_, err = s.db.Exec("ALTER TABLE api_keys ADD COLUMN last_seen_at DATETIME")
-		if err != nil {
-			return fmt.Errorf("failed to add last_seen_at column: %w", err)
-		}
-		log.Printf("[MIGRATION] Successfully added last_seen_at column")
-		// Update our column map to reflect the new column
-		columns["last_seen_at"] = true
-	}
-	
-	// Step 4: Create indexes ONLY after migration is complete
-	// Verify column exists before creating index (error suppression)
-	hasLastSeenAt := columns["last_seen_at"]
-	
 	// Create is_active index (always safe - column exists in base schema)
-	_, err = s.db.Exec("CREATE INDEX IF NOT EXISTS idx_api_keys_active ON api_keys(is_active)")
-	if err != nil {
-		// Suppress error - log but don't fail startup
-		log.Printf("[WARNING] Failed to create is_active index (non-fatal): %v", err)
-	} else {
-		log.Printf("[INFO] Created/verified is_active index")
-	}
-	
-	// Create last_seen_at index ONLY if column exists (after migration check)
-	if hasLastSeenAt {
-		_, err = s.db.Exec("CREATE INDEX IF NOT EXISTS idx_api_keys_last_seen ON api_keys(last_seen_at)")
-		if err != nil {
-			// Suppress error - log but don't fail startup
-			log.Printf("[WARNING] Failed to create last_seen_at index (non-fatal): %v", err)
-		} else {
-			log.Printf("[INFO] Created/verified last_seen_at index")
-		}
-	} else {
-		log.Printf("[INFO] Skipping last_seen_at index creation (column not present)")
+	if _, err := s.db.Exec("CREATE INDEX IF NOT EXISTS idx_api_keys_active ON api_keys(is_active)"); err != nil {
+		return fmt.Errorf("failed to create is_active index: %w", err)
 	}
-	
-	log.Printf("[INFO] Database migration complete for api_keys table")
-	return nil
+
+	_, err = RunMigrations(s.db, "api_keys", apiKeyMigrations)
+	return err
+}
+
+// apiKeyMigrations are the versioned schema changes for api_keys
+var apiKeyMigrations = []Migration{
+	{
+		Version:     1,
+		Description: "add last_seen_at to api_keys",
+		Up: func(tx *sql.Tx) error {
+			if err := AddColumnIfMissing(tx, "api_keys", "last_seen_at", "DATETIME"); err != nil {
+				return err
+			}
+			_, err := tx.Exec("CREATE INDEX IF NOT EXISTS idx_api_keys_last_seen ON api_keys(last_seen_at)")
+			return err
+		},
+	},
+	{
+		Version:     2,
+		Description: "add organization_id to api_keys",
+		Up: func(tx *sql.Tx) error {
+			if err := AddColumnIfMissing(tx, "api_keys", "organization_id", "TEXT"); err != nil {
+				return err
+			}
+			// Keys were generated with the organization ID as their client name
+			if _, err := tx.Exec("UPDATE api_keys SET organization_id = client_name WHERE organization_id IS NULL"); err != nil {
+				return err
+			}
+			_, err := tx.Exec("CREATE INDEX IF NOT EXISTS idx_api_keys_organization_id ON api_keys(organization_id)")
+			return err
+		},
+	},
 }
 
-// GenerateKey generates a new API key
-func (s *APIKeyStore) GenerateKey(clientName string) (string, error) {
+// GenerateKey generates a new API key belonging to the organization
+func (s *APIKeyStore) GenerateKey(clientName, organizationID string) (string, error) {
 	key := "hive_" + uuid.New().String()
 	
 	_, err := s.db.Exec(
-		"INSERT INTO api_keys (key, client_name, is_active, created_at) VALUES (?, ?, ?, ?)",
+		"INSERT INTO api_keys (key, client_name, organization_id, is_active, created_at) VALUES (?, ?, ?, ?, ?)",
 		key,
 		clientName,
+		organizationID,
 		true,
 		time.Now(),
 	)
@@ -151,6 +129,23 @@ func (s *APIKeyStore) ValidateKey(key string) (bool, error) {
 	return isActive, nil
 }
 
+// GetKeyOrganization returns the organization an active API key belongs to ("" if the key is
+// unknown, inactive or has no organization)
+func (s *APIKeyStore) GetKeyOrganization(key string) (string, error) {
+	var organizationID sql.NullString
+	err := s.db.QueryRow(
+		"SELECT organization_id FROM api_keys WHERE key = ? AND is_active = TRUE",
+		key,
+	).Scan(&organizationID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up API key organization: %w", err)
+	}
+	return organizationID.String, nil
+}
+
 // RevokeKey revokes an API key (sets is_active = FALSE)
 func (s *APIKeyStore) RevokeKey(key string) error {
 	result, err := s.db.Exec(
@@ -252,7 +247,7 @@ func (s *APIKeyStore) UpdateLastSeen(key string) error {
 // ListKeys returns all API keys with online status
 func (s *APIKeyStore) ListKeys() ([]APIKey, error) {
 	rows, err := s.db.Query(
-		"SELECT key, client_name, is_active, created_at, last_seen_at FROM api_keys ORDER BY created_at DESC",
+		"SELECT key, client_name, COALESCE(organization_id, ''), is_active, created_at, last_seen_at FROM api_keys ORDER BY created_at DESC",
 	)
 	if err != nil {
 		return nil, err
@@ -266,7 +261,7 @@ func (s *APIKeyStore) ListKeys() ([]APIKey, error) {
 	for rows.Next() {
 		var key APIKey
 		var lastSeenAt sql.NullTime
-		if err := rows.Scan(&key.Key, &key.ClientName, &key.IsActive, &key.CreatedAt, &lastSeenAt); err != nil {
+		if err := rows.Scan(&key.Key, &key.ClientName, &key.OrganizationID, &key.IsActive, &key.CreatedAt, &lastSeenAt); err != nil {
 			return nil, err
 		}
 		
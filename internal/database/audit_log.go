@@ -3,9 +3,9 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
 	"time"
 )
 
@@ -13,8 +13,17 @@ import (
 type AuditAction string
 
 const (
-	AuditActionSearch AuditAction = "SEARCH"
-	AuditActionIngest AuditAction = "INGEST"
+	AuditActionSearch        AuditAction = "SEARCH"
+	AuditActionIngest        AuditAction = "INGEST"
+	AuditActionContradiction AuditAction = "CONTRADICTION"
+	AuditActionRechunk       AuditAction = "RECHUNK"
+	AuditActionDBMaintenance AuditAction = "DB_MAINTENANCE"
+	AuditActionPurge         AuditAction = "PURGE"
+	AuditActionRetag         AuditAction = "RETAG"
+	AuditActionReconcile     AuditAction = "RECONCILE"
+	AuditActionInvite        AuditAction = "INVITE"
+	AuditActionConfigChange  AuditAction = "CONFIG_CHANGE"
+	AuditActionDelete        AuditAction = "DELETE"
 )
 
 // AuditLog represents an audit log entry
@@ -24,6 +33,8 @@ type AuditLog struct {
 	ClientIP  string    `json:"client_ip"`
 	Action    string    `json:"action"` // SEARCH or INGEST
 	Details   string    `json:"details"`
+	// Only filled in by EachLog, for exports that span organizations
+	OrganizationID string `json:"organization_id,omitempty"`
 }
 
 // AuditLogStore manages audit logs
@@ -61,61 +72,40 @@ func (s *AuditLogStore) initSchema() error {
 		return fmt.Errorf("failed to create base schema: %w", err)
 	}
 	
-	// Step 2: Check if organization_id column exists and add if missing
-	rows, err := s.db.Query("PRAGMA table_info(audit_logs)")
-	if err != nil {
-		return fmt.Errorf("failed to query table info: %w", err)
-	}
-	defer rows.Close()
-	
-	hasOrgID := false
-	for rows.Next() {
-		var cid int
-		var name, dataType string
-		var notNull, pk int
-		var defaultValue interface{}
-		
-		if err := rows.Scan(&cid, &name, &dataType, &notNull, &defaultValue, &pk); err != nil {
-			return fmt.Errorf("failed to scan table info: %w", err)
-		}
-		if name == "organization_id" {
-			hasOrgID = true
-			break
-		}
-	}
-	
-	// Step 3: Add organization_id column if missing
-	if !hasOrgID {
-		log.Printf("[MIGRATION] Adding organization_id column to audit_logs table")
-		_, err = s.db.Exec("ALTER TABLE audit_logs ADD COLUMN organization_id TEXT")
-		if err != nil {
-			return fmt.Errorf("failed to add organization_id column: %w", err)
-		}
-		log.Printf("[MIGRATION] Successfully added organization_id column")
-		
-		// Create index for organization_id
-		_, err = s.db.Exec("CREATE INDEX IF NOT EXISTS idx_audit_logs_organization_id ON audit_logs(organization_id)")
-		if err != nil {
-			// Log but don't fail - index creation is optional
-			log.Printf("Warning: Failed to create organization_id index: %v", err)
-		}
-	}
-	
-	return nil
+	_, err = RunMigrations(s.db, "audit_logs", auditLogMigrations)
+	return err
+}
+
+// auditLogMigrations are the versioned schema changes for audit_logs
+var auditLogMigrations = []Migration{
+	{
+		Version:     1,
+		Description: "add organization_id to audit_logs",
+		Up: func(tx *sql.Tx) error {
+			if err := AddColumnIfMissing(tx, "audit_logs", "organization_id", "TEXT"); err != nil {
+				return err
+			}
+			_, err := tx.Exec("CREATE INDEX IF NOT EXISTS idx_audit_logs_organization_id ON audit_logs(organization_id)")
+			return err
+		},
+	},
 }
 
 // LogAction logs a new audit entry
 // organizationID is optional - if provided, it will be stored for multi-tenancy filtering
 func (s *AuditLogStore) LogAction(clientIP string, action AuditAction, details string, organizationID string) error {
-	_, err := s.db.Exec(
-		"INSERT INTO audit_logs (timestamp, client_ip, action, details, organization_id) VALUES (?, ?, ?, ?, ?)",
-		time.Now(),
-		clientIP,
-		string(action),
-		details,
-		organizationID,
-	)
-	return err
+	now := time.Now()
+	return RetryOnBusy(context.Background(), func() error {
+		_, err := s.db.Exec(
+			"INSERT INTO audit_logs (timestamp, client_ip, action, details, organization_id) VALUES (?, ?, ?, ?, ?)",
+			now,
+			clientIP,
+			string(action),
+			details,
+			organizationID,
+		)
+		return err
+	})
 }
 
 // GetRecentLogs returns the last N audit logs, sorted by timestamp descending
@@ -162,3 +152,92 @@ func (s *AuditLogStore) GetRecentLogs(limit int, actionFilter string, organizati
 
 	return logs, nil
 }
+
+// AuditLogFilter selects the audit logs Query returns. Zero values don't filter; an empty
+// OrganizationID lists all organizations.
+type AuditLogFilter struct {
+	OrganizationID string
+	Action         string
+	From           time.Time // Logged at or after
+	To             time.Time // Logged before
+	Limit          int
+	Offset         int
+}
+
+// where returns the SQL WHERE clause and arguments that select the filter's logs
+func (f AuditLogFilter) where() (string, []interface{}) {
+	where := "WHERE 1 = 1"
+	var args []interface{}
+	if f.OrganizationID != "" {
+		where += " AND organization_id = ?"
+		args = append(args, f.OrganizationID)
+	}
+	if f.Action != "" {
+		where += " AND action = ?"
+		args = append(args, f.Action)
+	}
+	// julianday compares the instants, whatever offset the timestamps were stored with
+	if !f.From.IsZero() {
+		where += " AND julianday(timestamp) >= julianday(?)"
+		args = append(args, f.From.UTC().Format(time.RFC3339Nano))
+	}
+	if !f.To.IsZero() {
+		where += " AND julianday(timestamp) < julianday(?)"
+		args = append(args, f.To.UTC().Format(time.RFC3339Nano))
+	}
+	return where, args
+}
+
+// Query returns one page of the audit logs matching the filter, newest first, with the total number of matching logs
+func (s *AuditLogStore) Query(filter AuditLogFilter) ([]AuditLog, int, error) {
+	where, args := filter.where()
+
+	var total int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM audit_logs "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	rows, err := s.db.Query(
+		"SELECT id, timestamp, client_ip, action, details FROM audit_logs "+where+" ORDER BY timestamp DESC, id DESC LIMIT ? OFFSET ?",
+		append(args, filter.Limit, filter.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []AuditLog
+	for rows.Next() {
+		var log AuditLog
+		if err := rows.Scan(&log.ID, &log.Timestamp, &log.ClientIP, &log.Action, &log.Details); err != nil {
+			return nil, 0, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, total, rows.Err()
+}
+
+// EachLog calls fn with every audit log matching the filter (ignoring Limit and Offset), oldest first,
+// reading them one at a time so exports of any size don't build up in memory. It stops at fn's first error.
+func (s *AuditLogStore) EachLog(filter AuditLogFilter, fn func(AuditLog) error) error {
+	where, args := filter.where()
+	rows, err := s.db.Query(
+		"SELECT id, timestamp, client_ip, action, COALESCE(details, ''), COALESCE(organization_id, '') FROM audit_logs "+where+" ORDER BY timestamp, id",
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to export audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var log AuditLog
+		if err := rows.Scan(&log.ID, &log.Timestamp, &log.ClientIP, &log.Action, &log.Details, &log.OrganizationID); err != nil {
+			return err
+		}
+		if err := fn(log); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
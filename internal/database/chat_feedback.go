@@ -0,0 +1,169 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Chat feedback ratings
+const (
+	FeedbackUp   = 1
+	FeedbackDown = -1
+)
+
+// ChatFeedback is a user's rating of an assistant chat message
+type ChatFeedback struct {
+	MessageID      int64     `json:"message_id"`
+	SessionID      string    `json:"session_id"`
+	UserID         string    `json:"user_id"`
+	OrganizationID string    `json:"organization_id"`
+	Rating         int       `json:"rating"` // FeedbackUp or FeedbackDown
+	Comment        string    `json:"comment,omitempty"`
+	Answer         string    `json:"answer,omitempty"` // The rated answer, kept for review and prompt tuning
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ChatFeedbackReport aggregates an organization's chat feedback
+type ChatFeedbackReport struct {
+	OrganizationID string         `json:"organization_id"`
+	Total          int            `json:"total"`
+	Up             int            `json:"up"`
+	Down           int            `json:"down"`
+	Satisfaction   float64        `json:"satisfaction"` // Share of ratings that are thumbs up (0-1)
+	RecentNegative []ChatFeedback `json:"recent_negative"`
+}
+
+// ChatFeedbackStore manages feedback on chat answers
+type ChatFeedbackStore struct {
+	db *sql.DB
+}
+
+// NewChatFeedbackStore creates a new chat feedback store
+func NewChatFeedbackStore(db *sql.DB) (*ChatFeedbackStore, error) {
+	store := &ChatFeedbackStore{db: db}
+	if err := store.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize chat feedback schema: %w", err)
+	}
+	return store, nil
+}
+
+// initSchema creates the chat_feedback table if it doesn't exist
+func (s *ChatFeedbackStore) initSchema() error {
+	const schema = `
+	CREATE TABLE IF NOT EXISTS chat_feedback (
+		message_id INTEGER NOT NULL,
+		session_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		organization_id TEXT NOT NULL,
+		rating INTEGER NOT NULL,
+		comment TEXT,
+		answer TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (message_id, user_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_chat_feedback_org ON chat_feedback(organization_id, updated_at);
+	`
+	_, err := s.db.Exec(schema)
+	return err
+}
+
+// SaveFeedback records or replaces the user's rating of a message
+func (s *ChatFeedbackStore) SaveFeedback(feedback *ChatFeedback) error {
+	if feedback.Rating != FeedbackUp && feedback.Rating != FeedbackDown {
+		return fmt.Errorf("invalid rating: %d", feedback.Rating)
+	}
+
+	now := time.Now()
+	err := RetryOnBusy(context.Background(), func() error {
+		_, err := s.db.Exec(
+			`INSERT INTO chat_feedback (message_id, session_id, user_id, organization_id, rating, comment, answer, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(message_id, user_id) DO UPDATE SET rating = excluded.rating, comment = excluded.comment, updated_at = excluded.updated_at`,
+			feedback.MessageID,
+			feedback.SessionID,
+			feedback.UserID,
+			feedback.OrganizationID,
+			feedback.Rating,
+			feedback.Comment,
+			feedback.Answer,
+			now,
+			now,
+		)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save chat feedback: %w", err)
+	}
+	return nil
+}
+
+// GetFeedback returns the user's rating of a message, or nil if they haven't rated it
+func (s *ChatFeedbackStore) GetFeedback(messageID int64, userID string) (*ChatFeedback, error) {
+	var feedback ChatFeedback
+	var comment, answer sql.NullString
+	err := s.db.QueryRow(
+		"SELECT message_id, session_id, user_id, organization_id, rating, comment, answer, created_at, updated_at FROM chat_feedback WHERE message_id = ? AND user_id = ?",
+		messageID,
+		userID,
+	).Scan(&feedback.MessageID, &feedback.SessionID, &feedback.UserID, &feedback.OrganizationID, &feedback.Rating, &comment, &answer, &feedback.CreatedAt, &feedback.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat feedback: %w", err)
+	}
+	feedback.Comment = comment.String
+	feedback.Answer = answer.String
+	return &feedback, nil
+}
+
+// Report aggregates the organization's feedback, including the most recent thumbs-down entries
+func (s *ChatFeedbackStore) Report(organizationID string, recentLimit int) (*ChatFeedbackReport, error) {
+	report := &ChatFeedbackReport{OrganizationID: organizationID, RecentNegative: []ChatFeedback{}}
+
+	var up, down sql.NullInt64
+	err := s.db.QueryRow(
+		"SELECT COUNT(*), SUM(CASE WHEN rating > 0 THEN 1 ELSE 0 END), SUM(CASE WHEN rating < 0 THEN 1 ELSE 0 END) FROM chat_feedback WHERE organization_id = ?",
+		organizationID,
+	).Scan(&report.Total, &up, &down)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate chat feedback: %w", err)
+	}
+	report.Up = int(up.Int64)
+	report.Down = int(down.Int64)
+	if report.Total > 0 {
+		report.Satisfaction = float64(report.Up) / float64(report.Total)
+	}
+
+	if recentLimit <= 0 {
+		recentLimit = 20
+	}
+	rows, err := s.db.Query(
+		"SELECT message_id, session_id, user_id, organization_id, rating, comment, answer, created_at, updated_at FROM chat_feedback WHERE organization_id = ? AND rating < 0 ORDER BY updated_at DESC LIMIT ?",
+		organizationID,
+		recentLimit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chat feedback: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var feedback ChatFeedback
+		var comment, answer sql.NullString
+		if err := rows.Scan(&feedback.MessageID, &feedback.SessionID, &feedback.UserID, &feedback.OrganizationID, &feedback.Rating, &comment, &answer, &feedback.CreatedAt, &feedback.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan chat feedback: %w", err)
+		}
+		feedback.Comment = comment.String
+		feedback.Answer = answer.String
+		report.RecentNegative = append(report.RecentNegative, feedback)
+	}
+	return report, rows.Err()
+}
@@ -0,0 +1,384 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrChatSessionNotFound is returned when a session doesn't exist or belongs to someone else
+var ErrChatSessionNotFound = errors.New("chat session not found")
+
+// ChatSummaryRole is the role of the message that stands in for a session's trimmed messages
+const ChatSummaryRole = "summary"
+
+// maxSummaryQuestions bounds how many earlier questions a session's summary message lists
+const maxSummaryQuestions = 20
+
+// ChatRetentionPolicy is how long an organization keeps chat sessions, and how many messages each session keeps
+type ChatRetentionPolicy struct {
+	OrganizationID string    `json:"organization_id"`
+	RetentionDays  int       `json:"retention_days"` // 0 keeps sessions indefinitely
+	MaxMessages    int       `json:"max_messages"`   // 0 keeps every message of a session
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ChatRetentionStore manages chat retention policies, pinned sessions and pruning of the
+// chat store's chat_sessions and chat_messages tables. Pinned sessions are never pruned.
+type ChatRetentionStore struct {
+	db *sql.DB
+}
+
+// NewChatRetentionStore creates a new chat retention store
+func NewChatRetentionStore(db *sql.DB) (*ChatRetentionStore, error) {
+	store := &ChatRetentionStore{db: db}
+	if err := store.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize chat retention schema: %w", err)
+	}
+	return store, nil
+}
+
+// initSchema creates the retention policy and pinned session tables if they don't exist
+func (s *ChatRetentionStore) initSchema() error {
+	const schema = `
+	CREATE TABLE IF NOT EXISTS chat_retention_policies (
+		organization_id TEXT PRIMARY KEY,
+		retention_days INTEGER NOT NULL DEFAULT 0,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE IF NOT EXISTS chat_session_pins (
+		session_id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		organization_id TEXT NOT NULL,
+		pinned_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_chat_session_pins_user ON chat_session_pins(user_id, organization_id);
+	`
+	if _, err := s.db.Exec(schema); err != nil {
+		return err
+	}
+	_, err := RunMigrations(s.db, "chat_retention", chatRetentionMigrations)
+	return err
+}
+
+// chatRetentionMigrations are the versioned schema changes for chat_retention_policies
+var chatRetentionMigrations = []Migration{
+	{
+		Version:     1,
+		Description: "add max_messages to chat_retention_policies",
+		Up: func(tx *sql.Tx) error {
+			return AddColumnIfMissing(tx, "chat_retention_policies", "max_messages", "INTEGER NOT NULL DEFAULT 0")
+		},
+	},
+}
+
+// GetRetentionPolicy returns the organization's policy (retention 0 if none is set)
+func (s *ChatRetentionStore) GetRetentionPolicy(ctx context.Context, organizationID string) (*ChatRetentionPolicy, error) {
+	policy := &ChatRetentionPolicy{OrganizationID: organizationID}
+	err := s.db.QueryRowContext(ctx,
+		"SELECT retention_days, max_messages, updated_at FROM chat_retention_policies WHERE organization_id = ?",
+		organizationID,
+	).Scan(&policy.RetentionDays, &policy.MaxMessages, &policy.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return policy, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat retention policy: %w", err)
+	}
+	return policy, nil
+}
+
+// SetRetentionDays sets how many days the organization keeps chat sessions (0 keeps them indefinitely)
+func (s *ChatRetentionStore) SetRetentionDays(ctx context.Context, organizationID string, days int) error {
+	if days < 0 {
+		return fmt.Errorf("retention days must not be negative")
+	}
+	return RetryOnBusy(ctx, func() error {
+		_, err := s.db.ExecContext(ctx,
+			`INSERT INTO chat_retention_policies (organization_id, retention_days, updated_at) VALUES (?, ?, ?)
+			ON CONFLICT(organization_id) DO UPDATE SET retention_days = excluded.retention_days, updated_at = excluded.updated_at`,
+			organizationID, days, time.Now(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to set chat retention policy: %w", err)
+		}
+		return nil
+	})
+}
+
+// SetMaxMessages sets how many messages each of the organization's sessions keeps (0 keeps every message)
+func (s *ChatRetentionStore) SetMaxMessages(ctx context.Context, organizationID string, maxMessages int) error {
+	if maxMessages < 0 {
+		return fmt.Errorf("max messages must not be negative")
+	}
+	return RetryOnBusy(ctx, func() error {
+		_, err := s.db.ExecContext(ctx,
+			`INSERT INTO chat_retention_policies (organization_id, max_messages, updated_at) VALUES (?, ?, ?)
+			ON CONFLICT(organization_id) DO UPDATE SET max_messages = excluded.max_messages, updated_at = excluded.updated_at`,
+			organizationID, maxMessages, time.Now(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to set chat history limit: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListRetentionPolicies returns the organizations that prune chat sessions
+func (s *ChatRetentionStore) ListRetentionPolicies(ctx context.Context) ([]ChatRetentionPolicy, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT organization_id, retention_days, max_messages, updated_at FROM chat_retention_policies WHERE retention_days > 0 ORDER BY organization_id",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chat retention policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []ChatRetentionPolicy
+	for rows.Next() {
+		var policy ChatRetentionPolicy
+		if err := rows.Scan(&policy.OrganizationID, &policy.RetentionDays, &policy.MaxMessages, &policy.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan chat retention policy: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+	return policies, rows.Err()
+}
+
+// SetPinned pins or unpins one of the user's sessions
+func (s *ChatRetentionStore) SetPinned(ctx context.Context, sessionID, userID, organizationID string, pinned bool) error {
+	return RetryOnBusy(ctx, func() error {
+		var owned int
+		err := s.db.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM chat_sessions WHERE id = ? AND user_id = ? AND organization_id = ?",
+			sessionID, userID, organizationID,
+		).Scan(&owned)
+		if err != nil {
+			return fmt.Errorf("failed to look up chat session: %w", err)
+		}
+		if owned == 0 {
+			return ErrChatSessionNotFound
+		}
+
+		if pinned {
+			_, err = s.db.ExecContext(ctx,
+				"INSERT OR IGNORE INTO chat_session_pins (session_id, user_id, organization_id, pinned_at) VALUES (?, ?, ?, ?)",
+				sessionID, userID, organizationID, time.Now(),
+			)
+		} else {
+			_, err = s.db.ExecContext(ctx, "DELETE FROM chat_session_pins WHERE session_id = ?", sessionID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update pinned chat session: %w", err)
+		}
+		return nil
+	})
+}
+
+// PinnedSessionIDs returns the IDs of the user's pinned sessions, most recently pinned first
+func (s *ChatRetentionStore) PinnedSessionIDs(ctx context.Context, userID, organizationID string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT session_id FROM chat_session_pins WHERE user_id = ? AND organization_id = ? ORDER BY pinned_at DESC",
+		userID, organizationID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pinned chat sessions: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan pinned chat session: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// PruneSessions deletes the organization's unpinned sessions (and their messages) that have
+// not been updated since cutoff. It returns the number of sessions deleted.
+func (s *ChatRetentionStore) PruneSessions(ctx context.Context, organizationID string, cutoff time.Time) (int, error) {
+	return s.deleteSessions(ctx, "organization_id = ? AND updated_at < ?", []interface{}{organizationID, cutoff.UTC()}, false)
+}
+
+// DeleteUserSessions deletes the user's sessions in the organization. Pinned sessions are kept
+// unless includePinned is set; a non-zero olderThan only deletes sessions not updated since then.
+func (s *ChatRetentionStore) DeleteUserSessions(ctx context.Context, userID, organizationID string, olderThan time.Time, includePinned bool) (int, error) {
+	where := "user_id = ? AND organization_id = ?"
+	args := []interface{}{userID, organizationID}
+	if !olderThan.IsZero() {
+		where += " AND updated_at < ?"
+		args = append(args, olderThan.UTC())
+	}
+	return s.deleteSessions(ctx, where, args, includePinned)
+}
+
+// deleteSessions deletes the sessions matching where (skipping pinned ones unless includePinned)
+// together with their messages and pins, and returns how many sessions were deleted.
+// Timestamps are compared in UTC, as CURRENT_TIMESTAMP stores them.
+func (s *ChatRetentionStore) deleteSessions(ctx context.Context, where string, args []interface{}, includePinned bool) (int, error) {
+	if !includePinned {
+		where += " AND id NOT IN (SELECT session_id FROM chat_session_pins)"
+	}
+	matching := "SELECT id FROM chat_sessions WHERE " + where
+
+	var deleted int64
+	err := RetryOnBusy(ctx, func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM chat_messages WHERE session_id IN ("+matching+")", args...); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM chat_session_pins WHERE session_id IN ("+matching+")", args...); err != nil {
+			return err
+		}
+		result, err := tx.ExecContext(ctx, "DELETE FROM chat_sessions WHERE "+where, args...)
+		if err != nil {
+			return err
+		}
+		if deleted, err = result.RowsAffected(); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete chat sessions: %w", err)
+	}
+	return int(deleted), nil
+}
+
+// TrimSession keeps the session's most recent maxMessages messages and folds the older ones into a
+// single summary message (role ChatSummaryRole) in their place, listing the questions asked so far.
+// A summary from an earlier trim is rolled into the new one. It returns the number of messages
+// removed; maxMessages 0 keeps every message.
+func (s *ChatRetentionStore) TrimSession(ctx context.Context, sessionID string, maxMessages int) (int, error) {
+	if maxMessages <= 0 {
+		return 0, nil
+	}
+
+	var trimmed int
+	err := RetryOnBusy(ctx, func() error {
+		trimmed = 0
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		rows, err := tx.QueryContext(ctx,
+			"SELECT id, role, content, created_at FROM chat_messages WHERE session_id = ? ORDER BY id",
+			sessionID,
+		)
+		if err != nil {
+			return err
+		}
+		type message struct {
+			id        int64
+			role      string
+			content   string
+			createdAt time.Time
+		}
+		var summary *message
+		var messages []message
+		for rows.Next() {
+			var m message
+			if err := rows.Scan(&m.id, &m.role, &m.content, &m.createdAt); err != nil {
+				rows.Close()
+				return err
+			}
+			if m.role == ChatSummaryRole {
+				summary = &m
+				continue
+			}
+			messages = append(messages, m)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(messages) <= maxMessages {
+			return nil
+		}
+
+		older := messages[:len(messages)-maxMessages]
+		var questions []string
+		for _, m := range older {
+			if m.role == "user" {
+				questions = append(questions, m.content)
+			}
+		}
+		previous := ""
+		if summary != nil {
+			previous = summary.content
+			older = append([]message{*summary}, older...)
+		}
+		content := rollChatSummary(previous, len(messages)-maxMessages, questions)
+
+		// The summary takes the place of the newest trimmed message, so it sorts before the kept ones
+		last := older[len(older)-1]
+		for _, m := range older {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM chat_messages WHERE id = ?", m.id); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO chat_messages (id, session_id, role, content, created_at) VALUES (?, ?, ?, ?, ?)",
+			last.id, sessionID, ChatSummaryRole, content, last.createdAt,
+		); err != nil {
+			return err
+		}
+		trimmed = len(messages) - maxMessages
+		return tx.Commit()
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to trim chat session: %w", err)
+	}
+	return trimmed, nil
+}
+
+// rollChatSummary adds newly trimmed messages (and the questions among them) to a session's summary.
+// The summary counts every trimmed message and lists the latest maxSummaryQuestions questions.
+func rollChatSummary(previous string, trimmed int, questions []string) string {
+	var earlier []string
+	if previous != "" {
+		var count int
+		lines := strings.Split(previous, "\n")
+		if _, err := fmt.Sscanf(lines[0], "Earlier in this conversation (%d messages):", &count); err == nil {
+			trimmed += count
+		}
+		for _, line := range lines[1:] {
+			if question, ok := strings.CutPrefix(line, "- "); ok {
+				earlier = append(earlier, question)
+			}
+		}
+	}
+	for _, question := range questions {
+		question = strings.Join(strings.Fields(question), " ")
+		if runes := []rune(question); len(runes) > 200 {
+			question = string(runes[:200]) + "..."
+		}
+		earlier = append(earlier, question)
+	}
+	if len(earlier) > maxSummaryQuestions {
+		earlier = earlier[len(earlier)-maxSummaryQuestions:]
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Earlier in this conversation (%d messages):", trimmed)
+	for _, question := range earlier {
+		b.WriteString("\n- ")
+		b.WriteString(question)
+	}
+	return b.String()
+}
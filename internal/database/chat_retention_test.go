@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestChatRetentionStore_TrimSessionKeepsRecentMessagesAndSummary(t *testing.T) {
+	db := openTestDB(t)
+	// The chat store's message table
+	if _, err := db.Exec(`CREATE TABLE chat_messages (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		role TEXT NOT NULL,
+		content TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		t.Fatalf("Failed to create chat_messages: %v", err)
+	}
+	store, err := NewChatRetentionStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create chat retention store: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := store.SetRetentionDays(ctx, "org-a", 30); err != nil {
+		t.Fatalf("SetRetentionDays failed: %v", err)
+	}
+	if err := store.SetMaxMessages(ctx, "org-a", 4); err != nil {
+		t.Fatalf("SetMaxMessages failed: %v", err)
+	}
+	policy, err := store.GetRetentionPolicy(ctx, "org-a")
+	if err != nil || policy.MaxMessages != 4 || policy.RetentionDays != 30 {
+		t.Fatalf("Expected retention 30 days and 4 messages, got %+v, %v", policy, err)
+	}
+
+	exchange := func(n int) {
+		t.Helper()
+		for _, m := range [][2]string{{"user", fmt.Sprintf("Question %d?", n)}, {"assistant", fmt.Sprintf("Answer %d.", n)}} {
+			if _, err := db.Exec("INSERT INTO chat_messages (session_id, role, content) VALUES (?, ?, ?)", "session-1", m[0], m[1]); err != nil {
+				t.Fatalf("Failed to add message: %v", err)
+			}
+		}
+	}
+	messages := func() [][2]string {
+		t.Helper()
+		rows, err := db.Query("SELECT role, content FROM chat_messages WHERE session_id = 'session-1' ORDER BY id")
+		if err != nil {
+			t.Fatalf("Failed to read messages: %v", err)
+		}
+		defer rows.Close()
+		var got [][2]string
+		for rows.Next() {
+			var m [2]string
+			rows.Scan(&m[0], &m[1])
+			got = append(got, m)
+		}
+		return got
+	}
+
+	for n := 1; n <= 3; n++ {
+		exchange(n)
+	}
+	trimmed, err := store.TrimSession(ctx, "session-1", policy.MaxMessages)
+	if err != nil || trimmed != 2 {
+		t.Fatalf("Expected the first exchange to be trimmed, got %d, %v", trimmed, err)
+	}
+
+	// Two more exchanges roll the earlier summary into a new one
+	exchange(4)
+	exchange(5)
+	if trimmed, err = store.TrimSession(ctx, "session-1", policy.MaxMessages); err != nil || trimmed != 4 {
+		t.Fatalf("Expected exchanges 2 and 3 to be trimmed, got %d, %v", trimmed, err)
+	}
+
+	got := messages()
+	if len(got) != 5 {
+		t.Fatalf("Expected a summary and the 4 most recent messages, got %v", got)
+	}
+	if got[0][0] != ChatSummaryRole {
+		t.Fatalf("Expected the summary first, got %v", got[0])
+	}
+	wantSummary := "Earlier in this conversation (6 messages):\n- Question 1?\n- Question 2?\n- Question 3?"
+	if got[0][1] != wantSummary {
+		t.Errorf("Expected summary %q, got %q", wantSummary, got[0][1])
+	}
+	if got[1][1] != "Question 4?" || got[4][1] != "Answer 5." {
+		t.Errorf("Expected the last two exchanges to be kept in order, got %v", got[1:])
+	}
+
+	if trimmed, _ := store.TrimSession(ctx, "session-1", 0); trimmed != 0 || len(messages()) != 5 {
+		t.Error("Expected no limit to keep every message")
+	}
+}
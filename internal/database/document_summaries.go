@@ -0,0 +1,193 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DocumentSummary is an ingested document with its optional AI-generated summary
+type DocumentSummary struct {
+	OrganizationID string    `json:"organization_id"`
+	DocumentID     string    `json:"document_id"`
+	Filename       string    `json:"filename"`
+	ChunkCount     int       `json:"chunk_count"`
+	Summary        string    `json:"summary,omitempty"`
+	IngestedAt     time.Time `json:"ingested_at"`
+}
+
+// DocumentSummaryStore records ingested documents and their summaries per organization
+type DocumentSummaryStore struct {
+	db *sql.DB
+}
+
+// NewDocumentSummaryStore creates a new document summary store
+func NewDocumentSummaryStore(db *sql.DB) (*DocumentSummaryStore, error) {
+	store := &DocumentSummaryStore{db: db}
+	if err := store.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize document summary schema: %w", err)
+	}
+	return store, nil
+}
+
+// initSchema creates the document_summaries table if it doesn't exist
+func (s *DocumentSummaryStore) initSchema() error {
+	const schema = `
+	CREATE TABLE IF NOT EXISTS document_summaries (
+		organization_id TEXT NOT NULL,
+		document_id TEXT NOT NULL,
+		filename TEXT,
+		chunk_count INTEGER NOT NULL DEFAULT 0,
+		summary TEXT,
+		ingested_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (organization_id, document_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_document_summaries_ingested ON document_summaries(organization_id, ingested_at);
+	`
+	_, err := s.db.Exec(schema)
+	return err
+}
+
+// SaveDocument records or replaces a document; re-ingesting without a summary clears the old one
+func (s *DocumentSummaryStore) SaveDocument(doc *DocumentSummary) error {
+	if doc.DocumentID == "" {
+		return fmt.Errorf("document ID is required")
+	}
+	if doc.IngestedAt.IsZero() {
+		doc.IngestedAt = time.Now()
+	}
+
+	err := RetryOnBusy(context.Background(), func() error {
+		_, err := s.db.Exec(
+			`INSERT INTO document_summaries (organization_id, document_id, filename, chunk_count, summary, ingested_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT(organization_id, document_id) DO UPDATE SET filename = excluded.filename, chunk_count = excluded.chunk_count,
+				summary = excluded.summary, ingested_at = excluded.ingested_at`,
+			doc.OrganizationID,
+			doc.DocumentID,
+			doc.Filename,
+			doc.ChunkCount,
+			doc.Summary,
+			doc.IngestedAt,
+		)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save document summary: %w", err)
+	}
+	return nil
+}
+
+// DeleteDocument forgets a document of the organization (e.g. after its file was deleted)
+func (s *DocumentSummaryStore) DeleteDocument(organizationID, documentID string) error {
+	err := RetryOnBusy(context.Background(), func() error {
+		_, err := s.db.Exec("DELETE FROM document_summaries WHERE organization_id = ? AND document_id = ?", organizationID, documentID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete document summary: %w", err)
+	}
+	return nil
+}
+
+// GetDocument returns a document of the organization, or nil if it isn't recorded
+func (s *DocumentSummaryStore) GetDocument(organizationID, documentID string) (*DocumentSummary, error) {
+	row := s.db.QueryRow(
+		"SELECT organization_id, document_id, filename, chunk_count, summary, ingested_at FROM document_summaries WHERE organization_id = ? AND document_id = ?",
+		organizationID,
+		documentID,
+	)
+	doc, err := scanDocumentSummary(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document summary: %w", err)
+	}
+	return doc, nil
+}
+
+// ListDocuments returns the organization's documents, most recently ingested first
+func (s *DocumentSummaryStore) ListDocuments(organizationID string, limit, offset int) ([]DocumentSummary, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := s.db.Query(
+		"SELECT organization_id, document_id, filename, chunk_count, summary, ingested_at FROM document_summaries WHERE organization_id = ? ORDER BY ingested_at DESC LIMIT ? OFFSET ?",
+		organizationID,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	defer rows.Close()
+
+	docs := []DocumentSummary{}
+	for rows.Next() {
+		doc, err := scanDocumentSummary(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan document summary: %w", err)
+		}
+		docs = append(docs, *doc)
+	}
+	return docs, rows.Err()
+}
+
+// CountDocuments returns the number of documents recorded for the organization
+func (s *DocumentSummaryStore) CountDocuments(organizationID string) (int, error) {
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM document_summaries WHERE organization_id = ?", organizationID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count documents: %w", err)
+	}
+	return count, nil
+}
+
+// GetSummaries returns the summaries of the given documents keyed by document ID; documents without a summary are omitted
+func (s *DocumentSummaryStore) GetSummaries(organizationID string, documentIDs []string) (map[string]string, error) {
+	summaries := make(map[string]string)
+	if len(documentIDs) == 0 {
+		return summaries, nil
+	}
+
+	args := make([]interface{}, 0, len(documentIDs)+1)
+	args = append(args, organizationID)
+	for _, id := range documentIDs {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(documentIDs)), ",")
+	rows, err := s.db.Query(
+		"SELECT document_id, summary FROM document_summaries WHERE organization_id = ? AND document_id IN ("+placeholders+") AND summary IS NOT NULL AND summary != ''",
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document summaries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var documentID, summary string
+		if err := rows.Scan(&documentID, &summary); err != nil {
+			return nil, fmt.Errorf("failed to scan document summary: %w", err)
+		}
+		summaries[documentID] = summary
+	}
+	return summaries, rows.Err()
+}
+
+// scanDocumentSummary scans a row selected with the full column list
+func scanDocumentSummary(row interface{ Scan(...interface{}) error }) (*DocumentSummary, error) {
+	var doc DocumentSummary
+	var filename, summary sql.NullString
+	if err := row.Scan(&doc.OrganizationID, &doc.DocumentID, &filename, &doc.ChunkCount, &summary, &doc.IngestedAt); err != nil {
+		return nil, err
+	}
+	doc.Filename = filename.String
+	doc.Summary = summary.String
+	return &doc, nil
+}
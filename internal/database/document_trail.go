@@ -0,0 +1,141 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Sources of document trail entries
+const (
+	TrailSourceAuditLog  = "audit_log"
+	TrailSourceRuleEvent = "rule_event"
+	TrailSourceRuleMatch = "rule_match"
+)
+
+// maxTrailEntries bounds how many entries one source contributes to a document's trail
+const maxTrailEntries = 1000
+
+// DocumentTrailEntry is one thing that happened to a document, as recorded by one of the stores
+type DocumentTrailEntry struct {
+	Time    time.Time `json:"time"`
+	Source  string    `json:"source"` // audit_log, rule_event or rule_match
+	Type    string    `json:"type"`   // Audit action, rule event type or match type
+	Details string    `json:"details"`
+}
+
+// likeEscaper escapes the LIKE wildcards of a literal (used with ESCAPE '\')
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// DocumentTrail returns the organization's audit logs that mention one of the document references
+// (its ID or filename) in brackets, as audit details do ("uploaded file [report.pdf]"), oldest first
+func (s *AuditLogStore) DocumentTrail(organizationID string, refs []string) ([]DocumentTrailEntry, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	var conditions []string
+	args := []interface{}{organizationID}
+	for _, ref := range refs {
+		conditions = append(conditions, `details LIKE ? ESCAPE '\'`)
+		args = append(args, "%["+likeEscaper.Replace(ref)+"]%")
+	}
+	rows, err := s.db.Query(
+		"SELECT timestamp, action, COALESCE(details, '') FROM audit_logs WHERE organization_id = ? AND ("+strings.Join(conditions, " OR ")+") ORDER BY timestamp, id LIMIT ?",
+		append(args, maxTrailEntries)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit logs for document: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []DocumentTrailEntry
+	for rows.Next() {
+		entry := DocumentTrailEntry{Source: TrailSourceAuditLog}
+		if err := rows.Scan(&entry.Time, &entry.Type, &entry.Details); err != nil {
+			return nil, fmt.Errorf("failed to scan audit log: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// DocumentTrail returns the organization's rule events about the document (by ID or filename), oldest first
+func (s *RuleEventStore) DocumentTrail(organizationID string, refs []string) ([]DocumentTrailEntry, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	args := []interface{}{organizationID}
+	for _, ref := range refs {
+		args = append(args, ref)
+	}
+	rows, err := s.db.Query(
+		"SELECT created_at, event_type, COALESCE(status, ''), COALESCE(message, '') FROM rule_events WHERE organization_id = ? AND document IN ("+inPlaceholders(len(refs))+") ORDER BY created_at, id LIMIT ?",
+		append(args, maxTrailEntries)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rule events for document: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []DocumentTrailEntry
+	for rows.Next() {
+		var status, message string
+		entry := DocumentTrailEntry{Source: TrailSourceRuleEvent}
+		if err := rows.Scan(&entry.Time, &entry.Type, &status, &message); err != nil {
+			return nil, fmt.Errorf("failed to scan rule event: %w", err)
+		}
+		entry.Details = fmt.Sprintf("[%s] %s", status, message)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// DocumentTrail returns the organization's rule matches in which the document (by ID or filename) is
+// the uploaded or the matched document, oldest first
+func (s *RuleMatchStore) DocumentTrail(organizationID string, refs []string) ([]DocumentTrailEntry, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	args := []interface{}{organizationID}
+	for _, ref := range refs {
+		args = append(args, ref)
+	}
+	for _, ref := range refs {
+		args = append(args, ref)
+	}
+	in := inPlaceholders(len(refs))
+	rows, err := s.db.Query(
+		"SELECT created_at, COALESCE(match_type, ''), rule_id, COALESCE(rule_query, ''), COALESCE(uploaded_doc, ''), COALESCE(matched_doc, ''), COALESCE(ai_explanation, '') FROM rule_matches WHERE organization_id = ? AND (uploaded_doc IN ("+in+") OR matched_doc IN ("+in+")) ORDER BY created_at, id LIMIT ?",
+		append(args, maxTrailEntries)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rule matches for document: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []DocumentTrailEntry
+	for rows.Next() {
+		var ruleID int64
+		var ruleQuery, uploadedDoc, matchedDoc, explanation string
+		entry := DocumentTrailEntry{Source: TrailSourceRuleMatch}
+		if err := rows.Scan(&entry.Time, &entry.Type, &ruleID, &ruleQuery, &uploadedDoc, &matchedDoc, &explanation); err != nil {
+			return nil, fmt.Errorf("failed to scan rule match: %w", err)
+		}
+		entry.Details = fmt.Sprintf("Rule %d (%s) matched [%s]", ruleID, ruleQuery, uploadedDoc)
+		if matchedDoc != "" {
+			entry.Details += fmt.Sprintf(" against [%s]", matchedDoc)
+		}
+		if explanation != "" {
+			entry.Details += ": " + explanation
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// inPlaceholders returns n comma-separated SQL placeholders
+func inPlaceholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
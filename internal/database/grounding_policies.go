@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// GroundingPolicy controls how strictly chat answers must stick to retrieved context
+type GroundingPolicy struct {
+	OrganizationID   string    `json:"organization_id"`
+	Enabled          bool      `json:"enabled"`            // Instruct the model to answer only from the provided context
+	VerifyAnswers    bool      `json:"verify_answers"`     // Run a second AI pass that checks the answer against the cited chunks
+	MinCitations     int       `json:"min_citations"`      // Refuse with "insufficient evidence" unless this many chunks support the answer (0 disables)
+	MinCitationScore float64   `json:"min_citation_score"` // Lowest retrieval score a chunk needs to count towards MinCitations
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// GroundingPolicyStore manages per-organization answer grounding policies
+type GroundingPolicyStore struct {
+	db *sql.DB
+}
+
+// NewGroundingPolicyStore creates a new grounding policy store
+func NewGroundingPolicyStore(db *sql.DB) (*GroundingPolicyStore, error) {
+	store := &GroundingPolicyStore{db: db}
+	if err := store.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize grounding policy schema: %w", err)
+	}
+	return store, nil
+}
+
+// initSchema creates the grounding_policies table if it doesn't exist
+func (s *GroundingPolicyStore) initSchema() error {
+	const schema = `
+	CREATE TABLE IF NOT EXISTS grounding_policies (
+		organization_id TEXT PRIMARY KEY,
+		enabled INTEGER NOT NULL DEFAULT 0,
+		verify_answers INTEGER NOT NULL DEFAULT 0,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	`
+	if _, err := s.db.Exec(schema); err != nil {
+		return err
+	}
+	_, err := RunMigrations(s.db, "grounding_policies", groundingPolicyMigrations)
+	return err
+}
+
+// groundingPolicyMigrations are the versioned schema changes for grounding_policies
+var groundingPolicyMigrations = []Migration{
+	{
+		Version:     1,
+		Description: "add minimum citation policy to grounding_policies",
+		Up: func(tx *sql.Tx) error {
+			if err := AddColumnIfMissing(tx, "grounding_policies", "min_citations", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+				return err
+			}
+			return AddColumnIfMissing(tx, "grounding_policies", "min_citation_score", "REAL NOT NULL DEFAULT 0")
+		},
+	},
+}
+
+// GetPolicy returns the organization's grounding policy, or nil if none is set
+func (s *GroundingPolicyStore) GetPolicy(organizationID string) (*GroundingPolicy, error) {
+	var policy GroundingPolicy
+	err := s.db.QueryRow(
+		"SELECT organization_id, enabled, verify_answers, min_citations, min_citation_score, updated_at FROM grounding_policies WHERE organization_id = ?",
+		organizationID,
+	).Scan(&policy.OrganizationID, &policy.Enabled, &policy.VerifyAnswers, &policy.MinCitations, &policy.MinCitationScore, &policy.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get grounding policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// SetPolicy creates or replaces the organization's grounding policy
+func (s *GroundingPolicyStore) SetPolicy(policy GroundingPolicy) error {
+	_, err := s.db.Exec(
+		"INSERT INTO grounding_policies (organization_id, enabled, verify_answers, min_citations, min_citation_score, updated_at) VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT(organization_id) DO UPDATE SET enabled = excluded.enabled, verify_answers = excluded.verify_answers, min_citations = excluded.min_citations, min_citation_score = excluded.min_citation_score, updated_at = excluded.updated_at",
+		policy.OrganizationID,
+		policy.Enabled,
+		policy.VerifyAnswers,
+		policy.MinCitations,
+		policy.MinCitationScore,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set grounding policy: %w", err)
+	}
+	return nil
+}
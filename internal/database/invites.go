@@ -0,0 +1,219 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultInviteTTL is how long an invite link stays valid
+const DefaultInviteTTL = 7 * 24 * time.Hour
+
+var (
+	// ErrInviteNotFound is returned for unknown or revoked invite tokens
+	ErrInviteNotFound = errors.New("invite not found")
+	// ErrInviteExpired is returned when an invite is redeemed after its expiry
+	ErrInviteExpired = errors.New("invite has expired")
+	// ErrInviteAccepted is returned when an invite has already been redeemed
+	ErrInviteAccepted = errors.New("invite has already been accepted")
+)
+
+// Invite is a pending invitation for someone to join an organization with a role.
+// Only a hash of the token is stored; the token itself is handed out once, in the invite link.
+type Invite struct {
+	ID             string     `json:"id"`
+	Email          string     `json:"email"`
+	Role           UserRole   `json:"role"`
+	OrganizationID string     `json:"organization_id"`
+	InvitedBy      string     `json:"invited_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+}
+
+// InviteStore manages pending user invites
+type InviteStore struct {
+	db *sql.DB
+}
+
+// NewInviteStore creates a new invite store
+func NewInviteStore(db *sql.DB) (*InviteStore, error) {
+	store := &InviteStore{db: db}
+	if err := store.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize invites schema: %w", err)
+	}
+	return store, nil
+}
+
+// initSchema creates the user_invites table if it doesn't exist
+func (s *InviteStore) initSchema() error {
+	const schema = `
+	CREATE TABLE IF NOT EXISTS user_invites (
+		id TEXT PRIMARY KEY,
+		token_hash TEXT NOT NULL UNIQUE,
+		email TEXT NOT NULL,
+		role TEXT NOT NULL,
+		organization_id TEXT NOT NULL,
+		invited_by TEXT NOT NULL DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		expires_at DATETIME NOT NULL,
+		accepted_at DATETIME
+	);
+	CREATE INDEX IF NOT EXISTS idx_user_invites_org ON user_invites(organization_id, accepted_at);
+	`
+	_, err := s.db.Exec(schema)
+	return err
+}
+
+// hashInviteToken returns the stored form of an invite token
+func hashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateInvite stores a new invite and returns it with its one-time token.
+// Any earlier pending invite for the same email in the organization is replaced.
+func (s *InviteStore) CreateInvite(ctx context.Context, email string, role UserRole, organizationID, invitedBy string, ttl time.Duration) (*Invite, string, error) {
+	if ttl <= 0 {
+		ttl = DefaultInviteTTL
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", fmt.Errorf("failed to generate invite token: %w", err)
+	}
+	token := hex.EncodeToString(raw)
+
+	now := time.Now()
+	invite := &Invite{
+		ID:             uuid.New().String(),
+		Email:          strings.ToLower(strings.TrimSpace(email)),
+		Role:           role,
+		OrganizationID: organizationID,
+		InvitedBy:      invitedBy,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(ttl),
+	}
+
+	err := RetryOnBusy(ctx, func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx,
+			"DELETE FROM user_invites WHERE organization_id = ? AND email = ? AND accepted_at IS NULL",
+			invite.OrganizationID, invite.Email,
+		); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO user_invites (id, token_hash, email, role, organization_id, invited_by, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			invite.ID, hashInviteToken(token), invite.Email, string(invite.Role), invite.OrganizationID, invite.InvitedBy, invite.CreatedAt, invite.ExpiresAt,
+		); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create invite: %w", err)
+	}
+	return invite, token, nil
+}
+
+// scanInvite reads an invite row
+func scanInvite(scanner interface{ Scan(...interface{}) error }) (*Invite, error) {
+	var invite Invite
+	var role string
+	var acceptedAt sql.NullTime
+	if err := scanner.Scan(&invite.ID, &invite.Email, &role, &invite.OrganizationID, &invite.InvitedBy, &invite.CreatedAt, &invite.ExpiresAt, &acceptedAt); err != nil {
+		return nil, err
+	}
+	invite.Role = UserRole(role)
+	if acceptedAt.Valid {
+		invite.AcceptedAt = &acceptedAt.Time
+	}
+	return &invite, nil
+}
+
+const inviteColumns = "id, email, role, organization_id, invited_by, created_at, expires_at, accepted_at"
+
+// GetPendingInvite returns the invite for a token if it can still be redeemed
+func (s *InviteStore) GetPendingInvite(ctx context.Context, token string) (*Invite, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+inviteColumns+" FROM user_invites WHERE token_hash = ?", hashInviteToken(token))
+	invite, err := scanInvite(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrInviteNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invite: %w", err)
+	}
+	if invite.AcceptedAt != nil {
+		return nil, ErrInviteAccepted
+	}
+	if time.Now().After(invite.ExpiresAt) {
+		return nil, ErrInviteExpired
+	}
+	return invite, nil
+}
+
+// MarkAccepted records that the invite was redeemed, failing if it was redeemed concurrently
+func (s *InviteStore) MarkAccepted(ctx context.Context, id string) error {
+	return RetryOnBusy(ctx, func() error {
+		result, err := s.db.ExecContext(ctx, "UPDATE user_invites SET accepted_at = ? WHERE id = ? AND accepted_at IS NULL", time.Now(), id)
+		if err != nil {
+			return fmt.Errorf("failed to accept invite: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return ErrInviteAccepted
+		}
+		return nil
+	})
+}
+
+// ListPendingInvites returns the organization's invites that have not been accepted, newest first
+func (s *InviteStore) ListPendingInvites(ctx context.Context, organizationID string) ([]Invite, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+inviteColumns+" FROM user_invites WHERE organization_id = ? AND accepted_at IS NULL ORDER BY created_at DESC",
+		organizationID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invites: %w", err)
+	}
+	defer rows.Close()
+
+	var invites []Invite
+	for rows.Next() {
+		invite, err := scanInvite(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan invite: %w", err)
+		}
+		invites = append(invites, *invite)
+	}
+	return invites, rows.Err()
+}
+
+// RevokeInvite deletes a pending invite in the organization
+func (s *InviteStore) RevokeInvite(ctx context.Context, organizationID, id string) error {
+	return RetryOnBusy(ctx, func() error {
+		result, err := s.db.ExecContext(ctx, "DELETE FROM user_invites WHERE id = ? AND organization_id = ? AND accepted_at IS NULL", id, organizationID)
+		if err != nil {
+			return fmt.Errorf("failed to revoke invite: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return ErrInviteNotFound
+		}
+		return nil
+	})
+}
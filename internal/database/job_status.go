@@ -0,0 +1,190 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Job states recorded in the job status store
+const (
+	JobStateQueued  = "queued"
+	JobStateRunning = "running"
+	JobStateDone    = "done"
+	JobStateFailed  = "failed"
+)
+
+// JobStatus is the observable state of a long-running background job (re-chunk, re-tag, ...)
+type JobStatus struct {
+	ID             string     `json:"id"`
+	Type           string     `json:"type"`
+	OrganizationID string     `json:"organization_id"`
+	RequestedBy    string     `json:"requested_by,omitempty"`
+	State          string     `json:"state"` // queued, running, done, failed
+	Processed      int        `json:"processed"`
+	Total          int        `json:"total"`
+	Error          string     `json:"error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+}
+
+// JobStatusStore persists job state so progress is visible from any server instance and survives restarts
+type JobStatusStore struct {
+	db *sql.DB
+}
+
+// NewJobStatusStore creates a new job status store
+func NewJobStatusStore(db *sql.DB) (*JobStatusStore, error) {
+	store := &JobStatusStore{db: db}
+	if err := store.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize job status schema: %w", err)
+	}
+	return store, nil
+}
+
+// initSchema creates the job_status table if it doesn't exist
+func (s *JobStatusStore) initSchema() error {
+	const schema = `
+	CREATE TABLE IF NOT EXISTS job_status (
+		id TEXT PRIMARY KEY,
+		type TEXT NOT NULL,
+		organization_id TEXT NOT NULL DEFAULT '',
+		requested_by TEXT NOT NULL DEFAULT '',
+		state TEXT NOT NULL,
+		processed INTEGER NOT NULL DEFAULT 0,
+		total INTEGER NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		started_at DATETIME,
+		finished_at DATETIME
+	);
+	CREATE INDEX IF NOT EXISTS idx_job_status_state ON job_status(state, organization_id);
+	`
+	_, err := s.db.Exec(schema)
+	return err
+}
+
+// JobQueued records a new job. A job that is already recorded (e.g. enqueued by another instance) is left as is.
+func (s *JobStatusStore) JobQueued(ctx context.Context, jobID, jobType, organizationID, requestedBy string) error {
+	now := time.Now()
+	return RetryOnBusy(ctx, func() error {
+		_, err := s.db.ExecContext(ctx,
+			"INSERT OR IGNORE INTO job_status (id, type, organization_id, requested_by, state, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			jobID, jobType, organizationID, requestedBy, JobStateQueued, now, now,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record queued job: %w", err)
+		}
+		return nil
+	})
+}
+
+// JobRunning marks the job as started with the number of items it will process
+func (s *JobStatusStore) JobRunning(ctx context.Context, jobID string, total int) error {
+	now := time.Now()
+	return RetryOnBusy(ctx, func() error {
+		_, err := s.db.ExecContext(ctx,
+			"UPDATE job_status SET state = ?, total = ?, processed = 0, started_at = ?, updated_at = ? WHERE id = ?",
+			JobStateRunning, total, now, now, jobID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record running job: %w", err)
+		}
+		return nil
+	})
+}
+
+// JobProgress records how many items the job has processed so far
+func (s *JobStatusStore) JobProgress(ctx context.Context, jobID string, processed, total int) error {
+	return RetryOnBusy(ctx, func() error {
+		_, err := s.db.ExecContext(ctx,
+			"UPDATE job_status SET processed = ?, total = ?, updated_at = ? WHERE id = ?",
+			processed, total, time.Now(), jobID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record job progress: %w", err)
+		}
+		return nil
+	})
+}
+
+// JobFinished marks the job done, or failed with jobErr
+func (s *JobStatusStore) JobFinished(ctx context.Context, jobID string, jobErr error) error {
+	state, message := JobStateDone, ""
+	if jobErr != nil {
+		state, message = JobStateFailed, jobErr.Error()
+	}
+	now := time.Now()
+	return RetryOnBusy(ctx, func() error {
+		_, err := s.db.ExecContext(ctx,
+			"UPDATE job_status SET state = ?, error = ?, finished_at = ?, updated_at = ? WHERE id = ?",
+			state, message, now, now, jobID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record finished job: %w", err)
+		}
+		return nil
+	})
+}
+
+const jobStatusColumns = "id, type, organization_id, requested_by, state, processed, total, error, created_at, updated_at, started_at, finished_at"
+
+// scanJobStatus reads a job status row
+func scanJobStatus(scanner interface{ Scan(...interface{}) error }) (*JobStatus, error) {
+	var job JobStatus
+	var startedAt, finishedAt sql.NullTime
+	if err := scanner.Scan(&job.ID, &job.Type, &job.OrganizationID, &job.RequestedBy, &job.State, &job.Processed, &job.Total, &job.Error, &job.CreatedAt, &job.UpdatedAt, &startedAt, &finishedAt); err != nil {
+		return nil, err
+	}
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	return &job, nil
+}
+
+// GetJob returns the job's status, or nil if the job is unknown
+func (s *JobStatusStore) GetJob(ctx context.Context, jobID string) (*JobStatus, error) {
+	job, err := scanJobStatus(s.db.QueryRowContext(ctx, "SELECT "+jobStatusColumns+" FROM job_status WHERE id = ?", jobID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job status: %w", err)
+	}
+	return job, nil
+}
+
+// ListActiveJobs returns queued and running jobs, oldest first.
+// An empty organizationID lists active jobs for all organizations.
+func (s *JobStatusStore) ListActiveJobs(ctx context.Context, organizationID string) ([]JobStatus, error) {
+	query := "SELECT " + jobStatusColumns + " FROM job_status WHERE state IN (?, ?)"
+	args := []interface{}{JobStateQueued, JobStateRunning}
+	if organizationID != "" {
+		query += " AND organization_id = ?"
+		args = append(args, organizationID)
+	}
+	rows, err := s.db.QueryContext(ctx, query+" ORDER BY created_at ASC", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []JobStatus
+	for rows.Next() {
+		job, err := scanJobStatus(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job status: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+	return jobs, rows.Err()
+}
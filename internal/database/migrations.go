@@ -0,0 +1,123 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Migration is a versioned schema change for one component (table or store).
+// Up runs inside a transaction and must be safe on databases that already have the change
+// applied from before migrations were tracked (use AddColumnIfMissing and IF NOT EXISTS).
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(tx *sql.Tx) error
+}
+
+// RunMigrations applies the component's pending migrations in version order and records each
+// in schema_migrations. Returns the versions applied by this call.
+func RunMigrations(db *sql.DB, component string, migrations []Migration) ([]int, error) {
+	const schema = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		component TEXT NOT NULL,
+		version INTEGER NOT NULL,
+		description TEXT,
+		applied_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (component, version)
+	);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version <= migrations[i-1].Version {
+			return nil, fmt.Errorf("%s migrations out of order: version %d follows %d", component, migrations[i].Version, migrations[i-1].Version)
+		}
+	}
+
+	current, err := SchemaVersion(db, component)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := []int{}
+	for _, migration := range migrations {
+		if migration.Version <= current {
+			continue
+		}
+		if err := applyMigration(db, component, migration); err != nil {
+			return applied, err
+		}
+		log.Printf("[MIGRATION] Applied %s v%d: %s", component, migration.Version, migration.Description)
+		applied = append(applied, migration.Version)
+	}
+	return applied, nil
+}
+
+// applyMigration runs a single migration and records it in the same transaction
+func applyMigration(db *sql.DB, component string, migration Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %s v%d: %w", component, migration.Version, err)
+	}
+	defer tx.Rollback()
+
+	if err := migration.Up(tx); err != nil {
+		return fmt.Errorf("migration %s v%d (%s) failed: %w", component, migration.Version, migration.Description, err)
+	}
+	if _, err := tx.Exec(
+		"INSERT INTO schema_migrations (component, version, description, applied_at) VALUES (?, ?, ?, ?)",
+		component, migration.Version, migration.Description, time.Now(),
+	); err != nil {
+		return fmt.Errorf("failed to record migration %s v%d: %w", component, migration.Version, err)
+	}
+	return tx.Commit()
+}
+
+// SchemaVersion returns the highest applied migration version for the component (0 if none)
+func SchemaVersion(db *sql.DB, component string) (int, error) {
+	var version sql.NullInt64
+	if err := db.QueryRow("SELECT MAX(version) FROM schema_migrations WHERE component = ?", component).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version for %s: %w", component, err)
+	}
+	return int(version.Int64), nil
+}
+
+// AddColumnIfMissing adds a column to a table unless it already exists
+func AddColumnIfMissing(tx *sql.Tx, table, column, definition string) error {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to query table info for %s: %w", table, err)
+	}
+	exists := false
+	for rows.Next() {
+		var cid int
+		var name, dataType string
+		var notNull, pk int
+		var defaultValue interface{}
+		if err := rows.Scan(&cid, &name, &dataType, &notNull, &defaultValue, &pk); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan table info for %s: %w", table, err)
+		}
+		if name == column {
+			exists = true
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add %s to %s: %w", column, table, err)
+	}
+	return nil
+}
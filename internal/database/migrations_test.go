@@ -0,0 +1,124 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func hasColumn(t *testing.T, db *sql.DB, table, column string) bool {
+	t.Helper()
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&n); err != nil {
+		t.Fatalf("Failed to read table info: %v", err)
+	}
+	return n == 1
+}
+
+var testMigrations = []Migration{
+	{
+		Version:     1,
+		Description: "create widgets",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec("CREATE TABLE IF NOT EXISTS widgets (id INTEGER PRIMARY KEY, name TEXT)")
+			return err
+		},
+	},
+	{
+		Version:     2,
+		Description: "add organization_id to widgets",
+		Up: func(tx *sql.Tx) error {
+			return AddColumnIfMissing(tx, "widgets", "organization_id", "TEXT")
+		},
+	},
+}
+
+func TestRunMigrations_FreshAndAlreadyMigrated(t *testing.T) {
+	db := openTestDB(t)
+
+	applied, err := RunMigrations(db, "widgets", testMigrations)
+	if err != nil {
+		t.Fatalf("RunMigrations failed on a fresh database: %v", err)
+	}
+	if len(applied) != 2 || applied[0] != 1 || applied[1] != 2 {
+		t.Fatalf("Expected versions [1 2] applied, got %v", applied)
+	}
+	if !hasColumn(t, db, "widgets", "organization_id") {
+		t.Fatal("Expected organization_id column after migrating")
+	}
+
+	// Re-running is a no-op
+	applied, err = RunMigrations(db, "widgets", testMigrations)
+	if err != nil || len(applied) != 0 {
+		t.Fatalf("Expected no migrations on an already-migrated database, got %v, %v", applied, err)
+	}
+	if version, _ := SchemaVersion(db, "widgets"); version != 2 {
+		t.Errorf("Expected schema version 2, got %d", version)
+	}
+}
+
+func TestRunMigrations_LegacySchemaWithoutHistory(t *testing.T) {
+	db := openTestDB(t)
+
+	// A database migrated by the old ad-hoc checks: column present but nothing recorded
+	if _, err := db.Exec("CREATE TABLE widgets (id INTEGER PRIMARY KEY, name TEXT, organization_id TEXT)"); err != nil {
+		t.Fatalf("Failed to create legacy table: %v", err)
+	}
+
+	applied, err := RunMigrations(db, "widgets", testMigrations)
+	if err != nil {
+		t.Fatalf("RunMigrations failed on a legacy database: %v", err)
+	}
+	if len(applied) != 2 {
+		t.Errorf("Expected both migrations recorded, got %v", applied)
+	}
+}
+
+func TestRunMigrations_FailureRollsBack(t *testing.T) {
+	db := openTestDB(t)
+
+	failing := append(append([]Migration{}, testMigrations...), Migration{
+		Version:     3,
+		Description: "broken",
+		Up: func(tx *sql.Tx) error {
+			if _, err := tx.Exec("CREATE TABLE gadgets (id INTEGER)"); err != nil {
+				return err
+			}
+			return errors.New("boom")
+		},
+	})
+
+	applied, err := RunMigrations(db, "widgets", failing)
+	if err == nil {
+		t.Fatal("Expected the failing migration to return an error")
+	}
+	if len(applied) != 2 {
+		t.Errorf("Expected the earlier migrations to stay applied, got %v", applied)
+	}
+	if version, _ := SchemaVersion(db, "widgets"); version != 2 {
+		t.Errorf("Expected schema version 2 after the failure, got %d", version)
+	}
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'gadgets'").Scan(&n); err != nil || n != 0 {
+		t.Errorf("Expected the failed migration's changes to be rolled back")
+	}
+
+	if _, err := RunMigrations(db, "widgets", []Migration{testMigrations[1], testMigrations[0]}); err == nil {
+		t.Error("Expected out-of-order migrations to be rejected")
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// NotificationRouting maps each notification severity to the channels it is delivered on
+type NotificationRouting struct {
+	OrganizationID  string              `json:"organization_id"`
+	Routes          map[string][]string `json:"routes"`            // Severity (info, warning, critical) -> channels (feed, websocket, email, slack)
+	EmailRecipients []string            `json:"email_recipients"`  // Addresses the email channel delivers to
+	SlackWebhookURL string              `json:"slack_webhook_url"` // Incoming webhook the slack channel posts to
+	UpdatedAt       time.Time           `json:"updated_at"`
+}
+
+// NotificationRoutingStore manages per-organization notification routing
+type NotificationRoutingStore struct {
+	db *sql.DB
+}
+
+// NewNotificationRoutingStore creates a new notification routing store
+func NewNotificationRoutingStore(db *sql.DB) (*NotificationRoutingStore, error) {
+	store := &NotificationRoutingStore{db: db}
+	if err := store.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize notification routing schema: %w", err)
+	}
+	return store, nil
+}
+
+// initSchema creates the notification_routing table if it doesn't exist
+func (s *NotificationRoutingStore) initSchema() error {
+	const schema = `
+	CREATE TABLE IF NOT EXISTS notification_routing (
+		organization_id TEXT PRIMARY KEY,
+		routes TEXT NOT NULL DEFAULT '{}',
+		email_recipients TEXT NOT NULL DEFAULT '[]',
+		slack_webhook_url TEXT NOT NULL DEFAULT '',
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	`
+	_, err := s.db.Exec(schema)
+	return err
+}
+
+// GetRouting returns the organization's notification routing, or nil if none is set
+func (s *NotificationRoutingStore) GetRouting(organizationID string) (*NotificationRouting, error) {
+	var routing NotificationRouting
+	var routes, recipients string
+	err := s.db.QueryRow(
+		"SELECT organization_id, routes, email_recipients, slack_webhook_url, updated_at FROM notification_routing WHERE organization_id = ?",
+		organizationID,
+	).Scan(&routing.OrganizationID, &routes, &recipients, &routing.SlackWebhookURL, &routing.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification routing: %w", err)
+	}
+	if err := json.Unmarshal([]byte(routes), &routing.Routes); err != nil {
+		return nil, fmt.Errorf("failed to decode notification routes: %w", err)
+	}
+	if err := json.Unmarshal([]byte(recipients), &routing.EmailRecipients); err != nil {
+		return nil, fmt.Errorf("failed to decode email recipients: %w", err)
+	}
+	return &routing, nil
+}
+
+// SetRouting creates or replaces the organization's notification routing
+func (s *NotificationRoutingStore) SetRouting(routing NotificationRouting) error {
+	if routing.Routes == nil {
+		routing.Routes = map[string][]string{}
+	}
+	if routing.EmailRecipients == nil {
+		routing.EmailRecipients = []string{}
+	}
+	routes, err := json.Marshal(routing.Routes)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification routes: %w", err)
+	}
+	recipients, err := json.Marshal(routing.EmailRecipients)
+	if err != nil {
+		return fmt.Errorf("failed to encode email recipients: %w", err)
+	}
+	_, err = s.db.Exec(
+		"INSERT INTO notification_routing (organization_id, routes, email_recipients, slack_webhook_url, updated_at) VALUES (?, ?, ?, ?, ?) ON CONFLICT(organization_id) DO UPDATE SET routes = excluded.routes, email_recipients = excluded.email_recipients, slack_webhook_url = excluded.slack_webhook_url, updated_at = excluded.updated_at",
+		routing.OrganizationID,
+		string(routes),
+		string(recipients),
+		routing.SlackWebhookURL,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set notification routing: %w", err)
+	}
+	return nil
+}
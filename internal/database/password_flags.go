@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// PasswordFlagStore tracks users who must change their password at next login (e.g. imported with a temporary password)
+type PasswordFlagStore struct {
+	db *sql.DB
+}
+
+// NewPasswordFlagStore creates a new password flag store
+func NewPasswordFlagStore(db *sql.DB) (*PasswordFlagStore, error) {
+	store := &PasswordFlagStore{db: db}
+	if err := store.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize password flags schema: %w", err)
+	}
+	return store, nil
+}
+
+// initSchema creates the password_change_required table if it doesn't exist
+func (s *PasswordFlagStore) initSchema() error {
+	const schema = `
+	CREATE TABLE IF NOT EXISTS password_change_required (
+		user_id TEXT PRIMARY KEY,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	`
+	_, err := s.db.Exec(schema)
+	return err
+}
+
+// RequirePasswordChange marks the user as needing a password change
+func (s *PasswordFlagStore) RequirePasswordChange(userID string) error {
+	_, err := s.db.Exec(
+		"INSERT OR IGNORE INTO password_change_required (user_id, created_at) VALUES (?, ?)",
+		userID,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set password change flag: %w", err)
+	}
+	return nil
+}
+
+// MustChangePassword reports whether the user still has to change their password
+func (s *PasswordFlagStore) MustChangePassword(userID string) (bool, error) {
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM password_change_required WHERE user_id = ?", userID).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to get password change flag: %w", err)
+	}
+	return count > 0, nil
+}
+
+// ClearPasswordChange removes the flag once the user has set their own password
+func (s *PasswordFlagStore) ClearPasswordChange(userID string) error {
+	if _, err := s.db.Exec("DELETE FROM password_change_required WHERE user_id = ?", userID); err != nil {
+		return fmt.Errorf("failed to clear password change flag: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// PIIPolicyStore manages per-organization PII handling modes (off, alert, mask)
+type PIIPolicyStore struct {
+	db *sql.DB
+}
+
+// NewPIIPolicyStore creates a new PII policy store
+func NewPIIPolicyStore(db *sql.DB) (*PIIPolicyStore, error) {
+	store := &PIIPolicyStore{db: db}
+	if err := store.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize PII policies schema: %w", err)
+	}
+	return store, nil
+}
+
+// initSchema creates the pii_policies table if it doesn't exist
+func (s *PIIPolicyStore) initSchema() error {
+	const schema = `
+	CREATE TABLE IF NOT EXISTS pii_policies (
+		organization_id TEXT PRIMARY KEY,
+		mode TEXT NOT NULL,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	`
+	_, err := s.db.Exec(schema)
+	return err
+}
+
+// GetPIIMode returns the organization's PII mode, or an empty string if no policy is set
+func (s *PIIPolicyStore) GetPIIMode(organizationID string) (string, error) {
+	var mode string
+	err := s.db.QueryRow("SELECT mode FROM pii_policies WHERE organization_id = ?", organizationID).Scan(&mode)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get PII policy: %w", err)
+	}
+	return mode, nil
+}
+
+// SetPIIMode sets the organization's PII mode
+func (s *PIIPolicyStore) SetPIIMode(organizationID, mode string) error {
+	_, err := s.db.Exec(
+		"INSERT INTO pii_policies (organization_id, mode, updated_at) VALUES (?, ?, ?) ON CONFLICT(organization_id) DO UPDATE SET mode = excluded.mode, updated_at = excluded.updated_at",
+		organizationID,
+		mode,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set PII policy: %w", err)
+	}
+	return nil
+}
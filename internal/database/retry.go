@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Write retry policy for SQLITE_BUSY/SQLITE_LOCKED; the busy timeout covers most contention,
+// this catches what slips through (e.g. lock upgrades and checkpoints)
+const (
+	writeRetryAttempts  = 5
+	writeRetryBaseDelay = 50 * time.Millisecond
+	writeRetryMaxDelay  = time.Second
+)
+
+// IsBusyError reports whether err is SQLite reporting a busy or locked database
+func IsBusyError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "database is locked") || strings.Contains(msg, "database table is locked") || strings.Contains(msg, "sqlite_busy")
+}
+
+// RetryOnBusy runs a write, retrying with exponential backoff while SQLite reports the
+// database as busy or locked. Other errors and context cancellation return immediately.
+func RetryOnBusy(ctx context.Context, op func() error) error {
+	delay := writeRetryBaseDelay
+	var err error
+	for attempt := 1; attempt <= writeRetryAttempts; attempt++ {
+		if err = op(); err == nil || !IsBusyError(err) {
+			return err
+		}
+		if attempt == writeRetryAttempts {
+			break
+		}
+
+		log.Printf("[DB] Database busy (attempt %d/%d), retrying in %v: %v", attempt, writeRetryAttempts, delay, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > writeRetryMaxDelay {
+			delay = writeRetryMaxDelay
+		}
+	}
+	return err
+}
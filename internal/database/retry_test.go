@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRetryOnBusy_SucceedsAfterLockReleased(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "locked.db")
+
+	// No busy timeout, so a held lock surfaces immediately as "database is locked"
+	writer, err := sql.Open("sqlite3", path+"?_busy_timeout=0&_journal_mode=WAL")
+	if err != nil {
+		t.Fatalf("Failed to open writer: %v", err)
+	}
+	defer writer.Close()
+	if _, err := writer.Exec("CREATE TABLE notes (body TEXT)"); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	holder, err := sql.Open("sqlite3", path+"?_busy_timeout=0&_txlock=immediate")
+	if err != nil {
+		t.Fatalf("Failed to open lock holder: %v", err)
+	}
+	defer holder.Close()
+	tx, err := holder.Begin()
+	if err != nil {
+		t.Fatalf("Failed to take the write lock: %v", err)
+	}
+	go func() {
+		time.Sleep(120 * time.Millisecond)
+		tx.Commit()
+	}()
+
+	attempts := 0
+	err = RetryOnBusy(context.Background(), func() error {
+		attempts++
+		_, err := writer.Exec("INSERT INTO notes (body) VALUES ('hello')")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Expected the write to succeed once the lock was released, got %v", err)
+	}
+	if attempts < 2 {
+		t.Errorf("Expected at least one retry, got %d attempts", attempts)
+	}
+}
+
+func TestRetryOnBusy_DoesNotRetryOtherErrors(t *testing.T) {
+	attempts := 0
+	want := errors.New("constraint failed")
+	err := RetryOnBusy(context.Background(), func() error {
+		attempts++
+		return want
+	})
+	if !errors.Is(err, want) || attempts != 1 {
+		t.Errorf("Expected a single attempt returning the original error, got %d attempts, %v", attempts, err)
+	}
+}
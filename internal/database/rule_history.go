@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// RuleMatch is a rule that matched a document, as stored by RuleMatchStore.AddMatch
+type RuleMatch struct {
+	ID             int64     `json:"id"`
+	RuleID         int64     `json:"rule_id"`
+	RuleQuery      string    `json:"rule_query"`
+	UploadedDoc    string    `json:"uploaded_doc"`
+	MatchedDoc     string    `json:"matched_doc,omitempty"` // The other document of a cross-document match
+	MatchType      string    `json:"match_type"`            // single_doc, keyword or cross_doc
+	AIExplanation  string    `json:"ai_explanation"`
+	MatchedChunks  []string  `json:"matched_chunks"`
+	ClientID       string    `json:"client_id,omitempty"`
+	OrganizationID string    `json:"organization_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// RuleEvent is a step of rule processing for a document, as stored by RuleEventStore.AddEvent
+type RuleEvent struct {
+	ID             int64     `json:"id"`
+	RuleID         int64     `json:"rule_id"`
+	RuleQuery      string    `json:"rule_query"`
+	Document       string    `json:"document"`
+	EventType      string    `json:"event_type"`
+	Status         string    `json:"status"`
+	Message        string    `json:"message"`
+	ClientID       string    `json:"client_id,omitempty"`
+	OrganizationID string    `json:"organization_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// GetMatches returns the organization's latest rule matches, newest first
+func (s *RuleMatchStore) GetMatches(orgID string, limit int) ([]RuleMatch, error) {
+	rows, err := s.db.Query(
+		`SELECT id, rule_id, COALESCE(rule_query, ''), COALESCE(uploaded_doc, ''), COALESCE(matched_doc, ''), COALESCE(match_type, ''),
+			COALESCE(ai_explanation, ''), matched_chunks, COALESCE(client_id, ''), COALESCE(organization_id, ''), created_at
+		FROM rule_matches WHERE organization_id = ? ORDER BY created_at DESC, id DESC LIMIT ?`,
+		orgID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rule matches: %w", err)
+	}
+	defer rows.Close()
+
+	matches := []RuleMatch{}
+	for rows.Next() {
+		var match RuleMatch
+		var chunks sql.NullString
+		if err := rows.Scan(&match.ID, &match.RuleID, &match.RuleQuery, &match.UploadedDoc, &match.MatchedDoc, &match.MatchType,
+			&match.AIExplanation, &chunks, &match.ClientID, &match.OrganizationID, &match.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan rule match: %w", err)
+		}
+		match.MatchedChunks = decodeMatchedChunks(chunks.String)
+		matches = append(matches, match)
+	}
+	return matches, rows.Err()
+}
+
+// CountMatches returns how many rule matches the organization has
+func (s *RuleMatchStore) CountMatches(orgID string) (int, error) {
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM rule_matches WHERE organization_id = ?", orgID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count rule matches: %w", err)
+	}
+	return count, nil
+}
+
+// GetEvents returns the organization's latest rule events, newest first
+func (s *RuleEventStore) GetEvents(orgID string, limit int) ([]RuleEvent, error) {
+	rows, err := s.db.Query(
+		`SELECT id, COALESCE(rule_id, 0), COALESCE(rule_query, ''), COALESCE(document, ''), event_type, COALESCE(status, ''),
+			COALESCE(message, ''), COALESCE(client_id, ''), COALESCE(organization_id, ''), created_at
+		FROM rule_events WHERE organization_id = ? ORDER BY created_at DESC, id DESC LIMIT ?`,
+		orgID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rule events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []RuleEvent{}
+	for rows.Next() {
+		var event RuleEvent
+		if err := rows.Scan(&event.ID, &event.RuleID, &event.RuleQuery, &event.Document, &event.EventType, &event.Status,
+			&event.Message, &event.ClientID, &event.OrganizationID, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan rule event: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// CountEvents returns how many rule events the organization has
+func (s *RuleEventStore) CountEvents(orgID string) (int, error) {
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM rule_events WHERE organization_id = ?", orgID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count rule events: %w", err)
+	}
+	return count, nil
+}
+
+// decodeMatchedChunks reads the matched chunks column, a JSON array; older rows may hold a single plain chunk
+func decodeMatchedChunks(raw string) []string {
+	chunks := []string{}
+	if raw == "" {
+		return chunks
+	}
+	if err := json.Unmarshal([]byte(raw), &chunks); err != nil {
+		return []string{raw}
+	}
+	return chunks
+}
@@ -0,0 +1,243 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SavedSearch represents a named query stored by an analyst
+type SavedSearch struct {
+	ID             int64             `json:"id"`
+	Name           string            `json:"name"`
+	Query          string            `json:"query"`
+	TopK           int               `json:"top_k"`
+	Filters        map[string]string `json:"filters,omitempty"` // Exact-match metadata filters applied to results
+	OrganizationID string            `json:"organization_id"`
+	OwnerID        string            `json:"owner_id"`
+	Shared         bool              `json:"shared"` // Visible to everyone in the organization, not just the owner
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// SavedSearchStore manages saved searches
+type SavedSearchStore struct {
+	db *sql.DB
+}
+
+// NewSavedSearchStore creates a new saved search store
+func NewSavedSearchStore(db *sql.DB) (*SavedSearchStore, error) {
+	store := &SavedSearchStore{db: db}
+	if err := store.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize saved searches schema: %w", err)
+	}
+	return store, nil
+}
+
+// initSchema creates the saved_searches table if it doesn't exist
+func (s *SavedSearchStore) initSchema() error {
+	const schema = `
+	CREATE TABLE IF NOT EXISTS saved_searches (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		query TEXT NOT NULL,
+		top_k INTEGER NOT NULL DEFAULT 3,
+		filters TEXT,
+		organization_id TEXT NOT NULL,
+		owner_id TEXT NOT NULL,
+		shared BOOLEAN NOT NULL DEFAULT FALSE,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_saved_searches_org ON saved_searches(organization_id);
+	CREATE INDEX IF NOT EXISTS idx_saved_searches_owner ON saved_searches(owner_id);
+	`
+	_, err := s.db.Exec(schema)
+	return err
+}
+
+// CreateSavedSearch stores a new saved search and returns it with its assigned ID
+func (s *SavedSearchStore) CreateSavedSearch(search *SavedSearch) (*SavedSearch, error) {
+	filtersJSON, err := encodeFilters(search.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result, err := s.db.Exec(
+		"INSERT INTO saved_searches (name, query, top_k, filters, organization_id, owner_id, shared, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		search.Name,
+		search.Query,
+		search.TopK,
+		filtersJSON,
+		search.OrganizationID,
+		search.OwnerID,
+		search.Shared,
+		now,
+		now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create saved search: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved search ID: %w", err)
+	}
+
+	created := *search
+	created.ID = id
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	return &created, nil
+}
+
+// GetSavedSearch returns a saved search visible to the given user within the organization
+// Returns nil if the search does not exist or is private to another user
+func (s *SavedSearchStore) GetSavedSearch(id int64, organizationID, userID string) (*SavedSearch, error) {
+	row := s.db.QueryRow(
+		"SELECT id, name, query, top_k, filters, organization_id, owner_id, shared, created_at, updated_at FROM saved_searches WHERE id = ? AND organization_id = ? AND (owner_id = ? OR shared = TRUE)",
+		id,
+		organizationID,
+		userID,
+	)
+
+	search, err := scanSavedSearch(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved search: %w", err)
+	}
+	return search, nil
+}
+
+// ListSavedSearches returns the user's own searches plus searches shared within the organization
+func (s *SavedSearchStore) ListSavedSearches(organizationID, userID string) ([]SavedSearch, error) {
+	rows, err := s.db.Query(
+		"SELECT id, name, query, top_k, filters, organization_id, owner_id, shared, created_at, updated_at FROM saved_searches WHERE organization_id = ? AND (owner_id = ? OR shared = TRUE) ORDER BY name ASC",
+		organizationID,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+	defer rows.Close()
+
+	searches := []SavedSearch{}
+	for rows.Next() {
+		search, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved search: %w", err)
+		}
+		searches = append(searches, *search)
+	}
+
+	return searches, rows.Err()
+}
+
+// UpdateSavedSearch updates a saved search owned by the given user
+// Returns sql.ErrNoRows if the search does not exist or is not owned by the user
+func (s *SavedSearchStore) UpdateSavedSearch(search *SavedSearch) error {
+	filtersJSON, err := encodeFilters(search.Filters)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.Exec(
+		"UPDATE saved_searches SET name = ?, query = ?, top_k = ?, filters = ?, shared = ?, updated_at = ? WHERE id = ? AND organization_id = ? AND owner_id = ?",
+		search.Name,
+		search.Query,
+		search.TopK,
+		filtersJSON,
+		search.Shared,
+		time.Now(),
+		search.ID,
+		search.OrganizationID,
+		search.OwnerID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update saved search: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify update: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteSavedSearch deletes a saved search owned by the given user
+// Returns sql.ErrNoRows if the search does not exist or is not owned by the user
+func (s *SavedSearchStore) DeleteSavedSearch(id int64, organizationID, userID string) error {
+	result, err := s.db.Exec(
+		"DELETE FROM saved_searches WHERE id = ? AND organization_id = ? AND owner_id = ?",
+		id,
+		organizationID,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify delete: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSavedSearch scans a saved search from a row and decodes its filters
+func scanSavedSearch(row rowScanner) (*SavedSearch, error) {
+	var search SavedSearch
+	var filtersJSON sql.NullString
+	if err := row.Scan(
+		&search.ID,
+		&search.Name,
+		&search.Query,
+		&search.TopK,
+		&filtersJSON,
+		&search.OrganizationID,
+		&search.OwnerID,
+		&search.Shared,
+		&search.CreatedAt,
+		&search.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if filtersJSON.Valid && filtersJSON.String != "" {
+		if err := json.Unmarshal([]byte(filtersJSON.String), &search.Filters); err != nil {
+			return nil, fmt.Errorf("failed to decode filters: %w", err)
+		}
+	}
+
+	return &search, nil
+}
+
+// encodeFilters serializes filters for storage (empty filters are stored as an empty string)
+func encodeFilters(filters map[string]string) (string, error) {
+	if len(filters) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(filters)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode filters: %w", err)
+	}
+	return string(data), nil
+}
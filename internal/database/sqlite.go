@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	// DefaultBusyTimeout is how long a connection waits for a lock before returning "database is locked"
+	DefaultBusyTimeout = 10 * time.Second
+	// DefaultMaxOpenConns bounds the shared pool; WAL allows concurrent readers but only one writer
+	DefaultMaxOpenConns = 8
+	// DefaultConnMaxLifetime recycles connections occasionally without discarding the page cache too often
+	DefaultConnMaxLifetime = 30 * time.Minute
+)
+
+// SQLiteOptions tunes the shared SQLite handle
+type SQLiteOptions struct {
+	BusyTimeout     time.Duration
+	MaxOpenConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// DefaultSQLiteOptions returns the settings used by the server
+func DefaultSQLiteOptions() SQLiteOptions {
+	return SQLiteOptions{
+		BusyTimeout:     DefaultBusyTimeout,
+		MaxOpenConns:    DefaultMaxOpenConns,
+		ConnMaxLifetime: DefaultConnMaxLifetime,
+	}
+}
+
+// SQLiteDSN builds a DSN that applies the pragmas to every pooled connection.
+// Pragmas run with db.Exec only reach whichever connection served that call, so they
+// belong in the DSN. Transactions start with BEGIN IMMEDIATE so writers queue on the
+// busy timeout instead of failing when a read lock can't be upgraded.
+func SQLiteDSN(path string, opts SQLiteOptions) string {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%s_journal_mode=WAL&_busy_timeout=%d&_synchronous=NORMAL&_txlock=immediate",
+		path, sep, opts.BusyTimeout.Milliseconds())
+}
+
+// OpenSQLite opens the database with WAL, a busy timeout on every connection and pool limits
+// suited to SQLite. All stores share the returned handle.
+func OpenSQLite(path string, opts SQLiteOptions) (*sql.DB, error) {
+	if opts.BusyTimeout <= 0 {
+		opts.BusyTimeout = DefaultBusyTimeout
+	}
+	if opts.MaxOpenConns <= 0 {
+		opts.MaxOpenConns = DefaultMaxOpenConns
+	}
+
+	db, err := sql.Open("sqlite3", SQLiteDSN(path, opts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	db.SetMaxOpenConns(opts.MaxOpenConns)
+	db.SetMaxIdleConns(opts.MaxOpenConns)
+	db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+
+	var journalMode string
+	if err := db.QueryRow("PRAGMA journal_mode").Scan(&journalMode); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to read journal mode: %w", err)
+	}
+	if !strings.EqualFold(journalMode, "wal") && path != ":memory:" {
+		db.Close()
+		return nil, fmt.Errorf("failed to enable WAL mode (journal_mode=%s)", journalMode)
+	}
+	return db, nil
+}
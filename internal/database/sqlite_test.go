@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOpenSQLite_ConcurrentWriters(t *testing.T) {
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "hive.db"), DefaultSQLiteOptions())
+	if err != nil {
+		t.Fatalf("OpenSQLite failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("CREATE TABLE counters (name TEXT PRIMARY KEY, value INTEGER NOT NULL)"); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO counters (name, value) VALUES ('total', 0)"); err != nil {
+		t.Fatalf("Failed to seed counter: %v", err)
+	}
+
+	const writers, iterations = 8, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*iterations)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				// Read-then-write transactions deadlock on lock upgrade unless they begin IMMEDIATE
+				tx, err := db.Begin()
+				if err != nil {
+					errs <- err
+					return
+				}
+				var value int
+				if err := tx.QueryRow("SELECT value FROM counters WHERE name = 'total'").Scan(&value); err != nil {
+					tx.Rollback()
+					errs <- err
+					return
+				}
+				time.Sleep(time.Millisecond) // Let other writers take their snapshots
+				if _, err := tx.Exec("UPDATE counters SET value = ? WHERE name = 'total'", value+1); err != nil {
+					tx.Rollback()
+					errs <- err
+					return
+				}
+				if _, err := tx.Exec("INSERT INTO counters (name, value) VALUES (?, ?)", fmt.Sprintf("w%d-%d", w, i), i); err != nil {
+					tx.Rollback()
+					errs <- err
+					return
+				}
+				if err := tx.Commit(); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Concurrent write failed: %v", err)
+	}
+
+	var total int
+	if err := db.QueryRow("SELECT value FROM counters WHERE name = 'total'").Scan(&total); err != nil {
+		t.Fatalf("Failed to read counter: %v", err)
+	}
+	if total != writers*iterations {
+		t.Errorf("Expected counter %d, got %d (lost updates)", writers*iterations, total)
+	}
+}
+
+func TestSQLiteDSN_AppendsToExistingQuery(t *testing.T) {
+	dsn := SQLiteDSN("hive.db?cache=shared", DefaultSQLiteOptions())
+	if dsn != "hive.db?cache=shared&_journal_mode=WAL&_busy_timeout=10000&_synchronous=NORMAL&_txlock=immediate" {
+		t.Errorf("Unexpected DSN: %s", dsn)
+	}
+}
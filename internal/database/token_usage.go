@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// tokenUsageSchema holds the AI tokens used per request. The table is created on first use since
+// it was added after UsageStore's own tables.
+const tokenUsageSchema = `
+CREATE TABLE IF NOT EXISTS token_usage (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	organization_id TEXT NOT NULL,
+	user_id TEXT,
+	prompt_tokens INTEGER NOT NULL DEFAULT 0,
+	completion_tokens INTEGER NOT NULL DEFAULT 0,
+	model TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_token_usage_org_created ON token_usage(organization_id, created_at);
+`
+
+// BillingPeriodStart returns the start of the billing period containing t (the first of its month, UTC)
+func BillingPeriodStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// RecordUsage stores the tokens one AI request used for the organization (userID is empty for API keys)
+func (s *UsageStore) RecordUsage(orgID, userID string, promptTokens, completionTokens int, model string) error {
+	if _, err := s.db.Exec(tokenUsageSchema); err != nil {
+		return fmt.Errorf("failed to initialize token usage schema: %w", err)
+	}
+	err := RetryOnBusy(context.Background(), func() error {
+		_, err := s.db.Exec(
+			`INSERT INTO token_usage (organization_id, user_id, prompt_tokens, completion_tokens, model, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			orgID, sql.NullString{String: userID, Valid: userID != ""}, promptTokens, completionTokens, model, time.Now().UTC(),
+		)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record token usage: %w", err)
+	}
+	return nil
+}
+
+// TokenTotalsSince returns the tokens (prompt and completion) each organization has used since the given time
+func (s *UsageStore) TokenTotalsSince(since time.Time) (map[string]int64, error) {
+	if _, err := s.db.Exec(tokenUsageSchema); err != nil {
+		return nil, fmt.Errorf("failed to initialize token usage schema: %w", err)
+	}
+	rows, err := s.db.Query(
+		`SELECT organization_id, COALESCE(SUM(prompt_tokens + completion_tokens), 0) FROM token_usage WHERE created_at >= ? GROUP BY organization_id`,
+		since.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query token usage: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make(map[string]int64)
+	for rows.Next() {
+		var orgID string
+		var total int64
+		if err := rows.Scan(&orgID, &total); err != nil {
+			return nil, fmt.Errorf("failed to scan token usage: %w", err)
+		}
+		totals[orgID] = total
+	}
+	return totals, rows.Err()
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ImportedUser is a user to create with a temporary password
+type ImportedUser struct {
+	Email    string
+	Password string
+	Role     UserRole
+}
+
+// ImportUsers creates the users in organizationID in one transaction, each flagged in
+// password_change_required to replace its temporary password at next login. Either every
+// user is created or none is.
+func (s *UserStore) ImportUsers(ctx context.Context, users []ImportedUser, organizationID string) ([]*User, error) {
+	created := make([]*User, 0, len(users))
+	hashes := make([]string, 0, len(users))
+	now := time.Now()
+	for _, u := range users {
+		hash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password for %s: %w", u.Email, err)
+		}
+		hashes = append(hashes, string(hash))
+		created = append(created, &User{
+			ID:             uuid.New().String(),
+			Email:          strings.ToLower(strings.TrimSpace(u.Email)),
+			Role:           u.Role,
+			OrganizationID: organizationID,
+			CreatedAt:      now,
+		})
+	}
+
+	err := RetryOnBusy(ctx, func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		for i, user := range created {
+			if _, err := tx.ExecContext(ctx,
+				"INSERT INTO users (id, email, password_hash, role, organization_id, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+				user.ID, user.Email, hashes[i], string(user.Role), user.OrganizationID, user.CreatedAt,
+			); err != nil {
+				return fmt.Errorf("%s: %w", user.Email, err)
+			}
+			if _, err := tx.ExecContext(ctx,
+				"INSERT OR IGNORE INTO password_change_required (user_id, created_at) VALUES (?, ?)",
+				user.ID, now,
+			); err != nil {
+				return fmt.Errorf("%s: %w", user.Email, err)
+			}
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to import users: %w", err)
+	}
+	return created, nil
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package docid
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/google/uuid"
+)
+
+// New returns the deterministic document_id for a file ingested by a client.
+// It is derived from the client and the full path, so same-named files in different folders
+// (or on different machines) stay separate documents, while re-ingesting a file keeps its ID.
+// The human-readable name belongs in the "filename" metadata, not the ID.
+func New(clientID, filePath string) string {
+	if filePath != "" {
+		filePath = filepath.ToSlash(filepath.Clean(filePath))
+	}
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte("document:"+clientID+":"+filePath)).String()
+}
+
+// PointID returns the deterministic vector point ID for a document's chunk. It is derived from
+// the document ID, so re-ingesting a file updates its points while another client's or
+// organization's file at the same path keeps its own.
+func PointID(documentID string, chunkIndex int) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(fmt.Sprintf("%s-%d", documentID, chunkIndex))).String()
+}
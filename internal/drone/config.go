@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/spf13/viper"
@@ -21,6 +22,26 @@ type Config struct {
 	DisabledPaths     []string        `mapstructure:"disabled_paths"` // Paths that are configured but not actively watched
 	WebServer         WebServerConfig `mapstructure:"web_server"`
 	APIKey            string          `mapstructure:"api_key"`
+	DetectMoves       bool            `mapstructure:"detect_moves"`     // Ingest moved/renamed files as updates to the original document
+	MaxWatchedDirs    int             `mapstructure:"max_watched_dirs"` // Cap on directories watched across all paths (each uses an OS watch)
+	MaxFileSize       int64           `mapstructure:"max_file_size"`    // Largest file ingested, in bytes; larger files are skipped as too large
+	PollPaths         []string        `mapstructure:"poll_paths"`       // Watch paths rescanned on an interval instead of watched for events (SMB/NFS shares)
+	PollInterval      time.Duration   `mapstructure:"poll_interval"`    // How often poll_paths are rescanned
+	// Most desktop notifications shown per minute; the rest are summarized (0 shows every one)
+	OSNotificationsPerMinute int `mapstructure:"os_notifications_per_minute"`
+	// Events buffered for each UI event stream before it is told it missed some
+	EventBufferSize int `mapstructure:"event_buffer_size"`
+	// How files are split into chunks before they are sent to the Hive server
+	Chunking ChunkingConfig `mapstructure:"chunking"`
+}
+
+// ChunkingConfig holds chunk size settings, in estimated tokens (about 4 characters each)
+type ChunkingConfig struct {
+	MaxTokens       int  `mapstructure:"max_tokens"`        // Largest chunk
+	OverlapTokens   int  `mapstructure:"overlap_tokens"`    // Text repeated between consecutive chunks
+	SplitOnSentence bool `mapstructure:"split_on_sentence"` // End chunks at a sentence break instead of mid-sentence
+	// Chunking strategy by file extension without the dot (prose, markdown, code or rows), overriding the defaults
+	Strategies map[string]string `mapstructure:"strategies"`
 }
 
 // ServerConfig holds Hive server connection settings
@@ -31,6 +52,8 @@ type ServerConfig struct {
 // WebServerConfig holds web server settings
 type WebServerConfig struct {
 	Port int `mapstructure:"port"`
+	// Serve /api/logs and /api/logs/stream to other machines too, not just localhost
+	RemoteLogs bool `mapstructure:"remote_logs"`
 }
 
 // LoadConfig loads configuration from file and environment
@@ -43,6 +66,16 @@ func LoadConfig(configPath string) (*Config, error) {
 	viper.SetDefault("grpc_server_address", "localhost:50051")
 	viper.SetDefault("watch_paths", []string{"./watch"})
 	viper.SetDefault("web_server.port", 9090)
+	viper.SetDefault("web_server.remote_logs", false)
+	viper.SetDefault("detect_moves", true)
+	viper.SetDefault("max_watched_dirs", 8192)
+	viper.SetDefault("max_file_size", 104857600)
+	viper.SetDefault("poll_interval", "30s")
+	viper.SetDefault("os_notifications_per_minute", 5)
+	viper.SetDefault("event_buffer_size", 64)
+	viper.SetDefault("chunking.max_tokens", 250)
+	viper.SetDefault("chunking.overlap_tokens", 50)
+	viper.SetDefault("chunking.split_on_sentence", false)
 	// Note: client_id will be generated if missing, not set as default
 
 	// If config path is provided, use it
@@ -156,6 +189,20 @@ func SaveConfig(config *Config, configPath string) error {
 	viper.Set("watch_paths", config.WatchPaths)
 	viper.Set("disabled_paths", config.DisabledPaths)
 	viper.Set("web_server.port", config.WebServer.Port)
+	viper.Set("web_server.remote_logs", config.WebServer.RemoteLogs)
+	viper.Set("detect_moves", config.DetectMoves)
+	viper.Set("max_watched_dirs", config.MaxWatchedDirs)
+	viper.Set("max_file_size", config.MaxFileSize)
+	viper.Set("poll_paths", config.PollPaths)
+	viper.Set("poll_interval", config.PollInterval.String())
+	viper.Set("os_notifications_per_minute", config.OSNotificationsPerMinute)
+	viper.Set("event_buffer_size", config.EventBufferSize)
+	viper.Set("chunking.max_tokens", config.Chunking.MaxTokens)
+	viper.Set("chunking.overlap_tokens", config.Chunking.OverlapTokens)
+	viper.Set("chunking.split_on_sentence", config.Chunking.SplitOnSentence)
+	if len(config.Chunking.Strategies) > 0 {
+		viper.Set("chunking.strategies", config.Chunking.Strategies)
+	}
 
 	// Write to file
 	if err := viper.WriteConfigAs(configPath); err != nil {
@@ -180,6 +227,25 @@ api_key: ""  # API key for authentication (get from server settings)
 watch_paths:
   - "./watch"  # Directories to watch for files
 
+detect_moves: true  # Ingest moved/renamed files as updates to the original document
+
+max_watched_dirs: 8192  # Most directories watched across all paths; keep below the OS limit (fs.inotify.max_user_watches on Linux)
+
+max_file_size: 104857600  # Largest file ingested, in bytes (100 MB); larger files are skipped as too large
+
+poll_paths: []  # Watch paths on network shares (SMB/NFS) to rescan on an interval, since change events aren't reliable there
+poll_interval: "30s"  # How often poll_paths are rescanned
+
+os_notifications_per_minute: 5  # Most desktop popups for rule matches per minute; the rest are summarized (0 = no limit)
+
+event_buffer_size: 64  # File events buffered per open UI; a UI that falls further behind is told how many it missed
+
+chunking:  # How files are split before ingestion; sizes are in tokens (about 4 characters each)
+  max_tokens: 250  # Largest chunk
+  overlap_tokens: 50  # Text repeated between consecutive chunks
+  split_on_sentence: false  # End chunks at a sentence or paragraph break instead of mid-sentence
+  strategies: {}  # Chunking strategy by extension, e.g. {md: markdown, txt: prose}; markdown splits by heading, code by top-level block, rows (csv, xlsx) by whole rows
+
 web_server:
   port: 9090  # Web UI port
 `
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -22,6 +24,15 @@ type TrackedFile struct {
 	FileHash     string
 	LastProcessed sql.NullTime
 	ServerStatus string
+	ErrorCode    string // Outcome code of the last failed or partial ingest (e.g. "PARSE_ERROR"); empty on success
+}
+
+// SkippedFile records why a file was not ingested
+type SkippedFile struct {
+	FilePath  string    `json:"path"`
+	Reason    string    `json:"reason"`  // Machine-readable reason code (e.g. "unchanged", "too_large")
+	Message   string    `json:"message"` // Human-readable detail
+	SkippedAt time.Time `json:"skipped_at"`
 }
 
 // NewClientDB creates and initializes a new client database
@@ -76,10 +87,48 @@ func (c *ClientDB) initSchema() error {
 
 	CREATE INDEX IF NOT EXISTS idx_tracked_files_hash ON tracked_files(file_hash);
 	CREATE INDEX IF NOT EXISTS idx_tracked_files_status ON tracked_files(server_status);
+
+	CREATE TABLE IF NOT EXISTS skipped_files (
+		file_path TEXT PRIMARY KEY,
+		reason TEXT NOT NULL,
+		message TEXT,
+		skipped_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_skipped_files_reason ON skipped_files(reason, skipped_at);
 	`
 
-	_, err := c.db.Exec(schema)
-	return err
+	if _, err := c.db.Exec(schema); err != nil {
+		return err
+	}
+	return c.addColumnIfMissing("tracked_files", "error_code", "TEXT")
+}
+
+// addColumnIfMissing adds a column to a table created by an older drone version
+func (c *ClientDB) addColumnIfMissing(table, column, definition string) error {
+	rows, err := c.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to read %s columns: %w", table, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return fmt.Errorf("failed to scan %s column: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if _, err := c.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add %s.%s: %w", table, column, err)
+	}
+	return nil
 }
 
 // GetTrackedFile retrieves a tracked file by path
@@ -88,9 +137,9 @@ func (c *ClientDB) GetTrackedFile(filePath string) (*TrackedFile, error) {
 	var lastProcessed sql.NullTime
 
 	err := c.db.QueryRow(
-		"SELECT file_path, file_hash, last_processed, server_status FROM tracked_files WHERE file_path = ?",
+		"SELECT file_path, file_hash, last_processed, server_status, COALESCE(error_code, '') FROM tracked_files WHERE file_path = ?",
 		filePath,
-	).Scan(&tf.FilePath, &tf.FileHash, &lastProcessed, &tf.ServerStatus)
+	).Scan(&tf.FilePath, &tf.FileHash, &lastProcessed, &tf.ServerStatus, &tf.ErrorCode)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -103,18 +152,65 @@ func (c *ClientDB) GetTrackedFile(filePath string) (*TrackedFile, error) {
 	return &tf, nil
 }
 
-// UpsertTrackedFile inserts or updates a tracked file
-func (c *ClientDB) UpsertTrackedFile(filePath, fileHash, serverStatus string) error {
+// FindTrackedFilesByHash returns the tracked files whose content hash matches
+func (c *ClientDB) FindTrackedFilesByHash(fileHash string) ([]TrackedFile, error) {
+	rows, err := c.db.Query(
+		"SELECT file_path, file_hash, last_processed, server_status, COALESCE(error_code, '') FROM tracked_files WHERE file_hash = ? ORDER BY file_path",
+		fileHash,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tracked files by hash: %w", err)
+	}
+	defer rows.Close()
+
+	var files []TrackedFile
+	for rows.Next() {
+		var tf TrackedFile
+		if err := rows.Scan(&tf.FilePath, &tf.FileHash, &tf.LastProcessed, &tf.ServerStatus, &tf.ErrorCode); err != nil {
+			return nil, fmt.Errorf("failed to scan tracked file: %w", err)
+		}
+		files = append(files, tf)
+	}
+	return files, rows.Err()
+}
+
+// FindTrackedFilesUnder returns the tracked files inside the directory (at any depth)
+func (c *ClientDB) FindTrackedFilesUnder(dir string) ([]TrackedFile, error) {
+	prefix := strings.TrimSuffix(dir, string(filepath.Separator)) + string(filepath.Separator)
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix)
+	rows, err := c.db.Query(
+		`SELECT file_path, file_hash, last_processed, server_status, COALESCE(error_code, '') FROM tracked_files WHERE file_path LIKE ? ESCAPE '\' ORDER BY file_path`,
+		escaped+"%",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tracked files under %s: %w", dir, err)
+	}
+	defer rows.Close()
+
+	var files []TrackedFile
+	for rows.Next() {
+		var tf TrackedFile
+		if err := rows.Scan(&tf.FilePath, &tf.FileHash, &tf.LastProcessed, &tf.ServerStatus, &tf.ErrorCode); err != nil {
+			return nil, fmt.Errorf("failed to scan tracked file: %w", err)
+		}
+		files = append(files, tf)
+	}
+	return files, rows.Err()
+}
+
+// UpsertTrackedFile inserts or updates a tracked file; errorCode is empty when ingestion succeeded
+func (c *ClientDB) UpsertTrackedFile(filePath, fileHash, serverStatus, errorCode string) error {
 	const query = `
-		INSERT INTO tracked_files (file_path, file_hash, server_status, last_processed)
-		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		INSERT INTO tracked_files (file_path, file_hash, server_status, error_code, last_processed)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
 		ON CONFLICT(file_path) DO UPDATE SET
 			file_hash = excluded.file_hash,
 			server_status = excluded.server_status,
+			error_code = excluded.error_code,
 			last_processed = CURRENT_TIMESTAMP
 	`
 
-	_, err := c.db.Exec(query, filePath, fileHash, serverStatus)
+	_, err := c.db.Exec(query, filePath, fileHash, serverStatus, errorCode)
 	if err != nil {
 		return fmt.Errorf("failed to upsert tracked file: %w", err)
 	}
@@ -143,3 +239,64 @@ func (c *ClientDB) DeleteTrackedFile(filePath string) error {
 	return nil
 }
 
+
+// RecordSkip stores the latest skip decision for a file, replacing any earlier one
+func (c *ClientDB) RecordSkip(filePath, reason, message string) error {
+	const query = `
+		INSERT INTO skipped_files (file_path, reason, message, skipped_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(file_path) DO UPDATE SET
+			reason = excluded.reason,
+			message = excluded.message,
+			skipped_at = excluded.skipped_at
+	`
+
+	_, err := c.db.Exec(query, filePath, reason, message, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record skipped file: %w", err)
+	}
+	return nil
+}
+
+// ClearSkip removes the skip record for a file once it has been processed
+func (c *ClientDB) ClearSkip(filePath string) error {
+	_, err := c.db.Exec("DELETE FROM skipped_files WHERE file_path = ?", filePath)
+	if err != nil {
+		return fmt.Errorf("failed to clear skipped file: %w", err)
+	}
+	return nil
+}
+
+// ListSkippedFiles returns the most recent skip decisions, optionally filtered by reason
+func (c *ClientDB) ListSkippedFiles(reason string, limit int) ([]SkippedFile, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := "SELECT file_path, reason, message, skipped_at FROM skipped_files"
+	args := []interface{}{}
+	if reason != "" {
+		query += " WHERE reason = ?"
+		args = append(args, reason)
+	}
+	query += " ORDER BY skipped_at DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query skipped files: %w", err)
+	}
+	defer rows.Close()
+
+	skipped := []SkippedFile{}
+	for rows.Next() {
+		var sf SkippedFile
+		var message sql.NullString
+		if err := rows.Scan(&sf.FilePath, &sf.Reason, &message, &sf.SkippedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan skipped file: %w", err)
+		}
+		sf.Message = message.String
+		skipped = append(skipped, sf)
+	}
+	return skipped, rows.Err()
+}
@@ -3,38 +3,71 @@
 package events
 
 import (
+	"fmt"
 	"sync"
 	"time"
 )
 
 // Event represents a file processing event
 type Event struct {
-	Type      string    `json:"type"`      // "file_detected", "file_processing", "file_complete", "file_error"
+	Type      string    `json:"type"` // "file_detected", "file_processing", "file_complete", "file_error", "events_dropped"
 	Timestamp time.Time `json:"timestamp"`
 	Path      string    `json:"path,omitempty"`
 	Message   string    `json:"message"`
 	Chunks    int       `json:"chunks,omitempty"`
 	Error     string    `json:"error,omitempty"`
+	Code      string    `json:"code,omitempty"`    // Outcome code of a file_error (e.g. "PARSE_ERROR") for grouping and filtering
+	Dropped   int       `json:"dropped,omitempty"` // How many events were missed (events_dropped only)
 }
 
+// DefaultBufferSize is how many events a subscriber channel from NewChannel holds before events are dropped
+const DefaultBufferSize = 64
+
+// EventTypeDropped is the type of the catch-up event telling a subscriber how many events it missed
+const EventTypeDropped = "events_dropped"
+
 // Broadcaster manages SSE client subscriptions
 type Broadcaster struct {
-	subscribers map[chan Event]bool
-	mu          sync.RWMutex
+	subscribers map[chan Event]*subscriber
+	bufferSize  int
+	mu          sync.Mutex
+}
+
+// subscriber tracks the events a subscriber missed while its channel was full
+type subscriber struct {
+	dropped int
 }
 
 // NewBroadcaster creates a new event broadcaster
 func NewBroadcaster() *Broadcaster {
 	return &Broadcaster{
-		subscribers: make(map[chan Event]bool),
+		subscribers: make(map[chan Event]*subscriber),
+		bufferSize:  DefaultBufferSize,
 	}
 }
 
+// SetBufferSize sets the capacity of channels created by NewChannel (0 or less uses DefaultBufferSize)
+func (eb *Broadcaster) SetBufferSize(size int) {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.bufferSize = size
+}
+
+// NewChannel returns a channel with the configured buffer size, ready to Subscribe
+func (eb *Broadcaster) NewChannel() chan Event {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	return make(chan Event, eb.bufferSize)
+}
+
 // Subscribe adds a new subscriber
 func (eb *Broadcaster) Subscribe(ch chan Event) {
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
-	eb.subscribers[ch] = true
+	eb.subscribers[ch] = &subscriber{}
 }
 
 // Unsubscribe removes a subscriber
@@ -45,20 +78,41 @@ func (eb *Broadcaster) Unsubscribe(ch chan Event) {
 	close(ch)
 }
 
-// Broadcast sends an event to all subscribers
+// Broadcast sends an event to all subscribers without blocking. A subscriber whose channel is
+// full misses the event; once it has room again it first gets an EventTypeDropped event saying
+// how many it missed, so the UI can refresh instead of silently showing a gap.
 func (eb *Broadcaster) Broadcast(event Event) {
-	eb.mu.RLock()
-	defer eb.mu.RUnlock()
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
 
-	for ch := range eb.subscribers {
+	for ch, sub := range eb.subscribers {
+		if sub.dropped > 0 {
+			select {
+			case ch <- droppedEvent(sub.dropped):
+				sub.dropped = 0
+			default:
+				sub.dropped++
+				continue
+			}
+		}
 		select {
 		case ch <- event:
 		default:
-			// Channel is full, skip this subscriber
+			sub.dropped++
 		}
 	}
 }
 
+// droppedEvent is the catch-up event for a subscriber that missed n events
+func droppedEvent(n int) Event {
+	return Event{
+		Type:      EventTypeDropped,
+		Timestamp: time.Now(),
+		Message:   fmt.Sprintf("Dropped %d events", n),
+		Dropped:   n,
+	}
+}
+
 // BroadcastJSON broadcasts a JSON message directly
 func (eb *Broadcaster) BroadcastJSON(eventType, message string, data map[string]interface{}) {
 	event := Event{
@@ -77,8 +131,10 @@ func (eb *Broadcaster) BroadcastJSON(eventType, message string, data map[string]
 		if err, ok := data["error"].(string); ok {
 			event.Error = err
 		}
+		if code, ok := data["code"].(string); ok {
+			event.Code = code
+		}
 	}
 
 	eb.Broadcast(event)
 }
-
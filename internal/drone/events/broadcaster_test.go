@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package events
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBroadcaster_SlowSubscriberIsToldHowManyEventsItMissed(t *testing.T) {
+	eb := NewBroadcaster()
+	eb.SetBufferSize(2)
+	slow := eb.NewChannel()
+	if cap(slow) != 2 {
+		t.Fatalf("Expected a buffer of 2, got %d", cap(slow))
+	}
+	fast := make(chan Event, 100)
+	eb.Subscribe(slow)
+	eb.Subscribe(fast)
+
+	for i := 1; i <= 5; i++ {
+		eb.BroadcastJSON("file_detected", fmt.Sprintf("File detected: %d.txt", i), nil)
+	}
+	// The slow subscriber catches up on what fit in its buffer
+	for i := 1; i <= 2; i++ {
+		if event := <-slow; event.Message != fmt.Sprintf("File detected: %d.txt", i) {
+			t.Errorf("Expected event %d, got %+v", i, event)
+		}
+	}
+
+	eb.BroadcastJSON("file_complete", "Successfully processed: 6.txt", nil)
+	caughtUp := <-slow
+	if caughtUp.Type != EventTypeDropped || caughtUp.Dropped != 3 || caughtUp.Message != "Dropped 3 events" {
+		t.Errorf("Expected to be told 3 events were dropped, got %+v", caughtUp)
+	}
+	if event := <-slow; event.Type != "file_complete" {
+		t.Errorf("Expected the new event after the catch-up message, got %+v", event)
+	}
+	if len(fast) != 6 {
+		t.Errorf("Expected the fast subscriber to get all 6 events, got %d", len(fast))
+	}
+
+	// With no room for the catch-up message either, the count keeps growing
+	eb.BroadcastJSON("file_detected", "File detected: 7.txt", nil)
+	eb.BroadcastJSON("file_detected", "File detected: 8.txt", nil)
+	eb.BroadcastJSON("file_detected", "File detected: 9.txt", nil)
+	eb.BroadcastJSON("file_detected", "File detected: 10.txt", nil)
+	<-slow
+	<-slow
+	eb.BroadcastJSON("file_detected", "File detected: 11.txt", nil)
+	if event := <-slow; event.Dropped != 2 {
+		t.Errorf("Expected to be told 2 events were dropped, got %+v", event)
+	}
+}
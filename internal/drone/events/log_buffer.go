@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package events
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+// EventTypeLog is the type of the events carrying one drone log line
+const EventTypeLog = "log"
+
+// DefaultLogLines is how many recent log lines a LogBuffer keeps
+const DefaultLogLines = 1000
+
+// LogBuffer is an io.Writer for the drone's log output that keeps the most recent lines and
+// broadcasts each new one as an EventTypeLog event, so the log can be read and tailed over HTTP
+// without access to the console or log file.
+type LogBuffer struct {
+	mu          sync.Mutex
+	lines       []string // Ring of the latest lines; next is where the next one goes
+	next        int
+	full        bool
+	partial     []byte // Text written after the last newline
+	broadcaster *Broadcaster
+}
+
+// NewLogBuffer creates a buffer keeping the last size lines (DefaultLogLines if 0 or less)
+func NewLogBuffer(size int) *LogBuffer {
+	if size <= 0 {
+		size = DefaultLogLines
+	}
+	return &LogBuffer{
+		lines:       make([]string, size),
+		broadcaster: NewBroadcaster(),
+	}
+}
+
+// Write stores and broadcasts every complete line in p; a trailing partial line waits for its newline
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	b.partial = append(b.partial, p...)
+	var complete []string
+	for {
+		i := bytes.IndexByte(b.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(bytes.TrimRight(b.partial[:i], "\r"))
+		b.partial = b.partial[i+1:]
+		b.lines[b.next] = line
+		b.next = (b.next + 1) % len(b.lines)
+		if b.next == 0 {
+			b.full = true
+		}
+		complete = append(complete, line)
+	}
+	b.mu.Unlock()
+
+	now := time.Now()
+	for _, line := range complete {
+		b.broadcaster.Broadcast(Event{Type: EventTypeLog, Timestamp: now, Message: line})
+	}
+	return len(p), nil
+}
+
+// Recent returns up to n of the latest lines, oldest first (all kept lines if n is 0 or less)
+func (b *LogBuffer) Recent(n int) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	count := b.next
+	if b.full {
+		count = len(b.lines)
+	}
+	if n <= 0 || n > count {
+		n = count
+	}
+	recent := make([]string, n)
+	start := b.next - n
+	if start < 0 {
+		start += len(b.lines)
+	}
+	for i := range recent {
+		recent[i] = b.lines[(start+i)%len(b.lines)]
+	}
+	return recent
+}
+
+// Broadcaster returns the broadcaster new lines are sent to
+func (b *LogBuffer) Broadcaster() *Broadcaster {
+	return b.broadcaster
+}
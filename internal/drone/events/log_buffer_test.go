@@ -0,0 +1,36 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package events
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestLogBuffer_KeepsLatestLinesAndBroadcasts(t *testing.T) {
+	buf := NewLogBuffer(3)
+	ch := buf.Broadcaster().NewChannel()
+	buf.Broadcaster().Subscribe(ch)
+	defer buf.Broadcaster().Unsubscribe(ch)
+
+	// A line is only stored once its newline arrives
+	fmt.Fprint(buf, "line 1\nline ")
+	if got := buf.Recent(0); !reflect.DeepEqual(got, []string{"line 1"}) {
+		t.Errorf("Expected only the complete line, got %q", got)
+	}
+	fmt.Fprint(buf, "2\nline 3\nline 4\n")
+
+	if got := buf.Recent(0); !reflect.DeepEqual(got, []string{"line 2", "line 3", "line 4"}) {
+		t.Errorf("Expected the 3 latest lines oldest first, got %q", got)
+	}
+	if got := buf.Recent(2); !reflect.DeepEqual(got, []string{"line 3", "line 4"}) {
+		t.Errorf("Expected the 2 latest lines, got %q", got)
+	}
+	for i := 1; i <= 4; i++ {
+		event := <-ch
+		if event.Type != EventTypeLog || event.Message != fmt.Sprintf("line %d", i) {
+			t.Errorf("Expected log event for line %d, got %+v", i, event)
+		}
+	}
+}
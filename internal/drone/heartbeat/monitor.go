@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/gen2brain/beeep"
+
+	"github.com/the-hive/internal/httpclient"
 )
 
 // Monitor tracks server health status
@@ -78,9 +80,7 @@ func (m *Monitor) checkHealth() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
+	client := httpclient.New(5 * time.Second)
 
 	healthURL := fmt.Sprintf("%s/api/v1/health", m.serverURL)
 	req, err := http.NewRequestWithContext(ctx, "GET", healthURL, nil)
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package notify
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultPerMinute is how many OS notifications are shown per minute unless configured
+const DefaultPerMinute = 5
+
+// Notifier shows an OS (desktop) notification
+type Notifier interface {
+	Notify(title, message string) error
+}
+
+// NotifierFunc adapts a function (e.g. a beeep.Alert wrapper) to Notifier
+type NotifierFunc func(title, message string) error
+
+// Notify calls f
+func (f NotifierFunc) Notify(title, message string) error {
+	return f(title, message)
+}
+
+// Throttler caps how many OS notifications are shown per minute. Notifications over the cap are
+// held back and summarized in a single notification when the minute is up, so a burst of rule
+// matches doesn't flood the desktop with popups.
+type Throttler struct {
+	notifier Notifier
+	window   time.Duration
+
+	mu          sync.Mutex
+	limit       int // Zero or less shows every notification
+	windowStart time.Time
+	sent        int
+	held        int
+	heldTitle   string
+	flush       *time.Timer
+}
+
+// NewThrottler creates a throttler that shows at most perMinute notifications a minute
+func NewThrottler(notifier Notifier, perMinute int) *Throttler {
+	return &Throttler{
+		notifier: notifier,
+		window:   time.Minute,
+		limit:    perMinute,
+	}
+}
+
+// SetLimit changes how many notifications are shown per minute (zero or less for no limit)
+func (t *Throttler) SetLimit(perMinute int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.limit = perMinute
+}
+
+// Notify shows the notification, or holds it back for the summary if this minute's cap is reached
+func (t *Throttler) Notify(title, message string) error {
+	t.mu.Lock()
+	now := time.Now()
+	if now.Sub(t.windowStart) >= t.window {
+		t.windowStart = now
+		t.sent = 0
+	}
+	if t.limit > 0 && t.sent >= t.limit {
+		t.held++
+		t.heldTitle = title
+		if t.flush == nil {
+			t.flush = time.AfterFunc(t.windowStart.Add(t.window).Sub(now), t.flushHeld)
+		}
+		t.mu.Unlock()
+		return nil
+	}
+	t.sent++
+	t.mu.Unlock()
+
+	return t.notifier.Notify(title, message)
+}
+
+// flushHeld shows one summary of the notifications held back, which counts toward the new minute
+func (t *Throttler) flushHeld() {
+	t.mu.Lock()
+	held, title := t.held, t.heldTitle
+	t.held = 0
+	t.flush = nil
+	t.windowStart = time.Now()
+	t.sent = 1
+	t.mu.Unlock()
+
+	if held == 0 {
+		return
+	}
+	message := fmt.Sprintf("%d more notifications were held back to avoid flooding the desktop. Open the drone UI to see them all.", held)
+	if held == 1 {
+		message = "1 more notification was held back to avoid flooding the desktop. Open the drone UI to see it."
+	}
+	if err := t.notifier.Notify(title, message); err != nil {
+		log.Printf("Failed to send OS notification summary: %v", err)
+	}
+}
+
+// Stop cancels a pending summary
+func (t *Throttler) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.flush != nil {
+		t.flush.Stop()
+		t.flush = nil
+	}
+	t.held = 0
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package notify
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingNotifier records the OS notifications it is asked to show
+type recordingNotifier struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (n *recordingNotifier) Notify(title, message string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.messages = append(n.messages, message)
+	return nil
+}
+
+func (n *recordingNotifier) shown() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string(nil), n.messages...)
+}
+
+func TestThrottler_CapsBurstAndSummarizesOverflow(t *testing.T) {
+	notifier := &recordingNotifier{}
+	throttler := NewThrottler(notifier, 3)
+	throttler.window = 50 * time.Millisecond
+	defer throttler.Stop()
+
+	for i := 0; i < 20; i++ {
+		if err := throttler.Notify("The Hive - Rule Match", "match"); err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+	}
+	if got := notifier.shown(); len(got) != 3 {
+		t.Fatalf("Expected the burst to be capped at 3 notifications, got %d", len(got))
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(notifier.shown()) < 4 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	got := notifier.shown()
+	if len(got) != 4 || !strings.HasPrefix(got[3], "17 more notifications") {
+		t.Fatalf("Expected one summary of the 17 held back, got %v", got)
+	}
+
+	// The summary counts toward the new window
+	throttler.Notify("The Hive - Rule Match", "match")
+	throttler.Notify("The Hive - Rule Match", "match")
+	throttler.Notify("The Hive - Rule Match", "match")
+	if got := notifier.shown(); len(got) != 6 {
+		t.Errorf("Expected 2 more notifications after the summary, got %d", len(got)-4)
+	}
+}
+
+func TestThrottler_NoLimitShowsEverything(t *testing.T) {
+	notifier := &recordingNotifier{}
+	throttler := NewThrottler(notifier, 0)
+	for i := 0; i < 20; i++ {
+		throttler.Notify("The Hive - Rule Match", "match")
+	}
+	if got := notifier.shown(); len(got) != 20 {
+		t.Errorf("Expected every notification without a limit, got %d", len(got))
+	}
+}
@@ -18,8 +18,37 @@ type IngestType string
 const (
 	IngestTypeNew    IngestType = "new"
 	IngestTypeUpdate IngestType = "update"
+	IngestTypeMove   IngestType = "move" // Known content at a new path (file moved or renamed)
 )
 
+// SkipReason is a machine-readable code explaining why a file was not ingested
+type SkipReason string
+
+const (
+	SkipReasonUnchanged   SkipReason = "unchanged"
+	SkipReasonEmpty       SkipReason = "empty"
+	SkipReasonTooLarge    SkipReason = "too_large"
+	SkipReasonUnsupported SkipReason = "unsupported"
+)
+
+// OutcomeCode is a machine-readable code for why a file wasn't (fully) ingested. It is sent with
+// file_error events, alongside the human-readable message, and stored with the tracked file.
+type OutcomeCode string
+
+const (
+	OutcomeParseError   OutcomeCode = "PARSE_ERROR"   // Text could not be extracted
+	OutcomeChunkError   OutcomeCode = "CHUNK_ERROR"   // Extracted text could not be chunked
+	OutcomeNoServer     OutcomeCode = "NO_SERVER"     // Parsed, but no Hive server is configured
+	OutcomePartial      OutcomeCode = "PARTIAL"       // Some chunks were ingested
+	OutcomeIngestFailed OutcomeCode = "INGEST_FAILED" // No chunks were ingested
+	OutcomeSizeExceeded OutcomeCode = "SIZE_EXCEEDED" // The file or a chunk is over a size limit
+	OutcomeDeleteFailed OutcomeCode = "DELETE_FAILED" // A removed file's document could not be deleted
+	OutcomeWatchError   OutcomeCode = "WATCH_ERROR"   // The OS file watcher reported an error
+)
+
+// DefaultMaxFileSize is the largest file the drone will ingest (100 MB) unless max_file_size is configured
+const DefaultMaxFileSize int64 = 100 * 1024 * 1024
+
 // FileDecision represents the decision made about a file
 type FileDecision struct {
 	FilePath   string
@@ -27,16 +56,30 @@ type FileDecision struct {
 	IngestType IngestType
 	ShouldProcess bool
 	Reason     string
+	SkipReason SkipReason // Set when ShouldProcess is false
+	PreviousPath string // Set for moves: the tracked path the file was moved from
 }
 
 // DecisionEngine makes decisions about whether to process files
 type DecisionEngine struct {
-	db *database.ClientDB
+	db          *database.ClientDB
+	maxFileSize int64
+	detectMoves bool
 }
 
 // NewDecisionEngine creates a new decision engine
 func NewDecisionEngine(db *database.ClientDB) *DecisionEngine {
-	return &DecisionEngine{db: db}
+	return &DecisionEngine{db: db, maxFileSize: DefaultMaxFileSize, detectMoves: true}
+}
+
+// SetMaxFileSize sets the largest file size that will be processed (0 or less disables the limit)
+func (de *DecisionEngine) SetMaxFileSize(size int64) {
+	de.maxFileSize = size
+}
+
+// SetDetectMoves enables or disables treating a new path with already-tracked content as a move
+func (de *DecisionEngine) SetDetectMoves(enabled bool) {
+	de.detectMoves = enabled
 }
 
 // Decide determines whether and how to process a file
@@ -54,6 +97,13 @@ func (de *DecisionEngine) Decide(filePath string) (*FileDecision, error) {
 
 	if info.Size() == 0 {
 		decision.Reason = "File is empty"
+		decision.SkipReason = SkipReasonEmpty
+		return decision, nil
+	}
+
+	if de.maxFileSize > 0 && info.Size() > de.maxFileSize {
+		decision.Reason = fmt.Sprintf("File too large (%d bytes, limit %d)", info.Size(), de.maxFileSize)
+		decision.SkipReason = SkipReasonTooLarge
 		return decision, nil
 	}
 
@@ -70,6 +120,22 @@ func (de *DecisionEngine) Decide(filePath string) (*FileDecision, error) {
 		return nil, fmt.Errorf("failed to query database: %w", err)
 	}
 
+	if trackedFile == nil && de.detectMoves {
+		previousPath, err := de.findMovedFrom(hash)
+		if err != nil {
+			return nil, err
+		}
+		if previousPath != "" {
+			// Case A': Moved or renamed file - replaces the document at its old path
+			decision.IngestType = IngestTypeMove
+			decision.PreviousPath = previousPath
+			decision.ShouldProcess = true
+			decision.Reason = fmt.Sprintf("File moved from %s", previousPath)
+			log.Printf("File moved: %s -> %s", previousPath, filePath)
+			return decision, nil
+		}
+	}
+
 	if trackedFile == nil {
 		// Case A: New file
 		decision.IngestType = IngestTypeNew
@@ -86,15 +152,44 @@ func (de *DecisionEngine) Decide(filePath string) (*FileDecision, error) {
 		// Case C: Stale file
 		decision.ShouldProcess = false
 		decision.Reason = "File unchanged (hash matches)"
+		decision.SkipReason = SkipReasonUnchanged
 		log.Printf("File unchanged: %s (hash: %s)", filePath, hash)
 	}
 
 	return decision, nil
 }
 
-// MarkProcessed marks a file as processed in the database
-func (de *DecisionEngine) MarkProcessed(decision *FileDecision, serverStatus string) error {
-	return de.db.UpsertTrackedFile(decision.FilePath, decision.FileHash, serverStatus)
+// findMovedFrom returns the tracked path with the same content that no longer exists on disk,
+// or "" if the content is not tracked anywhere else (or every copy is still in place)
+func (de *DecisionEngine) findMovedFrom(hash string) (string, error) {
+	candidates, err := de.db.FindTrackedFilesByHash(hash)
+	if err != nil {
+		return "", fmt.Errorf("failed to query database: %w", err)
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate.FilePath); os.IsNotExist(err) {
+			return candidate.FilePath, nil
+		}
+	}
+	return "", nil
+}
+
+// MarkProcessed marks a file as processed in the database.
+// For a move, the old path is dropped once the document has been re-ingested under the new one.
+// code records why ingestion failed or was partial; it is empty on success.
+func (de *DecisionEngine) MarkProcessed(decision *FileDecision, serverStatus string, code OutcomeCode) error {
+	if err := de.db.ClearSkip(decision.FilePath); err != nil {
+		log.Printf("Failed to clear skip record for %s: %v", decision.FilePath, err)
+	}
+	if err := de.db.UpsertTrackedFile(decision.FilePath, decision.FileHash, serverStatus, string(code)); err != nil {
+		return err
+	}
+	if decision.IngestType == IngestTypeMove && decision.PreviousPath != "" && serverStatus == "success" {
+		if err := de.db.DeleteTrackedFile(decision.PreviousPath); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // calculateFileHash calculates SHA-256 hash of file content
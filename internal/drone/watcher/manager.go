@@ -4,12 +4,15 @@ package watcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -17,12 +20,30 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/the-hive/internal/client"
+	"github.com/the-hive/internal/docid"
 	"github.com/the-hive/internal/drone/database"
 	"github.com/the-hive/internal/drone/events"
+	"github.com/the-hive/internal/grpclimits"
+	"github.com/the-hive/internal/langdetect"
 	"github.com/the-hive/internal/parser"
+	"github.com/the-hive/internal/processor"
 	"github.com/the-hive/internal/proto"
 )
 
+// removalGracePeriod is how long a removed or renamed-away path is given to reappear (editors that save by
+// replacing the file) or to be picked up as a move before its document is deleted from the Hive
+const removalGracePeriod = 2 * time.Second
+
+// DefaultMaxWatchedDirs caps the directories watched across all paths unless SetMaxWatchedDirs changes it
+const DefaultMaxWatchedDirs = 8192
+
+var (
+	// errWatchCapReached means max_watched_dirs directories are already watched
+	errWatchCapReached = errors.New("watched directory limit reached")
+	// errOSWatchLimit means the OS refused another watch (inotify's max_user_watches on Linux)
+	errOSWatchLimit = errors.New("OS watch limit reached")
+)
+
 // Manager manages file watchers for multiple directories
 type Manager struct {
 	watchPaths       []string
@@ -32,10 +53,19 @@ type Manager struct {
 	eventBroadcaster *events.Broadcaster
 	watchers         map[string]*fsnotify.Watcher
 	droneClient      *client.DroneClient
-	chunker          *parser.Chunker
+	chunkers         *processor.ChunkerRegistry // Chunking strategy for each file type
+	chunkStrategies  map[string]string          // Overrides of the default strategies, kept across SetChunker
 	debouncer        *Debouncer
+	removals         *Debouncer // Removed or renamed-away paths waiting out removalGracePeriod
 	decisionEngine   *DecisionEngine
 	clientDB         *database.ClientDB
+	maxWatchedDirs   int
+	watchedDirs      map[string]int    // Directories watched under each root path
+	watchWarnings    map[string]string // Root path -> why some of its directories aren't watched
+	watchMu          sync.Mutex        // Guards the watch counts, which event goroutines update without holding mu
+	pollPaths        map[string]bool   // Watch paths (absolute) that are polled instead of watched for events
+	pollInterval     time.Duration
+	pollers          map[string]*poller
 	mu               sync.RWMutex
 	ctx              context.Context
 	cancel           context.CancelFunc
@@ -44,10 +74,14 @@ type Manager struct {
 
 // Status represents the current watcher status
 type Status struct {
-	WatchingPaths []string `json:"watching_paths"`
-	TotalFiles    int      `json:"total_files"`
-	Processed     int      `json:"processed"`
-	Errors        int      `json:"errors"`
+	WatchingPaths  []string `json:"watching_paths"`
+	TotalFiles     int      `json:"total_files"`
+	Processed      int      `json:"processed"`
+	Errors         int      `json:"errors"`
+	WatchedDirs    int      `json:"watched_dirs"`
+	MaxWatchedDirs int      `json:"max_watched_dirs"`
+	Warnings       []string `json:"warnings,omitempty"`      // Paths that are only partly watched, with how to fix it
+	PollingPaths   []string `json:"polling_paths,omitempty"` // Watching paths that are rescanned on an interval
 }
 
 // NewManager creates a new watcher manager
@@ -74,10 +108,17 @@ func NewManager(watchPaths []string, disabledPaths []string, serverAddr string,
 		clientID:         clientID,
 		eventBroadcaster: broadcaster,
 		watchers:         make(map[string]*fsnotify.Watcher),
-		chunker:          parser.NewChunker(),
+		chunkers:         newChunkerRegistry(parser.NewChunker()),
 		debouncer:        debouncer,
+		removals:         NewDebouncer(removalGracePeriod, nil),
 		decisionEngine:   decisionEngine,
 		clientDB:         clientDB,
+		maxWatchedDirs:   DefaultMaxWatchedDirs,
+		watchedDirs:      make(map[string]int),
+		watchWarnings:    make(map[string]string),
+		pollPaths:        make(map[string]bool),
+		pollInterval:     DefaultPollInterval,
+		pollers:          make(map[string]*poller),
 		ctx:              ctx,
 		cancel:           cancel,
 	}
@@ -89,8 +130,11 @@ func NewManager(watchPaths []string, disabledPaths []string, serverAddr string,
 		grpcAddr = strings.TrimPrefix(grpcAddr, "https://")
 		grpcAddr = strings.TrimSpace(grpcAddr)
 
-		log.Printf("Connecting to Hive server gRPC endpoint: %s", grpcAddr)
-		conn, err := grpc.Dial(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		// GRPC_MAX_MESSAGE_BYTES must match the server's so large chunks aren't rejected
+		maxMessageSize := grpclimits.MaxMessageSize()
+		log.Printf("Connecting to Hive server gRPC endpoint: %s (max message size %d bytes)", grpcAddr, maxMessageSize)
+		dialOptions := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, grpclimits.DialOptions(maxMessageSize)...)
+		conn, err := grpc.Dial(grpcAddr, dialOptions...)
 		if err != nil {
 			cancel()
 			// Don't close clientDB here - let main() handle it via watcherMgr.Stop()
@@ -99,6 +143,7 @@ func NewManager(watchPaths []string, disabledPaths []string, serverAddr string,
 
 		hiveClient := proto.NewHiveClient(conn)
 		mgr.droneClient = client.NewDroneClient(hiveClient)
+		mgr.droneClient.SetMaxMessageSize(maxMessageSize)
 		log.Printf("Successfully connected to Hive server gRPC endpoint")
 	} else {
 		log.Printf("Warning: No Hive server gRPC address configured. File watching will work but ingestion is disabled.")
@@ -107,6 +152,53 @@ func NewManager(watchPaths []string, disabledPaths []string, serverAddr string,
 	return mgr, nil
 }
 
+// SetDetectMoves enables or disables ingesting moved/renamed files as updates to the original document
+func (m *Manager) SetDetectMoves(enabled bool) {
+	m.decisionEngine.SetDetectMoves(enabled)
+}
+
+// SetMaxFileSize sets the largest file that will be ingested; larger files are skipped as too large.
+// Zero or less uses DefaultMaxFileSize.
+func (m *Manager) SetMaxFileSize(size int64) {
+	if size <= 0 {
+		size = DefaultMaxFileSize
+	}
+	m.decisionEngine.SetMaxFileSize(size)
+}
+
+// SetChunker replaces the chunker used to split files before they are sent (and previewed);
+// file types with their own chunking strategy use it to split oversized sections. Call it before Start
+func (m *Manager) SetChunker(chunker *parser.Chunker) {
+	m.chunkers = newChunkerRegistry(chunker)
+	m.chunkers.SetStrategies(m.chunkStrategies)
+}
+
+// SetChunkStrategies overrides the chunking strategy used for file extensions (e.g. {"log": "prose"});
+// see processor.DefaultChunkStrategies for the defaults. Call it before Start
+func (m *Manager) SetChunkStrategies(strategies map[string]string) error {
+	if err := m.chunkers.SetStrategies(strategies); err != nil {
+		return err
+	}
+	m.chunkStrategies = strategies
+	return nil
+}
+
+// newChunkerRegistry selects a chunking strategy by file extension, with chunker for prose
+func newChunkerRegistry(chunker *parser.Chunker) *processor.ChunkerRegistry {
+	return processor.NewChunkerRegistry(chunker, chunker.ChunkSize())
+}
+
+// SetMaxWatchedDirs caps the directories watched across all paths; directories beyond it aren't watched
+// for changes and Status reports a warning. Zero or less uses DefaultMaxWatchedDirs.
+func (m *Manager) SetMaxWatchedDirs(max int) {
+	if max <= 0 {
+		max = DefaultMaxWatchedDirs
+	}
+	m.watchMu.Lock()
+	defer m.watchMu.Unlock()
+	m.maxWatchedDirs = max
+}
+
 // Start starts watching all configured paths
 func (m *Manager) Start(ctx context.Context) error {
 	m.mu.Lock()
@@ -119,6 +211,7 @@ func (m *Manager) Start(ctx context.Context) error {
 		})
 		go m.processFile(filePath)
 	}
+	m.removals.Callback = m.handleRemoved
 
 	// Only watch paths that are not disabled
 	for _, path := range m.watchPaths {
@@ -145,6 +238,10 @@ func (m *Manager) Start(ctx context.Context) error {
 		m.wg.Add(1)
 		go m.processEvents(path, watcher)
 	}
+	for _, p := range m.pollers {
+		m.wg.Add(1)
+		go m.runPoller(p)
+	}
 
 	return nil
 }
@@ -153,6 +250,7 @@ func (m *Manager) Start(ctx context.Context) error {
 func (m *Manager) Stop() {
 	m.cancel()
 	m.debouncer.Stop()
+	m.removals.Stop()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -162,6 +260,14 @@ func (m *Manager) Stop() {
 		}
 		delete(m.watchers, path)
 	}
+	for path, p := range m.pollers {
+		close(p.stop)
+		delete(m.pollers, path)
+	}
+	m.watchMu.Lock()
+	m.watchedDirs = make(map[string]int)
+	m.watchWarnings = make(map[string]string)
+	m.watchMu.Unlock()
 
 	m.wg.Wait()
 
@@ -181,6 +287,7 @@ func (m *Manager) Reload(newPaths []string, disabledPaths []string) error {
 	m.watchPaths = newPaths
 	m.disabledPaths = disabledPaths
 	m.watchers = make(map[string]*fsnotify.Watcher)
+	m.pollers = make(map[string]*poller)
 	ctx, cancel := context.WithCancel(context.Background())
 	m.ctx = ctx
 	m.cancel = cancel
@@ -231,19 +338,35 @@ func (m *Manager) TogglePath(path string, enabled bool) error {
 		if watcher, exists := m.watchers[path]; exists {
 			watcher.Close()
 			delete(m.watchers, path)
+			m.watchMu.Lock()
+			delete(m.watchedDirs, path)
+			delete(m.watchWarnings, path)
+			m.watchMu.Unlock()
 			log.Printf("Disabled watching path: %s", path)
 		}
+		if p, exists := m.pollers[path]; exists {
+			close(p.stop)
+			delete(m.pollers, path)
+			log.Printf("Disabled polling path: %s", path)
+		}
 	} else {
 		// Remove from disabled and add watcher
 		m.disabledPaths = newDisabled
 		// Add watcher if not already watching
-		if _, exists := m.watchers[path]; !exists {
+		_, watching := m.watchers[path]
+		_, polling := m.pollers[path]
+		if !watching && !polling {
 			if err := m.addWatchPath(path); err != nil {
 				return fmt.Errorf("failed to enable path %s: %w", path, err)
 			}
-			// Start event processing for this watcher
-			m.wg.Add(1)
-			go m.processEvents(path, m.watchers[path])
+			// Start event processing (or polling) for this path
+			if watcher, exists := m.watchers[path]; exists {
+				m.wg.Add(1)
+				go m.processEvents(path, watcher)
+			} else if p, exists := m.pollers[path]; exists {
+				m.wg.Add(1)
+				go m.runPoller(p)
+			}
 			log.Printf("Enabled watching path: %s", path)
 		}
 		return nil
@@ -258,13 +381,31 @@ func (m *Manager) Status() Status {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	paths := make([]string, 0, len(m.watchers))
+	paths := make([]string, 0, len(m.watchers)+len(m.pollers))
 	for path := range m.watchers {
 		paths = append(paths, path)
 	}
+	var polling []string
+	for path := range m.pollers {
+		paths = append(paths, path)
+		polling = append(polling, path)
+	}
+	sort.Strings(polling)
+
+	m.watchMu.Lock()
+	defer m.watchMu.Unlock()
+	warnings := make([]string, 0, len(m.watchWarnings))
+	for _, warning := range m.watchWarnings {
+		warnings = append(warnings, warning)
+	}
+	sort.Strings(warnings)
 
 	return Status{
-		WatchingPaths: paths,
+		WatchingPaths:  paths,
+		WatchedDirs:    m.totalWatchedDirs(),
+		MaxWatchedDirs: m.maxWatchedDirs,
+		Warnings:       warnings,
+		PollingPaths:   polling,
 	}
 }
 
@@ -280,6 +421,9 @@ func (m *Manager) addWatchPath(rootPath string) error {
 	if _, exists := m.watchers[absPath]; exists {
 		return nil
 	}
+	if _, exists := m.pollers[absPath]; exists {
+		return nil
+	}
 
 	// Create directory if it doesn't exist
 	if _, err := os.Stat(absPath); os.IsNotExist(err) {
@@ -289,27 +433,47 @@ func (m *Manager) addWatchPath(rootPath string) error {
 		log.Printf("Created watch directory: %s", absPath)
 	}
 
+	// Network shares are rescanned on an interval instead, and don't use OS watches
+	if m.pollPaths[absPath] {
+		m.addPollPath(absPath)
+		return nil
+	}
+
 	// Create watcher
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return fmt.Errorf("failed to create watcher: %w", err)
 	}
 
-	// Recursively add all subdirectories
+	// Recursively add all subdirectories, up to the watched directory cap
+	m.watchMu.Lock()
+	defer m.watchMu.Unlock()
+	total, overCap, overOSLimit := 0, 0, 0
 	if err := filepath.Walk(absPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 		if info.IsDir() {
-			if err := watcher.Add(path); err != nil {
+			total++
+			switch err := m.watchDir(absPath, watcher, path); {
+			case errors.Is(err, errWatchCapReached):
+				overCap++
+			case errors.Is(err, errOSWatchLimit):
+				overOSLimit++
+			case err != nil:
 				log.Printf("Warning: failed to watch %s: %v", path, err)
 			}
 		}
 		return nil
 	}); err != nil {
 		watcher.Close()
+		delete(m.watchedDirs, absPath)
 		return fmt.Errorf("failed to walk directory: %w", err)
 	}
+	if overCap > 0 || overOSLimit > 0 {
+		m.watchWarnings[absPath] = watchLimitWarning(absPath, total-overCap-overOSLimit, total, overOSLimit > 0, m.maxWatchedDirs)
+		log.Printf("Warning: %s", m.watchWarnings[absPath])
+	}
 
 	m.watchers[absPath] = watcher
 	log.Printf("Watching directory (recursive): %s", absPath)
@@ -320,6 +484,56 @@ func (m *Manager) addWatchPath(rootPath string) error {
 	return nil
 }
 
+// watchDir adds one directory under root to its watcher unless the cap or the OS limit is reached.
+// The caller must hold watchMu.
+func (m *Manager) watchDir(root string, watcher *fsnotify.Watcher, dir string) error {
+	if m.totalWatchedDirs() >= m.maxWatchedDirs {
+		return errWatchCapReached
+	}
+	if err := watcher.Add(dir); err != nil {
+		if errors.Is(err, syscall.ENOSPC) {
+			return fmt.Errorf("%w: %v", errOSWatchLimit, err)
+		}
+		return err
+	}
+	m.watchedDirs[root]++
+	return nil
+}
+
+// watchNewDir watches a directory created under root after it was added, recording a warning if a limit stops it
+func (m *Manager) watchNewDir(root string, watcher *fsnotify.Watcher, dir string) error {
+	m.watchMu.Lock()
+	defer m.watchMu.Unlock()
+	err := m.watchDir(root, watcher, dir)
+	if errors.Is(err, errWatchCapReached) || errors.Is(err, errOSWatchLimit) {
+		m.watchWarnings[root] = fmt.Sprintf("New directories under %s aren't watched: %v. %s", root, err, watchLimitGuidance(errors.Is(err, errOSWatchLimit), m.maxWatchedDirs))
+	}
+	return err
+}
+
+// totalWatchedDirs counts directories watched across all paths; the caller must hold watchMu
+func (m *Manager) totalWatchedDirs() int {
+	total := 0
+	for _, count := range m.watchedDirs {
+		total += count
+	}
+	return total
+}
+
+// watchLimitWarning explains that only some directories under root are watched and how to fix it
+func watchLimitWarning(root string, watched, total int, osLimit bool, maxWatchedDirs int) string {
+	return fmt.Sprintf("Only %d of %d directories under %s are watched; changes in the rest won't be ingested. %s",
+		watched, total, root, watchLimitGuidance(osLimit, maxWatchedDirs))
+}
+
+// watchLimitGuidance says which limit was hit and how to raise it
+func watchLimitGuidance(osLimit bool, maxWatchedDirs int) string {
+	if osLimit {
+		return "The OS watch limit was reached: on Linux raise it with \"sysctl fs.inotify.max_user_watches=524288\" (persist it in /etc/sysctl.conf), or list large trees under poll_paths to poll them instead."
+	}
+	return fmt.Sprintf("The max_watched_dirs limit (%d) was reached: raise it in the drone config if the OS allows, or list large trees under poll_paths to poll them instead.", maxWatchedDirs)
+}
+
 // processEvents processes file system events
 func (m *Manager) processEvents(path string, watcher *fsnotify.Watcher) {
 	defer m.wg.Done()
@@ -338,7 +552,7 @@ func (m *Manager) processEvents(path string, watcher *fsnotify.Watcher) {
 				info, err := os.Stat(event.Name)
 				if err == nil && info.IsDir() {
 					// Add new directory to watcher
-					if err := watcher.Add(event.Name); err != nil {
+					if err := m.watchNewDir(path, watcher, event.Name); err != nil {
 						log.Printf("Failed to watch new directory %s: %v", event.Name, err)
 					} else {
 						log.Printf("Added new directory to watch: %s", event.Name)
@@ -346,30 +560,112 @@ func (m *Manager) processEvents(path string, watcher *fsnotify.Watcher) {
 				}
 			}
 
+			// A rename away from a path is resolved when the file shows up again: the Create for the
+			// new path carries the same content hash, so the decision engine ingests it as a move.
+			// If it doesn't show up within the watched roots, the removal check deletes its document.
+			if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				log.Printf("File removed or moved away: %s", event.Name)
+				m.removals.Trigger(event.Name)
+			}
+
 			// Handle file changes
 			if event.Op&fsnotify.Write == fsnotify.Write || event.Op&fsnotify.Create == fsnotify.Create {
-				// Skip temporary files
-				if parser.IsTemporaryFile(event.Name) {
+				// Skip temporary and unsupported files
+				if !m.acceptFile(event.Name) {
 					continue
 				}
-				// Check if file type is supported
-				if parser.IsSupportedFile(event.Name) {
-					m.eventBroadcaster.BroadcastJSON("file_detected", fmt.Sprintf("File detected: %s", event.Name), map[string]interface{}{
-						"path": event.Name,
-					})
-					go m.processFile(event.Name)
-				}
+				m.eventBroadcaster.BroadcastJSON("file_detected", fmt.Sprintf("File detected: %s", event.Name), map[string]interface{}{
+					"path": event.Name,
+				})
+				go m.processFile(event.Name)
 			}
 		case err, ok := <-watcher.Errors:
 			if !ok {
 				return
 			}
 			log.Printf("Watcher error for %s: %v", path, err)
-			m.eventBroadcaster.BroadcastJSON("file_error", fmt.Sprintf("Watcher error: %v", err), nil)
+			m.eventBroadcaster.BroadcastJSON("file_error", fmt.Sprintf("Watcher error: %v", err), map[string]interface{}{
+				"error": err.Error(),
+				"code":  string(OutcomeWatchError),
+			})
 		}
 	}
 }
 
+// handleRemoved deletes the documents of a removed path (a file or a whole directory) once the grace
+// period is up. Paths that exist again were replaced in place, and files that moved within the watched
+// roots were already re-ingested as moves, which drops their old tracking record.
+func (m *Manager) handleRemoved(path string) {
+	if _, err := os.Stat(path); err == nil {
+		return
+	}
+
+	var filePaths []string
+	tracked, err := m.clientDB.GetTrackedFile(path)
+	if err != nil {
+		log.Printf("Failed to look up removed file %s: %v", path, err)
+		return
+	}
+	if tracked != nil {
+		filePaths = append(filePaths, tracked.FilePath)
+	}
+	// A removed directory takes every file tracked under it with it
+	under, err := m.clientDB.FindTrackedFilesUnder(path)
+	if err != nil {
+		log.Printf("Failed to look up files under removed directory %s: %v", path, err)
+		return
+	}
+	for _, tf := range under {
+		filePaths = append(filePaths, tf.FilePath)
+	}
+
+	for _, filePath := range filePaths {
+		if _, err := os.Stat(filePath); err == nil {
+			continue
+		}
+		m.deleteDocument(filePath)
+	}
+}
+
+// deleteDocument removes a deleted file's document from the Hive and stops tracking it. If the Hive
+// can't be reached the file stays tracked, so the next removal of that path tries again.
+func (m *Manager) deleteDocument(filePath string) {
+	// Same ID the file was ingested under
+	documentID := docid.New(m.clientID, filePath)
+
+	if m.droneClient != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		err := m.droneClient.DeleteDocument(ctx, documentID, map[string]string{
+			"client_id": m.clientID,
+			"file_path": filePath,
+			"filename":  filepath.Base(filePath),
+		})
+		if err != nil {
+			log.Printf("Failed to delete document for %s: %v", filePath, err)
+			m.eventBroadcaster.BroadcastJSON("file_error", fmt.Sprintf("Failed to delete %s from the Hive: %v", filePath, err), map[string]interface{}{
+				"path":  filePath,
+				"error": err.Error(),
+				"code":  string(OutcomeDeleteFailed),
+			})
+			return
+		}
+	}
+
+	if err := m.clientDB.DeleteTrackedFile(filePath); err != nil {
+		log.Printf("Failed to stop tracking deleted file %s: %v", filePath, err)
+	}
+	if err := m.clientDB.ClearSkip(filePath); err != nil {
+		log.Printf("Failed to clear skip record for deleted file %s: %v", filePath, err)
+	}
+
+	log.Printf("Deleted document for removed file: %s", filePath)
+	m.eventBroadcaster.BroadcastJSON("file_deleted", fmt.Sprintf("File deleted: %s", filePath), map[string]interface{}{
+		"path":        filePath,
+		"document_id": documentID,
+	})
+}
+
 // processExistingFiles processes files that already exist in the directory
 // Uses debouncer to avoid immediate processing of all files at startup
 func (m *Manager) processExistingFiles(dir string) {
@@ -380,12 +676,8 @@ func (m *Manager) processExistingFiles(dir string) {
 			return err
 		}
 		if !info.IsDir() {
-			// Skip temporary files
-			if parser.IsTemporaryFile(path) {
-				return nil
-			}
 			// Process supported file types (use debouncer to batch process)
-			if parser.IsSupportedFile(path) {
+			if m.acceptFile(path) {
 				m.debouncer.Trigger(path)
 			}
 		}
@@ -397,6 +689,31 @@ func (m *Manager) processExistingFiles(dir string) {
 	}
 }
 
+// acceptFile applies the watcher's file filters, recording why unsupported files were skipped.
+// Temporary and editor files are rejected without a record, since they come and go on every save.
+func (m *Manager) acceptFile(filePath string) bool {
+	if parser.IsTemporaryFile(filePath) {
+		return false
+	}
+	if !parser.IsSupportedFile(filePath) {
+		m.recordSkip(filePath, SkipReasonUnsupported, fmt.Sprintf("Unsupported file type: %s", filepath.Ext(filePath)))
+		return false
+	}
+	return true
+}
+
+// recordSkip persists a skip decision so users can audit why a file wasn't ingested
+func (m *Manager) recordSkip(filePath string, reason SkipReason, message string) {
+	if err := m.clientDB.RecordSkip(filePath, string(reason), message); err != nil {
+		log.Printf("Failed to record skipped file %s: %v", filePath, err)
+	}
+}
+
+// SkippedFiles returns the most recent skip decisions, optionally filtered by reason
+func (m *Manager) SkippedFiles(reason string, limit int) ([]database.SkippedFile, error) {
+	return m.clientDB.ListSkippedFiles(reason, limit)
+}
+
 // processFile processes a single file using the decision engine
 func (m *Manager) processFile(filePath string) {
 	// Use decision engine to determine if we should process this file
@@ -408,9 +725,15 @@ func (m *Manager) processFile(filePath string) {
 
 	if !decision.ShouldProcess {
 		log.Printf("Skipping file: %s - %s", filePath, decision.Reason)
-		m.eventBroadcaster.BroadcastJSON("file_skipped", decision.Reason, map[string]interface{}{
-			"path": filePath,
-		})
+		m.recordSkip(filePath, decision.SkipReason, decision.Reason)
+		data := map[string]interface{}{
+			"path":   filePath,
+			"reason": decision.SkipReason,
+		}
+		if decision.SkipReason == SkipReasonTooLarge {
+			data["code"] = string(OutcomeSizeExceeded)
+		}
+		m.eventBroadcaster.BroadcastJSON("file_skipped", decision.Reason, data)
 		return
 	}
 
@@ -428,41 +751,48 @@ func (m *Manager) processFile(filePath string) {
 		m.eventBroadcaster.BroadcastJSON("file_error", fmt.Sprintf("Parse error: %s", err.Error()), map[string]interface{}{
 			"path":  filePath,
 			"error": err.Error(),
+			"code":  string(OutcomeParseError),
 		})
-		m.decisionEngine.MarkProcessed(decision, "chunk_failed")
+		m.decisionEngine.MarkProcessed(decision, "chunk_failed", OutcomeParseError)
 		return
 	}
 
 	// Chunk the text
-	chunks, err := m.chunker.ChunkText(text)
+	chunks, err := m.chunkers.ChunkFile(filePath, text)
 	if err != nil {
 		log.Printf("Failed to chunk text from %s: %v", filePath, err)
 		m.eventBroadcaster.BroadcastJSON("file_error", fmt.Sprintf("Chunk error: %s", err.Error()), map[string]interface{}{
 			"path":  filePath,
 			"error": err.Error(),
+			"code":  string(OutcomeChunkError),
 		})
-		m.decisionEngine.MarkProcessed(decision, "chunk_failed")
+		m.decisionEngine.MarkProcessed(decision, "chunk_failed", OutcomeChunkError)
 		return
 	}
 
-	log.Printf("Extracted %d chunks from %s", len(chunks), filePath)
-
 	// Send chunks to Hive (if client is available)
 	if m.droneClient == nil {
 		log.Printf("Skipping ingestion for %s: No Hive server configured", filePath)
 		m.eventBroadcaster.BroadcastJSON("file_error", fmt.Sprintf("No Hive server configured. File processed but not ingested: %s", filePath), map[string]interface{}{
 			"path": filePath,
+			"code": string(OutcomeNoServer),
 		})
-		m.decisionEngine.MarkProcessed(decision, "no_server")
+		m.decisionEngine.MarkProcessed(decision, "no_server", OutcomeNoServer)
 		return
 	}
 
+	// Chunks too large for one gRPC message are split further
+	chunks = client.SplitOversizedChunks(chunks, m.droneClient.MaxChunkContent())
+	log.Printf("Extracted %d chunks from %s", len(chunks), filePath)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	documentID := filepath.Base(filePath)
+	// Derived from the client and full path so same-named files in different folders don't collide
+	documentID := docid.New(m.clientID, filePath)
 	successCount := 0
 	serverStatus := "success"
+	tooLarge := false // A chunk was over the gRPC message size limit
 
 	// Prepare metadata with file hash, ingest type, and client_id
 	metadata := map[string]string{
@@ -473,20 +803,39 @@ func (m *Manager) processFile(filePath string) {
 		"file_hash":    decision.FileHash,
 		"ingest_type":  string(decision.IngestType),
 		"client_id":    m.clientID,
-		"total_chunks": fmt.Sprintf("%d", len(chunks)), // Add total chunks to metadata
+		"total_chunks": fmt.Sprintf("%d", len(chunks)),    // Add total chunks to metadata
+		"language":     langdetect.DetectDocument(chunks), // The whole document's language, on every chunk
+	}
+
+	// A moved file replaces the document ingested from its old path; the server drops the old
+	// chunks when the first chunk arrives, so only that chunk carries the hint
+	firstChunkMetadata := metadata
+	if decision.IngestType == IngestTypeMove {
+		firstChunkMetadata = make(map[string]string, len(metadata)+2)
+		for k, v := range metadata {
+			firstChunkMetadata[k] = v
+		}
+		firstChunkMetadata["replaces_document_id"] = docid.New(m.clientID, decision.PreviousPath)
+		firstChunkMetadata["replaces_path"] = decision.PreviousPath
 	}
 
 	for i, chunk := range chunks {
-		err := m.droneClient.IngestChunk(ctx, documentID, chunk, i, metadata)
+		chunkMetadata := metadata
+		if i == 0 {
+			chunkMetadata = firstChunkMetadata
+		}
+		err := m.droneClient.IngestChunk(ctx, documentID, chunk, i, chunkMetadata)
 		if err != nil {
 			log.Printf("Failed to ingest chunk %d from %s: %v", i, filePath, err)
 			serverStatus = "partial"
+			tooLarge = tooLarge || errors.Is(err, client.ErrChunkTooLarge)
 			continue
 		}
 		successCount++
 	}
 
 	// Update database with processing result
+	var code OutcomeCode
 	if successCount == len(chunks) {
 		serverStatus = "success"
 		log.Printf("Successfully processed %s (%d chunks, type: %s)", filePath, len(chunks), decision.IngestType)
@@ -496,21 +845,34 @@ func (m *Manager) processFile(filePath string) {
 		})
 	} else if successCount > 0 {
 		serverStatus = "partial"
+		code = ingestFailureCode(OutcomePartial, tooLarge)
 		log.Printf("Partially processed %s (%d/%d chunks)", filePath, successCount, len(chunks))
 		m.eventBroadcaster.BroadcastJSON("file_error", fmt.Sprintf("Partially processed %s", filePath), map[string]interface{}{
 			"path":   filePath,
 			"chunks": successCount,
+			"code":   string(code),
 		})
 	} else {
 		serverStatus = "failed"
+		code = ingestFailureCode(OutcomeIngestFailed, tooLarge)
 		log.Printf("Failed to process %s (0 chunks ingested)", filePath)
 		m.eventBroadcaster.BroadcastJSON("file_error", fmt.Sprintf("Failed to process %s", filePath), map[string]interface{}{
 			"path": filePath,
+			"code": string(code),
 		})
 	}
 
 	// Mark as processed in database
-	if err := m.decisionEngine.MarkProcessed(decision, serverStatus); err != nil {
+	if err := m.decisionEngine.MarkProcessed(decision, serverStatus, code); err != nil {
 		log.Printf("Failed to update database for %s: %v", filePath, err)
 	}
 }
+
+// ingestFailureCode reports a chunk over the message size limit as SIZE_EXCEEDED, since raising
+// the limit is the fix, and any other failure as the given code
+func ingestFailureCode(code OutcomeCode, tooLarge bool) OutcomeCode {
+	if tooLarge {
+		return OutcomeSizeExceeded
+	}
+	return code
+}
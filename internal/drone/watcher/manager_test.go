@@ -0,0 +1,543 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package watcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/the-hive/internal/client"
+	"github.com/the-hive/internal/docid"
+	"github.com/the-hive/internal/drone/events"
+	"github.com/the-hive/internal/proto"
+)
+
+func TestManager_RecordsSkipReasons(t *testing.T) {
+	configDir := t.TempDir()
+	watchDir := t.TempDir()
+
+	imagePath := filepath.Join(watchDir, "image.png")
+	notesPath := filepath.Join(watchDir, "notes.txt")
+	if err := os.WriteFile(imagePath, []byte("not really a png"), 0644); err != nil {
+		t.Fatalf("Failed to write image.png: %v", err)
+	}
+	if err := os.WriteFile(notesPath, []byte("Meeting notes."), 0644); err != nil {
+		t.Fatalf("Failed to write notes.txt: %v", err)
+	}
+
+	mgr, err := NewManager([]string{watchDir}, nil, "", "", "test-client", events.NewBroadcaster(), configDir)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.Stop()
+
+	if mgr.acceptFile(imagePath) {
+		t.Fatal("Expected image.png to be rejected")
+	}
+	// Editor lock and temp files are rejected without a skip record
+	if mgr.acceptFile(filepath.Join(watchDir, "~$notes.docx")) || mgr.acceptFile(filepath.Join(watchDir, "notes.txt.tmp")) {
+		t.Fatal("Expected temporary files to be rejected")
+	}
+	// First pass processes the file; the second finds it unchanged
+	mgr.processFile(notesPath)
+	mgr.processFile(notesPath)
+
+	skipped, err := mgr.SkippedFiles("", 0)
+	if err != nil {
+		t.Fatalf("SkippedFiles failed: %v", err)
+	}
+	reasons := map[string]string{}
+	for _, f := range skipped {
+		reasons[f.FilePath] = f.Reason
+		if f.SkippedAt.IsZero() || f.Message == "" {
+			t.Errorf("Expected a timestamp and message for %s, got %+v", f.FilePath, f)
+		}
+	}
+	if len(skipped) != 2 {
+		t.Fatalf("Expected 2 skipped files, got %+v", skipped)
+	}
+	if reasons[imagePath] != string(SkipReasonUnsupported) {
+		t.Errorf("Expected image.png skipped as unsupported, got %q", reasons[imagePath])
+	}
+	if reasons[notesPath] != string(SkipReasonUnchanged) {
+		t.Errorf("Expected notes.txt skipped as unchanged, got %q", reasons[notesPath])
+	}
+
+	unchanged, err := mgr.SkippedFiles(string(SkipReasonUnchanged), 0)
+	if err != nil || len(unchanged) != 1 || unchanged[0].FilePath != notesPath {
+		t.Errorf("Expected only notes.txt when filtering by reason, got %+v, %v", unchanged, err)
+	}
+
+	// Editing the file clears its skip record once it is processed again
+	if err := os.WriteFile(notesPath, []byte("Updated meeting notes."), 0644); err != nil {
+		t.Fatalf("Failed to update notes.txt: %v", err)
+	}
+	mgr.processFile(notesPath)
+	if remaining, _ := mgr.SkippedFiles("", 0); len(remaining) != 1 {
+		t.Errorf("Expected the processed file's skip record to be cleared, got %+v", remaining)
+	}
+}
+
+// recordingHiveClient captures ingested chunks and deleted documents in place of a Hive server
+type recordingHiveClient struct {
+	chunks  []*proto.Chunk
+	deletes []*proto.DeleteDocumentRequest
+}
+
+func (c *recordingHiveClient) Ingest(ctx context.Context, in *proto.Chunk, opts ...grpc.CallOption) (*proto.Status, error) {
+	c.chunks = append(c.chunks, in)
+	return &proto.Status{Success: true, ChunkId: in.Id}, nil
+}
+
+func (c *recordingHiveClient) Query(ctx context.Context, in *proto.Search, opts ...grpc.CallOption) (*proto.Result, error) {
+	return &proto.Result{}, nil
+}
+
+func (c *recordingHiveClient) DeleteDocument(ctx context.Context, in *proto.DeleteDocumentRequest, opts ...grpc.CallOption) (*proto.Status, error) {
+	c.deletes = append(c.deletes, in)
+	return &proto.Status{Success: true}, nil
+}
+
+func TestManager_IngestsRenamedFileAsMove(t *testing.T) {
+	configDir := t.TempDir()
+	watchDir := t.TempDir()
+
+	oldPath := filepath.Join(watchDir, "draft.txt")
+	newPath := filepath.Join(watchDir, "final.txt")
+	if err := os.WriteFile(oldPath, []byte("Quarterly planning notes."), 0644); err != nil {
+		t.Fatalf("Failed to write draft.txt: %v", err)
+	}
+
+	mgr, err := NewManager([]string{watchDir}, nil, "", "", "test-client", events.NewBroadcaster(), configDir)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.Stop()
+	hive := &recordingHiveClient{}
+	mgr.droneClient = client.NewDroneClient(hive)
+
+	mgr.processFile(oldPath)
+	if len(hive.chunks) == 0 {
+		t.Fatal("Expected draft.txt to be ingested")
+	}
+	if hint := hive.chunks[0].Metadata["replaces_document_id"]; hint != "" {
+		t.Errorf("Expected no replacement hint for a new file, got %q", hint)
+	}
+
+	if err := os.Rename(oldPath, newPath); err != nil {
+		t.Fatalf("Failed to rename file: %v", err)
+	}
+	decision, err := mgr.decisionEngine.Decide(newPath)
+	if err != nil {
+		t.Fatalf("Decide failed: %v", err)
+	}
+	if decision.IngestType != IngestTypeMove || decision.PreviousPath != oldPath {
+		t.Fatalf("Expected a move from %s, got %+v", oldPath, decision)
+	}
+
+	hive.chunks = nil
+	mgr.processFile(newPath)
+	if len(hive.chunks) == 0 {
+		t.Fatal("Expected final.txt to be ingested")
+	}
+	first := hive.chunks[0]
+	if first.DocumentId != docid.New("test-client", newPath) || first.Metadata["ingest_type"] != string(IngestTypeMove) {
+		t.Errorf("Expected final.txt ingested as a move, got %s (%s)", first.DocumentId, first.Metadata["ingest_type"])
+	}
+	if first.Metadata["replaces_document_id"] != docid.New("test-client", oldPath) || first.Metadata["replaces_path"] != oldPath {
+		t.Errorf("Expected the first chunk to replace draft.txt, got %+v", first.Metadata)
+	}
+
+	// Only the new path remains tracked, so the old document is not resurrected or duplicated
+	if tracked, err := mgr.clientDB.GetTrackedFile(oldPath); err != nil || tracked != nil {
+		t.Errorf("Expected %s to be untracked after the move, got %+v, %v", oldPath, tracked, err)
+	}
+	if tracked, err := mgr.clientDB.GetTrackedFile(newPath); err != nil || tracked == nil {
+		t.Errorf("Expected %s to be tracked, got %+v, %v", newPath, tracked, err)
+	}
+	// The rename away from the old path was resolved as a move, so it isn't treated as a deletion
+	mgr.handleRemoved(oldPath)
+	if len(hive.deletes) != 0 {
+		t.Errorf("Expected no deletion after a move within the watched root, got %d", len(hive.deletes))
+	}
+}
+
+func TestManager_DeletesDocumentOfFileMovedOutOfWatchedRoots(t *testing.T) {
+	configDir := t.TempDir()
+	watchDir := t.TempDir()
+	outsideDir := t.TempDir()
+
+	docsDir := filepath.Join(watchDir, "docs")
+	if err := os.Mkdir(docsDir, 0755); err != nil {
+		t.Fatalf("Failed to create docs dir: %v", err)
+	}
+	movedPath := filepath.Join(watchDir, "report.txt")
+	nestedPath := filepath.Join(docsDir, "notes.txt")
+	keptPath := filepath.Join(watchDir, "kept.txt")
+	for path, content := range map[string]string{
+		movedPath:  "Annual report.",
+		nestedPath: "Meeting notes.",
+		keptPath:   "Still here.",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	mgr, err := NewManager([]string{watchDir}, nil, "", "", "test-client", events.NewBroadcaster(), configDir)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.Stop()
+	hive := &recordingHiveClient{}
+	mgr.droneClient = client.NewDroneClient(hive)
+	for _, path := range []string{movedPath, nestedPath, keptPath} {
+		mgr.processFile(path)
+	}
+
+	// A path that reappears (an editor replacing the file on save) is left alone
+	mgr.handleRemoved(keptPath)
+	if len(hive.deletes) != 0 {
+		t.Fatalf("Expected no deletion for a file that still exists, got %d", len(hive.deletes))
+	}
+
+	// Moving a file and a whole directory out of the watched root deletes their documents
+	if err := os.Rename(movedPath, filepath.Join(outsideDir, "report.txt")); err != nil {
+		t.Fatalf("Failed to move file: %v", err)
+	}
+	if err := os.Rename(docsDir, filepath.Join(outsideDir, "docs")); err != nil {
+		t.Fatalf("Failed to move directory: %v", err)
+	}
+	mgr.handleRemoved(movedPath)
+	mgr.handleRemoved(docsDir)
+
+	if len(hive.deletes) != 2 {
+		t.Fatalf("Expected 2 documents deleted, got %d", len(hive.deletes))
+	}
+	for i, path := range []string{movedPath, nestedPath} {
+		if got, want := hive.deletes[i].DocumentId, docid.New("test-client", path); got != want {
+			t.Errorf("Expected %s deleted as %s, got %s", path, want, got)
+		}
+		if tracked, err := mgr.clientDB.GetTrackedFile(path); err != nil || tracked != nil {
+			t.Errorf("Expected %s to be untracked after deletion, got %+v, %v", path, tracked, err)
+		}
+	}
+	if tracked, err := mgr.clientDB.GetTrackedFile(keptPath); err != nil || tracked == nil {
+		t.Errorf("Expected %s to stay tracked, got %+v, %v", keptPath, tracked, err)
+	}
+}
+
+func TestDecisionEngine_MoveDetectionDisabled(t *testing.T) {
+	watchDir := t.TempDir()
+	mgr, err := NewManager([]string{watchDir}, nil, "", "", "test-client", events.NewBroadcaster(), t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.Stop()
+	mgr.SetDetectMoves(false)
+
+	oldPath := filepath.Join(watchDir, "a.txt")
+	newPath := filepath.Join(watchDir, "b.txt")
+	if err := os.WriteFile(oldPath, []byte("Same content."), 0644); err != nil {
+		t.Fatalf("Failed to write a.txt: %v", err)
+	}
+	mgr.processFile(oldPath)
+	if err := os.Rename(oldPath, newPath); err != nil {
+		t.Fatalf("Failed to rename file: %v", err)
+	}
+
+	decision, err := mgr.decisionEngine.Decide(newPath)
+	if err != nil {
+		t.Fatalf("Decide failed: %v", err)
+	}
+	if decision.IngestType != IngestTypeNew {
+		t.Errorf("Expected a new file with move detection disabled, got %s", decision.IngestType)
+	}
+}
+
+func TestManager_SameNamedFilesAreDistinctDocuments(t *testing.T) {
+	watchDir := t.TempDir()
+	mgr, err := NewManager([]string{watchDir}, nil, "", "", "test-client", events.NewBroadcaster(), t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.Stop()
+	hive := &recordingHiveClient{}
+	mgr.droneClient = client.NewDroneClient(hive)
+
+	var paths []string
+	for _, dir := range []string{"finance", "legal"} {
+		path := filepath.Join(watchDir, dir, "report.txt")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+		if err := os.WriteFile(path, []byte("Report for "+dir+"."), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+		paths = append(paths, path)
+		mgr.processFile(path)
+	}
+
+	documents := map[string]string{}
+	for _, chunk := range hive.chunks {
+		documents[chunk.DocumentId] = chunk.Metadata["path"]
+		if chunk.Metadata["filename"] != "report.txt" {
+			t.Errorf("Expected the friendly filename in metadata, got %q", chunk.Metadata["filename"])
+		}
+	}
+	if len(documents) != 2 {
+		t.Fatalf("Expected two distinct documents for same-named files, got %v", documents)
+	}
+	for _, path := range paths {
+		if documents[docid.New("test-client", path)] != path {
+			t.Errorf("Expected %s ingested under its own document_id, got %v", path, documents)
+		}
+	}
+
+	// Another drone (e.g. in another organization) ingesting the same full path gets its own points
+	other, err := NewManager([]string{watchDir}, nil, "", "", "other-client", events.NewBroadcaster(), t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer other.Stop()
+	otherHive := &recordingHiveClient{}
+	other.droneClient = client.NewDroneClient(otherHive)
+	other.processFile(paths[0])
+
+	// Upsert every chunk by point ID, as Qdrant does
+	points := map[string]string{}
+	for _, chunk := range append(append([]*proto.Chunk{}, hive.chunks...), otherHive.chunks...) {
+		points[chunk.Id] = chunk.DocumentId
+	}
+	survivors := map[string]bool{}
+	for _, documentID := range points {
+		survivors[documentID] = true
+	}
+	for _, documentID := range []string{docid.New("test-client", paths[0]), docid.New("test-client", paths[1]), docid.New("other-client", paths[0])} {
+		if !survivors[documentID] {
+			t.Errorf("Expected document %s to keep its points, got %v", documentID, points)
+		}
+	}
+}
+
+func TestManager_WatchedDirCapReportsWarning(t *testing.T) {
+	watchDir := t.TempDir()
+	for _, sub := range []string{"a", "b", "c", "a/deep", "b/deep"} {
+		if err := os.MkdirAll(filepath.Join(watchDir, sub), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", sub, err)
+		}
+	}
+
+	mgr, err := NewManager([]string{watchDir}, nil, "", "", "test-client", events.NewBroadcaster(), t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.Stop()
+	mgr.SetMaxWatchedDirs(3)
+	if err := mgr.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	status := mgr.Status()
+	if status.WatchedDirs != 3 || status.MaxWatchedDirs != 3 {
+		t.Fatalf("Expected 3 of at most 3 directories watched, got %d of %d", status.WatchedDirs, status.MaxWatchedDirs)
+	}
+	if len(status.Warnings) != 1 {
+		t.Fatalf("Expected one warning for the partly watched path, got %v", status.Warnings)
+	}
+	for _, want := range []string{"Only 3 of 6 directories", "max_watched_dirs"} {
+		if !strings.Contains(status.Warnings[0], want) {
+			t.Errorf("Expected the warning to mention %q, got %q", want, status.Warnings[0])
+		}
+	}
+
+	// A directory created later doesn't get past the cap either
+	if err := mgr.watchNewDir(mustAbs(t, watchDir), mgr.watchers[mustAbs(t, watchDir)], filepath.Join(watchDir, "c")); !errors.Is(err, errWatchCapReached) {
+		t.Errorf("Expected a new directory to hit the cap, got %v", err)
+	}
+	if got := mgr.Status().WatchedDirs; got != 3 {
+		t.Errorf("Expected the watched count to stay at 3, got %d", got)
+	}
+}
+
+// mustAbs resolves path the way addWatchPath keys its watchers
+func mustAbs(t *testing.T, path string) string {
+	t.Helper()
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		t.Fatalf("Failed to resolve %s: %v", path, err)
+	}
+	return abs
+}
+
+func TestManager_PollingDetectsChangesWithoutEvents(t *testing.T) {
+	watchDir := mustAbs(t, t.TempDir())
+	mgr, err := NewManager([]string{watchDir}, nil, "", "", "test-client", events.NewBroadcaster(), t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.Stop()
+	hive := &recordingHiveClient{}
+	mgr.droneClient = client.NewDroneClient(hive)
+	// An hour-long interval keeps the background poller idle so the test drives each scan
+	mgr.SetPolling([]string{watchDir}, time.Hour)
+	if err := mgr.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	if len(mgr.watchers) != 0 {
+		t.Fatalf("Expected a polled path to have no fsnotify watcher, got %d", len(mgr.watchers))
+	}
+	p := mgr.pollers[watchDir]
+	if p == nil {
+		t.Fatalf("Expected %s to be polled", watchDir)
+	}
+	if status := mgr.Status(); len(status.PollingPaths) != 1 || status.WatchedDirs != 0 {
+		t.Errorf("Expected the path reported as polled without OS watches, got %+v", status)
+	}
+
+	notesPath := filepath.Join(watchDir, "share", "notes.txt")
+	if err := os.MkdirAll(filepath.Dir(notesPath), 0755); err != nil {
+		t.Fatalf("Failed to create share: %v", err)
+	}
+	if err := os.WriteFile(notesPath, []byte("Notes on the network share."), 0644); err != nil {
+		t.Fatalf("Failed to write notes.txt: %v", err)
+	}
+	mgr.pollOnce(p)
+	if len(hive.chunks) == 0 || hive.chunks[0].Metadata["file_path"] != notesPath {
+		t.Fatalf("Expected the new file to be ingested by polling, got %d chunk(s)", len(hive.chunks))
+	}
+
+	// An unchanged file isn't picked up again; a changed one is
+	hive.chunks = nil
+	mgr.pollOnce(p)
+	if len(hive.chunks) != 0 {
+		t.Errorf("Expected no ingestion without changes, got %d chunk(s)", len(hive.chunks))
+	}
+	if err := os.WriteFile(notesPath, []byte("Revised notes on the network share."), 0644); err != nil {
+		t.Fatalf("Failed to update notes.txt: %v", err)
+	}
+	mgr.pollOnce(p)
+	if len(hive.chunks) == 0 {
+		t.Error("Expected the changed file to be ingested again")
+	}
+}
+
+// failingHiveClient rejects the ingests for which fail returns an error (calls count from 0)
+type failingHiveClient struct {
+	recordingHiveClient
+	calls int
+	fail  func(call int) error
+}
+
+func (c *failingHiveClient) Ingest(ctx context.Context, in *proto.Chunk, opts ...grpc.CallOption) (*proto.Status, error) {
+	call := c.calls
+	c.calls++
+	if err := c.fail(call); err != nil {
+		return nil, err
+	}
+	return c.recordingHiveClient.Ingest(ctx, in, opts...)
+}
+
+func TestManager_FileErrorsCarryOutcomeCodes(t *testing.T) {
+	watchDir := t.TempDir()
+	broadcaster := events.NewBroadcaster()
+	mgr, err := NewManager([]string{watchDir}, nil, "", "", "test-client", broadcaster, t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.Stop()
+	received := make(chan events.Event, 100)
+	broadcaster.Subscribe(received)
+
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		fail    func(call int) error // nil means no Hive server is configured
+		want    OutcomeCode
+		status  string
+	}{
+		{"parse error", "broken.docx", "not a zip archive", nil, OutcomeParseError, "chunk_failed"},
+		{"no server", "notes.txt", "Meeting notes.", nil, OutcomeNoServer, "no_server"},
+		{"partial", "long.txt", strings.Repeat("A sentence about the budget. ", 100), func(call int) error {
+			if call == 0 {
+				return unavailable
+			}
+			return nil
+		}, OutcomePartial, "partial"},
+		{"failed", "down.txt", "The server is down.", func(int) error { return unavailable }, OutcomeIngestFailed, "failed"},
+		{"chunk too large", "big.txt", "Over the server's message size.", func(int) error {
+			return status.Error(codes.ResourceExhausted, "message larger than max")
+		}, OutcomeSizeExceeded, "failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(watchDir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.file, err)
+			}
+			mgr.droneClient = nil
+			if tt.fail != nil {
+				mgr.droneClient = client.NewDroneClient(&failingHiveClient{fail: tt.fail})
+			}
+			mgr.processFile(path)
+
+			event := lastEvent(received)
+			if event.Type != "file_error" || event.Code != string(tt.want) || event.Message == "" {
+				t.Errorf("Expected a file_error with code %s and a message, got %+v", tt.want, event)
+			}
+			tracked, err := mgr.clientDB.GetTrackedFile(path)
+			if err != nil || tracked == nil || tracked.ErrorCode != string(tt.want) || tracked.ServerStatus != tt.status {
+				t.Errorf("Expected %s stored as %s with code %s, got %+v, %v", tt.file, tt.status, tt.want, tracked, err)
+			}
+		})
+	}
+
+	// Files over the size limit are skipped, with the same code
+	mgr.decisionEngine.SetMaxFileSize(4)
+	path := filepath.Join(watchDir, "huge.txt")
+	if err := os.WriteFile(path, []byte("Too large for the drone."), 0644); err != nil {
+		t.Fatalf("Failed to write huge.txt: %v", err)
+	}
+	mgr.processFile(path)
+	if event := lastEvent(received); event.Type != "file_skipped" || event.Code != string(OutcomeSizeExceeded) {
+		t.Errorf("Expected a file_skipped with code %s, got %+v", OutcomeSizeExceeded, event)
+	}
+
+	// A later successful ingest clears the stored code
+	mgr.decisionEngine.SetMaxFileSize(DefaultMaxFileSize)
+	hive := &recordingHiveClient{}
+	mgr.droneClient = client.NewDroneClient(hive)
+	down := filepath.Join(watchDir, "down.txt")
+	if err := os.WriteFile(down, []byte("The server is back."), 0644); err != nil {
+		t.Fatalf("Failed to update down.txt: %v", err)
+	}
+	mgr.processFile(down)
+	if tracked, _ := mgr.clientDB.GetTrackedFile(down); tracked == nil || tracked.ErrorCode != "" || tracked.ServerStatus != "success" {
+		t.Errorf("Expected the code cleared after a successful ingest, got %+v", tracked)
+	}
+}
+
+// lastEvent returns the most recent event already broadcast to ch
+func lastEvent(ch chan events.Event) events.Event {
+	var last events.Event
+	for {
+		select {
+		case event := <-ch:
+			last = event
+		default:
+			return last
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package watcher
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultPollInterval is how often polled paths are rescanned unless SetPolling changes it
+const DefaultPollInterval = 30 * time.Second
+
+// fileStamp is what the poller compares between scans; the decision engine checks the content hash
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+// poller rescans a directory tree on an interval, for network filesystems (SMB/NFS) where fsnotify
+// doesn't reliably deliver events
+type poller struct {
+	root     string
+	interval time.Duration
+	seen     map[string]fileStamp
+	stop     chan struct{}
+}
+
+// newPoller creates a poller whose first scan is the baseline, so only later changes are reported
+func newPoller(root string, interval time.Duration) *poller {
+	p := &poller{root: root, interval: interval, stop: make(chan struct{})}
+	p.seen = p.scan()
+	return p
+}
+
+// scan records the size and modification time of every file under the root
+func (p *poller) scan() map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	err := filepath.Walk(p.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// A file removed mid-scan, or a share that briefly dropped, shouldn't end the scan
+			if path != p.root {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			stamps[path] = fileStamp{size: info.Size(), modTime: info.ModTime()}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Polling %s failed: %v", p.root, err)
+	}
+	return stamps
+}
+
+// changes rescans and returns files that are new or whose size or modification time changed
+func (p *poller) changes() []string {
+	current := p.scan()
+	var changed []string
+	for path, stamp := range current {
+		if previous, ok := p.seen[path]; !ok || previous.size != stamp.size || !previous.modTime.Equal(stamp.modTime) {
+			changed = append(changed, path)
+		}
+	}
+	p.seen = current
+	sort.Strings(changed)
+	return changed
+}
+
+// SetPolling makes the given watch paths be rescanned every interval instead of watched for events.
+// Zero or less uses DefaultPollInterval. It takes effect for paths added after it is called.
+func (m *Manager) SetPolling(paths []string, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pollPaths = make(map[string]bool, len(paths))
+	for _, path := range paths {
+		if absPath, err := filepath.Abs(path); err == nil {
+			m.pollPaths[absPath] = true
+		}
+	}
+	m.pollInterval = interval
+}
+
+// addPollPath starts tracking a polled path; the caller must hold mu and start runPoller
+func (m *Manager) addPollPath(absPath string) {
+	p := newPoller(absPath, m.pollInterval)
+	m.pollers[absPath] = p
+	log.Printf("Polling directory every %s (recursive): %s", p.interval, absPath)
+
+	// Process existing files
+	go m.processExistingFiles(absPath)
+}
+
+// runPoller rescans a polled path until the manager stops or the path is disabled
+func (m *Manager) runPoller(p *poller) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			m.pollOnce(p)
+		}
+	}
+}
+
+// pollOnce processes every file that changed since the previous scan
+func (m *Manager) pollOnce(p *poller) {
+	for _, path := range p.changes() {
+		if !m.acceptFile(path) {
+			continue
+		}
+		m.eventBroadcaster.BroadcastJSON("file_detected", fmt.Sprintf("File detected: %s", path), map[string]interface{}{
+			"path": path,
+		})
+		m.processFile(path)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package watcher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/the-hive/internal/parser"
+	"github.com/the-hive/internal/processor"
+)
+
+// previewSnippetLength is the maximum number of characters returned for the first chunk preview
+const previewSnippetLength = 200
+
+// PreviewResult describes what would happen to a single file if it were ingested
+type PreviewResult struct {
+	Path         string     `json:"path"`
+	ShouldIngest bool       `json:"should_ingest"`
+	IngestType   IngestType `json:"ingest_type,omitempty"`
+	Reason       string     `json:"reason"`
+	ChunkCount   int        `json:"chunk_count"`
+	FirstChunk   string     `json:"first_chunk,omitempty"`
+	Error        string     `json:"error,omitempty"`
+}
+
+// Preview runs the decision engine, parser and chunker against a file or directory
+// without sending anything to the Hive server and without updating the local database
+func (m *Manager) Preview(path string) ([]PreviewResult, error) {
+	return previewPath(m.decisionEngine, m.chunkers, path)
+}
+
+// previewPath walks path (recursively if it is a directory) and previews every regular file
+func previewPath(de *DecisionEngine, chunkers *processor.ChunkerRegistry, path string) ([]PreviewResult, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat path: %w", err)
+	}
+
+	if !info.IsDir() {
+		return []PreviewResult{previewFile(de, chunkers, absPath)}, nil
+	}
+
+	results := []PreviewResult{}
+	err = filepath.Walk(absPath, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			results = append(results, previewFile(de, chunkers, filePath))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory: %w", err)
+	}
+
+	return results, nil
+}
+
+// previewFile mirrors the checks made by processFile, stopping before ingestion
+func previewFile(de *DecisionEngine, chunkers *processor.ChunkerRegistry, filePath string) PreviewResult {
+	result := PreviewResult{Path: filePath}
+
+	// Apply the same filters as the watcher before consulting the decision engine
+	if parser.IsTemporaryFile(filePath) {
+		result.Reason = "Temporary file"
+		return result
+	}
+	if !parser.IsSupportedFile(filePath) {
+		result.Reason = fmt.Sprintf("Unsupported file type: %s", filepath.Ext(filePath))
+		return result
+	}
+
+	decision, err := de.Decide(filePath)
+	if err != nil {
+		result.Reason = "Decision failed"
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Reason = decision.Reason
+	if !decision.ShouldProcess {
+		return result
+	}
+
+	text, err := parser.ParseFile(filePath)
+	if err != nil {
+		result.Reason = "Parse failed"
+		result.Error = err.Error()
+		return result
+	}
+
+	chunks, err := chunkers.ChunkFile(filePath, text)
+	if err != nil {
+		result.Reason = "Chunking failed"
+		result.Error = err.Error()
+		return result
+	}
+
+	result.ShouldIngest = true
+	result.IngestType = decision.IngestType
+	result.ChunkCount = len(chunks)
+	if len(chunks) > 0 {
+		result.FirstChunk = truncatePreview(chunks[0])
+	}
+
+	return result
+}
+
+// truncatePreview shortens a chunk for display in the preview response
+func truncatePreview(s string) string {
+	runes := []rune(s)
+	if len(runes) <= previewSnippetLength {
+		return s
+	}
+	return string(runes[:previewSnippetLength]) + "..."
+}
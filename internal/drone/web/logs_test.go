@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package web
+
+import (
+	"embed"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/the-hive/internal/drone"
+	"github.com/the-hive/internal/drone/events"
+)
+
+func TestHandleLogs_ReturnsRecentLines(t *testing.T) {
+	logBuffer := events.NewLogBuffer(10)
+	droneLog := log.New(logBuffer, "", 0)
+	for _, line := range []string{"Drone client running", "Watching /data/reports", "Ingested budget.xlsx: 12 chunks"} {
+		droneLog.Print(line)
+	}
+
+	config := &drone.Config{}
+	s := NewServer(config, nil, events.NewBroadcaster(), embed.FS{})
+	s.SetLogBuffer(logBuffer)
+	handler := s.Handler()
+
+	get := func(url, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/api/logs?lines=2", "127.0.0.1:52100")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 from localhost, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Lines []string `json:"lines"`
+		Total int      `json:"total"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if want := []string{"Watching /data/reports", "Ingested budget.xlsx: 12 chunks"}; !reflect.DeepEqual(resp.Lines, want) || resp.Total != 2 {
+		t.Errorf("Expected the 2 latest lines %q, got %+v", want, resp)
+	}
+
+	// Other machines are refused unless remote access is enabled
+	if rec := get("/api/logs", "192.0.2.10:52100"); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a remote client, got %d", rec.Code)
+	}
+	if rec := get("/api/logs/stream", "192.0.2.10:52100"); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a remote log stream, got %d", rec.Code)
+	}
+	config.WebServer.RemoteLogs = true
+	if rec := get("/api/logs", "192.0.2.10:52100"); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 for a remote client with remote_logs, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package web
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/drone"
+	"github.com/the-hive/internal/drone/database"
+	"github.com/the-hive/internal/drone/events"
+	"github.com/the-hive/internal/drone/watcher"
+)
+
+func TestHandlePreview_Directory(t *testing.T) {
+	configDir := t.TempDir()
+	watchDir := t.TempDir()
+
+	longText := strings.Repeat("This is a sentence about the quarterly budget. ", 60) // ~2800 chars
+	files := map[string]string{
+		"new.txt":      longText,
+		"unchanged.md": "Already ingested content.",
+		"empty.txt":    "",
+		"image.png":    "not really a png",
+		"~$draft.docx": "temporary",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(watchDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// Record unchanged.md as already processed with its current hash
+	clientDB, err := database.NewClientDB(configDir)
+	if err != nil {
+		t.Fatalf("NewClientDB failed: %v", err)
+	}
+	unchangedPath := filepath.Join(watchDir, "unchanged.md")
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(files["unchanged.md"])))
+	if err := clientDB.UpsertTrackedFile(unchangedPath, hash, "success", ""); err != nil {
+		t.Fatalf("UpsertTrackedFile failed: %v", err)
+	}
+	clientDB.Close()
+
+	// No gRPC address: the preview must work without any server connection
+	mgr, err := watcher.NewManager(nil, nil, "", "", "test-client", events.NewBroadcaster(), configDir)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer mgr.Stop()
+
+	srv := NewServer(&drone.Config{}, mgr, events.NewBroadcaster(), embed.FS{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/preview?path="+url.QueryEscape(watchDir), nil)
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		TotalFiles  int                     `json:"total_files"`
+		WouldIngest int                     `json:"would_ingest"`
+		TotalChunks int                     `json:"total_chunks"`
+		Files       []watcher.PreviewResult `json:"files"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if resp.TotalFiles != len(files) {
+		t.Errorf("Expected %d files, got %d", len(files), resp.TotalFiles)
+	}
+	if resp.WouldIngest != 1 {
+		t.Errorf("Expected 1 file to be ingested, got %d", resp.WouldIngest)
+	}
+
+	byName := make(map[string]watcher.PreviewResult)
+	for _, f := range resp.Files {
+		byName[filepath.Base(f.Path)] = f
+	}
+
+	newFile := byName["new.txt"]
+	if !newFile.ShouldIngest || newFile.IngestType != watcher.IngestTypeNew {
+		t.Errorf("Expected new.txt to be ingested as new, got %+v", newFile)
+	}
+	if newFile.ChunkCount < 2 {
+		t.Errorf("Expected new.txt to produce multiple chunks, got %d", newFile.ChunkCount)
+	}
+	if resp.TotalChunks != newFile.ChunkCount {
+		t.Errorf("Expected total_chunks %d, got %d", newFile.ChunkCount, resp.TotalChunks)
+	}
+	if !strings.HasPrefix(newFile.FirstChunk, "This is a sentence") {
+		t.Errorf("Unexpected first chunk preview: %q", newFile.FirstChunk)
+	}
+
+	for _, name := range []string{"unchanged.md", "empty.txt", "image.png", "~$draft.docx"} {
+		if byName[name].ShouldIngest {
+			t.Errorf("Expected %s to be skipped, got %+v", name, byName[name])
+		}
+	}
+	if byName["unchanged.md"].Reason != "File unchanged (hash matches)" {
+		t.Errorf("Unexpected reason for unchanged.md: %q", byName["unchanged.md"].Reason)
+	}
+
+	// Preview must not record anything in the local database
+	clientDB, err = database.NewClientDB(configDir)
+	if err != nil {
+		t.Fatalf("NewClientDB failed: %v", err)
+	}
+	defer clientDB.Close()
+	tracked, err := clientDB.GetTrackedFile(filepath.Join(watchDir, "new.txt"))
+	if err != nil {
+		t.Fatalf("GetTrackedFile failed: %v", err)
+	}
+	if tracked != nil {
+		t.Errorf("Preview should not track files, found %+v", tracked)
+	}
+}
+
+func TestHandlePreview_MissingPath(t *testing.T) {
+	srv := NewServer(&drone.Config{}, nil, events.NewBroadcaster(), embed.FS{})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/preview", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without path, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/preview?path=/does/not/exist", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing path, got %d", rec.Code)
+	}
+}
@@ -9,16 +9,20 @@ import (
 	"html/template"
 	"io/fs"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/the-hive/internal/drone"
 	"github.com/the-hive/internal/drone/events"
+	"github.com/the-hive/internal/drone/notify"
 	"github.com/the-hive/internal/drone/watcher"
+	"github.com/the-hive/internal/httpclient"
 )
 
 var (
@@ -46,6 +50,8 @@ type Server struct {
 	watcherMgr       *watcher.Manager
 	eventBroadcaster *events.Broadcaster
 	uiFiles          embed.FS
+	osNotifier       *notify.Throttler // Updated when the notification limit is saved
+	logBuffer        *events.LogBuffer // The drone's recent log lines, served by /api/logs
 	mu               sync.RWMutex
 }
 
@@ -59,6 +65,16 @@ func NewServer(config *drone.Config, watcherMgr *watcher.Manager, broadcaster *e
 	}
 }
 
+// SetOSNotifier sets the throttler whose limit follows the saved os_notifications_per_minute
+func (s *Server) SetOSNotifier(notifier *notify.Throttler) {
+	s.osNotifier = notifier
+}
+
+// SetLogBuffer sets the buffer of the drone's log output served by /api/logs and /api/logs/stream
+func (s *Server) SetLogBuffer(logBuffer *events.LogBuffer) {
+	s.logBuffer = logBuffer
+}
+
 // Address returns the server address
 func (s *Server) Address() string {
 	return fmt.Sprintf(":%d", s.config.WebServer.Port)
@@ -81,6 +97,10 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/api/watch-paths/add", s.handleAddWatchPath)
 	mux.HandleFunc("/api/watch-paths/remove", s.handleRemoveWatchPath)
 	mux.HandleFunc("/api/watch-paths/toggle", s.handleToggleWatchPath)
+	mux.HandleFunc("/api/preview", s.handlePreview)
+	mux.HandleFunc("/api/skipped", s.handleSkipped)
+	mux.HandleFunc("/api/logs", s.handleLogs)
+	mux.HandleFunc("/api/logs/stream", s.handleLogStream)
 	mux.HandleFunc("/api/v1/shutdown", s.handleShutdown)
 
 	return mux
@@ -196,6 +216,7 @@ func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
 		"WatchPaths":      config.WatchPaths,
 		"DisabledPaths":   config.DisabledPaths,
 		"WebServer":       config.WebServer,
+		"OSNotificationsPerMinute": config.OSNotificationsPerMinute,
 		"PathStatus":      pathStatus,
 	}
 
@@ -236,9 +257,16 @@ func (s *Server) handleSaveConfig(w http.ResponseWriter, r *http.Request) {
 	if newConfig.WebServer.Port > 0 {
 		s.config.WebServer.Port = newConfig.WebServer.Port
 	}
+	if newConfig.OSNotificationsPerMinute > 0 {
+		s.config.OSNotificationsPerMinute = newConfig.OSNotificationsPerMinute
+	}
 	config := s.config
 	s.mu.Unlock()
 
+	if s.osNotifier != nil {
+		s.osNotifier.SetLimit(config.OSNotificationsPerMinute)
+	}
+
 	// Save to file
 	configPath := ""
 	if r.URL.Query().Get("config") != "" {
@@ -327,11 +355,15 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 	
 	// Add path validation to status
 	statusWithValidation := map[string]interface{}{
-		"watching_paths": status.WatchingPaths,
-		"total_files":    status.TotalFiles,
-		"processed":      status.Processed,
-		"errors":         status.Errors,
-		"path_status":    pathStatus,
+		"watching_paths":   status.WatchingPaths,
+		"total_files":      status.TotalFiles,
+		"processed":        status.Processed,
+		"errors":           status.Errors,
+		"path_status":      pathStatus,
+		"watched_dirs":     status.WatchedDirs,
+		"max_watched_dirs": status.MaxWatchedDirs,
+		"warnings":         status.Warnings,
+		"polling_paths":    status.PollingPaths,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -368,8 +400,8 @@ func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
-	// Create a channel for this client
-	clientChan := make(chan events.Event, 10)
+	// Create a channel for this client; if it falls behind it is told how many events it missed
+	clientChan := s.eventBroadcaster.NewChannel()
 	s.eventBroadcaster.Subscribe(clientChan)
 	defer s.eventBroadcaster.Unsubscribe(clientChan)
 
@@ -576,6 +608,191 @@ func (s *Server) handleToggleWatchPath(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
 }
 
+// handlePreview handles GET /api/preview?path= requests
+// Runs the local decision engine, parser and chunker without contacting the Hive server
+func (s *Server) handlePreview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		http.Error(w, "path query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			http.Error(w, "Path does not exist", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Cannot access path: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	results, err := s.watcherMgr.Preview(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to preview path: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	wouldIngest := 0
+	totalChunks := 0
+	for _, result := range results {
+		if result.ShouldIngest {
+			wouldIngest++
+			totalChunks += result.ChunkCount
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"path":         path,
+		"files":        results,
+		"total_files":  len(results),
+		"would_ingest": wouldIngest,
+		"total_chunks": totalChunks,
+	})
+}
+
+// handleSkipped handles GET /api/skipped?reason=&limit= requests
+// Lists the files the drone decided not to ingest and why
+func (s *Server) handleSkipped(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	reason := r.URL.Query().Get("reason")
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	skipped, err := s.watcherMgr.SkippedFiles(reason, limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list skipped files: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"files": skipped,
+		"total": len(skipped),
+	})
+}
+
+// defaultLogLines is how many log lines /api/logs returns, and /api/logs/stream replays, without ?lines=
+const defaultLogLines = 200
+
+// allowLogAccess reports whether the request may read the drone's log: from localhost always, from
+// other machines only with web_server.remote_logs, since log lines name local files and servers.
+// It writes the error response when it returns false.
+func (s *Server) allowLogAccess(w http.ResponseWriter, r *http.Request) bool {
+	if s.logBuffer == nil {
+		http.Error(w, "Log capture is not enabled", http.StatusServiceUnavailable)
+		return false
+	}
+	s.mu.RLock()
+	remoteLogs := s.config.WebServer.RemoteLogs
+	s.mu.RUnlock()
+	if remoteLogs {
+		return true
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		http.Error(w, "Logs are only available from localhost (set web_server.remote_logs to allow remote access)", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// logLinesParam reads ?lines=, the number of recent log lines wanted
+func logLinesParam(r *http.Request) int {
+	lines := defaultLogLines
+	if v := r.URL.Query().Get("lines"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			lines = n
+		}
+	}
+	return lines
+}
+
+// handleLogs handles GET /api/logs?lines= requests
+// Returns the drone's most recent log lines, oldest first
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.allowLogAccess(w, r) {
+		return
+	}
+
+	lines := []string{}
+	if n := logLinesParam(r); n > 0 {
+		lines = s.logBuffer.Recent(n)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"lines": lines,
+		"total": len(lines),
+	})
+}
+
+// handleLogStream handles GET /api/logs/stream?lines= requests
+// Tails the drone's log over Server-Sent Events: the last lines first, then each new line as it is logged
+func (s *Server) handleLogStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.allowLogAccess(w, r) {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	// Subscribe before replaying so no line is missed in between (one may be sent twice)
+	broadcaster := s.logBuffer.Broadcaster()
+	clientChan := broadcaster.NewChannel()
+	broadcaster.Subscribe(clientChan)
+	defer broadcaster.Unsubscribe(clientChan)
+
+	fmt.Fprintf(w, "data: %s\n\n", `{"type":"connected","message":"Connected to log stream"}`)
+	if n := logLinesParam(r); n > 0 {
+		for _, line := range s.logBuffer.Recent(n) {
+			data, _ := json.Marshal(events.Event{Type: events.EventTypeLog, Message: line})
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case event := <-clientChan:
+			data, _ := json.Marshal(event)
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
 // handleShutdown handles POST /api/v1/shutdown requests
 func (s *Server) handleShutdown(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -606,7 +823,7 @@ func (s *Server) handleShutdown(w http.ResponseWriter, r *http.Request) {
 			req.Header.Set("Authorization", "Bearer "+apiKey)
 			req.Header.Set("Content-Type", "application/json")
 			
-			client := &http.Client{Timeout: 2 * time.Second}
+			client := httpclient.New(2 * time.Second)
 			resp, err := client.Do(req)
 			if err == nil {
 				resp.Body.Close()
@@ -0,0 +1,143 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package embeddings
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBatchSize is how many texts a batch holds when no size is configured
+	DefaultBatchSize = 64
+	// MaxBatchSize caps the texts in one batched provider call
+	MaxBatchSize = 512
+	// MaxBatchWindow caps how long a text may wait for others to join its batch
+	MaxBatchWindow = 250 * time.Millisecond
+	// batchTimeout bounds one batched provider call, which serves several callers
+	batchTimeout = 30 * time.Second
+)
+
+// batchResult is the outcome of one text's embedding in a batch
+type batchResult struct {
+	vector []float32
+	err    error
+}
+
+// batchRequest is a text waiting in the current batch
+type batchRequest struct {
+	text   string
+	result chan batchResult // Buffered, so a flush never blocks on a caller that gave up
+}
+
+// BatchingEmbedder coalesces concurrent EmbedText calls into EmbedBatch calls: a text waits up to
+// the window for others to join, and a full batch is sent at once. Under many small concurrent
+// ingests this makes far fewer (and larger) provider calls. EmbedBatch calls pass straight through.
+type BatchingEmbedder struct {
+	embedder Embedder
+	window   time.Duration
+	size     int
+
+	mu      sync.Mutex
+	pending []batchRequest
+	timer   *time.Timer
+}
+
+// NewBatchingEmbedder wraps an embedder with a batching window (at most MaxBatchWindow) and batch size
+// (1 to MaxBatchSize, DefaultBatchSize if not positive)
+func NewBatchingEmbedder(embedder Embedder, window time.Duration, size int) *BatchingEmbedder {
+	if window < 0 {
+		window = 0
+	}
+	if window > MaxBatchWindow {
+		window = MaxBatchWindow
+	}
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+	if size > MaxBatchSize {
+		size = MaxBatchSize
+	}
+	return &BatchingEmbedder{embedder: embedder, window: window, size: size}
+}
+
+// Dimension returns the wrapped embedder's dimension
+func (b *BatchingEmbedder) Dimension() int {
+	return b.embedder.Dimension()
+}
+
+// EmbedText adds the text to the current batch and waits for the batch's embeddings
+func (b *BatchingEmbedder) EmbedText(ctx context.Context, text string) ([]float32, error) {
+	req := batchRequest{text: text, result: make(chan batchResult, 1)}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, req)
+	var full []batchRequest
+	if len(b.pending) >= b.size {
+		full = b.takeLocked()
+	} else if b.timer == nil {
+		b.timer = time.AfterFunc(b.window, b.flushPending)
+	}
+	b.mu.Unlock()
+
+	if full != nil {
+		b.flush(full)
+	}
+
+	select {
+	case res := <-req.result:
+		return res.vector, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// EmbedBatch embeds the texts in one call to the wrapped embedder
+func (b *BatchingEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	return b.embedder.EmbedBatch(ctx, texts)
+}
+
+// takeLocked empties the current batch and stops its timer; b.mu must be held
+func (b *BatchingEmbedder) takeLocked() []batchRequest {
+	batch := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	return batch
+}
+
+// flushPending sends the current batch when its window ends
+func (b *BatchingEmbedder) flushPending() {
+	b.mu.Lock()
+	batch := b.takeLocked()
+	b.mu.Unlock()
+	if len(batch) > 0 {
+		b.flush(batch)
+	}
+}
+
+// flush embeds a batch in one provider call and hands each caller its vector (or the call's error)
+func (b *BatchingEmbedder) flush(batch []batchRequest) {
+	texts := make([]string, len(batch))
+	for i, req := range batch {
+		texts[i] = req.text
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), batchTimeout)
+	defer cancel()
+	vectors, err := b.embedder.EmbedBatch(ctx, texts)
+	if err == nil && len(vectors) != len(batch) {
+		err = fmt.Errorf("embedding batch returned %d vectors for %d texts", len(vectors), len(batch))
+	}
+	for i, req := range batch {
+		if err != nil {
+			req.result <- batchResult{err: err}
+			continue
+		}
+		req.result <- batchResult{vector: vectors[i]}
+	}
+}
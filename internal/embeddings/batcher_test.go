@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package embeddings
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatchingEmbedder_FullBatchFlushesWithoutWaiting(t *testing.T) {
+	provider := NewMockEmbedder(8)
+	batcher := NewBatchingEmbedder(provider, time.Hour, 3)
+	if batcher.window != MaxBatchWindow {
+		t.Errorf("Expected the window to be capped at %s, got %s", MaxBatchWindow, batcher.window)
+	}
+
+	texts := []string{"alpha", "beta", "gamma"}
+	vectors := make([][]float32, len(texts))
+	var wg sync.WaitGroup
+	for i, text := range texts {
+		wg.Add(1)
+		go func(i int, text string) {
+			defer wg.Done()
+			vector, err := batcher.EmbedText(context.Background(), text)
+			if err != nil {
+				t.Errorf("EmbedText(%q) failed: %v", text, err)
+			}
+			vectors[i] = vector
+		}(i, text)
+	}
+	start := time.Now()
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed >= MaxBatchWindow {
+		t.Errorf("Expected the full batch to be sent without waiting for the window, took %s", elapsed)
+	}
+
+	for i, text := range texts {
+		want, _ := provider.EmbedText(context.Background(), text)
+		if len(vectors[i]) != len(want) || vectors[i][0] != want[0] {
+			t.Errorf("Expected %q to get its own vector back, got %v", text, vectors[i])
+		}
+	}
+}
+
+func TestBatchingEmbedder_BatchErrorReachesEveryCaller(t *testing.T) {
+	provider := &failingEmbedder{dim: 8}
+	batcher := NewBatchingEmbedder(provider, 20*time.Millisecond, 10)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := batcher.EmbedText(context.Background(), "text"); err == nil {
+				t.Error("Expected the provider's error")
+			}
+		}()
+	}
+	wg.Wait()
+	if provider.calls != 1 {
+		t.Errorf("Expected one batched provider call, got %d", provider.calls)
+	}
+}
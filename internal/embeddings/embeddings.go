@@ -19,6 +19,12 @@ type Embedder interface {
 	Dimension() int
 }
 
+// ModelNamer is implemented by embedders that can name the model their vectors come from.
+// Vectors from different models aren't comparable even when their dimensions match.
+type ModelNamer interface {
+	ModelName() string
+}
+
 // NewEmbedder creates an embedder based on the provided type and configuration.
 // Supported types: "openai", "ollama", "mock" (for testing)
 func NewEmbedder(embedderType string, config map[string]string) (Embedder, error) {
@@ -48,6 +54,14 @@ func NewEmbedder(embedderType string, config map[string]string) (Embedder, error
 		if dimStr := config["dimension"]; dimStr != "" {
 			fmt.Sscanf(dimStr, "%d", &dim)
 		}
+		// A seed switches to token-hashing vectors so similar texts rank closer
+		if seedStr := config["seed"]; seedStr != "" {
+			var seed uint32
+			if _, err := fmt.Sscanf(seedStr, "%d", &seed); err != nil {
+				return nil, fmt.Errorf("invalid mock seed %q: %w", seedStr, err)
+			}
+			return NewSeededMockEmbedder(dim, seed), nil
+		}
 		return NewMockEmbedder(dim), nil
 	default:
 		return nil, fmt.Errorf("unknown embedder type: %s", embedderType)
@@ -0,0 +1,172 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// namedEmbedder is one provider in a fallback chain
+type namedEmbedder struct {
+	name     string
+	embedder Embedder
+}
+
+// FallbackEmbedder tries each provider in order until one succeeds, so an outage of the
+// primary provider doesn't stop ingest and chat. Every provider must produce vectors of the
+// primary's dimension and model family: other vectors can't share the collection, because
+// searches would compare them against vectors from a different embedding space.
+type FallbackEmbedder struct {
+	providers []namedEmbedder
+}
+
+// ModelFamily returns the embedder's model without its tag (e.g. "nomic-embed-text" for
+// "nomic-embed-text:latest"), or "" if the embedder doesn't name its model.
+func ModelFamily(embedder Embedder) string {
+	namer, ok := embedder.(ModelNamer)
+	if !ok {
+		return ""
+	}
+	family := strings.ToLower(strings.TrimSpace(namer.ModelName()))
+	if i := strings.Index(family, ":"); i >= 0 {
+		family = family[:i]
+	}
+	return family
+}
+
+// NewFallbackEmbedder chains embedders in order of preference; names are used in logs.
+// Fallbacks whose dimension or model family differs from the primary's are left out with a warning.
+func NewFallbackEmbedder(names []string, embedders []Embedder) (*FallbackEmbedder, error) {
+	if len(embedders) == 0 || len(names) != len(embedders) {
+		return nil, errors.New("fallback chain needs one name per embedder and at least one embedder")
+	}
+
+	dim := embedders[0].Dimension()
+	family := ModelFamily(embedders[0])
+	chain := &FallbackEmbedder{providers: []namedEmbedder{{name: names[0], embedder: embedders[0]}}}
+	for i := 1; i < len(embedders); i++ {
+		if embedders[i].Dimension() != dim {
+			log.Printf("[EMBEDDINGS] Skipping fallback %s: dimension %d is incompatible with %s (%d); use a separate collection for it",
+				names[i], embedders[i].Dimension(), names[0], dim)
+			continue
+		}
+		if fallbackFamily := ModelFamily(embedders[i]); family == "" || fallbackFamily != family {
+			log.Printf("[EMBEDDINGS] Skipping fallback %s: model %q is not the same model family as %s (%q); use a separate collection for it",
+				names[i], fallbackFamily, names[0], family)
+			continue
+		}
+		chain.providers = append(chain.providers, namedEmbedder{name: names[i], embedder: embedders[i]})
+	}
+	return chain, nil
+}
+
+// NewEmbedderChain creates the embedder for each type (e.g. "openai", "ollama") and chains them.
+// A fallback may name its model as "type:model" (e.g. "ollama:nomic-embed-text"); otherwise it uses
+// the primary's configured model. The mock embedder is refused as a fallback, since its vectors
+// would silently pollute the collection.
+func NewEmbedderChain(types []string, config map[string]string) (Embedder, error) {
+	if len(types) == 1 {
+		return NewEmbedder(types[0], config)
+	}
+
+	var names []string
+	var embedders []Embedder
+	for i, embedderType := range types {
+		providerConfig := config
+		if i > 0 {
+			model := ""
+			if j := strings.Index(embedderType, ":"); j >= 0 {
+				embedderType, model = embedderType[:j], embedderType[j+1:]
+			}
+			if embedderType == "mock" {
+				log.Printf("[EMBEDDINGS] Skipping fallback mock: the mock embedder is for tests and can't stand in for %s", types[0])
+				continue
+			}
+			providerConfig = make(map[string]string, len(config))
+			for k, v := range config {
+				providerConfig[k] = v
+			}
+			if model != "" {
+				providerConfig["model"] = model
+			}
+		}
+		embedder, err := NewEmbedder(embedderType, providerConfig)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			log.Printf("[EMBEDDINGS] Skipping fallback %s: %v", embedderType, err)
+			continue
+		}
+		names = append(names, embedderType)
+		embedders = append(embedders, embedder)
+	}
+	return NewFallbackEmbedder(names, embedders)
+}
+
+// Dimension returns the dimension shared by every provider in the chain.
+func (f *FallbackEmbedder) Dimension() int {
+	return f.providers[0].embedder.Dimension()
+}
+
+// Providers returns the provider names in the order they are tried.
+func (f *FallbackEmbedder) Providers() []string {
+	names := make([]string, len(f.providers))
+	for i, provider := range f.providers {
+		names[i] = provider.name
+	}
+	return names
+}
+
+// EmbedText embeds with the first provider that succeeds.
+func (f *FallbackEmbedder) EmbedText(ctx context.Context, text string) ([]float32, error) {
+	var errs []string
+	for i, provider := range f.providers {
+		vector, err := provider.embedder.EmbedText(ctx, text)
+		if err == nil && len(vector) != f.Dimension() {
+			err = fmt.Errorf("returned a %d-dimension vector, expected %d", len(vector), f.Dimension())
+		}
+		if err == nil {
+			if i > 0 {
+				log.Printf("[EMBEDDINGS] Used fallback provider %s (%s)", provider.name, strings.Join(errs, "; "))
+			}
+			return vector, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s failed: %v", provider.name, err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, fmt.Errorf("all embedding providers failed: %s", strings.Join(errs, "; "))
+}
+
+// EmbedBatch embeds the batch with the first provider that succeeds.
+func (f *FallbackEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	var errs []string
+	for i, provider := range f.providers {
+		vectors, err := provider.embedder.EmbedBatch(ctx, texts)
+		if err == nil {
+			for _, vector := range vectors {
+				if len(vector) != f.Dimension() {
+					err = fmt.Errorf("returned a %d-dimension vector, expected %d", len(vector), f.Dimension())
+					break
+				}
+			}
+		}
+		if err == nil {
+			if i > 0 {
+				log.Printf("[EMBEDDINGS] Used fallback provider %s for a batch of %d (%s)", provider.name, len(texts), strings.Join(errs, "; "))
+			}
+			return vectors, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s failed: %v", provider.name, err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, fmt.Errorf("all embedding providers failed: %s", strings.Join(errs, "; "))
+}
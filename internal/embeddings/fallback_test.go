@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// failingEmbedder simulates a provider outage
+type failingEmbedder struct {
+	dim   int
+	model string
+	calls int
+}
+
+func (e *failingEmbedder) EmbedText(ctx context.Context, text string) ([]float32, error) {
+	e.calls++
+	return nil, errors.New("provider unavailable")
+}
+
+func (e *failingEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	return nil, errors.New("provider unavailable")
+}
+
+func (e *failingEmbedder) Dimension() int { return e.dim }
+
+func (e *failingEmbedder) ModelName() string { return e.model }
+
+func TestFallbackEmbedder_UsesNextProviderOnFailure(t *testing.T) {
+	ctx := context.Background()
+	primary := &failingEmbedder{dim: 16, model: "mock"}
+	fallback := NewMockEmbedder(16)
+
+	chain, err := NewFallbackEmbedder([]string{"openai", "mock-small", "mock"}, []Embedder{primary, NewMockEmbedder(8), fallback})
+	if err != nil {
+		t.Fatalf("NewFallbackEmbedder failed: %v", err)
+	}
+	if got := chain.Providers(); !reflect.DeepEqual(got, []string{"openai", "mock"}) {
+		t.Errorf("Expected the incompatible 8-dimension fallback to be left out, got %v", got)
+	}
+
+	vector, err := chain.EmbedText(ctx, "quarterly report")
+	if err != nil {
+		t.Fatalf("Expected the fallback to succeed, got %v", err)
+	}
+	want, _ := fallback.EmbedText(ctx, "quarterly report")
+	if primary.calls != 1 || !reflect.DeepEqual(vector, want) {
+		t.Errorf("Expected the primary to be tried once and the fallback's vector returned")
+	}
+
+	vectors, err := chain.EmbedBatch(ctx, []string{"a", "b"})
+	if err != nil || len(vectors) != 2 || chain.Dimension() != 16 {
+		t.Errorf("Expected batch embedding to fall back too, got %d vectors, %v", len(vectors), err)
+	}
+
+	onlyFailing, _ := NewFallbackEmbedder([]string{"openai"}, []Embedder{primary})
+	if _, err := onlyFailing.EmbedText(ctx, "text"); err == nil {
+		t.Error("Expected an error when every provider fails")
+	}
+}
+
+func TestFallbackEmbedder_RequiresSameModelFamily(t *testing.T) {
+	primary := &failingEmbedder{dim: 16, model: "nomic-embed-text"}
+	sameFamily := &failingEmbedder{dim: 16, model: "nomic-embed-text:latest"}
+	otherModel := &failingEmbedder{dim: 16, model: "mxbai-embed-large"}
+	unnamed := NewMockEmbedder(16)
+
+	chain, err := NewFallbackEmbedder([]string{"primary", "same", "other", "mock"}, []Embedder{primary, sameFamily, otherModel, unnamed})
+	if err != nil {
+		t.Fatalf("NewFallbackEmbedder failed: %v", err)
+	}
+	if got := chain.Providers(); !reflect.DeepEqual(got, []string{"primary", "same"}) {
+		t.Errorf("Expected only the same-family fallback to be kept, got %v", got)
+	}
+}
+
+func TestNewEmbedderChain_RefusesMockFallback(t *testing.T) {
+	embedder, err := NewEmbedderChain([]string{"mock", "mock"}, map[string]string{"dimension": "16"})
+	if err != nil {
+		t.Fatalf("NewEmbedderChain failed: %v", err)
+	}
+	chain, ok := embedder.(*FallbackEmbedder)
+	if !ok {
+		t.Fatalf("Expected a fallback chain, got %T", embedder)
+	}
+	if got := chain.Providers(); !reflect.DeepEqual(got, []string{"mock"}) {
+		t.Errorf("Expected the mock fallback to be refused, got %v", got)
+	}
+}
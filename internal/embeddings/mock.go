@@ -4,13 +4,19 @@ package embeddings
 
 import (
 	"context"
+	"encoding/binary"
+	"fmt"
 	"hash/fnv"
 	"math"
+	"strings"
+	"unicode"
 )
 
 // MockEmbedder generates deterministic mock embeddings for testing.
 type MockEmbedder struct {
-	dim int
+	dim    int
+	seeded bool   // Use token feature hashing so related texts get related vectors
+	seed   uint32 // Mixed into every token hash; different seeds give different (but stable) vector spaces
 }
 
 // NewMockEmbedder creates a new mock embedder with the specified dimension.
@@ -18,13 +24,32 @@ func NewMockEmbedder(dim int) *MockEmbedder {
 	return &MockEmbedder{dim: dim}
 }
 
+// NewSeededMockEmbedder creates a mock embedder whose vectors are built from hashed word tokens.
+// Identical inputs always produce identical vectors, and texts sharing words score a higher
+// cosine similarity, giving realistic nearest-neighbour behaviour without an AI provider.
+func NewSeededMockEmbedder(dim int, seed uint32) *MockEmbedder {
+	return &MockEmbedder{dim: dim, seeded: true, seed: seed}
+}
+
 // Dimension returns the embedding dimension.
 func (e *MockEmbedder) Dimension() int {
 	return e.dim
 }
 
+// ModelName returns "mock"; seeded mocks include their seed, since each seed is its own vector space.
+func (e *MockEmbedder) ModelName() string {
+	if e.seeded {
+		return fmt.Sprintf("mock-seed-%d", e.seed)
+	}
+	return "mock"
+}
+
 // EmbedText generates a deterministic mock embedding based on text hash.
 func (e *MockEmbedder) EmbedText(ctx context.Context, text string) ([]float32, error) {
+	if e.seeded {
+		return e.embedTokens(text), nil
+	}
+
 	// Generate deterministic "embeddings" based on text hash
 	h := fnv.New32a()
 	h.Write([]byte(text))
@@ -38,6 +63,45 @@ func (e *MockEmbedder) EmbedText(ctx context.Context, text string) ([]float32, e
 	}
 
 	// Normalize the vector
+	normalize(embedding)
+
+	return embedding, nil
+}
+
+// embedTokens builds a normalized bag-of-words vector using the hashing trick:
+// each lowercase token adds +/-1 to a bucket chosen by its seeded hash.
+func (e *MockEmbedder) embedTokens(text string) []float32 {
+	embedding := make([]float32, e.dim)
+	if e.dim == 0 {
+		return embedding
+	}
+
+	var seedBytes [4]byte
+	binary.LittleEndian.PutUint32(seedBytes[:], e.seed)
+
+	tokens := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, token := range tokens {
+		h := fnv.New64a()
+		h.Write(seedBytes[:])
+		h.Write([]byte(token))
+		sum := h.Sum64()
+
+		bucket := int(sum % uint64(e.dim))
+		if (sum>>63)&1 == 1 {
+			embedding[bucket]--
+		} else {
+			embedding[bucket]++
+		}
+	}
+
+	normalize(embedding)
+	return embedding
+}
+
+// normalize scales the vector to unit length in place
+func normalize(embedding []float32) {
 	var sum float32
 	for _, v := range embedding {
 		sum += v * v
@@ -48,8 +112,6 @@ func (e *MockEmbedder) EmbedText(ctx context.Context, text string) ([]float32, e
 			embedding[i] /= norm
 		}
 	}
-
-	return embedding, nil
 }
 
 // EmbedBatch generates embeddings for multiple texts.
@@ -0,0 +1,67 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package embeddings
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func cosine(a, b []float32) float32 {
+	var dot float32
+	for i := range a {
+		dot += a[i] * b[i]
+	}
+	return dot // Vectors are normalized
+}
+
+func TestSeededMockEmbedder_Deterministic(t *testing.T) {
+	ctx := context.Background()
+	e1 := NewSeededMockEmbedder(64, 42)
+	e2 := NewSeededMockEmbedder(64, 42)
+
+	v1, _ := e1.EmbedText(ctx, "Quarterly revenue report")
+	v2, _ := e2.EmbedText(ctx, "Quarterly revenue report")
+	if !reflect.DeepEqual(v1, v2) {
+		t.Errorf("Expected identical inputs to yield identical vectors")
+	}
+	if len(v1) != 64 {
+		t.Errorf("Expected dimension 64, got %d", len(v1))
+	}
+
+	other, _ := NewSeededMockEmbedder(64, 7).EmbedText(ctx, "Quarterly revenue report")
+	if reflect.DeepEqual(v1, other) {
+		t.Errorf("Expected a different seed to give a different vector")
+	}
+}
+
+func TestSeededMockEmbedder_RelatedInputsRankCloser(t *testing.T) {
+	ctx := context.Background()
+	e := NewSeededMockEmbedder(256, 1)
+
+	query, _ := e.EmbedText(ctx, "employee vacation policy")
+	related, _ := e.EmbedText(ctx, "The vacation policy for every employee is 20 days")
+	unrelated, _ := e.EmbedText(ctx, "Server rack power supply specifications")
+
+	if cosine(query, related) <= cosine(query, unrelated) {
+		t.Errorf("Expected related text to score higher: related=%.3f unrelated=%.3f", cosine(query, related), cosine(query, unrelated))
+	}
+}
+
+func TestNewEmbedder_MockSeedAndDimension(t *testing.T) {
+	e, err := NewEmbedder("mock", map[string]string{"dimension": "32", "seed": "9"})
+	if err != nil {
+		t.Fatalf("NewEmbedder failed: %v", err)
+	}
+	if e.Dimension() != 32 {
+		t.Errorf("Expected dimension 32, got %d", e.Dimension())
+	}
+	if mock, ok := e.(*MockEmbedder); !ok || !mock.seeded || mock.seed != 9 {
+		t.Errorf("Expected a seeded mock embedder, got %+v", e)
+	}
+
+	if _, err := NewEmbedder("mock", map[string]string{"seed": "abc"}); err == nil {
+		t.Errorf("Expected error for invalid seed")
+	}
+}
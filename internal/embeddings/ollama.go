@@ -8,29 +8,58 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/the-hive/internal/httpclient"
+)
+
+// Retry policy for requests to Ollama; a local instance may still be starting or loading the model
+const (
+	ollamaRetryAttempts  = 5
+	ollamaRetryBaseDelay = 500 * time.Millisecond
+	ollamaRetryMaxDelay  = 8 * time.Second
+	// ollamaProbeTimeout bounds the startup call that discovers the model's dimension, retries included
+	ollamaProbeTimeout = 2 * time.Minute
 )
 
 // OllamaEmbedder uses a local Ollama instance for embeddings.
 type OllamaEmbedder struct {
-	baseURL string
-	model   string
-	client  *http.Client
-	dim     int
+	baseURL        string
+	model          string
+	client         *http.Client
+	dim            int
+	retryBaseDelay time.Duration
 }
 
-// NewOllamaEmbedder creates a new Ollama embedder.
+// NewOllamaEmbedder creates a new Ollama embedder. The model's dimension is discovered by embedding
+// a probe text, so it fails if Ollama can't be reached (after retries) or the model can't embed.
 func NewOllamaEmbedder(baseURL, model string) (*OllamaEmbedder, error) {
-	// Default dimension for nomic-embed-text
-	dim := 768
-
-	return &OllamaEmbedder{
-		baseURL: baseURL,
-		model:   model,
-		client:  &http.Client{Timeout: 60 * time.Second}, // Ollama can be slower
-		dim:     dim,
-	}, nil
+	e := &OllamaEmbedder{
+		baseURL:        strings.TrimRight(baseURL, "/"),
+		model:          model,
+		client:         httpclient.New(60 * time.Second), // Ollama can be slower
+		retryBaseDelay: ollamaRetryBaseDelay,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ollamaProbeTimeout)
+	defer cancel()
+	if err := e.probe(ctx); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// probe embeds a short text to learn the model's dimension
+func (e *OllamaEmbedder) probe(ctx context.Context) error {
+	vector, err := e.EmbedText(ctx, "dimension probe")
+	if err != nil {
+		return fmt.Errorf("failed to probe ollama model %s at %s: %w", e.model, e.baseURL, err)
+	}
+	e.dim = len(vector)
+	return nil
 }
 
 // Dimension returns the embedding dimension.
@@ -38,6 +67,11 @@ func (e *OllamaEmbedder) Dimension() int {
 	return e.dim
 }
 
+// ModelName returns the Ollama model used for embeddings.
+func (e *OllamaEmbedder) ModelName() string {
+	return e.model
+}
+
 // EmbedText generates an embedding for a single text.
 func (e *OllamaEmbedder) EmbedText(ctx context.Context, text string) ([]float32, error) {
 	type requestPayload struct {
@@ -55,23 +89,61 @@ func (e *OllamaEmbedder) EmbedText(ctx context.Context, text string) ([]float32,
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
+	var embedding []float64
+	delay := e.retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		var retryable bool
+		embedding, retryable, err = e.embed(ctx, jsonData)
+		if err == nil || !retryable || attempt == ollamaRetryAttempts {
+			break
+		}
+
+		log.Printf("[EMBEDDINGS] Ollama request failed (attempt %d/%d), retrying in %v: %v", attempt, ollamaRetryAttempts, delay, err)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > ollamaRetryMaxDelay {
+			delay = ollamaRetryMaxDelay
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(embedding) == 0 {
+		return nil, fmt.Errorf("ollama returned an empty embedding (is %s an embedding model?)", e.model)
+	}
+
+	// Convert float64 to float32
+	result := make([]float32, len(embedding))
+	for i, v := range embedding {
+		result[i] = float32(v)
+	}
+
+	return result, nil
+}
+
+// embed sends one request to /api/embeddings. retryable reports whether the failure is transient:
+// the connection failed or Ollama answered with a server error while starting up.
+func (e *OllamaEmbedder) embed(ctx context.Context, jsonData []byte) (embedding []float64, retryable bool, err error) {
 	url := fmt.Sprintf("%s/api/embeddings", e.baseURL)
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := e.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, ctx.Err() == nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("ollama API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, resp.StatusCode >= http.StatusInternalServerError, fmt.Errorf("ollama API error (status %d): %s", resp.StatusCode, string(body))
 	}
 
 	type responsePayload struct {
@@ -80,16 +152,9 @@ func (e *OllamaEmbedder) EmbedText(ctx context.Context, text string) ([]float32,
 
 	var response responsePayload
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	// Convert float64 to float32
-	result := make([]float32, len(response.Embedding))
-	for i, v := range response.Embedding {
-		result[i] = float32(v)
+		return nil, false, fmt.Errorf("failed to decode response: %w", err)
 	}
-
-	return result, nil
+	return response.Embedding, false, nil
 }
 
 // EmbedBatch generates embeddings for multiple texts (sequential for Ollama).
@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOllamaEmbedder_ProbesDimensionAndRetriesWhileWarmingUp(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/embeddings" {
+			http.NotFound(w, r)
+			return
+		}
+		var req struct {
+			Model  string `json:"model"`
+			Prompt string `json:"prompt"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != "nomic-embed-text" || req.Prompt == "" {
+			t.Errorf("Expected the model and a prompt, got %+v", req)
+		}
+		// The first request arrives while the model is still loading
+		if atomic.AddInt32(&calls, 1) == 1 {
+			http.Error(w, "model loading", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string][]float64{"embedding": {0.1, 0.2, 0.3, 0.4, 0.5}})
+	}))
+	defer server.Close()
+
+	embedder, err := NewEmbedder("ollama", map[string]string{"base_url": server.URL + "/"})
+	if err != nil {
+		t.Fatalf("NewEmbedder failed: %v", err)
+	}
+	if embedder.Dimension() != 5 {
+		t.Errorf("Expected the probed dimension 5, got %d", embedder.Dimension())
+	}
+	if calls != 2 {
+		t.Errorf("Expected the probe to be retried once, got %d calls", calls)
+	}
+
+	vector, err := embedder.EmbedText(context.Background(), "hello")
+	if err != nil || len(vector) != 5 || vector[0] != float32(0.1) {
+		t.Errorf("Expected the embedding from Ollama, got %v (err %v)", vector, err)
+	}
+}
+
+func TestOllamaEmbedder_DoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, `{"error":"model \"missing\" not found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	e := &OllamaEmbedder{baseURL: server.URL, model: "missing", client: server.Client(), retryBaseDelay: time.Millisecond}
+	if err := e.probe(context.Background()); err == nil {
+		t.Fatal("Expected the probe to fail for a missing model")
+	}
+	if calls != 1 {
+		t.Errorf("Expected a 404 not to be retried, got %d calls", calls)
+	}
+
+	// A server that can't be reached is retried until the attempts run out
+	server.Close()
+	if _, err := e.EmbedText(context.Background(), "hello"); err == nil {
+		t.Error("Expected an unreachable Ollama to fail after retrying")
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/the-hive/internal/httpclient"
 )
 
 // OpenAIEmbedder uses OpenAI's embedding API.
@@ -33,7 +35,7 @@ func NewOpenAIEmbedder(apiKey, model string) (*OpenAIEmbedder, error) {
 	return &OpenAIEmbedder{
 		apiKey: apiKey,
 		model:  model,
-		client: &http.Client{Timeout: 30 * time.Second},
+		client: httpclient.New(30 * time.Second),
 		dim:    dim,
 	}, nil
 }
@@ -43,6 +45,11 @@ func (e *OpenAIEmbedder) Dimension() int {
 	return e.dim
 }
 
+// ModelName returns the OpenAI model used for embeddings.
+func (e *OpenAIEmbedder) ModelName() string {
+	return e.model
+}
+
 // EmbedText generates an embedding for a single text.
 func (e *OpenAIEmbedder) EmbedText(ctx context.Context, text string) ([]float32, error) {
 	embeddings, err := e.EmbedBatch(ctx, []string{text})
@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package embeddings
+
+import (
+	"log"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMaxInputTokens keeps inputs under common provider limits (OpenAI embedding models accept 8191 tokens)
+	DefaultMaxInputTokens = 8000
+
+	// TruncatedMetadataKey is set to "true" in a chunk's metadata when its embedding input was truncated
+	TruncatedMetadataKey = "truncated"
+
+	// charsPerToken is a rough average for English text; it errs towards over-estimating tokens
+	charsPerToken = 4
+)
+
+var maxInputTokens = DefaultMaxInputTokens
+
+// SetMaxInputTokens sets the estimated token limit inputs are truncated to before embedding
+func SetMaxInputTokens(tokens int) {
+	if tokens > 0 {
+		maxInputTokens = tokens
+	}
+}
+
+// MaxInputTokens returns the configured embedding input limit in estimated tokens
+func MaxInputTokens() int {
+	return maxInputTokens
+}
+
+// EstimateTokens estimates how many tokens a provider will count for text
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
+
+// TruncateInput cuts text to the configured token limit, preferring a word boundary, and logs a
+// warning when it does. The second result reports whether the text was truncated, so callers can
+// flag the chunk (see TruncatedMetadataKey): its embedding no longer covers all of its content.
+func TruncateInput(text string) (string, bool) {
+	truncated, ok := truncateToTokens(text, maxInputTokens)
+	if ok {
+		log.Printf("[EMBED] Warning: input of ~%d tokens exceeds the %d token limit, embedding the first %d characters only",
+			EstimateTokens(text), maxInputTokens, len(truncated))
+	}
+	return truncated, ok
+}
+
+// truncateToTokens returns text cut to at most maxTokens estimated tokens
+func truncateToTokens(text string, maxTokens int) (string, bool) {
+	if maxTokens <= 0 || EstimateTokens(text) <= maxTokens {
+		return text, false
+	}
+
+	maxRunes := maxTokens * charsPerToken
+	cut := 0
+	for i := range text {
+		if maxRunes == 0 {
+			cut = i
+			break
+		}
+		maxRunes--
+	}
+
+	// Back up to the last whitespace so a word isn't split, unless that would discard too much
+	if i := strings.LastIndexFunc(text[:cut], unicode.IsSpace); i > cut*9/10 {
+		cut = i
+	}
+	return strings.TrimRightFunc(text[:cut], unicode.IsSpace), true
+}
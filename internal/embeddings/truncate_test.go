@@ -0,0 +1,37 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package embeddings
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTruncateInput(t *testing.T) {
+	previous := maxInputTokens
+	t.Cleanup(func() { maxInputTokens = previous })
+	SetMaxInputTokens(10)
+
+	short := "a short chunk"
+	if got, truncated := TruncateInput(short); truncated || got != short {
+		t.Errorf("Expected short input unchanged, got %q (truncated=%v)", got, truncated)
+	}
+
+	long := strings.Repeat("lorem ipsum ", 20)
+	got, truncated := TruncateInput(long)
+	if !truncated {
+		t.Fatal("Expected over-long input to be truncated")
+	}
+	if EstimateTokens(got) > 10 {
+		t.Errorf("Expected at most 10 estimated tokens, got %d (%q)", EstimateTokens(got), got)
+	}
+	if !strings.HasPrefix(long, got) || strings.HasSuffix(got, " ") || strings.HasSuffix(got, "lor") {
+		t.Errorf("Expected a prefix cut at a word boundary, got %q", got)
+	}
+
+	// Multi-byte text is cut on rune boundaries
+	got, _ = TruncateInput(strings.Repeat("日本語", 30))
+	if !strings.HasPrefix(strings.Repeat("日本語", 30), got) || len([]rune(got)) != 40 {
+		t.Errorf("Expected 40 whole runes, got %d runes", len([]rune(got)))
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package grpclimits
+
+import (
+	"log"
+	"os"
+	"strconv"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	// DefaultMaxMessageSize is the largest gRPC message sent or received (gRPC's own default is 4MB)
+	DefaultMaxMessageSize = 16 << 20
+	// EnvMaxMessageSize overrides DefaultMaxMessageSize, in bytes
+	EnvMaxMessageSize = "GRPC_MAX_MESSAGE_BYTES"
+	// messageOverhead is reserved for a chunk's ID, metadata and protobuf framing
+	messageOverhead = 64 << 10
+)
+
+// MaxMessageSize returns the configured message size limit (GRPC_MAX_MESSAGE_BYTES, default 16MB)
+func MaxMessageSize() int {
+	if v := os.Getenv(EnvMaxMessageSize); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+		log.Printf("Invalid %s %q, using default %d", EnvMaxMessageSize, v, DefaultMaxMessageSize)
+	}
+	return DefaultMaxMessageSize
+}
+
+// ServerOptions sets the receive and send limits on a gRPC server
+func ServerOptions(maxMessageSize int) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(maxMessageSize),
+		grpc.MaxSendMsgSize(maxMessageSize),
+	}
+}
+
+// DialOptions sets the receive and send limits on a gRPC client connection
+func DialOptions(maxMessageSize int) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(maxMessageSize),
+			grpc.MaxCallSendMsgSize(maxMessageSize),
+		),
+	}
+}
+
+// MaxChunkContent returns how much chunk text fits in one message, leaving room for the chunk's metadata
+func MaxChunkContent(maxMessageSize int) int {
+	overhead := messageOverhead
+	if overhead > maxMessageSize/4 {
+		overhead = maxMessageSize / 4
+	}
+	return maxMessageSize - overhead
+}
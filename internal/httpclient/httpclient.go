@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package httpclient
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds a whole outbound request (connect, headers and body) when the caller doesn't pick one
+	DefaultTimeout = 30 * time.Second
+	// EnvTimeout overrides DefaultTimeout (a Go duration, e.g. 45s)
+	EnvTimeout = "HTTP_CLIENT_TIMEOUT"
+	// EnvProxyURL sends all outbound requests through this proxy; otherwise HTTPS_PROXY/HTTP_PROXY/NO_PROXY apply
+	EnvProxyURL = "OUTBOUND_PROXY_URL"
+)
+
+var (
+	transportOnce sync.Once
+	transport     *http.Transport
+)
+
+// Transport returns the transport shared by every client, so connections to the same host are reused
+func Transport() *http.Transport {
+	transportOnce.Do(func() {
+		transport = &http.Transport{
+			Proxy: proxyFromEnv(),
+			DialContext: (&net.Dialer{
+				Timeout:   10 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+			IdleConnTimeout:       90 * time.Second,
+			MaxIdleConns:          100,
+			MaxIdleConnsPerHost:   10,
+			ForceAttemptHTTP2:     true,
+		}
+	})
+	return transport
+}
+
+// New returns a client on the shared transport that gives up on a request after timeout.
+// A zero timeout uses the configured default (HTTP_CLIENT_TIMEOUT, default 30s).
+// Each call returns a new client, so callers may adjust it (e.g. CheckRedirect) without affecting others.
+func New(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = defaultTimeout()
+	}
+	return &http.Client{Transport: Transport(), Timeout: timeout}
+}
+
+// defaultTimeout returns the configured default request timeout
+func defaultTimeout() time.Duration {
+	if v := os.Getenv(EnvTimeout); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+		log.Printf("Invalid %s %q, using default %s", EnvTimeout, v, DefaultTimeout)
+	}
+	return DefaultTimeout
+}
+
+// proxyFromEnv uses OUTBOUND_PROXY_URL when set and valid, and the standard proxy variables otherwise
+func proxyFromEnv() func(*http.Request) (*url.URL, error) {
+	if v := os.Getenv(EnvProxyURL); v != "" {
+		proxyURL, err := url.Parse(v)
+		if err == nil && proxyURL.Scheme != "" && proxyURL.Host != "" {
+			return http.ProxyURL(proxyURL)
+		}
+		log.Printf("Invalid %s, falling back to the standard proxy environment", EnvProxyURL)
+	}
+	return http.ProxyFromEnvironment
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package httpclient
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNew_SlowServerTripsTimeout(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	start := time.Now()
+	resp, err := New(100 * time.Millisecond).Get(slow.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("Expected the request to a slow server to time out")
+	}
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("Expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the client to give up promptly, took %s", elapsed)
+	}
+}
+
+func TestNew_DefaultTimeoutFromEnv(t *testing.T) {
+	t.Setenv(EnvTimeout, "45s")
+	if got := New(0).Timeout; got != 45*time.Second {
+		t.Errorf("Expected the configured default timeout, got %s", got)
+	}
+	t.Setenv(EnvTimeout, "soon")
+	if got := New(0).Timeout; got != DefaultTimeout {
+		t.Errorf("Expected an invalid value to fall back to %s, got %s", DefaultTimeout, got)
+	}
+	if New(time.Second).Transport != New(time.Minute).Transport {
+		t.Error("Expected clients to share one transport")
+	}
+}
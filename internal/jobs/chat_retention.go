@@ -0,0 +1,82 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/the-hive/internal/database"
+)
+
+const defaultChatRetentionInterval = 24 * time.Hour
+
+// ChatRetentionPruner deletes chat sessions older than each organization's retention policy.
+// Pinned sessions are kept regardless of age.
+type ChatRetentionPruner struct {
+	store    *database.ChatRetentionStore
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewChatRetentionPruner creates a pruner that runs daily
+func NewChatRetentionPruner(store *database.ChatRetentionStore) *ChatRetentionPruner {
+	return &ChatRetentionPruner{
+		store:    store,
+		interval: defaultChatRetentionInterval,
+		now:      time.Now,
+	}
+}
+
+// SetInterval sets how often scheduled pruning runs
+func (p *ChatRetentionPruner) SetInterval(interval time.Duration) {
+	if interval > 0 {
+		p.interval = interval
+	}
+}
+
+// Start prunes on every tick until the context is cancelled
+func (p *ChatRetentionPruner) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := p.Run(ctx); err != nil {
+					log.Printf("[CHAT RETENTION] Scheduled run failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Run prunes every organization with a retention policy and returns the number of sessions deleted.
+// A failure for one organization doesn't stop the others; the last error is returned.
+func (p *ChatRetentionPruner) Run(ctx context.Context) (int, error) {
+	policies, err := p.store.ListRetentionPolicies(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	var lastErr error
+	for _, policy := range policies {
+		cutoff := p.now().AddDate(0, 0, -policy.RetentionDays)
+		deleted, err := p.store.PruneSessions(ctx, policy.OrganizationID, cutoff)
+		if err != nil {
+			lastErr = fmt.Errorf("org %s: %w", policy.OrganizationID, err)
+			log.Printf("[CHAT RETENTION] Failed to prune chat sessions for org %s: %v", policy.OrganizationID, err)
+			continue
+		}
+		if deleted > 0 {
+			log.Printf("[CHAT RETENTION] Deleted %d chat sessions older than %d days for org %s", deleted, policy.RetentionDays, policy.OrganizationID)
+		}
+		total += deleted
+	}
+	return total, lastErr
+}
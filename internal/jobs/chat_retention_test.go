@@ -0,0 +1,134 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/the-hive/internal/database"
+)
+
+// newChatTestDB creates the chat store's session and message tables
+func newChatTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "chat.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`
+	CREATE TABLE chat_sessions (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		organization_id TEXT NOT NULL,
+		title TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE chat_messages (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		role TEXT NOT NULL,
+		content TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`); err != nil {
+		t.Fatalf("Failed to create chat tables: %v", err)
+	}
+	return db
+}
+
+// seedChatSession stores a session last updated age ago, with one message
+func seedChatSession(t *testing.T, db *sql.DB, id, userID, orgID string, age time.Duration) {
+	t.Helper()
+	updated := time.Now().Add(-age).UTC()
+	if _, err := db.Exec("INSERT INTO chat_sessions (id, user_id, organization_id, title, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+		id, userID, orgID, id, updated, updated); err != nil {
+		t.Fatalf("Failed to seed session %s: %v", id, err)
+	}
+	if _, err := db.Exec("INSERT INTO chat_messages (session_id, role, content) VALUES (?, 'user', 'hello')", id); err != nil {
+		t.Fatalf("Failed to seed message for %s: %v", id, err)
+	}
+}
+
+func chatSessionIDs(t *testing.T, db *sql.DB) map[string]bool {
+	t.Helper()
+	rows, err := db.Query("SELECT id FROM chat_sessions")
+	if err != nil {
+		t.Fatalf("Failed to list sessions: %v", err)
+	}
+	defer rows.Close()
+	ids := map[string]bool{}
+	for rows.Next() {
+		var id string
+		rows.Scan(&id)
+		ids[id] = true
+	}
+	return ids
+}
+
+func TestChatRetentionPruner_KeepsPinnedSessions(t *testing.T) {
+	db := newChatTestDB(t)
+	store, err := database.NewChatRetentionStore(db)
+	if err != nil {
+		t.Fatalf("NewChatRetentionStore failed: %v", err)
+	}
+	ctx := context.Background()
+	day := 24 * time.Hour
+
+	seedChatSession(t, db, "old", "alice", "org-1", 45*day)
+	seedChatSession(t, db, "old-pinned", "alice", "org-1", 90*day)
+	seedChatSession(t, db, "recent", "alice", "org-1", 2*day)
+	seedChatSession(t, db, "other-org-old", "bob", "org-2", 45*day) // org-2 has no retention policy
+
+	if err := store.SetPinned(ctx, "old-pinned", "alice", "org-1", true); err != nil {
+		t.Fatalf("SetPinned failed: %v", err)
+	}
+	if err := store.SetPinned(ctx, "other-org-old", "alice", "org-1", true); err != database.ErrChatSessionNotFound {
+		t.Errorf("Expected pinning someone else's session to fail with not found, got %v", err)
+	}
+	if err := store.SetRetentionDays(ctx, "org-1", 30); err != nil {
+		t.Fatalf("SetRetentionDays failed: %v", err)
+	}
+
+	deleted, err := NewChatRetentionPruner(store).Run(ctx)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Expected 1 session pruned, got %d", deleted)
+	}
+
+	remaining := chatSessionIDs(t, db)
+	if remaining["old"] {
+		t.Error("Expected the unpinned session past retention to be deleted")
+	}
+	for _, id := range []string{"old-pinned", "recent", "other-org-old"} {
+		if !remaining[id] {
+			t.Errorf("Expected session %s to be kept", id)
+		}
+	}
+	var orphaned int
+	db.QueryRow("SELECT COUNT(*) FROM chat_messages WHERE session_id = 'old'").Scan(&orphaned)
+	if orphaned != 0 {
+		t.Errorf("Expected the pruned session's messages to be deleted, %d remain", orphaned)
+	}
+
+	// A user's bulk delete also keeps pinned sessions unless asked to include them
+	if n, err := store.DeleteUserSessions(ctx, "alice", "org-1", time.Time{}, false); err != nil || n != 1 {
+		t.Fatalf("Expected bulk delete to remove 1 session, got %d, %v", n, err)
+	}
+	if ids := chatSessionIDs(t, db); !ids["old-pinned"] || ids["recent"] {
+		t.Errorf("Expected only the pinned session to survive a bulk delete, got %v", ids)
+	}
+	if n, err := store.DeleteUserSessions(ctx, "alice", "org-1", time.Time{}, true); err != nil || n != 1 {
+		t.Fatalf("Expected bulk delete with pinned sessions to remove 1 session, got %d, %v", n, err)
+	}
+	if pinned, _ := store.PinnedSessionIDs(ctx, "alice", "org-1"); len(pinned) != 0 {
+		t.Errorf("Expected the deleted session's pin to be removed, got %v", pinned)
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package jobs
+
+import (
+	"context"
+	"log"
+)
+
+// StatusTracker records the state of long-running jobs for the admin jobs API
+// (implemented by database.JobStatusStore). States are queued, running, done and failed.
+type StatusTracker interface {
+	JobQueued(ctx context.Context, jobID, jobType, organizationID, requestedBy string) error
+	JobRunning(ctx context.Context, jobID string, total int) error
+	JobProgress(ctx context.Context, jobID string, processed, total int) error
+	JobFinished(ctx context.Context, jobID string, jobErr error) error
+}
+
+// trackJob records a status change if a tracker is set. Tracking failures are logged but never
+// fail the job, and the write outlives a cancelled job context so failures are still recorded.
+func trackJob(ctx context.Context, tracker StatusTracker, jobID string, fn func(ctx context.Context, t StatusTracker) error) {
+	if tracker == nil {
+		return
+	}
+	if err := fn(context.WithoutCancel(ctx), tracker); err != nil {
+		log.Printf("Failed to record status for job %s: %v", jobID, err)
+	}
+}
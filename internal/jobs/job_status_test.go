@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/processor"
+	"github.com/the-hive/internal/queue"
+)
+
+// observingTracker records each state the job passes through before storing it
+type observingTracker struct {
+	*database.JobStatusStore
+	states   []string
+	progress []int
+}
+
+func (o *observingTracker) JobRunning(ctx context.Context, jobID string, total int) error {
+	o.states = append(o.states, database.JobStateRunning)
+	return o.JobStatusStore.JobRunning(ctx, jobID, total)
+}
+
+func (o *observingTracker) JobProgress(ctx context.Context, jobID string, processed, total int) error {
+	// A progress update must be visible to readers immediately
+	if err := o.JobStatusStore.JobProgress(ctx, jobID, processed, total); err != nil {
+		return err
+	}
+	job, err := o.GetJob(ctx, jobID)
+	if err != nil || job == nil {
+		return err
+	}
+	o.states = append(o.states, job.State)
+	o.progress = append(o.progress, job.Processed)
+	return nil
+}
+
+func (o *observingTracker) JobFinished(ctx context.Context, jobID string, jobErr error) error {
+	o.states = append(o.states, "finished")
+	return o.JobStatusStore.JobFinished(ctx, jobID, jobErr)
+}
+
+func TestRechunker_TracksJobStatusFromRunningToDone(t *testing.T) {
+	db := newRechunkTestDB(t)
+	vdb := &recordingVectorDB{points: make(map[string]map[string]string)}
+	chunker := processor.NewChunkerWithSettings(300, 50)
+	seedDocument(t, db, vdb, chunker, "org-a", "handbook.pdf", testDocumentText())
+	seedDocument(t, db, vdb, chunker, "org-a", "policy.pdf", testDocumentText())
+
+	store, err := database.NewJobStatusStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create job status store: %v", err)
+	}
+	tracker := &observingTracker{JobStatusStore: store}
+
+	rechunker := NewRechunker(db, vdb, embeddings.NewMockEmbedder(8), chunker)
+	rechunker.SetStatusTracker(tracker)
+
+	ctx := context.Background()
+	payload, _ := json.Marshal(RechunkPayload{JobID: "job-1", OrganizationID: "org-a", RequestedBy: "admin"})
+	if err := rechunker.Handle(ctx, queue.Job{Type: JobTypeRechunkOrganization, Payload: payload}); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	expectedStates := []string{database.JobStateRunning, database.JobStateRunning, database.JobStateRunning, "finished"}
+	if len(tracker.states) != len(expectedStates) {
+		t.Fatalf("Expected states %v, got %v", expectedStates, tracker.states)
+	}
+	for i := range expectedStates {
+		if tracker.states[i] != expectedStates[i] {
+			t.Fatalf("Expected states %v, got %v", expectedStates, tracker.states)
+		}
+	}
+	if len(tracker.progress) != 2 || tracker.progress[0] != 1 || tracker.progress[1] != 2 {
+		t.Errorf("Expected progress 1 then 2, got %v", tracker.progress)
+	}
+
+	job, err := store.GetJob(ctx, "job-1")
+	if err != nil || job == nil {
+		t.Fatalf("Expected the job to be recorded, got %v, %v", job, err)
+	}
+	if job.State != database.JobStateDone || job.Processed != 2 || job.Total != 2 || job.Error != "" {
+		t.Errorf("Expected done with 2/2 processed, got %+v", job)
+	}
+	if job.Type != JobTypeRechunkOrganization || job.OrganizationID != "org-a" || job.StartedAt == nil || job.FinishedAt == nil {
+		t.Errorf("Unexpected job details %+v", job)
+	}
+
+	active, err := store.ListActiveJobs(ctx, "org-a")
+	if err != nil || len(active) != 0 {
+		t.Errorf("Expected no active jobs after completion, got %v, %v", active, err)
+	}
+}
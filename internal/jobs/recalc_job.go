@@ -5,6 +5,7 @@ package jobs
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"time"
 
@@ -36,6 +37,7 @@ func NewRecalcIssuePriorityJob(payload RecalcIssuePriorityPayload) (queue.Job, e
 		Type:      JobTypeRecalcIssuePriority,
 		Payload:   payloadJSON,
 		CreatedAt: time.Now(),
+		Key:       fmt.Sprintf("issue:%d", payload.IssueID), // Serialize recalcs for the same issue
 	}
 
 	log.Printf("NewRecalcIssuePriorityJob: created job type=%s createdAt=%s", job.Type, job.CreatedAt.Format(time.RFC3339))
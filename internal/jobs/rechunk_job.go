@@ -0,0 +1,453 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/the-hive/internal/docid"
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/processor"
+	"github.com/the-hive/internal/queue"
+	"github.com/the-hive/internal/vectordb"
+)
+
+const JobTypeRechunkOrganization = "rechunk_organization"
+
+// minChunkOverlapMatch is the shortest suffix/prefix match treated as chunk overlap when stitching text back together
+const minChunkOverlapMatch = 20
+
+// RechunkPayload represents the payload for a rechunk organization job.
+type RechunkPayload struct {
+	JobID          string    `json:"jobId"`
+	OrganizationID string    `json:"organizationId"`
+	RequestedBy    string    `json:"requestedBy"`
+	RequestedAt    time.Time `json:"requestedAt"`
+}
+
+// RechunkProgress reports the state of a rechunk job.
+type RechunkProgress struct {
+	JobID              string     `json:"job_id"`
+	OrganizationID     string     `json:"organization_id"`
+	Status             string     `json:"status"` // queued, running, completed, failed
+	TotalDocuments     int        `json:"total_documents"`
+	ProcessedDocuments int        `json:"processed_documents"`
+	FailedDocuments    int        `json:"failed_documents"`
+	OldChunks          int        `json:"old_chunks"`
+	NewChunks          int        `json:"new_chunks"`
+	Error              string     `json:"error,omitempty"`
+	StartedAt          *time.Time `json:"started_at,omitempty"`
+	FinishedAt         *time.Time `json:"finished_at,omitempty"`
+}
+
+// Rechunker rebuilds an organization's chunks with the current chunker settings.
+// Unlike a reindex (which re-embeds existing chunks), this reconstructs each document's
+// text from SQLite, splits it again, and replaces both the SQLite rows and the Qdrant points.
+type Rechunker struct {
+	db       *sql.DB
+	vectorDB vectordb.VectorDB
+	embedder embeddings.Embedder
+	chunker  *processor.Chunker
+	status   StatusTracker
+	onChange func(organizationID string) // Called after an organization's chunks were rebuilt
+
+	mu       sync.RWMutex
+	progress map[string]*RechunkProgress
+}
+
+// NewRechunker creates a new rechunker.
+func NewRechunker(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder, chunker *processor.Chunker) *Rechunker {
+	return &Rechunker{
+		db:       db,
+		vectorDB: vectorDB,
+		embedder: embedder,
+		chunker:  chunker,
+		progress: make(map[string]*RechunkProgress),
+	}
+}
+
+// SetStatusTracker sets where job state and progress are recorded for the admin jobs API
+func (r *Rechunker) SetStatusTracker(tracker StatusTracker) {
+	r.status = tracker
+}
+
+// SetChangeHook sets a function called after a rechunk job has rebuilt an organization's chunks,
+// e.g. to invalidate caches derived from them
+func (r *Rechunker) SetChangeHook(fn func(organizationID string)) {
+	r.onChange = fn
+}
+
+// Enqueue records a queued rechunk job for the organization and adds it to the queue.
+func (r *Rechunker) Enqueue(ctx context.Context, q queue.Queue, organizationID, requestedBy string) (*RechunkProgress, error) {
+	payload := RechunkPayload{
+		JobID:          uuid.New().String(),
+		OrganizationID: organizationID,
+		RequestedBy:    requestedBy,
+		RequestedAt:    time.Now(),
+	}
+	log.Printf("Rechunker.Enqueue: jobId=%s organizationId=%s requestedBy=%s", payload.JobID, payload.OrganizationID, payload.RequestedBy)
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	r.setProgress(&RechunkProgress{
+		JobID:          payload.JobID,
+		OrganizationID: organizationID,
+		Status:         "queued",
+	})
+
+	trackJob(ctx, r.status, payload.JobID, func(ctx context.Context, t StatusTracker) error {
+		return t.JobQueued(ctx, payload.JobID, JobTypeRechunkOrganization, organizationID, requestedBy)
+	})
+
+	job := queue.Job{
+		Type:      JobTypeRechunkOrganization,
+		Payload:   payloadJSON,
+		CreatedAt: payload.RequestedAt,
+		Key:       "org:" + organizationID, // Never re-chunk the same organization concurrently
+	}
+	if err := q.Enqueue(ctx, job); err != nil {
+		r.update(payload.JobID, func(p *RechunkProgress) {
+			p.Status = "failed"
+			p.Error = err.Error()
+		})
+		trackJob(ctx, r.status, payload.JobID, func(ctx context.Context, t StatusTracker) error {
+			return t.JobFinished(ctx, payload.JobID, err)
+		})
+		return nil, err
+	}
+
+	return r.Progress(payload.JobID), nil
+}
+
+// Progress returns a snapshot of the job's progress, or nil if the job is unknown.
+func (r *Rechunker) Progress(jobID string) *RechunkProgress {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.progress[jobID]
+	if !ok {
+		return nil
+	}
+	snapshot := *p
+	return &snapshot
+}
+
+// Handle processes a rechunk organization job.
+func (r *Rechunker) Handle(ctx context.Context, job queue.Job) error {
+	if job.Type != JobTypeRechunkOrganization {
+		log.Printf("Rechunker.Handle: unexpected job type %s, expected %s", job.Type, JobTypeRechunkOrganization)
+		return nil
+	}
+
+	var payload RechunkPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		log.Printf("Rechunker.Handle: failed to unmarshal payload: %v", err)
+		return err
+	}
+
+	// Jobs enqueued by another server instance won't have a progress entry yet
+	if r.Progress(payload.JobID) == nil {
+		r.setProgress(&RechunkProgress{JobID: payload.JobID, OrganizationID: payload.OrganizationID})
+	}
+
+	trackJob(ctx, r.status, payload.JobID, func(ctx context.Context, t StatusTracker) error {
+		return t.JobQueued(ctx, payload.JobID, JobTypeRechunkOrganization, payload.OrganizationID, payload.RequestedBy)
+	})
+
+	err := r.RechunkOrganization(ctx, payload.JobID, payload.OrganizationID)
+	if r.onChange != nil {
+		r.onChange(payload.OrganizationID)
+	}
+	now := time.Now()
+	r.update(payload.JobID, func(p *RechunkProgress) {
+		p.FinishedAt = &now
+		if err != nil {
+			p.Status = "failed"
+			p.Error = err.Error()
+		} else {
+			p.Status = "completed"
+		}
+	})
+	trackJob(ctx, r.status, payload.JobID, func(ctx context.Context, t StatusTracker) error {
+		return t.JobFinished(ctx, payload.JobID, err)
+	})
+	return err
+}
+
+// RechunkOrganization re-chunks every document belonging to the organization, updating progress under jobID.
+func (r *Rechunker) RechunkOrganization(ctx context.Context, jobID, organizationID string) error {
+	documentIDs, err := listOrganizationDocuments(ctx, r.db, organizationID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	r.update(jobID, func(p *RechunkProgress) {
+		p.Status = "running"
+		p.TotalDocuments = len(documentIDs)
+		p.StartedAt = &now
+	})
+	trackJob(ctx, r.status, jobID, func(ctx context.Context, t StatusTracker) error {
+		return t.JobRunning(ctx, jobID, len(documentIDs))
+	})
+
+	chunkSize, chunkOverlap := r.chunker.Settings()
+	log.Printf("Rechunker: re-chunking %d documents for organization %s (chunk size %d, overlap %d)", len(documentIDs), organizationID, chunkSize, chunkOverlap)
+
+	for i, documentID := range documentIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		oldCount, newCount, err := r.RechunkDocument(ctx, organizationID, documentID)
+		r.update(jobID, func(p *RechunkProgress) {
+			p.ProcessedDocuments++
+			if err != nil {
+				p.FailedDocuments++
+				return
+			}
+			p.OldChunks += oldCount
+			p.NewChunks += newCount
+		})
+		if err != nil {
+			log.Printf("Rechunker: failed to re-chunk document %s: %v", documentID, err)
+		}
+		trackJob(ctx, r.status, jobID, func(ctx context.Context, t StatusTracker) error {
+			return t.JobProgress(ctx, jobID, i+1, len(documentIDs))
+		})
+	}
+
+	return nil
+}
+
+// rechunkPerChunkKeys are payload keys that describe one chunk rather than the document, so they
+// are not carried over from the old points
+var rechunkPerChunkKeys = []string{"content", "chunk_index", "total_chunks", embeddings.TruncatedMetadataKey}
+
+// RechunkDocument rebuilds a single document's chunks and returns the old and new chunk counts.
+// New chunks are embedded and stored before anything is deleted so a failure leaves the document
+// intact. The document-level payload (filename, tags, language, ...) is copied from its first old point.
+func (r *Rechunker) RechunkDocument(ctx context.Context, organizationID, documentID string) (int, int, error) {
+	oldIDs, contents, err := loadDocumentChunks(ctx, r.db, organizationID, documentID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(oldIDs) == 0 {
+		return 0, 0, nil
+	}
+
+	documentPayload := map[string]string{}
+	if reader, ok := r.vectorDB.(vectordb.PayloadReader); ok {
+		payload, err := reader.GetPayload(ctx, oldIDs[0])
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to read payload of chunk %s: %w", oldIDs[0], err)
+		}
+		for key, value := range payload {
+			documentPayload[key] = value
+		}
+		for _, key := range rechunkPerChunkKeys {
+			delete(documentPayload, key)
+		}
+	}
+
+	chunks, err := r.chunker.ChunkText(mergeChunks(contents))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to chunk text: %w", err)
+	}
+
+	vectors := make([][]float32, len(chunks))
+	truncated := make([]bool, len(chunks))
+	for i, chunk := range chunks {
+		var embedInput string
+		embedInput, truncated[i] = embeddings.TruncateInput(chunk)
+		vector, err := r.embedder.EmbedText(ctx, embedInput)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to embed chunk %d: %w", i, err)
+		}
+		vectors[i] = vector
+	}
+
+	oldIDSet := make(map[string]bool, len(oldIDs))
+	for _, id := range oldIDs {
+		oldIDSet[id] = true
+	}
+
+	// Store the new points; on failure drop the ones added so far and keep the old chunks
+	newIDs := make([]string, len(chunks))
+	newIDSet := make(map[string]bool, len(chunks))
+	for i, chunk := range chunks {
+		pointID := docid.PointID(documentID, i)
+		newIDs[i] = pointID
+		newIDSet[pointID] = true
+
+		metadata := make(map[string]string, len(documentPayload)+6)
+		for key, value := range documentPayload {
+			metadata[key] = value
+		}
+		metadata["document_id"] = documentID
+		metadata["organization_id"] = organizationID
+		metadata["chunk_index"] = fmt.Sprintf("%d", i)
+		metadata["total_chunks"] = fmt.Sprintf("%d", len(chunks))
+		metadata["content"] = chunk
+		if truncated[i] {
+			metadata[embeddings.TruncatedMetadataKey] = "true"
+		}
+		if err := r.vectorDB.Upsert(ctx, pointID, vectors[i], metadata); err != nil {
+			r.deleteNewPoints(ctx, newIDs[:i], oldIDSet)
+			return len(oldIDs), 0, fmt.Errorf("failed to upsert chunk %d: %w", i, err)
+		}
+	}
+
+	// Swap the rows in one transaction so the document never has a partial set
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.deleteNewPoints(ctx, newIDs, oldIDSet)
+		return len(oldIDs), 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM chunks WHERE document_id = ? AND organization_id = ?", documentID, organizationID); err != nil {
+		tx.Rollback()
+		r.deleteNewPoints(ctx, newIDs, oldIDSet)
+		return len(oldIDs), 0, fmt.Errorf("failed to delete old chunks: %w", err)
+	}
+	for i, chunk := range chunks {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT OR REPLACE INTO chunks (id, document_id, content, chunk_index, organization_id) VALUES (?, ?, ?, ?, ?)",
+			newIDs[i], documentID, chunk, i, organizationID,
+		); err != nil {
+			tx.Rollback()
+			r.deleteNewPoints(ctx, newIDs, oldIDSet)
+			return len(oldIDs), 0, fmt.Errorf("failed to store chunk %d: %w", i, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		r.deleteNewPoints(ctx, newIDs, oldIDSet)
+		return len(oldIDs), 0, fmt.Errorf("failed to commit chunks: %w", err)
+	}
+
+	// Only now remove the old points that the new ones didn't overwrite
+	for _, id := range oldIDs {
+		if newIDSet[id] {
+			continue
+		}
+		if err := r.vectorDB.Delete(ctx, id); err != nil {
+			log.Printf("Rechunker: failed to delete point %s: %v", id, err)
+		}
+	}
+
+	log.Printf("Rechunker: document %s re-chunked from %d to %d chunks", documentID, len(oldIDs), len(chunks))
+	return len(oldIDs), len(chunks), nil
+}
+
+// deleteNewPoints removes points written by a failed re-chunk, leaving any that share an old point's ID
+func (r *Rechunker) deleteNewPoints(ctx context.Context, ids []string, oldIDs map[string]bool) {
+	for _, id := range ids {
+		if oldIDs[id] {
+			continue
+		}
+		if err := r.vectorDB.Delete(ctx, id); err != nil {
+			log.Printf("Rechunker: failed to delete point %s: %v", id, err)
+		}
+	}
+}
+
+// listOrganizationDocuments returns the IDs of all documents with chunks in the organization
+func listOrganizationDocuments(ctx context.Context, db *sql.DB, organizationID string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, "SELECT DISTINCT document_id FROM chunks WHERE organization_id = ? ORDER BY document_id", organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	defer rows.Close()
+
+	var documentIDs []string
+	for rows.Next() {
+		var documentID string
+		if err := rows.Scan(&documentID); err != nil {
+			return nil, fmt.Errorf("failed to scan document ID: %w", err)
+		}
+		documentIDs = append(documentIDs, documentID)
+	}
+	return documentIDs, rows.Err()
+}
+
+// loadDocumentChunks returns the document's chunk IDs and contents in document order
+func loadDocumentChunks(ctx context.Context, db *sql.DB, organizationID, documentID string) ([]string, []string, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT id, content FROM chunks WHERE document_id = ? AND organization_id = ? ORDER BY chunk_index ASC, rowid ASC",
+		documentID, organizationID,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var ids, contents []string
+	for rows.Next() {
+		var id, content string
+		if err := rows.Scan(&id, &content); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan chunk: %w", err)
+		}
+		ids = append(ids, id)
+		contents = append(contents, content)
+	}
+	return ids, contents, rows.Err()
+}
+
+// mergeChunks stitches ordered chunks back into a single text, dropping the overlap between neighbours
+func mergeChunks(chunks []string) string {
+	var sb strings.Builder
+	for i, chunk := range chunks {
+		if i == 0 {
+			sb.WriteString(chunk)
+			continue
+		}
+
+		merged := sb.String()
+		maxOverlap := len(chunk)
+		if len(merged) < maxOverlap {
+			maxOverlap = len(merged)
+		}
+
+		overlap := 0
+		for k := maxOverlap; k >= minChunkOverlapMatch; k-- {
+			// The previous chunk was trimmed, so ignore whitespace at the end of the overlap
+			prefix := strings.TrimRightFunc(chunk[:k], unicode.IsSpace)
+			if len(prefix) >= minChunkOverlapMatch && strings.HasSuffix(merged, prefix) {
+				overlap = len(prefix)
+				break
+			}
+		}
+
+		if overlap == 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(chunk[overlap:])
+	}
+	return sb.String()
+}
+
+// setProgress stores a progress entry
+func (r *Rechunker) setProgress(p *RechunkProgress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.progress[p.JobID] = p
+}
+
+// update applies fn to the job's progress entry if it exists
+func (r *Rechunker) update(jobID string, fn func(p *RechunkProgress)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p, ok := r.progress[jobID]; ok {
+		fn(p)
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/processor"
+	"github.com/the-hive/internal/queue"
+	"github.com/the-hive/internal/vectordb"
+)
+
+// recordingVectorDB tracks which points exist after upserts and deletes
+type recordingVectorDB struct {
+	vectordb.MockVectorDB
+	points     map[string]map[string]string
+	failAfter  int // Upserts allowed before they start failing; zero for no failures
+	upsertSeen int
+}
+
+func (v *recordingVectorDB) Upsert(ctx context.Context, id string, vector []float32, metadata map[string]string) error {
+	v.upsertSeen++
+	if v.failAfter > 0 && v.upsertSeen > v.failAfter {
+		return fmt.Errorf("upsert failed")
+	}
+	v.points[id] = metadata
+	return nil
+}
+
+func (v *recordingVectorDB) GetPayload(ctx context.Context, id string) (map[string]string, error) {
+	return v.points[id], nil
+}
+
+func (v *recordingVectorDB) Delete(ctx context.Context, id string) error {
+	delete(v.points, id)
+	return nil
+}
+
+func newRechunkTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "rechunk.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`CREATE TABLE chunks (
+		id TEXT PRIMARY KEY,
+		document_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		chunk_index INTEGER NOT NULL,
+		organization_id TEXT
+	)`); err != nil {
+		t.Fatalf("Failed to create chunks table: %v", err)
+	}
+	return db
+}
+
+// seedDocument chunks text with the given chunker and stores it as an ingested document
+func seedDocument(t *testing.T, db *sql.DB, vdb *recordingVectorDB, chunker *processor.Chunker, orgID, docID, text string) int {
+	t.Helper()
+	chunks, err := chunker.ChunkText(text)
+	if err != nil {
+		t.Fatalf("Failed to chunk seed text: %v", err)
+	}
+	for i, chunk := range chunks {
+		id := fmt.Sprintf("%s-old-%d", docID, i)
+		if _, err := db.Exec("INSERT INTO chunks (id, document_id, content, chunk_index, organization_id) VALUES (?, ?, ?, ?, ?)", id, docID, chunk, i, orgID); err != nil {
+			t.Fatalf("Failed to seed chunk: %v", err)
+		}
+		vdb.points[id] = map[string]string{"organization_id": orgID, "document_id": docID, "filename": docID, "language": "en", "content": chunk, "chunk_index": fmt.Sprintf("%d", i)}
+	}
+	return len(chunks)
+}
+
+func testDocumentText() string {
+	var sb strings.Builder
+	for i := 0; i < 60; i++ {
+		fmt.Fprintf(&sb, "Sentence number %d describes part of the employee handbook in some detail. ", i)
+	}
+	return sb.String()
+}
+
+func TestRechunker_ChunkCountsMatchNewSettings(t *testing.T) {
+	db := newRechunkTestDB(t)
+	vdb := &recordingVectorDB{points: make(map[string]map[string]string)}
+	text := testDocumentText()
+
+	oldCount := seedDocument(t, db, vdb, processor.NewChunkerWithSettings(1000, 100), "org-a", "handbook.pdf", text)
+	otherCount := seedDocument(t, db, vdb, processor.NewChunkerWithSettings(1000, 100), "org-b", "other.pdf", text)
+
+	newChunker := processor.NewChunkerWithSettings(300, 50)
+	expected, _ := newChunker.ChunkText(text)
+	if len(expected) <= oldCount {
+		t.Fatalf("Test setup: expected smaller chunks to produce more than %d chunks, got %d", oldCount, len(expected))
+	}
+
+	rechunker := NewRechunker(db, vdb, embeddings.NewMockEmbedder(8), newChunker)
+	payload, _ := json.Marshal(RechunkPayload{JobID: "job-1", OrganizationID: "org-a"})
+	if err := rechunker.Handle(context.Background(), queue.Job{Type: JobTypeRechunkOrganization, Payload: payload}); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	progress := rechunker.Progress("job-1")
+	if progress == nil || progress.Status != "completed" {
+		t.Fatalf("Expected completed progress, got %+v", progress)
+	}
+	if progress.TotalDocuments != 1 || progress.ProcessedDocuments != 1 {
+		t.Errorf("Expected 1/1 documents processed, got %d/%d", progress.ProcessedDocuments, progress.TotalDocuments)
+	}
+	if progress.OldChunks != oldCount || progress.NewChunks != len(expected) {
+		t.Errorf("Expected %d -> %d chunks, got %d -> %d", oldCount, len(expected), progress.OldChunks, progress.NewChunks)
+	}
+
+	var stored int
+	db.QueryRow("SELECT COUNT(*) FROM chunks WHERE document_id = ? AND organization_id = ?", "handbook.pdf", "org-a").Scan(&stored)
+	if stored != len(expected) {
+		t.Errorf("Expected %d chunks in SQLite, got %d", len(expected), stored)
+	}
+
+	orgAPoints := 0
+	for id, metadata := range vdb.points {
+		if metadata["organization_id"] != "org-a" {
+			continue
+		}
+		if strings.Contains(id, "-old-") {
+			t.Errorf("Old point %s was not deleted", id)
+		}
+		orgAPoints++
+	}
+	if orgAPoints != len(expected) {
+		t.Errorf("Expected %d points for org-a, got %d", len(expected), orgAPoints)
+	}
+
+	// Other organizations are untouched
+	db.QueryRow("SELECT COUNT(*) FROM chunks WHERE organization_id = ?", "org-b").Scan(&stored)
+	if stored != otherCount {
+		t.Errorf("Expected org-b to keep %d chunks, got %d", otherCount, stored)
+	}
+}
+
+func TestMergeChunks_RemovesOverlap(t *testing.T) {
+	text := testDocumentText()
+	chunks, _ := processor.NewChunkerWithSettings(400, 80).ChunkText(text)
+
+	merged := mergeChunks(chunks)
+	if merged != strings.TrimSpace(text) {
+		t.Errorf("Expected merged text to match the original\ngot:  %q\nwant: %q", merged, strings.TrimSpace(text))
+	}
+}
+
+func TestRechunker_FlagsTruncatedEmbeddingInput(t *testing.T) {
+	previous := embeddings.MaxInputTokens()
+	embeddings.SetMaxInputTokens(20)
+	t.Cleanup(func() { embeddings.SetMaxInputTokens(previous) })
+
+	db := newRechunkTestDB(t)
+	vdb := &recordingVectorDB{points: make(map[string]map[string]string)}
+	seedDocument(t, db, vdb, processor.NewChunkerWithSettings(1000, 100), "org-a", "handbook.pdf", testDocumentText())
+
+	rechunker := NewRechunker(db, vdb, embeddings.NewMockEmbedder(8), processor.NewChunkerWithSettings(300, 50))
+	if _, _, err := rechunker.RechunkDocument(context.Background(), "org-a", "handbook.pdf"); err != nil {
+		t.Fatalf("RechunkDocument failed: %v", err)
+	}
+
+	flagged := 0
+	for id, metadata := range vdb.points {
+		if strings.Contains(id, "-old-") {
+			continue
+		}
+		if metadata[embeddings.TruncatedMetadataKey] == "true" {
+			flagged++
+		}
+		if embeddings.EstimateTokens(metadata["content"]) > 20 && metadata[embeddings.TruncatedMetadataKey] != "true" {
+			t.Errorf("Expected over-long chunk %s to be flagged as truncated", id)
+		}
+		if len(metadata["content"]) < 200 {
+			t.Errorf("Expected the stored content to stay complete, got %d chars", len(metadata["content"]))
+		}
+	}
+	if flagged == 0 {
+		t.Error("Expected the new chunks to be flagged as truncated")
+	}
+}
+
+func TestRechunker_KeepsDocumentPayload(t *testing.T) {
+	db := newRechunkTestDB(t)
+	vdb := &recordingVectorDB{points: make(map[string]map[string]string)}
+	seedDocument(t, db, vdb, processor.NewChunkerWithSettings(1000, 100), "org-a", "handbook.pdf", testDocumentText())
+	for _, metadata := range vdb.points {
+		metadata["tags"] = `["hr"]`
+	}
+
+	rechunker := NewRechunker(db, vdb, embeddings.NewMockEmbedder(8), processor.NewChunkerWithSettings(300, 50))
+	if _, _, err := rechunker.RechunkDocument(context.Background(), "org-a", "handbook.pdf"); err != nil {
+		t.Fatalf("RechunkDocument failed: %v", err)
+	}
+
+	for id, metadata := range vdb.points {
+		if metadata["filename"] != "handbook.pdf" || metadata["language"] != "en" || metadata["tags"] != `["hr"]` {
+			t.Errorf("Expected point %s to keep the document payload, got %v", id, metadata)
+		}
+	}
+}
+
+func TestRechunker_FailedUpsertKeepsOldChunks(t *testing.T) {
+	db := newRechunkTestDB(t)
+	vdb := &recordingVectorDB{points: make(map[string]map[string]string)}
+	oldCount := seedDocument(t, db, vdb, processor.NewChunkerWithSettings(1000, 100), "org-a", "handbook.pdf", testDocumentText())
+	vdb.failAfter = 2
+
+	rechunker := NewRechunker(db, vdb, embeddings.NewMockEmbedder(8), processor.NewChunkerWithSettings(300, 50))
+	if _, _, err := rechunker.RechunkDocument(context.Background(), "org-a", "handbook.pdf"); err == nil {
+		t.Fatal("Expected the failed upsert to be reported")
+	}
+
+	var stored int
+	db.QueryRow("SELECT COUNT(*) FROM chunks WHERE document_id = ?", "handbook.pdf").Scan(&stored)
+	if stored != oldCount {
+		t.Errorf("Expected the %d old chunk rows to be kept, got %d", oldCount, stored)
+	}
+	if len(vdb.points) != oldCount {
+		t.Errorf("Expected only the %d old points to remain, got %d", oldCount, len(vdb.points))
+	}
+	for id := range vdb.points {
+		if !strings.Contains(id, "-old-") {
+			t.Errorf("Expected the partially written point %s to be removed", id)
+		}
+	}
+}
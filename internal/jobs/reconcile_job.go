@@ -0,0 +1,425 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/queue"
+	"github.com/the-hive/internal/vectordb"
+)
+
+const JobTypeReconcileOrganization = "reconcile_organization"
+
+// ReconcilePayload represents the payload for a reconcile organization job.
+type ReconcilePayload struct {
+	JobID          string    `json:"jobId"`
+	OrganizationID string    `json:"organizationId"`
+	DryRun         bool      `json:"dryRun"`
+	RequestedBy    string    `json:"requestedBy"`
+	RequestedAt    time.Time `json:"requestedAt"`
+}
+
+// ReconcileReport reports the state of a reconcile job and the mismatches it found and repaired.
+type ReconcileReport struct {
+	JobID             string     `json:"job_id"`
+	OrganizationID    string     `json:"organization_id"`
+	DryRun            bool       `json:"dry_run"`             // Report mismatches without repairing them
+	Status            string     `json:"status"`              // queued, running, completed, failed
+	Chunks            int        `json:"chunks"`              // SQLite chunk rows
+	Points            int        `json:"points"`              // Vector points
+	MissingVectors    int        `json:"missing_vectors"`     // Rows without a point
+	ReembeddedVectors int        `json:"reembedded_vectors"`  // Missing points rebuilt from the row's content
+	OrphanedPoints    int        `json:"orphaned_points"`     // Points without a row
+	PayloadOnlyPoints int        `json:"payload_only_points"` // Points without a row that carry their own content (HTTP ingest); kept
+	DeletedPoints     int        `json:"deleted_points"`
+	OrphanedRows      int        `json:"orphaned_rows"` // Rows without a point or content to rebuild it from
+	DeletedRows       int        `json:"deleted_rows"`
+	FailedRepairs     int        `json:"failed_repairs"`
+	Error             string     `json:"error,omitempty"`
+	StartedAt         *time.Time `json:"started_at,omitempty"`
+	FinishedAt        *time.Time `json:"finished_at,omitempty"`
+}
+
+// reconcileChunk is a SQLite chunk row
+type reconcileChunk struct {
+	id         string
+	documentID string
+	content    string
+	chunkIndex int
+}
+
+// Reconciler repairs mismatches between an organization's SQLite chunks and its vector points left
+// by partial failures: rows without a point are re-embedded (search can't find them), points without
+// a row are deleted (search finds them but can't load their content), and rows with neither a point
+// nor content are deleted.
+type Reconciler struct {
+	db       *sql.DB
+	vectorDB vectordb.VectorDB
+	embedder embeddings.Embedder
+	status   StatusTracker
+	onChange func(organizationID string) // Called after an organization's chunks or points were repaired
+
+	mu      sync.RWMutex
+	reports map[string]*ReconcileReport
+}
+
+// NewReconciler creates a new reconciler.
+func NewReconciler(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.Embedder) *Reconciler {
+	return &Reconciler{
+		db:       db,
+		vectorDB: vectorDB,
+		embedder: embedder,
+		reports:  make(map[string]*ReconcileReport),
+	}
+}
+
+// SetStatusTracker sets where job state and progress are recorded for the admin jobs API
+func (r *Reconciler) SetStatusTracker(tracker StatusTracker) {
+	r.status = tracker
+}
+
+// SetChangeHook sets a function called after a reconcile job repaired an organization's chunks,
+// e.g. to invalidate caches derived from them
+func (r *Reconciler) SetChangeHook(fn func(organizationID string)) {
+	r.onChange = fn
+}
+
+// Start enqueues a reconcile job for every organization with chunks on each tick until the context
+// is cancelled. Jobs share the rechunk and retag key, so they never run alongside them.
+func (r *Reconciler) Start(ctx context.Context, q queue.Queue, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				organizationIDs, err := listChunkOrganizations(ctx, r.db)
+				if err != nil {
+					log.Printf("[RECONCILE] Failed to list organizations: %v", err)
+					continue
+				}
+				for _, organizationID := range organizationIDs {
+					if _, err := r.Enqueue(ctx, q, organizationID, "scheduler", false); err != nil {
+						log.Printf("[RECONCILE] Failed to enqueue reconcile job for org %s: %v", organizationID, err)
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Enqueue records a queued reconcile job for the organization and adds it to the queue.
+func (r *Reconciler) Enqueue(ctx context.Context, q queue.Queue, organizationID, requestedBy string, dryRun bool) (*ReconcileReport, error) {
+	payload := ReconcilePayload{
+		JobID:          uuid.New().String(),
+		OrganizationID: organizationID,
+		DryRun:         dryRun,
+		RequestedBy:    requestedBy,
+		RequestedAt:    time.Now(),
+	}
+	log.Printf("Reconciler.Enqueue: jobId=%s organizationId=%s requestedBy=%s dryRun=%v", payload.JobID, payload.OrganizationID, payload.RequestedBy, payload.DryRun)
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	r.setReport(&ReconcileReport{
+		JobID:          payload.JobID,
+		OrganizationID: organizationID,
+		DryRun:         dryRun,
+		Status:         "queued",
+	})
+
+	trackJob(ctx, r.status, payload.JobID, func(ctx context.Context, t StatusTracker) error {
+		return t.JobQueued(ctx, payload.JobID, JobTypeReconcileOrganization, organizationID, requestedBy)
+	})
+
+	job := queue.Job{
+		Type:      JobTypeReconcileOrganization,
+		Payload:   payloadJSON,
+		CreatedAt: payload.RequestedAt,
+		Key:       "org:" + organizationID, // A rechunk replaces rows and points, which would look like mismatches
+	}
+	if err := q.Enqueue(ctx, job); err != nil {
+		r.update(payload.JobID, func(p *ReconcileReport) {
+			p.Status = "failed"
+			p.Error = err.Error()
+		})
+		trackJob(ctx, r.status, payload.JobID, func(ctx context.Context, t StatusTracker) error {
+			return t.JobFinished(ctx, payload.JobID, err)
+		})
+		return nil, err
+	}
+
+	return r.Report(payload.JobID), nil
+}
+
+// Report returns a snapshot of the job's report, or nil if the job is unknown.
+func (r *Reconciler) Report(jobID string) *ReconcileReport {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.reports[jobID]
+	if !ok {
+		return nil
+	}
+	snapshot := *p
+	return &snapshot
+}
+
+// Handle processes a reconcile organization job.
+func (r *Reconciler) Handle(ctx context.Context, job queue.Job) error {
+	if job.Type != JobTypeReconcileOrganization {
+		log.Printf("Reconciler.Handle: unexpected job type %s, expected %s", job.Type, JobTypeReconcileOrganization)
+		return nil
+	}
+
+	var payload ReconcilePayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		log.Printf("Reconciler.Handle: failed to unmarshal payload: %v", err)
+		return err
+	}
+
+	// Jobs enqueued by another server instance won't have a report entry yet
+	if r.Report(payload.JobID) == nil {
+		r.setReport(&ReconcileReport{JobID: payload.JobID, OrganizationID: payload.OrganizationID, DryRun: payload.DryRun})
+	}
+
+	trackJob(ctx, r.status, payload.JobID, func(ctx context.Context, t StatusTracker) error {
+		return t.JobQueued(ctx, payload.JobID, JobTypeReconcileOrganization, payload.OrganizationID, payload.RequestedBy)
+	})
+
+	err := r.ReconcileOrganization(ctx, payload.JobID, payload.OrganizationID, payload.DryRun)
+	report := r.Report(payload.JobID)
+	if r.onChange != nil && report != nil && report.ReembeddedVectors+report.DeletedPoints+report.DeletedRows > 0 {
+		r.onChange(payload.OrganizationID)
+	}
+	now := time.Now()
+	r.update(payload.JobID, func(p *ReconcileReport) {
+		p.FinishedAt = &now
+		if err != nil {
+			p.Status = "failed"
+			p.Error = err.Error()
+		} else {
+			p.Status = "completed"
+		}
+	})
+	trackJob(ctx, r.status, payload.JobID, func(ctx context.Context, t StatusTracker) error {
+		return t.JobFinished(ctx, payload.JobID, err)
+	})
+	return err
+}
+
+// ReconcileOrganization compares the organization's chunk rows with its vector points and repairs
+// the difference (unless dryRun), recording the outcome in the report under jobID.
+func (r *Reconciler) ReconcileOrganization(ctx context.Context, jobID, organizationID string, dryRun bool) error {
+	lister, ok := r.vectorDB.(vectordb.PointLister)
+	if !ok {
+		return errors.New("vector database can't list points")
+	}
+
+	chunks, err := loadOrganizationChunks(ctx, r.db, organizationID)
+	if err != nil {
+		return err
+	}
+	pointIDs, err := lister.ListPointIDs(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	points := make(map[string]bool, len(pointIDs))
+	for _, id := range pointIDs {
+		points[id] = true
+	}
+	rows := make(map[string]bool, len(chunks))
+	documentChunks := make(map[string]int)
+	var missing, orphanedRows []reconcileChunk
+	for _, chunk := range chunks {
+		rows[chunk.id] = true
+		documentChunks[chunk.documentID]++
+		if points[chunk.id] {
+			continue
+		}
+		if chunk.content == "" {
+			orphanedRows = append(orphanedRows, chunk)
+		} else {
+			missing = append(missing, chunk)
+		}
+	}
+	// HTTP ingest stores chunks only as points, with their content in the payload, so a point without
+	// a row is only orphaned when it has no content either. Without a way to read payloads no point
+	// can be told apart, so none is deleted.
+	var orphanedPoints []string
+	payloadOnly := 0
+	reader, canReadPayloads := r.vectorDB.(vectordb.PayloadReader)
+	for _, id := range pointIDs {
+		if rows[id] {
+			continue
+		}
+		if !canReadPayloads {
+			payloadOnly++
+			continue
+		}
+		payload, err := reader.GetPayload(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to read point %s: %w", id, err)
+		}
+		if payload["content"] != "" {
+			payloadOnly++
+			continue
+		}
+		orphanedPoints = append(orphanedPoints, id)
+	}
+
+	now := time.Now()
+	r.update(jobID, func(p *ReconcileReport) {
+		p.Status = "running"
+		p.StartedAt = &now
+		p.Chunks = len(chunks)
+		p.Points = len(pointIDs)
+		p.MissingVectors = len(missing)
+		p.OrphanedPoints = len(orphanedPoints)
+		p.PayloadOnlyPoints = payloadOnly
+		p.OrphanedRows = len(orphanedRows)
+	})
+	total := len(missing) + len(orphanedPoints) + len(orphanedRows)
+	trackJob(ctx, r.status, jobID, func(ctx context.Context, t StatusTracker) error {
+		return t.JobRunning(ctx, jobID, total)
+	})
+	log.Printf("Reconciler: org %s has %d chunks and %d points; %d missing vectors, %d orphaned points, %d orphaned rows (dry run: %v)",
+		organizationID, len(chunks), len(pointIDs), len(missing), len(orphanedPoints), len(orphanedRows), dryRun)
+	if dryRun || total == 0 {
+		return nil
+	}
+
+	processed := 0
+	repaired := func(fn func(p *ReconcileReport), err error, what string) {
+		processed++
+		r.update(jobID, func(p *ReconcileReport) {
+			if err != nil {
+				p.FailedRepairs++
+				return
+			}
+			fn(p)
+		})
+		if err != nil {
+			log.Printf("Reconciler: failed to repair %s: %v", what, err)
+		}
+		trackJob(ctx, r.status, jobID, func(ctx context.Context, t StatusTracker) error {
+			return t.JobProgress(ctx, jobID, processed, total)
+		})
+	}
+
+	for _, chunk := range missing {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := r.reembed(ctx, organizationID, chunk, documentChunks[chunk.documentID])
+		repaired(func(p *ReconcileReport) { p.ReembeddedVectors++ }, err, "missing vector "+chunk.id)
+	}
+	for _, id := range orphanedPoints {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := r.vectorDB.Delete(ctx, id)
+		repaired(func(p *ReconcileReport) { p.DeletedPoints++ }, err, "orphaned point "+id)
+	}
+	for _, chunk := range orphanedRows {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		_, err := r.db.ExecContext(ctx, "DELETE FROM chunks WHERE id = ? AND organization_id = ?", chunk.id, organizationID)
+		repaired(func(p *ReconcileReport) { p.DeletedRows++ }, err, "orphaned row "+chunk.id)
+	}
+	return nil
+}
+
+// reembed rebuilds a chunk's missing point from its SQLite row
+func (r *Reconciler) reembed(ctx context.Context, organizationID string, chunk reconcileChunk, totalChunks int) error {
+	embedInput, truncated := embeddings.TruncateInput(chunk.content)
+	vector, err := r.embedder.EmbedText(ctx, embedInput)
+	if err != nil {
+		return fmt.Errorf("failed to embed chunk: %w", err)
+	}
+
+	metadata := map[string]string{
+		"document_id":     chunk.documentID,
+		"organization_id": organizationID,
+		"chunk_index":     fmt.Sprintf("%d", chunk.chunkIndex),
+		"total_chunks":    fmt.Sprintf("%d", totalChunks),
+		"content":         chunk.content,
+	}
+	if truncated {
+		metadata[embeddings.TruncatedMetadataKey] = "true"
+	}
+	return r.vectorDB.Upsert(ctx, chunk.id, vector, metadata)
+}
+
+// loadOrganizationChunks returns every chunk row of the organization
+func loadOrganizationChunks(ctx context.Context, db *sql.DB, organizationID string) ([]reconcileChunk, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT id, document_id, content, chunk_index FROM chunks WHERE organization_id = ? ORDER BY document_id, chunk_index",
+		organizationID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var chunks []reconcileChunk
+	for rows.Next() {
+		var chunk reconcileChunk
+		if err := rows.Scan(&chunk.id, &chunk.documentID, &chunk.content, &chunk.chunkIndex); err != nil {
+			return nil, fmt.Errorf("failed to scan chunk: %w", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, rows.Err()
+}
+
+// listChunkOrganizations returns the IDs of all organizations with chunks
+func listChunkOrganizations(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, "SELECT DISTINCT organization_id FROM chunks WHERE organization_id IS NOT NULL AND organization_id != '' ORDER BY organization_id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	defer rows.Close()
+
+	var organizationIDs []string
+	for rows.Next() {
+		var organizationID string
+		if err := rows.Scan(&organizationID); err != nil {
+			return nil, fmt.Errorf("failed to scan organization ID: %w", err)
+		}
+		organizationIDs = append(organizationIDs, organizationID)
+	}
+	return organizationIDs, rows.Err()
+}
+
+// setReport stores a report entry
+func (r *Reconciler) setReport(p *ReconcileReport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports[p.JobID] = p
+}
+
+// update applies fn to the job's report entry if it exists
+func (r *Reconciler) update(jobID string, fn func(p *ReconcileReport)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p, ok := r.reports[jobID]; ok {
+		fn(p)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/queue"
+	"github.com/the-hive/internal/vectordb"
+)
+
+func TestReconciler_RepairsChunkAndPointMismatches(t *testing.T) {
+	db := newRechunkTestDB(t)
+	vdb := vectordb.NewMemoryVectorDB()
+	ctx := context.Background()
+
+	for _, row := range []struct{ id, content string }{
+		{"intact", "Chunk with a vector."},
+		{"lost-vector", "Chunk whose vector upsert failed."},
+		{"empty", ""},
+	} {
+		if _, err := db.Exec("INSERT INTO chunks (id, document_id, content, chunk_index, organization_id) VALUES (?, 'handbook.pdf', ?, 0, 'org-a')", row.id, row.content); err != nil {
+			t.Fatalf("Failed to seed chunk: %v", err)
+		}
+	}
+	vdb.Upsert(ctx, "intact", []float32{1, 0}, map[string]string{"organization_id": "org-a"})
+	vdb.Upsert(ctx, "orphan", []float32{0, 1}, map[string]string{"organization_id": "org-a"})
+	vdb.Upsert(ctx, "other-org", []float32{0, 1}, map[string]string{"organization_id": "org-b"})
+
+	reconciler := NewReconciler(db, vdb, embeddings.NewMockEmbedder(2))
+	changed := ""
+	reconciler.SetChangeHook(func(organizationID string) { changed = organizationID })
+	run := func(jobID string, dryRun bool) *ReconcileReport {
+		t.Helper()
+		payload, _ := json.Marshal(ReconcilePayload{JobID: jobID, OrganizationID: "org-a", DryRun: dryRun})
+		if err := reconciler.Handle(ctx, queue.Job{Type: JobTypeReconcileOrganization, Payload: payload}); err != nil {
+			t.Fatalf("Handle failed: %v", err)
+		}
+		return reconciler.Report(jobID)
+	}
+
+	// A dry run reports the mismatches without touching anything
+	report := run("dry", true)
+	if report.Status != "completed" || report.Chunks != 3 || report.Points != 2 ||
+		report.MissingVectors != 1 || report.OrphanedPoints != 1 || report.OrphanedRows != 1 {
+		t.Fatalf("Unexpected dry run report %+v", report)
+	}
+	if report.ReembeddedVectors+report.DeletedPoints+report.DeletedRows != 0 || changed != "" {
+		t.Errorf("Expected a dry run to repair nothing, got %+v", report)
+	}
+	if ids, _ := vdb.ListPointIDs(ctx, "org-a"); len(ids) != 2 {
+		t.Errorf("Expected the dry run to leave the points alone, got %v", ids)
+	}
+
+	report = run("repair", false)
+	if report.ReembeddedVectors != 1 || report.DeletedPoints != 1 || report.DeletedRows != 1 || report.FailedRepairs != 0 {
+		t.Errorf("Expected every mismatch to be repaired, got %+v", report)
+	}
+	if changed != "org-a" {
+		t.Errorf("Expected the change hook to run for org-a, got %q", changed)
+	}
+
+	ids, _ := vdb.ListPointIDs(ctx, "org-a")
+	if len(ids) != 2 || ids[0] != "intact" || ids[1] != "lost-vector" {
+		t.Errorf("Expected points for exactly the chunk rows, got %v", ids)
+	}
+	matches, _ := vdb.Search(ctx, []float32{1, 0}, 10, "org-a")
+	for _, match := range matches {
+		if match.ID == "lost-vector" && (match.Metadata["content"] != "Chunk whose vector upsert failed." || match.DocumentID != "handbook.pdf") {
+			t.Errorf("Expected the rebuilt point to carry the chunk's content and document, got %+v", match)
+		}
+	}
+	var empty int
+	db.QueryRow("SELECT COUNT(*) FROM chunks WHERE id = 'empty'").Scan(&empty)
+	if empty != 0 {
+		t.Error("Expected the empty chunk without a vector to be deleted")
+	}
+	if ids, _ := vdb.ListPointIDs(ctx, "org-b"); len(ids) != 1 {
+		t.Errorf("Expected another organization's points to be left alone, got %v", ids)
+	}
+
+	// A second run finds nothing left to repair
+	report = run("again", false)
+	if report.MissingVectors+report.OrphanedPoints+report.OrphanedRows != 0 {
+		t.Errorf("Expected a consistent organization after repair, got %+v", report)
+	}
+}
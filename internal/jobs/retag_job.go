@@ -0,0 +1,333 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/the-hive/internal/queue"
+	"github.com/the-hive/internal/vectordb"
+)
+
+const JobTypeRetagOrganization = "retag_organization"
+
+// retagEventInterval is how many documents are processed between progress events
+const retagEventInterval = 25
+
+// Tagger generates tags for document content using the current tag vocabulary
+type Tagger interface {
+	Tag(content string) ([]string, error)
+}
+
+// RetagEventStore records re-tagging progress events
+type RetagEventStore interface {
+	AddEvent(ctx context.Context, event interface{}) error
+}
+
+// RetagPayload represents the payload for a retag organization job.
+type RetagPayload struct {
+	JobID          string    `json:"jobId"`
+	OrganizationID string    `json:"organizationId"`
+	UntaggedOnly   bool      `json:"untaggedOnly"`
+	RequestedBy    string    `json:"requestedBy"`
+	RequestedAt    time.Time `json:"requestedAt"`
+}
+
+// RetagProgress reports the state of a retag job.
+type RetagProgress struct {
+	JobID              string     `json:"job_id"`
+	OrganizationID     string     `json:"organization_id"`
+	UntaggedOnly       bool       `json:"untagged_only"`
+	Status             string     `json:"status"` // queued, running, completed, failed
+	TotalDocuments     int        `json:"total_documents"`
+	ProcessedDocuments int        `json:"processed_documents"`
+	TaggedDocuments    int        `json:"tagged_documents"`
+	SkippedDocuments   int        `json:"skipped_documents"` // Already tagged (untagged-only runs)
+	FailedDocuments    int        `json:"failed_documents"`
+	Error              string     `json:"error,omitempty"`
+	StartedAt          *time.Time `json:"started_at,omitempty"`
+	FinishedAt         *time.Time `json:"finished_at,omitempty"`
+}
+
+// Retagger re-runs tagging over an organization's documents with the current tag vocabulary.
+// Each document is tagged from its reconstructed text and the tags are written to all of its chunks.
+type Retagger struct {
+	db       *sql.DB
+	vectorDB vectordb.VectorDB
+	tagger   Tagger
+	events   RetagEventStore
+	status   StatusTracker
+
+	mu       sync.RWMutex
+	progress map[string]*RetagProgress
+}
+
+// NewRetagger creates a new retagger.
+func NewRetagger(db *sql.DB, vectorDB vectordb.VectorDB, tagger Tagger) *Retagger {
+	return &Retagger{
+		db:       db,
+		vectorDB: vectorDB,
+		tagger:   tagger,
+		progress: make(map[string]*RetagProgress),
+	}
+}
+
+// SetEventStore sets the store progress events are recorded in
+func (r *Retagger) SetEventStore(events RetagEventStore) {
+	r.events = events
+}
+
+// SetStatusTracker sets where job state and progress are recorded for the admin jobs API
+func (r *Retagger) SetStatusTracker(tracker StatusTracker) {
+	r.status = tracker
+}
+
+// Enqueue records a queued retag job for the organization and adds it to the queue.
+func (r *Retagger) Enqueue(ctx context.Context, q queue.Queue, organizationID, requestedBy string, untaggedOnly bool) (*RetagProgress, error) {
+	payload := RetagPayload{
+		JobID:          uuid.New().String(),
+		OrganizationID: organizationID,
+		UntaggedOnly:   untaggedOnly,
+		RequestedBy:    requestedBy,
+		RequestedAt:    time.Now(),
+	}
+	log.Printf("Retagger.Enqueue: jobId=%s organizationId=%s untaggedOnly=%v requestedBy=%s", payload.JobID, payload.OrganizationID, untaggedOnly, payload.RequestedBy)
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	r.setProgress(&RetagProgress{
+		JobID:          payload.JobID,
+		OrganizationID: organizationID,
+		UntaggedOnly:   untaggedOnly,
+		Status:         "queued",
+	})
+
+	trackJob(ctx, r.status, payload.JobID, func(ctx context.Context, t StatusTracker) error {
+		return t.JobQueued(ctx, payload.JobID, JobTypeRetagOrganization, organizationID, requestedBy)
+	})
+
+	job := queue.Job{
+		Type:      JobTypeRetagOrganization,
+		Payload:   payloadJSON,
+		CreatedAt: payload.RequestedAt,
+		Key:       "org:" + organizationID,
+	}
+	if err := q.Enqueue(ctx, job); err != nil {
+		r.update(payload.JobID, func(p *RetagProgress) {
+			p.Status = "failed"
+			p.Error = err.Error()
+		})
+		trackJob(ctx, r.status, payload.JobID, func(ctx context.Context, t StatusTracker) error {
+			return t.JobFinished(ctx, payload.JobID, err)
+		})
+		return nil, err
+	}
+
+	return r.Progress(payload.JobID), nil
+}
+
+// Progress returns a snapshot of the job's progress, or nil if the job is unknown.
+func (r *Retagger) Progress(jobID string) *RetagProgress {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.progress[jobID]
+	if !ok {
+		return nil
+	}
+	snapshot := *p
+	return &snapshot
+}
+
+// Handle processes a retag organization job.
+func (r *Retagger) Handle(ctx context.Context, job queue.Job) error {
+	if job.Type != JobTypeRetagOrganization {
+		log.Printf("Retagger.Handle: unexpected job type %s, expected %s", job.Type, JobTypeRetagOrganization)
+		return nil
+	}
+
+	var payload RetagPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		log.Printf("Retagger.Handle: failed to unmarshal payload: %v", err)
+		return err
+	}
+
+	// Jobs enqueued by another server instance won't have a progress entry yet
+	if r.Progress(payload.JobID) == nil {
+		r.setProgress(&RetagProgress{JobID: payload.JobID, OrganizationID: payload.OrganizationID, UntaggedOnly: payload.UntaggedOnly})
+	}
+
+	trackJob(ctx, r.status, payload.JobID, func(ctx context.Context, t StatusTracker) error {
+		return t.JobQueued(ctx, payload.JobID, JobTypeRetagOrganization, payload.OrganizationID, payload.RequestedBy)
+	})
+
+	err := r.RetagOrganization(ctx, payload.JobID, payload.OrganizationID, payload.UntaggedOnly)
+	now := time.Now()
+	r.update(payload.JobID, func(p *RetagProgress) {
+		p.FinishedAt = &now
+		if err != nil {
+			p.Status = "failed"
+			p.Error = err.Error()
+		} else {
+			p.Status = "completed"
+		}
+	})
+	trackJob(ctx, r.status, payload.JobID, func(ctx context.Context, t StatusTracker) error {
+		return t.JobFinished(ctx, payload.JobID, err)
+	})
+	r.recordEvent(ctx, payload.JobID)
+	return err
+}
+
+// RetagOrganization re-tags the organization's documents, updating progress under jobID.
+// With untaggedOnly, documents whose first chunk already has tags are skipped.
+func (r *Retagger) RetagOrganization(ctx context.Context, jobID, organizationID string, untaggedOnly bool) error {
+	var reader vectordb.PayloadReader
+	if untaggedOnly {
+		var ok bool
+		if reader, ok = r.vectorDB.(vectordb.PayloadReader); !ok {
+			return fmt.Errorf("untagged-only re-tagging requires a vector database that can read payloads")
+		}
+	}
+
+	documentIDs, err := listOrganizationDocuments(ctx, r.db, organizationID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	r.update(jobID, func(p *RetagProgress) {
+		p.Status = "running"
+		p.TotalDocuments = len(documentIDs)
+		p.StartedAt = &now
+	})
+	trackJob(ctx, r.status, jobID, func(ctx context.Context, t StatusTracker) error {
+		return t.JobRunning(ctx, jobID, len(documentIDs))
+	})
+	r.recordEvent(ctx, jobID)
+	log.Printf("Retagger: re-tagging %d documents for organization %s (untagged only: %v)", len(documentIDs), organizationID, untaggedOnly)
+
+	for i, documentID := range documentIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		tagged, err := r.RetagDocument(ctx, organizationID, documentID, reader)
+		r.update(jobID, func(p *RetagProgress) {
+			p.ProcessedDocuments++
+			switch {
+			case err != nil:
+				p.FailedDocuments++
+			case tagged:
+				p.TaggedDocuments++
+			default:
+				p.SkippedDocuments++
+			}
+		})
+		if err != nil {
+			log.Printf("Retagger: failed to re-tag document %s: %v", documentID, err)
+		}
+		trackJob(ctx, r.status, jobID, func(ctx context.Context, t StatusTracker) error {
+			return t.JobProgress(ctx, jobID, i+1, len(documentIDs))
+		})
+		if (i+1)%retagEventInterval == 0 && i+1 < len(documentIDs) {
+			r.recordEvent(ctx, jobID)
+		}
+	}
+
+	return nil
+}
+
+// RetagDocument tags a single document and writes the tags to all of its chunks.
+// If reader is set, a document whose first chunk already has tags is skipped and false is returned.
+func (r *Retagger) RetagDocument(ctx context.Context, organizationID, documentID string, reader vectordb.PayloadReader) (bool, error) {
+	ids, contents, err := loadDocumentChunks(ctx, r.db, organizationID, documentID)
+	if err != nil {
+		return false, err
+	}
+	if len(ids) == 0 {
+		return false, nil
+	}
+
+	if reader != nil {
+		payload, err := reader.GetPayload(ctx, ids[0])
+		if err != nil {
+			return false, err
+		}
+		if hasTags(payload) {
+			return false, nil
+		}
+	}
+
+	tags, err := r.tagger.Tag(mergeChunks(contents))
+	if err != nil {
+		return false, fmt.Errorf("failed to generate tags: %w", err)
+	}
+
+	for _, id := range ids {
+		if err := r.vectorDB.UpdatePayload(ctx, id, tags); err != nil {
+			return false, fmt.Errorf("failed to update tags for chunk %s: %w", id, err)
+		}
+	}
+	return true, nil
+}
+
+// hasTags reports whether a point payload carries at least one tag
+func hasTags(payload map[string]string) bool {
+	var tags []string
+	if err := json.Unmarshal([]byte(payload["tags"]), &tags); err != nil {
+		return false
+	}
+	return len(tags) > 0
+}
+
+// recordEvent records the job's current progress as an event
+func (r *Retagger) recordEvent(ctx context.Context, jobID string) {
+	if r.events == nil {
+		return
+	}
+	p := r.Progress(jobID)
+	if p == nil {
+		return
+	}
+	message := fmt.Sprintf("Re-tagging %s: %d/%d documents processed (%d tagged, %d skipped, %d failed)",
+		p.Status, p.ProcessedDocuments, p.TotalDocuments, p.TaggedDocuments, p.SkippedDocuments, p.FailedDocuments)
+	if p.Error != "" {
+		message += ": " + p.Error
+	}
+	event := map[string]interface{}{
+		"EventType":      "retag",
+		"Status":         p.Status,
+		"Message":        message,
+		"OrganizationID": p.OrganizationID,
+		"Document":       "job " + p.JobID,
+	}
+	if err := r.events.AddEvent(ctx, event); err != nil {
+		log.Printf("Retagger: failed to record progress event: %v", err)
+	}
+}
+
+// setProgress stores a progress entry
+func (r *Retagger) setProgress(p *RetagProgress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.progress[p.JobID] = p
+}
+
+// update applies fn to the job's progress entry if it exists
+func (r *Retagger) update(jobID string, fn func(p *RetagProgress)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p, ok := r.progress[jobID]; ok {
+		fn(p)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/the-hive/internal/processor"
+	"github.com/the-hive/internal/queue"
+)
+
+// taggingVectorDB stores tags in point payloads and can read them back
+type taggingVectorDB struct {
+	recordingVectorDB
+}
+
+func (v *taggingVectorDB) UpdatePayload(ctx context.Context, id string, tags []string) error {
+	tagsJSON, _ := json.Marshal(tags)
+	v.points[id]["tags"] = string(tagsJSON)
+	return nil
+}
+
+func (v *taggingVectorDB) GetPayload(ctx context.Context, id string) (map[string]string, error) {
+	return v.points[id], nil
+}
+
+// fakeTagger returns fixed tags and counts calls
+type fakeTagger struct {
+	tags  []string
+	calls int
+}
+
+func (f *fakeTagger) Tag(content string) ([]string, error) {
+	f.calls++
+	return f.tags, nil
+}
+
+// recordingRetagEvents records progress events
+type recordingRetagEvents struct {
+	events []map[string]interface{}
+}
+
+func (r *recordingRetagEvents) AddEvent(ctx context.Context, event interface{}) error {
+	r.events = append(r.events, event.(map[string]interface{}))
+	return nil
+}
+
+func TestRetagger_UntaggedOnlyTagsUntaggedDocument(t *testing.T) {
+	db := newRechunkTestDB(t)
+	vdb := &taggingVectorDB{recordingVectorDB{points: make(map[string]map[string]string)}}
+	chunker := processor.NewChunkerWithSettings(300, 50)
+
+	untaggedChunks := seedDocument(t, db, &vdb.recordingVectorDB, chunker, "org-a", "invoice.pdf", testDocumentText())
+	seedDocument(t, db, &vdb.recordingVectorDB, chunker, "org-a", "tagged.pdf", "Already tagged memo about the quarterly offsite plans.")
+	vdb.points["tagged.pdf-old-0"]["tags"] = `["#memo"]`
+
+	tagger := &fakeTagger{tags: []string{"#finance", "#invoice"}}
+	events := &recordingRetagEvents{}
+	retagger := NewRetagger(db, vdb, tagger)
+	retagger.SetEventStore(events)
+
+	payload, _ := json.Marshal(RetagPayload{JobID: "job-1", OrganizationID: "org-a", UntaggedOnly: true})
+	if err := retagger.Handle(context.Background(), queue.Job{Type: JobTypeRetagOrganization, Payload: payload}); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	progress := retagger.Progress("job-1")
+	if progress == nil || progress.Status != "completed" {
+		t.Fatalf("Expected completed progress, got %+v", progress)
+	}
+	if progress.TotalDocuments != 2 || progress.TaggedDocuments != 1 || progress.SkippedDocuments != 1 {
+		t.Errorf("Expected 1 tagged and 1 skipped of 2 documents, got %+v", progress)
+	}
+	if tagger.calls != 1 {
+		t.Errorf("Expected the tagger to run once, got %d", tagger.calls)
+	}
+
+	for i := 0; i < untaggedChunks; i++ {
+		var tags []string
+		json.Unmarshal([]byte(vdb.points[fmt.Sprintf("invoice.pdf-old-%d", i)]["tags"]), &tags)
+		if len(tags) != 2 || tags[0] != "#finance" {
+			t.Errorf("Expected chunk %d to be tagged, got %v", i, tags)
+		}
+	}
+	if vdb.points["tagged.pdf-old-0"]["tags"] != `["#memo"]` {
+		t.Errorf("Already-tagged document must keep its tags, got %s", vdb.points["tagged.pdf-old-0"]["tags"])
+	}
+
+	if len(events.events) != 2 || events.events[0]["Status"] != "running" || events.events[1]["Status"] != "completed" {
+		t.Errorf("Expected running and completed progress events, got %v", events.events)
+	}
+}
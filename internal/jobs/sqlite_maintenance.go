@@ -0,0 +1,214 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCheckpointInterval = 15 * time.Minute
+	defaultVacuumInterval     = 24 * time.Hour
+	// defaultVacuumFreeRatio is the share of free pages that makes a scheduled VACUUM worthwhile
+	defaultVacuumFreeRatio = 0.10
+)
+
+// ErrMaintenanceRunning is returned when a maintenance run is already in progress
+var ErrMaintenanceRunning = errors.New("database maintenance already running")
+
+// MaintenanceReport describes one maintenance run
+type MaintenanceReport struct {
+	StartedAt      time.Time `json:"started_at"`
+	DurationMs     int64     `json:"duration_ms"`
+	SizeBefore     int64     `json:"size_before_bytes"` // Database file plus WAL
+	SizeAfter      int64     `json:"size_after_bytes"`
+	WALSizeBefore  int64     `json:"wal_size_before_bytes"`
+	WALSizeAfter   int64     `json:"wal_size_after_bytes"`
+	FreePages      int64     `json:"free_pages"`
+	Checkpointed   bool      `json:"checkpointed"`
+	CheckpointBusy bool      `json:"checkpoint_busy"` // A writer or reader prevented a full checkpoint
+	Vacuumed       bool      `json:"vacuumed"`
+	VacuumSkipped  string    `json:"vacuum_skipped,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// SQLiteMaintainer checkpoints the WAL and periodically vacuums the database.
+// Checkpoints are cheap and run on every tick; VACUUM rewrites the whole file and holds
+// the write lock, so scheduled runs only vacuum when the last checkpoint wasn't blocked
+// by activity and enough of the file is free pages.
+type SQLiteMaintainer struct {
+	db                 *sql.DB
+	path               string // Database file path (for WAL size reporting)
+	checkpointInterval time.Duration
+	vacuumInterval     time.Duration
+	vacuumFreeRatio    float64
+
+	runMu      sync.Mutex // Held for the duration of a run
+	mu         sync.RWMutex
+	lastVacuum time.Time
+	lastReport *MaintenanceReport
+}
+
+// NewSQLiteMaintainer creates a maintainer for the database at path
+func NewSQLiteMaintainer(db *sql.DB, path string) *SQLiteMaintainer {
+	return &SQLiteMaintainer{
+		db:                 db,
+		path:               path,
+		checkpointInterval: defaultCheckpointInterval,
+		vacuumInterval:     defaultVacuumInterval,
+		vacuumFreeRatio:    defaultVacuumFreeRatio,
+		lastVacuum:         time.Now(), // Don't vacuum right after startup
+	}
+}
+
+// SetIntervals sets how often the WAL is checkpointed and the minimum time between scheduled VACUUMs
+func (m *SQLiteMaintainer) SetIntervals(checkpoint, vacuum time.Duration) {
+	if checkpoint > 0 {
+		m.checkpointInterval = checkpoint
+	}
+	if vacuum > 0 {
+		m.vacuumInterval = vacuum
+	}
+}
+
+// LastReport returns the most recent maintenance report, or nil if none has run
+func (m *SQLiteMaintainer) LastReport() *MaintenanceReport {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastReport
+}
+
+// Start runs scheduled maintenance until the context is cancelled
+func (m *SQLiteMaintainer) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(m.checkpointInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.mu.RLock()
+				vacuumDue := time.Since(m.lastVacuum) >= m.vacuumInterval
+				m.mu.RUnlock()
+
+				report, err := m.Run(ctx, vacuumDue, false)
+				if err != nil && !errors.Is(err, ErrMaintenanceRunning) {
+					log.Printf("[DB MAINTENANCE] Scheduled run failed: %v", err)
+				} else if report != nil && report.Vacuumed {
+					log.Printf("[DB MAINTENANCE] Vacuumed database: %d -> %d bytes in %dms", report.SizeBefore, report.SizeAfter, report.DurationMs)
+				}
+			}
+		}
+	}()
+}
+
+// Run checkpoints the WAL and, if vacuum is set, vacuums the database.
+// Unless force is set, VACUUM is skipped when the checkpoint was blocked by activity
+// or too little of the file is free pages.
+func (m *SQLiteMaintainer) Run(ctx context.Context, vacuum, force bool) (*MaintenanceReport, error) {
+	if !m.runMu.TryLock() {
+		return nil, ErrMaintenanceRunning
+	}
+	defer m.runMu.Unlock()
+
+	report := &MaintenanceReport{StartedAt: time.Now()}
+	err := m.run(ctx, report, vacuum, force)
+	if err != nil {
+		report.Error = err.Error()
+	}
+	report.DurationMs = time.Since(report.StartedAt).Milliseconds()
+
+	m.mu.Lock()
+	m.lastReport = report
+	if report.Vacuumed {
+		m.lastVacuum = time.Now()
+	}
+	m.mu.Unlock()
+
+	return report, err
+}
+
+func (m *SQLiteMaintainer) run(ctx context.Context, report *MaintenanceReport, vacuum, force bool) error {
+	var err error
+	if report.SizeBefore, report.WALSizeBefore, report.FreePages, err = m.sizes(ctx); err != nil {
+		return err
+	}
+
+	// busy is 1 if a reader or writer kept the checkpoint from completing
+	var busy, logFrames, checkpointed int
+	if err := m.db.QueryRowContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &logFrames, &checkpointed); err != nil {
+		return fmt.Errorf("failed to checkpoint WAL: %w", err)
+	}
+	report.Checkpointed = true
+	report.CheckpointBusy = busy != 0
+
+	if vacuum {
+		pageCount, err := m.pragmaInt(ctx, "page_count")
+		if err != nil {
+			return err
+		}
+		switch {
+		case force:
+			report.Vacuumed = true
+		case report.CheckpointBusy:
+			report.VacuumSkipped = "database busy"
+		case pageCount == 0 || float64(report.FreePages)/float64(pageCount) < m.vacuumFreeRatio:
+			report.VacuumSkipped = fmt.Sprintf("free pages below %.0f%%", m.vacuumFreeRatio*100)
+		default:
+			report.Vacuumed = true
+		}
+
+		if report.Vacuumed {
+			if _, err := m.db.ExecContext(ctx, "VACUUM"); err != nil {
+				report.Vacuumed = false
+				return fmt.Errorf("failed to vacuum database: %w", err)
+			}
+			// VACUUM in WAL mode writes through the WAL; truncate it again
+			if _, err := m.db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+				log.Printf("[DB MAINTENANCE] Post-vacuum checkpoint failed: %v", err)
+			}
+		}
+	}
+
+	if report.SizeAfter, report.WALSizeAfter, _, err = m.sizes(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// sizes returns the database size (file plus WAL), the WAL size and the number of free pages
+func (m *SQLiteMaintainer) sizes(ctx context.Context) (total, wal, freePages int64, err error) {
+	pageCount, err := m.pragmaInt(ctx, "page_count")
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	pageSize, err := m.pragmaInt(ctx, "page_size")
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if freePages, err = m.pragmaInt(ctx, "freelist_count"); err != nil {
+		return 0, 0, 0, err
+	}
+	if m.path != "" {
+		if info, statErr := os.Stat(m.path + "-wal"); statErr == nil {
+			wal = info.Size()
+		}
+	}
+	return pageCount*pageSize + wal, wal, freePages, nil
+}
+
+func (m *SQLiteMaintainer) pragmaInt(ctx context.Context, name string) (int64, error) {
+	var value int64
+	if err := m.db.QueryRowContext(ctx, "PRAGMA "+name).Scan(&value); err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// newWALTestDB opens a WAL-mode database with a table that has been filled and then emptied
+func newWALTestDB(t *testing.T) (*sql.DB, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "maintenance.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	for _, stmt := range []string{
+		"PRAGMA journal_mode=WAL",
+		"CREATE TABLE blobs (id INTEGER PRIMARY KEY, data TEXT)",
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("Failed to run %q: %v", stmt, err)
+		}
+	}
+
+	filler := strings.Repeat("x", 4000)
+	for i := 0; i < 200; i++ {
+		if _, err := db.Exec("INSERT INTO blobs (data) VALUES (?)", filler); err != nil {
+			t.Fatalf("Failed to insert: %v", err)
+		}
+	}
+	if _, err := db.Exec("DELETE FROM blobs"); err != nil {
+		t.Fatalf("Failed to delete: %v", err)
+	}
+	return db, path
+}
+
+func TestSQLiteMaintainer_RunVacuumShrinksDatabase(t *testing.T) {
+	db, path := newWALTestDB(t)
+	m := NewSQLiteMaintainer(db, path)
+
+	report, err := m.Run(context.Background(), true, false)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !report.Checkpointed || !report.Vacuumed {
+		t.Fatalf("Expected checkpoint and vacuum, got %+v", report)
+	}
+	if report.FreePages == 0 || report.SizeAfter >= report.SizeBefore {
+		t.Errorf("Expected vacuum to reclaim free pages: %+v", report)
+	}
+	if report.WALSizeAfter != 0 {
+		t.Errorf("Expected the WAL to be truncated, got %d bytes", report.WALSizeAfter)
+	}
+	if m.LastReport() != report {
+		t.Errorf("Expected the run to be recorded as the last report")
+	}
+}
+
+func TestSQLiteMaintainer_SkipsVacuumWhenNotFragmented(t *testing.T) {
+	db, path := newWALTestDB(t)
+	if _, err := db.Exec("VACUUM"); err != nil {
+		t.Fatalf("Failed to vacuum: %v", err)
+	}
+	m := NewSQLiteMaintainer(db, path)
+
+	report, err := m.Run(context.Background(), true, false)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if report.Vacuumed || report.VacuumSkipped == "" {
+		t.Errorf("Expected the scheduled vacuum to be skipped, got %+v", report)
+	}
+
+	// A forced (manual) vacuum always runs
+	if report, err = m.Run(context.Background(), true, true); err != nil || !report.Vacuumed {
+		t.Errorf("Expected a forced vacuum, got %+v, %v", report, err)
+	}
+}
+
+func TestSQLiteMaintainer_RejectsConcurrentRuns(t *testing.T) {
+	db, path := newWALTestDB(t)
+	m := NewSQLiteMaintainer(db, path)
+
+	m.runMu.Lock()
+	_, err := m.Run(context.Background(), false, false)
+	m.runMu.Unlock()
+	if !errors.Is(err, ErrMaintenanceRunning) {
+		t.Errorf("Expected ErrMaintenanceRunning, got %v", err)
+	}
+}
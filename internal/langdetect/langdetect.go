@@ -0,0 +1,182 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package langdetect
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Unknown is the code returned when the language can't be determined (ISO 639-2 "undetermined")
+const Unknown = "und"
+
+// minStopwordHits is the number of stopword matches needed before a Latin-script guess is trusted
+const minStopwordHits = 2
+
+// Names maps the detectable language codes to English names (used in prompts)
+var Names = map[string]string{
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"de": "German",
+	"it": "Italian",
+	"pt": "Portuguese",
+	"nl": "Dutch",
+	"ru": "Russian",
+	"el": "Greek",
+	"ar": "Arabic",
+	"he": "Hebrew",
+	"hi": "Hindi",
+	"th": "Thai",
+	"ko": "Korean",
+	"ja": "Japanese",
+	"zh": "Chinese",
+}
+
+// stopwords are frequent function words that are distinctive for each Latin-script language
+var stopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "in", "that", "it", "for", "with", "was", "on", "are", "this", "be", "by", "have", "not", "from", "or"},
+	"es": {"el", "la", "de", "que", "y", "en", "los", "del", "se", "las", "por", "un", "para", "con", "una", "es", "al", "lo", "como", "pero"},
+	"fr": {"le", "la", "les", "de", "des", "et", "est", "un", "une", "du", "que", "qui", "dans", "pour", "pas", "sur", "au", "avec", "ce", "sont"},
+	"de": {"der", "die", "und", "den", "das", "ist", "nicht", "mit", "von", "zu", "sich", "des", "auf", "ein", "eine", "dem", "für", "im", "auch", "wird"},
+	"it": {"il", "di", "che", "e", "la", "per", "un", "non", "sono", "della", "una", "del", "gli", "con", "le", "si", "nel", "alla", "anche", "questo"},
+	"pt": {"o", "de", "que", "e", "do", "da", "em", "um", "para", "com", "não", "uma", "os", "no", "se", "na", "por", "mais", "as", "dos"},
+	"nl": {"de", "het", "een", "en", "van", "is", "dat", "niet", "op", "te", "zijn", "voor", "met", "die", "ook", "aan", "er", "maar", "om", "wordt"},
+}
+
+var stopwordSets = func() map[string]map[string]bool {
+	sets := make(map[string]map[string]bool, len(stopwords))
+	for lang, words := range stopwords {
+		set := make(map[string]bool, len(words))
+		for _, w := range words {
+			set[w] = true
+		}
+		sets[lang] = set
+	}
+	return sets
+}()
+
+// scripts maps non-Latin Unicode scripts to the language they most likely indicate
+var scripts = []struct {
+	table *unicode.RangeTable
+	code  string
+}{
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Hangul, "ko"},
+	{unicode.Han, "zh"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Greek, "el"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Thai, "th"},
+}
+
+// Result is a detected language with a rough confidence (0-1)
+type Result struct {
+	Code       string  `json:"code"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Detect returns the dominant language of the text.
+// Non-Latin scripts are identified by character counts; Latin-script text by stopword frequency.
+func Detect(text string) Result {
+	letters := 0
+	latin := 0
+	scriptCounts := make(map[string]int)
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.Is(unicode.Latin, r) {
+			latin++
+			continue
+		}
+		for _, s := range scripts {
+			if unicode.Is(s.table, r) {
+				scriptCounts[s.code]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return Result{Code: Unknown}
+	}
+
+	// Japanese text mixes kana with Han characters; any kana means Japanese rather than Chinese
+	if scriptCounts["ja"] > 0 && scriptCounts["zh"] > 0 {
+		scriptCounts["ja"] += scriptCounts["zh"]
+		delete(scriptCounts, "zh")
+	}
+
+	bestScript, bestScriptCount := "", 0
+	for code, count := range scriptCounts {
+		if count > bestScriptCount || (count == bestScriptCount && code < bestScript) {
+			bestScript, bestScriptCount = code, count
+		}
+	}
+	if bestScriptCount > latin {
+		return Result{Code: bestScript, Confidence: float64(bestScriptCount) / float64(letters)}
+	}
+
+	return detectLatin(text)
+}
+
+// detectLatin scores Latin-script text against each language's stopwords
+func detectLatin(text string) Result {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+
+	hits := make(map[string]int)
+	total := 0
+	for _, word := range words {
+		for lang, set := range stopwordSets {
+			if set[word] {
+				hits[lang]++
+				total++
+			}
+		}
+	}
+
+	best, bestHits := "", 0
+	for lang, n := range hits {
+		if n > bestHits || (n == bestHits && lang < best) {
+			best, bestHits = lang, n
+		}
+	}
+	if bestHits < minStopwordHits {
+		return Result{Code: Unknown}
+	}
+	return Result{Code: best, Confidence: float64(bestHits) / float64(total)}
+}
+
+// DetectDocument returns the dominant language of a document split into parts (e.g. chunks).
+// Each part is detected separately and votes with its length, so a mostly-English document
+// with a foreign-language appendix is tagged English.
+func DetectDocument(parts []string) string {
+	votes := make(map[string]int)
+	for _, part := range parts {
+		if code := Detect(part).Code; code != Unknown {
+			votes[code] += len(part)
+		}
+	}
+
+	best, bestVotes := Unknown, 0
+	for code, n := range votes {
+		if n > bestVotes || (n == bestVotes && code < best) {
+			best, bestVotes = code, n
+		}
+	}
+	return best
+}
+
+// Name returns the English name of the language code, or the code itself if it isn't known
+func Name(code string) string {
+	if name, ok := Names[code]; ok {
+		return name
+	}
+	return code
+}
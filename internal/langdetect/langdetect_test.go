@@ -0,0 +1,47 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package langdetect
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "The contract is valid for one year and it can be renewed by either party with written notice.", "en"},
+		{"spanish", "El contrato es válido por un año y se puede renovar por cualquiera de las partes con un aviso por escrito.", "es"},
+		{"german", "Der Vertrag ist für ein Jahr gültig und kann von jeder Partei mit einer schriftlichen Mitteilung verlängert werden.", "de"},
+		{"french", "Le contrat est valable pour une année et il peut être renouvelé par les parties avec un préavis.", "fr"},
+		{"russian", "Договор действует один год и может быть продлен любой из сторон.", "ru"},
+		{"japanese", "この契約は一年間有効です。", "ja"},
+		{"too short", "Invoice 2024", Unknown},
+		{"empty", "", Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.text).Code; got != tt.want {
+				t.Errorf("Detect(%q) = %s, want %s", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectDocument_DominantLanguage(t *testing.T) {
+	english := strings.Repeat("The supplier delivers the goods to the warehouse and it is responsible for the packaging. ", 5)
+	spanish := "El proveedor entrega los productos en el almacén y es responsable del embalaje."
+
+	if got := DetectDocument([]string{english, spanish, english}); got != "en" {
+		t.Errorf("Expected the mostly-English document to be tagged en, got %s", got)
+	}
+	if got := DetectDocument([]string{spanish}); got != "es" {
+		t.Errorf("Expected es, got %s", got)
+	}
+	if got := DetectDocument(nil); got != Unknown {
+		t.Errorf("Expected %s for an empty document, got %s", Unknown, got)
+	}
+}
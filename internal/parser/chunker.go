@@ -6,10 +6,31 @@ import (
 	"strings"
 )
 
+const (
+	// charsPerToken converts token counts in ChunkOptions to characters; a rough average for English text
+	charsPerToken = 4
+	// sentenceSearchChars is how far back from a chunk's end a sentence boundary is looked for
+	sentenceSearchChars = 200
+)
+
+// ChunkOptions tunes how a Chunker splits text; sizes are in (estimated) tokens
+type ChunkOptions struct {
+	MaxTokens       int  // Largest chunk; zero or less uses the default
+	OverlapTokens   int  // Text repeated between consecutive chunks; must be smaller than MaxTokens
+	SplitOnSentence bool // End chunks at a sentence or paragraph break near the size limit instead of mid-sentence
+}
+
+// DefaultChunkOptions returns the options NewChunker uses: 250 tokens (~1000 characters)
+// with 50 tokens (~200 characters) of overlap, split at a fixed size
+func DefaultChunkOptions() ChunkOptions {
+	return ChunkOptions{MaxTokens: 250, OverlapTokens: 50}
+}
+
 // Chunker handles text chunking with configurable size and overlap
 type Chunker struct {
-	chunkSize    int
-	chunkOverlap int
+	chunkSize       int
+	chunkOverlap    int
+	splitOnSentence bool
 }
 
 // NewChunker creates a new chunker with default settings
@@ -20,6 +41,24 @@ func NewChunker() *Chunker {
 	}
 }
 
+// NewChunkerWithOptions creates a chunker from the given options; invalid sizes fall back to the defaults
+func NewChunkerWithOptions(opts ChunkOptions) *Chunker {
+	c := NewChunker()
+	if opts.MaxTokens > 0 {
+		c.chunkSize = opts.MaxTokens * charsPerToken
+	}
+	if overlap := opts.OverlapTokens * charsPerToken; overlap >= 0 && overlap < c.chunkSize {
+		c.chunkOverlap = overlap
+	}
+	c.splitOnSentence = opts.SplitOnSentence
+	return c
+}
+
+// ChunkSize returns the largest chunk this chunker makes, in characters
+func (c *Chunker) ChunkSize() int {
+	return c.chunkSize
+}
+
 // ChunkText splits text into overlapping chunks
 func (c *Chunker) ChunkText(text string) ([]string, error) {
 	if len(text) == 0 {
@@ -33,6 +72,8 @@ func (c *Chunker) ChunkText(text string) ([]string, error) {
 		end := start + c.chunkSize
 		if end > len(text) {
 			end = len(text)
+		} else if c.splitOnSentence {
+			end = sentenceBreak(text, start, end)
 		}
 
 		chunk := text[start:end]
@@ -42,12 +83,32 @@ func (c *Chunker) ChunkText(text string) ([]string, error) {
 			break
 		}
 
-		start = end - c.chunkOverlap
-		if start < 0 {
-			start = 0
+		// A sentence break can end a chunk early enough that the overlap reaches back past its
+		// start; drop the overlap then so each chunk moves forward
+		next := end - c.chunkOverlap
+		if next <= start {
+			next = end
 		}
+		start = next
 	}
 
 	return chunks, nil
 }
 
+// sentenceBreak returns the last sentence end (., ! or ? followed by whitespace) or paragraph
+// break in the final sentenceSearchChars of text[start:end], or end if there is none
+func sentenceBreak(text string, start, end int) int {
+	searchStart := end - sentenceSearchChars
+	if searchStart < start {
+		searchStart = start
+	}
+	for i := end - 1; i >= searchStart && i+1 < len(text); i-- {
+		switch next := text[i+1]; {
+		case (text[i] == '.' || text[i] == '!' || text[i] == '?') && (next == ' ' || next == '\n' || next == '\r'):
+			return i + 1
+		case text[i] == '\n' && next == '\n':
+			return i + 2
+		}
+	}
+	return end
+}
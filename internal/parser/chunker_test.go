@@ -0,0 +1,35 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewChunkerWithOptions(t *testing.T) {
+	text := strings.Repeat("Invoices are due within thirty days. Late payments accrue interest monthly. ", 60)
+
+	defaults, _ := NewChunker().ChunkText(text)
+	fromOptions, _ := NewChunkerWithOptions(DefaultChunkOptions()).ChunkText(text)
+	if strings.Join(defaults, "|") != strings.Join(fromOptions, "|") {
+		t.Error("Expected DefaultChunkOptions to chunk exactly like NewChunker")
+	}
+	if len(defaults[0]) != 1000 {
+		t.Errorf("Expected the default fixed split at 1000 characters, got %d", len(defaults[0]))
+	}
+
+	chunks, _ := NewChunkerWithOptions(ChunkOptions{MaxTokens: 100, OverlapTokens: 10, SplitOnSentence: true}).ChunkText(text)
+	if len(chunks) < 2 {
+		t.Fatalf("Expected several chunks, got %d", len(chunks))
+	}
+	for _, chunk := range chunks {
+		if len(chunk) > 400 || !strings.HasSuffix(chunk, ".") {
+			t.Errorf("Expected chunks of at most 400 characters ending on a sentence, got %d: %q", len(chunk), chunk)
+		}
+	}
+	// The 40 characters of overlap repeat the end of the previous chunk
+	if !strings.Contains(chunks[0], chunks[1][:20]) {
+		t.Errorf("Expected the second chunk to overlap the first, got %q", chunks[1][:20])
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package pii
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Type identifies a kind of personally identifiable information
+type Type string
+
+const (
+	TypeEmail      Type = "email"
+	TypePhone      Type = "phone"
+	TypeCreditCard Type = "credit_card"
+	TypeSSN        Type = "ssn"
+)
+
+// Mode controls what happens when PII is found at ingest
+type Mode string
+
+const (
+	ModeOff   Mode = "off"   // Don't scan
+	ModeAlert Mode = "alert" // Store content as-is, notify and record findings
+	ModeMask  Mode = "mask"  // Replace PII with placeholders before storage and record findings
+)
+
+// ParseMode converts a string to a Mode, returning an error for unknown values
+func ParseMode(s string) (Mode, error) {
+	switch Mode(strings.ToLower(strings.TrimSpace(s))) {
+	case ModeOff, "":
+		return ModeOff, nil
+	case ModeAlert:
+		return ModeAlert, nil
+	case ModeMask:
+		return ModeMask, nil
+	default:
+		return ModeOff, fmt.Errorf("invalid PII mode %q (expected off, alert or mask)", s)
+	}
+}
+
+// Pattern detects one type of PII
+type Pattern struct {
+	Type     Type
+	Regex    *regexp.Regexp
+	Validate func(match string) bool // Optional extra check (e.g. Luhn for card numbers)
+}
+
+// Finding is a single PII match in scanned text
+type Finding struct {
+	Type  Type
+	Start int
+	End   int
+}
+
+// Scanner finds and masks PII using a set of patterns
+type Scanner struct {
+	patterns []Pattern
+}
+
+// defaultRegexes are the built-in detection patterns, overridable via PII_<TYPE>_REGEX
+var defaultRegexes = map[Type]string{
+	TypeEmail:      `[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`,
+	TypePhone:      `(?:\+?1[\s.\-]?)?\(?\b[2-9]\d{2}\)?[\s.\-]?\d{3}[\s.\-]\d{4}\b`,
+	TypeCreditCard: `\b(?:\d[ \-]?){12,18}\d\b`,
+	TypeSSN:        `\b\d{3}-\d{2}-\d{4}\b`,
+}
+
+// allTypes lists the PII types in the order they are matched (most specific first)
+var allTypes = []Type{TypeEmail, TypeCreditCard, TypeSSN, TypePhone}
+
+// NewScanner creates a scanner with the given patterns
+func NewScanner(patterns []Pattern) *Scanner {
+	return &Scanner{patterns: patterns}
+}
+
+// DefaultPatterns returns the built-in patterns for all PII types
+func DefaultPatterns() []Pattern {
+	patterns := make([]Pattern, 0, len(allTypes))
+	for _, t := range allTypes {
+		patterns = append(patterns, newPattern(t, regexp.MustCompile(defaultRegexes[t])))
+	}
+	return patterns
+}
+
+// NewScannerFromEnv creates a scanner from the environment:
+// PII_TYPES selects the enabled types (comma-separated, default all) and
+// PII_EMAIL_REGEX, PII_PHONE_REGEX, PII_CREDIT_CARD_REGEX, PII_SSN_REGEX override the built-in regexes
+func NewScannerFromEnv() *Scanner {
+	enabled := make(map[Type]bool)
+	if typesStr := os.Getenv("PII_TYPES"); typesStr != "" {
+		for _, t := range strings.Split(typesStr, ",") {
+			enabled[Type(strings.ToLower(strings.TrimSpace(t)))] = true
+		}
+	} else {
+		for _, t := range allTypes {
+			enabled[t] = true
+		}
+	}
+
+	var patterns []Pattern
+	for _, t := range allTypes {
+		if !enabled[t] {
+			continue
+		}
+		expr := defaultRegexes[t]
+		envKey := "PII_" + strings.ToUpper(string(t)) + "_REGEX"
+		if custom := os.Getenv(envKey); custom != "" {
+			expr = custom
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			log.Printf("Invalid %s value: %v, using default", envKey, err)
+			re = regexp.MustCompile(defaultRegexes[t])
+		}
+		patterns = append(patterns, newPattern(t, re))
+	}
+
+	return NewScanner(patterns)
+}
+
+// newPattern attaches the type's validator to a regex
+func newPattern(t Type, re *regexp.Regexp) Pattern {
+	p := Pattern{Type: t, Regex: re}
+	if t == TypeCreditCard {
+		p.Validate = LuhnValid
+	}
+	return p
+}
+
+// Scan returns all non-overlapping PII findings in text, ordered by position
+func (s *Scanner) Scan(text string) []Finding {
+	var findings []Finding
+	for _, p := range s.patterns {
+		for _, loc := range p.Regex.FindAllStringIndex(text, -1) {
+			if p.Validate != nil && !p.Validate(text[loc[0]:loc[1]]) {
+				continue
+			}
+			if overlaps(findings, loc[0], loc[1]) {
+				continue
+			}
+			findings = append(findings, Finding{Type: p.Type, Start: loc[0], End: loc[1]})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].Start < findings[j].Start
+	})
+	return findings
+}
+
+// Mask replaces every PII finding with a [REDACTED:<TYPE>] placeholder
+func (s *Scanner) Mask(text string) (string, []Finding) {
+	findings := s.Scan(text)
+	if len(findings) == 0 {
+		return text, nil
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, f := range findings {
+		sb.WriteString(text[last:f.Start])
+		sb.WriteString("[REDACTED:" + strings.ToUpper(string(f.Type)) + "]")
+		last = f.End
+	}
+	sb.WriteString(text[last:])
+	return sb.String(), findings
+}
+
+// CountByType summarizes findings per PII type
+func CountByType(findings []Finding) map[Type]int {
+	counts := make(map[Type]int)
+	for _, f := range findings {
+		counts[f.Type]++
+	}
+	return counts
+}
+
+// Summary describes findings without including the matched values, e.g. "2 email, 1 ssn"
+func Summary(findings []Finding) string {
+	counts := CountByType(findings)
+	parts := make([]string, 0, len(counts))
+	for _, t := range allTypes {
+		if counts[t] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[t], t))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// LuhnValid reports whether the digits in s pass the Luhn checksum (spaces and dashes are ignored)
+func LuhnValid(s string) bool {
+	sum := 0
+	digits := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+		double = !double
+	}
+	return digits >= 13 && sum%10 == 0
+}
+
+// overlaps reports whether [start, end) overlaps an existing finding
+func overlaps(findings []Finding, start, end int) bool {
+	for _, f := range findings {
+		if start < f.End && f.Start < end {
+			return true
+		}
+	}
+	return false
+}
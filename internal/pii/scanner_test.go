@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package pii
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLuhnValid(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		{"4111111111111111", true},
+		{"4111 1111 1111 1111", true},
+		{"5500-0000-0000-0004", true},
+		{"378282246310005", true},
+		{"4111111111111112", false},
+		{"1234567812345678", false},
+		{"000000000000", false}, // Too short
+		{"4111a11111111111", false},
+	}
+
+	for _, tt := range tests {
+		if got := LuhnValid(tt.number); got != tt.want {
+			t.Errorf("LuhnValid(%q) = %v, want %v", tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestScanner_DetectsEachType(t *testing.T) {
+	scanner := NewScanner(DefaultPatterns())
+
+	tests := []struct {
+		name string
+		text string
+		want Type
+	}{
+		{"email", "Contact jane.doe@example.com for details", TypeEmail},
+		{"phone dashes", "Call 415-555-0134 after 5pm", TypePhone},
+		{"phone parens", "Office: (212) 555-0199", TypePhone},
+		{"credit card", "Card on file: 4111 1111 1111 1111", TypeCreditCard},
+		{"ssn", "SSN 123-45-6789 was provided", TypeSSN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := scanner.Scan(tt.text)
+			if len(findings) != 1 || findings[0].Type != tt.want {
+				t.Errorf("Scan(%q) = %+v, want one %s finding", tt.text, findings, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanner_IgnoresInvalidCardNumbers(t *testing.T) {
+	scanner := NewScanner(DefaultPatterns())
+
+	if findings := scanner.Scan("Order number 1234567812345678 shipped"); len(findings) != 0 {
+		t.Errorf("Expected Luhn-invalid number to be ignored, got %+v", findings)
+	}
+}
+
+func TestScanner_Mask(t *testing.T) {
+	scanner := NewScanner(DefaultPatterns())
+
+	masked, findings := scanner.Mask("Email bob@corp.io, SSN 123-45-6789, card 5500-0000-0000-0004.")
+	if len(findings) != 3 {
+		t.Fatalf("Expected 3 findings, got %+v", findings)
+	}
+	want := "Email [REDACTED:EMAIL], SSN [REDACTED:SSN], card [REDACTED:CREDIT_CARD]."
+	if masked != want {
+		t.Errorf("Mask() = %q, want %q", masked, want)
+	}
+	if got := Summary(findings); got != "1 email, 1 credit_card, 1 ssn" {
+		t.Errorf("Summary() = %q", got)
+	}
+}
+
+func TestNewScannerFromEnv(t *testing.T) {
+	t.Setenv("PII_TYPES", "email, ssn")
+	t.Setenv("PII_SSN_REGEX", `\bSSN#\d{9}\b`)
+
+	scanner := NewScannerFromEnv()
+	findings := scanner.Scan("a@b.co SSN#123456789 123-45-6789 415-555-0134")
+
+	var types []string
+	for _, f := range findings {
+		types = append(types, string(f.Type))
+	}
+	if strings.Join(types, ",") != "email,ssn" {
+		t.Errorf("Expected only email and custom SSN matches, got %v", types)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for input, want := range map[string]Mode{"": ModeOff, "off": ModeOff, "Alert": ModeAlert, " mask ": ModeMask} {
+		got, err := ParseMode(input)
+		if err != nil || got != want {
+			t.Errorf("ParseMode(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	if _, err := ParseMode("redact"); err == nil {
+		t.Errorf("Expected error for unknown mode")
+	}
+}
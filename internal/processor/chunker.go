@@ -3,31 +3,116 @@
 package processor
 
 import (
+	"log"
+	"os"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
+// charsPerToken converts token counts in ChunkOptions to characters; a rough average for English text
+const charsPerToken = 4
+
+// ChunkOptions tunes how a Chunker splits text; sizes are in (estimated) tokens
+type ChunkOptions struct {
+	MaxTokens       int  // Largest chunk; zero or less uses the default
+	OverlapTokens   int  // Text repeated between consecutive chunks; must be smaller than MaxTokens
+	SplitOnSentence bool // End chunks at a sentence or paragraph break near the size limit instead of mid-sentence
+}
+
+// DefaultChunkOptions returns the options NewChunker uses: 250 tokens (~1000 characters)
+// with 25 tokens (~100 characters) of overlap, split on sentences
+func DefaultChunkOptions() ChunkOptions {
+	return ChunkOptions{MaxTokens: 250, OverlapTokens: 25, SplitOnSentence: true}
+}
+
 // Chunker handles text chunking with sentence-aware splitting
 type Chunker struct {
-	chunkSize    int
-	chunkOverlap int
+	chunkSize       int
+	chunkOverlap    int
+	splitOnSentence bool
 }
 
 // NewChunker creates a new chunker with default settings
 // Default: ~1000 characters per chunk with 100 character overlap
 func NewChunker() *Chunker {
 	return &Chunker{
-		chunkSize:    1000,
-		chunkOverlap: 100,
+		chunkSize:       1000,
+		chunkOverlap:    100,
+		splitOnSentence: true,
+	}
+}
+
+// NewChunkerWithSettings creates a chunker with the given chunk size and overlap (in characters)
+// Invalid values fall back to the defaults
+func NewChunkerWithSettings(chunkSize, chunkOverlap int) *Chunker {
+	c := NewChunker()
+	if chunkSize > 0 {
+		c.chunkSize = chunkSize
+	}
+	if chunkOverlap >= 0 && chunkOverlap < c.chunkSize {
+		c.chunkOverlap = chunkOverlap
 	}
+	return c
+}
+
+// NewChunkerWithOptions creates a chunker from the given options; invalid sizes fall back to the defaults
+func NewChunkerWithOptions(opts ChunkOptions) *Chunker {
+	c := NewChunkerWithSettings(opts.MaxTokens*charsPerToken, opts.OverlapTokens*charsPerToken)
+	c.splitOnSentence = opts.SplitOnSentence
+	return c
+}
+
+// NewChunkerFromEnv creates a chunker from CHUNK_SIZE and CHUNK_OVERLAP, falling back to the defaults
+func NewChunkerFromEnv() *Chunker {
+	chunkSize := envInt("CHUNK_SIZE", 1000)
+	chunkOverlap := envInt("CHUNK_OVERLAP", 100)
+	return NewChunkerWithSettings(chunkSize, chunkOverlap)
+}
+
+// Settings returns the chunk size and overlap used by this chunker
+func (c *Chunker) Settings() (chunkSize, chunkOverlap int) {
+	return c.chunkSize, c.chunkOverlap
+}
+
+// envInt reads a positive integer from the environment, returning the default if unset or invalid
+func envInt(key string, defaultValue int) int {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil || value < 0 {
+		log.Printf("Invalid %s value '%s', using default %d", key, valueStr, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
+// Span is the byte range [Start, End) of one chunk within the chunked text
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
 }
 
 // ChunkText splits text into overlapping chunks, trying to avoid cutting sentences
 func (c *Chunker) ChunkText(text string) ([]string, error) {
+	spans := c.ChunkSpans(text)
+	chunks := make([]string, len(spans))
+	for i, span := range spans {
+		chunks[i] = text[span.Start:span.End]
+	}
+	return chunks, nil
+}
+
+// ChunkSpans returns where ChunkText would split text, as byte offsets of each
+// (whitespace-trimmed) chunk; overlapping chunks have overlapping spans
+func (c *Chunker) ChunkSpans(text string) []Span {
 	if len(text) == 0 {
-		return []string{}, nil
+		return []Span{}
 	}
 
-	var chunks []string
+	var spans []Span
 	start := 0
 	textLen := len(text)
 
@@ -38,7 +123,7 @@ func (c *Chunker) ChunkText(text string) ([]string, error) {
 		}
 
 		// If we're not at the end, try to find a sentence boundary
-		if end < textLen {
+		if end < textLen && c.splitOnSentence {
 			// Look for sentence endings within the last 200 characters
 			searchStart := end - 200
 			if searchStart < start {
@@ -73,9 +158,11 @@ func (c *Chunker) ChunkText(text string) ([]string, error) {
 			}
 		}
 
-		chunk := strings.TrimSpace(text[start:end])
+		raw := text[start:end]
+		chunk := strings.TrimSpace(raw)
 		if len(chunk) > 0 {
-			chunks = append(chunks, chunk)
+			chunkStart := start + len(raw) - len(strings.TrimLeftFunc(raw, unicode.IsSpace))
+			spans = append(spans, Span{Start: chunkStart, End: chunkStart + len(chunk)})
 		}
 
 		// Move start position with overlap
@@ -83,15 +170,14 @@ func (c *Chunker) ChunkText(text string) ([]string, error) {
 			break
 		}
 
-		start = end - c.chunkOverlap
-		if start < 0 {
-			start = 0
-		}
-		// Ensure we don't get stuck in a loop
-		if start >= end {
-			start = end
+		// Ensure we don't get stuck in a loop: a sentence break can end a chunk early
+		// enough that the overlap reaches back past its start
+		next := end - c.chunkOverlap
+		if next <= start {
+			next = end
 		}
+		start = next
 	}
 
-	return chunks, nil
+	return spans
 }
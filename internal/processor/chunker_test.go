@@ -156,3 +156,46 @@ func TestChunker_SentenceBoundaries(t *testing.T) {
 		// This is a warning, not a failure, as the chunker tries but may not always succeed
 	}
 }
+
+func TestNewChunkerWithOptions(t *testing.T) {
+	text := strings.Repeat("The first clause is short. The second clause runs a little longer than the first one. ", 40)
+
+	defaults, _ := NewChunker().ChunkText(text)
+	fromOptions, _ := NewChunkerWithOptions(DefaultChunkOptions()).ChunkText(text)
+	if strings.Join(defaults, "|") != strings.Join(fromOptions, "|") {
+		t.Error("Expected DefaultChunkOptions to chunk exactly like NewChunker")
+	}
+
+	fixed := NewChunkerWithOptions(ChunkOptions{MaxTokens: 50, OverlapTokens: 5})
+	if size, overlap := fixed.Settings(); size != 200 || overlap != 20 {
+		t.Errorf("Expected 200 characters with 20 of overlap, got %d and %d", size, overlap)
+	}
+	chunks, _ := fixed.ChunkText(text)
+	if len(chunks[0]) != 200 {
+		t.Errorf("Expected a fixed split at 200 characters, got %d: %q", len(chunks[0]), chunks[0])
+	}
+
+	chunks, _ = NewChunkerWithOptions(ChunkOptions{MaxTokens: 50, OverlapTokens: 5, SplitOnSentence: true}).ChunkText(text)
+	for _, chunk := range chunks {
+		if !strings.HasSuffix(chunk, ".") {
+			t.Errorf("Expected every chunk to end on a sentence, got %q", chunk)
+		}
+	}
+
+	// Overlap as large as the chunk is ignored
+	if _, overlap := NewChunkerWithOptions(ChunkOptions{MaxTokens: 50, OverlapTokens: 50}).Settings(); overlap != 100 {
+		t.Errorf("Expected the default overlap, got %d", overlap)
+	}
+}
+
+func TestChunker_SentenceSplitWithLargeOverlapMovesForward(t *testing.T) {
+	// Sentence breaks close to the chunk start would otherwise send the next chunk backwards
+	text := strings.Repeat("Ok. "+strings.Repeat("x", 150)+" ", 10)
+	chunks, _ := NewChunkerWithOptions(ChunkOptions{MaxTokens: 40, OverlapTokens: 30, SplitOnSentence: true}).ChunkText(text)
+	if len(chunks) == 0 || len(chunks) > len(text) {
+		t.Fatalf("Expected the chunker to finish, got %d chunks", len(chunks))
+	}
+	if last := chunks[len(chunks)-1]; !strings.HasSuffix(strings.TrimSpace(text), last) {
+		t.Errorf("Expected the last chunk to reach the end of the text, got %q", last)
+	}
+}
@@ -0,0 +1,342 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Chunking strategy names, as used in CHUNK_STRATEGIES and the drone's chunking.strategies
+const (
+	StrategyProse    = "prose"    // The default chunker's sliding window
+	StrategyMarkdown = "markdown" // One chunk per heading section
+	StrategyCode     = "code"     // Top-level blocks (functions, types) packed up to the chunk size
+	StrategyRows     = "rows"     // Whole rows packed up to the chunk size, each chunk repeating the header
+)
+
+// DefaultChunkStrategies maps file extensions to the strategy used for them; other files use prose
+var DefaultChunkStrategies = map[string]string{
+	".md":       StrategyMarkdown,
+	".markdown": StrategyMarkdown,
+	".go":       StrategyCode,
+	".py":       StrategyCode,
+	".js":       StrategyCode,
+	".ts":       StrategyCode,
+	".java":     StrategyCode,
+	".c":        StrategyCode,
+	".h":        StrategyCode,
+	".cpp":      StrategyCode,
+	".cs":       StrategyCode,
+	".rb":       StrategyCode,
+	".rs":       StrategyCode,
+	".php":      StrategyCode,
+	".sh":       StrategyCode,
+	".csv":      StrategyRows,
+	".tsv":      StrategyRows,
+	".xlsx":     StrategyRows,
+	".xls":      StrategyRows,
+}
+
+// TextChunker splits text into chunks; *Chunker and the drone's parser.Chunker implement it
+type TextChunker interface {
+	ChunkText(text string) ([]string, error)
+}
+
+// ChunkerRegistry picks a chunking strategy by file extension, falling back to a default chunker
+type ChunkerRegistry struct {
+	fallback TextChunker
+	maxChars int
+	byExt    map[string]TextChunker
+}
+
+// NewChunkerRegistry creates a registry with DefaultChunkStrategies. fallback chunks prose and files
+// without a strategy, and splits sections or blocks longer than maxChars characters.
+func NewChunkerRegistry(fallback TextChunker, maxChars int) *ChunkerRegistry {
+	r := &ChunkerRegistry{fallback: fallback, maxChars: maxChars, byExt: map[string]TextChunker{}}
+	for ext, strategy := range DefaultChunkStrategies {
+		r.byExt[ext] = r.strategy(strategy)
+	}
+	return r
+}
+
+// NewChunkerRegistryFromEnv creates a registry around chunker, applying CHUNK_STRATEGIES overrides
+func NewChunkerRegistryFromEnv(chunker *Chunker) (*ChunkerRegistry, error) {
+	chunkSize, _ := chunker.Settings()
+	r := NewChunkerRegistry(chunker, chunkSize)
+	overrides, err := ParseChunkStrategies(os.Getenv("CHUNK_STRATEGIES"))
+	if err != nil {
+		return r, err
+	}
+	return r, r.SetStrategies(overrides)
+}
+
+// ParseChunkStrategies reads a comma-separated list of extension=strategy pairs (e.g. ".log=prose,.txt=markdown")
+func ParseChunkStrategies(spec string) (map[string]string, error) {
+	strategies := map[string]string{}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		ext, strategy, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid chunk strategy %q, expected extension=strategy", pair)
+		}
+		strategies[ext] = strategy
+	}
+	return strategies, nil
+}
+
+// SetStrategies assigns strategies to extensions (with or without the leading dot), replacing the defaults for them
+func (r *ChunkerRegistry) SetStrategies(strategies map[string]string) error {
+	for ext, strategy := range strategies {
+		strategy = strings.ToLower(strings.TrimSpace(strategy))
+		chunker := r.strategy(strategy)
+		if chunker == nil {
+			return fmt.Errorf("unknown chunk strategy %q for %s (use %s, %s, %s or %s)", strategy, ext, StrategyProse, StrategyMarkdown, StrategyCode, StrategyRows)
+		}
+		r.byExt[normalizeExt(ext)] = chunker
+	}
+	return nil
+}
+
+// Strategies returns the strategy name used for each extension that doesn't use prose, sorted by extension
+func (r *ChunkerRegistry) Strategies() []string {
+	var pairs []string
+	for ext, chunker := range r.byExt {
+		if name := strategyName(chunker); name != StrategyProse {
+			pairs = append(pairs, ext+"="+name)
+		}
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// ForFile returns the chunker for a file name or path
+func (r *ChunkerRegistry) ForFile(filename string) TextChunker {
+	if chunker, ok := r.byExt[normalizeExt(filepath.Ext(filename))]; ok {
+		return chunker
+	}
+	return r.fallback
+}
+
+// ChunkFile splits text with the chunker for filename
+func (r *ChunkerRegistry) ChunkFile(filename, text string) ([]string, error) {
+	return r.ForFile(filename).ChunkText(text)
+}
+
+// strategy returns the chunker for a strategy name, or nil if the name is unknown
+func (r *ChunkerRegistry) strategy(name string) TextChunker {
+	switch name {
+	case StrategyProse:
+		return r.fallback
+	case StrategyMarkdown:
+		return &markdownChunker{fallback: r.fallback, maxChars: r.maxChars}
+	case StrategyCode:
+		return &codeChunker{fallback: r.fallback, maxChars: r.maxChars}
+	case StrategyRows:
+		return &rowChunker{maxChars: r.maxChars}
+	}
+	return nil
+}
+
+// strategyName names the strategy a registered chunker implements
+func strategyName(chunker TextChunker) string {
+	switch chunker.(type) {
+	case *markdownChunker:
+		return StrategyMarkdown
+	case *codeChunker:
+		return StrategyCode
+	case *rowChunker:
+		return StrategyRows
+	}
+	return StrategyProse
+}
+
+// normalizeExt lowercases an extension and adds the leading dot
+func normalizeExt(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+// markdownChunker makes one chunk per heading section (the heading and the text up to the next heading);
+// sections longer than maxChars are split further by the fallback chunker
+type markdownChunker struct {
+	fallback TextChunker
+	maxChars int
+}
+
+func (c *markdownChunker) ChunkText(text string) ([]string, error) {
+	var sections []string
+	var current []string
+	inFence := false
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		// A # inside a fenced code block is a comment, not a heading
+		if !inFence && isMarkdownHeading(line) && len(current) > 0 {
+			sections = append(sections, strings.Join(current, "\n"))
+			current = nil
+		}
+		current = append(current, line)
+	}
+	sections = append(sections, strings.Join(current, "\n"))
+	return splitOversized(sections, c.fallback, c.maxChars)
+}
+
+// isMarkdownHeading reports whether line is an ATX heading ("# Title" to "###### Title")
+func isMarkdownHeading(line string) bool {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	return level >= 1 && level <= 6 && (level == len(line) || line[level] == ' ' || line[level] == '\t')
+}
+
+// codeChunker splits source code into top-level blocks: runs of lines separated by a blank line followed
+// by an unindented line, so a function and its doc comment stay together even with blank lines in its body.
+// Consecutive blocks are packed into chunks of up to maxChars; a block longer than that is split by the fallback.
+type codeChunker struct {
+	fallback TextChunker
+	maxChars int
+}
+
+func (c *codeChunker) ChunkText(text string) ([]string, error) {
+	var blocks []string
+	var current []string
+	blank := false
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			blank = true
+			current = append(current, line)
+			continue
+		}
+		if blank && startsTopLevelBlock(line) && len(current) > 0 {
+			blocks = append(blocks, strings.Join(current, "\n"))
+			current = nil
+		}
+		blank = false
+		current = append(current, line)
+	}
+	blocks = append(blocks, strings.Join(current, "\n"))
+
+	var packed []string
+	var chunk strings.Builder
+	for _, block := range blocks {
+		block = strings.TrimSpace(block)
+		if block == "" {
+			continue
+		}
+		if chunk.Len() > 0 && chunk.Len()+2+len(block) > c.maxChars {
+			packed = append(packed, chunk.String())
+			chunk.Reset()
+		}
+		if chunk.Len() > 0 {
+			chunk.WriteString("\n\n")
+		}
+		chunk.WriteString(block)
+	}
+	if chunk.Len() > 0 {
+		packed = append(packed, chunk.String())
+	}
+	return splitOversized(packed, c.fallback, c.maxChars)
+}
+
+// startsTopLevelBlock reports whether a line after a blank line starts a new block: it is unindented
+// and doesn't close a bracket left open by the previous block
+func startsTopLevelBlock(line string) bool {
+	switch line[0] {
+	case ' ', '\t', '}', ')', ']':
+		return false
+	}
+	return true
+}
+
+// rowChunker packs whole rows (lines) into chunks of up to maxChars. For CSV the first line is the
+// header; for spreadsheets parsed by the drone each "Sheet: " line starts a new sheet. Every chunk
+// begins with its header so the rows can be read on their own.
+type rowChunker struct {
+	maxChars int
+}
+
+func (c *rowChunker) ChunkText(text string) ([]string, error) {
+	chunks := []string{}
+	header := ""
+	headerSeen := false
+	var rows []string
+	size := 0
+	flush := func() {
+		if len(rows) == 0 {
+			return
+		}
+		chunk := strings.Join(rows, "\n")
+		if header != "" {
+			chunk = header + "\n" + chunk
+		}
+		chunks = append(chunks, chunk)
+		rows = nil
+		size = len(header)
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "Sheet: ") {
+			flush()
+			header, headerSeen = line, true
+			size = len(header)
+			continue
+		}
+		if !headerSeen {
+			// Parsed spreadsheets label every row; anything else is CSV with a header line
+			headerSeen = true
+			if !strings.HasPrefix(line, "Row ") {
+				header = line
+				size = len(header)
+				continue
+			}
+		}
+		if len(rows) > 0 && size+1+len(line) > c.maxChars {
+			flush()
+		}
+		rows = append(rows, line)
+		size += 1 + len(line)
+	}
+	flush()
+	if len(chunks) == 0 && header != "" {
+		chunks = []string{header}
+	}
+	return chunks, nil
+}
+
+// splitOversized returns the non-empty pieces trimmed, splitting any longer than maxChars with the fallback chunker
+func splitOversized(pieces []string, fallback TextChunker, maxChars int) ([]string, error) {
+	chunks := []string{}
+	for _, piece := range pieces {
+		piece = strings.TrimSpace(piece)
+		if piece == "" {
+			continue
+		}
+		if maxChars <= 0 || len(piece) <= maxChars {
+			chunks = append(chunks, piece)
+			continue
+		}
+		split, err := fallback.ChunkText(piece)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, split...)
+	}
+	return chunks, nil
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package processor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const goSource = `package billing
+
+import "fmt"
+
+// Invoice is a bill sent to a customer
+type Invoice struct {
+	ID    string
+	Total int
+}
+
+// Describe formats the invoice for display
+func Describe(inv Invoice) string {
+	label := fmt.Sprintf("Invoice %s", inv.ID)
+
+	return fmt.Sprintf("%s: %d", label, inv.Total)
+}
+
+// Late reports whether the invoice is overdue
+func Late(days int) bool {
+	return days > 30
+}
+`
+
+func TestChunkerRegistry_CodeChunksByTopLevelBlocks(t *testing.T) {
+	registry := NewChunkerRegistry(NewChunkerWithSettings(200, 20), 200)
+
+	chunks, err := registry.ChunkFile("src/billing/invoice.go", goSource)
+	if err != nil {
+		t.Fatalf("ChunkFile failed: %v", err)
+	}
+	want := []string{
+		"package billing\n\nimport \"fmt\"\n\n// Invoice is a bill sent to a customer\ntype Invoice struct {\n\tID    string\n\tTotal int\n}",
+		"// Describe formats the invoice for display\nfunc Describe(inv Invoice) string {\n\tlabel := fmt.Sprintf(\"Invoice %s\", inv.ID)\n\n\treturn fmt.Sprintf(\"%s: %d\", label, inv.Total)\n}",
+		"// Late reports whether the invoice is overdue\nfunc Late(days int) bool {\n\treturn days > 30\n}",
+	}
+	if !reflect.DeepEqual(chunks, want) {
+		t.Errorf("Expected each declaration with its doc comment as a chunk (small blocks packed together), got %q", chunks)
+	}
+
+	// The prose chunker would cut through the function instead
+	prose, _ := registry.ChunkFile("notes.txt", goSource)
+	if reflect.DeepEqual(prose, want) {
+		t.Error("Expected a .txt file to use the prose chunker")
+	}
+}
+
+func TestChunkerRegistry_MarkdownChunksByHeadings(t *testing.T) {
+	registry := NewChunkerRegistry(NewChunkerWithSettings(200, 20), 200)
+	text := "Intro before any heading.\n\n# Install\nRun the installer.\n\n```sh\n# not a heading\nmake install\n```\n\n## Configure\nEdit the config file.\n\n# Usage\n" +
+		strings.Repeat("Run the drone against a folder. ", 10)
+
+	chunks, err := registry.ChunkFile("docs/README.MD", text)
+	if err != nil {
+		t.Fatalf("ChunkFile failed: %v", err)
+	}
+	if len(chunks) < 5 {
+		t.Fatalf("Expected the intro, three sections and the oversized section split further, got %q", chunks)
+	}
+	if chunks[0] != "Intro before any heading." {
+		t.Errorf("Expected the text before the first heading on its own, got %q", chunks[0])
+	}
+	if chunks[1] != "# Install\nRun the installer.\n\n```sh\n# not a heading\nmake install\n```" {
+		t.Errorf("Expected the Install section with its code block, got %q", chunks[1])
+	}
+	if chunks[2] != "## Configure\nEdit the config file." {
+		t.Errorf("Expected the Configure section, got %q", chunks[2])
+	}
+	for _, chunk := range chunks[3:] {
+		if len(chunk) > 200 {
+			t.Errorf("Expected the Usage section split to the chunk size, got %d characters", len(chunk))
+		}
+	}
+	if !strings.HasPrefix(chunks[3], "# Usage") {
+		t.Errorf("Expected the Usage section next, got %q", chunks[3])
+	}
+}
+
+func TestChunkerRegistry_RowsRepeatTheHeader(t *testing.T) {
+	registry := NewChunkerRegistry(NewChunker(), 40)
+
+	chunks, _ := registry.ChunkFile("sales.csv", "region,total\nnorth,10\nsouth,20\neast,30\nwest,40\n")
+	want := []string{"region,total\nnorth,10\nsouth,20\neast,30", "region,total\nwest,40"}
+	if !reflect.DeepEqual(chunks, want) {
+		t.Errorf("Expected whole rows under the CSV header, got %q", chunks)
+	}
+
+	chunks, _ = registry.ChunkFile("sales.xlsx", "Sheet: Q1\nRow 1: Region: north\nRow 2: Region: south\n\nSheet: Q2\nRow 1: Region: east\n")
+	want = []string{"Sheet: Q1\nRow 1: Region: north", "Sheet: Q1\nRow 2: Region: south", "Sheet: Q2\nRow 1: Region: east"}
+	if !reflect.DeepEqual(chunks, want) {
+		t.Errorf("Expected rows under their sheet name, got %q", chunks)
+	}
+}
+
+func TestChunkerRegistry_StrategyOverrides(t *testing.T) {
+	registry := NewChunkerRegistry(NewChunker(), 1000)
+	overrides, err := ParseChunkStrategies(".txt=markdown, md=prose")
+	if err != nil {
+		t.Fatalf("ParseChunkStrategies failed: %v", err)
+	}
+	if err := registry.SetStrategies(overrides); err != nil {
+		t.Fatalf("SetStrategies failed: %v", err)
+	}
+	if _, ok := registry.ForFile("a.txt").(*markdownChunker); !ok {
+		t.Error("Expected .txt to use the markdown strategy")
+	}
+	if registry.ForFile("a.md") != registry.fallback || registry.ForFile("a.unknown") != registry.fallback {
+		t.Error("Expected .md and unknown extensions to use the default chunker")
+	}
+
+	if err := registry.SetStrategies(map[string]string{".txt": "paragraphs"}); err == nil {
+		t.Error("Expected an unknown strategy to be rejected")
+	}
+	if _, err := ParseChunkStrategies(".txt"); err == nil {
+		t.Error("Expected a pair without a strategy to be rejected")
+	}
+}
@@ -342,6 +342,59 @@ func (x *Match) GetMetadata() map[string]string {
 	return nil
 }
 
+// Request to delete an ingested document
+type DeleteDocumentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DocumentId    string                 `protobuf:"bytes,1,opt,name=document_id,json=documentId,proto3" json:"document_id,omitempty"`                                                     // ID of the document, as sent with its chunks
+	Metadata      map[string]string      `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Additional metadata (client_id, file_path, organization_id)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDocumentRequest) Reset() {
+	*x = DeleteDocumentRequest{}
+	mi := &file_hive_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDocumentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDocumentRequest) ProtoMessage() {}
+
+func (x *DeleteDocumentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hive_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDocumentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDocumentRequest) Descriptor() ([]byte, []int) {
+	return file_hive_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteDocumentRequest) GetDocumentId() string {
+	if x != nil {
+		return x.DocumentId
+	}
+	return ""
+}
+
+func (x *DeleteDocumentRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
 var File_hive_proto protoreflect.FileDescriptor
 
 const file_hive_proto_rawDesc = "" +
@@ -377,10 +430,18 @@ const file_hive_proto_rawDesc = "" +
 	"\bmetadata\x18\x05 \x03(\v2\x19.hive.Match.MetadataEntryR\bmetadata\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012P\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xbc\x01\n" +
+	"\x15DeleteDocumentRequest\x12\x1f\n" +
+	"\vdocument_id\x18\x01 \x01(\tR\n" +
+	"documentId\x12E\n" +
+	"\bmetadata\x18\x02 \x03(\v2).hive.DeleteDocumentRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\x8d\x01\n" +
 	"\x04Hive\x12#\n" +
 	"\x06Ingest\x12\v.hive.Chunk\x1a\f.hive.Status\x12#\n" +
-	"\x05Query\x12\f.hive.Search\x1a\f.hive.ResultB$Z\"github.com/the-hive/internal/protob\x06proto3"
+	"\x05Query\x12\f.hive.Search\x1a\f.hive.Result\x12;\n" +
+	"\x0eDeleteDocument\x12\x1b.hive.DeleteDocumentRequest\x1a\f.hive.StatusB$Z\"github.com/the-hive/internal/protob\x06proto3"
 
 var (
 	file_hive_proto_rawDescOnce sync.Once
@@ -394,29 +455,34 @@ func file_hive_proto_rawDescGZIP() []byte {
 	return file_hive_proto_rawDescData
 }
 
-var file_hive_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_hive_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_hive_proto_goTypes = []any{
-	(*Chunk)(nil),  // 0: hive.Chunk
-	(*Status)(nil), // 1: hive.Status
-	(*Search)(nil), // 2: hive.Search
-	(*Result)(nil), // 3: hive.Result
-	(*Match)(nil),  // 4: hive.Match
-	nil,            // 5: hive.Chunk.MetadataEntry
-	nil,            // 6: hive.Match.MetadataEntry
+	(*Chunk)(nil),                 // 0: hive.Chunk
+	(*Status)(nil),                // 1: hive.Status
+	(*Search)(nil),                // 2: hive.Search
+	(*Result)(nil),                // 3: hive.Result
+	(*Match)(nil),                 // 4: hive.Match
+	(*DeleteDocumentRequest)(nil), // 5: hive.DeleteDocumentRequest
+	nil,                           // 6: hive.Chunk.MetadataEntry
+	nil,                           // 7: hive.Match.MetadataEntry
+	nil,                           // 8: hive.DeleteDocumentRequest.MetadataEntry
 }
 var file_hive_proto_depIdxs = []int32{
-	5, // 0: hive.Chunk.metadata:type_name -> hive.Chunk.MetadataEntry
+	6, // 0: hive.Chunk.metadata:type_name -> hive.Chunk.MetadataEntry
 	4, // 1: hive.Result.matches:type_name -> hive.Match
-	6, // 2: hive.Match.metadata:type_name -> hive.Match.MetadataEntry
-	0, // 3: hive.Hive.Ingest:input_type -> hive.Chunk
-	2, // 4: hive.Hive.Query:input_type -> hive.Search
-	1, // 5: hive.Hive.Ingest:output_type -> hive.Status
-	3, // 6: hive.Hive.Query:output_type -> hive.Result
-	5, // [5:7] is the sub-list for method output_type
-	3, // [3:5] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	7, // 2: hive.Match.metadata:type_name -> hive.Match.MetadataEntry
+	8, // 3: hive.DeleteDocumentRequest.metadata:type_name -> hive.DeleteDocumentRequest.MetadataEntry
+	0, // 4: hive.Hive.Ingest:input_type -> hive.Chunk
+	2, // 5: hive.Hive.Query:input_type -> hive.Search
+	5, // 6: hive.Hive.DeleteDocument:input_type -> hive.DeleteDocumentRequest
+	1, // 7: hive.Hive.Ingest:output_type -> hive.Status
+	3, // 8: hive.Hive.Query:output_type -> hive.Result
+	1, // 9: hive.Hive.DeleteDocument:output_type -> hive.Status
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_hive_proto_init() }
@@ -430,7 +496,7 @@ func file_hive_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_hive_proto_rawDesc), len(file_hive_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
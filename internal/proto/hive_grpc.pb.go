@@ -19,8 +19,9 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Hive_Ingest_FullMethodName = "/hive.Hive/Ingest"
-	Hive_Query_FullMethodName  = "/hive.Hive/Query"
+	Hive_Ingest_FullMethodName         = "/hive.Hive/Ingest"
+	Hive_Query_FullMethodName          = "/hive.Hive/Query"
+	Hive_DeleteDocument_FullMethodName = "/hive.Hive/DeleteDocument"
 )
 
 // HiveClient is the client API for Hive service.
@@ -33,6 +34,8 @@ type HiveClient interface {
 	Ingest(ctx context.Context, in *Chunk, opts ...grpc.CallOption) (*Status, error)
 	// Query the Hive for relevant documents
 	Query(ctx context.Context, in *Search, opts ...grpc.CallOption) (*Result, error)
+	// Delete an ingested document's chunks and vectors from the Hive
+	DeleteDocument(ctx context.Context, in *DeleteDocumentRequest, opts ...grpc.CallOption) (*Status, error)
 }
 
 type hiveClient struct {
@@ -63,6 +66,16 @@ func (c *hiveClient) Query(ctx context.Context, in *Search, opts ...grpc.CallOpt
 	return out, nil
 }
 
+func (c *hiveClient) DeleteDocument(ctx context.Context, in *DeleteDocumentRequest, opts ...grpc.CallOption) (*Status, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Status)
+	err := c.cc.Invoke(ctx, Hive_DeleteDocument_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HiveServer is the server API for Hive service.
 // All implementations must embed UnimplementedHiveServer
 // for forward compatibility.
@@ -73,6 +86,8 @@ type HiveServer interface {
 	Ingest(context.Context, *Chunk) (*Status, error)
 	// Query the Hive for relevant documents
 	Query(context.Context, *Search) (*Result, error)
+	// Delete an ingested document's chunks and vectors from the Hive
+	DeleteDocument(context.Context, *DeleteDocumentRequest) (*Status, error)
 	mustEmbedUnimplementedHiveServer()
 }
 
@@ -89,6 +104,9 @@ func (UnimplementedHiveServer) Ingest(context.Context, *Chunk) (*Status, error)
 func (UnimplementedHiveServer) Query(context.Context, *Search) (*Result, error) {
 	return nil, status.Error(codes.Unimplemented, "method Query not implemented")
 }
+func (UnimplementedHiveServer) DeleteDocument(context.Context, *DeleteDocumentRequest) (*Status, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteDocument not implemented")
+}
 func (UnimplementedHiveServer) mustEmbedUnimplementedHiveServer() {}
 func (UnimplementedHiveServer) testEmbeddedByValue()              {}
 
@@ -146,6 +164,24 @@ func _Hive_Query_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Hive_DeleteDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDocumentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HiveServer).DeleteDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Hive_DeleteDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HiveServer).DeleteDocument(ctx, req.(*DeleteDocumentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Hive_ServiceDesc is the grpc.ServiceDesc for Hive service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -161,6 +197,10 @@ var Hive_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Query",
 			Handler:    _Hive_Query_Handler,
 		},
+		{
+			MethodName: "DeleteDocument",
+			Handler:    _Hive_DeleteDocument_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "hive.proto",
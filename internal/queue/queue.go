@@ -13,6 +13,9 @@ type Job struct {
 	Type      string          `json:"type"`
 	Payload   json.RawMessage `json:"payload"`
 	CreatedAt time.Time       `json:"createdAt"`
+	Key       string          `json:"key,omitempty"` // Optional ordering key; keyed workers run same-key jobs serially
+
+	raw string // The job as dequeued, so Ack can remove exactly that entry
 }
 
 // Queue defines the interface for job queues.
@@ -25,3 +28,43 @@ type Queue interface {
 	Dequeue(ctx context.Context) (Job, error)
 }
 
+// Acker is implemented by queues that keep a dequeued job until it is acknowledged.
+// Jobs that are never acknowledged (e.g. because the process stopped mid-job) are recovered on the next start.
+type Acker interface {
+	// Ack marks a dequeued job as done.
+	Ack(ctx context.Context, job Job) error
+}
+
+// Ack acknowledges a dequeued job if the queue supports it; other queues drop jobs as they are dequeued.
+func Ack(ctx context.Context, q Queue, job Job) error {
+	if acker, ok := q.(Acker); ok {
+		return acker.Ack(ctx, job)
+	}
+	return nil
+}
+
+// DeadJob is a job that failed every attempt, as kept on the dead-letter list
+type DeadJob struct {
+	Job      Job       `json:"job"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// DeadLetterer is implemented by queues that keep jobs which failed every attempt, so they can be
+// inspected and replayed by hand instead of being lost.
+type DeadLetterer interface {
+	// DeadLetter records a job that failed attempts times, the last time with cause.
+	DeadLetter(ctx context.Context, job Job, cause error, attempts int) error
+
+	// DeadLetters returns up to limit dead-lettered jobs, newest first.
+	DeadLetters(ctx context.Context, limit int) ([]DeadJob, error)
+}
+
+// DeadLetter dead-letters a job if the queue supports it; other queues drop failed jobs.
+func DeadLetter(ctx context.Context, q Queue, job Job, cause error, attempts int) error {
+	if deadLetterer, ok := q.(DeadLetterer); ok {
+		return deadLetterer.DeadLetter(ctx, job, cause, attempts)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultReconnectInterval is how often ReconnectingQueue retries Redis while it can't be reached
+const DefaultReconnectInterval = 30 * time.Second
+
+// ErrNotConnected is returned while a ReconnectingQueue hasn't reached Redis yet
+var ErrNotConnected = errors.New("job queue is not connected to Redis")
+
+// ReconnectingQueue is a Queue that connects to Redis in the background, retrying until it succeeds,
+// so the server can start before Redis is up. Enqueue and Ack fail with ErrNotConnected until then;
+// Dequeue waits for the connection. Once connected, go-redis reconnects on its own.
+type ReconnectingQueue struct {
+	connect  func(ctx context.Context) (*RedisQueue, error)
+	interval time.Duration
+
+	mu      sync.RWMutex
+	queue   *RedisQueue
+	lastErr error
+	ready   chan struct{} // Closed once connected
+}
+
+// NewReconnectingQueue creates a queue that calls connect until it returns a queue, waiting interval
+// between attempts (DefaultReconnectInterval if zero or less). Call Start to begin connecting.
+func NewReconnectingQueue(connect func(ctx context.Context) (*RedisQueue, error), interval time.Duration) *ReconnectingQueue {
+	if interval <= 0 {
+		interval = DefaultReconnectInterval
+	}
+	return &ReconnectingQueue{
+		connect:  connect,
+		interval: interval,
+		lastErr:  ErrNotConnected,
+		ready:    make(chan struct{}),
+	}
+}
+
+// Start makes the first connection attempt before returning, then keeps retrying in the background
+// until connected or ctx is cancelled. It returns whether the first attempt connected.
+func (q *ReconnectingQueue) Start(ctx context.Context) bool {
+	if q.tryConnect(ctx) {
+		return true
+	}
+	go func() {
+		ticker := time.NewTicker(q.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if q.tryConnect(ctx) {
+					return
+				}
+			}
+		}
+	}()
+	return false
+}
+
+// tryConnect makes one connection attempt. Only the first failure and changes in the error are
+// logged, so a server deliberately run without Redis doesn't log every interval.
+func (q *ReconnectingQueue) tryConnect(ctx context.Context) bool {
+	redisQueue, err := q.connect(ctx)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err != nil {
+		if q.lastErr == ErrNotConnected || q.lastErr.Error() != err.Error() {
+			log.Printf("Job queue: Redis unavailable, retrying every %s: %v", q.interval, err)
+		}
+		q.lastErr = err
+		return false
+	}
+	q.queue = redisQueue
+	q.lastErr = nil
+	close(q.ready)
+	log.Printf("Job queue: connected to Redis")
+	return true
+}
+
+// Ready returns a channel that is closed once the queue has connected
+func (q *ReconnectingQueue) Ready() <-chan struct{} {
+	return q.ready
+}
+
+// Connected reports whether the queue has connected to Redis
+func (q *ReconnectingQueue) Connected() bool {
+	return q.current() != nil
+}
+
+// Ping reports why the queue can't be used: it hasn't connected yet, or Redis doesn't answer now
+func (q *ReconnectingQueue) Ping(ctx context.Context) error {
+	q.mu.RLock()
+	redisQueue, lastErr := q.queue, q.lastErr
+	q.mu.RUnlock()
+	if redisQueue == nil {
+		if lastErr == ErrNotConnected {
+			return lastErr
+		}
+		return fmt.Errorf("%w: %v", ErrNotConnected, lastErr)
+	}
+	return redisQueue.Ping(ctx)
+}
+
+// Client returns the connected Redis client, or nil until the queue has connected, so other Redis
+// users can share the connection once Redis comes up
+func (q *ReconnectingQueue) Client() *redis.Client {
+	if redisQueue := q.current(); redisQueue != nil {
+		return redisQueue.Client()
+	}
+	return nil
+}
+
+func (q *ReconnectingQueue) current() *RedisQueue {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.queue
+}
+
+// Enqueue adds a job to the queue, or returns ErrNotConnected
+func (q *ReconnectingQueue) Enqueue(ctx context.Context, job Job) error {
+	redisQueue := q.current()
+	if redisQueue == nil {
+		return ErrNotConnected
+	}
+	return redisQueue.Enqueue(ctx, job)
+}
+
+// Dequeue waits for the connection, then for a job
+func (q *ReconnectingQueue) Dequeue(ctx context.Context) (Job, error) {
+	select {
+	case <-ctx.Done():
+		return Job{}, ctx.Err()
+	case <-q.ready:
+	}
+	return q.current().Dequeue(ctx)
+}
+
+// Ack acknowledges a dequeued job
+func (q *ReconnectingQueue) Ack(ctx context.Context, job Job) error {
+	redisQueue := q.current()
+	if redisQueue == nil {
+		return ErrNotConnected
+	}
+	return redisQueue.Ack(ctx, job)
+}
+
+// DeadLetter dead-letters a failed job, or returns ErrNotConnected
+func (q *ReconnectingQueue) DeadLetter(ctx context.Context, job Job, cause error, attempts int) error {
+	redisQueue := q.current()
+	if redisQueue == nil {
+		return ErrNotConnected
+	}
+	return redisQueue.DeadLetter(ctx, job, cause, attempts)
+}
+
+// DeadLetters returns the newest dead-lettered jobs, or ErrNotConnected
+func (q *ReconnectingQueue) DeadLetters(ctx context.Context, limit int) ([]DeadJob, error) {
+	redisQueue := q.current()
+	if redisQueue == nil {
+		return nil, ErrNotConnected
+	}
+	return redisQueue.DeadLetters(ctx, limit)
+}
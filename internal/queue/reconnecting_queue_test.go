@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestReconnectingQueue_ConnectsInTheBackground(t *testing.T) {
+	var attempts int32
+	connected := &RedisQueue{client: redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"}), key: "jobs:test", processingKey: "jobs:test:processing"}
+	q := NewReconnectingQueue(func(ctx context.Context) (*RedisQueue, error) {
+		// Redis comes up on the third attempt
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return nil, errors.New("connection refused")
+		}
+		return connected, nil
+	}, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if q.Start(ctx) {
+		t.Fatal("Expected the first attempt to fail")
+	}
+	if q.Connected() || q.Client() != nil {
+		t.Error("Expected the queue to report it isn't connected")
+	}
+	if err := q.Enqueue(ctx, Job{Type: "test_job"}); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Expected Enqueue to fail with ErrNotConnected, got %v", err)
+	}
+	if err := q.Ping(ctx); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Expected Ping to report the missing connection, got %v", err)
+	}
+
+	select {
+	case <-q.Ready():
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Expected the queue to connect once Redis is up, %d attempts so far", atomic.LoadInt32(&attempts))
+	}
+	if !q.Connected() || q.current() != connected || q.Client() != connected.client {
+		t.Error("Expected the queue to use the connected Redis queue")
+	}
+	if n := atomic.LoadInt32(&attempts); n != 3 {
+		t.Errorf("Expected retries to stop once connected, got %d attempts", n)
+	}
+}
+
+func TestReconnectingQueue_DequeueWaitsForConnection(t *testing.T) {
+	q := NewReconnectingQueue(func(ctx context.Context) (*RedisQueue, error) {
+		return nil, errors.New("connection refused")
+	}, time.Hour)
+	q.Start(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := q.Dequeue(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected Dequeue to wait until the context ends, got %v", err)
+	}
+}
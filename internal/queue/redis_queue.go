@@ -5,6 +5,7 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -12,16 +13,26 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// DeadLetterKey is the Redis list jobs that failed every attempt are pushed onto
+const DeadLetterKey = "jobs:dead"
+
+// maxDeadLetters is how many dead-lettered jobs are kept; older ones are trimmed
+const maxDeadLetters = 1000
+
 // RedisQueue implements Queue using Redis Lists.
+// Dequeue moves each job to a processing list where it stays until Ack, so a job in flight when the
+// process stops is not lost: RecoverInFlight puts it back on the queue at the next start.
 type RedisQueue struct {
-	client *redis.Client
-	key    string
+	client        *redis.Client
+	key           string
+	processingKey string
+	deadKey       string
 }
 
 // NewRedisQueue creates a new Redis-backed queue.
 // client: the Redis client to use
 // key: the Redis key name for the queue (e.g., "jobs:default")
-func NewRedisQueue(client *redis.Client, key string) (Queue, error) {
+func NewRedisQueue(client *redis.Client, key string) (*RedisQueue, error) {
 	if key == "" {
 		key = "jobs:default"
 	}
@@ -36,11 +47,23 @@ func NewRedisQueue(client *redis.Client, key string) (Queue, error) {
 	}
 
 	return &RedisQueue{
-		client: client,
-		key:    key,
+		client:        client,
+		key:           key,
+		processingKey: key + ":processing",
+		deadKey:       DeadLetterKey,
 	}, nil
 }
 
+// Client returns the Redis client the queue uses
+func (r *RedisQueue) Client() *redis.Client {
+	return r.client
+}
+
+// Ping checks that Redis answers
+func (r *RedisQueue) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
 // Enqueue adds a job to the queue using RPUSH.
 func (r *RedisQueue) Enqueue(ctx context.Context, job Job) error {
 	log.Printf("Enqueue: job type=%s createdAt=%s", job.Type, job.CreatedAt.Format(time.RFC3339))
@@ -62,19 +85,20 @@ func (r *RedisQueue) Enqueue(ctx context.Context, job Job) error {
 	return nil
 }
 
-// Dequeue blocks until a job is available using BLPOP, then returns it.
+// Dequeue blocks until a job is available using BLMOVE, then returns it.
+// The job stays in the processing list until it is acknowledged with Ack.
 func (r *RedisQueue) Dequeue(ctx context.Context) (Job, error) {
 	log.Printf("Dequeue: waiting for job from key=%s", r.key)
 
 	// Use a channel to handle context cancellation
 	type result struct {
-		val []string
+		val string
 		err error
 	}
 	resultChan := make(chan result, 1)
 
 	go func() {
-		val, err := r.client.BLPop(ctx, 0, r.key).Result()
+		val, err := r.client.BLMove(ctx, r.key, r.processingKey, "LEFT", "RIGHT", 0).Result()
 		resultChan <- result{val: val, err: err}
 	}()
 
@@ -92,22 +116,97 @@ func (r *RedisQueue) Dequeue(ctx context.Context) (Job, error) {
 			return Job{}, res.err
 		}
 
-		if len(res.val) < 2 {
-			log.Printf("Dequeue: invalid result from Redis, expected 2 elements, got %d", len(res.val))
-			return Job{}, fmt.Errorf("invalid result from Redis")
-		}
-
-		data := res.val[1]
+		data := res.val
 		log.Printf("Dequeue: received job payloadSize=%d", len(data))
 
 		var job Job
 		if err := json.Unmarshal([]byte(data), &job); err != nil {
 			log.Printf("Dequeue: failed to unmarshal job: %v", err)
+			// Nothing can ever process it, so don't leave it to be recovered
+			r.client.LRem(context.Background(), r.processingKey, 1, data)
 			return Job{}, err
 		}
+		job.raw = data
 
 		log.Printf("Dequeue: successfully dequeued job type=%s createdAt=%s", job.Type, job.CreatedAt.Format(time.RFC3339))
 		return job, nil
 	}
 }
 
+// Ack removes a dequeued job from the processing list.
+func (r *RedisQueue) Ack(ctx context.Context, job Job) error {
+	if job.raw == "" {
+		return fmt.Errorf("job type=%s was not dequeued from this queue", job.Type)
+	}
+	if err := r.client.LRem(ctx, r.processingKey, 1, job.raw).Err(); err != nil {
+		log.Printf("Ack: failed to remove job type=%s from %s: %v", job.Type, r.processingKey, err)
+		return err
+	}
+	return nil
+}
+
+// RecoverInFlight moves jobs left in the processing list by a previous run back to the front of the queue
+// and returns how many were moved. Call it before starting workers; with several servers sharing one queue
+// key it would also requeue jobs the other servers are still running.
+func (r *RedisQueue) RecoverInFlight(ctx context.Context) (int, error) {
+	recovered := 0
+	for {
+		// Taking the newest first and pushing each to the front keeps their original order
+		err := r.client.LMove(ctx, r.processingKey, r.key, "RIGHT", "LEFT").Err()
+		if err == redis.Nil {
+			break
+		}
+		if err != nil {
+			return recovered, err
+		}
+		recovered++
+	}
+	if recovered > 0 {
+		log.Printf("RecoverInFlight: requeued %d unfinished jobs from %s", recovered, r.processingKey)
+	}
+	return recovered, nil
+}
+
+// DeadLetter pushes a job that failed every attempt onto the dead-letter list with its error and
+// attempt count, keeping the newest maxDeadLetters.
+func (r *RedisQueue) DeadLetter(ctx context.Context, job Job, cause error, attempts int) error {
+	if cause == nil {
+		cause = errors.New("unknown error")
+	}
+	data, err := json.Marshal(DeadJob{Job: job, Error: cause.Error(), Attempts: attempts, FailedAt: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.RPush(ctx, r.deadKey, data)
+	pipe.LTrim(ctx, r.deadKey, -maxDeadLetters, -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("DeadLetter: failed to push job type=%s to %s: %v", job.Type, r.deadKey, err)
+		return err
+	}
+	log.Printf("DeadLetter: job type=%s failed %d attempts, moved to %s", job.Type, attempts, r.deadKey)
+	return nil
+}
+
+// DeadLetters returns up to limit dead-lettered jobs, newest first.
+func (r *RedisQueue) DeadLetters(ctx context.Context, limit int) ([]DeadJob, error) {
+	if limit <= 0 || limit > maxDeadLetters {
+		limit = maxDeadLetters
+	}
+	values, err := r.client.LRange(ctx, r.deadKey, int64(-limit), -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	deadJobs := make([]DeadJob, 0, len(values))
+	for i := len(values) - 1; i >= 0; i-- {
+		var deadJob DeadJob
+		if err := json.Unmarshal([]byte(values[i]), &deadJob); err != nil {
+			log.Printf("DeadLetters: skipping unreadable entry in %s: %v", r.deadKey, err)
+			continue
+		}
+		deadJobs = append(deadJobs, deadJob)
+	}
+	return deadJobs, nil
+}
@@ -5,6 +5,7 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"strconv"
 	"testing"
 	"time"
@@ -154,3 +155,89 @@ func TestRedisQueue_ContextCancellation(t *testing.T) {
 		t.Errorf("Expected context.Canceled, got %v", err)
 	}
 }
+
+func TestRedisQueue_UnacknowledgedJobIsRecovered(t *testing.T) {
+	// Skip if Redis is not available
+	ctx := context.Background()
+	client, err := config.NewRedisClient(ctx)
+	if err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+
+	queueKey := "test:queue:recover:" + time.Now().Format("20060102150405")
+	q, err := NewRedisQueue(client, queueKey)
+	if err != nil {
+		t.Fatalf("NewRedisQueue failed: %v", err)
+	}
+	defer client.Del(ctx, queueKey, queueKey+":processing")
+
+	for _, jobType := range []string{"finished", "interrupted"} {
+		if err := q.Enqueue(ctx, Job{Type: jobType, Payload: []byte(`{}`), CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+
+	dequeueCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	finished, err := q.Dequeue(dequeueCtx)
+	if err != nil {
+		t.Fatalf("Dequeue failed: %v", err)
+	}
+	if err := q.Ack(ctx, finished); err != nil {
+		t.Fatalf("Ack failed: %v", err)
+	}
+	// Dequeued but never acknowledged, as when the server stops mid-job
+	if _, err := q.Dequeue(dequeueCtx); err != nil {
+		t.Fatalf("Dequeue failed: %v", err)
+	}
+
+	// The next start recovers only the unfinished job
+	restarted, err := NewRedisQueue(client, queueKey)
+	if err != nil {
+		t.Fatalf("NewRedisQueue failed: %v", err)
+	}
+	recovered, err := restarted.RecoverInFlight(ctx)
+	if err != nil || recovered != 1 {
+		t.Fatalf("Expected 1 recovered job, got %d, %v", recovered, err)
+	}
+	job, err := restarted.Dequeue(dequeueCtx)
+	if err != nil {
+		t.Fatalf("Dequeue failed: %v", err)
+	}
+	if job.Type != "interrupted" {
+		t.Errorf("Expected the interrupted job back, got %s", job.Type)
+	}
+}
+
+func TestRedisQueue_DeadLetter(t *testing.T) {
+	// Skip if Redis is not available
+	ctx := context.Background()
+	client, err := config.NewRedisClient(ctx)
+	if err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+
+	queueKey := "test:queue:dead:" + time.Now().Format("20060102150405.000")
+	q, err := NewRedisQueue(client, queueKey)
+	if err != nil {
+		t.Fatalf("NewRedisQueue failed: %v", err)
+	}
+	// Keep the test's entries off the real dead-letter list
+	q.deadKey = queueKey + ":dead"
+	defer client.Del(ctx, q.deadKey)
+
+	for i, attempts := range []int{3, 5} {
+		job := Job{Type: "test_job", Key: strconv.Itoa(i), CreatedAt: time.Now()}
+		if err := q.DeadLetter(ctx, job, errors.New("boom "+strconv.Itoa(i)), attempts); err != nil {
+			t.Fatalf("DeadLetter failed: %v", err)
+		}
+	}
+
+	dead, err := q.DeadLetters(ctx, 10)
+	if err != nil {
+		t.Fatalf("DeadLetters failed: %v", err)
+	}
+	if len(dead) != 2 || dead[0].Job.Key != "1" || dead[0].Attempts != 5 || dead[0].Error != "boom 1" || dead[1].Job.Key != "0" {
+		t.Errorf("Expected both jobs newest first with their error and attempts, got %+v", dead)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package rules
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/the-hive/internal/database"
+)
+
+// DefaultRule is a rule in the template seeded into every new organization
+type DefaultRule struct {
+	ID     int64  `json:"id"`
+	Query  string `json:"query"`
+	Kind   string `json:"kind"`  // semantic or keyword
+	Scope  string `json:"scope"` // single_doc, cross_doc or auto
+	Active bool   `json:"active"`
+}
+
+// BuiltInDefaultRules is the template the default_rules table starts with; super admins can edit it from there
+var BuiltInDefaultRules = []DefaultRule{
+	{Query: "CONFIDENTIAL", Kind: KindKeyword, Scope: ScopeAuto, Active: true},
+	{
+		Query:  "Does this document contain personally identifiable information (PII) such as home addresses, phone numbers, government ID numbers or bank account numbers?",
+		Kind:   KindSemantic,
+		Scope:  ScopeSingleDoc,
+		Active: true,
+	},
+}
+
+// createDefaultRulesTable creates the template table and fills it with BuiltInDefaultRules
+func createDefaultRulesTable(tx *sql.Tx) error {
+	if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS default_rules (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		query TEXT NOT NULL,
+		kind TEXT NOT NULL DEFAULT '` + KindSemantic + `',
+		scope TEXT NOT NULL DEFAULT '` + ScopeAuto + `',
+		active BOOLEAN NOT NULL DEFAULT 1,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return err
+	}
+	for _, rule := range BuiltInDefaultRules {
+		if _, err := tx.Exec("INSERT INTO default_rules (query, kind, scope, active) VALUES (?, ?, ?, ?)", rule.Query, rule.Kind, rule.Scope, rule.Active); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetDefaultRules returns the template seeded into new organizations, in the order it was saved
+func (s *Store) GetDefaultRules(ctx context.Context) ([]DefaultRule, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, query, kind, scope, active FROM default_rules ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	defaults := []DefaultRule{}
+	for rows.Next() {
+		var rule DefaultRule
+		if err := rows.Scan(&rule.ID, &rule.Query, &rule.Kind, &rule.Scope, &rule.Active); err != nil {
+			return nil, err
+		}
+		defaults = append(defaults, rule)
+	}
+	return defaults, rows.Err()
+}
+
+// SetDefaultRules replaces the template. An empty kind defaults to semantic and an empty scope to auto;
+// an empty list means new organizations start without rules. Existing organizations are unaffected.
+func (s *Store) SetDefaultRules(ctx context.Context, defaults []DefaultRule) ([]DefaultRule, error) {
+	normalized := make([]DefaultRule, 0, len(defaults))
+	for _, rule := range defaults {
+		rule.Query = strings.TrimSpace(rule.Query)
+		if rule.Query == "" {
+			return nil, fmt.Errorf("default rule query is required")
+		}
+		if rule.Kind == "" {
+			rule.Kind = KindSemantic
+		}
+		if rule.Kind != KindSemantic && rule.Kind != KindKeyword {
+			return nil, fmt.Errorf("invalid rule kind %q (expected %s or %s)", rule.Kind, KindSemantic, KindKeyword)
+		}
+		scope, err := normalizeScope(rule.Scope)
+		if err != nil {
+			return nil, err
+		}
+		rule.Scope = scope
+		normalized = append(normalized, rule)
+	}
+
+	err := database.RetryOnBusy(ctx, func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM default_rules"); err != nil {
+			return err
+		}
+		for _, rule := range normalized {
+			if _, err := tx.ExecContext(ctx, "INSERT INTO default_rules (query, kind, scope, active) VALUES (?, ?, ?, ?)", rule.Query, rule.Kind, rule.Scope, rule.Active); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetDefaultRules(ctx)
+}
+
+// DefaultKeywords returns the queries of the template's active keyword rules
+func (s *Store) DefaultKeywords(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT query FROM default_rules WHERE kind = ? AND active = 1 ORDER BY id", KindKeyword)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keywords []string
+	for rows.Next() {
+		var keyword string
+		if err := rows.Scan(&keyword); err != nil {
+			return nil, err
+		}
+		keywords = append(keywords, keyword)
+	}
+	return keywords, rows.Err()
+}
+
+// SeedDefaultRules copies the template into the organization's rules. A template rule the organization
+// already has (same kind and query, ignoring case) is skipped, so seeding twice adds nothing.
+// Returns the number of rules added.
+func (s *Store) SeedDefaultRules(ctx context.Context, organizationID string) (int, error) {
+	defaults, err := s.GetDefaultRules(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load default rules: %w", err)
+	}
+
+	added := 0
+	for _, rule := range defaults {
+		var result sql.Result
+		err := database.RetryOnBusy(ctx, func() error {
+			var execErr error
+			result, execErr = s.db.ExecContext(ctx,
+				`INSERT INTO rules (query, active, organization_id, kind, scope)
+				SELECT ?, ?, ?, ?, ?
+				WHERE NOT EXISTS (SELECT 1 FROM rules WHERE kind = ? AND organization_id = ? AND query = ? COLLATE NOCASE)`,
+				rule.Query, rule.Active, organizationID, rule.Kind, rule.Scope, rule.Kind, organizationID, rule.Query)
+			return execErr
+		})
+		if err != nil {
+			return added, fmt.Errorf("failed to seed default rule %q: %w", rule.Query, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			added++
+		}
+	}
+
+	if added > 0 {
+		if err := s.invalidateOrganization(organizationID); err != nil {
+			return added, err
+		}
+	}
+	return added, nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package rules
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/the-hive/internal/database"
+)
+
+// QueryEmbedding is the stored embedding of a rule's query
+type QueryEmbedding struct {
+	Query  string // The query text the embedding was computed from
+	Vector []float32
+}
+
+// SaveQueryEmbedding stores the embedding of a rule's query, replacing any previous one
+func (s *Store) SaveQueryEmbedding(ctx context.Context, ruleID int64, query string, vector []float32) error {
+	return database.RetryOnBusy(ctx, func() error {
+		_, err := s.db.ExecContext(ctx,
+			`INSERT INTO rule_embeddings (rule_id, query, embedding, updated_at) VALUES (?, ?, ?, ?)
+			ON CONFLICT(rule_id) DO UPDATE SET query = excluded.query, embedding = excluded.embedding, updated_at = excluded.updated_at`,
+			ruleID, query, encodeVector(vector), time.Now(),
+		)
+		return err
+	})
+}
+
+// GetQueryEmbeddings returns the stored query embeddings keyed by rule ID
+func (s *Store) GetQueryEmbeddings(ctx context.Context) (map[int64]QueryEmbedding, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT rule_id, query, embedding FROM rule_embeddings")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rule embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	embeddings := make(map[int64]QueryEmbedding)
+	for rows.Next() {
+		var ruleID int64
+		var query string
+		var blob []byte
+		if err := rows.Scan(&ruleID, &query, &blob); err != nil {
+			return nil, fmt.Errorf("failed to scan rule embedding: %w", err)
+		}
+		embeddings[ruleID] = QueryEmbedding{Query: query, Vector: decodeVector(blob)}
+	}
+	return embeddings, rows.Err()
+}
+
+// encodeVector packs a vector as little-endian float32s
+func encodeVector(vector []float32) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+// decodeVector unpacks a vector packed by encodeVector
+func decodeVector(buf []byte) []float32 {
+	vector := make([]float32, len(buf)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return vector
+}
@@ -8,13 +8,56 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/the-hive/internal/database"
+)
+
+// Rule kinds
+const (
+	KindSemantic = "semantic" // Yes/no question answered by the AI analyst
+	KindKeyword  = "keyword"  // Case-insensitive keyword match, no AI call
+)
+
+// Rule scopes decide which documents the analyst checks a rule against
+const (
+	ScopeSingleDoc = "single_doc" // Only the uploaded document
+	ScopeCrossDoc  = "cross_doc"  // The uploaded document against the organization's existing documents
+	ScopeAuto      = "auto"       // Decided from keywords in the query (e.g. "contradicts")
 )
 
+// ValidScope reports whether scope is a known rule scope
+func ValidScope(scope string) bool {
+	switch scope {
+	case ScopeSingleDoc, ScopeCrossDoc, ScopeAuto:
+		return true
+	}
+	return false
+}
+
+// normalizeScope defaults an empty scope to auto and rejects unknown ones
+func normalizeScope(scope string) (string, error) {
+	if scope == "" {
+		return ScopeAuto, nil
+	}
+	if !ValidScope(scope) {
+		return "", fmt.Errorf("invalid rule scope %q (expected %s, %s or %s)", scope, ScopeSingleDoc, ScopeCrossDoc, ScopeAuto)
+	}
+	return scope, nil
+}
+
 // Rule represents a semantic rule
 type Rule struct {
 	ID     int64  `json:"id"`
 	Query  string `json:"query"`
 	Active bool   `json:"active"`
+	Kind   string `json:"kind"`
+	Scope  string `json:"scope"`
+}
+
+// orgRulesEntry is an organization's cached active rules
+type orgRulesEntry struct {
+	rules    []Rule
+	loadedAt time.Time
 }
 
 // Store manages rules storage
@@ -23,12 +66,18 @@ type Store struct {
 	mu  sync.RWMutex
 	// In-memory cache for active rules
 	activeRules []Rule
+	// Per-organization cache of active rules, filled on first read and dropped when the org's rules change
+	orgRules      map[string]orgRulesEntry
+	orgGeneration map[string]uint64 // Bumped on invalidation so a read racing a change doesn't cache stale rules
+	cacheTTL      time.Duration     // Zero keeps entries until invalidated
 }
 
 // NewStore creates a new rules store
 func NewStore(db *sql.DB) (*Store, error) {
 	store := &Store{
-		db: db,
+		db:            db,
+		orgRules:      make(map[string]orgRulesEntry),
+		orgGeneration: make(map[string]uint64),
 	}
 
 	// Initialize schema
@@ -59,119 +108,172 @@ func (s *Store) initSchema() error {
 		return err
 	}
 	
-	// Migration: Add organization_id column if it doesn't exist
-	rows, err := s.db.Query("PRAGMA table_info(rules)")
-	if err == nil {
-		defer rows.Close()
-		hasOrgID := false
-		for rows.Next() {
-			var cid int
-			var name, dataType string
-			var notNull, pk int
-			var defaultValue interface{}
-			if err := rows.Scan(&cid, &name, &dataType, &notNull, &defaultValue, &pk); err == nil {
-				if name == "organization_id" {
-					hasOrgID = true
-					break
-				}
-			}
-		}
-		if !hasOrgID {
-			_, err = s.db.Exec("ALTER TABLE rules ADD COLUMN organization_id TEXT")
-			if err != nil {
-				return fmt.Errorf("failed to add organization_id to rules: %w", err)
-			}
-			_, err = s.db.Exec("CREATE INDEX IF NOT EXISTS idx_rules_organization_id ON rules(organization_id)")
-			if err != nil {
-				return fmt.Errorf("failed to create organization_id index on rules: %w", err)
-			}
-		} else {
-			// Column exists, ensure index exists
-			_, err = s.db.Exec("CREATE INDEX IF NOT EXISTS idx_rules_organization_id ON rules(organization_id)")
-			if err != nil {
-				return fmt.Errorf("failed to create organization_id index on rules: %w", err)
+	_, err = database.RunMigrations(s.db, "rules", rulesMigrations)
+	return err
+}
+
+// rulesMigrations are the versioned schema changes for rules
+var rulesMigrations = []database.Migration{
+	{
+		Version:     1,
+		Description: "add organization_id to rules",
+		Up: func(tx *sql.Tx) error {
+			if err := database.AddColumnIfMissing(tx, "rules", "organization_id", "TEXT"); err != nil {
+				return err
 			}
-		}
-	}
-	
-	return nil
+			_, err := tx.Exec("CREATE INDEX IF NOT EXISTS idx_rules_organization_id ON rules(organization_id)")
+			return err
+		},
+	},
+	{
+		Version:     2,
+		Description: "add kind to rules",
+		Up: func(tx *sql.Tx) error {
+			return database.AddColumnIfMissing(tx, "rules", "kind", "TEXT NOT NULL DEFAULT '"+KindSemantic+"'")
+		},
+	},
+	{
+		Version:     3,
+		Description: "add rule query embeddings",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS rule_embeddings (
+				rule_id INTEGER PRIMARY KEY,
+				query TEXT NOT NULL,
+				embedding BLOB NOT NULL,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`)
+			return err
+		},
+	},
+	{
+		Version:     4,
+		Description: "add scope to rules",
+		Up: func(tx *sql.Tx) error {
+			return database.AddColumnIfMissing(tx, "rules", "scope", "TEXT NOT NULL DEFAULT '"+ScopeAuto+"'")
+		},
+	},
+	{
+		Version:     5,
+		Description: "add default rule template",
+		Up:          createDefaultRulesTable,
+	},
 }
 
-// refreshCache refreshes the in-memory cache of active rules
-// If organizationID is provided, only caches rules for that organization
-func (s *Store) refreshCache(organizationID ...string) error {
+// SetCacheTTL sets how long an organization's active rules are cached. Changes made through this
+// store invalidate the cache immediately; a TTL also picks up changes made by other server instances.
+// Zero (the default) caches until invalidated.
+func (s *Store) SetCacheTTL(ttl time.Duration) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.cacheTTL = ttl
+}
 
+// refreshCache refreshes the in-memory cache of active rules across all organizations
+func (s *Store) refreshCache() error {
 	// Use context with timeout to prevent indefinite hanging
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var query string
-	var args []interface{}
-	if len(organizationID) > 0 && organizationID[0] != "" {
-		query = "SELECT id, query, active FROM rules WHERE active = 1 AND organization_id = ?"
-		args = []interface{}{organizationID[0]}
-	} else {
-		query = "SELECT id, query, active FROM rules WHERE active = 1"
-		args = []interface{}{}
+	rules, err := s.queryActiveRules(ctx, "SELECT id, query, active, kind, scope FROM rules WHERE active = 1")
+	if err != nil {
+		return err
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.activeRules = rules
+	return nil
+}
+
+// queryActiveRules runs a query selecting id, query, active, kind and scope
+func (s *Store) queryActiveRules(ctx context.Context, query string, args ...interface{}) ([]Rule, error) {
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
-			return fmt.Errorf("database query timed out: %w", err)
+			return nil, fmt.Errorf("database query timed out: %w", err)
 		}
-		return err
+		return nil, err
 	}
 	defer rows.Close()
 
 	var rules []Rule
 	for rows.Next() {
 		var rule Rule
-		if err := rows.Scan(&rule.ID, &rule.Query, &rule.Active); err != nil {
-			return err
+		if err := rows.Scan(&rule.ID, &rule.Query, &rule.Active, &rule.Kind, &rule.Scope); err != nil {
+			return nil, err
 		}
 		rules = append(rules, rule)
 	}
+	return rules, rows.Err()
+}
 
-	s.activeRules = rules
-	return nil
+// invalidateOrganization drops the organization's cached rules and refreshes the global cache
+func (s *Store) invalidateOrganization(organizationID string) error {
+	s.mu.Lock()
+	delete(s.orgRules, organizationID)
+	s.orgGeneration[organizationID]++
+	s.mu.Unlock()
+	return s.refreshCache()
+}
+
+// ruleOrganization returns the organization a rule belongs to ("" if it has none or doesn't exist)
+func (s *Store) ruleOrganization(ctx context.Context, id int64) (string, error) {
+	var organizationID sql.NullString
+	err := s.db.QueryRowContext(ctx, "SELECT organization_id FROM rules WHERE id = ?", id).Scan(&organizationID)
+	if err != nil && err != sql.ErrNoRows {
+		return "", err
+	}
+	return organizationID.String, nil
 }
 
 // GetActiveRules returns all active rules (from cache)
 // If organizationID is provided, only returns rules for that organization
 func (s *Store) GetActiveRules(organizationID ...string) ([]Rule, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	// If organizationID is provided, query DB directly (cache doesn't store org_id)
 	if len(organizationID) > 0 && organizationID[0] != "" {
-		s.mu.RUnlock()
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		rows, err := s.db.QueryContext(ctx, "SELECT id, query, active FROM rules WHERE active = 1 AND organization_id = ?", organizationID[0])
-		if err != nil {
-			return nil, err
-		}
-		defer rows.Close()
-		var rules []Rule
-		for rows.Next() {
-			var rule Rule
-			if err := rows.Scan(&rule.ID, &rule.Query, &rule.Active); err != nil {
-				return nil, err
-			}
-			rules = append(rules, rule)
-		}
-		return rules, nil
+		return s.getOrganizationRules(organizationID[0])
 	}
 
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	// Return a copy to avoid external modification
 	rules := make([]Rule, len(s.activeRules))
 	copy(rules, s.activeRules)
 	return rules, nil
 }
 
+// getOrganizationRules returns the organization's active rules, loading them into the cache on a miss
+func (s *Store) getOrganizationRules(organizationID string) ([]Rule, error) {
+	s.mu.RLock()
+	entry, ok := s.orgRules[organizationID]
+	fresh := ok && (s.cacheTTL <= 0 || time.Since(entry.loadedAt) < s.cacheTTL)
+	generation := s.orgGeneration[organizationID]
+	s.mu.RUnlock()
+	if fresh {
+		rules := make([]Rule, len(entry.rules))
+		copy(rules, entry.rules)
+		return rules, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rules, err := s.queryActiveRules(ctx, "SELECT id, query, active, kind, scope FROM rules WHERE active = 1 AND organization_id = ?", organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	// A change since the read began may not be in these rules, so only the next read caches
+	if s.orgGeneration[organizationID] == generation {
+		s.orgRules[organizationID] = orgRulesEntry{rules: rules, loadedAt: time.Now()}
+	}
+	s.mu.Unlock()
+
+	cached := make([]Rule, len(rules))
+	copy(cached, rules)
+	return cached, nil
+}
+
 // GetAllRules returns all rules
 // If organizationID is provided, only returns rules for that organization
 func (s *Store) GetAllRules(organizationID ...string) ([]Rule, error) {
@@ -181,10 +283,10 @@ func (s *Store) GetAllRules(organizationID ...string) ([]Rule, error) {
 	var query string
 	var args []interface{}
 	if len(organizationID) > 0 && organizationID[0] != "" {
-		query = "SELECT id, query, active FROM rules WHERE organization_id = ? ORDER BY id DESC"
+		query = "SELECT id, query, active, kind, scope FROM rules WHERE organization_id = ? ORDER BY id DESC"
 		args = []interface{}{organizationID[0]}
 	} else {
-		query = "SELECT id, query, active FROM rules ORDER BY id DESC"
+		query = "SELECT id, query, active, kind, scope FROM rules ORDER BY id DESC"
 		args = []interface{}{}
 	}
 
@@ -197,7 +299,7 @@ func (s *Store) GetAllRules(organizationID ...string) ([]Rule, error) {
 	var rules []Rule
 	for rows.Next() {
 		var rule Rule
-		if err := rows.Scan(&rule.ID, &rule.Query, &rule.Active); err != nil {
+		if err := rows.Scan(&rule.ID, &rule.Query, &rule.Active, &rule.Kind, &rule.Scope); err != nil {
 			return nil, err
 		}
 		rules = append(rules, rule)
@@ -206,9 +308,70 @@ func (s *Store) GetAllRules(organizationID ...string) ([]Rule, error) {
 	return rules, nil
 }
 
-// AddRule adds a new rule
+// GetRule returns the rule with the given ID, or nil if there is none
+// If organizationID is provided, a rule belonging to another organization is treated as missing
+func (s *Store) GetRule(id int64, organizationID ...string) (*Rule, error) {
+	query := "SELECT id, query, active, kind, scope FROM rules WHERE id = ?"
+	args := []interface{}{id}
+	if len(organizationID) > 0 && organizationID[0] != "" {
+		query += " AND organization_id = ?"
+		args = append(args, organizationID[0])
+	}
+
+	var rule Rule
+	err := s.db.QueryRow(query, args...).Scan(&rule.ID, &rule.Query, &rule.Active, &rule.Kind, &rule.Scope)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// ListRules returns one page of rules (newest first) with the total number of rules.
+// An empty organizationID lists rules across all organizations, as GetAllRules does.
+func (s *Store) ListRules(organizationID string, limit, offset int) ([]Rule, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	where := ""
+	var args []interface{}
+	if organizationID != "" {
+		where = " WHERE organization_id = ?"
+		args = append(args, organizationID)
+	}
+
+	var total int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM rules"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query("SELECT id, query, active, kind, scope FROM rules"+where+" ORDER BY id DESC LIMIT ? OFFSET ?", append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var rules []Rule
+	for rows.Next() {
+		var rule Rule
+		if err := rows.Scan(&rule.ID, &rule.Query, &rule.Active, &rule.Kind, &rule.Scope); err != nil {
+			return nil, 0, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, total, rows.Err()
+}
+
+// AddRule adds a new rule. An empty scope defaults to auto.
 // organizationID is optional - if provided, the rule will be scoped to that organization
-func (s *Store) AddRule(ctx context.Context, query string, active bool, organizationID ...string) (*Rule, error) {
+func (s *Store) AddRule(ctx context.Context, query string, active bool, scope string, organizationID ...string) (*Rule, error) {
+	scope, err := normalizeScope(scope)
+	if err != nil {
+		return nil, err
+	}
+
 	// Use context with timeout to prevent indefinite hanging
 	insertCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -219,7 +382,12 @@ func (s *Store) AddRule(ctx context.Context, query string, active bool, organiza
 	}
 
 	// Perform database insert WITHOUT holding the lock
-	result, err := s.db.ExecContext(insertCtx, "INSERT INTO rules (query, active, organization_id) VALUES (?, ?, ?)", query, active, orgID)
+	var result sql.Result
+	err = database.RetryOnBusy(insertCtx, func() error {
+		var execErr error
+		result, execErr = s.db.ExecContext(insertCtx, "INSERT INTO rules (query, active, organization_id, scope) VALUES (?, ?, ?, ?)", query, active, orgID, scope)
+		return execErr
+	})
 	if err != nil {
 		if insertCtx.Err() == context.DeadlineExceeded {
 			return nil, fmt.Errorf("database operation timed out: %w", err)
@@ -236,11 +404,13 @@ func (s *Store) AddRule(ctx context.Context, query string, active bool, organiza
 		ID:     id,
 		Query:  query,
 		Active: active,
+		Kind:   KindSemantic,
+		Scope:  scope,
 	}
 
 	// Refresh cache if rule is active (this will acquire its own lock)
 	if active {
-		if err := s.refreshCache(orgID); err != nil {
+		if err := s.invalidateOrganization(orgID); err != nil {
 			return nil, err
 		}
 	}
@@ -248,29 +418,86 @@ func (s *Store) AddRule(ctx context.Context, query string, active bool, organiza
 	return rule, nil
 }
 
-// UpdateRule updates an existing rule
-func (s *Store) UpdateRule(ctx context.Context, id int64, query string, active bool) error {
+// UpdateRule updates an existing rule. An empty scope defaults to auto.
+func (s *Store) UpdateRule(ctx context.Context, id int64, query string, active bool, scope string) error {
+	scope, err := normalizeScope(scope)
+	if err != nil {
+		return err
+	}
+
+	orgID, err := s.ruleOrganization(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	// Perform database update WITHOUT holding the lock
-	_, err := s.db.ExecContext(ctx, "UPDATE rules SET query = ?, active = ? WHERE id = ?", query, active, id)
+	err = database.RetryOnBusy(ctx, func() error {
+		_, execErr := s.db.ExecContext(ctx, "UPDATE rules SET query = ?, active = ?, scope = ? WHERE id = ?", query, active, scope, id)
+		return execErr
+	})
 	if err != nil {
 		return err
 	}
 
-	// Refresh cache (this will acquire its own lock)
-	// Note: We don't know the org_id here, so refresh all (or could query first)
-	return s.refreshCache()
+	// Invalidate the rule's organization (this will acquire its own lock)
+	return s.invalidateOrganization(orgID)
 }
 
 // DeleteRule deletes a rule
 func (s *Store) DeleteRule(ctx context.Context, id int64) error {
+	orgID, err := s.ruleOrganization(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	// Perform database delete WITHOUT holding the lock
-	_, err := s.db.ExecContext(ctx, "DELETE FROM rules WHERE id = ?", id)
+	err = database.RetryOnBusy(ctx, func() error {
+		if _, execErr := s.db.ExecContext(ctx, "DELETE FROM rules WHERE id = ?", id); execErr != nil {
+			return execErr
+		}
+		_, execErr := s.db.ExecContext(ctx, "DELETE FROM rule_embeddings WHERE rule_id = ?", id)
+		return execErr
+	})
 	if err != nil {
 		return err
 	}
 
-	// Refresh cache (this will acquire its own lock)
-	// Note: We don't know the org_id here, so refresh all (or could query first)
-	return s.refreshCache()
+	// Invalidate the rule's organization (this will acquire its own lock)
+	return s.invalidateOrganization(orgID)
 }
 
+
+// EnsureKeywordRules seeds active keyword rules for the organization.
+// A keyword that already has a keyword rule (active or not) is left alone,
+// so deactivating a seeded rule turns its alerts off. Returns the number of rules added.
+func (s *Store) EnsureKeywordRules(ctx context.Context, organizationID string, keywords []string) (int, error) {
+	added := 0
+	for _, keyword := range keywords {
+		if keyword == "" {
+			continue
+		}
+		var result sql.Result
+		err := database.RetryOnBusy(ctx, func() error {
+			var execErr error
+			result, execErr = s.db.ExecContext(ctx,
+				`INSERT INTO rules (query, active, organization_id, kind)
+				SELECT ?, 1, ?, ?
+				WHERE NOT EXISTS (SELECT 1 FROM rules WHERE kind = ? AND organization_id = ? AND query = ? COLLATE NOCASE)`,
+				keyword, organizationID, KindKeyword, KindKeyword, organizationID, keyword)
+			return execErr
+		})
+		if err != nil {
+			return added, fmt.Errorf("failed to seed keyword rule %q: %w", keyword, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			added++
+		}
+	}
+
+	if added > 0 {
+		if err := s.invalidateOrganization(organizationID); err != nil {
+			return added, err
+		}
+	}
+	return added, nil
+}
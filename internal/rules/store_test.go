@@ -0,0 +1,252 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package rules
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newTestStore(t *testing.T) (*Store, *sql.DB) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "rules.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := NewStore(db)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	return store, db
+}
+
+func activeQueries(t *testing.T, store *Store, orgID string) []string {
+	t.Helper()
+	rules, err := store.GetActiveRules(orgID)
+	if err != nil {
+		t.Fatalf("GetActiveRules(%s) failed: %v", orgID, err)
+	}
+	queries := make([]string, len(rules))
+	for i, rule := range rules {
+		queries[i] = rule.Query
+	}
+	return queries
+}
+
+func TestStore_OrganizationRuleCache(t *testing.T) {
+	store, db := newTestStore(t)
+	ctx := context.Background()
+
+	ruleA, err := store.AddRule(ctx, "Does it mention a breach?", true, "", "org-a")
+	if err != nil {
+		t.Fatalf("AddRule failed: %v", err)
+	}
+	if _, err := store.AddRule(ctx, "Is a refund promised?", true, "", "org-b"); err != nil {
+		t.Fatalf("AddRule failed: %v", err)
+	}
+
+	// Both organizations are now cached; a write that bypasses the store shows reads are cache hits
+	activeQueries(t, store, "org-a")
+	activeQueries(t, store, "org-b")
+	if _, err := db.Exec("UPDATE rules SET query = 'changed behind the cache'"); err != nil {
+		t.Fatalf("Failed to update rules: %v", err)
+	}
+	if got := activeQueries(t, store, "org-a"); len(got) != 1 || got[0] != "Does it mention a breach?" {
+		t.Errorf("Expected org-a's rules from the cache, got %v", got)
+	}
+
+	// Updating org-a's rule drops only org-a's entry
+	if err := store.UpdateRule(ctx, ruleA.ID, "Does it mention a data breach?", true, ""); err != nil {
+		t.Fatalf("UpdateRule failed: %v", err)
+	}
+	if got := activeQueries(t, store, "org-a"); len(got) != 1 || got[0] != "Does it mention a data breach?" {
+		t.Errorf("Expected the update to invalidate org-a's rules, got %v", got)
+	}
+	if got := activeQueries(t, store, "org-b"); len(got) != 1 || got[0] != "Is a refund promised?" {
+		t.Errorf("Expected org-b's rules to stay cached, got %v", got)
+	}
+
+	// Deactivating and deleting invalidate too
+	if err := store.UpdateRule(ctx, ruleA.ID, "Does it mention a data breach?", false, ""); err != nil {
+		t.Fatalf("UpdateRule failed: %v", err)
+	}
+	if got := activeQueries(t, store, "org-a"); len(got) != 0 {
+		t.Errorf("Expected no active rules for org-a after deactivation, got %v", got)
+	}
+	ruleB, _ := store.AddRule(ctx, "Is a deadline missed?", true, "", "org-b")
+	if got := activeQueries(t, store, "org-b"); len(got) != 2 {
+		t.Errorf("Expected adding a rule to invalidate org-b's rules, got %v", got)
+	}
+	if err := store.DeleteRule(ctx, ruleB.ID); err != nil {
+		t.Fatalf("DeleteRule failed: %v", err)
+	}
+	if got := activeQueries(t, store, "org-b"); len(got) != 1 {
+		t.Errorf("Expected the delete to invalidate org-b's rules, got %v", got)
+	}
+}
+
+func TestStore_OrganizationRuleCacheTTLAndConcurrency(t *testing.T) {
+	store, db := newTestStore(t)
+	ctx := context.Background()
+	store.SetCacheTTL(50 * time.Millisecond)
+
+	rule, err := store.AddRule(ctx, "Does it mention a breach?", true, "", "org-a")
+	if err != nil {
+		t.Fatalf("AddRule failed: %v", err)
+	}
+	activeQueries(t, store, "org-a")
+
+	// Another server instance changes the rule; the TTL picks it up
+	if _, err := db.Exec("UPDATE rules SET query = 'Is a regulator named?' WHERE id = ?", rule.ID); err != nil {
+		t.Fatalf("Failed to update rule: %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if got := activeQueries(t, store, "org-a"); len(got) != 1 || got[0] != "Is a regulator named?" {
+		t.Errorf("Expected an expired entry to be reloaded, got %v", got)
+	}
+
+	// Readers and writers for the same organization don't race
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if i == 0 {
+					store.UpdateRule(ctx, rule.ID, "Is a regulator named?", j%2 == 0, "")
+				} else if _, err := store.GetActiveRules("org-a"); err != nil {
+					t.Errorf("GetActiveRules failed: %v", err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if err := store.UpdateRule(ctx, rule.ID, "Is a regulator named?", true, ""); err != nil {
+		t.Fatalf("UpdateRule failed: %v", err)
+	}
+	if got := activeQueries(t, store, "org-a"); len(got) != 1 {
+		t.Errorf("Expected the last update to be visible, got %v", got)
+	}
+}
+
+func TestStore_RuleScopeRoundTrips(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	rule, err := store.AddRule(ctx, "Does it contradict its own appendix?", true, ScopeSingleDoc, "org-a")
+	if err != nil {
+		t.Fatalf("AddRule failed: %v", err)
+	}
+	defaulted, err := store.AddRule(ctx, "Does it mention a breach?", true, "", "org-a")
+	if err != nil {
+		t.Fatalf("AddRule failed: %v", err)
+	}
+	if rule.Scope != ScopeSingleDoc || defaulted.Scope != ScopeAuto {
+		t.Errorf("Expected single_doc and a default of auto, got %q and %q", rule.Scope, defaulted.Scope)
+	}
+	if _, err := store.AddRule(ctx, "Is a refund promised?", true, "everywhere", "org-a"); err == nil {
+		t.Error("Expected an unknown scope to be rejected")
+	}
+
+	if err := store.UpdateRule(ctx, rule.ID, rule.Query, true, ScopeCrossDoc); err != nil {
+		t.Fatalf("UpdateRule failed: %v", err)
+	}
+	stored, err := store.GetRule(rule.ID, "org-a")
+	if err != nil || stored == nil {
+		t.Fatalf("GetRule failed: %v", err)
+	}
+	if stored.Scope != ScopeCrossDoc {
+		t.Errorf("Expected the updated scope to be stored, got %q", stored.Scope)
+	}
+	active, _ := store.GetActiveRules("org-a")
+	for _, r := range active {
+		if r.ID == rule.ID && r.Scope != ScopeCrossDoc {
+			t.Errorf("Expected active rules to carry the scope, got %q", r.Scope)
+		}
+	}
+}
+
+func TestStore_DeleteRuleInvalidatesOnlyItsOrganization(t *testing.T) {
+	store, db := newTestStore(t)
+	ctx := context.Background()
+
+	ruleA, err := store.AddRule(ctx, "Does it mention a breach?", true, "", "org-a")
+	if err != nil {
+		t.Fatalf("AddRule failed: %v", err)
+	}
+	if _, err := store.AddRule(ctx, "Is a refund promised?", true, "", "org-b"); err != nil {
+		t.Fatalf("AddRule failed: %v", err)
+	}
+	activeQueries(t, store, "org-a")
+	activeQueries(t, store, "org-b")
+
+	// Changed behind the cache: only a reload of org-b would see it
+	if _, err := db.Exec("UPDATE rules SET query = 'changed behind the cache' WHERE organization_id = 'org-b'"); err != nil {
+		t.Fatalf("Failed to update rules: %v", err)
+	}
+	if err := store.DeleteRule(ctx, ruleA.ID); err != nil {
+		t.Fatalf("DeleteRule failed: %v", err)
+	}
+
+	if got := activeQueries(t, store, "org-a"); len(got) != 0 {
+		t.Errorf("Expected org-a's deleted rule to be gone, got %v", got)
+	}
+	if got := activeQueries(t, store, "org-b"); len(got) != 1 || got[0] != "Is a refund promised?" {
+		t.Errorf("Expected org-b's rules to stay cached, got %v", got)
+	}
+}
+
+func TestStore_SeedDefaultRulesFromEditableTemplate(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	defaults, err := store.GetDefaultRules(ctx)
+	if err != nil {
+		t.Fatalf("GetDefaultRules failed: %v", err)
+	}
+	if len(defaults) != len(BuiltInDefaultRules) || defaults[0].Query != "CONFIDENTIAL" || defaults[0].Kind != KindKeyword {
+		t.Fatalf("Expected the built-in template starting with the CONFIDENTIAL keyword rule, got %+v", defaults)
+	}
+
+	added, err := store.SeedDefaultRules(ctx, "org-a")
+	if err != nil || added != len(BuiltInDefaultRules) {
+		t.Fatalf("Expected every default rule seeded, got %d (err %v)", added, err)
+	}
+	if added, _ := store.SeedDefaultRules(ctx, "org-a"); added != 0 {
+		t.Errorf("Expected seeding twice to add nothing, got %d", added)
+	}
+
+	if _, err := store.SetDefaultRules(ctx, []DefaultRule{{Query: "Mentions a merger?", Kind: "regex"}}); err == nil {
+		t.Error("Expected an unknown kind to be rejected")
+	}
+	if _, err := store.SetDefaultRules(ctx, []DefaultRule{{Query: "  "}}); err == nil {
+		t.Error("Expected an empty query to be rejected")
+	}
+	saved, err := store.SetDefaultRules(ctx, []DefaultRule{{Query: "Mentions a merger?", Active: true}, {Query: "INTERNAL", Kind: KindKeyword, Active: true}})
+	if err != nil {
+		t.Fatalf("SetDefaultRules failed: %v", err)
+	}
+	if len(saved) != 2 || saved[0].Kind != KindSemantic || saved[0].Scope != ScopeAuto {
+		t.Errorf("Expected the new template with kind and scope defaulted, got %+v", saved)
+	}
+	if keywords, _ := store.DefaultKeywords(ctx); len(keywords) != 1 || keywords[0] != "INTERNAL" {
+		t.Errorf("Expected the template's keyword rules, got %v", keywords)
+	}
+
+	store.SeedDefaultRules(ctx, "org-b")
+	got := activeQueries(t, store, "org-b")
+	if len(got) != 2 || got[0] != "Mentions a merger?" || got[1] != "INTERNAL" {
+		t.Errorf("Expected org-b seeded from the edited template, got %v", got)
+	}
+	if got := activeQueries(t, store, "org-a"); len(got) != len(BuiltInDefaultRules) {
+		t.Errorf("Expected org-a to keep the rules it was seeded with, got %v", got)
+	}
+}
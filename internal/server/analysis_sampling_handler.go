@@ -0,0 +1,192 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/worker"
+)
+
+// SamplingPolicyLookup resolves an organization's analysis sampling policy
+type SamplingPolicyLookup interface {
+	GetPolicy(organizationID string) (*database.AnalysisSamplingPolicy, error)
+}
+
+// AnalysisEventStore records analysis decisions as rule-events
+type AnalysisEventStore interface {
+	AddEvent(ctx context.Context, event interface{}) error
+}
+
+// AnalysisSampler decides which ingested documents are sent for AI rule analysis, to bound AI cost
+type AnalysisSampler struct {
+	policies   SamplingPolicyLookup
+	eventStore AnalysisEventStore
+	mu         sync.Mutex
+	counters   map[string]uint64 // Documents seen per organization (for 1-in-N sampling)
+}
+
+// NewAnalysisSampler creates an analysis sampler. Organizations without a policy analyze every document.
+func NewAnalysisSampler(policies SamplingPolicyLookup, eventStore AnalysisEventStore) *AnalysisSampler {
+	return &AnalysisSampler{
+		policies:   policies,
+		eventStore: eventStore,
+		counters:   make(map[string]uint64),
+	}
+}
+
+// documentFileType returns the document's file type from metadata, falling back to the path extension
+func documentFileType(job worker.AnalystJob) string {
+	fileType := job.Metadata["filetype"]
+	if fileType == "" {
+		fileType = filepath.Ext(job.FilePath)
+	}
+	return strings.ToLower(strings.TrimPrefix(fileType, "."))
+}
+
+// ShouldAnalyze applies the organization's sampling policy to the job
+// Returns false and a reason when the document is skipped
+func (s *AnalysisSampler) ShouldAnalyze(job worker.AnalystJob) (bool, string) {
+	if s.policies == nil {
+		return true, ""
+	}
+	policy, err := s.policies.GetPolicy(job.OrganizationID)
+	if err != nil {
+		log.Printf("Failed to load analysis sampling policy for org %s: %v", job.OrganizationID, err)
+		return true, ""
+	}
+	if policy == nil {
+		return true, ""
+	}
+
+	if len(policy.FileTypes) > 0 {
+		fileType := documentFileType(job)
+		matched := false
+		for _, allowed := range policy.FileTypes {
+			if strings.EqualFold(strings.TrimPrefix(strings.TrimSpace(allowed), "."), fileType) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, fmt.Sprintf("filetype %q not in sampling policy", fileType)
+		}
+	}
+
+	for key, value := range policy.Metadata {
+		if job.Metadata[key] != value {
+			return false, fmt.Sprintf("metadata %s does not match sampling policy", key)
+		}
+	}
+
+	if policy.EveryN > 1 {
+		s.mu.Lock()
+		seen := s.counters[job.OrganizationID]
+		s.counters[job.OrganizationID] = seen + 1
+		s.mu.Unlock()
+
+		if seen%uint64(policy.EveryN) != 0 {
+			return false, fmt.Sprintf("sampled out (1 in %d)", policy.EveryN)
+		}
+	}
+
+	return true, ""
+}
+
+// Enqueue sends the job to the analyst pool if the sampling policy selects it. Otherwise it records a
+// skipped event and enqueues the job for keyword rules only, which need no AI call, so alerts such as
+// CONFIDENTIAL still fire on every document.
+func (s *AnalysisSampler) Enqueue(ctx context.Context, pool AnalystPoolInterface, job worker.AnalystJob) bool {
+	analyze, reason := s.ShouldAnalyze(job)
+	if analyze {
+		pool.Enqueue(job)
+		return true
+	}
+
+	log.Printf("[DEBUG] Skipping AI analysis of %s (org %s): %s", job.FilePath, job.OrganizationID, reason)
+	job.KeywordRulesOnly = true
+	pool.Enqueue(job)
+	if s.eventStore != nil {
+		eventCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		err := s.eventStore.AddEvent(eventCtx, map[string]interface{}{
+			"RuleID":         0,
+			"RuleQuery":      "sampling",
+			"Document":       job.FilePath,
+			"EventType":      "sampling",
+			"Status":         "skipped",
+			"Message":        reason,
+			"ClientID":       job.ClientID,
+			"OrganizationID": job.OrganizationID,
+		})
+		if err != nil {
+			log.Printf("Failed to record sampling event: %v", err)
+		}
+	}
+	return false
+}
+
+// HandleAnalysisSampling handles GET and PUT on /api/v1/settings/analysis-sampling for the caller's organization
+func HandleAnalysisSampling(w http.ResponseWriter, r *http.Request, store *database.AnalysisSamplingStore, auditLogStore *database.AuditLogStore) {
+	_, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		policy, err := store.GetPolicy(orgID)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if policy == nil {
+			policy = &database.AnalysisSamplingPolicy{OrganizationID: orgID, EveryN: 1}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(policy)
+	case http.MethodPut:
+		var policy database.AnalysisSamplingPolicy
+		if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		if policy.EveryN == 0 {
+			policy.EveryN = 1
+		}
+		policy.OrganizationID = orgID
+		if policy.EveryN < 1 {
+			writeJSONError(w, http.StatusBadRequest, "every_n must be at least 1")
+			return
+		}
+		previous, err := store.GetPolicy(orgID)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if previous == nil {
+			previous = &database.AnalysisSamplingPolicy{OrganizationID: orgID, EveryN: 1}
+		}
+		if err := store.SetPolicy(policy); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		auditConfigChanges(auditLogStore, r, orgID,
+			configChange{Setting: "analysis_sampling.every_n", Before: previous.EveryN, After: policy.EveryN},
+			configChange{Setting: "analysis_sampling.filetypes", Before: previous.FileTypes, After: policy.FileTypes},
+			configChange{Setting: "analysis_sampling.metadata", Before: previous.Metadata, After: policy.Metadata})
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(policy)
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
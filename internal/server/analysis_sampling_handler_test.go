@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/worker"
+)
+
+// recordingAnalystPool records enqueued analyst jobs
+type recordingAnalystPool struct {
+	jobs []worker.AnalystJob
+}
+
+func (p *recordingAnalystPool) Enqueue(job worker.AnalystJob) {
+	p.jobs = append(p.jobs, job)
+}
+
+func newTestSamplingStore(t *testing.T, policies ...database.AnalysisSamplingPolicy) *database.AnalysisSamplingStore {
+	t.Helper()
+	store, err := database.NewAnalysisSamplingStore(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Failed to create sampling store: %v", err)
+	}
+	for _, policy := range policies {
+		if err := store.SetPolicy(policy); err != nil {
+			t.Fatalf("Failed to set policy: %v", err)
+		}
+	}
+	return store
+}
+
+func TestAnalysisSampler_OneInN(t *testing.T) {
+	store := newTestSamplingStore(t, database.AnalysisSamplingPolicy{OrganizationID: "org-a", EveryN: 4})
+	events := &recordingEventStore{}
+	sampler := NewAnalysisSampler(store, events)
+	pool := &recordingAnalystPool{}
+
+	for i := 0; i < 12; i++ {
+		sampler.Enqueue(context.Background(), pool, worker.AnalystJob{FilePath: fmt.Sprintf("doc-%d.txt", i), OrganizationID: "org-a"})
+	}
+	// Organizations without a policy analyze everything
+	for i := 0; i < 3; i++ {
+		sampler.Enqueue(context.Background(), pool, worker.AnalystJob{FilePath: "other.txt", OrganizationID: "org-b"})
+	}
+
+	sampledA, keywordOnlyA := 0, 0
+	for _, job := range pool.jobs {
+		if job.OrganizationID != "org-a" {
+			continue
+		}
+		if job.KeywordRulesOnly {
+			keywordOnlyA++
+		} else {
+			sampledA++
+		}
+	}
+	if sampledA != 3 {
+		t.Errorf("Expected 1 in 4 of 12 documents to be analyzed, got %d", sampledA)
+	}
+	// Sampled-out documents are still checked against keyword rules
+	if keywordOnlyA != 9 {
+		t.Errorf("Expected the 9 sampled-out documents to be queued for keyword rules, got %d", keywordOnlyA)
+	}
+	if len(pool.jobs) != 15 {
+		t.Errorf("Expected 15 enqueued jobs in total, got %d", len(pool.jobs))
+	}
+	if len(events.events) != 9 {
+		t.Fatalf("Expected a skipped event per sampled-out document, got %d", len(events.events))
+	}
+	if events.events[0]["EventType"] != "sampling" || events.events[0]["Status"] != "skipped" || events.events[0]["OrganizationID"] != "org-a" {
+		t.Errorf("Unexpected event: %v", events.events[0])
+	}
+}
+
+func TestAnalysisSampler_FileTypeAndMetadataFilters(t *testing.T) {
+	store := newTestSamplingStore(t, database.AnalysisSamplingPolicy{
+		OrganizationID: "org-a",
+		EveryN:         1,
+		FileTypes:      []string{"pdf", ".docx"},
+		Metadata:       map[string]string{"department": "legal"},
+	})
+	sampler := NewAnalysisSampler(store, nil)
+
+	tests := []struct {
+		path     string
+		metadata map[string]string
+		want     bool
+	}{
+		{"contract.pdf", map[string]string{"department": "legal"}, true},
+		{"memo.DOCX", map[string]string{"department": "legal"}, true},
+		{"notes.txt", map[string]string{"department": "legal"}, false},
+		{"contract.pdf", map[string]string{"department": "sales"}, false},
+		{"upload", map[string]string{"filetype": "pdf", "department": "legal"}, true},
+	}
+
+	for _, tt := range tests {
+		got, reason := sampler.ShouldAnalyze(worker.AnalystJob{FilePath: tt.path, Metadata: tt.metadata, OrganizationID: "org-a"})
+		if got != tt.want {
+			t.Errorf("ShouldAnalyze(%s, %v) = %v (%s), want %v", tt.path, tt.metadata, got, reason, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/the-hive/internal/worker"
+)
+
+const (
+	// maxAnalyzeContentBytes bounds the snippet accepted by POST /api/v1/analyze
+	maxAnalyzeContentBytes = 1 << 20
+	// analyzeTimeout bounds one ad-hoc analysis, which makes an AI call per rule
+	analyzeTimeout = 2 * time.Minute
+)
+
+// ContentAnalyzer checks content against an organization's active rules without storing it
+type ContentAnalyzer interface {
+	AnalyzeContent(ctx context.Context, organizationID, content string) (*worker.AdHocAnalysis, error)
+}
+
+// AnalyzeRequest is a snippet to check against the organization's rules
+type AnalyzeRequest struct {
+	Content string `json:"content"`
+}
+
+// AnalyzeHandler runs transient compliance checks on pasted content
+type AnalyzeHandler struct {
+	analyzer ContentAnalyzer
+}
+
+// NewAnalyzeHandler creates a new analyze handler
+func NewAnalyzeHandler(analyzer ContentAnalyzer) *AnalyzeHandler {
+	return &AnalyzeHandler{analyzer: analyzer}
+}
+
+// HandleAnalyze handles POST /api/v1/analyze
+// The content is checked against the caller's organization's active rules and discarded; it is
+// never written to the vector store or the database.
+func (h *AnalyzeHandler) HandleAnalyze(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	_, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+	if orgID == "" {
+		writeJSONError(w, http.StatusBadRequest, "organization ID required")
+		return
+	}
+
+	var req AnalyzeRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAnalyzeContentBytes)).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, "content too large")
+			return
+		}
+		writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		writeJSONError(w, http.StatusBadRequest, "content is required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), analyzeTimeout)
+	defer cancel()
+	result, err := h.analyzer.AnalyzeContent(ctx, orgID, req.Content)
+	if err != nil {
+		log.Printf("Ad-hoc analysis failed for org %s: %v", orgID, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to analyze content")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
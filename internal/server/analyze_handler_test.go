@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/rules"
+	"github.com/the-hive/internal/vectordb"
+	"github.com/the-hive/internal/worker"
+)
+
+func TestHandleAnalyze_ReturnsMatchesAndStoresNothing(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.Exec(`CREATE TABLE chunks (
+		id TEXT PRIMARY KEY,
+		document_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		chunk_index INTEGER NOT NULL,
+		organization_id TEXT
+	)`); err != nil {
+		t.Fatalf("Failed to create chunks table: %v", err)
+	}
+	ruleStore, err := rules.NewStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create rule store: %v", err)
+	}
+	vdb := vectordb.NewMemoryVectorDB()
+	// The built-in CONFIDENTIAL keyword rule needs no AI call
+	pool := worker.NewAnalystPool(ruleStore, nil, nil, vdb, nil, nil, nil, 1)
+	h := NewAnalyzeHandler(pool)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/analyze", strings.NewReader(`{"content": "Confidential: Q4 price list for partners."}`))
+	req = withUser(req, &database.User{ID: "alice"}, "org-a")
+	rec := httptest.NewRecorder()
+	h.HandleAnalyze(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var result worker.AdHocAnalysis
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(result.Matches) != 1 || result.Matches[0].MatchType != "keyword" || !strings.Contains(result.Matches[0].Evidence, "price list") {
+		t.Errorf("Expected a keyword match quoting the snippet, got %+v", result)
+	}
+
+	var chunks int
+	if err := db.QueryRow("SELECT COUNT(*) FROM chunks").Scan(&chunks); err != nil {
+		t.Fatalf("Failed to count chunks: %v", err)
+	}
+	if points, _ := vdb.GetPointCount(context.Background()); chunks != 0 || points != 0 {
+		t.Errorf("Expected nothing to be stored, found %d chunks and %d vectors", chunks, points)
+	}
+
+	// Empty content is rejected
+	req = withUser(httptest.NewRequest(http.MethodPost, "/api/v1/analyze", strings.NewReader(`{"content": "  "}`)), &database.User{ID: "alice"}, "org-a")
+	rec = httptest.NewRecorder()
+	h.HandleAnalyze(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for empty content, got %d", rec.Code)
+	}
+}
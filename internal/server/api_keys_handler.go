@@ -46,7 +46,7 @@ func HandleGenerateAPIKey(w http.ResponseWriter, r *http.Request, apiKeyStore *d
 		return
 	}
 
-	key, err := apiKeyStore.GenerateKey(orgIDStr)
+	key, err := apiKeyStore.GenerateKey(orgIDStr, orgIDStr)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
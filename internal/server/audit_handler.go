@@ -6,11 +6,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/the-hive/internal/database"
 )
 
+const (
+	// DefaultAuditLogLimit is the page size of GET /api/v1/audit without a limit
+	DefaultAuditLogLimit = 100
+	// MaxAuditLogLimit caps the limit a GET /api/v1/audit request may ask for
+	MaxAuditLogLimit = 1000
+)
+
 // HandleAuditLogs handles GET /api/v1/audit requests
+// Query parameters: limit, offset, action, and from/to (RFC 3339 or YYYY-MM-DD; to is exclusive, a date includes that day)
 func HandleAuditLogs(w http.ResponseWriter, r *http.Request, auditLogStore *database.AuditLogStore) {
 	if r.Method != http.MethodGet {
 		w.Header().Set("Content-Type", "application/json")
@@ -19,13 +29,10 @@ func HandleAuditLogs(w http.ResponseWriter, r *http.Request, auditLogStore *data
 		return
 	}
 
-	// Get limit from query parameter (default to 50)
-	limit := 50
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		var err error
-		if limit, err = parseAuditLimit(limitStr); err != nil {
-			limit = 50
-		}
+	filter, err := parseAuditLogFilter(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
 	// Get organization ID from context
@@ -35,7 +42,8 @@ func HandleAuditLogs(w http.ResponseWriter, r *http.Request, auditLogStore *data
 			orgID = orgIDStr
 		}
 	}
-	logs, err := auditLogStore.GetRecentLogs(limit, "", orgID)
+	filter.OrganizationID = orgID
+	logs, total, err := auditLogStore.Query(filter)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -43,17 +51,51 @@ func HandleAuditLogs(w http.ResponseWriter, r *http.Request, auditLogStore *data
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"logs":  logs,
-		"count": len(logs),
-	})
+	writePage(w, logs, total, filter.Limit, filter.Offset)
 }
 
-// parseAuditLimit is a simple helper to parse integer from string
-func parseAuditLimit(s string) (int, error) {
-	var result int
-	_, err := fmt.Sscanf(s, "%d", &result)
-	return result, err
+// parseAuditLogFilter reads the audit log query parameters, defaulting and capping the limit
+func parseAuditLogFilter(r *http.Request) (database.AuditLogFilter, error) {
+	query := r.URL.Query()
+	filter := database.AuditLogFilter{Action: query.Get("action"), Limit: DefaultAuditLogLimit}
+	if n, err := strconv.Atoi(query.Get("limit")); err == nil && n > 0 {
+		filter.Limit = n
+	}
+	if filter.Limit > MaxAuditLogLimit {
+		filter.Limit = MaxAuditLogLimit
+	}
+	if n, err := strconv.Atoi(query.Get("offset")); err == nil && n > 0 {
+		filter.Offset = n
+	}
+
+	var err error
+	if filter.From, err = parseAuditTime(query.Get("from"), false); err != nil {
+		return filter, fmt.Errorf("invalid from: %w", err)
+	}
+	if filter.To, err = parseAuditTime(query.Get("to"), true); err != nil {
+		return filter, fmt.Errorf("invalid to: %w", err)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, fmt.Errorf("from must be before to")
+	}
+	return filter, nil
+}
+
+// parseAuditTime parses an RFC 3339 time or a YYYY-MM-DD date (UTC). As an upper bound, a date
+// means the end of that day. An empty value is the zero time.
+func parseAuditTime(value string, upper bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not an RFC 3339 time or a YYYY-MM-DD date", value)
+	}
+	if upper {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day, nil
 }
@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/the-hive/internal/database"
+)
+
+func TestHandleAuditLogs_FiltersByActionAndTime(t *testing.T) {
+	db := newTestDB(t)
+	auditLogStore, err := database.NewAuditLogStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create audit log store: %v", err)
+	}
+	// Stored with different offsets, as servers in other time zones would
+	for _, l := range []struct {
+		at     time.Time
+		action database.AuditAction
+		orgID  string
+	}{
+		{time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC), database.AuditActionSearch, "org-a"},
+		{time.Date(2025, 3, 2, 9, 0, 0, 0, time.FixedZone("EST", -5*3600)), database.AuditActionIngest, "org-a"},
+		{time.Date(2025, 3, 2, 23, 30, 0, 0, time.UTC), database.AuditActionSearch, "org-a"},
+		{time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC), database.AuditActionSearch, "org-a"},
+		{time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC), database.AuditActionSearch, "org-b"},
+	} {
+		if _, err := db.Exec("INSERT INTO audit_logs (timestamp, client_ip, action, details, organization_id) VALUES (?, ?, ?, ?, ?)", l.at, "10.0.0.1", string(l.action), "", l.orgID); err != nil {
+			t.Fatalf("Failed to insert audit log: %v", err)
+		}
+	}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		HandleAuditLogs(rec, withUser(httptest.NewRequest(http.MethodGet, "/api/v1/audit"+query, nil), &database.User{ID: "admin"}, "org-a"), auditLogStore)
+		return rec
+	}
+
+	items, total, limit, _ := decodePage(t, get(""))
+	if total != 4 || len(items) != 4 || limit != DefaultAuditLogLimit {
+		t.Errorf("Expected all 4 of the organization's logs with the default limit, got %d items, total %d, limit %d", len(items), total, limit)
+	}
+
+	items, total, _, _ = decodePage(t, get("?from=2025-03-02&to=2025-03-02"))
+	if total != 2 || len(items) != 2 {
+		t.Errorf("Expected the 2 logs of March 2, got %d items, total %d", len(items), total)
+	}
+
+	items, total, _, _ = decodePage(t, get("?action=SEARCH&from=2025-03-02T00:00:00Z&to=2025-03-03T09:00:00Z"))
+	if total != 1 || len(items) != 1 {
+		t.Errorf("Expected 1 search log from March 2 until (not including) March 3 09:00, got %d items, total %d", len(items), total)
+	}
+
+	items, total, limit, _ = decodePage(t, get("?limit=5000&offset=1"))
+	if limit != MaxAuditLogLimit || total != 4 || len(items) != 3 {
+		t.Errorf("Expected the limit capped at %d and the page to skip one log, got %d items, total %d, limit %d", MaxAuditLogLimit, len(items), total, limit)
+	}
+
+	for _, query := range []string{"?from=yesterday", "?from=2025-03-03&to=2025-03-01"} {
+		if rec := get(query); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", query, rec.Code)
+		}
+	}
+}
+
+func TestHandleExportAuditLogs_StreamsFilteredCSV(t *testing.T) {
+	auditLogStore, err := database.NewAuditLogStore(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Failed to create audit log store: %v", err)
+	}
+	auditLogStore.LogAction("10.0.0.1", database.AuditActionIngest, "Client [10.0.0.1] uploaded file [a.txt] (1 chunks)", "org-a")
+	auditLogStore.LogAction("10.0.0.2", database.AuditActionSearch, "Searched for \"pricing, 2025\"", "org-a")
+	auditLogStore.LogAction("10.0.0.3", database.AuditActionIngest, "Client [10.0.0.3] uploaded file [b.txt] (1 chunks)", "org-a")
+	auditLogStore.LogAction("10.0.0.9", database.AuditActionIngest, "other org", "org-b")
+
+	rec := httptest.NewRecorder()
+	HandleExportAuditLogs(rec, withUser(httptest.NewRequest(http.MethodGet, "/api/v1/audit/export?action=INGEST&limit=1", nil), &database.User{ID: "admin"}, "org-a"), auditLogStore)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	wantDisposition := "attachment; filename=audit-" + time.Now().UTC().Format("20060102") + ".csv"
+	if got := rec.Header().Get("Content-Disposition"); got != wantDisposition {
+		t.Errorf("Expected Content-Disposition %q, got %q", wantDisposition, got)
+	}
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Response is not CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected a header and the organization's 2 ingests (the limit doesn't apply), got %v", records)
+	}
+	if strings.Join(records[0], ",") != "timestamp,client_ip,action,details,organization_id" {
+		t.Errorf("Unexpected header %v", records[0])
+	}
+	if records[1][1] != "10.0.0.1" || records[2][1] != "10.0.0.3" || records[1][2] != "INGEST" || records[1][4] != "org-a" {
+		t.Errorf("Expected the ingests oldest first with their organization, got %v", records[1:])
+	}
+	if _, err := time.Parse(time.RFC3339, records[1][0]); err != nil {
+		t.Errorf("Expected an RFC 3339 timestamp, got %q", records[1][0])
+	}
+
+	rec = httptest.NewRecorder()
+	HandleExportAuditLogs(rec, withUser(httptest.NewRequest(http.MethodGet, "/api/v1/audit/export?action=SEARCH", nil), &database.User{ID: "admin"}, "org-a"), auditLogStore)
+	records, _ = csv.NewReader(rec.Body).ReadAll()
+	if len(records) != 2 || records[1][3] != "Searched for \"pricing, 2025\"" {
+		t.Errorf("Expected the search with its details quoted intact, got %v", records)
+	}
+}
@@ -18,7 +18,7 @@ func HandleLoginPage(w http.ResponseWriter, r *http.Request, metadataStore *data
 		return
 	}
 
-	if err := renderTemplate(w, "login.html", nil); err != nil {
+	if err := renderTemplate(w, "login.html", newPageData(r, metadataStore, orgStore)); err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -31,7 +31,7 @@ func HandleChangePasswordPage(w http.ResponseWriter, r *http.Request, metadataSt
 		return
 	}
 
-	if err := renderTemplate(w, "change_password.html", nil); err != nil {
+	if err := renderTemplate(w, "change_password.html", newPageData(r, metadataStore, orgStore)); err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -48,6 +48,15 @@ type LoginResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message,omitempty"`
 	Token   string `json:"token,omitempty"`
+	// MustChangePassword is set for users created with a temporary password
+	MustChangePassword bool `json:"must_change_password,omitempty"`
+}
+
+var passwordFlagStore *database.PasswordFlagStore
+
+// SetPasswordFlagStore sets the store consulted for must-change-password flags
+func SetPasswordFlagStore(store *database.PasswordFlagStore) {
+	passwordFlagStore = store
 }
 
 // HandleLogin handles POST /api/v1/login
@@ -109,10 +118,18 @@ func HandleLogin(w http.ResponseWriter, r *http.Request, userStore *database.Use
 		Path:     "/",
 	})
 
+	mustChange := false
+	if passwordFlagStore != nil {
+		if flagged, err := passwordFlagStore.MustChangePassword(user.ID); err == nil {
+			mustChange = flagged
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(LoginResponse{
-		Success: true,
-		Token:   sessionToken,
+		Success:            true,
+		Token:              sessionToken,
+		MustChangePassword: mustChange,
 	})
 }
 
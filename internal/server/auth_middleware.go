@@ -3,6 +3,7 @@
 package server
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"strings"
@@ -11,50 +12,127 @@ import (
 )
 
 // AuthMiddleware creates an authentication middleware that validates API keys
+// The key's organization, when it has one, is put in the request context as organization_id.
 func AuthMiddleware(apiKeyStore *database.APIKeyStore) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Extract API key from Authorization header
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusUnauthorized)
-				w.Write([]byte(`{"error":"missing authorization header"}`))
+			r, ok := authenticateAPIKey(w, r, apiKeyStore, false)
+			if !ok {
 				return
 			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
 
-			// Support both "Bearer <key>" and just "<key>" formats
-			key := strings.TrimSpace(authHeader)
-			if strings.HasPrefix(key, "Bearer ") {
-				key = strings.TrimPrefix(key, "Bearer ")
+// APIKeyOrSession lets programmatic callers use an API key in place of a login session.
+// Requests with an Authorization header are authenticated by key and scoped to the key's
+// organization (rejected if it has none); all others go through the session middleware.
+func APIKeyOrSession(apiKeyStore *database.APIKeyStore, session func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		sessionHandler := session(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				sessionHandler.ServeHTTP(w, r)
+				return
 			}
-
-			// Validate the key
-			isValid, err := apiKeyStore.ValidateKey(key)
-			if err != nil {
-				log.Printf("Error validating API key: %v", err)
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusInternalServerError)
-				w.Write([]byte(`{"error":"internal server error"}`))
+			r, ok := authenticateAPIKey(w, r, apiKeyStore, true)
+			if !ok {
 				return
 			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// passwordChangePaths stay reachable for users who must still replace a temporary password
+var passwordChangePaths = map[string]bool{
+	"/change-password":               true,
+	"/api/v1/users/current/password": true,
+	"/api/v1/me":                     true,
+	"/api/v1/logout":                 true,
+}
 
-			if !isValid {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusUnauthorized)
-				w.Write([]byte(`{"error":"invalid or inactive API key"}`))
+// RequirePasswordChanged runs after the login middleware and holds users flagged to replace a
+// temporary password (e.g. imported from CSV) to changing it: pages redirect to /change-password
+// and API requests get 403 until the flag is cleared by a successful password change.
+func RequirePasswordChanged(flags *database.PasswordFlagStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := r.Context().Value("user").(*database.User)
+			if flags == nil || !ok || passwordChangePaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
 				return
 			}
 
-			// Update last_seen_at timestamp for this key
-			if err := apiKeyStore.UpdateLastSeen(key); err != nil {
-				log.Printf("Warning: Failed to update last_seen_at for key: %v", err)
-				// Don't fail the request, just log the warning
+			mustChange, err := flags.MustChangePassword(user.ID)
+			if err != nil {
+				log.Printf("Error checking password change flag for user %s: %v", user.ID, err)
+				writeJSONError(w, http.StatusInternalServerError, "internal server error")
+				return
 			}
-
-			// Key is valid, proceed to next handler
-			next.ServeHTTP(w, r)
+			if !mustChange {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				writeJSONError(w, http.StatusForbidden, "password change required")
+				return
+			}
+			http.Redirect(w, r, "/change-password", http.StatusSeeOther)
 		})
 	}
 }
 
+// authenticateAPIKey validates the request's API key, writing an error response on failure.
+// On success the returned request carries the key (as api_key) and its organization.
+func authenticateAPIKey(w http.ResponseWriter, r *http.Request, apiKeyStore *database.APIKeyStore, requireOrg bool) (*http.Request, bool) {
+	// Extract API key from Authorization header
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		writeJSONError(w, http.StatusUnauthorized, "missing authorization header")
+		return r, false
+	}
+
+	// Support both "Bearer <key>" and just "<key>" formats
+	key := strings.TrimSpace(authHeader)
+	if strings.HasPrefix(key, "Bearer ") {
+		key = strings.TrimPrefix(key, "Bearer ")
+	}
+
+	// Validate the key
+	isValid, err := apiKeyStore.ValidateKey(key)
+	if err != nil {
+		log.Printf("Error validating API key: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "internal server error")
+		return r, false
+	}
+
+	if !isValid {
+		writeJSONError(w, http.StatusUnauthorized, "invalid or inactive API key")
+		return r, false
+	}
+
+	orgID, err := apiKeyStore.GetKeyOrganization(key)
+	if err != nil {
+		log.Printf("Error resolving API key organization: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "internal server error")
+		return r, false
+	}
+	if orgID == "" && requireOrg {
+		writeJSONError(w, http.StatusForbidden, "API key is not associated with an organization")
+		return r, false
+	}
+
+	// Update last_seen_at timestamp for this key
+	if err := apiKeyStore.UpdateLastSeen(key); err != nil {
+		log.Printf("Warning: Failed to update last_seen_at for key: %v", err)
+		// Don't fail the request, just log the warning
+	}
+
+	ctx := context.WithValue(r.Context(), "api_key", key)
+	if orgID != "" {
+		ctx = context.WithValue(ctx, "organization_id", orgID)
+	}
+	return r.WithContext(ctx), true
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/vectordb"
+)
+
+func TestAPIKeyOrSession_SearchScopedToKeyOrganization(t *testing.T) {
+	keys, err := database.NewAPIKeyStore(newTestDB(t))
+	if err != nil {
+		t.Fatalf("NewAPIKeyStore failed: %v", err)
+	}
+	orgOneKey, _ := keys.GenerateKey("drone-1", "org-1")
+	noOrgKey, _ := keys.GenerateKey("legacy-script", "")
+
+	ctx := context.Background()
+	embedder := embeddings.NewMockEmbedder(8)
+	vdb := vectordb.NewMemoryVectorDB()
+	for id, org := range map[string]string{"a": "org-1", "b": "org-2"} {
+		content := "Holiday policy for " + org
+		vector, _ := embedder.EmbedText(ctx, content)
+		vdb.Upsert(ctx, id, vector, map[string]string{"organization_id": org, "document_id": org + ".txt", "content": content})
+	}
+
+	// The session middleware is only reached by requests without an API key
+	session := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeJSONError(w, http.StatusUnauthorized, "login required")
+		})
+	}
+	handler := APIKeyOrSession(keys, session)(http.HandlerFunc(NewSearchHandler(vdb, embedder, nil).HandleSearch))
+
+	search := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/search", strings.NewReader(`{"query": "holiday policy", "top_k": 10}`))
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := search(orgOneKey)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected an API-key search to succeed, got %d %s", rec.Code, rec.Body.String())
+	}
+	var resp SearchResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.Count != 1 || resp.Matches[0].DocumentID != "org-1.txt" {
+		t.Errorf("Expected only org-1's document, got %+v", resp.Matches)
+	}
+
+	if rec := search(noOrgKey); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected a key without an organization to be rejected, got %d", rec.Code)
+	}
+	if rec := search("hive_not-a-key"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected an unknown key to be rejected, got %d", rec.Code)
+	}
+	if rec := search(""); rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), "login required") {
+		t.Errorf("Expected a request without a key to go through the session middleware, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRequirePasswordChanged(t *testing.T) {
+	flags, err := database.NewPasswordFlagStore(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Failed to create flag store: %v", err)
+	}
+	user := &database.User{ID: "imported", Role: database.RoleViewer, OrganizationID: "org-a"}
+	flags.RequirePasswordChange(user.ID)
+
+	handler := RequirePasswordChanged(flags)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	serve := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, withUser(httptest.NewRequest(http.MethodGet, path, nil), user, "org-a"))
+		return rec
+	}
+
+	if rec := serve("/api/v1/search"); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for the API before the password is changed, got %d", rec.Code)
+	}
+	if rec := serve("/dashboard"); rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/change-password" {
+		t.Errorf("Expected pages to redirect to /change-password, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+	for _, path := range []string{"/change-password", "/api/v1/users/current/password", "/api/v1/me", "/api/v1/logout"} {
+		if rec := serve(path); rec.Code != http.StatusNoContent {
+			t.Errorf("Expected %s to stay reachable, got %d", path, rec.Code)
+		}
+	}
+
+	flags.ClearPasswordChange(user.ID)
+	if rec := serve("/api/v1/search"); rec.Code != http.StatusNoContent {
+		t.Errorf("Expected the API once the password is changed, got %d", rec.Code)
+	}
+}
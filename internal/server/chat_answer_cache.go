@@ -0,0 +1,128 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultChatAnswerCacheEntries bounds the chat answer cache's memory use
+const defaultChatAnswerCacheEntries = 5000
+
+// chatAnswerKey identifies an answer to one question over one version of an organization's corpus
+type chatAnswerKey struct {
+	organizationID string
+	query          string
+	corpusVersion  uint64
+}
+
+// cachedChatAnswer is a stored answer with the citations and grounding check returned with it
+type cachedChatAnswer struct {
+	answer    string
+	citations []map[string]interface{}
+	grounding *GroundingCheck
+	expiresAt time.Time
+}
+
+// ChatAnswerCache reuses chat answers for repeated questions while an organization's documents
+// are unchanged. Every ingest or delete bumps the organization's corpus version, so answers
+// computed before it are never served again.
+type ChatAnswerCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[chatAnswerKey]cachedChatAnswer
+	versions   map[string]uint64 // Corpus version per organization
+	epoch      uint64            // Bumped when every organization's corpus changes at once
+	now        func() time.Time  // replaceable in tests
+}
+
+// NewChatAnswerCache creates a cache holding answers for ttl
+func NewChatAnswerCache(ttl time.Duration) *ChatAnswerCache {
+	return &ChatAnswerCache{
+		ttl:        ttl,
+		maxEntries: defaultChatAnswerCacheEntries,
+		entries:    make(map[chatAnswerKey]cachedChatAnswer),
+		versions:   make(map[string]uint64),
+		now:        time.Now,
+	}
+}
+
+// normalizeChatQuery folds case, whitespace and trailing punctuation so trivially different
+// phrasings of a question share an answer
+func normalizeChatQuery(query string) string {
+	query = strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	return strings.TrimRight(query, "?!. ")
+}
+
+// CorpusVersion returns the organization's current corpus version; capture it before answering
+// and pass it to Put so an answer raced by an ingest isn't cached
+func (c *ChatAnswerCache) CorpusVersion(organizationID string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.epoch + c.versions[organizationID]
+}
+
+// Get returns the cached answer to the question over the organization's current corpus
+func (c *ChatAnswerCache) Get(organizationID, query string) (cachedChatAnswer, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := chatAnswerKey{
+		organizationID: organizationID,
+		query:          normalizeChatQuery(query),
+		corpusVersion:  c.epoch + c.versions[organizationID],
+	}
+	entry, ok := c.entries[key]
+	if !ok {
+		return cachedChatAnswer{}, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return cachedChatAnswer{}, false
+	}
+	return entry, true
+}
+
+// Put stores an answer computed at corpusVersion; it is dropped if the corpus has changed since
+func (c *ChatAnswerCache) Put(organizationID, query string, corpusVersion uint64, answer cachedChatAnswer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if corpusVersion != c.epoch+c.versions[organizationID] {
+		return
+	}
+	now := c.now()
+	if len(c.entries) >= c.maxEntries {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, key)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			c.entries = make(map[chatAnswerKey]cachedChatAnswer)
+		}
+	}
+	answer.expiresAt = now.Add(c.ttl)
+	c.entries[chatAnswerKey{organizationID: organizationID, query: normalizeChatQuery(query), corpusVersion: corpusVersion}] = answer
+}
+
+// Invalidate bumps the organization's corpus version after its documents change
+func (c *ChatAnswerCache) Invalidate(organizationID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.versions[organizationID]++
+	for key := range c.entries {
+		if key.organizationID == organizationID {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// InvalidateAll bumps every organization's corpus version (e.g. after a whole-collection purge)
+func (c *ChatAnswerCache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.epoch++
+	c.entries = make(map[chatAnswerKey]cachedChatAnswer)
+}
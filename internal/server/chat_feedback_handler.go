@@ -0,0 +1,155 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/the-hive/internal/database"
+)
+
+const maxFeedbackCommentLen = 2000
+
+// ChatMessageFinder is the subset of the chat store used to check message ownership
+type ChatMessageFinder interface {
+	GetUserSessions(userID, orgID string) ([]database.ChatSession, error)
+	GetSessionMessages(sessionID string) ([]database.ChatMessage, error)
+}
+
+// ChatFeedbackRequest rates an assistant message
+type ChatFeedbackRequest struct {
+	Rating    string `json:"rating"` // "up" or "down"
+	Comment   string `json:"comment,omitempty"`
+	SessionID string `json:"session_id,omitempty"` // Optional; avoids scanning all of the user's sessions
+}
+
+// findOwnedMessage returns the message if it belongs to one of the user's sessions
+func findOwnedMessage(chats ChatMessageFinder, userID, orgID string, messageID int64, sessionHint string) (*database.ChatMessage, error) {
+	sessions, err := chats.GetUserSessions(userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		if sessionHint != "" && session.ID != sessionHint {
+			continue
+		}
+		messages, err := chats.GetSessionMessages(session.ID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range messages {
+			if messages[i].ID == messageID {
+				message := messages[i]
+				message.SessionID = session.ID
+				return &message, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// HandleChatMessageFeedback handles POST /api/v1/chat/messages/{id}/feedback
+// Users may only rate assistant messages in their own sessions; rating again replaces the previous rating
+func HandleChatMessageFeedback(w http.ResponseWriter, r *http.Request, chats ChatMessageFinder, feedbackStore *database.ChatFeedbackStore) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	messageID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid message ID")
+		return
+	}
+
+	var req ChatFeedbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+
+	var rating int
+	switch strings.ToLower(strings.TrimSpace(req.Rating)) {
+	case "up":
+		rating = database.FeedbackUp
+	case "down":
+		rating = database.FeedbackDown
+	default:
+		writeJSONError(w, http.StatusBadRequest, "rating must be \"up\" or \"down\"")
+		return
+	}
+	if len(req.Comment) > maxFeedbackCommentLen {
+		writeJSONError(w, http.StatusBadRequest, "comment is too long")
+		return
+	}
+
+	message, err := findOwnedMessage(chats, dbUser.ID, orgID, messageID, req.SessionID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if message == nil {
+		writeJSONError(w, http.StatusNotFound, "message not found")
+		return
+	}
+	if message.Role != "assistant" {
+		writeJSONError(w, http.StatusBadRequest, "only assistant messages can be rated")
+		return
+	}
+
+	feedback := &database.ChatFeedback{
+		MessageID:      messageID,
+		SessionID:      message.SessionID,
+		UserID:         dbUser.ID,
+		OrganizationID: orgID,
+		Rating:         rating,
+		Comment:        strings.TrimSpace(req.Comment),
+		Answer:         message.Content,
+	}
+	if err := feedbackStore.SaveFeedback(feedback); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	saved, err := feedbackStore.GetFeedback(messageID, dbUser.ID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+}
+
+// HandleChatFeedbackReport handles GET /api/v1/chat/feedback/report for the caller's organization
+func HandleChatFeedbackReport(w http.ResponseWriter, r *http.Request, feedbackStore *database.ChatFeedbackStore) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	_, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit > 100 {
+		limit = 100
+	}
+
+	report, err := feedbackStore.Report(orgID, limit)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
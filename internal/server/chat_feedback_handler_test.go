@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+)
+
+// fakeChatFinder serves sessions and messages from memory
+type fakeChatFinder struct {
+	sessions map[string][]database.ChatSession // By user ID
+	messages map[string][]database.ChatMessage // By session ID
+}
+
+func (f *fakeChatFinder) GetUserSessions(userID, orgID string) ([]database.ChatSession, error) {
+	return f.sessions[userID], nil
+}
+
+func (f *fakeChatFinder) GetSessionMessages(sessionID string) ([]database.ChatMessage, error) {
+	return f.messages[sessionID], nil
+}
+
+func newFeedbackFixture(t *testing.T) (*fakeChatFinder, *database.ChatFeedbackStore) {
+	t.Helper()
+	chats := &fakeChatFinder{
+		sessions: map[string][]database.ChatSession{
+			"alice": {{ID: "s-alice", UserID: "alice", OrganizationID: "org-a"}},
+			"bob":   {{ID: "s-bob", UserID: "bob", OrganizationID: "org-a"}},
+		},
+		messages: map[string][]database.ChatMessage{
+			"s-alice": {
+				{ID: 1, SessionID: "s-alice", Role: "user", Content: "What is the refund policy?"},
+				{ID: 2, SessionID: "s-alice", Role: "assistant", Content: "Refunds are issued within 30 days."},
+			},
+			"s-bob": {
+				{ID: 3, SessionID: "s-bob", Role: "assistant", Content: "The office opens at 9."},
+			},
+		},
+	}
+	store, err := database.NewChatFeedbackStore(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Failed to create feedback store: %v", err)
+	}
+	return chats, store
+}
+
+func postFeedback(chats ChatMessageFinder, store *database.ChatFeedbackStore, userID, messageID, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/chat/messages/"+messageID+"/feedback", strings.NewReader(body))
+	req.SetPathValue("id", messageID)
+	req = withUser(req, &database.User{ID: userID}, "org-a")
+	rec := httptest.NewRecorder()
+	HandleChatMessageFeedback(rec, req, chats, store)
+	return rec
+}
+
+func TestHandleChatMessageFeedback_RecordAndReadBack(t *testing.T) {
+	chats, store := newFeedbackFixture(t)
+
+	rec := postFeedback(chats, store, "alice", "2", `{"rating":"down","comment":"Policy is 14 days"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	saved, err := store.GetFeedback(2, "alice")
+	if err != nil || saved == nil {
+		t.Fatalf("Expected stored feedback, got %v, %v", saved, err)
+	}
+	if saved.Rating != database.FeedbackDown || saved.Comment != "Policy is 14 days" || saved.SessionID != "s-alice" || saved.OrganizationID != "org-a" {
+		t.Errorf("Unexpected feedback: %+v", saved)
+	}
+
+	// Rating again replaces the previous rating
+	if rec := postFeedback(chats, store, "alice", "2", `{"rating":"up"}`); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 on re-rate, got %d", rec.Code)
+	}
+	postFeedback(chats, store, "bob", "3", `{"rating":"down","comment":"Wrong hours"}`)
+
+	report, err := store.Report("org-a", 10)
+	if err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	if report.Total != 2 || report.Up != 1 || report.Down != 1 || report.Satisfaction != 0.5 {
+		t.Errorf("Unexpected report totals: %+v", report)
+	}
+	if len(report.RecentNegative) != 1 || report.RecentNegative[0].Answer != "The office opens at 9." {
+		t.Errorf("Expected bob's thumbs-down with the rated answer, got %+v", report.RecentNegative)
+	}
+}
+
+func TestHandleChatMessageFeedback_ScopedToOwner(t *testing.T) {
+	chats, store := newFeedbackFixture(t)
+
+	tests := []struct {
+		name      string
+		userID    string
+		messageID string
+		body      string
+		want      int
+	}{
+		{"another user's message", "bob", "2", `{"rating":"up"}`, http.StatusNotFound},
+		{"user message", "alice", "1", `{"rating":"up"}`, http.StatusBadRequest},
+		{"invalid rating", "alice", "2", `{"rating":"meh"}`, http.StatusBadRequest},
+		{"invalid id", "alice", "abc", `{"rating":"up"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := postFeedback(chats, store, tt.userID, tt.messageID, tt.body); rec.Code != tt.want {
+				t.Errorf("Expected %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	HandleChatFeedbackReport(rec, withUser(httptest.NewRequest(http.MethodGet, "/api/v1/chat/feedback/report", nil), &database.User{ID: "admin"}, "org-a"), store)
+	var report database.ChatFeedbackReport
+	json.NewDecoder(rec.Body).Decode(&report)
+	if report.Total != 0 {
+		t.Errorf("Rejected feedback must not be stored, got %+v", report)
+	}
+}
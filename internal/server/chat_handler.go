@@ -3,39 +3,263 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/the-hive/internal/ai"
 	"github.com/the-hive/internal/database"
 	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/langdetect"
 	"github.com/the-hive/internal/vectordb"
 )
 
 // ChatHandler handles chat/Q&A requests
 type ChatHandler struct {
-	vectorDB      vectordb.VectorDB
-	embedder      embeddings.Embedder
-	auditLogStore *database.AuditLogStore
-	chatStore     *database.ChatStore
-	orgStore      *database.OrganizationStore
-	usageStore    *database.UsageStore
+	vectorDB       vectordb.VectorDB
+	embedder       embeddings.Embedder
+	auditLogStore  *database.AuditLogStore
+	chatStore      *database.ChatStore
+	orgStore       OrganizationLookup // Nil when organizations have no system context to add
+	usageStore     *database.UsageStore
+	queryValidator *QueryValidator
+	mmrLambda      float64 // Relevance vs. diversity trade-off for context selection (1 disables MMR)
+	generate       AnswerGenerator
+	grounding      GroundingPolicyLookup
+	timeout        time.Duration // Budget for embedding, search and generation together
+	maxTopK        int           // Upper bound on chunks fetched per retrieval, including MMR candidates
+	answerCache    *ChatAnswerCache
+	history        ChatHistoryLimiter // Caps the messages kept per session; nil keeps them all
+}
+
+const (
+	chatContextChunks      = 5 // Chunks used as chat context
+	mmrCandidateMultiplier = 4 // Candidates fetched per context chunk for MMR re-selection
+
+	// DefaultChatTimeout bounds a whole chat request so a hung provider can't hold it open
+	DefaultChatTimeout = 60 * time.Second
+)
+
+// OrganizationLookup resolves an organization, whose system context is added to chat prompts
+type OrganizationLookup interface {
+	GetOrganizationByID(id string) (*database.Organization, error)
 }
 
 // NewChatHandler creates a new chat handler
 func NewChatHandler(vectorDB vectordb.VectorDB, embedder embeddings.Embedder, auditLogStore *database.AuditLogStore, chatStore *database.ChatStore, orgStore *database.OrganizationStore, usageStore *database.UsageStore) *ChatHandler {
-	return &ChatHandler{
+	h := &ChatHandler{
 		vectorDB:      vectorDB,
 		embedder:      embedder,
 		auditLogStore: auditLogStore,
 		chatStore:     chatStore,
-		orgStore:      orgStore,
 		usageStore:    usageStore,
+		mmrLambda:     vectordb.DefaultMMRLambda,
+		generate:      completeAnswer,
+		timeout:       DefaultChatTimeout,
+		maxTopK:       DefaultMaxSearchTopK,
+	}
+	if orgStore != nil {
+		h.orgStore = orgStore
+	}
+	return h
+}
+
+// completeAnswer is the default answer generator, a chat completion from the configured AI provider
+func completeAnswer(ctx context.Context, systemPrompt, prompt string) (string, error) {
+	answer, usage, err := ai.Complete(ctx, systemPrompt, prompt, 0)
+	addChatUsage(ctx, usage)
+	return answer, err
+}
+
+// chatUsageContextKey carries the *chatUsage of the chat request being answered
+const chatUsageContextKey = "chat_usage"
+
+// chatUsage totals the tokens of every model call made to answer one chat request
+type chatUsage struct {
+	mu               sync.Mutex
+	promptTokens     int
+	completionTokens int
+	model            string
+}
+
+// addChatUsage adds a model call's tokens to the chat request's total, if the context carries one
+func addChatUsage(ctx context.Context, usage *ai.Usage) {
+	total, ok := ctx.Value(chatUsageContextKey).(*chatUsage)
+	if !ok || usage == nil {
+		return
+	}
+	total.mu.Lock()
+	defer total.mu.Unlock()
+	total.promptTokens += usage.InputTokens
+	total.completionTokens += usage.OutputTokens
+	if usage.Model != "" {
+		total.model = usage.Model
 	}
 }
 
+// recordUsage stores the tokens used to answer a chat request against the organization
+func (h *ChatHandler) recordUsage(orgID string, dbUser *database.User, usage *chatUsage) {
+	usage.mu.Lock()
+	promptTokens, completionTokens, model := usage.promptTokens, usage.completionTokens, usage.model
+	usage.mu.Unlock()
+	if h.usageStore == nil || promptTokens+completionTokens == 0 {
+		return
+	}
+
+	userID := ""
+	if dbUser != nil {
+		userID = dbUser.ID
+	}
+	if err := h.usageStore.RecordUsage(orgID, userID, promptTokens, completionTokens, model); err != nil {
+		log.Printf("Failed to record chat token usage for org %s: %v", orgID, err)
+	}
+}
+
+// SetQueryValidator sets the validator used to reject degenerate queries before embedding
+func (h *ChatHandler) SetQueryValidator(validator *QueryValidator) {
+	h.queryValidator = validator
+}
+
+// SetMMRLambda sets the MMR lambda used to diversify chat context (0-1; 1 keeps plain top-K)
+func (h *ChatHandler) SetMMRLambda(lambda float64) {
+	if lambda >= 0 && lambda <= 1 {
+		h.mmrLambda = lambda
+	}
+}
+
+// SetAnswerGenerator sets the model used to answer from retrieved context
+func (h *ChatHandler) SetAnswerGenerator(generate AnswerGenerator) {
+	h.generate = generate
+}
+
+// SetTimeout sets the overall budget for answering a chat request
+func (h *ChatHandler) SetTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		h.timeout = timeout
+	}
+}
+
+// SetMaxTopK sets the most chunks one chat retrieval may fetch, the same bound as search
+func (h *ChatHandler) SetMaxTopK(maxTopK int) {
+	if maxTopK > 0 {
+		h.maxTopK = maxTopK
+	}
+}
+
+// SetAnswerCache enables reuse of answers to repeated questions over an unchanged corpus
+func (h *ChatHandler) SetAnswerCache(cache *ChatAnswerCache) {
+	h.answerCache = cache
+}
+
+// SetGroundingPolicies sets the lookup for per-organization answer grounding policies
+func (h *ChatHandler) SetGroundingPolicies(policies GroundingPolicyLookup) {
+	h.grounding = policies
+}
+
+// SetHistoryLimiter sets the per-organization cap on the messages each chat session keeps
+func (h *ChatHandler) SetHistoryLimiter(history ChatHistoryLimiter) {
+	h.history = history
+}
+
+// generateAnswer answers the query from the retrieved chunks, applying the organization's grounding policy.
+// Too few chunks meeting the minimum citation policy get an insufficient-evidence refusal, and no chunks
+// at all get a no-documents reply without asking the model. Grounded organizations get a context-only
+// prompt, and with verification enabled a second pass checks the answer against the chunks; the
+// returned check is nil when neither applied.
+func (h *ChatHandler) generateAnswer(ctx context.Context, orgID, query string, matches []vectordb.Match) (string, *GroundingCheck, error) {
+	policy := h.groundingPolicy(orgID)
+
+	// Too little supporting context is refused rather than answered from thin evidence
+	if check := checkEvidence(policy, matches); check != nil {
+		log.Printf("[GROUNDING] Refused chat answer for org %s: insufficient evidence (%s)", orgID, check.Reason)
+		return InsufficientEvidenceAnswer, check, nil
+	}
+
+	systemPrompt := chatSystemPrompt
+	grounded := policy != nil && policy.Enabled
+	if grounded {
+		systemPrompt = groundedSystemPrompt
+	}
+	// Without context the model could only guess
+	if len(matches) == 0 {
+		if grounded {
+			return NotInDocumentsAnswer, nil, nil
+		}
+		return NoRelevantDocumentsAnswer, nil, nil
+	}
+	if systemContext := h.systemContext(orgID); systemContext != "" {
+		systemPrompt += "\n\nOrganization instructions:\n" + systemContext
+	}
+	// Reply in the language the question was asked in
+	if lang := langdetect.Detect(query).Code; lang != langdetect.Unknown && lang != "en" {
+		systemPrompt += " Answer in " + langdetect.Name(lang) + "."
+	}
+
+	answer, err := h.generate(ctx, systemPrompt, buildAnswerPrompt(query, matches))
+	if err != nil {
+		return "", nil, err
+	}
+
+	if policy == nil || !policy.VerifyAnswers || isNotInDocuments(answer) {
+		return answer, nil, nil
+	}
+	check := verifyGrounding(ctx, h.generate, answer, matches)
+	if !check.Grounded {
+		log.Printf("[GROUNDING] Flagged chat answer for org %s (verified=%v): %s", orgID, check.Verified, check.Reason)
+	}
+	return answer, check, nil
+}
+
+// systemContext returns the organization's system context for chat prompts, or "" if it has none or it can't be read
+func (h *ChatHandler) systemContext(orgID string) string {
+	if h.orgStore == nil || orgID == "" {
+		return ""
+	}
+	org, err := h.orgStore.GetOrganizationByID(orgID)
+	if err != nil {
+		log.Printf("Failed to load system context for org %s: %v", orgID, err)
+		return ""
+	}
+	if org == nil {
+		return ""
+	}
+	return strings.TrimSpace(org.SystemContext)
+}
+
+// groundingPolicy returns the organization's grounding policy, or nil if it has none or it can't be read
+func (h *ChatHandler) groundingPolicy(orgID string) *database.GroundingPolicy {
+	if h.grounding == nil {
+		return nil
+	}
+	policy, err := h.grounding.GetPolicy(orgID)
+	if err != nil {
+		log.Printf("Failed to load grounding policy for org %s: %v", orgID, err)
+	}
+	return policy
+}
+
+// retrieveContext returns the chunks used as chat context
+// When the vector database can return vectors, a wider candidate set is re-selected with MMR
+// so near-duplicate chunks don't crowd out other relevant ones
+func (h *ChatHandler) retrieveContext(ctx context.Context, queryVector []float32, orgID string) ([]vectordb.Match, error) {
+	contextChunks := clampTopK(chatContextChunks, chatContextChunks, h.maxTopK)
+	searcher, ok := h.vectorDB.(vectordb.VectorSearcher)
+	if !ok || h.mmrLambda >= 1 {
+		return h.vectorDB.Search(ctx, queryVector, contextChunks, orgID)
+	}
+
+	candidates, err := searcher.SearchWithVectors(ctx, queryVector, clampTopK(contextChunks*mmrCandidateMultiplier, contextChunks, h.maxTopK), orgID)
+	if err != nil {
+		return nil, err
+	}
+	return vectordb.MMR(candidates, contextChunks, h.mmrLambda), nil
+}
+
 // ChatRequest represents a chat request
 type ChatRequest struct {
 	Query     string `json:"query"`
@@ -47,6 +271,44 @@ type ChatResponse struct {
 	Answer    string                   `json:"answer"`
 	SessionID string                   `json:"session_id"`
 	Citations []map[string]interface{} `json:"citations,omitempty"`
+	Grounding *GroundingCheck          `json:"grounding,omitempty"` // Set when the organization verifies answers or the answer was refused
+}
+
+// ChatTimeoutResponse is returned with 504 when the chat budget runs out, with whatever was found before it did
+type ChatTimeoutResponse struct {
+	Error     string                   `json:"error"`
+	Stage     string                   `json:"stage"` // embedding, search or generation
+	Citations []map[string]interface{} `json:"citations"`
+}
+
+// writeChatTimeout responds with 504 for a chat that ran out of time at the given stage
+func writeChatTimeout(w http.ResponseWriter, stage string, matches []vectordb.Match) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusGatewayTimeout)
+	json.NewEncoder(w).Encode(ChatTimeoutResponse{
+		Error:     fmt.Sprintf("chat timed out during %s", stage),
+		Stage:     stage,
+		Citations: buildCitations(matches),
+	})
+}
+
+// buildCitations converts the context chunks to the citations returned and stored with an answer
+func buildCitations(matches []vectordb.Match) []map[string]interface{} {
+	citations := make([]map[string]interface{}, 0, len(matches))
+	for _, match := range matches {
+		content := match.Metadata["content"]
+		chunkID := match.Metadata["chunk_id"]
+		if chunkID == "" {
+			chunkID = match.ID
+		}
+		citations = append(citations, map[string]interface{}{
+			"document_id": match.DocumentID,
+			"chunk_id":    chunkID,
+			"content":     content,
+			"score":       match.Score,
+		})
+	}
+	return citations
 }
 
 // HandleChat handles POST /api/v1/chat
@@ -58,6 +320,10 @@ func (h *ChatHandler) HandleChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, ok := requireAcceptable(w, r, contentTypeJSON); !ok {
+		return
+	}
+
 	var req ChatRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		w.Header().Set("Content-Type", "application/json")
@@ -73,21 +339,13 @@ func (h *ChatHandler) HandleChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get user from context
-	user := r.Context().Value("user")
-	if user == nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(map[string]string{"error": "not authenticated"})
-		return
-	}
-
-	dbUser, ok := user.(*database.User)
-	if !ok {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "invalid user type"})
-		return
+	if h.queryValidator != nil {
+		if err := h.queryValidator.Validate(req.Query); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
 	}
 
 	// Get organization ID from context
@@ -98,14 +356,46 @@ func (h *ChatHandler) HandleChat(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Get user from context; API-key callers have no user, so their chats aren't saved as sessions
+	var dbUser *database.User
+	if user := r.Context().Value("user"); user != nil {
+		var ok bool
+		if dbUser, ok = user.(*database.User); !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid user type"})
+			return
+		}
+	} else if _, isAPIKey := r.Context().Value("api_key").(string); !isAPIKey || orgID == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "not authenticated"})
+		return
+	}
+
+	// A repeated question over an unchanged corpus is answered from the cache
+	var corpusVersion uint64
+	if h.answerCache != nil {
+		if cached, ok := h.answerCache.Get(orgID, req.Query); ok {
+			h.writeChatAnswer(w, dbUser, orgID, req, cached.answer, cached.citations, cached.grounding)
+			return
+		}
+		corpusVersion = h.answerCache.CorpusVersion(orgID)
+	}
+
+	// Embedding, search and generation share one budget and are cancelled together
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+	usage := &chatUsage{}
+	ctx = context.WithValue(ctx, chatUsageContextKey, usage)
+
 	// Generate query embedding
-	ctx := r.Context()
 	var queryVector []float32
 	var err error
 
 	if h.embedder != nil {
 		queryVector, err = h.embedder.EmbedText(ctx, req.Query)
-		if err != nil {
+		if err != nil && ctx.Err() == nil {
 			log.Printf("Failed to generate embedding with embedder: %v, falling back to ai.GenerateEmbedding", err)
 			queryVector, err = ai.GenerateEmbedding(req.Query)
 		}
@@ -113,6 +403,11 @@ func (h *ChatHandler) HandleChat(w http.ResponseWriter, r *http.Request) {
 		queryVector, err = ai.GenerateEmbedding(req.Query)
 	}
 
+	if ctx.Err() == context.DeadlineExceeded {
+		log.Printf("Chat timed out after %s while embedding the query", h.timeout)
+		writeChatTimeout(w, "embedding", nil)
+		return
+	}
 	if err != nil {
 		log.Printf("Failed to generate query embedding: %v", err)
 		w.Header().Set("Content-Type", "application/json")
@@ -122,7 +417,12 @@ func (h *ChatHandler) HandleChat(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Search for relevant context
-	matches, err := h.vectorDB.Search(ctx, queryVector, 5, orgID)
+	matches, err := h.retrieveContext(ctx, queryVector, orgID)
+	if ctx.Err() == context.DeadlineExceeded {
+		log.Printf("Chat timed out after %s while searching", h.timeout)
+		writeChatTimeout(w, "search", nil)
+		return
+	}
 	if err != nil {
 		log.Printf("Failed to search: %v", err)
 		w.Header().Set("Content-Type", "application/json")
@@ -131,27 +431,44 @@ func (h *ChatHandler) HandleChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Build context from matches
-	contextText := ""
-	for _, match := range matches {
-		// Extract content from metadata
-		content := match.Metadata["content"]
-		if content == "" {
-			content = "No content available"
-		}
-		contextText += content + "\n\n"
+	// Only chunks with content are passed to the model, so only they are cited
+	matches = contextMatches(matches)
+
+	answer, grounding, err := h.generateAnswer(ctx, orgID, req.Query, matches)
+	// Tokens are billed even when the answer can't be used
+	h.recordUsage(orgID, dbUser, usage)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		log.Printf("Chat timed out after %s while generating the answer", h.timeout)
+		writeChatTimeout(w, "generation", matches)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to generate chat answer: %v", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		json.NewEncoder(w).Encode(map[string]string{"error": "failed to generate answer"})
+		return
 	}
 
-	// Generate answer using AI (simple implementation - just return the query for now)
-	// TODO: Implement proper AI answer generation
-	answer := fmt.Sprintf("Based on the context: %s", req.Query)
-	if contextText != "" {
-		answer = fmt.Sprintf("Based on the search results, here's what I found related to your question: %s", req.Query)
+	citations := buildCitations(matches)
+	if h.answerCache != nil {
+		h.answerCache.Put(orgID, req.Query, corpusVersion, cachedChatAnswer{
+			answer:    answer,
+			citations: citations,
+			grounding: grounding,
+		})
 	}
 
+	h.writeChatAnswer(w, dbUser, orgID, req, answer, citations, grounding)
+}
+
+// writeChatAnswer saves the exchange to the user's session (creating one if needed) and writes the response
+func (h *ChatHandler) writeChatAnswer(w http.ResponseWriter, dbUser *database.User, orgID string, req ChatRequest, answer string, citations []map[string]interface{}, grounding *GroundingCheck) {
 	// Create or get session
 	sessionID := req.SessionID
-	if sessionID == "" {
+	if dbUser == nil {
+		sessionID = ""
+	} else if sessionID == "" {
 		// Create new session
 		session, err := h.chatStore.CreateSession(dbUser.ID, orgID, req.Query)
 		if err != nil {
@@ -169,53 +486,24 @@ func (h *ChatHandler) HandleChat(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Save assistant message with citations
-		citations := make([]map[string]interface{}, 0)
-		for _, match := range matches {
-			content := match.Metadata["content"]
-			if content == "" {
-				content = "No content available"
-			}
-			chunkID := match.Metadata["chunk_id"]
-			if chunkID == "" {
-				chunkID = match.ID
-			}
-			citations = append(citations, map[string]interface{}{
-				"document_id": match.DocumentID,
-				"chunk_id":    chunkID,
-				"content":     content,
-				"score":       match.Score,
-			})
-		}
-
-		if err := h.chatStore.AddMessage(sessionID, "assistant", answer, map[string]interface{}{
+		messageMetadata := map[string]interface{}{
 			"citations": citations,
-		}); err != nil {
+		}
+		if grounding != nil {
+			messageMetadata["grounding"] = grounding
+		}
+		if err := h.chatStore.AddMessage(sessionID, "assistant", answer, messageMetadata); err != nil {
 			log.Printf("Failed to save assistant message: %v", err)
 		}
+		trimChatHistory(context.Background(), h.history, orgID, sessionID)
 	}
 
 	// Build response
 	response := ChatResponse{
 		Answer:    answer,
 		SessionID: sessionID,
-		Citations: make([]map[string]interface{}, 0),
-	}
-
-	for _, match := range matches {
-		content := match.Metadata["content"]
-		if content == "" {
-			content = "No content available"
-		}
-		chunkID := match.Metadata["chunk_id"]
-		if chunkID == "" {
-			chunkID = match.ID
-		}
-		response.Citations = append(response.Citations, map[string]interface{}{
-			"document_id": match.DocumentID,
-			"chunk_id":    chunkID,
-			"content":     content,
-			"score":       match.Score,
-		})
+		Citations: citations,
+		Grounding: grounding,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -229,7 +517,7 @@ func HandleChatPage(w http.ResponseWriter, r *http.Request, metadataStore *datab
 		return
 	}
 
-	if err := renderTemplate(w, "chat.html", nil); err != nil {
+	if err := renderTemplate(w, "chat.html", newPageData(r, metadataStore, orgStore)); err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -242,9 +530,8 @@ func HandleAnalystPage(w http.ResponseWriter, r *http.Request, metadataStore *da
 		return
 	}
 
-	if err := renderTemplate(w, "analyst.html", nil); err != nil {
+	if err := renderTemplate(w, "analyst.html", newPageData(r, metadataStore, orgStore)); err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 }
-
@@ -0,0 +1,259 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/the-hive/internal/ai"
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/proto"
+	"github.com/the-hive/internal/vectordb"
+)
+
+func TestHandleChat_GenerationTimeoutReturns504WithCitations(t *testing.T) {
+	vdb := &fakeVectorDB{matches: []vectordb.Match{
+		{ID: "c1", DocumentID: "handbook.pdf", Score: 0.9, Metadata: map[string]string{"content": "Holidays are approved by your manager."}},
+	}}
+	h := NewChatHandler(vdb, embeddings.NewMockEmbedder(8), nil, nil, nil, nil)
+	h.SetMMRLambda(1)
+	h.SetTimeout(50 * time.Millisecond)
+
+	cancelled := make(chan struct{})
+	h.SetAnswerGenerator(func(ctx context.Context, systemPrompt, prompt string) (string, error) {
+		// A hung provider that only gives up when the chat budget is cancelled
+		<-ctx.Done()
+		close(cancelled)
+		return "", ctx.Err()
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/chat", strings.NewReader(`{"query":"Who approves holidays?"}`))
+	req = withUser(req, &database.User{ID: "alice"}, "org-a")
+	rec := httptest.NewRecorder()
+
+	start := time.Now()
+	h.HandleChat(rec, req)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Expected the chat to give up after its timeout, took %s", elapsed)
+	}
+
+	select {
+	case <-cancelled:
+	default:
+		t.Error("Expected the generator's context to be cancelled")
+	}
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("Expected 504, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp ChatTimeoutResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Stage != "generation" || len(resp.Citations) != 1 || resp.Citations[0]["document_id"] != "handbook.pdf" {
+		t.Errorf("Expected a generation timeout with the citations found, got %+v", resp)
+	}
+}
+
+func TestHandleChat_AnswerCacheInvalidatedByIngest(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	db := newTestDB(t)
+	if _, err := db.Exec(`CREATE TABLE chunks (
+		id TEXT PRIMARY KEY,
+		document_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		chunk_index INTEGER NOT NULL,
+		organization_id TEXT
+	)`); err != nil {
+		t.Fatalf("Failed to create chunks table: %v", err)
+	}
+	vdb := vectordb.NewMemoryVectorDB()
+	embedder := embeddings.NewMockEmbedder(8)
+	cache := NewChatAnswerCache(time.Hour)
+	hive := NewHiveService(db, vdb, embedder)
+	hive.SetAnswerCache(cache)
+
+	ingest := func(id, content string) {
+		t.Helper()
+		status, err := hive.Ingest(context.Background(), &proto.Chunk{
+			Id: id, DocumentId: "handbook.txt", Content: content,
+			Metadata: map[string]string{"organization_id": "org-a"},
+		})
+		if err != nil || !status.Success {
+			t.Fatalf("Ingest failed: %v %+v", err, status)
+		}
+	}
+	ingest("c1", "Holidays are approved by your manager.")
+
+	h := NewChatHandler(vdb, embedder, nil, nil, nil, nil)
+	h.SetMMRLambda(1)
+	h.SetAnswerCache(cache)
+	generations := 0
+	h.SetAnswerGenerator(func(ctx context.Context, systemPrompt, prompt string) (string, error) {
+		generations++
+		return "Your manager approves holidays.", nil
+	})
+
+	chat := func(query string) ChatResponse {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/chat", strings.NewReader(`{"query":"`+query+`"}`))
+		ctx := context.WithValue(req.Context(), "api_key", "key-1")
+		ctx = context.WithValue(ctx, "organization_id", "org-a")
+		rec := httptest.NewRecorder()
+		h.HandleChat(rec, req.WithContext(ctx))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp ChatResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return resp
+	}
+
+	first := chat("Who approves holidays?")
+	second := chat("  who approves HOLIDAYS ")
+	if generations != 1 {
+		t.Fatalf("Expected the repeated question to be answered from the cache, generated %d times", generations)
+	}
+	if second.Answer != first.Answer || len(second.Citations) != len(first.Citations) || len(second.Citations) == 0 {
+		t.Errorf("Expected the cached answer and citations, got %+v (first %+v)", second, first)
+	}
+
+	ingest("c2", "Sick days do not need approval.")
+	chat("Who approves holidays?")
+	if generations != 2 {
+		t.Errorf("Expected an ingest to invalidate the cached answer, generated %d times", generations)
+	}
+}
+
+// staticOrganizations resolves organizations from a fixed map
+type staticOrganizations map[string]*database.Organization
+
+func (o staticOrganizations) GetOrganizationByID(id string) (*database.Organization, error) {
+	return o[id], nil
+}
+
+func TestHandleChat_AnswersFromRetrievedContext(t *testing.T) {
+	vdb := &fakeVectorDB{matches: []vectordb.Match{
+		{ID: "c1", DocumentID: "handbook.pdf", Score: 0.9, Metadata: map[string]string{"content": "Holidays are approved by your manager."}},
+		{ID: "c2", DocumentID: "scan.pdf", Score: 0.8, Metadata: map[string]string{}},
+		{ID: "c3", DocumentID: "policy.pdf", Score: 0.7, Metadata: map[string]string{"content": "Requests need two weeks' notice."}},
+	}}
+	h := NewChatHandler(vdb, embeddings.NewMockEmbedder(8), nil, nil, nil, nil)
+	h.SetMMRLambda(1)
+	h.orgStore = staticOrganizations{"org-a": {ID: "org-a", SystemContext: "We are a Dutch logistics company."}}
+
+	var systemPrompt, prompt string
+	h.SetAnswerGenerator(func(ctx context.Context, system, user string) (string, error) {
+		systemPrompt, prompt = system, user
+		return "Your manager approves holidays [1], with two weeks' notice [2].", nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/chat", strings.NewReader(`{"query":"Who approves holidays?"}`))
+	ctx := context.WithValue(req.Context(), "api_key", "key-1")
+	ctx = context.WithValue(ctx, "organization_id", "org-a")
+	rec := httptest.NewRecorder()
+	h.HandleChat(rec, req.WithContext(ctx))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp ChatResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if resp.Answer != "Your manager approves holidays [1], with two weeks' notice [2]." {
+		t.Errorf("Expected the model's answer, got %q", resp.Answer)
+	}
+	if !strings.Contains(systemPrompt, "We are a Dutch logistics company.") {
+		t.Errorf("Expected the organization's system context in the system prompt, got %q", systemPrompt)
+	}
+	if !strings.Contains(prompt, "[1] (handbook.pdf)") || !strings.Contains(prompt, "[2] (policy.pdf)") || !strings.Contains(prompt, "Who approves holidays?") {
+		t.Errorf("Expected the numbered context passages and the question in the prompt, got %q", prompt)
+	}
+	if len(resp.Citations) != 2 || resp.Citations[0]["document_id"] != "handbook.pdf" || resp.Citations[1]["document_id"] != "policy.pdf" {
+		t.Errorf("Expected citations for the two chunks passed to the model, got %+v", resp.Citations)
+	}
+}
+
+func TestHandleChat_NoContextSaysNoDocumentsFound(t *testing.T) {
+	h := NewChatHandler(&fakeVectorDB{}, embeddings.NewMockEmbedder(8), nil, nil, nil, nil)
+	h.SetMMRLambda(1)
+	calls := 0
+	h.SetAnswerGenerator(func(ctx context.Context, systemPrompt, prompt string) (string, error) {
+		calls++
+		return "Probably your manager.", nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/chat", strings.NewReader(`{"query":"Who approves holidays?"}`))
+	ctx := context.WithValue(req.Context(), "api_key", "key-1")
+	ctx = context.WithValue(ctx, "organization_id", "org-a")
+	rec := httptest.NewRecorder()
+	h.HandleChat(rec, req.WithContext(ctx))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp ChatResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Answer != NoRelevantDocumentsAnswer || calls != 0 {
+		t.Errorf("Expected the no-documents reply without a model call, got %q after %d calls", resp.Answer, calls)
+	}
+	if len(resp.Citations) != 0 {
+		t.Errorf("Expected no citations, got %+v", resp.Citations)
+	}
+}
+
+func TestHandleChat_RecordsTokenUsage(t *testing.T) {
+	usageStore, err := database.NewUsageStore(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Failed to create usage store: %v", err)
+	}
+	vdb := &fakeVectorDB{matches: []vectordb.Match{
+		{ID: "c1", DocumentID: "handbook.pdf", Score: 0.9, Metadata: map[string]string{"content": "Holidays are approved by your manager."}},
+	}}
+	h := NewChatHandler(vdb, embeddings.NewMockEmbedder(8), nil, nil, nil, usageStore)
+	h.SetMMRLambda(1)
+	h.SetAnswerGenerator(func(ctx context.Context, systemPrompt, prompt string) (string, error) {
+		addChatUsage(ctx, &ai.Usage{InputTokens: 120, OutputTokens: 30, Model: "gpt-test"})
+		return "Your manager approves holidays [1].", nil
+	})
+
+	chat := func() {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/chat", strings.NewReader(`{"query":"Who approves holidays?"}`))
+		ctx := context.WithValue(req.Context(), "api_key", "key-1")
+		ctx = context.WithValue(ctx, "organization_id", "org-a")
+		rec := httptest.NewRecorder()
+		h.HandleChat(rec, req.WithContext(ctx))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	chat()
+	totals, err := usageStore.TokenTotalsSince(database.BillingPeriodStart(time.Now()))
+	if err != nil {
+		t.Fatalf("TokenTotalsSince failed: %v", err)
+	}
+	if totals["org-a"] != 150 {
+		t.Fatalf("Expected 150 tokens recorded for org-a, got %d", totals["org-a"])
+	}
+
+	chat()
+	totals, err = usageStore.TokenTotalsSince(database.BillingPeriodStart(time.Now()))
+	if err != nil {
+		t.Fatalf("TokenTotalsSince failed: %v", err)
+	}
+	if totals["org-a"] != 300 || len(totals) != 1 {
+		t.Errorf("Expected a second chat to bring org-a to 300 tokens, got %v", totals)
+	}
+}
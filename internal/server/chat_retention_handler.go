@@ -0,0 +1,199 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/the-hive/internal/database"
+)
+
+// ChatRetentionRequest sets how long the organization keeps chat sessions, and how many messages each keeps
+type ChatRetentionRequest struct {
+	RetentionDays int  `json:"retention_days"`         // 0 keeps sessions indefinitely
+	MaxMessages   *int `json:"max_messages,omitempty"` // 0 keeps every message; omitted leaves it unchanged
+}
+
+// MinChatHistoryMessages is the smallest history cap, so a session keeps at least its latest exchange
+const MinChatHistoryMessages = 2
+
+// ChatHistoryLimiter caps how many messages the organization's chat sessions keep
+type ChatHistoryLimiter interface {
+	GetRetentionPolicy(ctx context.Context, organizationID string) (*database.ChatRetentionPolicy, error)
+	TrimSession(ctx context.Context, sessionID string, maxMessages int) (int, error)
+}
+
+// trimChatHistory applies the organization's message cap to a session, folding older messages into
+// a summary message. Failures are logged: the session just keeps its messages until the next trim.
+func trimChatHistory(ctx context.Context, history ChatHistoryLimiter, orgID, sessionID string) {
+	if history == nil || sessionID == "" {
+		return
+	}
+	policy, err := history.GetRetentionPolicy(ctx, orgID)
+	if err != nil {
+		log.Printf("Failed to get chat history limit for org %s: %v", orgID, err)
+		return
+	}
+	if policy.MaxMessages <= 0 {
+		return
+	}
+	if trimmed, err := history.TrimSession(ctx, sessionID, policy.MaxMessages); err != nil {
+		log.Printf("Failed to trim chat session %s: %v", sessionID, err)
+	} else if trimmed > 0 {
+		log.Printf("Summarized %d earlier messages of chat session %s (limit %d)", trimmed, sessionID, policy.MaxMessages)
+	}
+}
+
+// HandleChatRetentionPolicy handles GET and PUT /api/v1/settings/chat-retention (admin)
+func HandleChatRetentionPolicy(w http.ResponseWriter, r *http.Request, store *database.ChatRetentionStore, auditLogStore *database.AuditLogStore) {
+	_, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req ChatRetentionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		if req.RetentionDays < 0 {
+			writeJSONError(w, http.StatusBadRequest, "retention_days must not be negative")
+			return
+		}
+		if req.MaxMessages != nil && *req.MaxMessages != 0 && *req.MaxMessages < MinChatHistoryMessages {
+			writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("max_messages must be 0 (unlimited) or at least %d", MinChatHistoryMessages))
+			return
+		}
+		previous, err := store.GetRetentionPolicy(r.Context(), orgID)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if err := store.SetRetentionDays(r.Context(), orgID, req.RetentionDays); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		changes := []configChange{{Setting: "chat_retention_days", Before: previous.RetentionDays, After: req.RetentionDays}}
+		if req.MaxMessages != nil {
+			if err := store.SetMaxMessages(r.Context(), orgID, *req.MaxMessages); err != nil {
+				writeJSONError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			changes = append(changes, configChange{Setting: "chat_max_messages", Before: previous.MaxMessages, After: *req.MaxMessages})
+		}
+		auditConfigChanges(auditLogStore, r, orgID, changes...)
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	policy, err := store.GetRetentionPolicy(r.Context(), orgID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policy)
+}
+
+// HandleChatSessionPin handles PUT (pin) and DELETE (unpin) on /api/v1/chat/sessions/{id}/pin.
+// Pinned sessions are kept by retention pruning and bulk deletes.
+func HandleChatSessionPin(w http.ResponseWriter, r *http.Request, store *database.ChatRetentionStore) {
+	var pinned bool
+	switch r.Method {
+	case http.MethodPut:
+		pinned = true
+	case http.MethodDelete:
+		pinned = false
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	sessionID := r.PathValue("id")
+	err := store.SetPinned(r.Context(), sessionID, dbUser.ID, orgID, pinned)
+	if errors.Is(err, database.ErrChatSessionNotFound) {
+		writeJSONError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to update pin for chat session %s: %v", sessionID, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to update session")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"session_id": sessionID, "pinned": pinned})
+}
+
+// HandlePinnedChatSessions handles GET /api/v1/chat/sessions/pinned (the caller's pinned session IDs)
+func HandlePinnedChatSessions(w http.ResponseWriter, r *http.Request, store *database.ChatRetentionStore) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	ids, err := store.PinnedSessionIDs(r.Context(), dbUser.ID, orgID)
+	if err != nil {
+		log.Printf("Failed to list pinned chat sessions: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to list pinned sessions")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"session_ids": ids})
+}
+
+// HandleBulkDeleteChatSessions handles DELETE /api/v1/chat/sessions: it deletes the caller's own
+// sessions, optionally only those idle for ?older_than_days=N. Pinned sessions are kept unless
+// ?include_pinned=true.
+func HandleBulkDeleteChatSessions(w http.ResponseWriter, r *http.Request, store *database.ChatRetentionStore) {
+	if r.Method != http.MethodDelete {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	var olderThan time.Time
+	if daysStr := r.URL.Query().Get("older_than_days"); daysStr != "" {
+		days, err := strconv.Atoi(daysStr)
+		if err != nil || days < 0 {
+			writeJSONError(w, http.StatusBadRequest, "older_than_days must be a non-negative integer")
+			return
+		}
+		olderThan = time.Now().AddDate(0, 0, -days)
+	}
+	includePinned, _ := strconv.ParseBool(r.URL.Query().Get("include_pinned"))
+
+	deleted, err := store.DeleteUserSessions(r.Context(), dbUser.ID, orgID, olderThan, includePinned)
+	if err != nil {
+		log.Printf("Failed to bulk delete chat sessions for user %s: %v", dbUser.ID, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to delete sessions")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"deleted": deleted})
+}
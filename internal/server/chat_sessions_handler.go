@@ -101,7 +101,8 @@ func HandleCreateSession(w http.ResponseWriter, r *http.Request, chatStore *data
 }
 
 // HandleGetSessionMessages handles GET /api/v1/chat/sessions/{id}/messages
-func HandleGetSessionMessages(w http.ResponseWriter, r *http.Request, chatStore *database.ChatStore) {
+// Sessions longer than the organization's history cap are trimmed first, leaving a summary of the older messages.
+func HandleGetSessionMessages(w http.ResponseWriter, r *http.Request, chatStore *database.ChatStore, history ChatHistoryLimiter) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -120,6 +121,9 @@ func HandleGetSessionMessages(w http.ResponseWriter, r *http.Request, chatStore
 		return
 	}
 
+	orgID, _ := r.Context().Value("organization_id").(string)
+	trimChatHistory(r.Context(), history, orgID, sessionID)
+
 	// Get messages for session
 	messages, err := chatStore.GetSessionMessages(sessionID)
 	if err != nil {
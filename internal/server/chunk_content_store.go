@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ChunkContentStore serves full chunk content from the SQLite chunks table, so vector payloads can
+// keep only a preview (PAYLOAD_CONTENT_PREVIEW_CHARS). It implements vectordb.ContentStore.
+type ChunkContentStore struct {
+	db *sql.DB
+}
+
+// NewChunkContentStore creates a content store over the chunks table
+func NewChunkContentStore(db *sql.DB) *ChunkContentStore {
+	return &ChunkContentStore{db: db}
+}
+
+// SaveContent writes a chunk's full content. Ingest usually stored the chunk already; this keeps
+// points upserted by other paths (HTTP ingest, re-chunking) resolvable too.
+func (s *ChunkContentStore) SaveContent(ctx context.Context, id string, metadata map[string]string, content string) error {
+	documentID := metadata["document_id"]
+	if documentID == "" {
+		documentID = id
+	}
+	chunkIndex, _ := strconv.Atoi(metadata["chunk_index"])
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO chunks (id, document_id, content, chunk_index, organization_id)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET content = excluded.content
+	`, id, documentID, content, chunkIndex, metadata["organization_id"])
+	if err != nil {
+		return fmt.Errorf("failed to save chunk content: %w", err)
+	}
+	return nil
+}
+
+// LoadContent returns the content of the given chunks keyed by ID
+func (s *ChunkContentStore) LoadContent(ctx context.Context, ids []string) (map[string]string, error) {
+	return s.LoadOrganizationContent(ctx, ids, "")
+}
+
+// LoadOrganizationContent returns the content of the given chunks keyed by ID in one query, leaving
+// out chunks of other organizations. An empty organizationID doesn't filter.
+func (s *ChunkContentStore) LoadOrganizationContent(ctx context.Context, ids []string, organizationID string) (map[string]string, error) {
+	contents := make(map[string]string, len(ids))
+	if len(ids) == 0 {
+		return contents, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, len(ids), len(ids)+1)
+	for i, id := range ids {
+		args[i] = id
+	}
+	query := "SELECT id, content FROM chunks WHERE id IN (" + placeholders + ")"
+	if organizationID != "" {
+		query += " AND organization_id = ?"
+		args = append(args, organizationID)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chunk content: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, content string
+		if err := rows.Scan(&id, &content); err != nil {
+			return nil, fmt.Errorf("failed to scan chunk content: %w", err)
+		}
+		contents[id] = content
+	}
+	return contents, rows.Err()
+}
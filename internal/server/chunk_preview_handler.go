@@ -0,0 +1,85 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"unicode/utf8"
+)
+
+// ChunkPreviewRequest is content to chunk without ingesting it
+type ChunkPreviewRequest struct {
+	Content  string `json:"content"`
+	Filetype string `json:"filetype,omitempty"`
+}
+
+// ChunkPreview is one chunk as ingest would store it. Start and End are byte offsets
+// into the content as it would be stored (after PII masking, if the org masks PII).
+type ChunkPreview struct {
+	Index   int    `json:"index"`
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+	Chars   int    `json:"chars"`
+	Content string `json:"content"`
+}
+
+// ChunkPreviewResponse describes how the server would chunk the content with its current settings
+type ChunkPreviewResponse struct {
+	Filetype     string         `json:"filetype,omitempty"`
+	ChunkSize    int            `json:"chunk_size"`
+	ChunkOverlap int            `json:"chunk_overlap"`
+	TotalChars   int            `json:"total_chars"`
+	ChunkCount   int            `json:"chunk_count"`
+	Masked       bool           `json:"masked"` // PII masking changed the content before chunking
+	Chunks       []ChunkPreview `json:"chunks"`
+}
+
+// HandleChunkPreview handles POST /api/v1/chunk/preview: it runs the ingest chunker over the
+// content and returns the chunk boundaries without embedding or storing anything
+func (h *IngestHandler) HandleChunkPreview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req ChunkPreviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	if req.Content == "" {
+		writeJSONError(w, http.StatusBadRequest, "content is required")
+		return
+	}
+
+	orgID, _ := r.Context().Value("organization_id").(string)
+	content := req.Content
+	if h.piiGuard != nil {
+		content = h.piiGuard.Masked(content, orgID)
+	}
+
+	chunkSize, chunkOverlap := h.chunker.Settings()
+	resp := ChunkPreviewResponse{
+		Filetype:     req.Filetype,
+		ChunkSize:    chunkSize,
+		ChunkOverlap: chunkOverlap,
+		TotalChars:   utf8.RuneCountInString(content),
+		Masked:       content != req.Content,
+		Chunks:       []ChunkPreview{},
+	}
+	for i, span := range h.chunker.ChunkSpans(content) {
+		chunk := content[span.Start:span.End]
+		resp.Chunks = append(resp.Chunks, ChunkPreview{
+			Index:   i,
+			Start:   span.Start,
+			End:     span.End,
+			Chars:   utf8.RuneCountInString(chunk),
+			Content: chunk,
+		})
+	}
+	resp.ChunkCount = len(resp.Chunks)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
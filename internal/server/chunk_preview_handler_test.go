@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/processor"
+)
+
+func TestHandleChunkPreview_MatchesIngest(t *testing.T) {
+	// Without an API key embeddings are placeholder vectors, so ingest runs offline
+	t.Setenv("OPENAI_API_KEY", "")
+
+	var text strings.Builder
+	for i := 0; i < 40; i++ {
+		text.WriteString("Section " + strconv.Itoa(i) + " covers the quarterly budget review in detail. ")
+		if i%7 == 6 {
+			text.WriteString("\n\n")
+		}
+	}
+	content := text.String()
+
+	vdb := &pointsVectorDB{points: map[string]map[string]string{}}
+	handler := NewIngestHandler(vdb, nil, nil, nil, nil, nil)
+	handler.chunker = processor.NewChunkerWithSettings(300, 50)
+
+	body, _ := json.Marshal(ChunkPreviewRequest{Content: content, Filetype: ".txt"})
+	rec := httptest.NewRecorder()
+	handler.HandleChunkPreview(rec, withUser(httptest.NewRequest(http.MethodPost, "/api/v1/chunk/preview", bytes.NewReader(body)), nil, "org-1"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var preview ChunkPreviewResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &preview); err != nil {
+		t.Fatalf("Failed to decode preview: %v", err)
+	}
+	if preview.ChunkSize != 300 || preview.ChunkOverlap != 50 || preview.Filetype != ".txt" {
+		t.Errorf("Expected the handler's chunk settings echoed, got %+v", preview)
+	}
+	if preview.ChunkCount < 2 || preview.ChunkCount != len(preview.Chunks) {
+		t.Fatalf("Expected several chunks, got count %d with %d chunks", preview.ChunkCount, len(preview.Chunks))
+	}
+	for i, chunk := range preview.Chunks {
+		if chunk.Index != i || content[chunk.Start:chunk.End] != chunk.Content {
+			t.Errorf("Chunk %d boundaries [%d, %d) don't match its content", i, chunk.Start, chunk.End)
+		}
+	}
+	if len(vdb.points) != 0 {
+		t.Fatalf("Expected the preview not to store anything, got %d points", len(vdb.points))
+	}
+
+	body, _ = json.Marshal(IngestRequest{FilePath: "budget.txt", Content: content})
+	rec = httptest.NewRecorder()
+	handler.HandleIngest(rec, withUser(httptest.NewRequest(http.MethodPost, "/api/v1/ingest", bytes.NewReader(body)), nil, "org-1"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected ingest to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	ingested := map[int]string{}
+	for _, metadata := range vdb.points {
+		index, _ := strconv.Atoi(metadata["chunk_index"])
+		ingested[index] = metadata["content"]
+	}
+	if len(ingested) != preview.ChunkCount {
+		t.Fatalf("Expected ingest to store %d chunks like the preview, got %d", preview.ChunkCount, len(ingested))
+	}
+	for _, chunk := range preview.Chunks {
+		if ingested[chunk.Index] != chunk.Content {
+			t.Errorf("Chunk %d differs between preview and ingest:\npreview: %q\ningest:  %q", chunk.Index, chunk.Content, ingested[chunk.Index])
+		}
+	}
+}
+
+func TestHandleChunkPreview_RequiresContent(t *testing.T) {
+	handler := NewIngestHandler(nil, nil, nil, nil, nil, nil)
+	rec := httptest.NewRecorder()
+	handler.HandleChunkPreview(rec, httptest.NewRequest(http.MethodPost, "/api/v1/chunk/preview", strings.NewReader(`{"filetype":".md"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without content, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/the-hive/internal/database"
+)
+
+// maxAuditedConfigValue bounds how much of a long setting (e.g. a system context) is written to the audit log
+const maxAuditedConfigValue = 500
+
+// configChange is one setting changed by an admin; Secret values are masked in the audit log
+type configChange struct {
+	Setting string
+	Before  interface{}
+	After   interface{}
+	Secret  bool
+}
+
+// embeddedSecretPattern matches credentials pasted into free-text settings (OpenAI, Gemini and Hive API keys)
+var embeddedSecretPattern = regexp.MustCompile(`sk-[A-Za-z0-9_\-]{8,}|AIza[A-Za-z0-9_\-]{20,}|hive_[A-Za-z0-9\-]{16,}`)
+
+// urlCredentialsPattern matches the user:password part of a URL such as redis://:secret@host:6379
+var urlCredentialsPattern = regexp.MustCompile(`://[^/@\s]+@`)
+
+// auditConfigChanges records who changed which settings of the organization (empty for server-wide
+// settings) from what to what. Settings whose value didn't change are left out; nothing is logged
+// if none changed.
+func auditConfigChanges(auditLogStore *database.AuditLogStore, r *http.Request, orgID string, changes ...configChange) {
+	if auditLogStore == nil {
+		return
+	}
+
+	var parts []string
+	for _, change := range changes {
+		before := auditedConfigValue(change.Before, change.Secret)
+		after := auditedConfigValue(change.After, change.Secret)
+		// Masked secrets can look alike, so those are compared unmasked
+		if before == after && (!change.Secret || reflect.DeepEqual(change.Before, change.After)) {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s from %s to %s", change.Setting, before, after))
+	}
+	if len(parts) == 0 {
+		return
+	}
+
+	details := fmt.Sprintf("Configuration changed by %s: %s", configChangeActor(r), strings.Join(parts, "; "))
+	if err := auditLogStore.LogAction(getClientIP(r), database.AuditActionConfigChange, details, orgID); err != nil {
+		log.Printf("Failed to log configuration change: %v", err)
+	}
+}
+
+// configChangeActor names who made a request: the user's email, or the API key's masked value
+func configChangeActor(r *http.Request) string {
+	if user, ok := r.Context().Value("user").(*database.User); ok && user != nil {
+		return user.Email
+	}
+	if key, ok := r.Context().Value("api_key").(string); ok {
+		return "API key " + maskAPIKey(key)
+	}
+	return "unknown"
+}
+
+// auditedConfigValue renders a setting's value for the audit log: secrets are masked, credentials
+// inside free text are masked, and long values are truncated
+func auditedConfigValue(value interface{}, secret bool) string {
+	if value == nil {
+		return "(unset)"
+	}
+	if v := reflect.ValueOf(value); (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0 {
+		return "(unset)"
+	}
+	text, isString := value.(string)
+	if !isString {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return "(unprintable)"
+		}
+		text = string(encoded)
+	}
+	if secret {
+		if text == "" {
+			return "(unset)"
+		}
+		return maskAPIKey(text)
+	}
+	text = embeddedSecretPattern.ReplaceAllStringFunc(text, maskAPIKey)
+	text = urlCredentialsPattern.ReplaceAllString(text, "://****@")
+	if len(text) > maxAuditedConfigValue {
+		text = text[:maxAuditedConfigValue] + "..."
+	}
+	if isString {
+		return strconv.Quote(text)
+	}
+	return text
+}
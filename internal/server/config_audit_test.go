@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+)
+
+func TestHandleSaveSystemContext_AuditsRedactedChange(t *testing.T) {
+	db := newTestDB(t)
+	orgStore, err := database.NewOrganizationStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create organization store: %v", err)
+	}
+	auditLogStore, err := database.NewAuditLogStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create audit log store: %v", err)
+	}
+	org, err := orgStore.CreateOrganization("Acme", "active")
+	if err != nil {
+		t.Fatalf("Failed to create organization: %v", err)
+	}
+	if err := orgStore.UpdateSystemContext(org.ID, "Answer briefly."); err != nil {
+		t.Fatalf("Failed to seed system context: %v", err)
+	}
+	before := ""
+	if seeded, err := orgStore.GetOrganizationByID(org.ID); err == nil && seeded != nil {
+		before = seeded.SystemContext
+	}
+
+	const secret = "sk-live0123456789abcdef"
+	body := `{"system_context": "Answer formally. Use key ` + secret + ` for lookups."}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/config/system-context", strings.NewReader(body))
+	req = withUser(req, &database.User{ID: "u1", Email: "admin@acme.test"}, org.ID)
+	rec := httptest.NewRecorder()
+	HandleSaveSystemContext(rec, req, orgStore, auditLogStore)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	logs, err := auditLogStore.GetRecentLogs(10, string(database.AuditActionConfigChange), org.ID)
+	if err != nil {
+		t.Fatalf("Failed to read audit logs: %v", err)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("Expected one configuration audit entry, got %d", len(logs))
+	}
+	details := logs[0].Details
+	for _, want := range []string{"admin@acme.test", "system_context", auditedConfigValue(before, false), "Answer formally.", maskAPIKey(secret)} {
+		if !strings.Contains(details, want) {
+			t.Errorf("Expected the audit entry to contain %q, got %q", want, details)
+		}
+	}
+	if strings.Contains(details, secret) {
+		t.Errorf("Expected the key in the system context to be redacted, got %q", details)
+	}
+}
+
+func TestAuditedConfigValue_MasksSecrets(t *testing.T) {
+	if got := auditedConfigValue("sk-abcdefghijklmnop", true); strings.Contains(got, "defghijkl") {
+		t.Errorf("Expected a secret setting to be masked, got %q", got)
+	}
+	if got := auditedConfigValue("", true); got != "(unset)" {
+		t.Errorf("Expected an empty secret to read (unset), got %q", got)
+	}
+	if got := auditedConfigValue("redis://:hunter2@cache:6379", false); strings.Contains(got, "hunter2") {
+		t.Errorf("Expected URL credentials to be masked, got %q", got)
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"strings"
 
 	"github.com/joho/godotenv"
+
+	"github.com/the-hive/internal/database"
 )
 
 // ConfigRequest represents the configuration update request
@@ -51,7 +53,7 @@ func HandleGetConfig(w http.ResponseWriter, r *http.Request) {
 }
 
 // HandleSaveConfig updates the configuration and saves to .env file
-func HandleSaveConfig(w http.ResponseWriter, r *http.Request) {
+func HandleSaveConfig(w http.ResponseWriter, r *http.Request, auditLogStore *database.AuditLogStore) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -96,6 +98,15 @@ func HandleSaveConfig(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Server-wide settings as they were, for the audit log
+	changes := []configChange{
+		{Setting: "EMBEDDER_TYPE", Before: os.Getenv("EMBEDDER_TYPE"), After: os.Getenv("EMBEDDER_TYPE")},
+		{Setting: "OPENAI_API_KEY", Before: os.Getenv("OPENAI_API_KEY"), After: os.Getenv("OPENAI_API_KEY"), Secret: true},
+		{Setting: "QDRANT_URL", Before: os.Getenv("QDRANT_URL"), After: os.Getenv("QDRANT_URL")},
+		{Setting: "REDIS_URL", Before: os.Getenv("REDIS_URL"), After: os.Getenv("REDIS_URL")},
+		{Setting: "NORTHBOUND_LICENSE_KEY", Before: os.Getenv("NORTHBOUND_LICENSE_KEY"), After: os.Getenv("NORTHBOUND_LICENSE_KEY"), Secret: true},
+	}
+
 	// Update environment variables
 	if req.AIProvider != "" {
 		os.Setenv("EMBEDDER_TYPE", req.AIProvider)
@@ -134,6 +145,10 @@ func HandleSaveConfig(w http.ResponseWriter, r *http.Request) {
 	if err := godotenv.Load(); err != nil {
 		log.Printf("Warning: Failed to reload .env file: %v", err)
 	}
+	for i := range changes {
+		changes[i].After = os.Getenv(changes[i].Setting)
+	}
+	auditConfigChanges(auditLogStore, r, "", changes...)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/jobs"
+)
+
+// DBMaintenanceHandler exposes SQLite maintenance to super admins
+type DBMaintenanceHandler struct {
+	maintainer    *jobs.SQLiteMaintainer
+	auditLogStore *database.AuditLogStore
+}
+
+// NewDBMaintenanceHandler creates a new database maintenance handler
+func NewDBMaintenanceHandler(maintainer *jobs.SQLiteMaintainer, auditLogStore *database.AuditLogStore) *DBMaintenanceHandler {
+	return &DBMaintenanceHandler{
+		maintainer:    maintainer,
+		auditLogStore: auditLogStore,
+	}
+}
+
+// HandleDBMaintenance handles /api/v1/admin/db-maintenance
+// GET returns the last report; POST runs a WAL checkpoint now, plus VACUUM with ?vacuum=true
+func (h *DBMaintenanceHandler) HandleDBMaintenance(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"last_report": h.maintainer.LastReport()})
+	case http.MethodPost:
+		dbUser, orgID, ok := requireUserAndOrg(w, r)
+		if !ok {
+			return
+		}
+
+		// A manual VACUUM is explicit, so it runs regardless of activity or fragmentation
+		vacuum := r.URL.Query().Get("vacuum") == "true"
+		report, err := h.maintainer.Run(r.Context(), vacuum, vacuum)
+		if errors.Is(err, jobs.ErrMaintenanceRunning) {
+			writeJSONError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if err != nil {
+			log.Printf("Database maintenance failed: %v", err)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(report)
+			return
+		}
+
+		if h.auditLogStore != nil {
+			details := fmt.Sprintf("Database maintenance by %s (vacuum=%v): %d -> %d bytes", dbUser.Email, report.Vacuumed, report.SizeBefore, report.SizeAfter)
+			if err := h.auditLogStore.LogAction(getClientIP(r), database.AuditActionDBMaintenance, details, orgID); err != nil {
+				log.Printf("Failed to log maintenance audit entry: %v", err)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/the-hive/internal/queue"
+)
+
+// HandleDeadLetters handles GET /api/v1/admin/dead-jobs, listing queue jobs that failed every retry
+// (newest first, with their error and attempt count) as a paginated list
+func HandleDeadLetters(w http.ResponseWriter, r *http.Request, jobQueue queue.Queue) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	deadLetterer, ok := jobQueue.(queue.DeadLetterer)
+	if !jobQueueAvailable(jobQueue) || !ok {
+		writeJSONError(w, http.StatusServiceUnavailable, "job queue unavailable (Redis required)")
+		return
+	}
+
+	// The list is capped, so it is read whole and paged in memory
+	deadJobs, err := deadLetterer.DeadLetters(r.Context(), 0)
+	if err != nil {
+		log.Printf("[ERROR] Failed to list dead-lettered jobs: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to list dead-lettered jobs")
+		return
+	}
+	limit, offset := parsePagination(r)
+	writePage(w, pageSlice(deadJobs, limit, offset), len(deadJobs), limit, offset)
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/rules"
+)
+
+// HandleDefaultRules handles GET and PUT /api/v1/admin/default-rules (super admin): the template of
+// rules seeded into every new organization. PUT replaces the whole template with {"rules": [...]};
+// organizations that already exist keep their rules.
+func HandleDefaultRules(w http.ResponseWriter, r *http.Request, ruleStore *rules.Store, auditLogStore *database.AuditLogStore) {
+	switch r.Method {
+	case http.MethodGet:
+		defaults, err := ruleStore.GetDefaultRules(r.Context())
+		if err != nil {
+			log.Printf("Failed to load default rules: %v", err)
+			writeJSONError(w, http.StatusInternalServerError, "failed to load default rules")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"rules": defaults})
+
+	case http.MethodPut:
+		var req struct {
+			Rules []rules.DefaultRule `json:"rules"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		previous, _ := ruleStore.GetDefaultRules(r.Context())
+		defaults, err := ruleStore.SetDefaultRules(r.Context(), req.Rules)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		auditConfigChanges(auditLogStore, r, "", configChange{Setting: "default_rules", Before: defaultRuleQueries(previous), After: defaultRuleQueries(defaults)})
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"rules": defaults})
+
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// defaultRuleQueries summarizes a template for the audit log as kind:query entries
+func defaultRuleQueries(defaults []rules.DefaultRule) []string {
+	queries := make([]string, 0, len(defaults))
+	for _, rule := range defaults {
+		queries = append(queries, rule.Kind+":"+rule.Query)
+	}
+	return queries
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/rules"
+)
+
+func TestHandleCreateOrganization_SeedsDefaultRules(t *testing.T) {
+	db := newTestDB(t)
+	orgStore, err := database.NewOrganizationStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create organization store: %v", err)
+	}
+	ruleStore, err := rules.NewStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create rule store: %v", err)
+	}
+	superAdmin := &database.User{ID: "root", Email: "root@hive.test"}
+	createOrg := func(name string) string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		req := withUser(httptest.NewRequest(http.MethodPost, "/api/v1/admin/organizations", strings.NewReader(`{"name": "`+name+`"}`)), superAdmin, "")
+		HandleCreateOrganization(rec, req, orgStore, nil, ruleStore)
+		var org database.Organization
+		if err := json.NewDecoder(rec.Body).Decode(&org); err != nil || org.ID == "" {
+			t.Fatalf("Failed to create organization %s: %d %v", name, rec.Code, err)
+		}
+		return org.ID
+	}
+
+	orgID := createOrg("Acme")
+	seeded, err := ruleStore.GetAllRules(orgID)
+	if err != nil {
+		t.Fatalf("GetAllRules failed: %v", err)
+	}
+	if len(seeded) != len(rules.BuiltInDefaultRules) {
+		t.Fatalf("Expected the new organization to get the %d default rules, got %+v", len(rules.BuiltInDefaultRules), seeded)
+	}
+	hasConfidential := false
+	for _, rule := range seeded {
+		if rule.Kind == rules.KindKeyword && rule.Query == "CONFIDENTIAL" && rule.Active {
+			hasConfidential = true
+		}
+	}
+	if !hasConfidential {
+		t.Errorf("Expected the CONFIDENTIAL keyword rule among the defaults, got %+v", seeded)
+	}
+
+	// A super admin replaces the template; the next organization gets the new one
+	rec := httptest.NewRecorder()
+	req := withUser(httptest.NewRequest(http.MethodPut, "/api/v1/admin/default-rules", strings.NewReader(`{"rules": [{"query": "EMBARGOED", "kind": "keyword", "active": true}]}`)), superAdmin, "")
+	HandleDefaultRules(rec, req, ruleStore, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	next, _ := ruleStore.GetAllRules(createOrg("Globex"))
+	if len(next) != 1 || next[0].Query != "EMBARGOED" {
+		t.Errorf("Expected the edited template, got %+v", next)
+	}
+
+	rec = httptest.NewRecorder()
+	HandleDefaultRules(rec, withUser(httptest.NewRequest(http.MethodPut, "/api/v1/admin/default-rules", strings.NewReader(`{"rules": [{"query": ""}]}`)), superAdmin, ""), ruleStore, nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a rule without a query, got %d", rec.Code)
+	}
+}
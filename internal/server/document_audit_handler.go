@@ -0,0 +1,100 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/the-hive/internal/database"
+)
+
+// DocumentTrailSource is a store whose records about a document (by ID or filename) belong in its audit trail
+// (the audit log, rule events and rule matches)
+type DocumentTrailSource interface {
+	DocumentTrail(organizationID string, refs []string) ([]database.DocumentTrailEntry, error)
+}
+
+// DocumentAuditTrail is everything recorded about one document of the organization, oldest first
+type DocumentAuditTrail struct {
+	DocumentID string                        `json:"document_id"`
+	Filename   string                        `json:"filename,omitempty"`
+	Entries    []database.DocumentTrailEntry `json:"entries"`
+	Incomplete bool                          `json:"incomplete,omitempty"` // A source couldn't be read
+}
+
+// HandleDocumentAudit handles GET /api/v1/documents/{id}/audit, returning the document's audit trail as a download.
+// The filename comes from the document listing; ?filename= names it for documents that were since deleted.
+func HandleDocumentAudit(w http.ResponseWriter, r *http.Request, documents *database.DocumentSummaryStore, sources ...DocumentTrailSource) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	_, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+	if orgID == "" {
+		writeJSONError(w, http.StatusBadRequest, "organization is required")
+		return
+	}
+	documentID := r.PathValue("id")
+	if documentID == "" {
+		writeJSONError(w, http.StatusBadRequest, "document id is required")
+		return
+	}
+
+	trail := DocumentAuditTrail{DocumentID: documentID, Filename: r.URL.Query().Get("filename")}
+	doc, err := documents.GetDocument(orgID, documentID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if doc != nil {
+		trail.Filename = doc.Filename
+	}
+
+	refs := []string{documentID}
+	if trail.Filename != "" && trail.Filename != documentID {
+		refs = append(refs, trail.Filename)
+	}
+	for _, source := range sources {
+		entries, err := source.DocumentTrail(orgID, refs)
+		if err != nil {
+			log.Printf("[AUDIT] Failed to read part of the audit trail for document %s: %v", documentID, err)
+			trail.Incomplete = true
+			continue
+		}
+		trail.Entries = append(trail.Entries, entries...)
+	}
+	if doc == nil && len(trail.Entries) == 0 {
+		writeJSONError(w, http.StatusNotFound, "document not found")
+		return
+	}
+	sort.SliceStable(trail.Entries, func(i, j int) bool {
+		return trail.Entries[i].Time.Before(trail.Entries[j].Time)
+	})
+	if trail.Entries == nil {
+		trail.Entries = []database.DocumentTrailEntry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", auditTrailFilename(documentID)))
+	json.NewEncoder(w).Encode(trail)
+}
+
+// auditTrailFilename names the downloaded trail after the document, keeping only filename-safe characters
+func auditTrailFilename(documentID string) string {
+	safe := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, documentID)
+	return safe + "-audit.json"
+}
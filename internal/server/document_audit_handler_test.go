@@ -0,0 +1,95 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/the-hive/internal/database"
+)
+
+// staticTrail is a trail source with fixed entries, recording the references it was asked about
+type staticTrail struct {
+	entries []database.DocumentTrailEntry
+	refs    []string
+}
+
+func (s *staticTrail) DocumentTrail(organizationID string, refs []string) ([]database.DocumentTrailEntry, error) {
+	s.refs = refs
+	return s.entries, nil
+}
+
+func TestHandleDocumentAudit_TrailSpansIngestAndMatch(t *testing.T) {
+	db := newTestDB(t)
+	documents, err := database.NewDocumentSummaryStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create document summary store: %v", err)
+	}
+	auditLogs, err := database.NewAuditLogStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create audit log store: %v", err)
+	}
+	if err := documents.SaveDocument(&database.DocumentSummary{OrganizationID: "org-a", DocumentID: "doc-1", Filename: "contract.pdf", ChunkCount: 2, IngestedAt: time.Now()}); err != nil {
+		t.Fatalf("Failed to save document: %v", err)
+	}
+
+	for _, l := range []struct{ details, orgID string }{
+		{"Client [10.0.0.1] uploaded file [contract.pdf] (2 chunks)", "org-a"},
+		{"Client [10.0.0.1] uploaded file [old-contract.pdf] (1 chunks)", "org-a"},
+		{"Client [10.0.0.9] uploaded file [contract.pdf] (4 chunks)", "org-b"},
+	} {
+		if err := auditLogs.LogAction("10.0.0.1", database.AuditActionIngest, l.details, l.orgID); err != nil {
+			t.Fatalf("Failed to log ingest: %v", err)
+		}
+	}
+	matches := &staticTrail{entries: []database.DocumentTrailEntry{{
+		Time:    time.Now().Add(time.Hour),
+		Source:  database.TrailSourceRuleMatch,
+		Type:    "keyword",
+		Details: "Rule 7 (confidential) matched [contract.pdf]",
+	}}}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/documents/doc-1/audit", nil)
+	req.SetPathValue("id", "doc-1")
+	rec := httptest.NewRecorder()
+	HandleDocumentAudit(rec, withUser(req, &database.User{ID: "alice"}, "org-a"), documents, matches, auditLogs)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="doc-1-audit.json"` {
+		t.Errorf("Expected the trail as a download, got Content-Disposition %q", got)
+	}
+	if len(matches.refs) != 2 || matches.refs[0] != "doc-1" || matches.refs[1] != "contract.pdf" {
+		t.Errorf("Expected the document to be looked up by ID and filename, got %v", matches.refs)
+	}
+
+	var trail DocumentAuditTrail
+	if err := json.Unmarshal(rec.Body.Bytes(), &trail); err != nil {
+		t.Fatalf("Failed to decode trail: %v", err)
+	}
+	if trail.Filename != "contract.pdf" || trail.Incomplete {
+		t.Errorf("Expected a complete trail for contract.pdf, got %+v", trail)
+	}
+	if len(trail.Entries) != 2 {
+		t.Fatalf("Expected the ingest and the match (not other files or organizations), got %+v", trail.Entries)
+	}
+	if trail.Entries[0].Source != database.TrailSourceAuditLog || trail.Entries[0].Type != string(database.AuditActionIngest) {
+		t.Errorf("Expected the ingest first, got %+v", trail.Entries[0])
+	}
+	if trail.Entries[1].Source != database.TrailSourceRuleMatch {
+		t.Errorf("Expected the match after the ingest, got %+v", trail.Entries[1])
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/documents/doc-1/audit", nil)
+	req.SetPathValue("id", "doc-1")
+	rec = httptest.NewRecorder()
+	HandleDocumentAudit(rec, withUser(req, &database.User{ID: "bob"}, "org-b"), documents, auditLogs)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected another organization's document to be not found, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
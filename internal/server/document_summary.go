@@ -0,0 +1,124 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/the-hive/internal/database"
+)
+
+const (
+	// DefaultSummaryMinChars is the document length below which no summary is generated
+	DefaultSummaryMinChars = 1500
+	// maxSummaryInputChars caps how much of a document is sent to the model
+	maxSummaryInputChars = 12000
+	summaryTimeout       = 60 * time.Second
+
+	summarySystemPrompt = "You summarize documents for people browsing a document library. " +
+		"Write a single plain-text paragraph of at most five sentences describing what the document is and its key points. " +
+		"Use only information in the document."
+)
+
+// DocumentSummarizer records ingested documents and, when a generator is set, stores a one-paragraph summary of each
+type DocumentSummarizer struct {
+	store    *database.DocumentSummaryStore
+	generate AnswerGenerator // Nil disables summaries; documents are still recorded
+	minChars int
+}
+
+// NewDocumentSummarizer creates a summarizer backed by the store
+func NewDocumentSummarizer(store *database.DocumentSummaryStore) *DocumentSummarizer {
+	return &DocumentSummarizer{
+		store:    store,
+		minChars: DefaultSummaryMinChars,
+	}
+}
+
+// SetGenerator sets the model used to write summaries
+func (s *DocumentSummarizer) SetGenerator(generate AnswerGenerator) {
+	s.generate = generate
+}
+
+// SetMinChars sets the document length below which no summary is generated
+func (s *DocumentSummarizer) SetMinChars(minChars int) {
+	if minChars >= 0 {
+		s.minChars = minChars
+	}
+}
+
+// Store returns the store documents are recorded in
+func (s *DocumentSummarizer) Store() *database.DocumentSummaryStore {
+	return s.store
+}
+
+// Summarize records the assembled document and generates its summary if enabled and the document is long enough.
+// A failed generation still records the document, without a summary.
+func (s *DocumentSummarizer) Summarize(ctx context.Context, orgID, documentID, filename string, chunks []string) (*database.DocumentSummary, error) {
+	content := strings.Join(chunks, "\n\n")
+	doc := &database.DocumentSummary{
+		OrganizationID: orgID,
+		DocumentID:     documentID,
+		Filename:       filename,
+		ChunkCount:     len(chunks),
+	}
+
+	if s.generate != nil && len(strings.TrimSpace(content)) >= s.minChars {
+		if len(content) > maxSummaryInputChars {
+			content = content[:maxSummaryInputChars]
+		}
+		summary, err := s.generate(ctx, summarySystemPrompt, "Document: "+filename+"\n\n"+content)
+		if err != nil {
+			log.Printf("[SUMMARY] Failed to summarize %s: %v", documentID, err)
+		} else {
+			doc.Summary = strings.TrimSpace(summary)
+		}
+	}
+
+	if err := s.store.SaveDocument(doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// SummarizeAsync runs Summarize in the background so ingest isn't held up by the model
+func (s *DocumentSummarizer) SummarizeAsync(orgID, documentID, filename string, chunks []string) {
+	chunks = append([]string(nil), chunks...)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), summaryTimeout)
+		defer cancel()
+		if _, err := s.Summarize(ctx, orgID, documentID, filename, chunks); err != nil {
+			log.Printf("[SUMMARY] Failed to record document %s: %v", documentID, err)
+		}
+	}()
+}
+
+// HandleListDocuments handles GET /api/v1/documents, listing the organization's documents with their summaries
+func HandleListDocuments(w http.ResponseWriter, r *http.Request, store *database.DocumentSummaryStore) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	_, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	docs, err := store.ListDocuments(orgID, limit, offset)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	total, err := store.CountDocuments(orgID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writePage(w, docs, total, limit, offset)
+}
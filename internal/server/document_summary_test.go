@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+)
+
+func newTestSummarizer(t *testing.T) (*DocumentSummarizer, *[]string) {
+	t.Helper()
+	store, err := database.NewDocumentSummaryStore(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Failed to create document summary store: %v", err)
+	}
+	prompts := &[]string{}
+	summarizer := NewDocumentSummarizer(store)
+	summarizer.SetGenerator(func(ctx context.Context, systemPrompt, prompt string) (string, error) {
+		*prompts = append(*prompts, prompt)
+		return "  A supplier agreement covering pricing and delivery terms.\n", nil
+	})
+	return summarizer, prompts
+}
+
+func TestDocumentSummarizer_MultiChunkDocument(t *testing.T) {
+	summarizer, prompts := newTestSummarizer(t)
+	chunks := []string{
+		strings.Repeat("The supplier delivers goods within ten days. ", 20),
+		strings.Repeat("Prices are fixed for the first year. ", 20),
+		strings.Repeat("Either party may terminate with notice. ", 20),
+	}
+
+	doc, err := summarizer.Summarize(context.Background(), "org-a", "contract.pdf", "contract.pdf", chunks)
+	if err != nil {
+		t.Fatalf("Summarize failed: %v", err)
+	}
+	if doc.Summary != "A supplier agreement covering pricing and delivery terms." {
+		t.Errorf("Unexpected summary: %q", doc.Summary)
+	}
+	if len(*prompts) != 1 || !strings.Contains((*prompts)[0], "Prices are fixed") || !strings.Contains((*prompts)[0], "terminate with notice") {
+		t.Errorf("Expected one prompt containing every chunk, got %d", len(*prompts))
+	}
+
+	stored, err := summarizer.Store().GetDocument("org-a", "contract.pdf")
+	if err != nil || stored == nil {
+		t.Fatalf("Expected stored document, got %v, %v", stored, err)
+	}
+	if stored.Summary != doc.Summary || stored.ChunkCount != 3 {
+		t.Errorf("Unexpected stored document: %+v", stored)
+	}
+
+	// Other organizations don't see the summary
+	summaries, err := summarizer.Store().GetSummaries("org-b", []string{"contract.pdf"})
+	if err != nil || len(summaries) != 0 {
+		t.Errorf("Expected no summaries for another organization, got %v, %v", summaries, err)
+	}
+
+	rec := httptest.NewRecorder()
+	HandleListDocuments(rec, withUser(httptest.NewRequest(http.MethodGet, "/api/v1/documents", nil), &database.User{ID: "alice"}, "org-a"), summarizer.Store())
+	var listing struct {
+		Items []database.DocumentSummary `json:"items"`
+	}
+	json.NewDecoder(rec.Body).Decode(&listing)
+	if len(listing.Items) != 1 || listing.Items[0].Summary != doc.Summary {
+		t.Errorf("Expected the summary in the document listing, got %+v", listing.Items)
+	}
+}
+
+func TestDocumentSummarizer_SkipsShortDocuments(t *testing.T) {
+	summarizer, prompts := newTestSummarizer(t)
+
+	doc, err := summarizer.Summarize(context.Background(), "org-a", "note.txt", "note.txt", []string{"Call Bob back."})
+	if err != nil {
+		t.Fatalf("Summarize failed: %v", err)
+	}
+	if doc.Summary != "" || len(*prompts) != 0 {
+		t.Errorf("Expected no summary for a short document, got %q after %d calls", doc.Summary, len(*prompts))
+	}
+	if stored, _ := summarizer.Store().GetDocument("org-a", "note.txt"); stored == nil {
+		t.Error("Expected the short document to still be listed")
+	}
+}
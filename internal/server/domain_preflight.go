@@ -0,0 +1,295 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/httpclient"
+)
+
+// Preflight check statuses
+const (
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// DomainResolver resolves a hostname to its addresses (satisfied by *net.Resolver)
+type DomainResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// DomainLookup finds an existing custom domain mapping
+type DomainLookup interface {
+	GetDomainByHost(host string) (*database.CustomDomain, error)
+}
+
+// DomainCheck is the outcome of a single preflight check
+type DomainCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// DomainPreflightResult is the full set of diagnostics for a domain
+type DomainPreflightResult struct {
+	Domain      string        `json:"domain"`
+	Ready       bool          `json:"ready"` // True when no check failed
+	ResolvedIPs []string      `json:"resolved_ips"`
+	Checks      []DomainCheck `json:"checks"`
+}
+
+// DomainPreflight validates a custom domain before an admin maps it and waits on certificate issuance
+type DomainPreflight struct {
+	resolver  DomainResolver
+	domains   DomainLookup
+	serverIPs []string
+	probe     func(ctx context.Context, domain, addr string) error // Reachability check against a vetted address (replaceable in tests)
+}
+
+// NewDomainPreflight creates a domain preflight checker. serverIPs are the addresses the domain must resolve to.
+func NewDomainPreflight(resolver DomainResolver, domains DomainLookup, serverIPs []string) *DomainPreflight {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &DomainPreflight{
+		resolver:  resolver,
+		domains:   domains,
+		serverIPs: serverIPs,
+		probe:     probeDomainHTTP,
+	}
+}
+
+// ServerIPsFromEnv reads the server's public addresses from SERVER_PUBLIC_IPS (comma-separated)
+func ServerIPsFromEnv() []string {
+	var ips []string
+	for _, ip := range strings.Split(os.Getenv("SERVER_PUBLIC_IPS"), ",") {
+		if ip = strings.TrimSpace(ip); ip != "" {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+// normalizeDomain strips scheme, path, port and trailing dot from user input
+func normalizeDomain(input string) (string, error) {
+	domain := strings.ToLower(strings.TrimSpace(input))
+	if strings.Contains(domain, "://") {
+		parsed, err := url.Parse(domain)
+		if err != nil {
+			return "", fmt.Errorf("invalid domain: %s", input)
+		}
+		domain = parsed.Host
+	}
+	if i := strings.IndexAny(domain, "/?#"); i >= 0 {
+		domain = domain[:i]
+	}
+	if host, _, err := net.SplitHostPort(domain); err == nil {
+		domain = host
+	}
+	domain = strings.TrimSuffix(domain, ".")
+
+	if domain == "" || !strings.Contains(domain, ".") || len(domain) > 253 {
+		return "", fmt.Errorf("invalid domain: %s", input)
+	}
+	if net.ParseIP(domain) != nil {
+		return "", fmt.Errorf("expected a domain name, got an IP address: %s", input)
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return "", fmt.Errorf("invalid domain: %s", input)
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+				return "", fmt.Errorf("invalid domain: %s", input)
+			}
+		}
+	}
+	return domain, nil
+}
+
+// Check runs DNS, mapping and reachability checks for the domain on behalf of organizationID
+func (p *DomainPreflight) Check(ctx context.Context, domain, organizationID string) DomainPreflightResult {
+	result := DomainPreflightResult{Domain: domain, ResolvedIPs: []string{}}
+
+	dnsCheck, resolved := p.checkDNS(ctx, domain)
+	result.ResolvedIPs = resolved
+	result.Checks = append(result.Checks, dnsCheck, p.checkMapping(domain, organizationID))
+
+	// Reachability is only probed once the domain is known to point at this server, so the check
+	// can't be used to make requests to arbitrary (or internal) hosts
+	if dnsCheck.Status == CheckPass && p.probe != nil {
+		reachability := DomainCheck{Name: "reachability", Status: CheckPass, Message: "Server responded over HTTP"}
+		if addr := p.probeAddress(resolved); addr == "" {
+			reachability.Status = CheckWarn
+			reachability.Message = fmt.Sprintf("Skipped the HTTP check: %s resolves to a private, loopback or link-local address.", domain)
+		} else if err := p.probe(ctx, domain, addr); err != nil {
+			reachability.Status = CheckWarn
+			reachability.Message = fmt.Sprintf("Could not reach the server at http://%s: %v. Certificate issuance needs ports 80 and 443 open.", domain, err)
+		}
+		result.Checks = append(result.Checks, reachability)
+	}
+
+	result.Ready = true
+	for _, check := range result.Checks {
+		if check.Status == CheckFail {
+			result.Ready = false
+		}
+	}
+	return result
+}
+
+// checkDNS verifies the domain resolves, and to one of the server's addresses when they are known
+func (p *DomainPreflight) checkDNS(ctx context.Context, domain string) (DomainCheck, []string) {
+	lookupCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	addrs, err := p.resolver.LookupHost(lookupCtx, domain)
+	if err != nil || len(addrs) == 0 {
+		return DomainCheck{
+			Name:    "dns",
+			Status:  CheckFail,
+			Message: fmt.Sprintf("%s does not resolve. Create an A record pointing to %s.", domain, p.serverIPHint()),
+		}, []string{}
+	}
+
+	if len(p.serverIPs) == 0 {
+		return DomainCheck{
+			Name:    "dns",
+			Status:  CheckWarn,
+			Message: fmt.Sprintf("%s resolves to %s, but SERVER_PUBLIC_IPS is not set so the target could not be verified.", domain, strings.Join(addrs, ", ")),
+		}, addrs
+	}
+
+	for _, addr := range addrs {
+		for _, ip := range p.serverIPs {
+			if addr == ip {
+				return DomainCheck{
+					Name:    "dns",
+					Status:  CheckPass,
+					Message: fmt.Sprintf("%s resolves to this server (%s)", domain, addr),
+				}, addrs
+			}
+		}
+	}
+	return DomainCheck{
+		Name:    "dns",
+		Status:  CheckFail,
+		Message: fmt.Sprintf("%s resolves to %s, not this server. Update the A record to %s.", domain, strings.Join(addrs, ", "), p.serverIPHint()),
+	}, addrs
+}
+
+// checkMapping verifies the domain isn't already claimed by another organization
+func (p *DomainPreflight) checkMapping(domain, organizationID string) DomainCheck {
+	if p.domains == nil {
+		return DomainCheck{Name: "mapping", Status: CheckWarn, Message: "Domain mappings are not available"}
+	}
+
+	existing, err := p.domains.GetDomainByHost(domain)
+	if err != nil {
+		return DomainCheck{Name: "mapping", Status: CheckWarn, Message: fmt.Sprintf("Failed to check existing mappings: %v", err)}
+	}
+	if existing == nil {
+		return DomainCheck{Name: "mapping", Status: CheckPass, Message: "Domain is not mapped to any organization"}
+	}
+	if existing.OrganizationID != organizationID {
+		return DomainCheck{Name: "mapping", Status: CheckFail, Message: "Domain is already mapped to another organization"}
+	}
+	if !existing.Verified {
+		return DomainCheck{Name: "mapping", Status: CheckWarn, Message: "Domain is mapped to your organization but not yet verified"}
+	}
+	return DomainCheck{Name: "mapping", Status: CheckPass, Message: "Domain is mapped to your organization and verified"}
+}
+
+// probeAddress returns the resolved address that is one of the server's and publicly routable, or ""
+func (p *DomainPreflight) probeAddress(resolved []string) string {
+	for _, addr := range resolved {
+		ip := net.ParseIP(addr)
+		if ip == nil || !isPublicIP(ip) {
+			continue
+		}
+		for _, serverIP := range p.serverIPs {
+			if addr == serverIP {
+				return addr
+			}
+		}
+	}
+	return ""
+}
+
+// isPublicIP reports whether ip is not a private, loopback, link-local or unspecified address
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsUnspecified()
+}
+
+func (p *DomainPreflight) serverIPHint() string {
+	if len(p.serverIPs) == 0 {
+		return "this server's public IP"
+	}
+	return strings.Join(p.serverIPs, " or ")
+}
+
+// probeDomainHTTP checks the server answers its health endpoint on the domain. It connects to addr,
+// the address already vetted by the caller, rather than resolving the domain again.
+func probeDomainHTTP(ctx context.Context, domain, addr string) error {
+	probeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(probeCtx, http.MethodGet, "http://"+domain+"/api/v1/health", nil)
+	if err != nil {
+		return err
+	}
+	client := httpclient.New(5 * time.Second)
+	transport := httpclient.Transport().Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{Timeout: 5 * time.Second}).DialContext(ctx, network, net.JoinHostPort(addr, "80"))
+	}
+	client.Transport = transport
+	// Caddy redirects HTTP to HTTPS; a redirect still proves the server is reachable
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error { return http.ErrUseLastResponse }
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// HandleDomainPreflight dry-runs a custom domain mapping for the caller's organization
+// GET /api/v1/admin/domains/preflight?domain=example.com
+func HandleDomainPreflight(w http.ResponseWriter, r *http.Request, preflight *DomainPreflight) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	_, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	domain, err := normalizeDomain(r.URL.Query().Get("domain"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result := preflight.Check(r.Context(), domain, orgID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+)
+
+// fakeResolver resolves hosts from a fixed table
+type fakeResolver map[string][]string
+
+func (f fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if addrs, ok := f[host]; ok {
+		return addrs, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+// fakeDomainLookup returns mappings from a fixed table
+type fakeDomainLookup map[string]*database.CustomDomain
+
+func (f fakeDomainLookup) GetDomainByHost(host string) (*database.CustomDomain, error) {
+	return f[host], nil
+}
+
+func checkStatus(result DomainPreflightResult, name string) string {
+	for _, check := range result.Checks {
+		if check.Name == name {
+			return check.Status
+		}
+	}
+	return ""
+}
+
+func newTestPreflight() *DomainPreflight {
+	resolver := fakeResolver{
+		"docs.acme.com":  {"203.0.113.10"},
+		"wrong.acme.com": {"198.51.100.7"},
+		"taken.acme.com": {"203.0.113.10"},
+		"inner.acme.com": {"10.0.0.5"},
+	}
+	domains := fakeDomainLookup{
+		"taken.acme.com": {Host: "taken.acme.com", OrganizationID: "org-b", Verified: true},
+	}
+	preflight := NewDomainPreflight(resolver, domains, []string{"203.0.113.10", "10.0.0.5"})
+	preflight.probe = func(ctx context.Context, domain, addr string) error { return nil }
+	return preflight
+}
+
+func TestDomainPreflight_Check(t *testing.T) {
+	preflight := newTestPreflight()
+
+	tests := []struct {
+		name      string
+		domain    string
+		ready     bool
+		dns       string
+		mapping   string
+		reachable string
+	}{
+		{"resolves to server", "docs.acme.com", true, CheckPass, CheckPass, CheckPass},
+		{"does not resolve", "missing.acme.com", false, CheckFail, CheckPass, ""},
+		{"resolves elsewhere", "wrong.acme.com", false, CheckFail, CheckPass, ""},
+		{"mapped to another org", "taken.acme.com", false, CheckPass, CheckFail, CheckPass},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := preflight.Check(context.Background(), tt.domain, "org-a")
+			if result.Ready != tt.ready {
+				t.Errorf("Ready = %v, want %v (%+v)", result.Ready, tt.ready, result.Checks)
+			}
+			if got := checkStatus(result, "dns"); got != tt.dns {
+				t.Errorf("dns = %q, want %q", got, tt.dns)
+			}
+			if got := checkStatus(result, "mapping"); got != tt.mapping {
+				t.Errorf("mapping = %q, want %q", got, tt.mapping)
+			}
+			if got := checkStatus(result, "reachability"); got != tt.reachable {
+				t.Errorf("reachability = %q, want %q", got, tt.reachable)
+			}
+		})
+	}
+}
+
+func TestDomainPreflight_UnreachableIsWarning(t *testing.T) {
+	preflight := newTestPreflight()
+	preflight.probe = func(ctx context.Context, domain, addr string) error { return errors.New("connection refused") }
+
+	result := preflight.Check(context.Background(), "docs.acme.com", "org-a")
+	if !result.Ready || checkStatus(result, "reachability") != CheckWarn {
+		t.Errorf("Expected an unreachable server to warn without failing, got %+v", result)
+	}
+}
+
+func TestDomainPreflight_DoesNotProbeUnverifiedOrPrivateAddresses(t *testing.T) {
+	preflight := newTestPreflight()
+	var probed []string
+	preflight.probe = func(ctx context.Context, domain, addr string) error {
+		probed = append(probed, domain+"@"+addr)
+		return nil
+	}
+
+	for _, domain := range []string{"wrong.acme.com", "missing.acme.com", "inner.acme.com"} {
+		preflight.Check(context.Background(), domain, "org-a")
+	}
+	if len(probed) != 0 {
+		t.Errorf("Expected no HTTP probes, got %v", probed)
+	}
+	if result := preflight.Check(context.Background(), "inner.acme.com", "org-a"); checkStatus(result, "reachability") != CheckWarn {
+		t.Errorf("Expected the skipped probe to warn, got %+v", result.Checks)
+	}
+
+	preflight.Check(context.Background(), "docs.acme.com", "org-a")
+	if len(probed) != 1 || probed[0] != "docs.acme.com@203.0.113.10" {
+		t.Errorf("Expected one probe against the server's public address, got %v", probed)
+	}
+}
+
+func TestHandleDomainPreflight(t *testing.T) {
+	preflight := newTestPreflight()
+	user := &database.User{Email: "admin@acme.com"}
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/v1/admin/domains/preflight?domain=HTTPS://Docs.Acme.com:443/", nil), user, "org-a")
+	rec := httptest.NewRecorder()
+	HandleDomainPreflight(rec, req, preflight)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var result DomainPreflightResult
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if result.Domain != "docs.acme.com" || !result.Ready {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	req = withUser(httptest.NewRequest(http.MethodGet, "/api/v1/admin/domains/preflight?domain=not_a_domain", nil), user, "org-a")
+	rec = httptest.NewRecorder()
+	HandleDomainPreflight(rec, req, preflight)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid domain, got %d", rec.Code)
+	}
+}
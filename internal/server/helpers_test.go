@@ -0,0 +1,51 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/vectordb"
+)
+
+// fakeVectorDB returns canned matches and records the organization each search was scoped to
+type fakeVectorDB struct {
+	vectordb.MockVectorDB
+	matches     []vectordb.Match
+	searchOrgID string
+	searchTopK  int
+}
+
+func (f *fakeVectorDB) Search(ctx context.Context, queryVector []float32, topK int, organizationID string) ([]vectordb.Match, error) {
+	f.searchOrgID = organizationID
+	f.searchTopK = topK
+	if topK < len(f.matches) {
+		return f.matches[:topK], nil
+	}
+	return f.matches, nil
+}
+
+// newTestDB opens a throwaway SQLite database for the duration of the test
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// withUser attaches a user and organization to the request context as RequireLogin/RequireTenant would
+func withUser(r *http.Request, user *database.User, orgID string) *http.Request {
+	ctx := context.WithValue(r.Context(), "user", user)
+	ctx = context.WithValue(ctx, "organization_id", orgID)
+	return r.WithContext(ctx)
+}
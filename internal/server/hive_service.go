@@ -5,18 +5,31 @@ package server
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/the-hive/internal/database"
 	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/langdetect"
 	"github.com/the-hive/internal/proto"
 	"github.com/the-hive/internal/vectordb"
 	"github.com/the-hive/internal/worker"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
+// OrganizationMetadataKey is the gRPC metadata key a caller sets to scope Query to its organization
+const OrganizationMetadataKey = "organization_id"
+
+// ReplacesDocumentMetadataKey is the chunk metadata a drone sets when a moved or renamed file
+// replaces an already-ingested document; its value is the old document_id.
+const ReplacesDocumentMetadataKey = "replaces_document_id"
+
 // HiveService implements the gRPC Hive service.
 type HiveService struct {
 	proto.UnimplementedHiveServer
@@ -25,6 +38,13 @@ type HiveService struct {
 	embedder    embeddings.Embedder
 	wsManager   *WebSocketManager
 	analystPool AnalystPoolInterface // Interface to avoid circular dependency
+	piiGuard    *PIIGuard
+	sampler     *AnalysisSampler
+	summarizer  *DocumentSummarizer
+	maxTopK     int // Upper bound on results one gRPC search may request
+	answerCache *ChatAnswerCache // Invalidated for the organization after each ingested chunk
+	auditLogs   *database.AuditLogStore // Records document deletions
+	ingestLimiter *IngestLimiter // Bounds each organization's concurrent ingests, shared with HTTP ingest
 	// Track documents being ingested to trigger analysis when complete
 	docTrackers map[string]*documentTracker
 	docMu       sync.Mutex
@@ -34,6 +54,8 @@ type HiveService struct {
 type documentTracker struct {
 	documentID   string
 	chunks       []string
+	pointIDs     []string // Points stored for the chunks, to tag with the document's language once complete
+	detectLanguage bool   // The client sent no language, so it is detected over the whole document
 	metadata     map[string]string
 	lastChunk    time.Time
 	totalChunks  int
@@ -52,10 +74,23 @@ func NewHiveService(db *sql.DB, vectorDB vectordb.VectorDB, embedder embeddings.
 		db:          db,
 		vectorDB:    vectorDB,
 		embedder:    embedder,
+		maxTopK:     DefaultMaxSearchTopK,
 		docTrackers: make(map[string]*documentTracker),
 	}
 }
 
+// SetMaxTopK sets the most results one gRPC search may request
+func (s *HiveService) SetMaxTopK(maxTopK int) {
+	if maxTopK > 0 {
+		s.maxTopK = maxTopK
+	}
+}
+
+// SetAnswerCache sets the chat answer cache invalidated when an organization's documents change
+func (s *HiveService) SetAnswerCache(cache *ChatAnswerCache) {
+	s.answerCache = cache
+}
+
 // SetWebSocketManager sets the WebSocket manager for notifications
 func (s *HiveService) SetWebSocketManager(wsManager *WebSocketManager) {
 	s.wsManager = wsManager
@@ -66,6 +101,56 @@ func (s *HiveService) SetAnalystPool(analystPool AnalystPoolInterface) {
 	s.analystPool = analystPool
 }
 
+// SetPIIGuard sets the PII guard used to alert on or mask PII before storage
+func (s *HiveService) SetPIIGuard(piiGuard *PIIGuard) {
+	s.piiGuard = piiGuard
+}
+
+// SetAnalysisSampler sets the sampler that decides which documents get AI rule analysis
+func (s *HiveService) SetAnalysisSampler(sampler *AnalysisSampler) {
+	s.sampler = sampler
+}
+
+// SetDocumentSummarizer sets the summarizer that records ingested documents and their summaries
+func (s *HiveService) SetDocumentSummarizer(summarizer *DocumentSummarizer) {
+	s.summarizer = summarizer
+}
+
+// SetAuditLogStore sets the store that records document deletions
+func (s *HiveService) SetAuditLogStore(auditLogStore *database.AuditLogStore) {
+	s.auditLogs = auditLogStore
+}
+
+// tagDocumentLanguage tags every chunk of a completed document with the language detected over the
+// whole document, as HTTP ingest does, unless the client supplied the language with its chunks
+func (s *HiveService) tagDocumentLanguage(ctx context.Context, tracker *documentTracker) {
+	setter, ok := s.vectorDB.(vectordb.PayloadFieldSetter)
+	if !ok {
+		return
+	}
+	tracker.mu.Lock()
+	detect := tracker.detectLanguage
+	chunks := append([]string(nil), tracker.chunks...)
+	pointIDs := append([]string(nil), tracker.pointIDs...)
+	tracker.mu.Unlock()
+	if !detect {
+		return
+	}
+
+	language := langdetect.DetectDocument(chunks)
+	for _, id := range pointIDs {
+		if err := setter.SetPayloadFields(ctx, id, map[string]string{"language": language}); err != nil {
+			log.Printf("Failed to tag chunk %s of document %s with its language: %v", id, tracker.documentID, err)
+		}
+	}
+}
+
+// SetIngestLimiter bounds each organization's concurrent Ingest calls (nil for no limit). Share the
+// HTTP ingest route's limiter so an organization's limit covers drones, webhooks and the API together.
+func (s *HiveService) SetIngestLimiter(limiter *IngestLimiter) {
+	s.ingestLimiter = limiter
+}
+
 // Ingest persists chunk metadata and forwards the vector payload to the vector DB.
 func (s *HiveService) Ingest(ctx context.Context, req *proto.Chunk) (*proto.Status, error) {
 	if req == nil {
@@ -77,25 +162,81 @@ func (s *HiveService) Ingest(ctx context.Context, req *proto.Chunk) (*proto.Stat
 	if req.Metadata != nil {
 		orgID = req.Metadata["organization_id"]
 	}
-	
+
+	// A drone sends a document's chunks one call at a time, so a slot per call bounds its documents too
+	if s.ingestLimiter != nil && orgID != "" {
+		release, err := s.ingestLimiter.Acquire(ctx, "org:"+orgID)
+		if err != nil {
+			if errors.Is(err, ErrIngestQueueFull) {
+				log.Printf("Rejected gRPC ingest for org %s: %v", orgID, err)
+				return nil, status.Error(codes.ResourceExhausted, err.Error())
+			}
+			return nil, status.FromContextError(err).Err()
+		}
+		defer release()
+	}
+
+	// Scan for PII before the chunk is stored (masks the content if the org policy says so)
+	if s.piiGuard != nil {
+		document := req.Metadata["filename"]
+		if document == "" {
+			document = req.DocumentId
+		}
+		req.Content = s.piiGuard.Apply(ctx, req.Content, orgID, req.Metadata["client_id"], document)
+	}
+
+	// Chunk position within the document (used to reconstruct full text when re-chunking)
+	chunkIndex := 0
+	if idx, ok := req.Metadata["chunk_index"]; ok {
+		fmt.Sscanf(idx, "%d", &chunkIndex)
+	}
+
+	// A moved/renamed file replaces its old document: drop the old chunks before storing the new ones
+	if replaced := req.Metadata[ReplacesDocumentMetadataKey]; replaced != "" {
+		if err := s.removeDocument(ctx, replaced, req.Id, orgID); err != nil {
+			return &proto.Status{
+				Success: false,
+				Message: fmt.Sprintf("failed to replace document %s: %v", replaced, err),
+			}, nil
+		}
+	}
+
+	// The first chunk of a re-ingested file clears the document's previous chunks, so a file that
+	// shrank doesn't leave its old tail behind
+	if _, indexed := req.Metadata["chunk_index"]; indexed && chunkIndex == 0 && req.DocumentId != "" {
+		if err := s.removeDocument(ctx, req.DocumentId, req.Id, orgID); err != nil {
+			return &proto.Status{
+				Success: false,
+				Message: fmt.Sprintf("failed to clear previous chunks of document %s: %v", req.DocumentId, err),
+			}, nil
+		}
+	}
+
 	const insertChunk = `
 		INSERT OR REPLACE INTO chunks (id, document_id, content, chunk_index, organization_id)
 		VALUES (?, ?, ?, ?, ?);
 	`
 
-	if _, err := s.db.ExecContext(ctx, insertChunk, req.Id, req.DocumentId, req.Content, 0, orgID); err != nil {
+	if _, err := s.db.ExecContext(ctx, insertChunk, req.Id, req.DocumentId, req.Content, chunkIndex, orgID); err != nil {
 		return &proto.Status{
 			Success: false,
 			Message: fmt.Sprintf("failed to store chunk: %v", err),
 		}, nil
 	}
 
+	if s.answerCache != nil {
+		s.answerCache.Invalidate(orgID)
+	}
+
 	// Generate embedding if not provided
 	var vector []float32
+	truncated := false
 	if req.Vector != nil && len(req.Vector) > 0 {
 		vector = req.Vector
 	} else if s.embedder != nil {
-		embedding, err := s.embedder.EmbedText(ctx, req.Content)
+		var embedInput string
+		embedInput, truncated = embeddings.TruncateInput(req.Content)
+		embedding, err := s.embedder.EmbedText(ctx, embedInput)
 		if err != nil {
 			log.Printf("failed to generate embedding for chunk %s: %v", req.Id, err)
 			// Continue without vector - chunk is still stored in SQLite
@@ -113,11 +254,24 @@ func (s *HiveService) Ingest(ctx context.Context, req *proto.Chunk) (*proto.Stat
 				metadata[k] = v
 			}
 		}
+		// The payload's document_id groups chunks into documents for search and citations
+		if metadata["document_id"] == "" {
+			metadata["document_id"] = req.DocumentId
+		}
 		// Add content to metadata if not already present
 		// This is critical for chat/RAG to work - content must be in Qdrant payload
 		if req.Content != "" && metadata["content"] == "" {
 			metadata["content"] = req.Content
 		}
+		// Chunks arrive one at a time: until the document is complete a chunk carries its own language,
+		// then every chunk is tagged with the document's (see tagDocumentLanguage)
+		if metadata["language"] == "" {
+			metadata["language"] = langdetect.Detect(req.Content).Code
+		}
+		if truncated {
+			metadata[embeddings.TruncatedMetadataKey] = "true"
+		}
+		delete(metadata, ReplacesDocumentMetadataKey)
 		
 		if err := s.vectorDB.Upsert(ctx, req.Id, vector, metadata); err != nil {
 			log.Printf("[ERROR] Job failed: vector upsert failed for chunk (pointID: %s): %v", req.Id, err)
@@ -126,11 +280,6 @@ func (s *HiveService) Ingest(ctx context.Context, req *proto.Chunk) (*proto.Stat
 	}
 
 	// Track document chunks for analyst processing
-	chunkIndex := 0
-	if idx, ok := req.Metadata["chunk_index"]; ok {
-		fmt.Sscanf(idx, "%d", &chunkIndex)
-	}
-	
 	totalChunks := 0
 	if total, ok := req.Metadata["total_chunks"]; ok {
 		fmt.Sscanf(total, "%d", &totalChunks)
@@ -146,6 +295,7 @@ func (s *HiveService) Ingest(ctx context.Context, req *proto.Chunk) (*proto.Stat
 			metadata:       make(map[string]string),
 			totalChunks:    totalChunks,
 			receivedChunks: 0,
+			detectLanguage: req.Metadata["language"] == "",
 		}
 		if req.Metadata != nil {
 			for k, v := range req.Metadata {
@@ -156,6 +306,7 @@ func (s *HiveService) Ingest(ctx context.Context, req *proto.Chunk) (*proto.Stat
 	}
 	tracker.mu.Lock()
 	tracker.chunks = append(tracker.chunks, req.Content)
+	tracker.pointIDs = append(tracker.pointIDs, req.Id)
 	tracker.receivedChunks++
 	tracker.lastChunk = time.Now()
 	tracker.mu.Unlock()
@@ -188,6 +339,24 @@ func (s *HiveService) Ingest(ctx context.Context, req *proto.Chunk) (*proto.Stat
 		}()
 	}
 
+	if shouldAnalyze {
+		s.tagDocumentLanguage(ctx, tracker)
+	}
+
+	// Record the completed document and summarize it in the background
+	if shouldAnalyze && s.summarizer != nil {
+		tracker.mu.Lock()
+		filename := tracker.metadata["filename"]
+		if filename == "" {
+			filename = tracker.metadata["file_path"]
+		}
+		if filename == "" {
+			filename = req.DocumentId
+		}
+		s.summarizer.SummarizeAsync(orgID, req.DocumentId, filename, tracker.chunks)
+		tracker.mu.Unlock()
+	}
+
 	// Trigger analyst if document is complete
 	if shouldAnalyze && s.analystPool != nil {
 		tracker.mu.Lock()
@@ -204,13 +373,19 @@ func (s *HiveService) Ingest(ctx context.Context, req *proto.Chunk) (*proto.Stat
 		
 		// Create analyst job
 		job := worker.AnalystJob{
-			FilePath:  filePath,
-			Content:   fullContent,
-			Metadata:  metadata,
-			ClientID:  clientID,
-			AllChunks: tracker.chunks,
+			FilePath:       filePath,
+			Content:        fullContent,
+			Metadata:       metadata,
+			ClientID:       clientID,
+			AllChunks:      tracker.chunks,
+			OrganizationID: metadata["organization_id"],
+			DocumentID:     req.DocumentId,
+		}
+		if s.sampler != nil {
+			s.sampler.Enqueue(ctx, s.analystPool, job)
+		} else {
+			s.analystPool.Enqueue(job)
 		}
-		s.analystPool.Enqueue(job)
 		
 		// Clean up tracker
 		s.docMu.Lock()
@@ -218,33 +393,6 @@ func (s *HiveService) Ingest(ctx context.Context, req *proto.Chunk) (*proto.Stat
 		s.docMu.Unlock()
 	}
 
-	// Notification Logic: Check for "CONFIDENTIAL" keyword (only on first chunk to avoid duplicates)
-	if s.wsManager != nil && req.Metadata != nil {
-		// Only check first chunk to avoid duplicate notifications
-		if chunkIndex == 0 {
-			contentUpper := strings.ToUpper(req.Content)
-			if strings.Contains(contentUpper, "CONFIDENTIAL") {
-				clientID := req.Metadata["client_id"]
-				if clientID != "" {
-					filename := req.Metadata["filename"]
-					if filename == "" {
-						filename = req.DocumentId
-					}
-
-					notification := NotificationMessage{
-						Type:    "ALERT",
-						Message: fmt.Sprintf("Sensitive document detected: %s", filename),
-						Level:   "critical",
-					}
-
-					if err := s.wsManager.SendNotification(clientID, notification); err != nil {
-						log.Printf("Failed to send notification to client %s: %v", clientID, err)
-					}
-				}
-			}
-		}
-	}
-
 	return &proto.Status{
 		Success: true,
 		Message: "chunk ingested",
@@ -252,7 +400,68 @@ func (s *HiveService) Ingest(ctx context.Context, req *proto.Chunk) (*proto.Stat
 	}, nil
 }
 
-// Query delegates to the vector DB and stitches the textual payload from SQLite.
+// DeleteDocument removes a document's chunks, vectors and listing, e.g. after the drone saw its
+// file deleted or moved out of every watched directory.
+func (s *HiveService) DeleteDocument(ctx context.Context, req *proto.DeleteDocumentRequest) (*proto.Status, error) {
+	if req == nil || req.DocumentId == "" {
+		return &proto.Status{Success: false, Message: "document_id is required"}, nil
+	}
+	orgID := req.Metadata["organization_id"]
+
+	if err := s.removeDocument(ctx, req.DocumentId, "", orgID); err != nil {
+		return &proto.Status{
+			Success: false,
+			Message: fmt.Sprintf("failed to delete document %s: %v", req.DocumentId, err),
+		}, nil
+	}
+	if s.summarizer != nil {
+		if err := s.summarizer.Store().DeleteDocument(orgID, req.DocumentId); err != nil {
+			log.Printf("Failed to remove deleted document %s from the listing: %v", req.DocumentId, err)
+		}
+	}
+	if s.answerCache != nil {
+		s.answerCache.Invalidate(orgID)
+	}
+
+	// Forget a partly received upload of the document
+	s.docMu.Lock()
+	delete(s.docTrackers, req.DocumentId)
+	s.docMu.Unlock()
+
+	if s.auditLogs != nil {
+		details := fmt.Sprintf("Client [%s] deleted file [%s] (document [%s])", req.Metadata["client_id"], req.Metadata["filename"], req.DocumentId)
+		if err := s.auditLogs.LogAction(req.Metadata["client_id"], database.AuditActionDelete, details, orgID); err != nil {
+			log.Printf("Failed to log delete audit entry: %v", err)
+		}
+	}
+
+	log.Printf("Deleted document %s (org %s, file %s)", req.DocumentId, orgID, req.Metadata["file_path"])
+	return &proto.Status{Success: true, Message: "document deleted"}, nil
+}
+
+// removeDocument deletes a document's chunks and vectors when its file is deleted or before it is
+// replaced: by a moved or renamed file, or by a re-ingest of the same file. keepID (the chunk being ingested, which may
+// reuse an ID) keeps its SQLite row; its point is upserted again straight after.
+func (s *HiveService) removeDocument(ctx context.Context, documentID, keepID, orgID string) error {
+	deleted, err := s.vectorDB.DeleteByDocument(ctx, documentID, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to delete vectors: %w", err)
+	}
+	result, err := s.db.ExecContext(ctx,
+		"DELETE FROM chunks WHERE document_id = ? AND organization_id = ? AND id != ?",
+		documentID, orgID, keepID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete chunks: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows > 0 || deleted > 0 {
+		log.Printf("Removed %d chunks and %d vectors of document %s (org %s)", rows, deleted, documentID, orgID)
+	}
+	return nil
+}
+
+// Query delegates to the vector DB and stitches the textual payload from SQLite. Results are limited
+// to the organization in the "organization_id" gRPC metadata when the caller sets it.
 func (s *HiveService) Query(ctx context.Context, req *proto.Search) (*proto.Result, error) {
 	// Generate query embedding if not provided
 	var queryVector []float32
@@ -268,43 +477,31 @@ func (s *HiveService) Query(ctx context.Context, req *proto.Search) (*proto.Resu
 		return &proto.Result{}, fmt.Errorf("query text or vector is required")
 	}
 
-	topK := int(req.TopK)
-	if topK <= 0 {
-		topK = 10 // default
-	}
+	topK := clampTopK(int(req.TopK), 10, s.maxTopK)
 
-	// For gRPC calls, organization_id is not available in context
-	// We'll search all orgs but filter results by organization_id from metadata
-	// This is a legacy endpoint - ideally organization_id should come from gRPC metadata
-	matches, err := s.vectorDB.Search(ctx, queryVector, topK, "")
+	// Callers name their organization in the gRPC metadata; without it every organization is searched
+	orgID := grpcOrganizationID(ctx)
+	matches, err := s.vectorDB.Search(ctx, queryVector, topK, orgID)
 	if err != nil {
 		return &proto.Result{}, fmt.Errorf("vector search failed: %w", err)
 	}
 
+	// Fetch every match's content in one query instead of one per match
+	ids := make([]string, len(matches))
+	for i, match := range matches {
+		ids[i] = match.ID
+	}
+	contents, err := NewChunkContentStore(s.db).LoadOrganizationContent(ctx, ids, orgID)
+	if err != nil {
+		return &proto.Result{}, err
+	}
+
 	protoMatches := make([]*proto.Match, 0, len(matches))
 	for _, match := range matches {
-		// Extract organization_id from match metadata for filtering
-		matchOrgID := ""
-		if match.Metadata != nil {
-			matchOrgID = match.Metadata["organization_id"]
-		}
-		
-		var content string
-		// Filter chunks query by organization_id for multi-tenancy isolation
-		var query string
-		var args []interface{}
-		if matchOrgID != "" {
-			query = "SELECT content FROM chunks WHERE id = ? AND organization_id = ?"
-			args = []interface{}{match.ID, matchOrgID}
-		} else {
-			// Fallback: if no org_id in metadata, still filter by id (backward compatibility)
-			query = "SELECT content FROM chunks WHERE id = ?"
-			args = []interface{}{match.ID}
-		}
-		
-		if err := s.db.QueryRowContext(ctx, query, args...).Scan(&content); err != nil {
+		content, ok := contents[match.ID]
+		if !ok {
 			// Missing row should not fail the entire request.
-			log.Printf("failed to fetch chunk %s content: %v", match.ID, err)
+			log.Printf("failed to fetch chunk %s content: not found", match.ID)
 			continue
 		}
 		protoMatches = append(protoMatches, &proto.Match{
@@ -318,3 +515,15 @@ func (s *HiveService) Query(ctx context.Context, req *proto.Search) (*proto.Resu
 
 	return &proto.Result{Matches: protoMatches}, nil
 }
+
+// grpcOrganizationID returns the organization named in the incoming gRPC metadata, or "" if none is
+func grpcOrganizationID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(OrganizationMetadataKey); len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	return ""
+}
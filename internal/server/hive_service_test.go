@@ -0,0 +1,358 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/proto"
+	"github.com/the-hive/internal/vectordb"
+	"google.golang.org/grpc/metadata"
+)
+
+// pointsVectorDB keeps upserted points so tests can check what the vector store holds
+type pointsVectorDB struct {
+	vectordb.MockVectorDB
+	points  map[string]map[string]string
+	batches int // UpsertBatch calls
+}
+
+func (v *pointsVectorDB) Upsert(ctx context.Context, id string, vector []float32, metadata map[string]string) error {
+	v.points[id] = metadata
+	return nil
+}
+
+func (v *pointsVectorDB) UpsertBatch(ctx context.Context, points []vectordb.PointInput) error {
+	v.batches++
+	for _, p := range points {
+		v.points[p.ID] = p.Metadata
+	}
+	return nil
+}
+
+func (v *pointsVectorDB) Delete(ctx context.Context, id string) error {
+	delete(v.points, id)
+	return nil
+}
+
+func (v *pointsVectorDB) DeleteByDocument(ctx context.Context, documentID, organizationID string) (int, error) {
+	deleted := 0
+	for id, metadata := range v.points {
+		if metadata["document_id"] == documentID && (organizationID == "" || metadata["organization_id"] == organizationID) {
+			delete(v.points, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func TestHiveService_IngestMovedFileReplacesOldDocument(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.Exec(`CREATE TABLE chunks (
+		id TEXT PRIMARY KEY,
+		document_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		chunk_index INTEGER NOT NULL,
+		organization_id TEXT
+	)`); err != nil {
+		t.Fatalf("Failed to create chunks table: %v", err)
+	}
+	vdb := &pointsVectorDB{points: map[string]map[string]string{}}
+	service := NewHiveService(db, vdb, nil)
+	ctx := context.Background()
+
+	ingest := func(id, docID, content string, extra map[string]string) {
+		t.Helper()
+		metadata := map[string]string{"organization_id": "org-1", "filename": docID}
+		for k, v := range extra {
+			metadata[k] = v
+		}
+		status, err := service.Ingest(ctx, &proto.Chunk{Id: id, DocumentId: docID, Content: content, Vector: []float32{1, 0}, Metadata: metadata})
+		if err != nil || !status.Success {
+			t.Fatalf("Ingest %s failed: %v, %+v", id, err, status)
+		}
+	}
+
+	ingest("old-0", "draft.txt", "Quarterly planning", nil)
+	ingest("old-1", "draft.txt", "notes.", nil)
+	ingest("other-0", "draft.txt", "Another org's copy", map[string]string{"organization_id": "org-2"})
+
+	// The renamed file arrives under its new document_id, pointing at the one it replaces
+	ingest("new-0", "final.txt", "Quarterly planning", map[string]string{ReplacesDocumentMetadataKey: "draft.txt"})
+	ingest("new-1", "final.txt", "notes.", nil)
+
+	var oldChunks int
+	if err := db.QueryRow("SELECT COUNT(*) FROM chunks WHERE document_id = 'draft.txt' AND organization_id = 'org-1'").Scan(&oldChunks); err != nil {
+		t.Fatalf("Failed to count chunks: %v", err)
+	}
+	if oldChunks != 0 {
+		t.Errorf("Expected the old document's chunks to be removed, %d remain", oldChunks)
+	}
+	var newChunks int
+	if err := db.QueryRow("SELECT COUNT(*) FROM chunks WHERE document_id = 'final.txt'").Scan(&newChunks); err != nil {
+		t.Fatalf("Failed to count chunks: %v", err)
+	}
+	if newChunks != 2 {
+		t.Errorf("Expected 2 chunks for the new document, got %d", newChunks)
+	}
+
+	for _, id := range []string{"old-0", "old-1"} {
+		if _, ok := vdb.points[id]; ok {
+			t.Errorf("Expected vector %s of the old document to be deleted", id)
+		}
+	}
+	if _, ok := vdb.points["other-0"]; !ok {
+		t.Error("Expected another organization's document with the same name to be left alone")
+	}
+	if hint, ok := vdb.points["new-0"][ReplacesDocumentMetadataKey]; ok {
+		t.Errorf("Expected the replacement hint not to be stored in the payload, got %q", hint)
+	}
+}
+
+func TestHiveService_DeleteDocumentRemovesChunksAndVectors(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.Exec(`CREATE TABLE chunks (
+		id TEXT PRIMARY KEY,
+		document_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		chunk_index INTEGER NOT NULL,
+		organization_id TEXT
+	)`); err != nil {
+		t.Fatalf("Failed to create chunks table: %v", err)
+	}
+	vdb := &pointsVectorDB{points: map[string]map[string]string{}}
+	service := NewHiveService(db, vdb, nil)
+	auditLogs, err := database.NewAuditLogStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create audit log store: %v", err)
+	}
+	service.SetAuditLogStore(auditLogs)
+	ctx := context.Background()
+
+	for _, c := range []struct{ id, docID string }{{"a-0", "report.txt"}, {"a-1", "report.txt"}, {"b-0", "notes.txt"}} {
+		status, err := service.Ingest(ctx, &proto.Chunk{Id: c.id, DocumentId: c.docID, Content: "text", Vector: []float32{1, 0}, Metadata: map[string]string{"filename": c.docID}})
+		if err != nil || !status.Success {
+			t.Fatalf("Ingest %s failed: %v, %+v", c.id, err, status)
+		}
+	}
+
+	status, err := service.DeleteDocument(ctx, &proto.DeleteDocumentRequest{DocumentId: "report.txt", Metadata: map[string]string{"client_id": "drone-1", "filename": "report.txt"}})
+	if err != nil || !status.Success {
+		t.Fatalf("DeleteDocument failed: %v, %+v", err, status)
+	}
+	if logs, _ := auditLogs.GetRecentLogs(10, string(database.AuditActionDelete), ""); len(logs) != 1 || logs[0].Details != "Client [drone-1] deleted file [report.txt] (document [report.txt])" {
+		t.Errorf("Expected the deletion to be audited, got %+v", logs)
+	}
+
+	var remaining int
+	if err := db.QueryRow("SELECT COUNT(*) FROM chunks WHERE document_id = 'report.txt'").Scan(&remaining); err != nil {
+		t.Fatalf("Failed to count chunks: %v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("Expected the deleted document's chunks to be removed, %d remain", remaining)
+	}
+	for _, id := range []string{"a-0", "a-1"} {
+		if _, ok := vdb.points[id]; ok {
+			t.Errorf("Expected vector %s of the deleted document to be removed", id)
+		}
+	}
+	if _, ok := vdb.points["b-0"]; !ok {
+		t.Error("Expected other documents to be left alone")
+	}
+
+	if status, _ := service.DeleteDocument(ctx, &proto.DeleteDocumentRequest{}); status.Success {
+		t.Error("Expected a request without document_id to fail")
+	}
+}
+
+func TestHiveService_OffloadedContentResolvesFromSQLite(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.Exec(`CREATE TABLE chunks (
+		id TEXT PRIMARY KEY,
+		document_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		chunk_index INTEGER NOT NULL,
+		organization_id TEXT
+	)`); err != nil {
+		t.Fatalf("Failed to create chunks table: %v", err)
+	}
+	vdb := vectordb.NewMemoryVectorDB()
+	vdb.SetContentStore(NewChunkContentStore(db), 10)
+	service := NewHiveService(db, vdb, nil)
+	ctx := context.Background()
+
+	content := "Retention policy: invoices are kept for seven years."
+	status, err := service.Ingest(ctx, &proto.Chunk{
+		Id: "chunk-0", DocumentId: "policy.txt", Content: content, Vector: []float32{1, 0},
+		Metadata: map[string]string{"organization_id": "org-1", "filename": "policy.txt"},
+	})
+	if err != nil || !status.Success {
+		t.Fatalf("Ingest failed: %v, %+v", err, status)
+	}
+
+	matches, err := vdb.Search(ctx, []float32{1, 0}, 5, "org-1")
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("Expected one match, got %d (%v)", len(matches), err)
+	}
+	if got := matches[0].Metadata["content"]; got != content {
+		t.Errorf("Expected search to resolve the full content, got %q", got)
+	}
+	if _, ok := matches[0].Metadata[vectordb.ContentOffloadedKey]; ok {
+		t.Error("Expected the offload marker to be dropped from resolved results")
+	}
+
+	// The payload only has a preview, so an edit to the SQLite row shows the content comes from there
+	if _, err := db.Exec("UPDATE chunks SET content = 'Revised retention policy.' WHERE id = 'chunk-0'"); err != nil {
+		t.Fatalf("Failed to update chunk: %v", err)
+	}
+	matches, err = vdb.Search(ctx, []float32{1, 0}, 5, "org-1")
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("Expected one match, got %d (%v)", len(matches), err)
+	}
+	if got := matches[0].Metadata["content"]; got != "Revised retention policy." {
+		t.Errorf("Expected content to be read from SQLite, got %q", got)
+	}
+
+	// Without the row only the preview is left
+	if _, err := db.Exec("DELETE FROM chunks WHERE id = 'chunk-0'"); err != nil {
+		t.Fatalf("Failed to delete chunk: %v", err)
+	}
+	matches, err = vdb.Search(ctx, []float32{1, 0}, 5, "org-1")
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("Expected one match, got %d (%v)", len(matches), err)
+	}
+	if got := matches[0].Metadata["content"]; got != "Retention " {
+		t.Errorf("Expected the payload to hold a 10-character preview, got %q", got)
+	}
+}
+
+// sqlQueries counts the queries run through the "sqlite3_counting" driver
+var (
+	sqlQueries             int64
+	registerCountingDriver sync.Once
+)
+
+// countingConn is a SQLite connection that counts QueryContext calls
+type countingConn struct {
+	*sqlite3.SQLiteConn
+}
+
+func (c *countingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	atomic.AddInt64(&sqlQueries, 1)
+	return c.SQLiteConn.QueryContext(ctx, query, args)
+}
+
+type countingDriver struct {
+	sqlite3.SQLiteDriver
+}
+
+func (d *countingDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.SQLiteDriver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{conn.(*sqlite3.SQLiteConn)}, nil
+}
+
+func TestHiveService_QueryFetchesOrganizationContentInOneQuery(t *testing.T) {
+	registerCountingDriver.Do(func() { sql.Register("sqlite3_counting", &countingDriver{}) })
+	db, err := sql.Open("sqlite3_counting", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(`CREATE TABLE chunks (
+		id TEXT PRIMARY KEY,
+		document_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		chunk_index INTEGER NOT NULL,
+		organization_id TEXT
+	)`); err != nil {
+		t.Fatalf("Failed to create chunks table: %v", err)
+	}
+
+	vdb := vectordb.NewMemoryVectorDB()
+	ctx := context.Background()
+	for i, c := range []struct{ id, orgID, content string }{
+		{"a-0", "org-a", "Renewal terms for Acme."},
+		{"a-1", "org-a", "Acme pricing schedule."},
+		{"a-2", "org-a", "Acme support hours."},
+		{"b-0", "org-b", "Globex renewal terms."},
+	} {
+		if _, err := db.Exec("INSERT INTO chunks (id, document_id, content, chunk_index, organization_id) VALUES (?, ?, ?, ?, ?)", c.id, "doc-"+c.orgID, c.content, i, c.orgID); err != nil {
+			t.Fatalf("Failed to insert chunk: %v", err)
+		}
+		vdb.Upsert(ctx, c.id, []float32{1, float32(i) / 10}, map[string]string{"organization_id": c.orgID, "document_id": "doc-" + c.orgID})
+	}
+	service := NewHiveService(db, vdb, nil)
+
+	atomic.StoreInt64(&sqlQueries, 0)
+	orgCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(OrganizationMetadataKey, "org-a"))
+	result, err := service.Query(orgCtx, &proto.Search{QueryVector: []float32{1, 0}, TopK: 10})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if queries := atomic.LoadInt64(&sqlQueries); queries != 1 {
+		t.Errorf("Expected the content of every match fetched in 1 query, got %d", queries)
+	}
+	if len(result.Matches) != 3 {
+		t.Fatalf("Expected org-a's 3 chunks, got %+v", result.Matches)
+	}
+	for _, match := range result.Matches {
+		if match.Metadata["organization_id"] != "org-a" || match.Content == "" {
+			t.Errorf("Expected only org-a chunks with their content, got %+v", match)
+		}
+	}
+	if result.Matches[0].ChunkId != "a-0" || result.Matches[0].Content != "Renewal terms for Acme." {
+		t.Errorf("Expected matches in score order with their own content, got %+v", result.Matches[0])
+	}
+
+	// Without an organization in the metadata every organization is searched
+	result, err = service.Query(ctx, &proto.Search{QueryVector: []float32{1, 0}, TopK: 10})
+	if err != nil || len(result.Matches) != 4 {
+		t.Errorf("Expected all 4 chunks without an organization, got %d (err %v)", len(result.Matches), err)
+	}
+}
+
+func TestHiveService_IngestTagsChunksWithDocumentLanguage(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.Exec(`CREATE TABLE chunks (
+		id TEXT PRIMARY KEY,
+		document_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		chunk_index INTEGER NOT NULL,
+		organization_id TEXT
+	)`); err != nil {
+		t.Fatalf("Failed to create chunks table: %v", err)
+	}
+	vdb := vectordb.NewMemoryVectorDB()
+	service := NewHiveService(db, vdb, nil)
+	ctx := context.Background()
+
+	english := "The contract is valid for one year and it can be renewed by either party with written notice."
+	german := "Der Vertrag ist für ein Jahr gültig und kann von jeder Partei mit einer schriftlichen Mitteilung verlängert werden."
+	contents := []string{english, english, german}
+	for i, content := range contents {
+		metadata := map[string]string{"organization_id": "org-1", "chunk_index": fmt.Sprintf("%d", i), "total_chunks": "3"}
+		status, err := service.Ingest(ctx, &proto.Chunk{Id: fmt.Sprintf("c-%d", i), DocumentId: "contract.txt", Content: content, Vector: []float32{1, 0}, Metadata: metadata})
+		if err != nil || !status.Success {
+			t.Fatalf("Ingest chunk %d failed: %v, %+v", i, err, status)
+		}
+	}
+
+	for i := range contents {
+		payload, _ := vdb.GetPayload(ctx, fmt.Sprintf("c-%d", i))
+		if payload["language"] != "en" {
+			t.Errorf("Expected chunk %d to carry the document's language en, got %q", i, payload["language"])
+		}
+	}
+}
@@ -8,17 +8,23 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/the-hive/internal/ai"
 	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/docid"
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/langdetect"
 	"github.com/the-hive/internal/processor"
 	"github.com/the-hive/internal/vectordb"
 	"github.com/the-hive/internal/worker"
 )
 
+// IngestBatchSize is the most chunks of a document embedded in one call, and upserted in one call
+const IngestBatchSize = 64
+
 // IngestRequest represents the ingestion request payload
 type IngestRequest struct {
 	FilePath string            `json:"file_path"`
@@ -30,18 +36,24 @@ type IngestRequest struct {
 type IngestHandler struct {
 	vectorDB      vectordb.VectorDB
 	chunker       *processor.Chunker
+	chunkers      *processor.ChunkerRegistry // Picks a chunking strategy by file type when set, instead of chunker
 	wsManager     *WebSocketManager
 	analystPool   *worker.AnalystPool
 	taggerPool    *worker.TaggerPool
 	eventLogger   *database.EventLogger
 	auditLogStore *database.AuditLogStore
+	piiGuard      *PIIGuard
+	sampler       *AnalysisSampler
+	summarizer    *DocumentSummarizer
+	answerCache   *ChatAnswerCache    // Invalidated for the organization after each ingest
+	embedder      embeddings.Embedder // Embeds chunks when set (e.g. a BatchingEmbedder), instead of ai.GenerateEmbedding
 }
 
 // NewIngestHandler creates a new ingest handler with dependencies
 func NewIngestHandler(vectorDB vectordb.VectorDB, wsManager *WebSocketManager, analystPool *worker.AnalystPool, taggerPool *worker.TaggerPool, eventLogger *database.EventLogger, auditLogStore *database.AuditLogStore) *IngestHandler {
 	return &IngestHandler{
 		vectorDB:      vectorDB,
-		chunker:       processor.NewChunker(),
+		chunker:       processor.NewChunkerFromEnv(),
 		wsManager:     wsManager,
 		analystPool:   analystPool,
 		taggerPool:    taggerPool,
@@ -50,6 +62,36 @@ func NewIngestHandler(vectorDB vectordb.VectorDB, wsManager *WebSocketManager, a
 	}
 }
 
+// SetPIIGuard sets the PII guard used to alert on or mask PII before storage
+func (h *IngestHandler) SetPIIGuard(piiGuard *PIIGuard) {
+	h.piiGuard = piiGuard
+}
+
+// SetAnalysisSampler sets the sampler that decides which documents get AI rule analysis
+func (h *IngestHandler) SetAnalysisSampler(sampler *AnalysisSampler) {
+	h.sampler = sampler
+}
+
+// SetDocumentSummarizer sets the summarizer that records ingested documents and their summaries
+func (h *IngestHandler) SetDocumentSummarizer(summarizer *DocumentSummarizer) {
+	h.summarizer = summarizer
+}
+
+// SetAnswerCache sets the chat answer cache invalidated when an organization's documents change
+func (h *IngestHandler) SetAnswerCache(cache *ChatAnswerCache) {
+	h.answerCache = cache
+}
+
+// SetEmbedder sets the embedder used for chunks, e.g. a BatchingEmbedder that coalesces concurrent ingests
+func (h *IngestHandler) SetEmbedder(embedder embeddings.Embedder) {
+	h.embedder = embedder
+}
+
+// SetChunkerRegistry chunks each file with the strategy for its type (markdown by heading, code by block, ...)
+func (h *IngestHandler) SetChunkerRegistry(chunkers *processor.ChunkerRegistry) {
+	h.chunkers = chunkers
+}
+
 // HandleIngest handles POST /api/v1/ingest requests
 func (h *IngestHandler) HandleIngest(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -73,8 +115,35 @@ func (h *IngestHandler) HandleIngest(w http.ResponseWriter, r *http.Request) {
 		fmt.Printf(" [METADATA] %+v\n", req.Metadata)
 	}
 
+	// Get organization ID from context
+	orgID := ""
+	if orgIDVal := r.Context().Value("organization_id"); orgIDVal != nil {
+		if orgIDStr, ok := orgIDVal.(string); ok {
+			orgID = orgIDStr
+		}
+	}
+
+	// Scan for PII before anything is stored (masks the content if the org policy says so)
+	if h.piiGuard != nil {
+		documentName := req.Metadata["filename"]
+		if documentName == "" {
+			documentName = req.FilePath
+		}
+		req.Content = h.piiGuard.Apply(r.Context(), req.Content, orgID, req.Metadata["client_id"], documentName)
+	}
+
 	// Chunk the content
-	chunks, err := h.chunker.ChunkText(req.Content)
+	var chunks []string
+	var err error
+	if h.chunkers != nil {
+		filename := req.Metadata["filename"]
+		if filename == "" {
+			filename = req.FilePath
+		}
+		chunks, err = h.chunkers.ChunkFile(filename, req.Content)
+	} else {
+		chunks, err = h.chunker.ChunkText(req.Content)
+	}
 	if err != nil {
 		log.Printf("Failed to chunk text: %v", err)
 		w.Header().Set("Content-Type", "application/json")
@@ -89,29 +158,96 @@ func (h *IngestHandler) HandleIngest(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	documentID := req.Metadata["filename"]
-	if documentID == "" {
-		documentID = req.FilePath
+	// The document_id comes from the client and full path so same-named files in different folders
+	// stay separate; the friendly filename is kept in metadata for search results and listings
+	sourcePath := req.Metadata["file_path"]
+	if sourcePath == "" {
+		sourcePath = req.FilePath
+	}
+	if sourcePath == "" {
+		sourcePath = req.Metadata["filename"]
+	}
+	documentID := docid.New(req.Metadata["client_id"], sourcePath)
+	filename := req.Metadata["filename"]
+	if filename == "" && sourcePath != "" {
+		filename = filepath.Base(sourcePath)
+	}
+
+	// Tag every chunk with the document's dominant language unless the client supplied one
+	language := req.Metadata["language"]
+	if language == "" {
+		language = langdetect.DetectDocument(chunks)
+	}
+
+	// Point IDs are deterministic per chunk index, so a re-ingest overwrites the chunks it still has;
+	// clearing the document first removes the ones past the end of a file that shrank
+	if deleted, err := h.vectorDB.DeleteByDocument(ctx, documentID, orgID); err != nil {
+		log.Printf("[ERROR] Failed to clear previous chunks of %s: %v", req.FilePath, err)
+	} else if deleted > 0 {
+		log.Printf("Cleared %d previous chunks of %s before re-ingesting", deleted, req.FilePath)
 	}
 
 	successCount := 0
 	failedChunks := 0
 	var lastError error
 
-	for i, chunk := range chunks {
-		// Generate embedding
-		embedding, err := ai.GenerateEmbedding(chunk)
+	// Generate embeddings in batches; an over-long chunk is truncated for the embedding only and
+	// flagged. A failed batch fails only its own chunks.
+	vectors := make([][]float32, len(chunks))
+	truncatedChunks := make([]bool, len(chunks))
+	for start := 0; start < len(chunks); start += IngestBatchSize {
+		end := min(start+IngestBatchSize, len(chunks))
+		inputs := make([]string, 0, end-start)
+		for i := start; i < end; i++ {
+			input, truncated := embeddings.TruncateInput(chunks[i])
+			inputs = append(inputs, input)
+			truncatedChunks[i] = truncated
+		}
+		batch, err := h.embedBatch(ctx, inputs)
 		if err != nil {
-			log.Printf("[ERROR] Job failed: Failed to generate embedding for chunk %d: %v", i, err)
+			log.Printf("[ERROR] Job failed: Failed to generate embeddings for chunks %d-%d: %v", start, end-1, err)
 			lastError = err
+			continue
+		}
+		copy(vectors[start:end], batch)
+	}
+
+	// Points are upserted in batches too; a failed upsert fails only its own chunks
+	var pending []vectordb.PointInput
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		if err := h.vectorDB.UpsertBatch(ctx, pending); err != nil {
+			log.Printf("[ERROR] Job failed: Failed to upsert chunks %s-%s to Qdrant: %v", pending[0].Metadata["chunk_index"], pending[len(pending)-1].Metadata["chunk_index"], err)
+			lastError = err
+			failedChunks += len(pending)
+		} else {
+			successCount += len(pending)
+
+			// Send to tagging pool for auto-tagging (non-blocking, only for first chunk)
+			if first := pending[0]; h.taggerPool != nil && first.Metadata["chunk_index"] == "0" {
+				job := worker.TaggingJob{
+					ChunkID:  first.ID,  // Use the deterministic UUID
+					Content:  chunks[0], // Use first chunk for tagging
+					VectorDB: h.vectorDB,
+				}
+				h.taggerPool.Enqueue(job)
+			}
+		}
+		pending = nil
+	}
+
+	for i, chunk := range chunks {
+		embedding := vectors[i]
+		if embedding == nil {
 			failedChunks++
 			continue
 		}
 
-		// Create a deterministic UUID based on the file path and chunk index
+		// Create a deterministic UUID based on the document and chunk index
 		// This ensures if we re-ingest the same file, we update the existing vectors (Idempotency)
-		seed := fmt.Sprintf("%s-%d", req.FilePath, i)
-		pointID := uuid.NewSHA1(uuid.NameSpaceURL, []byte(seed)).String()
+		pointID := docid.PointID(documentID, i)
 
 		// Prepare metadata for Qdrant
 		// Ensure filename, chunk_index, and file_path are explicitly in the payload
@@ -119,12 +255,7 @@ func (h *IngestHandler) HandleIngest(w http.ResponseWriter, r *http.Request) {
 		metadata["document_id"] = documentID
 		metadata["chunk_index"] = fmt.Sprintf("%d", i)
 		metadata["content"] = chunk // Store content in metadata
-		// Explicitly add filename (preserve from request metadata)
-		if req.Metadata["filename"] != "" {
-			metadata["filename"] = req.Metadata["filename"]
-		} else {
-			metadata["filename"] = documentID // Fallback to documentID
-		}
+		metadata["filename"] = filename
 		// Explicitly add file_path (preserve from request)
 		if req.Metadata["file_path"] != "" {
 			metadata["file_path"] = req.Metadata["file_path"]
@@ -138,29 +269,26 @@ func (h *IngestHandler) HandleIngest(w http.ResponseWriter, r *http.Request) {
 		if req.Metadata["client_id"] != "" {
 			metadata["client_id"] = req.Metadata["client_id"]
 		}
-
-		// Upsert to Qdrant
-		if err := h.vectorDB.Upsert(ctx, pointID, embedding, metadata); err != nil {
-			log.Printf("[ERROR] Job failed: Failed to upsert chunk %d to Qdrant (pointID: %s): %v", i, pointID, err)
-			lastError = err
-			failedChunks++
-			continue
+		if orgID != "" {
+			metadata["organization_id"] = orgID
 		}
-
-		// Send to tagging pool for auto-tagging (non-blocking, only for first chunk)
-		if h.taggerPool != nil && i == 0 {
-			job := worker.TaggingJob{
-				ChunkID:  pointID, // Use the deterministic UUID
-				Content:  chunk,   // Use first chunk for tagging
-				VectorDB: h.vectorDB,
-			}
-			h.taggerPool.Enqueue(job)
+		metadata["language"] = language
+		if truncatedChunks[i] {
+			metadata[embeddings.TruncatedMetadataKey] = "true"
 		}
 
-		successCount++
+		// Queue the point for the next upsert to Qdrant
+		pending = append(pending, vectordb.PointInput{ID: pointID, Vector: embedding, Metadata: metadata})
+		if len(pending) == IngestBatchSize {
+			flush()
+		}
 	}
+	flush()
 
 	fmt.Printf(" [INGESTED] %s: %d/%d chunks stored\n", req.FilePath, successCount, len(chunks))
+	if h.answerCache != nil && successCount > 0 {
+		h.answerCache.Invalidate(orgID)
+	}
 
 	// Log error summary if any chunks failed
 	if failedChunks > 0 {
@@ -193,61 +321,32 @@ func (h *IngestHandler) HandleIngest(w http.ResponseWriter, r *http.Request) {
 		if documentName == "" {
 			documentName = req.FilePath
 		}
-		// Get organization ID from context
-		orgID := ""
-		if orgIDVal := r.Context().Value("organization_id"); orgIDVal != nil {
-			if orgIDStr, ok := orgIDVal.(string); ok {
-				orgID = orgIDStr
-			}
-		}
 		details := fmt.Sprintf("Client [%s] uploaded file [%s] (%d chunks)", clientIP, documentName, successCount)
 		if err := h.auditLogStore.LogAction(clientIP, database.AuditActionIngest, details, orgID); err != nil {
 			log.Printf("Failed to log ingest audit entry: %v", err)
 		}
 	}
 
+	// Record the document and summarize it in the background
+	if h.summarizer != nil && successCount > 0 {
+		h.summarizer.SummarizeAsync(orgID, documentID, filename, chunks)
+	}
+
 	// Send to analyst pool for rule checking (non-blocking)
 	if h.analystPool != nil {
 		clientID := req.Metadata["client_id"]
 		job := worker.AnalystJob{
-			FilePath: req.FilePath,
-			Content:  req.Content,
-			Metadata: req.Metadata,
-			ClientID: clientID,
+			FilePath:       req.FilePath,
+			Content:        req.Content,
+			Metadata:       req.Metadata,
+			ClientID:       clientID,
+			OrganizationID: orgID,
+			DocumentID:     documentID,
 		}
-		h.analystPool.Enqueue(job)
-	}
-
-	// Legacy notification logic: Check for "CONFIDENTIAL" keyword (keep for backward compatibility)
-	if h.wsManager != nil {
-		contentUpper := strings.ToUpper(req.Content)
-		if strings.Contains(contentUpper, "CONFIDENTIAL") {
-			// Get client_id from metadata (sent by drone-client)
-			clientID := req.Metadata["client_id"]
-			if clientID != "" {
-				filename := req.Metadata["filename"]
-				if filename == "" {
-					filename = req.FilePath
-				}
-
-				notification := NotificationMessage{
-					Type:    "ALERT",
-					Message: fmt.Sprintf("Sensitive document detected: %s", filename),
-					Level:   "critical",
-				}
-
-				if err := h.wsManager.SendNotification(clientID, notification); err != nil {
-					log.Printf("Failed to send notification to client %s: %v", clientID, err)
-				}
-
-				// Log alert event
-				if h.eventLogger != nil {
-					details := fmt.Sprintf("Alert triggered: CONFIDENTIAL keyword detected")
-					if err := h.eventLogger.LogEvent("alert", filename, details); err != nil {
-						log.Printf("Failed to log alert event: %v", err)
-					}
-				}
-			}
+		if h.sampler != nil {
+			h.sampler.Enqueue(r.Context(), h.analystPool, job)
+		} else {
+			h.analystPool.Enqueue(job)
 		}
 	}
 
@@ -262,6 +361,42 @@ func (h *IngestHandler) HandleIngest(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// embed generates a chunk's embedding with the configured embedder, or ai.GenerateEmbedding without one
+func (h *IngestHandler) embed(ctx context.Context, text string) ([]float32, error) {
+	if h.embedder != nil {
+		return h.embedder.EmbedText(ctx, text)
+	}
+	return ai.GenerateEmbedding(text)
+}
+
+// embedBatch generates the embeddings of a batch of chunks in one call, with the configured embedder or
+// ai.GenerateEmbeddings. A single chunk goes through embed, so a BatchingEmbedder can still coalesce
+// it with the chunks of other ingests.
+func (h *IngestHandler) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 1 {
+		vector, err := h.embed(ctx, texts[0])
+		if err != nil {
+			return nil, err
+		}
+		return [][]float32{vector}, nil
+	}
+
+	var vectors [][]float32
+	var err error
+	if h.embedder != nil {
+		vectors, err = h.embedder.EmbedBatch(ctx, texts)
+	} else {
+		vectors, err = ai.GenerateEmbeddings(texts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("got %d embeddings for %d chunks", len(vectors), len(texts))
+	}
+	return vectors, nil
+}
+
 // getClientIPFromRequest extracts the client IP address from the request
 func getClientIPFromRequest(r *http.Request) string {
 	// Check X-Forwarded-For header (for proxies/load balancers)
@@ -0,0 +1,282 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/the-hive/internal/docid"
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/jobs"
+	"github.com/the-hive/internal/processor"
+	"github.com/the-hive/internal/vectordb"
+)
+
+func TestHandleIngest_SameNamedFilesStayDistinct(t *testing.T) {
+	// Without an API key embeddings are placeholder vectors, so ingest runs offline
+	t.Setenv("OPENAI_API_KEY", "")
+
+	vdb := &pointsVectorDB{points: map[string]map[string]string{}}
+	handler := NewIngestHandler(vdb, nil, nil, nil, nil, nil)
+
+	paths := []string{"/shares/finance/report.pdf", "/shares/legal/report.pdf"}
+	for _, path := range paths {
+		body, _ := json.Marshal(IngestRequest{
+			FilePath: path,
+			Content:  "Quarterly report stored at " + path + ".",
+			Metadata: map[string]string{"filename": "report.pdf", "client_id": "drone-1"},
+		})
+		rec := httptest.NewRecorder()
+		handler.HandleIngest(rec, withUser(httptest.NewRequest(http.MethodPost, "/api/v1/ingest", bytes.NewReader(body)), nil, "org-1"))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Ingest of %s failed: %d %s", path, rec.Code, rec.Body.String())
+		}
+	}
+
+	documents := map[string]string{}
+	for _, metadata := range vdb.points {
+		documents[metadata["document_id"]] = metadata["file_path"]
+		if metadata["filename"] != "report.pdf" {
+			t.Errorf("Expected the friendly filename to be kept, got %q", metadata["filename"])
+		}
+	}
+	if len(documents) != 2 {
+		t.Fatalf("Expected two distinct documents, got %v", documents)
+	}
+	for _, path := range paths {
+		if documents[docid.New("drone-1", path)] != path {
+			t.Errorf("Expected %s stored under its own document_id, got %v", path, documents)
+		}
+	}
+
+	// The same full path from another drone in another organization gets its own points
+	body, _ := json.Marshal(IngestRequest{
+		FilePath: paths[0],
+		Content:  "Another tenant's report at the same path.",
+		Metadata: map[string]string{"filename": "report.pdf", "client_id": "drone-2"},
+	})
+	rec := httptest.NewRecorder()
+	handler.HandleIngest(rec, withUser(httptest.NewRequest(http.MethodPost, "/api/v1/ingest", bytes.NewReader(body)), nil, "org-2"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ingest for org-2 failed: %d %s", rec.Code, rec.Body.String())
+	}
+	byOrg := map[string]int{}
+	for _, metadata := range vdb.points {
+		byOrg[metadata["organization_id"]]++
+		if metadata["organization_id"] == "org-1" && metadata["document_id"] == docid.New("drone-2", paths[0]) {
+			t.Errorf("Expected org-2's ingest to leave org-1's points alone, got %v", metadata)
+		}
+	}
+	if len(vdb.points) != 3 || byOrg["org-1"] != 2 || byOrg["org-2"] != 1 {
+		t.Errorf("Expected org-1's 2 points and org-2's 1 point to survive, got %v", byOrg)
+	}
+}
+
+func TestHandleIngest_ReingestClearsChunksOfShrunkFile(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+
+	vdb := &pointsVectorDB{points: map[string]map[string]string{}}
+	handler := NewIngestHandler(vdb, nil, nil, nil, nil, nil)
+	// A chunk of another organization's document with the same ID must survive
+	vdb.points["other-org"] = map[string]string{"document_id": docid.New("drone-1", "/shares/notes.txt"), "organization_id": "org-2"}
+
+	ingest := func(content string) {
+		t.Helper()
+		body, _ := json.Marshal(IngestRequest{
+			FilePath: "/shares/notes.txt",
+			Content:  content,
+			Metadata: map[string]string{"filename": "notes.txt", "client_id": "drone-1"},
+		})
+		rec := httptest.NewRecorder()
+		handler.HandleIngest(rec, withUser(httptest.NewRequest(http.MethodPost, "/api/v1/ingest", bytes.NewReader(body)), nil, "org-1"))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Ingest failed: %d %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	ingest(strings.Repeat("A long paragraph about quarterly planning and budgets. ", 200))
+	before := len(vdb.points)
+	if before < 3 {
+		t.Fatalf("Expected the long file to produce several chunks, got %d points", before)
+	}
+	ingest("Short notes.")
+
+	if len(vdb.points) != 2 {
+		t.Errorf("Expected one chunk for the shrunk file plus the other organization's point, got %d", len(vdb.points))
+	}
+	if _, ok := vdb.points["other-org"]; !ok {
+		t.Error("Expected another organization's document to be left alone")
+	}
+	for id, metadata := range vdb.points {
+		if id != "other-org" && metadata["organization_id"] != "org-1" {
+			t.Errorf("Expected point %s to carry the organization, got %v", id, metadata)
+		}
+	}
+}
+
+// countingEmbedder records the provider calls a batching embedder makes
+type countingEmbedder struct {
+	embeddings.MockEmbedder
+	mu    sync.Mutex
+	calls int
+	texts int
+}
+
+func (e *countingEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	e.mu.Lock()
+	e.calls++
+	e.texts += len(texts)
+	e.mu.Unlock()
+	return e.MockEmbedder.EmbedBatch(ctx, texts)
+}
+
+func TestHandleIngest_ConcurrentIngestsShareEmbeddingCalls(t *testing.T) {
+	provider := &countingEmbedder{MockEmbedder: *embeddings.NewMockEmbedder(8)}
+	handler := NewIngestHandler(&vectordb.MockVectorDB{}, nil, nil, nil, nil, nil)
+	handler.SetEmbedder(embeddings.NewBatchingEmbedder(provider, 100*time.Millisecond, 64))
+
+	const ingests = 8
+	var wg sync.WaitGroup
+	codes := make([]int, ingests)
+	for i := 0; i < ingests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body, _ := json.Marshal(IngestRequest{
+				FilePath: fmt.Sprintf("/shares/note-%d.txt", i),
+				Content:  "A short single-chunk note.",
+				Metadata: map[string]string{"client_id": "drone-1"},
+			})
+			rec := httptest.NewRecorder()
+			handler.HandleIngest(rec, withUser(httptest.NewRequest(http.MethodPost, "/api/v1/ingest", bytes.NewReader(body)), nil, "org-1"))
+			codes[i] = rec.Code
+		}(i)
+	}
+	wg.Wait()
+
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("Ingest %d failed with %d", i, code)
+		}
+	}
+	if provider.texts != ingests {
+		t.Fatalf("Expected all %d chunks to be embedded, got %d", ingests, provider.texts)
+	}
+	if provider.calls >= ingests {
+		t.Errorf("Expected %d concurrent ingests to share provider calls, got %d calls", ingests, provider.calls)
+	}
+}
+
+// failingBatchEmbedder fails the batch starting with the given text
+type failingBatchEmbedder struct {
+	countingEmbedder
+	failFirst string
+}
+
+func (e *failingBatchEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if texts[0] == e.failFirst {
+		return nil, fmt.Errorf("provider unavailable")
+	}
+	return e.countingEmbedder.EmbedBatch(ctx, texts)
+}
+
+func TestHandleIngest_EmbedsChunksInBatches(t *testing.T) {
+	// One sentence per chunk, so every chunk is distinct
+	chunkSize, _ := processor.NewChunkerFromEnv().Settings()
+	var content strings.Builder
+	for i := 0; i < IngestBatchSize+6; i++ {
+		sentence := fmt.Sprintf("Paragraph %d of the handbook. ", i)
+		content.WriteString(sentence + strings.Repeat("x", chunkSize-len(sentence)-1) + ".\n\n")
+	}
+	ingest := func(t *testing.T, provider embeddings.Embedder) (*pointsVectorDB, map[string]interface{}) {
+		t.Helper()
+		vdb := &pointsVectorDB{points: map[string]map[string]string{}}
+		handler := NewIngestHandler(vdb, nil, nil, nil, nil, nil)
+		handler.SetEmbedder(provider)
+		body, _ := json.Marshal(IngestRequest{
+			FilePath: "/shares/handbook.txt",
+			Content:  content.String(),
+			Metadata: map[string]string{"client_id": "drone-1"},
+		})
+		rec := httptest.NewRecorder()
+		handler.HandleIngest(rec, withUser(httptest.NewRequest(http.MethodPost, "/api/v1/ingest", bytes.NewReader(body)), nil, "org-1"))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Ingest failed: %d %s", rec.Code, rec.Body.String())
+		}
+		var resp map[string]interface{}
+		json.NewDecoder(rec.Body).Decode(&resp)
+		return vdb, resp
+	}
+
+	provider := &countingEmbedder{MockEmbedder: *embeddings.NewMockEmbedder(8)}
+	vdb, resp := ingest(t, provider)
+	total := int(resp["chunks_total"].(float64))
+	if total <= IngestBatchSize || int(resp["chunks_stored"].(float64)) != total {
+		t.Fatalf("Expected more than %d chunks, all stored, got %v", IngestBatchSize, resp)
+	}
+	if provider.calls != 2 || provider.texts != total {
+		t.Errorf("Expected %d chunks embedded in 2 calls, got %d texts in %d calls", total, provider.texts, provider.calls)
+	}
+	if vdb.batches != 2 {
+		t.Errorf("Expected the points upserted in 2 batches, got %d", vdb.batches)
+	}
+	for i := 0; i < total; i++ {
+		pointID := docid.PointID(docid.New("drone-1", "/shares/handbook.txt"), i)
+		if vdb.points[pointID]["chunk_index"] != fmt.Sprint(i) {
+			t.Errorf("Expected chunk %d stored under its deterministic ID", i)
+		}
+	}
+
+	// A failed batch doesn't abort the rest of the document
+	failing := &failingBatchEmbedder{countingEmbedder: countingEmbedder{MockEmbedder: *embeddings.NewMockEmbedder(8)}}
+	chunks, _ := processor.NewChunkerFromEnv().ChunkText(content.String())
+	failing.failFirst = chunks[0]
+	vdb, resp = ingest(t, failing)
+	if stored := int(resp["chunks_stored"].(float64)); stored != total-IngestBatchSize || len(vdb.points) != stored {
+		t.Errorf("Expected only the second batch's %d chunks stored, got %v", total-IngestBatchSize, resp)
+	}
+}
+
+func TestHandleIngest_DocumentSurvivesReconcile(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+
+	// HTTP ingest writes points only, with their content in the payload, and no chunk rows
+	db := newTestDB(t)
+	if _, err := db.Exec("CREATE TABLE chunks (id TEXT PRIMARY KEY, document_id TEXT, content TEXT, chunk_index INTEGER, organization_id TEXT)"); err != nil {
+		t.Fatalf("Failed to create chunks table: %v", err)
+	}
+	vdb := vectordb.NewMemoryVectorDB()
+	handler := NewIngestHandler(vdb, nil, nil, nil, nil, nil)
+	body, _ := json.Marshal(IngestRequest{
+		FilePath: "/shares/policies/travel.md",
+		Content:  "Travel must be booked through the approved agency.",
+		Metadata: map[string]string{"filename": "travel.md", "client_id": "drone-1"},
+	})
+	rec := httptest.NewRecorder()
+	handler.HandleIngest(rec, withUser(httptest.NewRequest(http.MethodPost, "/api/v1/ingest", bytes.NewReader(body)), nil, "org-1"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ingest failed: %d %s", rec.Code, rec.Body.String())
+	}
+	before, _ := vdb.ListPointIDs(context.Background(), "org-1")
+	if len(before) == 0 {
+		t.Fatal("Expected the ingest to store points")
+	}
+
+	reconciler := jobs.NewReconciler(db, vdb, embeddings.NewMockEmbedder(4))
+	if err := reconciler.ReconcileOrganization(context.Background(), "reconcile-1", "org-1", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	after, _ := vdb.ListPointIDs(context.Background(), "org-1")
+	if len(after) != len(before) {
+		t.Errorf("Expected the HTTP-ingested document to survive a reconcile, had %d points, now %d", len(before), len(after))
+	}
+}
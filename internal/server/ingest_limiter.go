@@ -0,0 +1,193 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultIngestConcurrencyPerOrg is how many ingests one organization may run at once unless INGEST_MAX_CONCURRENT_PER_ORG changes it
+	DefaultIngestConcurrencyPerOrg = 4
+	// DefaultIngestQueuePerOrg is how many more of an organization's ingests may wait for a slot before it gets 429s
+	DefaultIngestQueuePerOrg = 16
+	// DefaultIngestQueueTimeout is how long a queued ingest waits for a slot before it gets a 429
+	DefaultIngestQueueTimeout = 30 * time.Second
+)
+
+// ErrIngestQueueFull is returned when an organization already has as many ingests running and waiting as it may
+var ErrIngestQueueFull = errors.New("too many concurrent ingests for this organization")
+
+// orgIngestSlots are one organization's running ingests (the semaphore) and queued ones
+type orgIngestSlots struct {
+	slots   chan struct{}
+	waiting int
+	users   int // Running plus waiting; the entry is dropped when it reaches zero
+}
+
+// IngestLimiter bounds the ingests each organization runs at once, so one tenant flooding ingest
+// can't monopolize embedding and analysis for everyone sharing the server. Ingests over the limit
+// wait in a bounded queue; once that is full, or the wait times out, they are rejected.
+type IngestLimiter struct {
+	maxConcurrent int
+	maxQueued     int
+	queueTimeout  time.Duration
+	mu            sync.Mutex
+	orgs          map[string]*orgIngestSlots
+}
+
+// NewIngestLimiter allows maxConcurrent ingests per organization, with up to maxQueued more waiting
+// at most queueTimeout for a slot
+func NewIngestLimiter(maxConcurrent, maxQueued int, queueTimeout time.Duration) *IngestLimiter {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	if maxQueued < 0 {
+		maxQueued = 0
+	}
+	return &IngestLimiter{
+		maxConcurrent: maxConcurrent,
+		maxQueued:     maxQueued,
+		queueTimeout:  queueTimeout,
+		orgs:          make(map[string]*orgIngestSlots),
+	}
+}
+
+// NewIngestLimiterFromEnv reads INGEST_MAX_CONCURRENT_PER_ORG (default 4, 0 disables the limit),
+// INGEST_MAX_QUEUED_PER_ORG (default 16) and INGEST_QUEUE_TIMEOUT (default 30s). It returns nil
+// when the limit is disabled.
+func NewIngestLimiterFromEnv() *IngestLimiter {
+	maxConcurrent := DefaultIngestConcurrencyPerOrg
+	if v := os.Getenv("INGEST_MAX_CONCURRENT_PER_ORG"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			maxConcurrent = n
+		} else {
+			log.Printf("Invalid INGEST_MAX_CONCURRENT_PER_ORG %q, using default %d", v, DefaultIngestConcurrencyPerOrg)
+		}
+	}
+	if maxConcurrent == 0 {
+		return nil
+	}
+
+	maxQueued := DefaultIngestQueuePerOrg
+	if v := os.Getenv("INGEST_MAX_QUEUED_PER_ORG"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			maxQueued = n
+		} else {
+			log.Printf("Invalid INGEST_MAX_QUEUED_PER_ORG %q, using default %d", v, DefaultIngestQueuePerOrg)
+		}
+	}
+
+	queueTimeout := DefaultIngestQueueTimeout
+	if v := os.Getenv("INGEST_QUEUE_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			queueTimeout = d
+		} else {
+			log.Printf("Invalid INGEST_QUEUE_TIMEOUT %q, using default %s", v, DefaultIngestQueueTimeout)
+		}
+	}
+	return NewIngestLimiter(maxConcurrent, maxQueued, queueTimeout)
+}
+
+// Acquire takes one of the organization's ingest slots, waiting in its queue if they are all taken.
+// It returns ErrIngestQueueFull if the queue is full or the wait times out, and the context's error
+// if it ends first. Call release once the ingest is done.
+func (l *IngestLimiter) Acquire(ctx context.Context, orgID string) (release func(), err error) {
+	l.mu.Lock()
+	org, ok := l.orgs[orgID]
+	if !ok {
+		org = &orgIngestSlots{slots: make(chan struct{}, l.maxConcurrent)}
+		l.orgs[orgID] = org
+	}
+	select {
+	case org.slots <- struct{}{}:
+		org.users++
+		l.mu.Unlock()
+		return l.releaser(orgID, org), nil
+	default:
+	}
+	if org.waiting >= l.maxQueued {
+		if org.users == 0 {
+			delete(l.orgs, orgID)
+		}
+		l.mu.Unlock()
+		return nil, ErrIngestQueueFull
+	}
+	org.waiting++
+	org.users++
+	l.mu.Unlock()
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+	select {
+	case org.slots <- struct{}{}:
+		l.mu.Lock()
+		org.waiting--
+		l.mu.Unlock()
+		return l.releaser(orgID, org), nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timer.C:
+		err = ErrIngestQueueFull
+	}
+	l.mu.Lock()
+	org.waiting--
+	l.leave(orgID, org)
+	l.mu.Unlock()
+	return nil, err
+}
+
+// releaser returns the function that gives back an acquired slot (safe to call more than once)
+func (l *IngestLimiter) releaser(orgID string, org *orgIngestSlots) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-org.slots
+			l.mu.Lock()
+			l.leave(orgID, org)
+			l.mu.Unlock()
+		})
+	}
+}
+
+// leave drops one user of the organization's slots, and the entry once nobody uses it; l.mu must be held
+func (l *IngestLimiter) leave(orgID string, org *orgIngestSlots) {
+	org.users--
+	if org.users == 0 && l.orgs[orgID] == org {
+		delete(l.orgs, orgID)
+	}
+}
+
+// Middleware runs each request under its organization's ingest limit and answers 429 with Retry-After
+// when the organization's queue is full. It must run after authentication so the organization is
+// known; a nil limiter passes requests through unchanged.
+func (l *IngestLimiter) Middleware(next http.Handler) http.Handler {
+	if l == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := rateLimitTenant(r)
+		if tenant == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		release, err := l.Acquire(r.Context(), tenant)
+		if err != nil {
+			if errors.Is(err, ErrIngestQueueFull) {
+				log.Printf("Rejected ingest for %s: %v", tenant, err)
+				w.Header().Set("Retry-After", "5")
+				writeJSONError(w, http.StatusTooManyRequests, "too many concurrent ingests for this organization, retry shortly")
+			}
+			return
+		}
+		defer release()
+		next.ServeHTTP(w, r)
+	})
+}
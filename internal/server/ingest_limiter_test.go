@@ -0,0 +1,144 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIngestLimiter_BoundsOneOrganizationWithoutBlockingOthers(t *testing.T) {
+	limiter := NewIngestLimiter(2, 1, time.Minute)
+
+	var running, maxRunning int32
+	started := make(chan string, 10)
+	unblock := make(chan struct{})
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orgID, _ := r.Context().Value("organization_id").(string)
+		if orgID == "org-a" {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+					break
+				}
+			}
+			started <- orgID
+			<-unblock
+			atomic.AddInt32(&running, -1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	ingest := func(orgID string) int {
+		rec := httptest.NewRecorder()
+		req := withUser(httptest.NewRequest(http.MethodPost, "/api/v1/ingest", nil), &database.User{ID: "user-" + orgID}, orgID)
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Two of org-a's ingests run and the third queues
+	codes := make(chan int, 3)
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- ingest("org-a")
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(2 * time.Second):
+			t.Fatal("Expected two of org-a's ingests to start")
+		}
+	}
+	select {
+	case <-started:
+		t.Fatal("Expected org-a's third ingest to wait for a slot")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Once the third is queued org-a's queue is full, so its next ingest is rejected while org-b proceeds
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		limiter.mu.Lock()
+		queued := limiter.orgs["org:org-a"].waiting
+		limiter.mu.Unlock()
+		if queued == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected org-a's third ingest to queue")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if code := ingest("org-a"); code != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 once org-a's queue is full, got %d", code)
+	}
+	if code := ingest("org-b"); code != http.StatusOK {
+		t.Errorf("Expected org-b's ingest to proceed while org-a is saturated, got %d", code)
+	}
+
+	close(unblock)
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("Expected org-a's running and queued ingests to complete, got %d", code)
+		}
+	}
+	if max := atomic.LoadInt32(&maxRunning); max != 2 {
+		t.Errorf("Expected at most 2 concurrent ingests for org-a, got %d", max)
+	}
+	if len(limiter.orgs) != 0 {
+		t.Errorf("Expected idle organizations to be dropped, got %d", len(limiter.orgs))
+	}
+}
+
+func TestHiveService_IngestSharesTheOrganizationLimit(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.Exec(`CREATE TABLE chunks (
+		id TEXT PRIMARY KEY,
+		document_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		chunk_index INTEGER NOT NULL,
+		organization_id TEXT
+	)`); err != nil {
+		t.Fatalf("Failed to create chunks table: %v", err)
+	}
+	limiter := NewIngestLimiter(1, 0, time.Minute)
+	service := NewHiveService(db, &pointsVectorDB{points: map[string]map[string]string{}}, nil)
+	service.SetIngestLimiter(limiter)
+
+	// An HTTP ingest for org-a holds its only slot
+	release, err := limiter.Acquire(context.Background(), "org:org-a")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	chunk := func(orgID string) *proto.Chunk {
+		return &proto.Chunk{Id: "c-" + orgID, DocumentId: "doc.txt", Content: "text", Metadata: map[string]string{"organization_id": orgID}}
+	}
+	if _, err := service.Ingest(context.Background(), chunk("org-a")); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted while org-a is at its limit, got %v", err)
+	}
+	if resp, err := service.Ingest(context.Background(), chunk("org-b")); err != nil || !resp.Success {
+		t.Errorf("Expected another organization to ingest, got %v, %+v", err, resp)
+	}
+
+	release()
+	if resp, err := service.Ingest(context.Background(), chunk("org-a")); err != nil || !resp.Success {
+		t.Errorf("Expected org-a to ingest once its slot is free, got %v, %+v", err, resp)
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/the-hive/internal/database"
+)
+
+// CreateInviteRequest is an admin's request to invite someone to the organization
+type CreateInviteRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role,omitempty"` // Defaults to the configured default role
+}
+
+// CreateInviteResponse returns the invite and its one-time link
+type CreateInviteResponse struct {
+	Invite    *database.Invite `json:"invite"`
+	InviteURL string           `json:"invite_url"` // Send this to the invitee; it is not shown again
+}
+
+// AcceptInviteRequest is the invitee choosing their password
+type AcceptInviteRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// InviteUserStore is the subset of the user store needed to accept invites
+type InviteUserStore interface {
+	GetUserByEmail(email string) (*database.User, error)
+	CreateUser(email, password string, role database.UserRole, orgID string) (*database.User, error)
+	DeleteUser(id string) error
+}
+
+// InviteHandler handles invite-based onboarding: admins invite by email and role,
+// and the invitee sets their own password through the tokened link.
+type InviteHandler struct {
+	invites       *database.InviteStore
+	users         InviteUserStore
+	auditLogStore *database.AuditLogStore
+	ttl           time.Duration
+}
+
+// NewInviteHandler creates a new invite handler
+func NewInviteHandler(invites *database.InviteStore, users InviteUserStore, auditLogStore *database.AuditLogStore) *InviteHandler {
+	return &InviteHandler{
+		invites:       invites,
+		users:         users,
+		auditLogStore: auditLogStore,
+		ttl:           database.DefaultInviteTTL,
+	}
+}
+
+// SetTTL sets how long new invite links stay valid
+func (h *InviteHandler) SetTTL(ttl time.Duration) {
+	if ttl > 0 {
+		h.ttl = ttl
+	}
+}
+
+// HandleInvites handles GET (pending invites) and POST (create invite) on /api/v1/invites
+func (h *InviteHandler) HandleInvites(w http.ResponseWriter, r *http.Request) {
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+	if orgID == "" {
+		writeJSONError(w, http.StatusBadRequest, "organization_id is required")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		invites, err := h.invites.ListPendingInvites(r.Context(), orgID)
+		if err != nil {
+			log.Printf("Failed to list invites for org %s: %v", orgID, err)
+			writeJSONError(w, http.StatusInternalServerError, "failed to list invites")
+			return
+		}
+		if invites == nil {
+			invites = []database.Invite{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"invites": invites})
+	case http.MethodPost:
+		h.createInvite(w, r, dbUser, orgID)
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (h *InviteHandler) createInvite(w http.ResponseWriter, r *http.Request, dbUser *database.User, orgID string) {
+	var req CreateInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+
+	email := strings.TrimSpace(req.Email)
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		writeJSONError(w, http.StatusBadRequest, "invalid email")
+		return
+	}
+
+	role := database.UserRole(strings.ToLower(strings.TrimSpace(req.Role)))
+	if role == "" {
+		role = defaultUserRole
+	}
+	if !importableRoles[role] {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid role: %q", req.Role))
+		return
+	}
+
+	if existing, err := h.users.GetUserByEmail(email); err == nil && existing != nil {
+		writeJSONError(w, http.StatusConflict, "a user with this email already exists")
+		return
+	}
+
+	invite, token, err := h.invites.CreateInvite(r.Context(), email, role, orgID, dbUser.ID, h.ttl)
+	if err != nil {
+		log.Printf("Failed to create invite for %s: %v", email, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to create invite")
+		return
+	}
+
+	if h.auditLogStore != nil {
+		details := fmt.Sprintf("Invite for %s as %s created by %s (expires %s)", invite.Email, invite.Role, dbUser.Email, invite.ExpiresAt.Format(time.RFC3339))
+		if err := h.auditLogStore.LogAction(getClientIP(r), database.AuditActionInvite, details, orgID); err != nil {
+			log.Printf("Failed to log invite audit entry: %v", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateInviteResponse{
+		Invite:    invite,
+		InviteURL: inviteURL(r, token),
+	})
+}
+
+// inviteURL builds the link the invitee opens to choose a password
+func inviteURL(r *http.Request, token string) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/login?invite=%s", scheme, r.Host, url.QueryEscape(token))
+}
+
+// HandleRevokeInvite handles DELETE /api/v1/invites/{id}
+func (h *InviteHandler) HandleRevokeInvite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	_, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.invites.RevokeInvite(r.Context(), orgID, r.PathValue("id"))
+	if errors.Is(err, database.ErrInviteNotFound) {
+		writeJSONError(w, http.StatusNotFound, "invite not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to revoke invite: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to revoke invite")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleAcceptInvite handles POST /api/v1/invites/accept (public - the token is the credential).
+// The account is created with the invitee's own password and the invite is consumed.
+func (h *InviteHandler) HandleAcceptInvite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req AcceptInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	if req.Token == "" {
+		writeJSONError(w, http.StatusBadRequest, "token is required")
+		return
+	}
+	if len(req.Password) < minTempPasswordLen {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("password must be at least %d characters", minTempPasswordLen))
+		return
+	}
+
+	invite, err := h.invites.GetPendingInvite(r.Context(), req.Token)
+	if err != nil {
+		writeInviteError(w, err)
+		return
+	}
+
+	if existing, err := h.users.GetUserByEmail(invite.Email); err == nil && existing != nil {
+		writeJSONError(w, http.StatusConflict, "a user with this email already exists")
+		return
+	}
+
+	user, err := h.users.CreateUser(invite.Email, req.Password, invite.Role, invite.OrganizationID)
+	if err != nil {
+		log.Printf("Failed to create user from invite %s: %v", invite.ID, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to create account")
+		return
+	}
+
+	// Consume the invite; if another request won the race, undo this account
+	if err := h.invites.MarkAccepted(r.Context(), invite.ID); err != nil {
+		if delErr := h.users.DeleteUser(user.ID); delErr != nil {
+			log.Printf("Failed to roll back user %s for invite %s: %v", user.ID, invite.ID, delErr)
+		}
+		writeInviteError(w, err)
+		return
+	}
+
+	if h.auditLogStore != nil {
+		details := fmt.Sprintf("Invite for %s accepted (role %s)", invite.Email, invite.Role)
+		if err := h.auditLogStore.LogAction(getClientIP(r), database.AuditActionInvite, details, invite.OrganizationID); err != nil {
+			log.Printf("Failed to log invite audit entry: %v", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// writeInviteError maps invite store errors to responses
+func writeInviteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, database.ErrInviteNotFound):
+		writeJSONError(w, http.StatusNotFound, "invite not found")
+	case errors.Is(err, database.ErrInviteExpired):
+		writeJSONError(w, http.StatusGone, "invite has expired")
+	case errors.Is(err, database.ErrInviteAccepted):
+		writeJSONError(w, http.StatusConflict, "invite has already been accepted")
+	default:
+		log.Printf("Invite lookup failed: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to redeem invite")
+	}
+}
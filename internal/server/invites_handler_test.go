@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/the-hive/internal/database"
+)
+
+func newTestInviteHandler(t *testing.T, users *fakeUserStore) *InviteHandler {
+	t.Helper()
+	invites, err := database.NewInviteStore(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Failed to create invite store: %v", err)
+	}
+	return NewInviteHandler(invites, users, nil)
+}
+
+// createTestInvite invites email as an org-a admin and returns the invite token
+func createTestInvite(t *testing.T, h *InviteHandler, body string) (CreateInviteResponse, string) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/invites", strings.NewReader(body))
+	req = withUser(req, &database.User{ID: "admin-1", Email: "admin@acme.com", Role: database.RoleAdmin}, "org-a")
+	rec := httptest.NewRecorder()
+	h.HandleInvites(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp CreateInviteResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	link, err := url.Parse(resp.InviteURL)
+	if err != nil || link.Query().Get("invite") == "" {
+		t.Fatalf("Expected a tokened invite link, got %q", resp.InviteURL)
+	}
+	return resp, link.Query().Get("invite")
+}
+
+func acceptTestInvite(h *InviteHandler, token, password string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(AcceptInviteRequest{Token: token, Password: password})
+	rec := httptest.NewRecorder()
+	h.HandleAcceptInvite(rec, httptest.NewRequest(http.MethodPost, "/api/v1/invites/accept", strings.NewReader(string(body))))
+	return rec
+}
+
+func TestInviteHandler_CreateAndRedeem(t *testing.T) {
+	users := newFakeUserStore("existing@acme.com")
+	h := newTestInviteHandler(t, users)
+
+	resp, token := createTestInvite(t, h, `{"email":"new@acme.com","role":"editor"}`)
+	if resp.Invite.Email != "new@acme.com" || resp.Invite.Role != database.RoleEditor || resp.Invite.OrganizationID != "org-a" {
+		t.Errorf("Unexpected invite %+v", resp.Invite)
+	}
+	if users.users["new@acme.com"] != nil {
+		t.Fatal("No account should exist until the invite is redeemed")
+	}
+
+	// Existing users and unknown roles are rejected
+	for _, body := range []string{`{"email":"existing@acme.com"}`, `{"email":"x@acme.com","role":"super_admin"}`} {
+		req := withUser(httptest.NewRequest(http.MethodPost, "/api/v1/invites", strings.NewReader(body)), &database.User{ID: "admin-1"}, "org-a")
+		rec := httptest.NewRecorder()
+		h.HandleInvites(rec, req)
+		if rec.Code == http.StatusCreated {
+			t.Errorf("Expected %s to be rejected", body)
+		}
+	}
+
+	if rec := acceptTestInvite(h, token, "short"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected a short password to be rejected, got %d", rec.Code)
+	}
+	if rec := acceptTestInvite(h, token, "my-own-password"); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 on redemption, got %d: %s", rec.Code, rec.Body.String())
+	}
+	user := users.users["new@acme.com"]
+	if user == nil || user.Role != database.RoleEditor || user.OrganizationID != "org-a" {
+		t.Fatalf("Expected an editor account in org-a, got %+v", user)
+	}
+
+	// Tokens are single use
+	if rec := acceptTestInvite(h, token, "my-own-password"); rec.Code != http.StatusConflict {
+		t.Errorf("Expected 409 on reuse, got %d", rec.Code)
+	}
+	if rec := acceptTestInvite(h, "not-a-token", "my-own-password"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown token, got %d", rec.Code)
+	}
+}
+
+func TestInviteHandler_DefaultRole(t *testing.T) {
+	previous := defaultUserRole
+	t.Cleanup(func() { defaultUserRole = previous })
+	if err := SetDefaultUserRole(database.RoleSuperAdmin); err == nil {
+		t.Error("Expected super_admin to be rejected as a default role")
+	}
+	if err := SetDefaultUserRole(database.RoleEditor); err != nil {
+		t.Fatalf("SetDefaultUserRole failed: %v", err)
+	}
+
+	resp, _ := createTestInvite(t, newTestInviteHandler(t, newFakeUserStore()), `{"email":"new@acme.com"}`)
+	if resp.Invite.Role != database.RoleEditor {
+		t.Errorf("Expected the configured default role, got %s", resp.Invite.Role)
+	}
+}
+
+func TestInviteHandler_ExpiredInvite(t *testing.T) {
+	users := newFakeUserStore()
+	h := newTestInviteHandler(t, users)
+	h.SetTTL(time.Millisecond)
+
+	_, token := createTestInvite(t, h, `{"email":"late@acme.com"}`)
+	time.Sleep(10 * time.Millisecond)
+
+	if rec := acceptTestInvite(h, token, "my-own-password"); rec.Code != http.StatusGone {
+		t.Fatalf("Expected 410 for an expired invite, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if users.users["late@acme.com"] != nil {
+		t.Error("An expired invite must not create an account")
+	}
+}
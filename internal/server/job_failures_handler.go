@@ -0,0 +1,31 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/the-hive/internal/worker"
+)
+
+// JobFailuresResponse is the job failure counts per pool and outcome, with the latest failures
+type JobFailuresResponse struct {
+	Counts []worker.JobFailureCount `json:"counts"`
+	Recent []worker.JobFailure      `json:"recent"`
+}
+
+// HandleJobFailures handles GET /api/v1/admin/job-failures, reporting jobs dropped, failed or of an
+// unknown type in every worker pool since the server started
+func HandleJobFailures(w http.ResponseWriter, r *http.Request, recorder *worker.JobFailureRecorder) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(JobFailuresResponse{
+		Counts: recorder.Counts(),
+		Recent: recorder.Recent(),
+	})
+}
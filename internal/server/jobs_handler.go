@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/the-hive/internal/database"
+)
+
+// JobsHandler exposes the state and progress of long-running admin jobs
+type JobsHandler struct {
+	store *database.JobStatusStore
+}
+
+// NewJobsHandler creates a new jobs handler
+func NewJobsHandler(store *database.JobStatusStore) *JobsHandler {
+	return &JobsHandler{store: store}
+}
+
+// HandleListActiveJobs handles GET /api/v1/admin/jobs, listing queued and running jobs.
+// Super admins see every organization's jobs with ?all=true.
+func (h *JobsHandler) HandleListActiveJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+	if r.URL.Query().Get("all") == "true" && dbUser.Role == database.RoleSuperAdmin {
+		orgID = ""
+	} else if orgID == "" {
+		writeJSONError(w, http.StatusBadRequest, "organization_id is required")
+		return
+	}
+
+	jobs, err := h.store.ListActiveJobs(r.Context(), orgID)
+	if err != nil {
+		log.Printf("Failed to list active jobs: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to list jobs")
+		return
+	}
+	if jobs == nil {
+		jobs = []database.JobStatus{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"jobs": jobs})
+}
+
+// HandleGetJob handles GET /api/v1/admin/jobs/{id}
+func (h *JobsHandler) HandleGetJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := h.store.GetJob(r.Context(), r.PathValue("id"))
+	if err != nil {
+		log.Printf("Failed to get job status: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to get job")
+		return
+	}
+	if job == nil || (job.OrganizationID != orgID && dbUser.Role != database.RoleSuperAdmin) {
+		writeJSONError(w, http.StatusNotFound, "job not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
@@ -15,7 +15,7 @@ func TrafficLogger(next http.Handler) http.Handler {
 		start := time.Now()
 		
 		// Skip logging for polling endpoints to reduce noise
-		skipPaths := []string{"/api/v1/stats", "/api/v1/health", "/api/v1/keys"}
+		skipPaths := []string{"/api/v1/stats", "/api/v1/health", "/api/v1/ready", "/api/v1/keys"}
 		shouldLog := true
 		for _, path := range skipPaths {
 			if strings.HasPrefix(r.URL.Path, path) {
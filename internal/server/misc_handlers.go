@@ -3,8 +3,12 @@
 package server
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
+	"log"
 	"net/http"
+	"time"
 
 	"github.com/the-hive/internal/database"
 )
@@ -23,7 +27,8 @@ func HandleLoginAs(w http.ResponseWriter, r *http.Request, orgStore *database.Or
 	json.NewEncoder(w).Encode(map[string]bool{"success": true})
 }
 
-// HandleGetRuleMatches handles GET /api/v1/rules/matches
+// HandleGetRuleMatches handles GET /api/v1/rule-matches, listing the organization's rule matches newest first
+// with their AI explanation and matched chunks
 func HandleGetRuleMatches(w http.ResponseWriter, r *http.Request, ruleMatchStore *database.RuleMatchStore) {
 	if r.Method != http.MethodGet {
 		w.Header().Set("Content-Type", "application/json")
@@ -32,12 +37,26 @@ func HandleGetRuleMatches(w http.ResponseWriter, r *http.Request, ruleMatchStore
 		return
 	}
 
-	// TODO: Implement get rule matches
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode([]interface{}{})
+	limit, offset := parsePagination(r)
+	if ruleMatchStore == nil {
+		writePage(w, []database.RuleMatch{}, 0, limit, offset)
+		return
+	}
+	orgID, _ := r.Context().Value("organization_id").(string)
+	matches, err := ruleMatchStore.GetMatches(orgID, offset+limit)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	total, err := ruleMatchStore.CountMatches(orgID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writePage(w, pageSlice(matches, limit, offset), total, limit, offset)
 }
 
-// HandleGetRuleEvents handles GET /api/v1/rules/events
+// HandleGetRuleEvents handles GET /api/v1/rule-events, listing the organization's rule events newest first
 func HandleGetRuleEvents(w http.ResponseWriter, r *http.Request, ruleEventStore *database.RuleEventStore) {
 	if r.Method != http.MethodGet {
 		w.Header().Set("Content-Type", "application/json")
@@ -46,12 +65,27 @@ func HandleGetRuleEvents(w http.ResponseWriter, r *http.Request, ruleEventStore
 		return
 	}
 
-	// TODO: Implement get rule events
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode([]interface{}{})
+	limit, offset := parsePagination(r)
+	if ruleEventStore == nil {
+		writePage(w, []database.RuleEvent{}, 0, limit, offset)
+		return
+	}
+	orgID, _ := r.Context().Value("organization_id").(string)
+	events, err := ruleEventStore.GetEvents(orgID, offset+limit)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	total, err := ruleEventStore.CountEvents(orgID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writePage(w, pageSlice(events, limit, offset), total, limit, offset)
 }
 
-// HandleExportAuditLogs handles GET /api/v1/audit/export
+// HandleExportAuditLogs handles GET /api/v1/audit/export, streaming the organization's audit logs as CSV,
+// oldest first. It takes the filters of GET /api/v1/audit (action, from, to) and exports every matching log.
 func HandleExportAuditLogs(w http.ResponseWriter, r *http.Request, auditLogStore *database.AuditLogStore) {
 	if r.Method != http.MethodGet {
 		w.Header().Set("Content-Type", "application/json")
@@ -60,9 +94,52 @@ func HandleExportAuditLogs(w http.ResponseWriter, r *http.Request, auditLogStore
 		return
 	}
 
-	// TODO: Implement export audit logs
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode([]interface{}{})
+	filter, err := parseAuditLogFilter(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if orgIDVal := r.Context().Value("organization_id"); orgIDVal != nil {
+		if orgIDStr, ok := orgIDVal.(string); ok {
+			filter.OrganizationID = orgIDStr
+		}
+	}
+
+	// The header row goes out with the first log, so a failed query can still be reported as JSON
+	var writer *csv.Writer
+	start := func() {
+		w.Header().Set("Content-Type", contentTypeCSV+"; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=audit-%s.csv", time.Now().UTC().Format("20060102")))
+		w.WriteHeader(http.StatusOK)
+		writer = csv.NewWriter(w)
+		writer.Write([]string{"timestamp", "client_ip", "action", "details", "organization_id"})
+	}
+	err = auditLogStore.EachLog(filter, func(entry database.AuditLog) error {
+		if writer == nil {
+			start()
+		}
+		return writer.Write([]string{
+			entry.Timestamp.UTC().Format(time.RFC3339),
+			entry.ClientIP,
+			entry.Action,
+			entry.Details,
+			entry.OrganizationID,
+		})
+	})
+	if err != nil && writer == nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Audit log export stopped early: %v", err)
+	}
+	if writer == nil {
+		start()
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("Failed to write audit logs as CSV: %v", err)
+	}
 }
 
 // HandleListLogos handles GET /api/v1/logos
@@ -131,7 +208,7 @@ func HandleGetSystemContext(w http.ResponseWriter, r *http.Request, orgStore *da
 }
 
 // HandleSaveSystemContext handles POST /api/v1/config/system-context
-func HandleSaveSystemContext(w http.ResponseWriter, r *http.Request, orgStore *database.OrganizationStore) {
+func HandleSaveSystemContext(w http.ResponseWriter, r *http.Request, orgStore *database.OrganizationStore, auditLogStore *database.AuditLogStore) {
 	if r.Method != http.MethodPost {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusMethodNotAllowed)
@@ -164,6 +241,11 @@ func HandleSaveSystemContext(w http.ResponseWriter, r *http.Request, orgStore *d
 		return
 	}
 
+	previous := ""
+	if org, err := orgStore.GetOrganizationByID(orgID); err == nil && org != nil {
+		previous = org.SystemContext
+	}
+
 	// Update system context
 	if err := orgStore.UpdateSystemContext(orgID, req.SystemContext); err != nil {
 		w.Header().Set("Content-Type", "application/json")
@@ -171,6 +253,7 @@ func HandleSaveSystemContext(w http.ResponseWriter, r *http.Request, orgStore *d
 		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
 	}
+	auditConfigChanges(auditLogStore, r, orgID, configChange{Setting: "system_context", Before: previous, After: req.SystemContext})
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]bool{"success": true})
@@ -214,7 +297,7 @@ func HandleGetTenantOpenAIKey(w http.ResponseWriter, r *http.Request, orgStore *
 }
 
 // HandleUpdateTenantOpenAIKey handles POST /api/v1/config/tenant-openai-key
-func HandleUpdateTenantOpenAIKey(w http.ResponseWriter, r *http.Request, orgStore *database.OrganizationStore) {
+func HandleUpdateTenantOpenAIKey(w http.ResponseWriter, r *http.Request, orgStore *database.OrganizationStore, auditLogStore *database.AuditLogStore) {
 	if r.Method != http.MethodPost {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusMethodNotAllowed)
@@ -247,6 +330,11 @@ func HandleUpdateTenantOpenAIKey(w http.ResponseWriter, r *http.Request, orgStor
 		return
 	}
 
+	previous := ""
+	if org, err := orgStore.GetOrganizationByID(orgID); err == nil && org != nil {
+		previous = org.TenantOpenAIKey
+	}
+
 	// Update tenant OpenAI key
 	if err := orgStore.UpdateTenantOpenAIKey(orgID, req.TenantOpenAIKey); err != nil {
 		w.Header().Set("Content-Type", "application/json")
@@ -254,6 +342,7 @@ func HandleUpdateTenantOpenAIKey(w http.ResponseWriter, r *http.Request, orgStor
 		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
 	}
+	auditConfigChanges(auditLogStore, r, orgID, configChange{Setting: "tenant_openai_key", Before: previous, After: req.TenantOpenAIKey, Secret: true})
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]bool{"success": true})
@@ -0,0 +1,102 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/the-hive/internal/database"
+)
+
+func TestHandleGetRuleMatchesAndEvents_NewestFirstForOrganization(t *testing.T) {
+	db := newTestDB(t)
+	matchStore, err := database.NewRuleMatchStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create rule match store: %v", err)
+	}
+	eventStore, err := database.NewRuleEventStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create rule event store: %v", err)
+	}
+	if _, err := db.Exec(`
+	CREATE TABLE IF NOT EXISTS rule_matches (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		rule_id INTEGER NOT NULL,
+		rule_query TEXT,
+		uploaded_doc TEXT,
+		matched_doc TEXT,
+		match_type TEXT,
+		ai_explanation TEXT,
+		matched_chunks TEXT,
+		client_id TEXT,
+		organization_id TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE IF NOT EXISTS rule_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		rule_id INTEGER,
+		rule_query TEXT,
+		document TEXT,
+		event_type TEXT NOT NULL,
+		status TEXT,
+		message TEXT,
+		client_id TEXT,
+		organization_id TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`); err != nil {
+		t.Fatalf("Failed to create rule tables: %v", err)
+	}
+
+	start := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	for i, m := range []struct{ doc, orgID string }{{"old.pdf", "org-a"}, {"new.pdf", "org-a"}, {"theirs.pdf", "org-b"}} {
+		if _, err := db.Exec(
+			"INSERT INTO rule_matches (rule_id, rule_query, uploaded_doc, match_type, ai_explanation, matched_chunks, organization_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			7, "Mentions a penalty clause?", m.doc, "single_doc", "The contract sets a late delivery penalty.", `["Penalty: 5% per week late."]`, m.orgID, start.Add(time.Duration(i)*time.Hour),
+		); err != nil {
+			t.Fatalf("Failed to insert rule match: %v", err)
+		}
+		if _, err := db.Exec(
+			"INSERT INTO rule_events (rule_id, document, event_type, status, message, organization_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			7, m.doc, "analyzed", "completed", "Checked "+m.doc, m.orgID, start.Add(time.Duration(i)*time.Hour),
+		); err != nil {
+			t.Fatalf("Failed to insert rule event: %v", err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	HandleGetRuleMatches(rec, withUser(httptest.NewRequest(http.MethodGet, "/api/v1/rule-matches", nil), &database.User{ID: "analyst"}, "org-a"), matchStore)
+	var matches struct {
+		Items []database.RuleMatch `json:"items"`
+		Total int                  `json:"total"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&matches); err != nil {
+		t.Fatalf("Failed to decode matches: %v", err)
+	}
+	if matches.Total != 2 || len(matches.Items) != 2 {
+		t.Fatalf("Expected the organization's 2 matches, got %+v", matches)
+	}
+	newest := matches.Items[0]
+	if newest.UploadedDoc != "new.pdf" || matches.Items[1].UploadedDoc != "old.pdf" {
+		t.Errorf("Expected the newest match first, got %+v", matches.Items)
+	}
+	if newest.AIExplanation != "The contract sets a late delivery penalty." || len(newest.MatchedChunks) != 1 || newest.MatchedChunks[0] != "Penalty: 5% per week late." {
+		t.Errorf("Expected the explanation and matched chunks, got %+v", newest)
+	}
+
+	rec = httptest.NewRecorder()
+	HandleGetRuleEvents(rec, withUser(httptest.NewRequest(http.MethodGet, "/api/v1/rule-events?limit=1", nil), &database.User{ID: "analyst"}, "org-a"), eventStore)
+	var events struct {
+		Items []database.RuleEvent `json:"items"`
+		Total int                  `json:"total"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&events); err != nil {
+		t.Fatalf("Failed to decode events: %v", err)
+	}
+	if events.Total != 2 || len(events.Items) != 1 || events.Items[0].Document != "new.pdf" || events.Items[0].Status != "completed" {
+		t.Errorf("Expected the newest of the organization's 2 events, got %+v", events)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Response media types offered by the API
+const (
+	contentTypeJSON = "application/json"
+	contentTypeCSV  = "text/csv"
+)
+
+// negotiateContentType picks the response media type from the request's Accept header.
+// offered is in order of preference; the first is the default when Accept is missing or a
+// wildcard. ok is false when the client accepts none of the offered types.
+func negotiateContentType(r *http.Request, offered ...string) (string, bool) {
+	accept := strings.TrimSpace(r.Header.Get("Accept"))
+	if accept == "" {
+		return offered[0], true
+	}
+
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if qStr, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(qStr, 64); err != nil {
+				continue
+			}
+		}
+		if q <= bestQ {
+			continue
+		}
+		for _, candidate := range offered {
+			if mediaTypeMatches(mediaType, candidate) {
+				best, bestQ = candidate, q
+				break
+			}
+		}
+	}
+	return best, best != ""
+}
+
+// mediaTypeMatches reports whether an Accept range (e.g. "text/*") covers the media type
+func mediaTypeMatches(accepted, mediaType string) bool {
+	if accepted == "*/*" || accepted == mediaType {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(accepted, "/*"); ok {
+		return strings.HasPrefix(mediaType, prefix+"/")
+	}
+	return false
+}
+
+// requireAcceptable negotiates the response type, answering 406 (as JSON, so clients can always
+// parse API errors) when the client accepts none of the offered types
+func requireAcceptable(w http.ResponseWriter, r *http.Request, offered ...string) (string, bool) {
+	contentType, ok := negotiateContentType(r, offered...)
+	if !ok {
+		writeJSONError(w, http.StatusNotAcceptable, fmt.Sprintf("unsupported Accept header; available: %s", strings.Join(offered, ", ")))
+	}
+	return contentType, ok
+}
+
+// HandleAPINotFound answers unknown /api/ paths with a JSON 404 rather than falling through to
+// the web interface's HTML pages
+func HandleAPINotFound(w http.ResponseWriter, r *http.Request) {
+	writeJSONError(w, http.StatusNotFound, "unknown API endpoint: "+r.URL.Path)
+}
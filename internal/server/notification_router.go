@@ -0,0 +1,280 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/httpclient"
+	"github.com/the-hive/internal/worker"
+)
+
+// Notification channels an organization can route a severity to
+const (
+	NotificationChannelFeed      = "feed"      // The activity feed (event log) only
+	NotificationChannelWebSocket = "websocket" // The drone that uploaded the document
+	NotificationChannelEmail     = "email"     // The organization's email recipients, via SMTP
+	NotificationChannelSlack     = "slack"     // The organization's Slack incoming webhook
+)
+
+// notificationSeverities are the levels a route can be configured for
+var notificationSeverities = []string{"info", "warning", "critical"}
+
+// DefaultNotificationRoutes applies to any severity an organization hasn't routed itself
+var DefaultNotificationRoutes = map[string][]string{
+	"info":     {NotificationChannelFeed},
+	"warning":  {NotificationChannelWebSocket},
+	"critical": {NotificationChannelWebSocket, NotificationChannelEmail, NotificationChannelSlack},
+}
+
+// FeedLogger records notifications in the activity feed
+type FeedLogger interface {
+	LogEvent(eventType, documentName, details string) error
+}
+
+// NotificationRouter delivers analyst notifications on the channels each organization routes
+// their severity to. It implements worker.OrgNotificationSender.
+type NotificationRouter struct {
+	store     *database.NotificationRoutingStore
+	websocket worker.NotificationSender
+	feed      FeedLogger
+	mailer    Mailer
+	client    *http.Client
+}
+
+// NewNotificationRouter creates a router that delivers the websocket channel through websocket
+func NewNotificationRouter(store *database.NotificationRoutingStore, websocket worker.NotificationSender) *NotificationRouter {
+	return &NotificationRouter{
+		store:     store,
+		websocket: websocket,
+		client:    httpclient.New(5 * time.Second),
+	}
+}
+
+// SetFeed sets where the feed channel records notifications
+func (nr *NotificationRouter) SetFeed(feed FeedLogger) {
+	nr.feed = feed
+}
+
+// SetMailer enables the email channel
+func (nr *NotificationRouter) SetMailer(mailer Mailer) {
+	nr.mailer = mailer
+}
+
+// SendNotification sends a notification to a client with no organization routing
+func (nr *NotificationRouter) SendNotification(clientID string, notificationType, message, level string) error {
+	return nr.websocket.SendNotification(clientID, notificationType, message, level)
+}
+
+// SendOrgNotification delivers a notification on every channel the organization routes its level to.
+// A failing channel doesn't stop the others; their errors are returned together.
+func (nr *NotificationRouter) SendOrgNotification(organizationID, clientID, notificationType, message, level string) error {
+	routing := nr.routing(organizationID)
+
+	var errs []error
+	for _, channel := range routeFor(routing.Routes, level) {
+		var err error
+		switch channel {
+		case NotificationChannelFeed:
+			log.Printf("[NOTIFY] %s %s for org %s: %s", level, notificationType, organizationID, message)
+			if nr.feed != nil {
+				err = nr.feed.LogEvent(notificationType, "", fmt.Sprintf("[%s] %s", level, message))
+			}
+		case NotificationChannelWebSocket:
+			if clientID != "" && nr.websocket != nil {
+				err = nr.websocket.SendNotification(clientID, notificationType, message, level)
+			}
+		case NotificationChannelEmail:
+			if nr.mailer != nil && len(routing.EmailRecipients) > 0 {
+				subject := fmt.Sprintf("[Hive %s] %s", strings.ToUpper(level), notificationType)
+				err = nr.mailer.SendMail(routing.EmailRecipients, subject, message)
+			}
+		case NotificationChannelSlack:
+			if routing.SlackWebhookURL != "" {
+				err = nr.postSlack(routing.SlackWebhookURL, fmt.Sprintf("*%s* %s: %s", strings.ToUpper(level), notificationType, message))
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", channel, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// routing returns the organization's routing, or the defaults if none is set or it can't be read
+func (nr *NotificationRouter) routing(organizationID string) *database.NotificationRouting {
+	if nr.store != nil {
+		routing, err := nr.store.GetRouting(organizationID)
+		if err != nil {
+			log.Printf("Failed to get notification routing for org %s, using defaults: %v", organizationID, err)
+		} else if routing != nil {
+			return routing
+		}
+	}
+	return &database.NotificationRouting{OrganizationID: organizationID}
+}
+
+// routeFor returns the channels for a level, falling back to the default route and then to the feed
+func routeFor(routes map[string][]string, level string) []string {
+	if channels, ok := routes[level]; ok {
+		return channels
+	}
+	if channels, ok := DefaultNotificationRoutes[level]; ok {
+		return channels
+	}
+	return []string{NotificationChannelFeed}
+}
+
+// postSlack posts a message to a Slack incoming webhook
+func (nr *NotificationRouter) postSlack(webhookURL, text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := nr.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ValidateRouting checks that every severity and channel is known and that each routed channel is
+// configured: email needs SMTP and at least one valid recipient, slack needs an https webhook URL.
+func (nr *NotificationRouter) ValidateRouting(routing database.NotificationRouting) error {
+	usesEmail, usesSlack := false, false
+	for severity, channels := range routing.Routes {
+		if _, ok := DefaultNotificationRoutes[severity]; !ok {
+			return fmt.Errorf("unknown severity %q (expected one of %s)", severity, strings.Join(notificationSeverities, ", "))
+		}
+		seen := make(map[string]bool)
+		for _, channel := range channels {
+			switch channel {
+			case NotificationChannelFeed, NotificationChannelWebSocket:
+			case NotificationChannelEmail:
+				usesEmail = true
+			case NotificationChannelSlack:
+				usesSlack = true
+			default:
+				return fmt.Errorf("unknown channel %q for severity %q", channel, severity)
+			}
+			if seen[channel] {
+				return fmt.Errorf("channel %q is listed twice for severity %q", channel, severity)
+			}
+			seen[channel] = true
+		}
+	}
+
+	if usesEmail {
+		if nr.mailer == nil {
+			return fmt.Errorf("email channel requires SMTP to be configured (SMTP_HOST and SMTP_FROM)")
+		}
+		if len(routing.EmailRecipients) == 0 {
+			return fmt.Errorf("email channel requires at least one recipient")
+		}
+	}
+	for _, recipient := range routing.EmailRecipients {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return fmt.Errorf("invalid email recipient %q", recipient)
+		}
+	}
+
+	if usesSlack && routing.SlackWebhookURL == "" {
+		return fmt.Errorf("slack channel requires a slack_webhook_url")
+	}
+	if routing.SlackWebhookURL != "" {
+		parsed, err := url.Parse(routing.SlackWebhookURL)
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			return fmt.Errorf("slack_webhook_url must be an https URL")
+		}
+	}
+	return nil
+}
+
+// effectiveRouting fills in the default route for every severity the organization hasn't routed
+func effectiveRouting(routing *database.NotificationRouting) *database.NotificationRouting {
+	effective := *routing
+	effective.Routes = make(map[string][]string, len(notificationSeverities))
+	for _, severity := range notificationSeverities {
+		effective.Routes[severity] = routeFor(routing.Routes, severity)
+	}
+	if effective.EmailRecipients == nil {
+		effective.EmailRecipients = []string{}
+	}
+	return &effective
+}
+
+// HandleNotificationRouting handles GET/PUT /api/v1/settings/notification-routing
+func (nr *NotificationRouter) HandleNotificationRouting(w http.ResponseWriter, r *http.Request, auditLogStore *database.AuditLogStore) {
+	_, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+	if nr.store == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "notification routing not available")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(effectiveRouting(nr.routing(orgID)))
+	case http.MethodPut:
+		var routing database.NotificationRouting
+		if err := json.NewDecoder(r.Body).Decode(&routing); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		routing.OrganizationID = orgID
+		if err := nr.ValidateRouting(routing); err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		previous, err := nr.store.GetRouting(orgID)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if previous == nil {
+			previous = &database.NotificationRouting{OrganizationID: orgID}
+		}
+		if err := nr.store.SetRouting(routing); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		saved, err := nr.store.GetRouting(orgID)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		auditConfigChanges(auditLogStore, r, orgID,
+			configChange{Setting: "notifications.routes", Before: effectiveRouting(previous).Routes, After: effectiveRouting(saved).Routes},
+			configChange{Setting: "notifications.email_recipients", Before: previous.EmailRecipients, After: saved.EmailRecipients},
+			configChange{Setting: "notifications.slack_webhook_url", Before: previous.SlackWebhookURL, After: saved.SlackWebhookURL, Secret: true})
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(effectiveRouting(saved))
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
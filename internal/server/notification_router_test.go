@@ -0,0 +1,161 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+)
+
+// channelRecorder records deliveries on every notification channel
+type channelRecorder struct {
+	mu        sync.Mutex
+	websocket []string
+	email     []string
+	feed      []string
+	slack     int
+}
+
+func (c *channelRecorder) SendNotification(clientID string, notificationType, message, level string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.websocket = append(c.websocket, clientID+": "+message)
+	return nil
+}
+
+func (c *channelRecorder) SendMail(to []string, subject, body string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.email = append(c.email, strings.Join(to, ",")+": "+subject)
+	return nil
+}
+
+func (c *channelRecorder) LogEvent(eventType, documentName, details string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.feed = append(c.feed, details)
+	return nil
+}
+
+func newTestNotificationRouter(t *testing.T) (*NotificationRouter, *database.NotificationRoutingStore, *channelRecorder) {
+	t.Helper()
+	store, err := database.NewNotificationRoutingStore(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Failed to create notification routing store: %v", err)
+	}
+	recorder := &channelRecorder{}
+	router := NewNotificationRouter(store, recorder)
+	router.SetFeed(recorder)
+	router.SetMailer(recorder)
+	return router, store, recorder
+}
+
+func TestSendOrgNotification_RoutesBySeverity(t *testing.T) {
+	router, store, recorder := newTestNotificationRouter(t)
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder.mu.Lock()
+		recorder.slack++
+		recorder.mu.Unlock()
+	}))
+	defer slack.Close()
+
+	// Stored directly: validation would reject the test server's plain http URL
+	if err := store.SetRouting(database.NotificationRouting{
+		OrganizationID:  "org-a",
+		EmailRecipients: []string{"security@acme.test"},
+		SlackWebhookURL: slack.URL,
+	}); err != nil {
+		t.Fatalf("Failed to set routing: %v", err)
+	}
+
+	if err := router.SendOrgNotification("org-a", "drone-1", "ALERT", "Sensitive document detected: plan.pdf", "critical"); err != nil {
+		t.Fatalf("SendOrgNotification returned error: %v", err)
+	}
+	if len(recorder.websocket) != 1 || len(recorder.email) != 1 || recorder.slack != 1 {
+		t.Fatalf("Expected a critical match on websocket, email and slack, got websocket=%v email=%v slack=%d", recorder.websocket, recorder.email, recorder.slack)
+	}
+	if !strings.Contains(recorder.email[0], "security@acme.test") || !strings.Contains(recorder.email[0], "CRITICAL") {
+		t.Errorf("Expected a critical email to the org's recipients, got %q", recorder.email[0])
+	}
+	if len(recorder.feed) != 0 {
+		t.Errorf("Expected a critical match not to be routed to the feed by default, got %v", recorder.feed)
+	}
+
+	if err := router.SendOrgNotification("org-a", "drone-1", "ALERT", "Document indexed: notes.txt", "info"); err != nil {
+		t.Fatalf("SendOrgNotification returned error: %v", err)
+	}
+	if len(recorder.feed) != 1 {
+		t.Fatalf("Expected an info match in the feed, got %v", recorder.feed)
+	}
+	if len(recorder.websocket) != 1 || len(recorder.email) != 1 || recorder.slack != 1 {
+		t.Errorf("Expected an info match to only be logged, got websocket=%v email=%v slack=%d", recorder.websocket, recorder.email, recorder.slack)
+	}
+}
+
+func TestValidateRouting_RequiresConfiguredChannels(t *testing.T) {
+	store, err := database.NewNotificationRoutingStore(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Failed to create notification routing store: %v", err)
+	}
+	router := NewNotificationRouter(store, &channelRecorder{})
+	emailRoute := database.NotificationRouting{
+		Routes:          map[string][]string{"critical": {"email"}},
+		EmailRecipients: []string{"security@acme.test"},
+	}
+
+	if err := router.ValidateRouting(emailRoute); err == nil || !strings.Contains(err.Error(), "SMTP") {
+		t.Errorf("Expected the email channel to require SMTP, got %v", err)
+	}
+	router.SetMailer(&channelRecorder{})
+	if err := router.ValidateRouting(emailRoute); err != nil {
+		t.Errorf("Expected a configured email route to be valid, got %v", err)
+	}
+
+	invalid := []database.NotificationRouting{
+		{Routes: map[string][]string{"critical": {"email"}}},
+		{Routes: map[string][]string{"critical": {"pager"}}},
+		{Routes: map[string][]string{"urgent": {"feed"}}},
+		{Routes: map[string][]string{"critical": {"slack"}}},
+		{Routes: map[string][]string{"critical": {"slack"}}, SlackWebhookURL: "http://hooks.slack.test/x"},
+	}
+	for _, routing := range invalid {
+		if err := router.ValidateRouting(routing); err == nil {
+			t.Errorf("Expected %+v to be rejected", routing)
+		}
+	}
+}
+
+func TestHandleNotificationRouting_RejectsInvalidRouting(t *testing.T) {
+	router, store, _ := newTestNotificationRouter(t)
+	user := &database.User{ID: "u1", Email: "admin@acme.test"}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/settings/notification-routing", strings.NewReader(`{"routes": {"warning": ["slack"]}}`))
+	rec := httptest.NewRecorder()
+	router.HandleNotificationRouting(rec, withUser(req, user, "org-a"), nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 for a slack route without a webhook URL, got %d", rec.Code)
+	}
+
+	body := `{"routes": {"warning": ["websocket", "slack"]}, "slack_webhook_url": "https://hooks.slack.test/T000/B000"}`
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/settings/notification-routing", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	router.HandleNotificationRouting(rec, withUser(req, user, "org-a"), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	saved, err := store.GetRouting("org-a")
+	if err != nil || saved == nil {
+		t.Fatalf("Expected the routing to be saved, got %v, %v", saved, err)
+	}
+	if got := routeFor(saved.Routes, "warning"); len(got) != 2 {
+		t.Errorf("Expected the warning route to be saved, got %v", got)
+	}
+	if got := routeFor(saved.Routes, "info"); len(got) != 1 || got[0] != NotificationChannelFeed {
+		t.Errorf("Expected unrouted severities to keep their default, got %v", got)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+
+	"github.com/the-hive/internal/database"
+)
+
+// Default branding used when an organization has none configured
+const (
+	DefaultBrandName    = "The Hive"
+	DefaultPrimaryColor = "#f5a623"
+	DefaultAccentColor  = "#1f2933"
+)
+
+var brandColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// PageBranding is the organization's branding as injected into rendered pages
+type PageBranding struct {
+	Name         string `json:"name"`
+	LogoURL      string `json:"logo_url"`
+	PrimaryColor string `json:"primary_color"`
+	AccentColor  string `json:"accent_color"`
+}
+
+// PageData is the data every page template is rendered with
+type PageData struct {
+	Branding PageBranding
+}
+
+// DefaultPageBranding returns the built-in branding (no logo; pages show the name)
+func DefaultPageBranding() PageBranding {
+	return PageBranding{
+		Name:         DefaultBrandName,
+		PrimaryColor: DefaultPrimaryColor,
+		AccentColor:  DefaultAccentColor,
+	}
+}
+
+// BrandingMetadataKey is the system metadata key holding an organization's branding JSON
+func BrandingMetadataKey(organizationID string) string {
+	return "branding:" + organizationID
+}
+
+// ResolvePageBranding returns the branding for the request's organization (set by the login or
+// tenant-from-domain middleware). Missing or invalid fields fall back to the defaults, and an
+// organization without a brand name uses its own name.
+func ResolvePageBranding(r *http.Request, metadataStore *database.SystemMetadataStore, orgStore *database.OrganizationStore) PageBranding {
+	branding := DefaultPageBranding()
+
+	orgID, _ := r.Context().Value("organization_id").(string)
+	if orgID == "" {
+		return branding
+	}
+
+	var custom PageBranding
+	if metadataStore != nil {
+		if raw, err := metadataStore.Get(BrandingMetadataKey(orgID)); err == nil && raw != "" {
+			if err := json.Unmarshal([]byte(raw), &custom); err != nil {
+				log.Printf("Ignoring invalid branding for org %s: %v", orgID, err)
+			}
+		}
+	}
+	if custom.Name == "" && orgStore != nil {
+		if org, err := orgStore.GetOrganizationByID(orgID); err == nil && org != nil {
+			custom.Name = org.Name
+		}
+	}
+
+	if custom.Name != "" {
+		branding.Name = custom.Name
+	}
+	if custom.LogoURL != "" {
+		branding.LogoURL = custom.LogoURL
+	}
+	if brandColorPattern.MatchString(custom.PrimaryColor) {
+		branding.PrimaryColor = custom.PrimaryColor
+	}
+	if brandColorPattern.MatchString(custom.AccentColor) {
+		branding.AccentColor = custom.AccentColor
+	}
+	return branding
+}
+
+// newPageData builds the template data for a page request
+func newPageData(r *http.Request, metadataStore *database.SystemMetadataStore, orgStore *database.OrganizationStore) PageData {
+	return PageData{Branding: ResolvePageBranding(r, metadataStore, orgStore)}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+)
+
+func TestHandleWeb_RendersOrganizationBranding(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "base.html", `<title>{{.Branding.Name}}</title>{{if .Branding.LogoURL}}<img src="{{.Branding.LogoURL}}">{{end}}<style>:root{--primary:{{.Branding.PrimaryColor}}}</style>{{template "content" .}}`)
+	writeTemplate(t, dir, "index.html", `{{define "content"}}search{{end}}`)
+
+	previous := defaultTemplates
+	defaultTemplates = NewTemplateSet(os.DirFS(dir))
+	t.Cleanup(func() { defaultTemplates = previous })
+
+	metadataStore, err := database.NewSystemMetadataStore(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Failed to create metadata store: %v", err)
+	}
+	metadataStore.Set(BrandingMetadataKey("org-acme"), `{"name":"Acme Knowledge","logo_url":"/static/acme.png","primary_color":"#123abc","accent_color":"red;}"}`)
+
+	render := func(orgID string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = withUser(req, &database.User{ID: "u1"}, orgID)
+		rec := httptest.NewRecorder()
+		HandleWeb(rec, req, metadataStore, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		return rec.Body.String()
+	}
+
+	body := render("org-acme")
+	for _, want := range []string{"<title>Acme Knowledge</title>", `<img src="/static/acme.png">`, "--primary:#123abc"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in branded page, got %q", want, body)
+		}
+	}
+	if branding := ResolvePageBranding(withUser(httptest.NewRequest(http.MethodGet, "/", nil), &database.User{ID: "u1"}, "org-acme"), metadataStore, nil); branding.AccentColor != DefaultAccentColor {
+		t.Errorf("Expected an invalid color to fall back to the default, got %q", branding.AccentColor)
+	}
+
+	body = render("org-other")
+	if !strings.Contains(body, "<title>"+DefaultBrandName+"</title>") || strings.Contains(body, "<img") {
+		t.Errorf("Expected default branding for an unbranded org, got %q", body)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// DefaultPageLimit is used when a list request has no (or an invalid) limit
+	DefaultPageLimit = 50
+	// MaxPageLimit caps the limit a list request may ask for
+	MaxPageLimit = 500
+)
+
+// Page is the envelope every list endpoint returns, so the UI can paginate any list the same way
+type Page struct {
+	Items  interface{} `json:"items"`
+	Total  int         `json:"total"` // Total items across all pages
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
+
+// parsePagination reads ?limit= and ?offset=, falling back to the defaults for missing or invalid values
+func parsePagination(r *http.Request) (limit, offset int) {
+	limit = DefaultPageLimit
+	if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n > 0 {
+		limit = n
+	}
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+	if n, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && n > 0 {
+		offset = n
+	}
+	return limit, offset
+}
+
+// pageSlice returns the items in [offset, offset+limit) for lists that are already in memory
+func pageSlice[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end]
+}
+
+// writePage writes a paginated list response; a nil slice is sent as an empty list
+func writePage[T any](w http.ResponseWriter, items []T, total, limit, offset int) {
+	if items == nil {
+		items = []T{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(Page{
+		Items:  items,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
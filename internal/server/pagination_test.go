@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/rules"
+)
+
+// decodePage decodes a list response, failing on any key outside the envelope
+func decodePage(t *testing.T, rec *httptest.ResponseRecorder) (items []map[string]interface{}, total, limit, offset int) {
+	t.Helper()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	dec := json.NewDecoder(rec.Body)
+	dec.DisallowUnknownFields()
+	var page struct {
+		Items  []map[string]interface{} `json:"items"`
+		Total  int                      `json:"total"`
+		Limit  int                      `json:"limit"`
+		Offset int                      `json:"offset"`
+	}
+	if err := dec.Decode(&page); err != nil {
+		t.Fatalf("Response is not a page envelope: %v", err)
+	}
+	if page.Items == nil {
+		t.Fatal("Expected items to be a list, got null")
+	}
+	return page.Items, page.Total, page.Limit, page.Offset
+}
+
+func TestListEndpoints_PaginatedEnvelope(t *testing.T) {
+	db := newTestDB(t)
+
+	auditLogStore, err := database.NewAuditLogStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create audit log store: %v", err)
+	}
+	for i := 0; i < 7; i++ {
+		auditLogStore.LogAction("10.0.0.1", database.AuditActionSearch, fmt.Sprintf("search %d", i), "org-a")
+	}
+	auditLogStore.LogAction("10.0.0.1", database.AuditActionIngest, "other org", "org-b")
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/v1/audit?limit=3&offset=6", nil), &database.User{ID: "admin"}, "org-a")
+	rec := httptest.NewRecorder()
+	HandleAuditLogs(rec, req, auditLogStore)
+	items, total, limit, offset := decodePage(t, rec)
+	if total != 7 || limit != 3 || offset != 6 || len(items) != 1 {
+		t.Errorf("Expected the last 1 of 7 audit logs (limit 3, offset 6), got %d items, total %d, limit %d, offset %d", len(items), total, limit, offset)
+	}
+
+	ruleStore, err := rules.NewStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create rule store: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		ruleStore.AddRule(context.Background(), fmt.Sprintf("Does the document mention item %d?", i), true, "", "org-a")
+	}
+	ruleStore.AddRule(context.Background(), "Another organization's rule", true, "", "org-b")
+
+	req = withUser(httptest.NewRequest(http.MethodGet, "/api/v1/rules?limit=2", nil), &database.User{ID: "admin"}, "org-a")
+	rec = httptest.NewRecorder()
+	HandleGetRules(rec, req, ruleStore)
+	items, total, limit, offset = decodePage(t, rec)
+	if total != 5 || limit != 2 || offset != 0 || len(items) != 2 {
+		t.Errorf("Expected the first 2 of 5 rules, got %d items, total %d, limit %d, offset %d", len(items), total, limit, offset)
+	}
+
+	// Out-of-range pages are empty lists, not null
+	req = withUser(httptest.NewRequest(http.MethodGet, "/api/v1/rule-matches?offset=100", nil), &database.User{ID: "admin"}, "org-a")
+	rec = httptest.NewRecorder()
+	HandleGetRuleMatches(rec, req, nil)
+	if items, total, limit, _ := decodePage(t, rec); len(items) != 0 || total != 0 || limit != DefaultPageLimit {
+		t.Errorf("Expected an empty page with the default limit, got %d items, total %d, limit %d", len(items), total, limit)
+	}
+}
+
+func TestPageSlice(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	if got := pageSlice(items, 2, 3); len(got) != 2 || got[0] != 4 {
+		t.Errorf("Expected [4 5], got %v", got)
+	}
+	if got := pageSlice(items, 10, 5); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty slice past the end, got %v", got)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/pii"
+)
+
+// PIIPolicyLookup resolves an organization's PII mode
+type PIIPolicyLookup interface {
+	GetPIIMode(organizationID string) (string, error)
+}
+
+// PIIEventStore records PII findings as rule-events
+type PIIEventStore interface {
+	AddEvent(ctx context.Context, event interface{}) error
+}
+
+// PIIGuard scans ingested content for PII and alerts or masks according to the organization's policy
+type PIIGuard struct {
+	scanner     *pii.Scanner
+	policies    PIIPolicyLookup
+	defaultMode pii.Mode
+	eventStore  PIIEventStore
+	wsManager   *WebSocketManager
+}
+
+// NewPIIGuard creates a PII guard. defaultMode applies to organizations without a stored policy.
+func NewPIIGuard(scanner *pii.Scanner, policies PIIPolicyLookup, defaultMode pii.Mode, eventStore PIIEventStore, wsManager *WebSocketManager) *PIIGuard {
+	return &PIIGuard{
+		scanner:     scanner,
+		policies:    policies,
+		defaultMode: defaultMode,
+		eventStore:  eventStore,
+		wsManager:   wsManager,
+	}
+}
+
+// ModeFor returns the PII mode for the organization
+func (g *PIIGuard) ModeFor(organizationID string) pii.Mode {
+	if g.policies != nil && organizationID != "" {
+		modeStr, err := g.policies.GetPIIMode(organizationID)
+		if err != nil {
+			log.Printf("Failed to load PII policy for org %s: %v", organizationID, err)
+		} else if modeStr != "" {
+			if mode, err := pii.ParseMode(modeStr); err == nil {
+				return mode
+			}
+		}
+	}
+	return g.defaultMode
+}
+
+// Masked returns the content as Apply would store it, without recording findings or notifying anyone
+func (g *PIIGuard) Masked(content, organizationID string) string {
+	if g.ModeFor(organizationID) != pii.ModeMask {
+		return content
+	}
+	masked, _ := g.scanner.Mask(content)
+	return masked
+}
+
+// Apply scans content and returns the content to store (masked in mask mode)
+// Findings are recorded as rule-events; in alert mode the client is also notified
+func (g *PIIGuard) Apply(ctx context.Context, content, organizationID, clientID, document string) string {
+	mode := g.ModeFor(organizationID)
+	if mode == pii.ModeOff {
+		return content
+	}
+
+	var findings []pii.Finding
+	stored := content
+	if mode == pii.ModeMask {
+		stored, findings = g.scanner.Mask(content)
+	} else {
+		findings = g.scanner.Scan(content)
+	}
+	if len(findings) == 0 {
+		return content
+	}
+
+	summary := pii.Summary(findings)
+	status := "detected"
+	if mode == pii.ModeMask {
+		status = "masked"
+	}
+	log.Printf("PII %s in %s (org %s): %s", status, document, organizationID, summary)
+
+	if g.eventStore != nil {
+		eventCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		for piiType, count := range pii.CountByType(findings) {
+			err := g.eventStore.AddEvent(eventCtx, map[string]interface{}{
+				"RuleID":         0,
+				"RuleQuery":      "pii:" + string(piiType),
+				"Document":       document,
+				"EventType":      "pii",
+				"Status":         status,
+				"Message":        fmt.Sprintf("%d %s value(s) %s", count, piiType, status),
+				"ClientID":       clientID,
+				"OrganizationID": organizationID,
+			})
+			if err != nil {
+				log.Printf("Failed to record PII event: %v", err)
+			}
+		}
+	}
+
+	if mode == pii.ModeAlert && g.wsManager != nil && clientID != "" {
+		notification := NotificationMessage{
+			Type:    "ALERT",
+			Message: fmt.Sprintf("PII detected in %s: %s", document, summary),
+			Level:   "warning",
+		}
+		if err := g.wsManager.SendNotification(clientID, notification); err != nil {
+			log.Printf("Failed to send PII notification to client %s: %v", clientID, err)
+		}
+	}
+
+	return stored
+}
+
+// HandlePIIPolicy handles GET and PUT on /api/v1/settings/pii for the caller's organization
+func HandlePIIPolicy(w http.ResponseWriter, r *http.Request, store *database.PIIPolicyStore, guard *PIIGuard, auditLogStore *database.AuditLogStore) {
+	_, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"mode": string(guard.ModeFor(orgID))})
+	case http.MethodPut:
+		var req struct {
+			Mode string `json:"mode"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		mode, err := pii.ParseMode(req.Mode)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		previous := guard.ModeFor(orgID)
+		if err := store.SetPIIMode(orgID, string(mode)); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		auditConfigChanges(auditLogStore, r, orgID, configChange{Setting: "pii_mode", Before: string(previous), After: string(mode)})
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"mode": string(mode)})
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
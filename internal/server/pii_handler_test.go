@@ -0,0 +1,98 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/pii"
+)
+
+// recordingEventStore records rule-events
+type recordingEventStore struct {
+	mu     sync.Mutex
+	events []map[string]interface{}
+}
+
+func (s *recordingEventStore) AddEvent(ctx context.Context, event interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event.(map[string]interface{}))
+	return nil
+}
+
+const piiTestContent = "Reach me at jane@example.com or 415-555-0134. SSN 123-45-6789."
+
+func TestPIIGuard_MaskMode(t *testing.T) {
+	policies, err := database.NewPIIPolicyStore(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Failed to create policy store: %v", err)
+	}
+	policies.SetPIIMode("org-a", "mask")
+
+	events := &recordingEventStore{}
+	wm := NewWebSocketManager(nil)
+	defer wm.Stop()
+	guard := NewPIIGuard(pii.NewScanner(pii.DefaultPatterns()), policies, pii.ModeOff, events, wm)
+
+	stored := guard.Apply(context.Background(), piiTestContent, "org-a", "drone-1", "contacts.txt")
+
+	if strings.Contains(stored, "jane@example.com") || strings.Contains(stored, "123-45-6789") || strings.Contains(stored, "415-555-0134") {
+		t.Errorf("Expected PII to be masked, got %q", stored)
+	}
+	if !strings.Contains(stored, "[REDACTED:EMAIL]") {
+		t.Errorf("Expected placeholder in masked content, got %q", stored)
+	}
+	if len(events.events) != 3 {
+		t.Fatalf("Expected one rule-event per PII type, got %d", len(events.events))
+	}
+	for _, event := range events.events {
+		if event["Status"] != "masked" || event["OrganizationID"] != "org-a" {
+			t.Errorf("Unexpected event: %v", event)
+		}
+		if strings.Contains(event["Message"].(string), "jane@example.com") {
+			t.Errorf("Event must not contain the PII value: %v", event)
+		}
+	}
+	if stats := wm.NotificationStats(); stats.Missed != 0 {
+		t.Errorf("Mask mode should not notify, got %+v", stats)
+	}
+}
+
+func TestPIIGuard_AlertMode(t *testing.T) {
+	events := &recordingEventStore{}
+	wm := NewWebSocketManager(nil)
+	defer wm.Stop()
+	guard := NewPIIGuard(pii.NewScanner(pii.DefaultPatterns()), nil, pii.ModeAlert, events, wm)
+
+	stored := guard.Apply(context.Background(), piiTestContent, "org-a", "drone-1", "contacts.txt")
+
+	if stored != piiTestContent {
+		t.Errorf("Alert mode must not modify content, got %q", stored)
+	}
+	if len(events.events) != 3 || events.events[0]["Status"] != "detected" {
+		t.Errorf("Expected detected rule-events, got %v", events.events)
+	}
+
+	// The drone is offline, so the alert lands in its mailbox
+	messages := wm.takeFallback("drone-1")
+	if len(messages) != 1 || !strings.Contains(string(messages[0]), "PII detected in contacts.txt") {
+		t.Errorf("Expected a PII alert notification, got %q", messages)
+	}
+}
+
+func TestPIIGuard_OffMode(t *testing.T) {
+	events := &recordingEventStore{}
+	guard := NewPIIGuard(pii.NewScanner(pii.DefaultPatterns()), nil, pii.ModeOff, events, nil)
+
+	if stored := guard.Apply(context.Background(), piiTestContent, "org-a", "", "contacts.txt"); stored != piiTestContent {
+		t.Errorf("Off mode must not modify content, got %q", stored)
+	}
+	if len(events.events) != 0 {
+		t.Errorf("Off mode must not record events, got %v", events.events)
+	}
+}
@@ -3,10 +3,13 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/the-hive/internal/database"
 	"github.com/the-hive/internal/vectordb"
@@ -17,8 +20,13 @@ type PurgeHandler struct {
 	vectorDB      vectordb.VectorDB
 	db            *sql.DB
 	auditLogStore *database.AuditLogStore
+	orgStore      *database.OrganizationStore // Looks up the organization name a purge must be confirmed with
+	answerCache   *ChatAnswerCache
 }
 
+// purgeAllConfirmation must be sent as confirm to purge every organization
+const purgeAllConfirmation = "all organizations"
+
 // NewPurgeHandler creates a new purge handler
 func NewPurgeHandler(vectorDB vectordb.VectorDB, db *sql.DB, auditLogStore *database.AuditLogStore) *PurgeHandler {
 	return &PurgeHandler{
@@ -28,88 +36,306 @@ func NewPurgeHandler(vectorDB vectordb.VectorDB, db *sql.DB, auditLogStore *data
 	}
 }
 
+// SetOrganizationStore sets the store used to look up the organization name that confirms a purge
+func (h *PurgeHandler) SetOrganizationStore(orgStore *database.OrganizationStore) {
+	h.orgStore = orgStore
+}
+
+// SetAnswerCache sets the chat answer cache invalidated by a purge
+func (h *PurgeHandler) SetAnswerCache(cache *ChatAnswerCache) {
+	h.answerCache = cache
+}
+
 // PurgeRequest represents a purge request
 type PurgeRequest struct {
-	OrganizationID string `json:"organization_id,omitempty"` // If empty, purges all
+	OrganizationID string `json:"organization_id,omitempty"` // Defaults to the caller's organization; other organizations require super admin
+	All            bool   `json:"all,omitempty"`             // Purge every organization's data (super admin only)
+	Confirm        string `json:"confirm,omitempty"`         // Must equal the preview's confirmation text to execute
+}
+
+// PurgePreview reports what a purge would remove, without removing anything
+type PurgePreview struct {
+	OrganizationID   string `json:"organization_id,omitempty"` // Empty for a global purge
+	OrganizationName string `json:"organization_name,omitempty"`
+	VectorsToDelete  int    `json:"vectors_to_delete"` // -1 when the vector database can't count an organization's points
+	ChunksToDelete   int64  `json:"chunks_to_delete"`
+	PurgeCounts
+	Confirmation string `json:"confirmation"` // Send this as confirm to execute the purge
 }
 
+// PurgeResponse reports what a purge removed
+type PurgeResponse struct {
+	Success        bool   `json:"success"`
+	OrganizationID string `json:"organization_id,omitempty"` // Empty for a global purge
+	VectorsDeleted int    `json:"vectors_deleted"`
+	ChunksDeleted  int64  `json:"chunks_deleted"`
+	PurgeCounts
+}
+
+// PurgeCounts reports the document records a purge removes alongside the chunks
+type PurgeCounts struct {
+	Documents  int64 `json:"documents"`
+	Summaries  int64 `json:"summaries"`
+	GraphEdges int64 `json:"graph_edges"`
+}
+
+// purgeDocumentIDs selects the IDs of an organization's documents. The documents and graph_edges
+// tables carry no organization_id, so they are matched through the organization's chunks and summaries.
+const purgeDocumentIDs = `SELECT document_id FROM chunks WHERE organization_id = ?
+	UNION SELECT document_id FROM document_summaries WHERE organization_id = ?`
+
 // HandlePurge handles POST /api/v1/purge
+// Organization admins purge only their own organization; purging another organization
+// or the whole collection requires super admin. The request must carry confirm matching
+// the organization name (or "all organizations"), as returned by the preview.
 func (h *PurgeHandler) HandlePurge(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusMethodNotAllowed)
-		json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+	dbUser, req, orgID, ok := h.resolvePurgeScope(w, r)
+	if !ok {
 		return
 	}
 
-	var req PurgeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "invalid JSON"})
+	confirmation, err := h.confirmationFor(orgID)
+	if err != nil {
+		log.Printf("Failed to look up organization %s for purge: %v", orgID, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to look up organization")
 		return
 	}
-
-	// Get organization ID from context or request
-	orgID := req.OrganizationID
-	if orgID == "" {
-		if orgIDVal := r.Context().Value("organization_id"); orgIDVal != nil {
-			if orgIDStr, ok := orgIDVal.(string); ok {
-				orgID = orgIDStr
-			}
-		}
+	if strings.TrimSpace(req.Confirm) != confirmation {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("confirm must be %q to purge; preview the purge with /api/v1/purge/preview", confirmation))
+		return
 	}
 
+	resp := PurgeResponse{Success: true, OrganizationID: orgID}
+	ctx := r.Context()
+
 	// Purge vectors from Qdrant
 	if h.vectorDB != nil {
-		ctx := r.Context()
 		if orgID != "" {
-			// Purge by organization
-			if _, err := h.vectorDB.PurgeByOrganization(ctx, orgID); err != nil {
+			deleted, err := h.vectorDB.PurgeByOrganization(ctx, orgID)
+			if err != nil {
 				log.Printf("Failed to purge vectors for org %s: %v", orgID, err)
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusInternalServerError)
-				json.NewEncoder(w).Encode(map[string]string{"error": "failed to purge vectors"})
+				writeJSONError(w, http.StatusInternalServerError, "failed to purge vectors")
 				return
 			}
+			resp.VectorsDeleted = deleted
+		} else if err := h.vectorDB.PurgeCollection(ctx); err != nil {
+			log.Printf("Failed to purge all vectors: %v", err)
+			writeJSONError(w, http.StatusInternalServerError, "failed to purge vectors")
+			return
+		}
+	}
+
+	// Cached chat answers may cite the purged documents
+	if h.answerCache != nil {
+		if orgID != "" {
+			h.answerCache.Invalidate(orgID)
 		} else {
-			// Purge all
-			if err := h.vectorDB.PurgeCollection(ctx); err != nil {
-				log.Printf("Failed to purge all vectors: %v", err)
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusInternalServerError)
-				json.NewEncoder(w).Encode(map[string]string{"error": "failed to purge vectors"})
-				return
-			}
+			h.answerCache.InvalidateAll()
 		}
 	}
 
 	// Purge database records
 	if h.db != nil {
+		counts, chunks, err := h.purgeRecords(ctx, orgID)
+		if err != nil {
+			log.Printf("Failed to purge database records (org %q): %v", orgID, err)
+			writeJSONError(w, http.StatusInternalServerError, "failed to purge database")
+			return
+		}
+		resp.PurgeCounts, resp.ChunksDeleted = counts, chunks
+	}
+
+	if h.auditLogStore != nil {
+		scope := "all organizations"
 		if orgID != "" {
-			// Purge chunks for organization
-			_, err := h.db.Exec("DELETE FROM chunks WHERE organization_id = ?", orgID)
-			if err != nil {
-				log.Printf("Failed to purge chunks for org %s: %v", orgID, err)
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusInternalServerError)
-				json.NewEncoder(w).Encode(map[string]string{"error": "failed to purge database"})
-				return
-			}
+			scope = "organization " + orgID
+		}
+		details := fmt.Sprintf("Purge of %s by %s (%d vectors, %d chunks, %d documents, %d summaries, %d graph edges)",
+			scope, dbUser.Email, resp.VectorsDeleted, resp.ChunksDeleted, resp.Documents, resp.Summaries, resp.GraphEdges)
+		if err := h.auditLogStore.LogAction(getClientIP(r), database.AuditActionPurge, details, orgID); err != nil {
+			log.Printf("Failed to log purge action: %v", err)
+		}
+	}
+
+	log.Printf("[PURGE] %s purged org %q (%d vectors, %d chunks, %d documents, %d summaries, %d graph edges)",
+		dbUser.Email, orgID, resp.VectorsDeleted, resp.ChunksDeleted, resp.Documents, resp.Summaries, resp.GraphEdges)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandlePurgePreview handles POST /api/v1/purge/preview: it counts the vectors, chunks and documents the same
+// purge request would delete, and returns the confirmation text needed to execute it
+func (h *PurgeHandler) HandlePurgePreview(w http.ResponseWriter, r *http.Request) {
+	_, _, orgID, ok := h.resolvePurgeScope(w, r)
+	if !ok {
+		return
+	}
+
+	preview := PurgePreview{OrganizationID: orgID}
+	ctx := r.Context()
+	var err error
+	if preview.Confirmation, err = h.confirmationFor(orgID); err != nil {
+		log.Printf("Failed to look up organization %s for purge preview: %v", orgID, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to look up organization")
+		return
+	}
+	if orgID != "" {
+		preview.OrganizationName = preview.Confirmation
+	}
+
+	if h.vectorDB != nil {
+		if orgID == "" {
+			preview.VectorsToDelete, err = h.vectorDB.GetPointCount(ctx)
+		} else if counter, ok := h.vectorDB.(vectordb.OrganizationCounter); ok {
+			preview.VectorsToDelete, err = counter.CountByOrganization(ctx, orgID)
 		} else {
-			// Purge all chunks
-			_, err := h.db.Exec("DELETE FROM chunks")
-			if err != nil {
-				log.Printf("Failed to purge all chunks: %v", err)
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusInternalServerError)
-				json.NewEncoder(w).Encode(map[string]string{"error": "failed to purge database"})
-				return
-			}
+			preview.VectorsToDelete = -1
+		}
+		if err != nil {
+			log.Printf("Failed to count vectors for purge preview (org %q): %v", orgID, err)
+			writeJSONError(w, http.StatusInternalServerError, "failed to count vectors")
+			return
+		}
+	}
+
+	if h.db != nil {
+		if preview.PurgeCounts, preview.ChunksToDelete, err = h.countRecords(ctx, orgID); err != nil {
+			log.Printf("Failed to count database records for purge preview (org %q): %v", orgID, err)
+			writeJSONError(w, http.StatusInternalServerError, "failed to count chunks")
+			return
 		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+	json.NewEncoder(w).Encode(preview)
+}
+
+// purgeTarget is a table a purge deletes from, with the filter scoping it to one organization
+type purgeTarget struct {
+	from  string // Table plus WHERE clause, shared by the DELETE and its preview COUNT
+	args  []interface{}
+	count *int64 // Receives the rows deleted or counted
+}
+
+// purgeTargets lists what a purge removes, or every organization's records when orgID is empty,
+// recording each table's rows into counts and chunks. Edges and documents are matched through
+// chunks and summaries, so they are deleted first.
+func purgeTargets(orgID string, counts *PurgeCounts, chunks *int64) []purgeTarget {
+	targets := []purgeTarget{
+		{from: "graph_edges", count: &counts.GraphEdges},
+		{from: "documents", count: &counts.Documents},
+		{from: "document_summaries", count: &counts.Summaries},
+		{from: "chunks", count: chunks},
+	}
+	if orgID == "" {
+		return targets
+	}
+	targets[0].from += " WHERE source_doc_id IN (" + purgeDocumentIDs + ") OR target_doc_id IN (" + purgeDocumentIDs + ")"
+	targets[0].args = []interface{}{orgID, orgID, orgID, orgID}
+	targets[1].from += " WHERE id IN (" + purgeDocumentIDs + ")"
+	targets[1].args = []interface{}{orgID, orgID}
+	targets[2].from += " WHERE organization_id = ?"
+	targets[2].args = []interface{}{orgID}
+	targets[3].from += " WHERE organization_id = ?"
+	targets[3].args = []interface{}{orgID}
+	return targets
+}
+
+// purgeRecords deletes the organization's chunks, documents, summaries and graph edges in one transaction
+func (h *PurgeHandler) purgeRecords(ctx context.Context, orgID string) (PurgeCounts, int64, error) {
+	var counts PurgeCounts
+	var chunks int64
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return counts, 0, err
+	}
+	defer tx.Rollback()
+
+	for _, target := range purgeTargets(orgID, &counts, &chunks) {
+		result, err := tx.ExecContext(ctx, "DELETE FROM "+target.from, target.args...)
+		if err != nil {
+			return PurgeCounts{}, 0, err
+		}
+		*target.count, _ = result.RowsAffected()
+	}
+	if err := tx.Commit(); err != nil {
+		return PurgeCounts{}, 0, err
+	}
+	return counts, chunks, nil
+}
+
+// countRecords counts what purgeRecords would delete for the same organization
+func (h *PurgeHandler) countRecords(ctx context.Context, orgID string) (PurgeCounts, int64, error) {
+	var counts PurgeCounts
+	var chunks int64
+	for _, target := range purgeTargets(orgID, &counts, &chunks) {
+		if err := h.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+target.from, target.args...).Scan(target.count); err != nil {
+			return PurgeCounts{}, 0, err
+		}
+	}
+	return counts, chunks, nil
 }
 
+// resolvePurgeScope decodes a purge request and checks the caller may purge its target, writing an
+// error on failure. orgID is empty for a purge of every organization.
+func (h *PurgeHandler) resolvePurgeScope(w http.ResponseWriter, r *http.Request) (*database.User, PurgeRequest, string, bool) {
+	var req PurgeRequest
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return nil, req, "", false
+	}
+
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return nil, req, "", false
+	}
+
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+			return nil, req, "", false
+		}
+	}
+
+	isSuperAdmin := dbUser.Role == database.RoleSuperAdmin
+	if req.All {
+		if !isSuperAdmin {
+			writeJSONError(w, http.StatusForbidden, "only super admins can purge all organizations")
+			return nil, req, "", false
+		}
+		return dbUser, req, "", true
+	}
+
+	if req.OrganizationID != "" && req.OrganizationID != orgID {
+		if !isSuperAdmin {
+			writeJSONError(w, http.StatusForbidden, "cannot purge another organization")
+			return nil, req, "", false
+		}
+		orgID = req.OrganizationID
+	}
+	if orgID == "" {
+		writeJSONError(w, http.StatusBadRequest, "organization_id is required")
+		return nil, req, "", false
+	}
+	return dbUser, req, orgID, true
+}
+
+// confirmationFor returns the text a purge of the organization must be confirmed with: its name,
+// or its ID when it has no name on record
+func (h *PurgeHandler) confirmationFor(orgID string) (string, error) {
+	if orgID == "" {
+		return purgeAllConfirmation, nil
+	}
+	if h.orgStore == nil {
+		return orgID, nil
+	}
+	org, err := h.orgStore.GetOrganizationByID(orgID)
+	if err != nil {
+		return "", err
+	}
+	if org == nil || org.Name == "" {
+		return orgID, nil
+	}
+	return org.Name, nil
+}
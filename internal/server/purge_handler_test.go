@@ -0,0 +1,201 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/vectordb"
+)
+
+// purgeVectorDB keeps per-organization point counts and records purges
+type purgeVectorDB struct {
+	vectordb.MockVectorDB
+	points      map[string]int
+	purgedAll   bool
+	purgedByOrg []string
+}
+
+func (p *purgeVectorDB) PurgeCollection(ctx context.Context) error {
+	p.purgedAll = true
+	p.points = map[string]int{}
+	return nil
+}
+
+func (p *purgeVectorDB) CountByOrganization(ctx context.Context, organizationID string) (int, error) {
+	return p.points[organizationID], nil
+}
+
+func (p *purgeVectorDB) GetPointCount(ctx context.Context) (int, error) {
+	total := 0
+	for _, n := range p.points {
+		total += n
+	}
+	return total, nil
+}
+
+func (p *purgeVectorDB) PurgeByOrganization(ctx context.Context, organizationID string) (int, error) {
+	p.purgedByOrg = append(p.purgedByOrg, organizationID)
+	deleted := p.points[organizationID]
+	delete(p.points, organizationID)
+	return deleted, nil
+}
+
+func newPurgeFixture(t *testing.T) (*purgeVectorDB, *sql.DB) {
+	t.Helper()
+	db := newTestDB(t)
+	if _, err := db.Exec("CREATE TABLE chunks (id TEXT PRIMARY KEY, document_id TEXT, content TEXT, chunk_index INTEGER, organization_id TEXT)"); err != nil {
+		t.Fatalf("Failed to create chunks table: %v", err)
+	}
+	for _, row := range [][]string{{"a1", "doc-a", "org-a"}, {"a2", "doc-a", "org-a"}, {"b1", "doc-b", "org-b"}} {
+		if _, err := db.Exec("INSERT INTO chunks (id, document_id, content, chunk_index, organization_id) VALUES (?, ?, 'text', 0, ?)", row[0], row[1], row[2]); err != nil {
+			t.Fatalf("Failed to insert chunk: %v", err)
+		}
+	}
+	for _, stmt := range []string{
+		"CREATE TABLE documents (id TEXT PRIMARY KEY, filename TEXT NOT NULL, uploaded_at DATETIME, metadata TEXT)",
+		"CREATE TABLE document_summaries (organization_id TEXT NOT NULL, document_id TEXT NOT NULL, filename TEXT, chunk_count INTEGER NOT NULL DEFAULT 0, summary TEXT, ingested_at DATETIME, PRIMARY KEY (organization_id, document_id))",
+		"CREATE TABLE graph_edges (source_doc_id TEXT NOT NULL, target_doc_id TEXT NOT NULL, relationship_type TEXT NOT NULL, description TEXT, created_at DATETIME, PRIMARY KEY (source_doc_id, target_doc_id, relationship_type))",
+		"INSERT INTO documents (id, filename) VALUES ('doc-a', 'a.txt'), ('doc-a2', 'a2.txt'), ('doc-b', 'b.txt')",
+		"INSERT INTO document_summaries (organization_id, document_id, filename) VALUES ('org-a', 'doc-a', 'a.txt'), ('org-a', 'doc-a2', 'a2.txt'), ('org-b', 'doc-b', 'b.txt')",
+		"INSERT INTO graph_edges (source_doc_id, target_doc_id, relationship_type) VALUES ('doc-a', 'doc-a2', 'related'), ('doc-b', 'doc-b', 'related')",
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("Failed to set up purge fixture: %v", err)
+		}
+	}
+	return &purgeVectorDB{points: map[string]int{"org-a": 2, "org-b": 1}}, db
+}
+
+func doPurge(h *PurgeHandler, user *database.User, orgID, body string) *httptest.ResponseRecorder {
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/v1/purge", strings.NewReader(body)), user, orgID)
+	rec := httptest.NewRecorder()
+	h.HandlePurge(rec, req)
+	return rec
+}
+
+func countChunks(t *testing.T, db *sql.DB, orgID string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM chunks WHERE organization_id = ?", orgID).Scan(&n); err != nil {
+		t.Fatalf("Failed to count chunks: %v", err)
+	}
+	return n
+}
+
+func TestHandlePurge_OrgAdminPurgesOnlyOwnOrg(t *testing.T) {
+	vdb, db := newPurgeFixture(t)
+	h := NewPurgeHandler(vdb, db, nil)
+	admin := &database.User{ID: "admin-a", Role: database.RoleAdmin, OrganizationID: "org-a"}
+
+	rec := doPurge(h, admin, "org-a", `{"confirm":"org-a"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp PurgeResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.OrganizationID != "org-a" || resp.VectorsDeleted != 2 || resp.ChunksDeleted != 2 {
+		t.Errorf("Unexpected purge response: %+v", resp)
+	}
+	if vdb.purgedAll || len(vdb.purgedByOrg) != 1 || vdb.purgedByOrg[0] != "org-a" {
+		t.Errorf("Expected only org-a vectors purged, got all=%v orgs=%v", vdb.purgedAll, vdb.purgedByOrg)
+	}
+	if countChunks(t, db, "org-a") != 0 || countChunks(t, db, "org-b") != 1 {
+		t.Errorf("Expected org-b chunks to survive org-a's purge")
+	}
+	if vdb.points["org-b"] != 1 {
+		t.Errorf("Expected org-b vectors to survive org-a's purge")
+	}
+	if resp.Documents != 2 || resp.Summaries != 2 || resp.GraphEdges != 1 {
+		t.Errorf("Expected org-a's 2 documents, 2 summaries and 1 graph edge purged, got %+v", resp.PurgeCounts)
+	}
+	for table, want := range map[string]int{"documents": 1, "document_summaries": 1, "graph_edges": 1} {
+		var n int
+		if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+			t.Fatalf("Failed to count %s: %v", table, err)
+		}
+		if n != want {
+			t.Errorf("Expected org-b's %s rows to survive org-a's purge, %d left", table, n)
+		}
+	}
+}
+
+func TestHandlePurge_CrossOrgAndGlobalRequireSuperAdmin(t *testing.T) {
+	vdb, db := newPurgeFixture(t)
+	h := NewPurgeHandler(vdb, db, nil)
+	admin := &database.User{ID: "admin-a", Role: database.RoleAdmin, OrganizationID: "org-a"}
+
+	for _, body := range []string{`{"organization_id":"org-b","confirm":"org-b"}`, `{"all":true,"confirm":"all organizations"}`} {
+		if rec := doPurge(h, admin, "org-a", body); rec.Code != http.StatusForbidden {
+			t.Errorf("%s: expected 403 for org admin, got %d", body, rec.Code)
+		}
+	}
+	if vdb.purgedAll || len(vdb.purgedByOrg) != 0 || countChunks(t, db, "org-b") != 1 {
+		t.Fatalf("Rejected purges must not delete anything")
+	}
+
+	super := &database.User{ID: "root", Role: database.RoleSuperAdmin}
+	if rec := doPurge(h, super, "", `{"organization_id":"org-b","confirm":"org-b"}`); rec.Code != http.StatusOK {
+		t.Fatalf("Expected super admin to purge org-b, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if countChunks(t, db, "org-b") != 0 || countChunks(t, db, "org-a") != 2 {
+		t.Errorf("Expected only org-b chunks removed")
+	}
+
+	if rec := doPurge(h, super, "", `{"all":true,"confirm":"all organizations"}`); rec.Code != http.StatusOK {
+		t.Fatalf("Expected super admin global purge, got %d", rec.Code)
+	}
+	if !vdb.purgedAll || countChunks(t, db, "org-a") != 0 {
+		t.Errorf("Expected global purge to clear everything")
+	}
+}
+
+func TestHandlePurgePreview_CountsMatchPurge(t *testing.T) {
+	vdb, db := newPurgeFixture(t)
+	h := NewPurgeHandler(vdb, db, nil)
+	super := &database.User{ID: "root", Role: database.RoleSuperAdmin}
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/v1/purge/preview", strings.NewReader(`{"organization_id":"org-a"}`)), super, "")
+	rec := httptest.NewRecorder()
+	h.HandlePurgePreview(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 from preview, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var preview PurgePreview
+	json.NewDecoder(rec.Body).Decode(&preview)
+	if len(vdb.purgedByOrg) != 0 || countChunks(t, db, "org-a") != 2 {
+		t.Fatalf("Preview must not delete anything")
+	}
+
+	// Without the confirmation text nothing is deleted
+	if rec := doPurge(h, super, "", `{"organization_id":"org-a","confirm":"org-b"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected a wrong confirmation to be rejected, got %d", rec.Code)
+	}
+	if len(vdb.purgedByOrg) != 0 || countChunks(t, db, "org-a") != 2 {
+		t.Fatalf("An unconfirmed purge must not delete anything")
+	}
+
+	rec = doPurge(h, super, "", `{"organization_id":"org-a","confirm":"`+preview.Confirmation+`"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the confirmed purge to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp PurgeResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if preview.PurgeCounts != resp.PurgeCounts {
+		t.Errorf("Preview counted %+v, purge deleted %+v", preview.PurgeCounts, resp.PurgeCounts)
+	}
+	if preview.VectorsToDelete != resp.VectorsDeleted || preview.ChunksToDelete != resp.ChunksDeleted {
+		t.Errorf("Preview counted %d vectors / %d chunks, purge deleted %d / %d",
+			preview.VectorsToDelete, preview.ChunksToDelete, resp.VectorsDeleted, resp.ChunksDeleted)
+	}
+	if resp.VectorsDeleted != 2 || resp.ChunksDeleted != 2 {
+		t.Errorf("Unexpected purge response: %+v", resp)
+	}
+}
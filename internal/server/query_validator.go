@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// defaultMinQueryLength is the minimum number of meaningful characters a query must contain
+const defaultMinQueryLength = 3
+
+// defaultStopWords are common English words that carry no meaning on their own
+var defaultStopWords = []string{
+	"a", "an", "and", "are", "as", "at", "be", "but", "by", "for", "from", "had", "has", "have",
+	"he", "her", "his", "i", "if", "in", "into", "is", "it", "its", "me", "my", "no", "not", "of",
+	"on", "or", "our", "she", "so", "that", "the", "their", "them", "then", "there", "these",
+	"they", "this", "to", "was", "we", "were", "what", "when", "where", "which", "who", "why",
+	"will", "with", "you", "your",
+}
+
+// QueryValidator rejects degenerate queries before they are embedded
+// Stop-word-only or very short queries produce meaningless embeddings and noisy results
+type QueryValidator struct {
+	minLength int
+	stopWords map[string]bool
+}
+
+// NewQueryValidator creates a validator with the given minimum length and stop-word list
+func NewQueryValidator(minLength int, stopWords []string) *QueryValidator {
+	words := make(map[string]bool, len(stopWords))
+	for _, word := range stopWords {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word != "" {
+			words[word] = true
+		}
+	}
+	return &QueryValidator{
+		minLength: minLength,
+		stopWords: words,
+	}
+}
+
+// NewQueryValidatorFromEnv creates a validator from QUERY_MIN_LENGTH and QUERY_STOP_WORDS (comma-separated)
+// Falls back to the built-in defaults when the variables are not set
+func NewQueryValidatorFromEnv() *QueryValidator {
+	minLength := defaultMinQueryLength
+	if minLengthStr := os.Getenv("QUERY_MIN_LENGTH"); minLengthStr != "" {
+		parsed, err := strconv.Atoi(minLengthStr)
+		if err != nil || parsed < 0 {
+			log.Printf("Invalid QUERY_MIN_LENGTH value '%s', using default %d", minLengthStr, defaultMinQueryLength)
+		} else {
+			minLength = parsed
+		}
+	}
+
+	stopWords := defaultStopWords
+	if stopWordsStr := os.Getenv("QUERY_STOP_WORDS"); stopWordsStr != "" {
+		stopWords = strings.Split(stopWordsStr, ",")
+	}
+
+	return NewQueryValidator(minLength, stopWords)
+}
+
+// Validate returns an error describing why the query cannot be searched, or nil if it is usable
+func (v *QueryValidator) Validate(query string) error {
+	terms := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	meaningful := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if !v.stopWords[term] {
+			meaningful = append(meaningful, term)
+		}
+	}
+
+	if len(meaningful) == 0 {
+		if len(terms) > 0 {
+			return fmt.Errorf("query contains only common words; please add more specific terms")
+		}
+		return fmt.Errorf("query contains no searchable terms")
+	}
+
+	if len([]rune(strings.Join(meaningful, " "))) < v.minLength {
+		return fmt.Errorf("query is too short; please use at least %d meaningful characters", v.minLength)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/vectordb"
+)
+
+func TestQueryValidator_Validate(t *testing.T) {
+	validator := NewQueryValidator(defaultMinQueryLength, defaultStopWords)
+
+	tests := []struct {
+		query   string
+		wantErr bool
+	}{
+		{"the", true},
+		{"The a, AN?", true},
+		{"!!!", true},
+		{"of x", true},
+		{"vacation policy", false},
+		{"what is the refund policy", false},
+		{"Q3 revenue", false},
+	}
+
+	for _, tt := range tests {
+		err := validator.Validate(tt.query)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+		}
+	}
+}
+
+func TestQueryValidator_FromEnv(t *testing.T) {
+	t.Setenv("QUERY_MIN_LENGTH", "10")
+	t.Setenv("QUERY_STOP_WORDS", "foo, bar")
+
+	validator := NewQueryValidatorFromEnv()
+
+	if err := validator.Validate("foo bar"); err == nil {
+		t.Errorf("Expected configured stop-words to be rejected")
+	}
+	if err := validator.Validate("the plan"); err == nil {
+		t.Errorf("Expected query shorter than configured minimum to be rejected")
+	}
+	if err := validator.Validate("the handbook"); err != nil {
+		t.Errorf("Expected 'the' to be allowed when not in the configured list, got %v", err)
+	}
+}
+
+func TestHandleSearch_RejectsStopWordQuery(t *testing.T) {
+	handler := NewSearchHandler(vectordb.NewMockVectorDB(), nil, nil)
+	handler.SetQueryValidator(NewQueryValidator(defaultMinQueryLength, defaultStopWords))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/search", strings.NewReader(`{"query":"the a"}`))
+	rec := httptest.NewRecorder()
+	handler.HandleSearch(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 for stop-word-only query, got %d", rec.Code)
+	}
+
+	var resp map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !strings.Contains(resp["error"], "common words") {
+		t.Errorf("Expected helpful error message, got %q", resp["error"])
+	}
+}
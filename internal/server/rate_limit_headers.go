@@ -0,0 +1,155 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/the-hive/internal/database"
+)
+
+const (
+	// DefaultRateLimitPerMinute is the advertised per-tenant request rate unless RATE_LIMIT_PER_MINUTE changes it
+	DefaultRateLimitPerMinute = 120
+	// rateLimitWindow is the fixed window the rate limit is counted over
+	rateLimitWindow = time.Minute
+)
+
+// RateLimitStatus is a tenant's standing after a request
+type RateLimitStatus struct {
+	Limit          int
+	Remaining      int
+	Reset          time.Time // When the current window ends
+	QuotaRemaining int       // Requests left today (UTC); only meaningful when HasQuota
+	HasQuota       bool
+}
+
+// tenantUsage counts one tenant's requests in the current window and day
+type tenantUsage struct {
+	windowStart time.Time
+	count       int
+	day         string
+	dayCount    int
+}
+
+// TenantRateLimiter counts each tenant's requests per window and per day. It is advisory: requests
+// are never rejected, but the X-RateLimit-* and X-Quota-Remaining headers let clients back off
+// before they reach a hard limit.
+type TenantRateLimiter struct {
+	limit      int
+	window     time.Duration
+	dailyQuota int // Zero omits X-Quota-Remaining
+	now        func() time.Time
+	mu         sync.Mutex
+	tenants    map[string]*tenantUsage
+}
+
+// NewTenantRateLimiter creates a limiter advertising limit requests per window and dailyQuota per day
+func NewTenantRateLimiter(limit int, window time.Duration, dailyQuota int) *TenantRateLimiter {
+	return &TenantRateLimiter{
+		limit:      limit,
+		window:     window,
+		dailyQuota: dailyQuota,
+		now:        time.Now,
+		tenants:    make(map[string]*tenantUsage),
+	}
+}
+
+// NewTenantRateLimiterFromEnv reads RATE_LIMIT_PER_MINUTE (default 120, 0 disables the headers) and
+// TENANT_DAILY_REQUEST_QUOTA (default 0, no quota). It returns nil when the headers are disabled.
+func NewTenantRateLimiterFromEnv() *TenantRateLimiter {
+	limit := DefaultRateLimitPerMinute
+	if v := os.Getenv("RATE_LIMIT_PER_MINUTE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			limit = n
+		} else {
+			log.Printf("Invalid RATE_LIMIT_PER_MINUTE %q, using default %d", v, DefaultRateLimitPerMinute)
+		}
+	}
+	if limit == 0 {
+		return nil
+	}
+
+	quota := 0
+	if v := os.Getenv("TENANT_DAILY_REQUEST_QUOTA"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			quota = n
+		} else {
+			log.Printf("Invalid TENANT_DAILY_REQUEST_QUOTA %q, no daily quota is reported", v)
+		}
+	}
+	return NewTenantRateLimiter(limit, rateLimitWindow, quota)
+}
+
+// Record counts a request for the tenant and returns its standing
+func (l *TenantRateLimiter) Record(tenant string) RateLimitStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	usage, ok := l.tenants[tenant]
+	if !ok {
+		usage = &tenantUsage{}
+		l.tenants[tenant] = usage
+	}
+	if now.Sub(usage.windowStart) >= l.window {
+		usage.windowStart = now
+		usage.count = 0
+	}
+	if day := now.UTC().Format("2006-01-02"); usage.day != day {
+		usage.day = day
+		usage.dayCount = 0
+	}
+	usage.count++
+	usage.dayCount++
+
+	status := RateLimitStatus{
+		Limit:     l.limit,
+		Remaining: max(l.limit-usage.count, 0),
+		Reset:     usage.windowStart.Add(l.window),
+	}
+	if l.dailyQuota > 0 {
+		status.HasQuota = true
+		status.QuotaRemaining = max(l.dailyQuota-usage.dayCount, 0)
+	}
+	return status
+}
+
+// Headers counts each request against its tenant and sets the rate limit headers. It must run after
+// authentication so the tenant is known; a nil limiter passes requests through unchanged.
+func (l *TenantRateLimiter) Headers(next http.Handler) http.Handler {
+	if l == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tenant := rateLimitTenant(r); tenant != "" {
+			status := l.Record(tenant)
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(status.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(status.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(status.Reset.Unix(), 10))
+			if status.HasQuota {
+				w.Header().Set("X-Quota-Remaining", strconv.Itoa(status.QuotaRemaining))
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimitTenant identifies who a request is counted against: its organization, or else the API key or user
+func rateLimitTenant(r *http.Request) string {
+	if orgID, ok := r.Context().Value("organization_id").(string); ok && orgID != "" {
+		return "org:" + orgID
+	}
+	if key, ok := r.Context().Value("api_key").(string); ok && key != "" {
+		return "key:" + key
+	}
+	if user, ok := r.Context().Value("user").(*database.User); ok && user != nil {
+		return "user:" + user.ID
+	}
+	return ""
+}
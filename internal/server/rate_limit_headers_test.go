@@ -0,0 +1,67 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/the-hive/internal/database"
+)
+
+func TestTenantRateLimiter_HeadersDecrement(t *testing.T) {
+	limiter := NewTenantRateLimiter(3, time.Minute, 5)
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	handler := limiter.Headers(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	request := func(orgID string) http.Header {
+		req := withUser(httptest.NewRequest(http.MethodPost, "/api/v1/search", nil), &database.User{ID: "u1"}, orgID)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Header()
+	}
+
+	for i, want := range []struct{ remaining, quota string }{{"2", "4"}, {"1", "3"}, {"0", "2"}, {"0", "1"}} {
+		headers := request("org-a")
+		if headers.Get("X-RateLimit-Limit") != "3" {
+			t.Errorf("Request %d: expected limit 3, got %q", i+1, headers.Get("X-RateLimit-Limit"))
+		}
+		if got := headers.Get("X-RateLimit-Remaining"); got != want.remaining {
+			t.Errorf("Request %d: expected %s remaining, got %q", i+1, want.remaining, got)
+		}
+		if got := headers.Get("X-Quota-Remaining"); got != want.quota {
+			t.Errorf("Request %d: expected quota %s remaining, got %q", i+1, want.quota, got)
+		}
+		if got := headers.Get("X-RateLimit-Reset"); got != strconv.FormatInt(now.Add(time.Minute).Unix(), 10) {
+			t.Errorf("Request %d: expected the window to reset a minute after it started, got %q", i+1, got)
+		}
+	}
+
+	// Other organizations are counted separately
+	if got := request("org-b").Get("X-RateLimit-Remaining"); got != "2" {
+		t.Errorf("Expected another organization to have its own window, got %q", got)
+	}
+
+	// A new window restores the rate limit but not the daily quota
+	now = now.Add(time.Minute)
+	headers := request("org-a")
+	if headers.Get("X-RateLimit-Remaining") != "2" || headers.Get("X-Quota-Remaining") != "0" {
+		t.Errorf("Expected a fresh window with the quota used up, got remaining %q, quota %q", headers.Get("X-RateLimit-Remaining"), headers.Get("X-Quota-Remaining"))
+	}
+}
+
+func TestTenantRateLimiter_DisabledPassesThrough(t *testing.T) {
+	t.Setenv("RATE_LIMIT_PER_MINUTE", "0")
+	limiter := NewTenantRateLimiterFromEnv()
+	rec := httptest.NewRecorder()
+	limiter.Headers(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(rec, withUser(httptest.NewRequest(http.MethodGet, "/api/v1/search", nil), &database.User{ID: "u1"}, "org-a"))
+	if rec.Header().Get("X-RateLimit-Limit") != "" {
+		t.Errorf("Expected no rate limit headers when disabled, got %v", rec.Header())
+	}
+}
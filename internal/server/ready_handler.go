@@ -0,0 +1,138 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/the-hive/internal/queue"
+	"github.com/the-hive/internal/vectordb"
+)
+
+// readinessTimeout bounds each dependency check so a hung dependency fails the probe instead of stalling it
+const readinessTimeout = 3 * time.Second
+
+// ReadinessCheck pings one dependency the server needs to serve traffic
+type ReadinessCheck struct {
+	Name     string
+	Check    func(ctx context.Context) error
+	Optional bool // Reported in the breakdown, but doesn't fail the probe when down
+}
+
+// DependencyStatus is the result of one readiness check
+type DependencyStatus struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"` // up or down
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+	Optional  bool    `json:"optional,omitempty"`
+}
+
+// ReadinessResponse is the body of GET /api/v1/ready
+type ReadinessResponse struct {
+	Status       string             `json:"status"` // ready or not_ready
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+// DatabaseReadinessCheck verifies the SQLite database answers a query
+func DatabaseReadinessCheck(db *sql.DB) ReadinessCheck {
+	return ReadinessCheck{Name: "database", Check: func(ctx context.Context) error {
+		var one int
+		return db.QueryRowContext(ctx, "SELECT 1").Scan(&one)
+	}}
+}
+
+// VectorDBReadinessCheck verifies the vector database (Qdrant) answers a point count
+func VectorDBReadinessCheck(vectorDB vectordb.VectorDB) ReadinessCheck {
+	return ReadinessCheck{Name: "vector_db", Check: func(ctx context.Context) error {
+		_, err := vectorDB.GetPointCount(ctx)
+		return err
+	}}
+}
+
+// JobQueueReadinessCheck verifies the job queue is connected and Redis answers. A nil queue (Redis
+// configured but no queue was created) always reports down.
+func JobQueueReadinessCheck(jobQueue interface {
+	Ping(ctx context.Context) error
+}) ReadinessCheck {
+	return ReadinessCheck{Name: "job_queue", Check: func(ctx context.Context) error {
+		if jobQueue == nil {
+			return errors.New("not connected")
+		}
+		return jobQueue.Ping(ctx)
+	}}
+}
+
+// jobQueueAvailable reports whether jobs can be queued: there is a queue and, if it connects in the
+// background, it has connected
+func jobQueueAvailable(jobQueue queue.Queue) bool {
+	if jobQueue == nil {
+		return false
+	}
+	if q, ok := jobQueue.(interface{ Connected() bool }); ok {
+		return q.Connected()
+	}
+	return true
+}
+
+// HandleReady returns a handler for GET /api/v1/ready, the readiness probe: it runs every check in
+// parallel and answers 200 only when all required ones pass, 503 otherwise, with each dependency's
+// status and latency. /api/v1/health stays the cheap liveness probe.
+func HandleReady(checks ...ReadinessCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		response := ReadinessResponse{Status: "ready", Dependencies: make([]DependencyStatus, len(checks))}
+		var wg sync.WaitGroup
+		for i, check := range checks {
+			wg.Add(1)
+			go func(i int, check ReadinessCheck) {
+				defer wg.Done()
+				response.Dependencies[i] = runReadinessCheck(r.Context(), check)
+			}(i, check)
+		}
+		wg.Wait()
+
+		status := http.StatusOK
+		for _, dep := range response.Dependencies {
+			if dep.Status != "up" && !dep.Optional {
+				response.Status = "not_ready"
+				status = http.StatusServiceUnavailable
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// runReadinessCheck runs one check under readinessTimeout and times it
+func runReadinessCheck(ctx context.Context, check ReadinessCheck) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Check(ctx)
+	result := DependencyStatus{
+		Name:      check.Name,
+		Status:    "up",
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+		Optional:  check.Optional,
+	}
+	if err != nil {
+		result.Status = "down"
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/the-hive/internal/vectordb"
+)
+
+// unreachableVectorDB simulates Qdrant being down
+type unreachableVectorDB struct {
+	vectordb.MockVectorDB
+}
+
+func (v *unreachableVectorDB) GetPointCount(ctx context.Context) (int, error) {
+	return 0, errors.New("connection refused")
+}
+
+func TestHandleReady_ReportsEachDependency(t *testing.T) {
+	db := newTestDB(t)
+	get := func(checks ...ReadinessCheck) (int, ReadinessResponse) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		HandleReady(checks...)(rec, httptest.NewRequest(http.MethodGet, "/api/v1/ready", nil))
+		var resp ReadinessResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return rec.Code, resp
+	}
+
+	code, resp := get(DatabaseReadinessCheck(db), VectorDBReadinessCheck(vectordb.NewMemoryVectorDB()))
+	if code != http.StatusOK || resp.Status != "ready" || len(resp.Dependencies) != 2 {
+		t.Fatalf("Expected ready with both dependencies, got %d %+v", code, resp)
+	}
+	if resp.Dependencies[0].Name != "database" || resp.Dependencies[1].Name != "vector_db" || resp.Dependencies[0].Status != "up" {
+		t.Errorf("Expected each dependency named and up, got %+v", resp.Dependencies)
+	}
+
+	code, resp = get(DatabaseReadinessCheck(db), VectorDBReadinessCheck(&unreachableVectorDB{}), JobQueueReadinessCheck(nil))
+	if code != http.StatusServiceUnavailable || resp.Status != "not_ready" {
+		t.Fatalf("Expected 503 when Qdrant and the job queue are down, got %d %+v", code, resp)
+	}
+	if resp.Dependencies[0].Status != "up" {
+		t.Errorf("Expected the database to stay up, got %+v", resp.Dependencies[0])
+	}
+	for _, dep := range resp.Dependencies[1:] {
+		if dep.Status != "down" || dep.Error == "" {
+			t.Errorf("Expected %s to be down with its error, got %+v", dep.Name, dep)
+		}
+	}
+
+	// An optional dependency is reported but doesn't fail the probe
+	optionalQueue := JobQueueReadinessCheck(nil)
+	optionalQueue.Optional = true
+	if code, resp = get(DatabaseReadinessCheck(db), optionalQueue); code != http.StatusOK || resp.Dependencies[1].Status != "down" || !resp.Dependencies[1].Optional {
+		t.Errorf("Expected ready with the optional job queue reported down, got %d %+v", code, resp)
+	}
+
+	db.Close()
+	if code, resp = get(DatabaseReadinessCheck(db)); code != http.StatusServiceUnavailable || resp.Dependencies[0].Status != "down" {
+		t.Errorf("Expected a closed database to fail the probe, got %d %+v", code, resp)
+	}
+}
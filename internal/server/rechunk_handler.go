@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/jobs"
+	"github.com/the-hive/internal/queue"
+)
+
+// RechunkRequest represents a request to re-chunk an organization's documents
+type RechunkRequest struct {
+	OrganizationID string `json:"organization_id,omitempty"` // Super admins only; defaults to the caller's organization
+}
+
+// RechunkHandler handles re-chunking documents with the current chunk settings
+type RechunkHandler struct {
+	rechunker     *jobs.Rechunker
+	jobQueue      queue.Queue
+	auditLogStore *database.AuditLogStore
+}
+
+// NewRechunkHandler creates a new rechunk handler
+func NewRechunkHandler(rechunker *jobs.Rechunker, jobQueue queue.Queue, auditLogStore *database.AuditLogStore) *RechunkHandler {
+	return &RechunkHandler{
+		rechunker:     rechunker,
+		jobQueue:      jobQueue,
+		auditLogStore: auditLogStore,
+	}
+}
+
+// HandleRechunk handles POST /api/v1/admin/rechunk
+func (h *RechunkHandler) HandleRechunk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	var req RechunkRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+	}
+
+	// Only super admins may target another organization
+	if req.OrganizationID != "" && req.OrganizationID != orgID {
+		if dbUser.Role != database.RoleSuperAdmin {
+			writeJSONError(w, http.StatusForbidden, "cannot re-chunk another organization")
+			return
+		}
+		orgID = req.OrganizationID
+	}
+	if orgID == "" {
+		writeJSONError(w, http.StatusBadRequest, "organization_id is required")
+		return
+	}
+
+	if !jobQueueAvailable(h.jobQueue) {
+		writeJSONError(w, http.StatusServiceUnavailable, "job queue not available (Redis required)")
+		return
+	}
+
+	progress, err := h.rechunker.Enqueue(r.Context(), h.jobQueue, orgID, dbUser.ID)
+	if err != nil {
+		log.Printf("Failed to enqueue rechunk job for org %s: %v", orgID, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to enqueue rechunk job")
+		return
+	}
+
+	if h.auditLogStore != nil {
+		clientIP := getClientIP(r)
+		if err := h.auditLogStore.LogAction(clientIP, database.AuditActionRechunk, "Re-chunk requested by "+dbUser.Email+" (job "+progress.JobID+")", orgID); err != nil {
+			log.Printf("Failed to log rechunk audit entry: %v", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(progress)
+}
+
+// HandleRechunkStatus handles GET /api/v1/admin/rechunk/{id}
+func (h *RechunkHandler) HandleRechunkStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	progress := h.rechunker.Progress(r.PathValue("id"))
+	if progress == nil || (progress.OrganizationID != orgID && dbUser.Role != database.RoleSuperAdmin) {
+		writeJSONError(w, http.StatusNotFound, "rechunk job not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(progress)
+}
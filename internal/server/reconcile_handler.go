@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/jobs"
+	"github.com/the-hive/internal/queue"
+)
+
+// ReconcileRequest represents a request to reconcile an organization's chunks with its vectors
+type ReconcileRequest struct {
+	OrganizationID string `json:"organization_id,omitempty"` // Super admins only; defaults to the caller's organization
+	DryRun         bool   `json:"dry_run"`                   // Only report mismatches
+}
+
+// ReconcileHandler handles repairing mismatches between SQLite chunks and vector points
+type ReconcileHandler struct {
+	reconciler    *jobs.Reconciler
+	jobQueue      queue.Queue
+	auditLogStore *database.AuditLogStore
+}
+
+// NewReconcileHandler creates a new reconcile handler
+func NewReconcileHandler(reconciler *jobs.Reconciler, jobQueue queue.Queue, auditLogStore *database.AuditLogStore) *ReconcileHandler {
+	return &ReconcileHandler{
+		reconciler:    reconciler,
+		jobQueue:      jobQueue,
+		auditLogStore: auditLogStore,
+	}
+}
+
+// HandleReconcile handles POST /api/v1/admin/reconcile
+func (h *ReconcileHandler) HandleReconcile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	var req ReconcileRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+	}
+
+	// Only super admins may target another organization
+	if req.OrganizationID != "" && req.OrganizationID != orgID {
+		if dbUser.Role != database.RoleSuperAdmin {
+			writeJSONError(w, http.StatusForbidden, "cannot reconcile another organization")
+			return
+		}
+		orgID = req.OrganizationID
+	}
+	if orgID == "" {
+		writeJSONError(w, http.StatusBadRequest, "organization_id is required")
+		return
+	}
+
+	if !jobQueueAvailable(h.jobQueue) {
+		writeJSONError(w, http.StatusServiceUnavailable, "job queue not available (Redis required)")
+		return
+	}
+
+	report, err := h.reconciler.Enqueue(r.Context(), h.jobQueue, orgID, dbUser.ID, req.DryRun)
+	if err != nil {
+		log.Printf("Failed to enqueue reconcile job for org %s: %v", orgID, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to enqueue reconcile job")
+		return
+	}
+
+	if h.auditLogStore != nil && !req.DryRun {
+		clientIP := getClientIP(r)
+		if err := h.auditLogStore.LogAction(clientIP, database.AuditActionReconcile, "Reconcile requested by "+dbUser.Email+" (job "+report.JobID+")", orgID); err != nil {
+			log.Printf("Failed to log reconcile audit entry: %v", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(report)
+}
+
+// HandleReconcileStatus handles GET /api/v1/admin/reconcile/{id}
+func (h *ReconcileHandler) HandleReconcileStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	report := h.reconciler.Report(r.PathValue("id"))
+	if report == nil || (report.OrganizationID != orgID && dbUser.Role != database.RoleSuperAdmin) {
+		writeJSONError(w, http.StatusNotFound, "reconcile job not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
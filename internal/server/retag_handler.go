@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/jobs"
+	"github.com/the-hive/internal/queue"
+)
+
+// RetagRequest represents a request to re-tag an organization's documents
+type RetagRequest struct {
+	OrganizationID string `json:"organization_id,omitempty"` // Super admins only; defaults to the caller's organization
+	UntaggedOnly   bool   `json:"untagged_only"`             // Skip documents that already have tags
+}
+
+// RetagHandler handles re-tagging documents with the current tag vocabulary
+type RetagHandler struct {
+	retagger      *jobs.Retagger
+	jobQueue      queue.Queue
+	auditLogStore *database.AuditLogStore
+}
+
+// NewRetagHandler creates a new retag handler
+func NewRetagHandler(retagger *jobs.Retagger, jobQueue queue.Queue, auditLogStore *database.AuditLogStore) *RetagHandler {
+	return &RetagHandler{
+		retagger:      retagger,
+		jobQueue:      jobQueue,
+		auditLogStore: auditLogStore,
+	}
+}
+
+// HandleRetag handles POST /api/v1/admin/retag
+func (h *RetagHandler) HandleRetag(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	var req RetagRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+	}
+
+	// Only super admins may target another organization
+	if req.OrganizationID != "" && req.OrganizationID != orgID {
+		if dbUser.Role != database.RoleSuperAdmin {
+			writeJSONError(w, http.StatusForbidden, "cannot re-tag another organization")
+			return
+		}
+		orgID = req.OrganizationID
+	}
+	if orgID == "" {
+		writeJSONError(w, http.StatusBadRequest, "organization_id is required")
+		return
+	}
+
+	if !jobQueueAvailable(h.jobQueue) {
+		writeJSONError(w, http.StatusServiceUnavailable, "job queue not available (Redis required)")
+		return
+	}
+
+	progress, err := h.retagger.Enqueue(r.Context(), h.jobQueue, orgID, dbUser.ID, req.UntaggedOnly)
+	if err != nil {
+		log.Printf("Failed to enqueue retag job for org %s: %v", orgID, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to enqueue retag job")
+		return
+	}
+
+	if h.auditLogStore != nil {
+		clientIP := getClientIP(r)
+		details := fmt.Sprintf("Re-tag requested by %s (job %s, untagged only: %v)", dbUser.Email, progress.JobID, req.UntaggedOnly)
+		if err := h.auditLogStore.LogAction(clientIP, database.AuditActionRetag, details, orgID); err != nil {
+			log.Printf("Failed to log retag audit entry: %v", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(progress)
+}
+
+// HandleRetagStatus handles GET /api/v1/admin/retag/{id}
+func (h *RetagHandler) HandleRetagStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	progress := h.retagger.Progress(r.PathValue("id"))
+	if progress == nil || (progress.OrganizationID != orgID && dbUser.Role != database.RoleSuperAdmin) {
+		writeJSONError(w, http.StatusNotFound, "retag job not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(progress)
+}
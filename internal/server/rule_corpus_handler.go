@@ -0,0 +1,257 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/the-hive/internal/rules"
+	"github.com/the-hive/internal/vectordb"
+	"github.com/the-hive/internal/worker"
+)
+
+const (
+	// DefaultRuleTestDocuments is how many documents a rule is tested against when the request doesn't say
+	DefaultRuleTestDocuments = 25
+	// MaxRuleTestDocuments caps the documents one test checks, since semantic rules make an AI call per document
+	MaxRuleTestDocuments = 100
+	// ruleCorpusTestTimeout bounds one corpus test; documents not reached by then are reported as unchecked
+	ruleCorpusTestTimeout = 5 * time.Minute
+)
+
+// RuleChecker checks a single rule against content without storing or notifying anything
+type RuleChecker interface {
+	CheckRule(ctx context.Context, rule rules.Rule, content string) (*worker.RuleFinding, error)
+}
+
+// RuleCorpusTestRequest is a draft rule to try against the organization's documents
+type RuleCorpusTestRequest struct {
+	Query        string `json:"query"`
+	Kind         string `json:"kind"`          // semantic (default) or keyword
+	Scope        string `json:"scope"`         // single_doc or auto (default); cross_doc rules can't be tested
+	MaxDocuments int    `json:"max_documents"` // Defaults to DefaultRuleTestDocuments, at most MaxRuleTestDocuments
+}
+
+// RuleCorpusMatch is a document the draft rule would flag
+type RuleCorpusMatch struct {
+	DocumentID  string `json:"document_id"`
+	Filename    string `json:"filename,omitempty"`
+	MatchType   string `json:"match_type"`
+	Explanation string `json:"explanation,omitempty"`
+	Evidence    string `json:"evidence,omitempty"`
+}
+
+// RuleCorpusTestResponse lists the documents a draft rule would flag
+type RuleCorpusTestResponse struct {
+	Query            string            `json:"query"`
+	Kind             string            `json:"kind"`
+	DocumentsTotal   int               `json:"documents_total"`
+	DocumentsChecked int               `json:"documents_checked"`
+	Sampled          bool              `json:"sampled"`   // Only the most recently ingested documents were checked
+	TimedOut         bool              `json:"timed_out"` // The test ran out of time before checking every sampled document
+	Matches          []RuleCorpusMatch `json:"matches"`
+	FailedDocuments  []string          `json:"failed_documents,omitempty"` // Documents the AI couldn't answer for
+}
+
+// RuleCorpusTester runs a draft rule against already-ingested documents
+type RuleCorpusTester struct {
+	db       *sql.DB
+	vectorDB vectordb.VectorDB // Resolves filenames from point payloads when it can read them
+	checker  RuleChecker
+}
+
+// NewRuleCorpusTester creates a new rule corpus tester
+func NewRuleCorpusTester(db *sql.DB, vectorDB vectordb.VectorDB, checker RuleChecker) *RuleCorpusTester {
+	return &RuleCorpusTester{db: db, vectorDB: vectorDB, checker: checker}
+}
+
+// HandleTestCorpus handles POST /api/v1/rules/test-corpus
+// The rule is checked against the organization's most recently ingested documents the way the analyst
+// would check it on upload, but nothing is saved: no rule, matches, events or notifications.
+func (h *RuleCorpusTester) HandleTestCorpus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	_, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+	if orgID == "" {
+		writeJSONError(w, http.StatusBadRequest, "organization ID required")
+		return
+	}
+
+	var req RuleCorpusTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Query == "" {
+		writeJSONError(w, http.StatusBadRequest, "query is required")
+		return
+	}
+	if req.Kind == "" {
+		req.Kind = rules.KindSemantic
+	}
+	if req.Kind != rules.KindSemantic && req.Kind != rules.KindKeyword {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid kind %q (expected %s or %s)", req.Kind, rules.KindSemantic, rules.KindKeyword))
+		return
+	}
+	if req.Scope == "" {
+		req.Scope = rules.ScopeAuto
+	}
+	if !rules.ValidScope(req.Scope) {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid scope %q", req.Scope))
+		return
+	}
+	if req.MaxDocuments <= 0 {
+		req.MaxDocuments = DefaultRuleTestDocuments
+	}
+	if req.MaxDocuments > MaxRuleTestDocuments {
+		req.MaxDocuments = MaxRuleTestDocuments
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), ruleCorpusTestTimeout)
+	defer cancel()
+
+	total, err := h.countDocuments(ctx, orgID)
+	if err != nil {
+		log.Printf("Rule corpus test: failed to count documents for org %s: %v", orgID, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to list documents")
+		return
+	}
+	documentIDs, err := h.recentDocuments(ctx, orgID, req.MaxDocuments)
+	if err != nil {
+		log.Printf("Rule corpus test: failed to list documents for org %s: %v", orgID, err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to list documents")
+		return
+	}
+
+	rule := rules.Rule{Query: req.Query, Kind: req.Kind, Active: true, Scope: req.Scope}
+	resp := RuleCorpusTestResponse{
+		Query:          req.Query,
+		Kind:           req.Kind,
+		DocumentsTotal: total,
+		Sampled:        total > len(documentIDs),
+		Matches:        []RuleCorpusMatch{},
+	}
+	for _, documentID := range documentIDs {
+		if ctx.Err() != nil {
+			resp.TimedOut = true
+			break
+		}
+		firstChunkID, content, err := h.documentContent(ctx, orgID, documentID)
+		if err != nil {
+			log.Printf("Rule corpus test: failed to load document %s: %v", documentID, err)
+			resp.FailedDocuments = append(resp.FailedDocuments, documentID)
+			continue
+		}
+
+		finding, err := h.checker.CheckRule(ctx, rule, content)
+		if errors.Is(err, worker.ErrCrossDocumentRule) {
+			writeJSONError(w, http.StatusBadRequest, "cross-document rules can't be tested against the corpus")
+			return
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				resp.TimedOut = true
+				break
+			}
+			log.Printf("Rule corpus test: failed to check document %s: %v", documentID, err)
+			resp.FailedDocuments = append(resp.FailedDocuments, documentID)
+			continue
+		}
+		resp.DocumentsChecked++
+		if finding == nil {
+			continue
+		}
+		resp.Matches = append(resp.Matches, RuleCorpusMatch{
+			DocumentID:  documentID,
+			Filename:    h.filename(ctx, firstChunkID),
+			MatchType:   finding.MatchType,
+			Explanation: finding.Explanation,
+			Evidence:    finding.Evidence,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// countDocuments returns how many documents the organization has chunks for
+func (h *RuleCorpusTester) countDocuments(ctx context.Context, orgID string) (int, error) {
+	var count int
+	err := h.db.QueryRowContext(ctx, "SELECT COUNT(DISTINCT document_id) FROM chunks WHERE organization_id = ?", orgID).Scan(&count)
+	return count, err
+}
+
+// recentDocuments returns the IDs of the organization's most recently ingested documents, newest first
+func (h *RuleCorpusTester) recentDocuments(ctx context.Context, orgID string, limit int) ([]string, error) {
+	rows, err := h.db.QueryContext(ctx,
+		"SELECT document_id FROM chunks WHERE organization_id = ? GROUP BY document_id ORDER BY MAX(created_at) DESC, document_id LIMIT ?",
+		orgID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// documentContent returns a document's first chunk ID and its chunks joined in order
+func (h *RuleCorpusTester) documentContent(ctx context.Context, orgID, documentID string) (string, string, error) {
+	rows, err := h.db.QueryContext(ctx,
+		"SELECT id, content FROM chunks WHERE document_id = ? AND organization_id = ? ORDER BY chunk_index ASC, rowid ASC",
+		documentID, orgID,
+	)
+	if err != nil {
+		return "", "", err
+	}
+	defer rows.Close()
+
+	var firstID string
+	var parts []string
+	for rows.Next() {
+		var id, content string
+		if err := rows.Scan(&id, &content); err != nil {
+			return "", "", err
+		}
+		if firstID == "" {
+			firstID = id
+		}
+		parts = append(parts, content)
+	}
+	return firstID, strings.Join(parts, "\n\n"), rows.Err()
+}
+
+// filename reads a document's filename from its first point's payload, if the vector database can
+func (h *RuleCorpusTester) filename(ctx context.Context, chunkID string) string {
+	reader, ok := h.vectorDB.(vectordb.PayloadReader)
+	if !ok || chunkID == "" {
+		return ""
+	}
+	payload, err := reader.GetPayload(ctx, chunkID)
+	if err != nil {
+		return ""
+	}
+	return payload["filename"]
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/rules"
+	"github.com/the-hive/internal/vectordb"
+	"github.com/the-hive/internal/worker"
+)
+
+func TestHandleTestCorpus_FlagsMatchingDocumentsWithoutSaving(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := db.Exec(`CREATE TABLE chunks (
+		id TEXT PRIMARY KEY,
+		document_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		chunk_index INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		organization_id TEXT
+	)`); err != nil {
+		t.Fatalf("Failed to create chunks table: %v", err)
+	}
+	ruleStore, err := rules.NewStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create rule store: %v", err)
+	}
+	vdb := vectordb.NewMemoryVectorDB()
+
+	corpus := []struct {
+		docID, filename, orgID, createdAt string
+		chunks                            []string
+	}{
+		{"doc-handbook", "handbook.pdf", "org-a", "2025-01-01 09:00:00", []string{"Welcome to the team.", "Holidays are listed on the intranet."}},
+		{"doc-pricing", "pricing.xlsx", "org-a", "2025-01-02 09:00:00", []string{"Partner discounts:", "Embargoed until launch: 40% off for resellers."}},
+		{"doc-memo", "memo.txt", "org-a", "2025-01-03 09:00:00", []string{"Lunch is at noon."}},
+		{"doc-other", "leak.txt", "org-b", "2025-01-04 09:00:00", []string{"Embargoed figures for another org."}},
+	}
+	for _, doc := range corpus {
+		for i, chunk := range doc.chunks {
+			id := fmt.Sprintf("%s-%d", doc.docID, i)
+			if _, err := db.Exec("INSERT INTO chunks (id, document_id, content, chunk_index, created_at, organization_id) VALUES (?, ?, ?, ?, ?, ?)",
+				id, doc.docID, chunk, i, doc.createdAt, doc.orgID); err != nil {
+				t.Fatalf("Failed to insert chunk: %v", err)
+			}
+			vdb.Upsert(context.Background(), id, []float32{1, 0}, map[string]string{"filename": doc.filename, "organization_id": doc.orgID})
+		}
+	}
+
+	pool := worker.NewAnalystPool(ruleStore, nil, nil, vdb, nil, nil, nil, 1)
+	h := NewRuleCorpusTester(db, vdb, pool)
+	post := func(body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := withUser(httptest.NewRequest(http.MethodPost, "/api/v1/rules/test-corpus", strings.NewReader(body)), &database.User{ID: "admin"}, "org-a")
+		rec := httptest.NewRecorder()
+		h.HandleTestCorpus(rec, req)
+		return rec
+	}
+
+	rec := post(`{"query": "embargoed", "kind": "keyword"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp RuleCorpusTestResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.DocumentsTotal != 3 || resp.DocumentsChecked != 3 || resp.Sampled {
+		t.Errorf("Expected all 3 of the organization's documents to be checked, got %+v", resp)
+	}
+	if len(resp.Matches) != 1 {
+		t.Fatalf("Expected only the pricing document to be flagged, got %+v", resp.Matches)
+	}
+	match := resp.Matches[0]
+	if match.DocumentID != "doc-pricing" || match.Filename != "pricing.xlsx" || match.MatchType != "keyword" || !strings.Contains(match.Evidence, "40% off") {
+		t.Errorf("Expected the pricing document with its evidence, got %+v", match)
+	}
+
+	// Sampling takes the most recently ingested documents
+	rec = post(`{"query": "embargoed", "kind": "keyword", "max_documents": 1}`)
+	resp = RuleCorpusTestResponse{}
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if !resp.Sampled || resp.DocumentsChecked != 1 || len(resp.Matches) != 0 {
+		t.Errorf("Expected only the newest document (the memo) to be checked, got %+v", resp)
+	}
+
+	// Nothing was saved
+	if all, _ := ruleStore.GetAllRules("org-a"); len(all) != 0 {
+		t.Errorf("Expected no rule to be saved, got %+v", all)
+	}
+
+	for _, body := range []string{`{"query": "  "}`, `{"query": "embargoed", "kind": "regex"}`, `{"query": "Does this contradict earlier documents?"}`} {
+		if rec := post(body); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", body, rec.Code)
+		}
+	}
+}
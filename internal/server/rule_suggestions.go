@@ -0,0 +1,98 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/rules"
+	"github.com/the-hive/internal/vectordb"
+)
+
+const (
+	// DefaultRelatedRuleThreshold is the minimum query similarity for an existing rule to be suggested
+	DefaultRelatedRuleThreshold = 0.8
+	maxRelatedRules             = 3
+)
+
+// RelatedRule is an existing rule whose query is similar to a new rule's
+type RelatedRule struct {
+	rules.Rule
+	Similarity float64 `json:"similarity"`
+}
+
+// RuleSuggester finds existing rules that are semantically similar to a new one, to help avoid duplicates.
+// Rule query embeddings are stored with the rules so each suggestion only embeds the new query.
+type RuleSuggester struct {
+	store     *rules.Store
+	embedder  embeddings.Embedder
+	threshold float64
+}
+
+// NewRuleSuggester creates a suggester using the embedder for rule queries
+func NewRuleSuggester(store *rules.Store, embedder embeddings.Embedder) *RuleSuggester {
+	return &RuleSuggester{
+		store:     store,
+		embedder:  embedder,
+		threshold: DefaultRelatedRuleThreshold,
+	}
+}
+
+// SetThreshold sets the minimum similarity (0-1) for a rule to be suggested
+func (s *RuleSuggester) SetThreshold(threshold float64) {
+	if threshold > 0 && threshold <= 1 {
+		s.threshold = threshold
+	}
+}
+
+// Related stores the new rule's query embedding and returns the most similar existing rules.
+// Rules without a current embedding (new, or edited since) are embedded and stored on the way.
+func (s *RuleSuggester) Related(ctx context.Context, rule *rules.Rule, organizationID string) ([]RelatedRule, error) {
+	vector, err := s.embedder.EmbedText(ctx, rule.Query)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.SaveQueryEmbedding(ctx, rule.ID, rule.Query, vector); err != nil {
+		log.Printf("[RULES] Failed to store embedding for rule %d: %v", rule.ID, err)
+	}
+
+	existing, err := s.store.GetAllRules(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.store.GetQueryEmbeddings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var related []RelatedRule
+	for _, candidate := range existing {
+		if candidate.ID == rule.ID || candidate.Kind == rules.KindKeyword {
+			continue
+		}
+		embedding, ok := stored[candidate.ID]
+		if !ok || embedding.Query != candidate.Query {
+			candidateVector, err := s.embedder.EmbedText(ctx, candidate.Query)
+			if err != nil {
+				return nil, err
+			}
+			if err := s.store.SaveQueryEmbedding(ctx, candidate.ID, candidate.Query, candidateVector); err != nil {
+				log.Printf("[RULES] Failed to store embedding for rule %d: %v", candidate.ID, err)
+			}
+			embedding = rules.QueryEmbedding{Query: candidate.Query, Vector: candidateVector}
+		}
+
+		if similarity := vectordb.CosineSimilarity(vector, embedding.Vector); similarity >= s.threshold {
+			related = append(related, RelatedRule{Rule: candidate, Similarity: similarity})
+		}
+	}
+
+	sort.Slice(related, func(i, j int) bool { return related[i].Similarity > related[j].Similarity })
+	if len(related) > maxRelatedRules {
+		related = related[:maxRelatedRules]
+	}
+	return related, nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/rules"
+)
+
+func TestHandleAddRule_SuggestsNearDuplicate(t *testing.T) {
+	store, err := rules.NewStore(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Failed to create rule store: %v", err)
+	}
+	existing, _ := store.AddRule(context.Background(), "Does the document mention a termination clause for the contract?", true, "", "org-a")
+	store.AddRule(context.Background(), "Is this invoice overdue by more than 30 days?", true, "", "org-a")
+
+	suggester := NewRuleSuggester(store, embeddings.NewSeededMockEmbedder(256, 7))
+	suggester.SetThreshold(0.6)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/rules", strings.NewReader(`{"query":"Does the contract mention a termination clause?","active":true}`))
+	req = withUser(req, &database.User{ID: "admin"}, "org-a")
+	rec := httptest.NewRecorder()
+	HandleAddRule(rec, req, store, suggester)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		ID           int64         `json:"id"`
+		Query        string        `json:"query"`
+		RelatedRules []RelatedRule `json:"related_rules"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.ID == 0 || resp.Query == "" {
+		t.Errorf("Expected the created rule in the response, got %+v", resp)
+	}
+	if len(resp.RelatedRules) != 1 || resp.RelatedRules[0].ID != existing.ID {
+		t.Fatalf("Expected only the near-duplicate rule %d to be suggested, got %+v", existing.ID, resp.RelatedRules)
+	}
+	if resp.RelatedRules[0].Similarity < 0.6 || resp.RelatedRules[0].Similarity > 1 {
+		t.Errorf("Unexpected similarity %f", resp.RelatedRules[0].Similarity)
+	}
+
+	// Embeddings are stored so later suggestions only embed the new query
+	stored, err := store.GetQueryEmbeddings(context.Background())
+	if err != nil || len(stored) != 3 {
+		t.Errorf("Expected embeddings for all 3 rules, got %d, %v", len(stored), err)
+	}
+}
+
+func TestHandleAddRule_SuggestsOnlyOwnOrganizationRules(t *testing.T) {
+	store, err := rules.NewStore(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Failed to create rule store: %v", err)
+	}
+	store.AddRule(context.Background(), "Does the document mention a termination clause for the contract?", true, "", "org-b")
+
+	suggester := NewRuleSuggester(store, embeddings.NewSeededMockEmbedder(256, 7))
+	suggester.SetThreshold(0.6)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/rules", strings.NewReader(`{"query":"Does the contract mention a termination clause?","active":true}`))
+	req = withUser(req, &database.User{ID: "admin"}, "org-a")
+	rec := httptest.NewRecorder()
+	HandleAddRule(rec, req, store, suggester)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		ID           int64         `json:"id"`
+		RelatedRules []RelatedRule `json:"related_rules"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.RelatedRules) != 0 {
+		t.Errorf("Expected no suggestions from another organization's rules, got %+v", resp.RelatedRules)
+	}
+	if rule, err := store.GetRule(resp.ID, "org-a"); err != nil || rule == nil {
+		t.Errorf("Expected the rule to be created under org-a, got %v, %v", rule, err)
+	}
+}
@@ -9,33 +9,70 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
+	"github.com/the-hive/internal/database"
 	"github.com/the-hive/internal/rules"
 )
 
-// HandleGetRules returns all rules
+// HandleGetRules handles GET /api/v1/rules, returning a page of the organization's rules
 func HandleGetRules(w http.ResponseWriter, r *http.Request, ruleStore *rules.Store) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	allRules, err := ruleStore.GetAllRules()
+	orgID, _ := r.Context().Value("organization_id").(string)
+	limit, offset := parsePagination(r)
+	page, total, err := ruleStore.ListRules(orgID, limit, offset)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to get rules: %v", err), http.StatusInternalServerError)
 		return
 	}
 
+	writePage(w, page, total, limit, offset)
+}
+
+// HandleGetRule handles GET /api/v1/rules/{id}, returning one of the organization's rules
+func HandleGetRule(w http.ResponseWriter, r *http.Request, ruleStore *rules.Store) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	orgID, _ := r.Context().Value("organization_id").(string)
+	rule, err := ruleStore.GetRule(id, orgID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get rule: %v", err), http.StatusInternalServerError)
+		return
+	}
+	// Another organization's rule is reported as missing rather than forbidden
+	if rule == nil {
+		http.Error(w, "Rule not found", http.StatusNotFound)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"rules": allRules,
-	})
+	json.NewEncoder(w).Encode(rule)
 }
 
-// HandleAddRule adds a new rule
-func HandleAddRule(w http.ResponseWriter, r *http.Request, ruleStore *rules.Store) {
+// relatedRulesTimeout bounds how long adding a rule waits for duplicate suggestions
+const relatedRulesTimeout = 2 * time.Second
+
+// AddRuleResponse is the created rule with any similar existing rules
+type AddRuleResponse struct {
+	*rules.Rule
+	RelatedRules []RelatedRule `json:"related_rules"`
+}
+
+// HandleAddRule adds a new rule and suggests similar existing rules (suggester may be nil)
+func HandleAddRule(w http.ResponseWriter, r *http.Request, ruleStore *rules.Store, suggester *RuleSuggester) {
 	log.Printf("[RULES] Received CreateRule request: Method=%s, Content-Type=%s", r.Method, r.Header.Get("Content-Type"))
 	
 	if r.Method != http.MethodPost {
@@ -47,6 +84,7 @@ func HandleAddRule(w http.ResponseWriter, r *http.Request, ruleStore *rules.Stor
 	var req struct {
 		Query  string `json:"query"`
 		Active bool   `json:"active"`
+		Scope  string `json:"scope"` // single_doc, cross_doc or auto (default)
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -62,6 +100,10 @@ func HandleAddRule(w http.ResponseWriter, r *http.Request, ruleStore *rules.Stor
 		http.Error(w, "query is required", http.StatusBadRequest)
 		return
 	}
+	if req.Scope != "" && !rules.ValidScope(req.Scope) {
+		http.Error(w, "scope must be single_doc, cross_doc or auto", http.StatusBadRequest)
+		return
+	}
 
 	log.Printf("[RULES] DEBUG: Attempting to insert rule into DB...")
 	
@@ -69,51 +111,38 @@ func HandleAddRule(w http.ResponseWriter, r *http.Request, ruleStore *rules.Stor
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 	
-	// Retry logic: Try up to 3 times for busy/locked errors
-	var rule *rules.Rule
-	var err error
-	maxRetries := 3
-	retryDelay := 500 * time.Millisecond
-	
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		rule, err = ruleStore.AddRule(ctx, req.Query, req.Active)
-		
-		if err == nil {
-			// Success!
-			break
-		}
-		
-		// Check if error is due to database being busy/locked
-		errStr := err.Error()
-		isBusy := strings.Contains(strings.ToLower(errStr), "busy") || 
-		          strings.Contains(strings.ToLower(errStr), "locked") ||
-		          strings.Contains(strings.ToLower(errStr), "database is locked")
-		
-		if !isBusy || attempt == maxRetries {
-			// Not a busy error, or we've exhausted retries
-			break
-		}
-		
-		// Log retry attempt
-		log.Printf("[RULES] Database busy (attempt %d/%d), retrying in %v...", attempt, maxRetries, retryDelay)
-		time.Sleep(retryDelay)
-	}
-	
+	// Busy/locked retries happen in the store
+	orgID, _ := r.Context().Value("organization_id").(string)
+	rule, err := ruleStore.AddRule(ctx, req.Query, req.Active, req.Scope, orgID)
 	if err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			log.Printf("[RULES] Database operation timed out after 5 seconds")
+		if ctx.Err() == context.DeadlineExceeded || database.IsBusyError(err) {
+			log.Printf("[RULES] Database busy or timed out: %v", err)
 			http.Error(w, "Database Busy", http.StatusServiceUnavailable)
 			return
 		}
-		log.Printf("[RULES] Failed to add rule to database after %d attempts: %v", maxRetries, err)
+		log.Printf("[RULES] Failed to add rule to database: %v", err)
 		http.Error(w, fmt.Sprintf("Failed to add rule: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	log.Printf("[RULES] DEBUG: Rule insert successful")
 	log.Printf("[RULES] Rule created successfully with ID=%d, returning JSON", rule.ID)
+
+	// Suggestions are best effort; the rule is already created
+	response := AddRuleResponse{Rule: rule, RelatedRules: []RelatedRule{}}
+	if suggester != nil {
+		suggestCtx, suggestCancel := context.WithTimeout(r.Context(), relatedRulesTimeout)
+		related, err := suggester.Related(suggestCtx, rule, orgID)
+		suggestCancel()
+		if err != nil {
+			log.Printf("[RULES] Failed to find related rules for rule %d: %v", rule.ID, err)
+		} else if len(related) > 0 {
+			response.RelatedRules = related
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(rule); err != nil {
+	if err := json.NewEncoder(w).Encode(response); err != nil {
 		log.Printf("[RULES] Failed to encode JSON response: %v", err)
 	}
 	log.Printf("[RULES] Response sent successfully")
@@ -142,43 +171,42 @@ func HandleUpdateRule(w http.ResponseWriter, r *http.Request, ruleStore *rules.S
 	var req struct {
 		Query  string `json:"query"`
 		Active bool   `json:"active"`
+		Scope  string `json:"scope"` // Empty keeps the rule's current scope
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
 		return
 	}
+	if req.Scope != "" && !rules.ValidScope(req.Scope) {
+		http.Error(w, "scope must be single_doc, cross_doc or auto", http.StatusBadRequest)
+		return
+	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 	
-	// If query is empty, fetch the existing rule to preserve the query text
-	var query string
-	if req.Query == "" {
-		// Get existing rule to preserve query
-		allRules, err := ruleStore.GetAllRules()
+	// If query or scope is empty, fetch the existing rule to preserve it
+	query, scope := req.Query, req.Scope
+	if query == "" || scope == "" {
+		rule, err := ruleStore.GetRule(id)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Failed to get existing rule: %v", err), http.StatusInternalServerError)
 			return
 		}
-		// Find the rule with matching ID
-		found := false
-		for _, rule := range allRules {
-			if rule.ID == id {
-				query = rule.Query
-				found = true
-				break
-			}
-		}
-		if !found {
+		if rule == nil {
 			http.Error(w, "Rule not found", http.StatusNotFound)
 			return
 		}
-	} else {
-		query = req.Query
+		if query == "" {
+			query = rule.Query
+		}
+		if scope == "" {
+			scope = rule.Scope
+		}
 	}
 	
-	if err := ruleStore.UpdateRule(ctx, id, query, req.Active); err != nil {
+	if err := ruleStore.UpdateRule(ctx, id, query, req.Active, scope); err != nil {
 		http.Error(w, fmt.Sprintf("Failed to update rule: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/rules"
+)
+
+func TestHandleGetRule(t *testing.T) {
+	ruleStore, err := rules.NewStore(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Failed to create rule store: %v", err)
+	}
+	ownRule, err := ruleStore.AddRule(context.Background(), "Does the contract allow early termination?", true, "", "org-a")
+	if err != nil {
+		t.Fatalf("Failed to add rule: %v", err)
+	}
+	otherRule, err := ruleStore.AddRule(context.Background(), "Another organization's rule", true, "", "org-b")
+	if err != nil {
+		t.Fatalf("Failed to add rule: %v", err)
+	}
+
+	getRule := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/rules/"+id, nil)
+		req.SetPathValue("id", id)
+		rec := httptest.NewRecorder()
+		HandleGetRule(rec, withUser(req, &database.User{ID: "u1"}, "org-a"), ruleStore)
+		return rec
+	}
+
+	rec := getRule(fmt.Sprint(ownRule.ID))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 for an existing rule, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var rule rules.Rule
+	if err := json.NewDecoder(rec.Body).Decode(&rule); err != nil {
+		t.Fatalf("Failed to decode rule: %v", err)
+	}
+	if rule.ID != ownRule.ID || rule.Query != ownRule.Query || !rule.Active {
+		t.Errorf("Expected %+v, got %+v", ownRule, rule)
+	}
+
+	if rec := getRule("9999"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a non-existent rule, got %d", rec.Code)
+	}
+	if rec := getRule(fmt.Sprint(otherRule.ID)); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for another organization's rule, got %d", rec.Code)
+	}
+	if rec := getRule("abc"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid id, got %d", rec.Code)
+	}
+}
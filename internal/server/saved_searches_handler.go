@@ -0,0 +1,278 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/the-hive/internal/database"
+)
+
+// savedSearchFilterOverfetch widens the vector search when filters are set so filtering still fills top_k
+const savedSearchFilterOverfetch = 3
+
+// SavedSearchRequest represents the create/update payload for a saved search
+type SavedSearchRequest struct {
+	Name    string            `json:"name"`
+	Query   string            `json:"query"`
+	TopK    int               `json:"top_k"`
+	Filters map[string]string `json:"filters,omitempty"`
+	Shared  bool              `json:"shared"`
+}
+
+// SavedSearchHandler handles saved search CRUD and execution
+type SavedSearchHandler struct {
+	store         *database.SavedSearchStore
+	searchHandler *SearchHandler
+}
+
+// NewSavedSearchHandler creates a new saved search handler
+func NewSavedSearchHandler(store *database.SavedSearchStore, searchHandler *SearchHandler) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		store:         store,
+		searchHandler: searchHandler,
+	}
+}
+
+// HandleSavedSearches handles GET (list) and POST (create) on /api/v1/saved-searches
+func (h *SavedSearchHandler) HandleSavedSearches(w http.ResponseWriter, r *http.Request) {
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		searches, err := h.store.ListSavedSearches(orgID, dbUser.ID)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"saved_searches": searches,
+			"count":          len(searches),
+		})
+	case http.MethodPost:
+		req, ok := decodeSavedSearchRequest(w, r, h.searchHandler.MaxTopK())
+		if !ok {
+			return
+		}
+		created, err := h.store.CreateSavedSearch(&database.SavedSearch{
+			Name:           req.Name,
+			Query:          req.Query,
+			TopK:           req.TopK,
+			Filters:        req.Filters,
+			OrganizationID: orgID,
+			OwnerID:        dbUser.ID,
+			Shared:         req.Shared,
+		})
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(created)
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// HandleSavedSearch handles GET, PUT and DELETE on /api/v1/saved-searches/{id}
+func (h *SavedSearchHandler) HandleSavedSearch(w http.ResponseWriter, r *http.Request) {
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid saved search id")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		search, err := h.store.GetSavedSearch(id, orgID, dbUser.ID)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if search == nil {
+			writeJSONError(w, http.StatusNotFound, "saved search not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(search)
+	case http.MethodPut:
+		req, ok := decodeSavedSearchRequest(w, r, h.searchHandler.MaxTopK())
+		if !ok {
+			return
+		}
+		search := &database.SavedSearch{
+			ID:             id,
+			Name:           req.Name,
+			Query:          req.Query,
+			TopK:           req.TopK,
+			Filters:        req.Filters,
+			OrganizationID: orgID,
+			OwnerID:        dbUser.ID,
+			Shared:         req.Shared,
+		}
+		if err := h.store.UpdateSavedSearch(search); err != nil {
+			if err == sql.ErrNoRows {
+				writeJSONError(w, http.StatusNotFound, "saved search not found")
+				return
+			}
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]bool{"success": true})
+	case http.MethodDelete:
+		if err := h.store.DeleteSavedSearch(id, orgID, dbUser.ID); err != nil {
+			if err == sql.ErrNoRows {
+				writeJSONError(w, http.StatusNotFound, "saved search not found")
+				return
+			}
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]bool{"success": true})
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// HandleExecuteSavedSearch handles POST /api/v1/saved-searches/{id}/execute
+func (h *SavedSearchHandler) HandleExecuteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	dbUser, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid saved search id")
+		return
+	}
+
+	search, err := h.store.GetSavedSearch(id, orgID, dbUser.ID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if search == nil {
+		writeJSONError(w, http.StatusNotFound, "saved search not found")
+		return
+	}
+
+	// Searches saved before the maximum was lowered are capped too
+	topK := clampTopK(search.TopK, defaultSearchTopK, h.searchHandler.MaxTopK())
+	searchTopK := topK
+	if len(search.Filters) > 0 {
+		searchTopK = topK * savedSearchFilterOverfetch
+	}
+
+	results, err := h.searchHandler.Search(r.Context(), search.Query, searchTopK, orgID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	matches := make([]SearchMatch, 0, len(results))
+	for _, match := range results {
+		if !matchesFilters(match.Metadata, search.Filters) {
+			continue
+		}
+		matches = append(matches, match)
+		if len(matches) == topK {
+			break
+		}
+	}
+
+	if h.searchHandler.auditLogStore != nil {
+		clientIP := getClientIP(r)
+		details := fmt.Sprintf("Client [%s] ran saved search [%s] for [%s]", clientIP, search.Name, search.Query)
+		if err := h.searchHandler.auditLogStore.LogAction(clientIP, database.AuditActionSearch, details, orgID); err != nil {
+			log.Printf("Failed to log saved search audit entry: %v", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SearchResponse{
+		Matches: matches,
+		Count:   len(matches),
+		TopK:    topK,
+	})
+}
+
+// decodeSavedSearchRequest decodes and validates a saved search payload, writing an error on failure.
+// top_k is defaulted and capped at maxTopK.
+func decodeSavedSearchRequest(w http.ResponseWriter, r *http.Request, maxTopK int) (*SavedSearchRequest, bool) {
+	var req SavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+		return nil, false
+	}
+	if req.Name == "" || req.Query == "" {
+		writeJSONError(w, http.StatusBadRequest, "name and query are required")
+		return nil, false
+	}
+	req.TopK = clampTopK(req.TopK, defaultSearchTopK, maxTopK)
+	return &req, true
+}
+
+// matchesFilters reports whether every filter key has the expected value in the metadata
+func matchesFilters(metadata map[string]string, filters map[string]string) bool {
+	for key, value := range filters {
+		if metadata[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// requireUserAndOrg extracts the authenticated user and organization from the request context
+// Writes an error response and returns false if the user is missing
+func requireUserAndOrg(w http.ResponseWriter, r *http.Request) (*database.User, string, bool) {
+	user := r.Context().Value("user")
+	if user == nil {
+		writeJSONError(w, http.StatusUnauthorized, "not authenticated")
+		return nil, "", false
+	}
+
+	dbUser, ok := user.(*database.User)
+	if !ok {
+		writeJSONError(w, http.StatusInternalServerError, "invalid user type")
+		return nil, "", false
+	}
+
+	orgID := ""
+	if orgIDVal := r.Context().Value("organization_id"); orgIDVal != nil {
+		if orgIDStr, ok := orgIDVal.(string); ok {
+			orgID = orgIDStr
+		}
+	}
+
+	return dbUser, orgID, true
+}
+
+// writeJSONError writes a JSON error response with the given status code
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/vectordb"
+)
+
+func TestSavedSearches_SaveListExecute(t *testing.T) {
+	store, err := database.NewSavedSearchStore(newTestDB(t))
+	if err != nil {
+		t.Fatalf("NewSavedSearchStore failed: %v", err)
+	}
+
+	vdb := &fakeVectorDB{matches: []vectordb.Match{
+		{ID: "c1", DocumentID: "policy.pdf", Score: 0.9, Metadata: map[string]string{"content": "Refunds within 30 days", "filetype": ".pdf"}},
+		{ID: "c2", DocumentID: "notes.txt", Score: 0.8, Metadata: map[string]string{"content": "Refund notes", "filetype": ".txt"}},
+	}}
+	handler := NewSavedSearchHandler(store, NewSearchHandler(vdb, embeddings.NewMockEmbedder(8), nil))
+
+	owner := &database.User{ID: "user-1", OrganizationID: "org-a"}
+	colleague := &database.User{ID: "user-2", OrganizationID: "org-a"}
+	outsider := &database.User{ID: "user-3", OrganizationID: "org-b"}
+
+	// Save a private search with a filetype filter
+	body := `{"name":"Refund PDFs","query":"refund policy","top_k":5,"filters":{"filetype":".pdf"}}`
+	rec := httptest.NewRecorder()
+	handler.HandleSavedSearches(rec, withUser(httptest.NewRequest(http.MethodPost, "/api/v1/saved-searches", strings.NewReader(body)), owner, "org-a"))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created database.SavedSearch
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatalf("Failed to decode created search: %v", err)
+	}
+
+	// Owner sees it; a colleague does not until it is shared; another org never does
+	listCount := func(user *database.User, orgID string) int {
+		rec := httptest.NewRecorder()
+		handler.HandleSavedSearches(rec, withUser(httptest.NewRequest(http.MethodGet, "/api/v1/saved-searches", nil), user, orgID))
+		var resp struct {
+			Count int `json:"count"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode list: %v", err)
+		}
+		return resp.Count
+	}
+	if got := listCount(owner, "org-a"); got != 1 {
+		t.Errorf("Expected owner to see 1 saved search, got %d", got)
+	}
+	if got := listCount(colleague, "org-a"); got != 0 {
+		t.Errorf("Expected colleague to see 0 private searches, got %d", got)
+	}
+
+	created.Shared = true
+	if err := store.UpdateSavedSearch(&created); err != nil {
+		t.Fatalf("UpdateSavedSearch failed: %v", err)
+	}
+	if got := listCount(colleague, "org-a"); got != 1 {
+		t.Errorf("Expected colleague to see shared search, got %d", got)
+	}
+	if got := listCount(outsider, "org-b"); got != 0 {
+		t.Errorf("Expected other org to see 0 searches, got %d", got)
+	}
+
+	// Execute by id: results are scoped to the org and filtered by metadata
+	id := fmt.Sprintf("%d", created.ID)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/saved-searches/"+id+"/execute", nil)
+	req.SetPathValue("id", id)
+	rec = httptest.NewRecorder()
+	handler.HandleExecuteSavedSearch(rec, withUser(req, colleague, "org-a"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp SearchResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode execute response: %v", err)
+	}
+	if resp.Count != 1 || resp.Matches[0].DocumentID != "policy.pdf" {
+		t.Errorf("Expected only policy.pdf after filtering, got %+v", resp.Matches)
+	}
+	if vdb.searchOrgID != "org-a" {
+		t.Errorf("Expected search scoped to org-a, got %q", vdb.searchOrgID)
+	}
+
+	// Another org cannot execute it
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/saved-searches/"+id+"/execute", nil)
+	req.SetPathValue("id", id)
+	rec = httptest.NewRecorder()
+	handler.HandleExecuteSavedSearch(rec, withUser(req, outsider, "org-b"))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for other org, got %d", rec.Code)
+	}
+
+	// Only the owner can delete
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/saved-searches/"+id, nil)
+	req.SetPathValue("id", id)
+	rec = httptest.NewRecorder()
+	handler.HandleSavedSearch(rec, withUser(req, colleague, "org-a"))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 when non-owner deletes, got %d", rec.Code)
+	}
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/saved-searches/"+id, nil)
+	req.SetPathValue("id", id)
+	rec = httptest.NewRecorder()
+	handler.HandleSavedSearch(rec, withUser(req, owner, "org-a"))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 when owner deletes, got %d", rec.Code)
+	}
+}
@@ -3,11 +3,14 @@
 package server
 
 import (
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/the-hive/internal/ai"
@@ -16,16 +19,39 @@ import (
 	"github.com/the-hive/internal/vectordb"
 )
 
+// filterOverfetch is how many extra candidates are fetched per result when filtering by payload fields in memory
+const filterOverfetch = 5
+
+const (
+	// defaultSearchTopK is used when a search doesn't ask for a result count
+	defaultSearchTopK = 3
+	// DefaultMaxSearchTopK caps the results one search may request (override with SEARCH_MAX_TOP_K)
+	DefaultMaxSearchTopK = 50
+)
+
+// clampTopK returns defaultTopK for a missing or negative topK and caps it at maxTopK
+func clampTopK(topK, defaultTopK, maxTopK int) int {
+	if topK <= 0 {
+		topK = defaultTopK
+	}
+	if maxTopK > 0 && topK > maxTopK {
+		topK = maxTopK
+	}
+	return topK
+}
+
 // SearchRequest represents the search request payload
 type SearchRequest struct {
-	Query string `json:"query"`
-	TopK  int    `json:"top_k"`
+	Query    string `json:"query"`
+	TopK     int    `json:"top_k"`              // Defaults to 3; capped at the server's maximum
+	Language string `json:"language,omitempty"` // Optional language code (e.g. "de") to restrict results to
 }
 
 // SearchResponse represents the search response
 type SearchResponse struct {
 	Matches []SearchMatch `json:"matches"`
 	Count   int           `json:"count"`
+	TopK    int           `json:"top_k"` // The result count actually searched for, after clamping
 }
 
 // SearchMatch represents a single search result
@@ -35,13 +61,18 @@ type SearchMatch struct {
 	Content    string            `json:"content"`
 	Score      float32           `json:"score"`
 	Metadata   map[string]string `json:"metadata"`
+	Summary    string            `json:"document_summary,omitempty"` // AI-generated summary of the whole document, if available
+	Language   string            `json:"language,omitempty"`         // Detected language of the document
 }
 
 // SearchHandler holds dependencies for the search handler
 type SearchHandler struct {
-	vectorDB      vectordb.VectorDB
-	embedder      embeddings.Embedder
-	auditLogStore *database.AuditLogStore
+	vectorDB       vectordb.VectorDB
+	embedder       embeddings.Embedder
+	auditLogStore  *database.AuditLogStore
+	queryValidator *QueryValidator
+	summaryStore   *database.DocumentSummaryStore
+	maxTopK        int // Upper bound on the results one search may request
 }
 
 // NewSearchHandler creates a new search handler with dependencies
@@ -50,85 +81,265 @@ func NewSearchHandler(vectorDB vectordb.VectorDB, embedder embeddings.Embedder,
 		vectorDB:      vectorDB,
 		embedder:      embedder,
 		auditLogStore: auditLogStore,
+		maxTopK:       DefaultMaxSearchTopK,
+	}
+}
+
+// SetMaxTopK sets the most results one search may request
+func (h *SearchHandler) SetMaxTopK(maxTopK int) {
+	if maxTopK > 0 {
+		h.maxTopK = maxTopK
 	}
 }
 
-// HandleSearch handles POST /api/v1/search requests
+// MaxTopK returns the most results one search may request
+func (h *SearchHandler) MaxTopK() int {
+	return h.maxTopK
+}
+
+// SetQueryValidator sets the validator used to reject degenerate queries before embedding
+func (h *SearchHandler) SetQueryValidator(validator *QueryValidator) {
+	h.queryValidator = validator
+}
+
+// SetSummaryStore sets the store used to attach document summaries to search results
+func (h *SearchHandler) SetSummaryStore(store *database.DocumentSummaryStore) {
+	h.summaryStore = store
+}
+
+// HandleSearch handles POST /api/v1/search requests.
+// Results are JSON by default, or CSV with "Accept: text/csv"; errors are always JSON.
 func (h *SearchHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusMethodNotAllowed)
-		json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	contentType, ok := requireAcceptable(w, r, contentTypeJSON, contentTypeCSV)
+	if !ok {
 		return
 	}
 
 	var req SearchRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("invalid JSON: %v", err)})
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %v", err))
 		return
 	}
 
 	if req.Query == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "query is required"})
+		writeJSONError(w, http.StatusBadRequest, "query is required")
+		return
+	}
+
+	if h.queryValidator != nil {
+		if err := h.queryValidator.Validate(req.Query); err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	// Default to top 3 if not specified, and never more than the configured maximum
+	req.TopK = clampTopK(req.TopK, defaultSearchTopK, h.maxTopK)
+
+	// Get organization ID from context
+	orgID := ""
+	if orgIDVal := r.Context().Value("organization_id"); orgIDVal != nil {
+		if orgIDStr, ok := orgIDVal.(string); ok {
+			orgID = orgIDStr
+		}
+	}
+
+	results, err := h.SearchInLanguage(r.Context(), req.Query, req.TopK, orgID, strings.ToLower(strings.TrimSpace(req.Language)))
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := SearchResponse{
+		Matches: results,
+		Count:   len(results),
+		TopK:    req.TopK,
+	}
+
+	// Log audit entry
+	if h.auditLogStore != nil {
+		clientIP := getClientIP(r)
+		// Get organization ID from context (already retrieved above)
+		details := fmt.Sprintf("Client [%s] searched for [%s]", clientIP, req.Query)
+		if err := h.auditLogStore.LogAction(clientIP, database.AuditActionSearch, details, orgID); err != nil {
+			log.Printf("Failed to log search audit entry: %v", err)
+		}
+	}
+
+	// Return results
+	if contentType == contentTypeCSV {
+		writeSearchCSV(w, results)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// DocumentSearchResponse is the response of a search within one document
+type DocumentSearchResponse struct {
+	DocumentID string                `json:"document_id"`
+	Matches    []DocumentSearchMatch `json:"matches"`
+	Count      int                   `json:"count"`
+	TopK       int                   `json:"top_k"`
+}
+
+// DocumentSearchMatch is a chunk of the searched document, ranked by similarity to the query
+type DocumentSearchMatch struct {
+	ChunkID    string  `json:"chunk_id"`
+	ChunkIndex int     `json:"chunk_index"`
+	Content    string  `json:"content"`
+	Score      float32 `json:"score"`
+}
+
+// HandleDocumentSearch handles POST /api/v1/documents/{id}/search, a search restricted to one document's chunks
+func (h *SearchHandler) HandleDocumentSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
-	// Default to top 3 if not specified
-	if req.TopK <= 0 {
-		req.TopK = 3
+	orgID, _ := r.Context().Value("organization_id").(string)
+	if orgID == "" {
+		writeJSONError(w, http.StatusForbidden, "organization required")
+		return
+	}
+	documentID := r.PathValue("id")
+	if documentID == "" {
+		writeJSONError(w, http.StatusBadRequest, "document id is required")
+		return
 	}
 
-	ctx := r.Context()
+	var req SearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %v", err))
+		return
+	}
+	if req.Query == "" {
+		writeJSONError(w, http.StatusBadRequest, "query is required")
+		return
+	}
+	if h.queryValidator != nil {
+		if err := h.queryValidator.Validate(req.Query); err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	req.TopK = clampTopK(req.TopK, defaultSearchTopK, h.maxTopK)
 
+	results, err := h.SearchDocument(r.Context(), req.Query, req.TopK, orgID, documentID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	matches := make([]DocumentSearchMatch, 0, len(results))
+	for _, result := range results {
+		chunkIndex, _ := strconv.Atoi(result.Metadata["chunk_index"])
+		matches = append(matches, DocumentSearchMatch{
+			ChunkID:    result.ChunkID,
+			ChunkIndex: chunkIndex,
+			Content:    result.Content,
+			Score:      result.Score,
+		})
+	}
+
+	if h.auditLogStore != nil {
+		clientIP := getClientIP(r)
+		details := fmt.Sprintf("Client [%s] searched document [%s] for [%s]", clientIP, documentID, req.Query)
+		if err := h.auditLogStore.LogAction(clientIP, database.AuditActionSearch, details, orgID); err != nil {
+			log.Printf("Failed to log document search audit entry: %v", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(DocumentSearchResponse{
+		DocumentID: documentID,
+		Matches:    matches,
+		Count:      len(matches),
+		TopK:       req.TopK,
+	})
+}
+
+// writeSearchCSV writes search results as CSV, one row per matching chunk
+func writeSearchCSV(w http.ResponseWriter, results []SearchMatch) {
+	w.Header().Set("Content-Type", contentTypeCSV+"; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"chunk_id", "document_id", "filename", "score", "language", "content", "document_summary"})
+	for _, result := range results {
+		writer.Write([]string{
+			result.ChunkID,
+			result.DocumentID,
+			result.Metadata["filename"],
+			strconv.FormatFloat(float64(result.Score), 'f', 4, 32),
+			result.Language,
+			result.Content,
+			result.Summary,
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("Failed to write search results as CSV: %v", err)
+	}
+}
+
+// Search embeds the query and returns the top matches for the organization
+func (h *SearchHandler) Search(ctx context.Context, query string, topK int, orgID string) ([]SearchMatch, error) {
+	return h.SearchInLanguage(ctx, query, topK, orgID, "")
+}
+
+// SearchInLanguage is Search restricted to documents detected as the given language; an empty language searches all
+func (h *SearchHandler) SearchInLanguage(ctx context.Context, query string, topK int, orgID, language string) ([]SearchMatch, error) {
+	var filters map[string]string
+	if language != "" {
+		filters = map[string]string{"language": language}
+	}
+	return h.search(ctx, query, topK, orgID, filters)
+}
+
+// SearchDocument is Search restricted to the chunks of one document
+func (h *SearchHandler) SearchDocument(ctx context.Context, query string, topK int, orgID, documentID string) ([]SearchMatch, error) {
+	return h.search(ctx, query, topK, orgID, map[string]string{"document_id": documentID})
+}
+
+// search embeds the query and returns the top matches for the organization whose payload fields equal filters
+func (h *SearchHandler) search(ctx context.Context, query string, topK int, orgID string, filters map[string]string) ([]SearchMatch, error) {
 	// Generate query embedding
 	var queryVector []float32
 	var err error
 
 	// Try using the embedder if available, otherwise use ai.GenerateEmbedding
 	if h.embedder != nil {
-		queryVector, err = h.embedder.EmbedText(ctx, req.Query)
+		queryVector, err = h.embedder.EmbedText(ctx, query)
 		if err != nil {
 			log.Printf("Failed to generate embedding with embedder: %v, falling back to ai.GenerateEmbedding", err)
-			queryVector, err = ai.GenerateEmbedding(req.Query)
+			queryVector, err = ai.GenerateEmbedding(query)
 		}
 	} else {
-		queryVector, err = ai.GenerateEmbedding(req.Query)
+		queryVector, err = ai.GenerateEmbedding(query)
 	}
 
 	if err != nil {
 		log.Printf("Failed to generate query embedding: %v", err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("failed to generate embedding: %v", err)})
-		return
+		return nil, fmt.Errorf("failed to generate embedding: %v", err)
 	}
 
-	// Get organization ID from context
-	orgID := ""
-	if orgIDVal := r.Context().Value("organization_id"); orgIDVal != nil {
-		if orgIDStr, ok := orgIDVal.(string); ok {
-			orgID = orgIDStr
-		}
-	}
 	// Search in Qdrant
-	matches, err := h.vectorDB.Search(ctx, queryVector, req.TopK, orgID)
+	matches, err := h.searchVectors(ctx, queryVector, topK, orgID, filters)
 	if err != nil {
 		log.Printf("Failed to search Qdrant: %v", err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("search failed: %v", err)})
-		return
+		return nil, fmt.Errorf("search failed: %v", err)
 	}
 
 	// Convert matches to response format
-	response := SearchResponse{
-		Matches: make([]SearchMatch, 0, len(matches)),
-		Count:   len(matches),
-	}
+	results := make([]SearchMatch, 0, len(matches))
 
 	for _, match := range matches {
 		// Extract content from metadata
@@ -164,29 +375,78 @@ func (h *SearchHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
 			metadata["tags_list"] = tagsStr
 		}
 
-		response.Matches = append(response.Matches, SearchMatch{
+		results = append(results, SearchMatch{
 			ChunkID:    match.ID,
 			DocumentID: match.DocumentID,
 			Content:    content,
 			Score:      match.Score,
 			Metadata:   metadata,
+			Language:   match.Metadata["language"],
 		})
 	}
 
-	// Log audit entry
-	if h.auditLogStore != nil {
-		clientIP := getClientIP(r)
-		// Get organization ID from context (already retrieved above)
-		details := fmt.Sprintf("Client [%s] searched for [%s]", clientIP, req.Query)
-		if err := h.auditLogStore.LogAction(clientIP, database.AuditActionSearch, details, orgID); err != nil {
-			log.Printf("Failed to log search audit entry: %v", err)
+	h.attachSummaries(results, orgID)
+
+	return results, nil
+}
+
+// searchVectors runs the vector search, filtering on payload fields (e.g. language) when filters are given.
+// Vector databases without payload filtering are over-fetched and filtered here.
+func (h *SearchHandler) searchVectors(ctx context.Context, queryVector []float32, topK int, orgID string, filters map[string]string) ([]vectordb.Match, error) {
+	if len(filters) == 0 {
+		return h.vectorDB.Search(ctx, queryVector, topK, orgID)
+	}
+	if searcher, ok := h.vectorDB.(vectordb.FilteredSearcher); ok {
+		return searcher.SearchFiltered(ctx, queryVector, topK, orgID, filters)
+	}
+
+	matches, err := h.vectorDB.Search(ctx, queryVector, topK*filterOverfetch, orgID)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]vectordb.Match, 0, topK)
+	for _, match := range matches {
+		if metadataMatches(match.Metadata, filters) {
+			filtered = append(filtered, match)
+			if len(filtered) == topK {
+				break
+			}
 		}
 	}
+	return filtered, nil
+}
 
-	// Return results
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+// metadataMatches reports whether the metadata has every field value in filters
+func metadataMatches(metadata, filters map[string]string) bool {
+	for key, value := range filters {
+		if metadata[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// attachSummaries fills in the document summary of each result from the summary store
+func (h *SearchHandler) attachSummaries(results []SearchMatch, orgID string) {
+	if h.summaryStore == nil || len(results) == 0 {
+		return
+	}
+	seen := make(map[string]bool)
+	documentIDs := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.DocumentID != "" && !seen[result.DocumentID] {
+			seen[result.DocumentID] = true
+			documentIDs = append(documentIDs, result.DocumentID)
+		}
+	}
+	summaries, err := h.summaryStore.GetSummaries(orgID, documentIDs)
+	if err != nil {
+		log.Printf("Failed to load document summaries: %v", err)
+		return
+	}
+	for i := range results {
+		results[i].Summary = summaries[results[i].DocumentID]
+	}
 }
 
 // getClientIP extracts the client IP address from the request
@@ -0,0 +1,196 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/vectordb"
+)
+
+func TestSearchInLanguage_FiltersByDetectedLanguage(t *testing.T) {
+	vdb := &fakeVectorDB{matches: []vectordb.Match{
+		{ID: "1", DocumentID: "handbook.pdf", Score: 0.9, Metadata: map[string]string{"content": "Holidays are approved by your manager.", "language": "en"}},
+		{ID: "2", DocumentID: "handbuch.pdf", Score: 0.8, Metadata: map[string]string{"content": "Urlaub wird vom Vorgesetzten genehmigt.", "language": "de"}},
+		{ID: "3", DocumentID: "policy.pdf", Score: 0.7, Metadata: map[string]string{"content": "Sick leave must be reported.", "language": "en"}},
+	}}
+	handler := NewSearchHandler(vdb, embeddings.NewMockEmbedder(8), nil)
+
+	results, err := handler.SearchInLanguage(context.Background(), "vacation approval", 1, "org-a", "de")
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].DocumentID != "handbuch.pdf" || results[0].Language != "de" {
+		t.Errorf("Expected only the German document, got %+v", results)
+	}
+	if vdb.searchTopK <= 1 {
+		t.Errorf("Expected the in-memory language filter to over-fetch candidates, got top_k %d", vdb.searchTopK)
+	}
+
+	all, err := handler.Search(context.Background(), "vacation approval", 3, "org-a")
+	if err != nil || len(all) != 3 {
+		t.Errorf("Expected unfiltered search to return every match, got %d, %v", len(all), err)
+	}
+}
+
+func TestHandleSearch_ErrorsAreJSON(t *testing.T) {
+	handler := NewSearchHandler(&fakeVectorDB{}, embeddings.NewMockEmbedder(8), nil)
+
+	cases := []struct {
+		name   string
+		method string
+		accept string
+		body   string
+		status int
+	}{
+		{"invalid JSON", http.MethodPost, "", "{not json", http.StatusBadRequest},
+		{"empty query", http.MethodPost, "application/json", `{"query": ""}`, http.StatusBadRequest},
+		{"empty query as CSV", http.MethodPost, "text/csv", `{"query": ""}`, http.StatusBadRequest},
+		{"wrong method", http.MethodGet, "", "", http.StatusMethodNotAllowed},
+		{"unacceptable type", http.MethodPost, "text/html", `{"query": "leave"}`, http.StatusNotAcceptable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/api/v1/search", strings.NewReader(tc.body))
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rec := httptest.NewRecorder()
+			handler.HandleSearch(rec, req)
+
+			if rec.Code != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, rec.Code)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Expected a JSON error, got Content-Type %q", ct)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] == "" {
+				t.Errorf("Expected a JSON body with an error message, got %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleSearch_CSVResults(t *testing.T) {
+	vdb := &fakeVectorDB{matches: []vectordb.Match{
+		{ID: "1", DocumentID: "doc-1", Score: 0.9, Metadata: map[string]string{"content": "Holidays, approved by your manager.", "filename": "handbook.pdf"}},
+	}}
+	handler := NewSearchHandler(vdb, embeddings.NewMockEmbedder(8), nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/search", strings.NewReader(`{"query": "holidays"}`))
+	req.Header.Set("Accept", "application/json;q=0.5, text/csv")
+	rec := httptest.NewRecorder()
+	handler.HandleSearch(rec, req)
+
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("Expected CSV results, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(records) != 2 || records[0][0] != "chunk_id" {
+		t.Fatalf("Expected a header and one row, got %v", records)
+	}
+	if records[1][1] != "doc-1" || records[1][2] != "handbook.pdf" || records[1][5] != "Holidays, approved by your manager." {
+		t.Errorf("Unexpected CSV row: %v", records[1])
+	}
+}
+
+func TestHandleAPINotFound_ReturnsJSON(t *testing.T) {
+	rec := httptest.NewRecorder()
+	HandleAPINotFound(rec, httptest.NewRequest(http.MethodGet, "/api/v1/nope", nil))
+
+	if rec.Code != http.StatusNotFound || rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected a JSON 404, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestHandleSearch_ClampsExcessiveTopK(t *testing.T) {
+	vdb := &fakeVectorDB{}
+	handler := NewSearchHandler(vdb, embeddings.NewMockEmbedder(8), nil)
+	handler.SetMaxTopK(20)
+
+	rec := httptest.NewRecorder()
+	handler.HandleSearch(rec, httptest.NewRequest(http.MethodPost, "/api/v1/search", strings.NewReader(`{"query": "holidays", "top_k": 100000}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Search failed: %d %s", rec.Code, rec.Body.String())
+	}
+
+	var resp SearchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.TopK != 20 {
+		t.Errorf("Expected the response to report top_k clamped to 20, got %d", resp.TopK)
+	}
+	if vdb.searchTopK != 20 {
+		t.Errorf("Expected the vector search to ask for 20 results, got %d", vdb.searchTopK)
+	}
+
+	if got := clampTopK(0, defaultSearchTopK, 20); got != defaultSearchTopK {
+		t.Errorf("Expected a missing top_k to default to %d, got %d", defaultSearchTopK, got)
+	}
+}
+
+func TestHandleDocumentSearch_OnlyMatchesTheDocument(t *testing.T) {
+	embedder := embeddings.NewMockEmbedder(8)
+	vdb := vectordb.NewMemoryVectorDB()
+	ctx := context.Background()
+	for _, chunk := range []struct{ id, documentID, orgID, index, content string }{
+		{"h-0", "handbook", "org-a", "0", "Welcome to the company."},
+		{"h-1", "handbook", "org-a", "1", "Vacation requests go to your manager."},
+		{"h-2", "handbook", "org-a", "2", "Expenses are reimbursed monthly."},
+		// The best match for the query, but in another document or organization
+		{"p-0", "policy", "org-a", "0", "Vacation approval"},
+		{"x-0", "handbook", "org-b", "0", "Vacation approval"},
+	} {
+		vector, _ := embedder.EmbedText(ctx, chunk.content)
+		vdb.Upsert(ctx, chunk.id, vector, map[string]string{"document_id": chunk.documentID, "organization_id": chunk.orgID, "chunk_index": chunk.index, "content": chunk.content})
+	}
+	handler := NewSearchHandler(vdb, embedder, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/documents/handbook/search", strings.NewReader(`{"query": "Vacation approval", "top_k": 2}`))
+	req.SetPathValue("id", "handbook")
+	rec := httptest.NewRecorder()
+	handler.HandleDocumentSearch(rec, withUser(req, nil, "org-a"))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp DocumentSearchResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.DocumentID != "handbook" || resp.Count != 2 || len(resp.Matches) != 2 {
+		t.Fatalf("Expected 2 matches in the handbook, got %+v", resp)
+	}
+	for _, match := range resp.Matches {
+		if !strings.HasPrefix(match.ChunkID, "h-") {
+			t.Errorf("Expected only the organization's handbook chunks, got %+v", match)
+		}
+		if want := match.ChunkID[len("h-"):]; strconv.Itoa(match.ChunkIndex) != want {
+			t.Errorf("Expected chunk %s to report chunk_index %s, got %d", match.ChunkID, want, match.ChunkIndex)
+		}
+	}
+	if resp.Matches[0].Score < resp.Matches[1].Score {
+		t.Errorf("Expected matches ranked by score, got %+v", resp.Matches)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/documents/handbook/search", strings.NewReader(`{"query": ""}`))
+	req.SetPathValue("id", "handbook")
+	rec = httptest.NewRecorder()
+	handler.HandleDocumentSearch(rec, withUser(req, nil, "org-a"))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an empty query, got %d", rec.Code)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 
 	"github.com/the-hive/internal/vectordb"
+	"github.com/the-hive/internal/worker"
 )
 
 // StatsResponse represents the system statistics
@@ -19,17 +20,27 @@ type StatsResponse struct {
 	CollectionName  string `json:"collection_name"`
 }
 
+// AnalystQueueStats is how full the analyst job queue is; a queue near capacity means ingests are
+// waiting for analysis and, past the enqueue timeout, jobs are dropped
+type AnalystQueueStats struct {
+	Depth    int `json:"depth"`
+	Capacity int `json:"capacity"`
+}
+
 // HandleStats returns system statistics
-func HandleStats(w http.ResponseWriter, r *http.Request, vectorDB vectordb.VectorDB, db *sql.DB) {
+func HandleStats(w http.ResponseWriter, r *http.Request, vectorDB vectordb.VectorDB, db *sql.DB, analystPool *worker.AnalystPool) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
 	stats := StatsResponse{
-		CollectionName: "the_hive",
+		CollectionName: vectordb.DefaultQdrantCollection,
 		DatabaseStatus: "unknown",
 	}
+	if namer, ok := vectorDB.(vectordb.CollectionNamer); ok {
+		stats.CollectionName = namer.CollectionName()
+	}
 
 	// Get vector count from Qdrant
 	if vectorDB != nil {
@@ -39,7 +50,7 @@ func HandleStats(w http.ResponseWriter, r *http.Request, vectorDB vectordb.Vecto
 			stats.VectorsInMemory = -1 // Error indicator
 		} else {
 			stats.VectorsInMemory = count
-			log.Printf("[DEBUG] Qdrant stats: %d vectors in collection 'the_hive'", count)
+			log.Printf("[DEBUG] Qdrant stats: %d vectors in collection '%s'", count, stats.CollectionName)
 		}
 	} else {
 		log.Printf("[WARN] Stats: vectorDB is nil, using mock or not initialized")
@@ -72,6 +83,10 @@ func HandleStats(w http.ResponseWriter, r *http.Request, vectorDB vectordb.Vecto
 		"database_status":   stats.DatabaseStatus,
 		"trial_days":        trialDays,
 	}
+	if analystPool != nil {
+		depth, capacity := analystPool.QueueDepth()
+		response["analyst_queue"] = AnalystQueueStats{Depth: depth, Capacity: capacity}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
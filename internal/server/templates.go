@@ -0,0 +1,219 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const baseTemplate = "base.html"
+
+// TemplateSet caches parsed page templates (each parsed together with base.html).
+// Templates are parsed once and reused; Reload re-reads them from the source so
+// edits take effect without a restart. In dev mode every render re-parses.
+type TemplateSet struct {
+	mu             sync.RWMutex
+	source         fs.FS
+	pages          map[string]*template.Template
+	reloadOnRender bool
+}
+
+// TemplateParseError reports the pages that failed to parse during a reload
+type TemplateParseError struct {
+	Errors map[string]string
+}
+
+func (e *TemplateParseError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+": "+e.Errors[name])
+	}
+	return "template parse failed: " + strings.Join(parts, "; ")
+}
+
+// NewTemplateSet creates a template set reading templates from the root of source
+func NewTemplateSet(source fs.FS) *TemplateSet {
+	return &TemplateSet{
+		source: source,
+		pages:  make(map[string]*template.Template),
+	}
+}
+
+// defaultTemplates serves the embedded templates unless UseTemplateDir is called
+var defaultTemplates = newEmbeddedTemplateSet()
+
+func newEmbeddedTemplateSet() *TemplateSet {
+	sub, err := fs.Sub(templatesFS, "templates")
+	if err != nil {
+		log.Fatalf("Failed to open embedded templates: %v", err)
+	}
+	return NewTemplateSet(sub)
+}
+
+// DefaultTemplates returns the template set used by the web handlers
+func DefaultTemplates() *TemplateSet {
+	return defaultTemplates
+}
+
+// UseTemplateDir reads templates from dir on disk instead of the embedded copies,
+// so a reload picks up edits. The directory is parsed up front and rejected if broken.
+func (t *TemplateSet) UseTemplateDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	candidate := NewTemplateSet(os.DirFS(dir))
+	if _, err := candidate.Reload(); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.source = candidate.source
+	t.pages = candidate.pages
+	t.mu.Unlock()
+	return nil
+}
+
+// SetReloadOnRender re-parses templates on every render (development only; parsing is not free)
+func (t *TemplateSet) SetReloadOnRender(enabled bool) {
+	t.mu.Lock()
+	t.reloadOnRender = enabled
+	t.mu.Unlock()
+}
+
+// Reload re-parses every page from the source and swaps them in together.
+// If any page fails to parse, the previously cached templates stay in use and a
+// *TemplateParseError describes the failures. It returns the number of pages loaded.
+func (t *TemplateSet) Reload() (int, error) {
+	t.mu.RLock()
+	source := t.source
+	t.mu.RUnlock()
+
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return 0, err
+	}
+
+	pages := make(map[string]*template.Template)
+	parseErrors := make(map[string]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || name == baseTemplate || path.Ext(name) != ".html" {
+			continue
+		}
+		tmpl, err := parsePage(source, name)
+		if err != nil {
+			parseErrors[name] = err.Error()
+			continue
+		}
+		pages[name] = tmpl
+	}
+	if len(parseErrors) > 0 {
+		return 0, &TemplateParseError{Errors: parseErrors}
+	}
+
+	t.mu.Lock()
+	t.pages = pages
+	t.mu.Unlock()
+	return len(pages), nil
+}
+
+// Render executes the named page within the base layout
+func (t *TemplateSet) Render(w http.ResponseWriter, tmplName string, data interface{}) error {
+	tmpl, err := t.lookup(tmplName)
+	if err != nil {
+		log.Printf("Failed to parse template %s: %v", tmplName, err)
+		return err
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.ExecuteTemplate(w, baseTemplate, data); err != nil {
+		log.Printf("Failed to execute template %s: %v", tmplName, err)
+		return err
+	}
+	return nil
+}
+
+// lookup returns the cached page, parsing it on first use (or every time in dev mode)
+func (t *TemplateSet) lookup(tmplName string) (*template.Template, error) {
+	t.mu.RLock()
+	tmpl, ok := t.pages[tmplName]
+	source, reloadOnRender := t.source, t.reloadOnRender
+	t.mu.RUnlock()
+	if ok && !reloadOnRender {
+		return tmpl, nil
+	}
+
+	parsed, err := parsePage(source, tmplName)
+	if err != nil {
+		// Keep serving the last good copy rather than failing the page
+		if ok {
+			log.Printf("Template %s failed to re-parse, serving cached copy: %v", tmplName, err)
+			return tmpl, nil
+		}
+		return nil, err
+	}
+
+	t.mu.Lock()
+	t.pages[tmplName] = parsed
+	t.mu.Unlock()
+	return parsed, nil
+}
+
+func parsePage(source fs.FS, tmplName string) (*template.Template, error) {
+	return template.ParseFS(source, baseTemplate, tmplName)
+}
+
+// HandleReloadTemplates handles POST /api/v1/admin/templates/reload
+// Parse failures are reported with 422 and the previous templates keep serving.
+func HandleReloadTemplates(w http.ResponseWriter, r *http.Request, templates *TemplateSet) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	count, err := templates.Reload()
+	w.Header().Set("Content-Type", "application/json")
+
+	var parseErr *TemplateParseError
+	if errors.As(err, &parseErr) {
+		log.Printf("Template reload rejected: %v", err)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"reloaded": false,
+			"errors":   parseErr.Errors,
+		})
+		return
+	}
+	if err != nil {
+		log.Printf("Template reload failed: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "failed to reload templates")
+		return
+	}
+
+	log.Printf("Reloaded %d templates", count)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"reloaded":  true,
+		"templates": count,
+	})
+}
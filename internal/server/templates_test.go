@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+}
+
+func renderPage(t *testing.T, templates *TemplateSet, name string) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	if err := templates.Render(rec, name, nil); err != nil {
+		t.Fatalf("Render %s failed: %v", name, err)
+	}
+	return rec.Body.String()
+}
+
+func TestTemplateSet_ReloadPicksUpChangedTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "base.html", `<html>{{template "content" .}}</html>`)
+	writeTemplate(t, dir, "index.html", `{{define "content"}}Welcome v1{{end}}`)
+
+	templates := NewTemplateSet(os.DirFS(dir))
+	if err := templates.UseTemplateDir(dir); err != nil {
+		t.Fatalf("UseTemplateDir failed: %v", err)
+	}
+	if body := renderPage(t, templates, "index.html"); !strings.Contains(body, "Welcome v1") {
+		t.Fatalf("Expected v1 content, got %q", body)
+	}
+
+	// Cached until reloaded
+	writeTemplate(t, dir, "index.html", `{{define "content"}}Welcome v2{{end}}`)
+	if body := renderPage(t, templates, "index.html"); !strings.Contains(body, "Welcome v1") {
+		t.Errorf("Expected the cached template before reload, got %q", body)
+	}
+
+	rec := httptest.NewRecorder()
+	HandleReloadTemplates(rec, httptest.NewRequest(http.MethodPost, "/api/v1/admin/templates/reload", nil), templates)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 from reload, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if body := renderPage(t, templates, "index.html"); !strings.Contains(body, "Welcome v2") {
+		t.Errorf("Expected v2 content after reload, got %q", body)
+	}
+
+	// A broken template is reported and the last good copy keeps serving
+	writeTemplate(t, dir, "index.html", `{{define "content"}}Broken {{if}}{{end}}`)
+	rec = httptest.NewRecorder()
+	HandleReloadTemplates(rec, httptest.NewRequest(http.MethodPost, "/api/v1/admin/templates/reload", nil), templates)
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), "index.html") {
+		t.Fatalf("Expected 422 naming index.html, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if body := renderPage(t, templates, "index.html"); !strings.Contains(body, "Welcome v2") {
+		t.Errorf("Expected the last good template after a failed reload, got %q", body)
+	}
+}
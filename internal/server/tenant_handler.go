@@ -4,9 +4,12 @@ package server
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
+	"time"
 
 	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/rules"
 )
 
 // HandleListOrganizations handles GET /api/v1/organizations
@@ -27,12 +30,33 @@ func HandleListOrganizations(w http.ResponseWriter, r *http.Request, orgStore *d
 		return
 	}
 
+	// Token totals are best effort; the organizations are listed without them if they can't be read
+	var tokens map[string]int64
+	if usageStore != nil {
+		tokens, err = usageStore.TokenTotalsSince(database.BillingPeriodStart(time.Now()))
+		if err != nil {
+			log.Printf("Failed to load token usage for organizations: %v", err)
+		}
+	}
+
+	summaries := make([]OrganizationSummary, 0, len(orgs))
+	for _, org := range orgs {
+		summaries = append(summaries, OrganizationSummary{Organization: org, TokensThisPeriod: tokens[org.ID]})
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(orgs)
+	json.NewEncoder(w).Encode(summaries)
+}
+
+// OrganizationSummary is an organization as listed for super admins, with its AI token usage
+type OrganizationSummary struct {
+	database.Organization
+	TokensThisPeriod int64 `json:"tokens_this_period"` // Prompt and completion tokens since the billing period (month, UTC) began
 }
 
 // HandleCreateOrganization handles POST /api/v1/organizations
-func HandleCreateOrganization(w http.ResponseWriter, r *http.Request, orgStore *database.OrganizationStore, userStore *database.UserStore) {
+// The new organization starts with the default rule template (see HandleDefaultRules).
+func HandleCreateOrganization(w http.ResponseWriter, r *http.Request, orgStore *database.OrganizationStore, userStore *database.UserStore, ruleStore *rules.Store) {
 	if r.Method != http.MethodPost {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusMethodNotAllowed)
@@ -59,12 +83,22 @@ func HandleCreateOrganization(w http.ResponseWriter, r *http.Request, orgStore *
 		return
 	}
 
+	// The organization exists either way; a failed seed leaves it without rules, which admins can add
+	if ruleStore != nil {
+		added, err := ruleStore.SeedDefaultRules(r.Context(), org.ID)
+		if err != nil {
+			log.Printf("Failed to seed default rules for organization %s: %v", org.ID, err)
+		} else {
+			log.Printf("Seeded %d default rule(s) for organization %s", added, org.ID)
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(org)
 }
 
 // HandleUpdateOrganization handles PUT /api/v1/organizations/{id}
-func HandleUpdateOrganization(w http.ResponseWriter, r *http.Request, orgStore *database.OrganizationStore) {
+func HandleUpdateOrganization(w http.ResponseWriter, r *http.Request, orgStore *database.OrganizationStore, auditLogStore *database.AuditLogStore) {
 	if r.Method != http.MethodPut && r.Method != http.MethodPatch {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusMethodNotAllowed)
@@ -91,7 +125,9 @@ func HandleUpdateOrganization(w http.ResponseWriter, r *http.Request, orgStore *
 	if req.SubscriptionStatus != "" {
 		subscriptionStatus = req.SubscriptionStatus
 	}
-	
+
+	previous, _ := orgStore.GetOrganizationByID(id)
+
 	err := orgStore.UpdateOrganization(id, req.Name, subscriptionStatus)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
@@ -108,6 +144,11 @@ func HandleUpdateOrganization(w http.ResponseWriter, r *http.Request, orgStore *
 		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
 	}
+	if previous != nil && org != nil {
+		auditConfigChanges(auditLogStore, r, id,
+			configChange{Setting: "name", Before: previous.Name, After: org.Name},
+			configChange{Setting: "subscription_status", Before: previous.SubscriptionStatus, After: org.SubscriptionStatus})
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(org)
@@ -4,12 +4,26 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
+	"log"
 	"net/http"
 
 	"github.com/the-hive/internal/database"
 )
 
-// HandleListUsers handles GET /api/v1/users
+// defaultUserRole is assigned to new users and invites that don't specify a role
+var defaultUserRole = database.RoleViewer
+
+// SetDefaultUserRole sets the role given to users created or invited without one
+func SetDefaultUserRole(role database.UserRole) error {
+	if !importableRoles[role] {
+		return fmt.Errorf("invalid default role: %q", role)
+	}
+	defaultUserRole = role
+	return nil
+}
+
+// HandleListUsers handles GET /api/v1/users, returning a page of the organization's users
 func HandleListUsers(w http.ResponseWriter, r *http.Request, userStore *database.UserStore) {
 	if r.Method != http.MethodGet {
 		w.Header().Set("Content-Type", "application/json")
@@ -42,15 +56,9 @@ func HandleListUsers(w http.ResponseWriter, r *http.Request, userStore *database
 			users = append(users, user)
 		}
 	}
-	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
-		return
-	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(users)
+	limit, offset := parsePagination(r)
+	writePage(w, pageSlice(users, limit, offset), len(users), limit, offset)
 }
 
 // HandleCreateUser handles POST /api/v1/users
@@ -85,7 +93,7 @@ func HandleCreateUser(w http.ResponseWriter, r *http.Request, userStore *databas
 	// Convert role string to UserRole
 	role := database.UserRole(req.Role)
 	if role == "" {
-		role = database.RoleViewer // Default role
+		role = defaultUserRole
 	}
 
 	// Create user
@@ -154,6 +162,13 @@ func HandleUpdateCurrentUserPassword(w http.ResponseWriter, r *http.Request, use
 		return
 	}
 
+	// The user has replaced their temporary password
+	if passwordFlagStore != nil {
+		if err := passwordFlagStore.ClearPasswordChange(dbUser.ID); err != nil {
+			log.Printf("Failed to clear password change flag for user %s: %v", dbUser.ID, err)
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]bool{"success": true})
 }
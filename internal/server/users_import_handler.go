@@ -0,0 +1,222 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"github.com/the-hive/internal/database"
+)
+
+const (
+	maxUserImportRows  = 1000
+	maxUserImportBytes = 5 << 20
+	minTempPasswordLen = 8
+)
+
+// User import row statuses
+const (
+	ImportCreated = "created"
+	ImportSkipped = "skipped"
+	ImportError   = "error"
+)
+
+// UserImportStore is the subset of the user store needed for CSV import
+type UserImportStore interface {
+	GetUserByEmail(email string) (*database.User, error)
+	// ImportUsers creates every user, flagged to change their password at next login, or none of them
+	ImportUsers(ctx context.Context, users []database.ImportedUser, orgID string) ([]*database.User, error)
+}
+
+// UserImportResult is the outcome for one CSV row
+type UserImportResult struct {
+	Row     int    `json:"row"` // 1-based line number in the CSV, header included
+	Email   string `json:"email"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	UserID  string `json:"user_id,omitempty"`
+}
+
+// importableRoles are the roles an org admin may assign via import
+var importableRoles = map[database.UserRole]bool{
+	database.RoleAdmin:  true,
+	database.RoleEditor: true,
+	database.RoleViewer: true,
+}
+
+// ImportUsersCSV creates users in orgID from CSV rows of email,role,temp_password
+// Every row is validated first; invalid rows are reported as errors and existing emails are skipped.
+// The valid rows are then created in one transaction: if any creation fails, none of them are.
+func ImportUsersCSV(ctx context.Context, r io.Reader, orgID string, users UserImportStore) ([]UserImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("CSV is empty")
+	}
+
+	// Skip the header row if present
+	first := 0
+	if len(records[0]) > 0 && strings.EqualFold(strings.TrimSpace(records[0][0]), "email") {
+		first = 1
+	}
+	if len(records)-first > maxUserImportRows {
+		return nil, fmt.Errorf("too many rows: %d (max %d)", len(records)-first, maxUserImportRows)
+	}
+
+	results := make([]UserImportResult, 0, len(records)-first)
+	var pending []database.ImportedUser
+	var pendingRows []int // Index in results of each pending user
+	seen := make(map[string]bool)
+
+	for i := first; i < len(records); i++ {
+		record := records[i]
+		result := UserImportResult{Row: i + 1}
+		if len(record) > 0 {
+			result.Email = strings.ToLower(strings.TrimSpace(record[0]))
+		}
+
+		role, password, validationErr := validateImportRow(record)
+		switch {
+		case validationErr != nil:
+			result.Status = ImportError
+			result.Message = validationErr.Error()
+		case seen[result.Email]:
+			result.Status = ImportSkipped
+			result.Message = "duplicate email in CSV"
+		default:
+			existing, err := users.GetUserByEmail(result.Email)
+			if err == nil && existing != nil {
+				result.Status = ImportSkipped
+				result.Message = "user already exists"
+			}
+		}
+		if result.Email != "" {
+			seen[result.Email] = true
+		}
+		results = append(results, result)
+
+		if result.Status == "" {
+			pending = append(pending, database.ImportedUser{Email: result.Email, Password: password, Role: role})
+			pendingRows = append(pendingRows, len(results)-1)
+		}
+	}
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	created, err := users.ImportUsers(ctx, pending, orgID)
+	if err != nil {
+		for _, i := range pendingRows {
+			results[i].Status = ImportError
+			results[i].Message = "import rolled back"
+		}
+		return results, fmt.Errorf("import rolled back: %w", err)
+	}
+	for n, i := range pendingRows {
+		results[i].Status = ImportCreated
+		results[i].UserID = created[n].ID
+	}
+
+	return results, nil
+}
+
+// validateImportRow checks a CSV record and returns its role and temporary password
+func validateImportRow(record []string) (database.UserRole, string, error) {
+	if len(record) < 3 {
+		return "", "", errors.New("expected email,role,temp_password")
+	}
+
+	email := strings.TrimSpace(record[0])
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return "", "", fmt.Errorf("invalid email: %q", email)
+	}
+
+	role := database.UserRole(strings.ToLower(strings.TrimSpace(record[1])))
+	if role == "" {
+		role = database.RoleViewer
+	}
+	if !importableRoles[role] {
+		return "", "", fmt.Errorf("invalid role: %q", record[1])
+	}
+
+	password := record[2]
+	if len(password) < minTempPasswordLen {
+		return "", "", fmt.Errorf("temp_password must be at least %d characters", minTempPasswordLen)
+	}
+
+	return role, password, nil
+}
+
+// HandleImportUsers handles POST /api/v1/users/import
+// Accepts text/csv (or a multipart "file" field) with columns email,role,temp_password, up to 5MB
+func HandleImportUsers(w http.ResponseWriter, r *http.Request, users UserImportStore) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	_, orgID, ok := requireUserAndOrg(w, r)
+	if !ok {
+		return
+	}
+
+	// Limit the body before the multipart form is parsed, since FormFile reads all of it
+	r.Body = http.MaxBytesReader(w, r.Body, maxUserImportBytes)
+	body := io.Reader(r.Body)
+	var tooLarge *http.MaxBytesError
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			if errors.As(err, &tooLarge) {
+				writeJSONError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("CSV is larger than %d bytes", maxUserImportBytes))
+				return
+			}
+			writeJSONError(w, http.StatusBadRequest, "missing file field")
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	results, err := ImportUsersCSV(r.Context(), body, orgID, users)
+	if err != nil && results == nil {
+		if errors.As(err, &tooLarge) {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("CSV is larger than %d bytes", maxUserImportBytes))
+			return
+		}
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	summary := map[string]int{ImportCreated: 0, ImportSkipped: 0, ImportError: 0}
+	for _, result := range results {
+		summary[result.Status]++
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	response := map[string]interface{}{
+		"results": results,
+		"summary": summary,
+	}
+	if err != nil {
+		response["error"] = err.Error()
+	}
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+)
+
+// fakeUserStore keeps users in memory
+type fakeUserStore struct {
+	users   map[string]*database.User
+	flagged map[string]bool
+	failOn  string
+	nextID  int
+}
+
+func newFakeUserStore(existing ...string) *fakeUserStore {
+	s := &fakeUserStore{users: make(map[string]*database.User), flagged: make(map[string]bool)}
+	for _, email := range existing {
+		s.CreateUser(email, "password123", database.RoleViewer, "org-a")
+	}
+	return s
+}
+
+func (s *fakeUserStore) GetUserByEmail(email string) (*database.User, error) {
+	return s.users[email], nil
+}
+
+func (s *fakeUserStore) CreateUser(email, password string, role database.UserRole, orgID string) (*database.User, error) {
+	if email == s.failOn {
+		return nil, errors.New("database is locked")
+	}
+	s.nextID++
+	user := &database.User{ID: fmt.Sprintf("user-%d", s.nextID), Email: email, Role: role, OrganizationID: orgID}
+	s.users[email] = user
+	return user, nil
+}
+
+func (s *fakeUserStore) DeleteUser(id string) error {
+	for email, user := range s.users {
+		if user.ID == id {
+			delete(s.users, email)
+		}
+	}
+	return nil
+}
+
+// ImportUsers creates all of the users or, like the database transaction, none of them
+func (s *fakeUserStore) ImportUsers(ctx context.Context, users []database.ImportedUser, orgID string) ([]*database.User, error) {
+	var created []*database.User
+	for _, u := range users {
+		if u.Email == s.failOn {
+			return nil, errors.New("database is locked")
+		}
+		s.nextID++
+		created = append(created, &database.User{ID: fmt.Sprintf("user-%d", s.nextID), Email: u.Email, Role: u.Role, OrganizationID: orgID})
+	}
+	for _, user := range created {
+		s.users[user.Email] = user
+		s.flagged[user.ID] = true
+	}
+	return created, nil
+}
+
+func TestHandleImportUsers(t *testing.T) {
+	users := newFakeUserStore("existing@acme.com")
+
+	csvBody := "email,role,temp_password\n" +
+		"alice@acme.com,editor,Welcome-2025\n" +
+		"bob@acme.com,owner,Welcome-2025\n" +
+		"existing@acme.com,viewer,Welcome-2025\n" +
+		"carol@acme.com,,Welcome-2025\n"
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/import", strings.NewReader(csvBody))
+	req.Header.Set("Content-Type", "text/csv")
+	req = withUser(req, &database.User{ID: "admin", Role: database.RoleAdmin}, "org-b")
+	rec := httptest.NewRecorder()
+	HandleImportUsers(rec, req, users)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Results []UserImportResult `json:"results"`
+		Summary map[string]int     `json:"summary"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	want := []string{ImportCreated, ImportError, ImportSkipped, ImportCreated}
+	if len(resp.Results) != len(want) {
+		t.Fatalf("Expected %d results, got %+v", len(want), resp.Results)
+	}
+	for i, status := range want {
+		if resp.Results[i].Status != status {
+			t.Errorf("Row %d: status %q, want %q (%s)", resp.Results[i].Row, resp.Results[i].Status, status, resp.Results[i].Message)
+		}
+	}
+	if resp.Results[1].Row != 3 || !strings.Contains(resp.Results[1].Message, "invalid role") {
+		t.Errorf("Expected the invalid role on line 3 to be reported, got %+v", resp.Results[1])
+	}
+	if resp.Summary[ImportCreated] != 2 || resp.Summary[ImportError] != 1 || resp.Summary[ImportSkipped] != 1 {
+		t.Errorf("Unexpected summary: %v", resp.Summary)
+	}
+
+	carol := users.users["carol@acme.com"]
+	if carol == nil || carol.Role != database.RoleViewer || carol.OrganizationID != "org-b" {
+		t.Errorf("Expected carol as a viewer in the caller's org, got %+v", carol)
+	}
+	if !users.flagged[carol.ID] {
+		t.Errorf("Expected imported users to be flagged for a password change")
+	}
+	if users.users["bob@acme.com"] != nil {
+		t.Errorf("Invalid row must not create a user")
+	}
+}
+
+func TestImportUsersCSV_RollsBackOnFailure(t *testing.T) {
+	users := newFakeUserStore()
+	users.failOn = "bob@acme.com"
+
+	csvBody := "alice@acme.com,admin,Welcome-2025\nbob@acme.com,viewer,Welcome-2025\n"
+	results, err := ImportUsersCSV(context.Background(), strings.NewReader(csvBody), "org-a", users)
+	if err == nil {
+		t.Fatalf("Expected an error when a creation fails")
+	}
+	if len(users.users) != 0 || len(users.flagged) != 0 {
+		t.Errorf("Expected no users created, users=%v flagged=%v", users.users, users.flagged)
+	}
+	for _, result := range results {
+		if result.Status != ImportError {
+			t.Errorf("Expected every row to be reported as an error, got %+v", result)
+		}
+	}
+}
+
+func TestHandleImportUsers_MultipartBodyIsLimited(t *testing.T) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "users.csv")
+	part.Write([]byte("email,role,temp_password\n"))
+	part.Write(bytes.Repeat([]byte("padding@acme.com,viewer,Welcome-2025\n"), maxUserImportBytes/30))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/import", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req = withUser(req, &database.User{ID: "admin", Role: database.RoleAdmin}, "org-a")
+	rec := httptest.NewRecorder()
+	users := newFakeUserStore()
+	HandleImportUsers(rec, req, users)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 for an oversized upload, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(users.users) != 0 {
+		t.Errorf("An oversized upload must not create users")
+	}
+}
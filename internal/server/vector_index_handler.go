@@ -0,0 +1,39 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/the-hive/internal/vectordb"
+)
+
+// HandleRebuildPayloadIndexes handles POST /api/v1/admin/vector-indexes
+// (Re)creates payload field indexes on the existing collection
+func HandleRebuildPayloadIndexes(w http.ResponseWriter, r *http.Request, vectorDB vectordb.VectorDB) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	indexer, ok := vectorDB.(vectordb.PayloadIndexer)
+	if !ok {
+		writeJSONError(w, http.StatusNotImplemented, "vector database does not support payload indexes")
+		return
+	}
+
+	indexed, err := indexer.EnsurePayloadIndexes(r.Context())
+	if err != nil {
+		log.Printf("Failed to rebuild payload indexes: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"indexed": indexed,
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/the-hive/internal/buildinfo"
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/vectordb"
+)
+
+// VersionResponse is returned by GET /api/v1/version. It is public, so it names the active modes
+// but never models, endpoints or keys.
+type VersionResponse struct {
+	buildinfo.Info
+	Embedder string `json:"embedder"`  // e.g. openai, or ollama,mock for a fallback chain
+	VectorDB string `json:"vector_db"` // qdrant, memory or mock
+}
+
+// HandleVersion returns a handler for GET /api/v1/version
+func HandleVersion(embedder embeddings.Embedder, vectorDB vectordb.VectorDB) http.HandlerFunc {
+	response := VersionResponse{
+		Info:     buildinfo.Get(),
+		Embedder: embedderMode(embedder),
+		VectorDB: vectorDBMode(vectorDB),
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// embedderMode names the embedder provider, or each provider of a fallback chain in order
+func embedderMode(embedder embeddings.Embedder) string {
+	switch e := embedder.(type) {
+	case *embeddings.FallbackEmbedder:
+		return strings.Join(e.Providers(), ",")
+	case *embeddings.OpenAIEmbedder:
+		return "openai"
+	case *embeddings.OllamaEmbedder:
+		return "ollama"
+	case *embeddings.MockEmbedder:
+		return "mock"
+	case nil:
+		return "none"
+	default:
+		return "custom"
+	}
+}
+
+// vectorDBMode names the vector database backend
+func vectorDBMode(vectorDB vectordb.VectorDB) string {
+	switch vectorDB.(type) {
+	case *vectordb.QdrantVectorDB:
+		return "qdrant"
+	case *vectordb.MemoryVectorDB:
+		return "memory"
+	case *vectordb.MockVectorDB:
+		return "mock"
+	case nil:
+		return "none"
+	default:
+		return "custom"
+	}
+}
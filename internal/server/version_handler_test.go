@@ -0,0 +1,56 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/the-hive/internal/buildinfo"
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/vectordb"
+)
+
+func TestHandleVersion_ReportsBuildAndModes(t *testing.T) {
+	version, commit := buildinfo.Version, buildinfo.Commit
+	buildinfo.Version, buildinfo.Commit = "1.4.0", "abc1234"
+	t.Cleanup(func() { buildinfo.Version, buildinfo.Commit = version, commit })
+
+	get := func(handler http.HandlerFunc) map[string]string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/version", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var body map[string]string
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return body
+	}
+
+	body := get(HandleVersion(embeddings.NewMockEmbedder(8), vectordb.NewMemoryVectorDB()))
+	for _, field := range []string{"version", "commit", "build_date", "go_version", "embedder", "vector_db"} {
+		if body[field] == "" {
+			t.Errorf("Expected %s in the response, got %v", field, body)
+		}
+	}
+	if body["version"] != "1.4.0" || body["commit"] != "abc1234" || body["go_version"] != runtime.Version() {
+		t.Errorf("Expected the injected build details, got %v", body)
+	}
+	if body["embedder"] != "mock" || body["vector_db"] != "memory" {
+		t.Errorf("Expected the mock embedder and memory vector DB, got %v", body)
+	}
+
+	chain, err := embeddings.NewFallbackEmbedder([]string{"ollama", "mock"}, []embeddings.Embedder{embeddings.NewMockEmbedder(8), embeddings.NewMockEmbedder(8)})
+	if err != nil {
+		t.Fatalf("Failed to build embedder chain: %v", err)
+	}
+	if body := get(HandleVersion(chain, vectordb.NewMockVectorDB())); body["embedder"] != "ollama,mock" || body["vector_db"] != "mock" {
+		t.Errorf("Expected the configured fallback chain and mock vector DB, got %v", body)
+	}
+}
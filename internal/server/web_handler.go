@@ -4,8 +4,6 @@ package server
 
 import (
 	"embed"
-	"html/template"
-	"log"
 	"net/http"
 
 	"github.com/the-hive/internal/database"
@@ -16,19 +14,7 @@ var templatesFS embed.FS
 
 // renderTemplate is a helper function to render templates with base layout
 func renderTemplate(w http.ResponseWriter, tmplName string, data interface{}) error {
-	// Parse both base.html and the requested template together
-	tmpl, err := template.ParseFS(templatesFS, "templates/base.html", "templates/"+tmplName)
-	if err != nil {
-		log.Printf("Failed to parse template %s: %v", tmplName, err)
-		return err
-	}
-
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := tmpl.ExecuteTemplate(w, "base.html", data); err != nil {
-		log.Printf("Failed to execute template %s: %v", tmplName, err)
-		return err
-	}
-	return nil
+	return defaultTemplates.Render(w, tmplName, data)
 }
 
 // HandleWeb serves the main web interface (search page)
@@ -38,7 +24,7 @@ func HandleWeb(w http.ResponseWriter, r *http.Request, metadataStore *database.S
 		return
 	}
 
-	if err := renderTemplate(w, "index.html", nil); err != nil {
+	if err := renderTemplate(w, "index.html", newPageData(r, metadataStore, orgStore)); err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -51,7 +37,7 @@ func HandleSettings(w http.ResponseWriter, r *http.Request, metadataStore *datab
 		return
 	}
 
-	if err := renderTemplate(w, "settings.html", nil); err != nil {
+	if err := renderTemplate(w, "settings.html", newPageData(r, metadataStore, orgStore)); err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -64,7 +50,7 @@ func HandleTimelinePage(w http.ResponseWriter, r *http.Request, metadataStore *d
 		return
 	}
 
-	if err := renderTemplate(w, "timeline.html", nil); err != nil {
+	if err := renderTemplate(w, "timeline.html", newPageData(r, metadataStore, orgStore)); err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -77,7 +63,7 @@ func HandleGraphPage(w http.ResponseWriter, r *http.Request, metadataStore *data
 		return
 	}
 
-	if err := renderTemplate(w, "graph.html", nil); err != nil {
+	if err := renderTemplate(w, "graph.html", newPageData(r, metadataStore, orgStore)); err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -90,7 +76,7 @@ func HandleActivityPage(w http.ResponseWriter, r *http.Request, metadataStore *d
 		return
 	}
 
-	if err := renderTemplate(w, "activity.html", nil); err != nil {
+	if err := renderTemplate(w, "activity.html", newPageData(r, metadataStore, orgStore)); err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -103,7 +89,7 @@ func HandleAccessPage(w http.ResponseWriter, r *http.Request, metadataStore *dat
 		return
 	}
 
-	if err := renderTemplate(w, "access.html", nil); err != nil {
+	if err := renderTemplate(w, "access.html", newPageData(r, metadataStore, orgStore)); err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -116,7 +102,7 @@ func HandleSuperAdminPage(w http.ResponseWriter, r *http.Request, metadataStore
 		return
 	}
 
-	if err := renderTemplate(w, "super_admin.html", nil); err != nil {
+	if err := renderTemplate(w, "super_admin.html", newPageData(r, metadataStore, orgStore)); err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
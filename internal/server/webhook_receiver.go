@@ -0,0 +1,455 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/docid"
+	"github.com/the-hive/internal/httpclient"
+	"github.com/the-hive/internal/langdetect"
+	"github.com/the-hive/internal/parser"
+	"github.com/the-hive/internal/processor"
+	"github.com/the-hive/internal/proto"
+	"github.com/the-hive/internal/queue"
+)
+
+// JobTypeWebhookIngest ingests one document announced by an inbound webhook
+const JobTypeWebhookIngest = "webhook_ingest"
+
+const (
+	// DefaultWebhookSignatureHeader carries the hex HMAC-SHA256 of the request body ("sha256=" prefix optional)
+	DefaultWebhookSignatureHeader = "X-Hive-Signature-256"
+	// maxWebhookBodyBytes bounds an inbound webhook payload
+	maxWebhookBodyBytes = 1 << 20
+	// maxWebhookDocumentBytes bounds a document fetched from a webhook's URL
+	maxWebhookDocumentBytes = 10 << 20
+)
+
+// WebhookMapping says where a payload keeps its documents. Paths are dot-separated keys
+// (e.g. "resourceData.webUrl"); Items, when set, points at an array of documents and the
+// other paths are relative to each element.
+type WebhookMapping struct {
+	Items    string `json:"items,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Content  string `json:"content,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+// WebhookShapes are the built-in payload mappings
+var WebhookShapes = map[string]WebhookMapping{
+	// {"url": "...", "content": "...", "filename": "..."}
+	"generic": {URL: "url", Content: "content", Filename: "filename"},
+	// Microsoft Graph / SharePoint change notifications: {"value": [{"resourceData": {"webUrl": "...", "name": "..."}}]}
+	"sharepoint": {Items: "value", URL: "resourceData.webUrl", Filename: "resourceData.name"},
+	// Google Drive file events relayed as {"file": {"webContentLink": "...", "name": "..."}}
+	"google_drive": {URL: "file.webContentLink", Filename: "file.name"},
+}
+
+// WebhookSource is an external system allowed to push documents into one organization
+type WebhookSource struct {
+	Name            string          `json:"name"` // Path segment: POST /api/v1/webhooks/ingest/{name}
+	Secret          string          `json:"secret"`
+	OrganizationID  string          `json:"organization_id"`
+	Shape           string          `json:"shape,omitempty"`            // A WebhookShapes key; defaults to "generic"
+	Mapping         *WebhookMapping `json:"mapping,omitempty"`          // Overrides the shape
+	SignatureHeader string          `json:"signature_header,omitempty"` // Defaults to DefaultWebhookSignatureHeader
+}
+
+// WebhookIngestPayload is the queued job for one webhook document
+type WebhookIngestPayload struct {
+	Source         string    `json:"source"`
+	OrganizationID string    `json:"organizationId"`
+	URL            string    `json:"url,omitempty"`
+	Content        string    `json:"content,omitempty"`
+	Filename       string    `json:"filename,omitempty"`
+	ReceivedAt     time.Time `json:"receivedAt"`
+}
+
+// ParseWebhookSources parses the WEBHOOK_SOURCES JSON array, checking each source is usable
+func ParseWebhookSources(raw string) ([]WebhookSource, error) {
+	var sources []WebhookSource
+	if err := json.Unmarshal([]byte(raw), &sources); err != nil {
+		return nil, fmt.Errorf("invalid webhook sources: %w", err)
+	}
+	for i := range sources {
+		source := &sources[i]
+		if source.Name == "" || source.Secret == "" || source.OrganizationID == "" {
+			return nil, fmt.Errorf("webhook source %d needs a name, secret and organization_id", i)
+		}
+		if source.Shape == "" {
+			source.Shape = "generic"
+		}
+		if _, ok := WebhookShapes[source.Shape]; !ok && source.Mapping == nil {
+			return nil, fmt.Errorf("webhook source %s: unknown shape %q", source.Name, source.Shape)
+		}
+		if source.SignatureHeader == "" {
+			source.SignatureHeader = DefaultWebhookSignatureHeader
+		}
+	}
+	return sources, nil
+}
+
+// WebhookReceiver accepts signed document notifications from external systems and queues them for ingest
+type WebhookReceiver struct {
+	sources       map[string]WebhookSource
+	jobQueue      queue.Queue
+	auditLogStore *database.AuditLogStore
+}
+
+// NewWebhookReceiver creates a receiver for the configured sources
+func NewWebhookReceiver(sources []WebhookSource, jobQueue queue.Queue, auditLogStore *database.AuditLogStore) *WebhookReceiver {
+	bySource := make(map[string]WebhookSource, len(sources))
+	for _, source := range sources {
+		bySource[source.Name] = source
+	}
+	return &WebhookReceiver{
+		sources:       bySource,
+		jobQueue:      jobQueue,
+		auditLogStore: auditLogStore,
+	}
+}
+
+// HandleWebhook handles POST /api/v1/webhooks/ingest/{source}
+// The body must be signed with the source's secret; each document it names is queued for ingest
+// into the source's organization.
+func (h *WebhookReceiver) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	source, ok := h.sources[r.PathValue("source")]
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "unknown webhook source")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
+	if err != nil {
+		writeJSONError(w, http.StatusRequestEntityTooLarge, "payload too large")
+		return
+	}
+	if !validWebhookSignature(source.Secret, body, r.Header.Get(source.SignatureHeader)) {
+		writeJSONError(w, http.StatusUnauthorized, "invalid signature")
+		return
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+
+	mapping := WebhookShapes[source.Shape]
+	if source.Mapping != nil {
+		mapping = *source.Mapping
+	}
+	documents := extractWebhookDocuments(payload, mapping)
+	if len(documents) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "payload names no document URL or content")
+		return
+	}
+
+	if !jobQueueAvailable(h.jobQueue) {
+		writeJSONError(w, http.StatusServiceUnavailable, "job queue not available (Redis required)")
+		return
+	}
+
+	queued := 0
+	for _, document := range documents {
+		document.Source = source.Name
+		document.OrganizationID = source.OrganizationID
+		document.ReceivedAt = time.Now()
+		payloadJSON, err := json.Marshal(document)
+		if err != nil {
+			continue
+		}
+		job := queue.Job{
+			Type:      JobTypeWebhookIngest,
+			Payload:   payloadJSON,
+			CreatedAt: document.ReceivedAt,
+			Key:       "org:" + source.OrganizationID,
+		}
+		if err := h.jobQueue.Enqueue(r.Context(), job); err != nil {
+			log.Printf("Failed to enqueue webhook ingest from %s: %v", source.Name, err)
+			writeJSONError(w, http.StatusInternalServerError, "failed to enqueue ingest")
+			return
+		}
+		queued++
+	}
+
+	if h.auditLogStore != nil {
+		details := fmt.Sprintf("Webhook [%s] queued %d document(s) for ingest", source.Name, queued)
+		if err := h.auditLogStore.LogAction(getClientIP(r), database.AuditActionIngest, details, source.OrganizationID); err != nil {
+			log.Printf("Failed to log webhook audit entry: %v", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{"queued": queued})
+}
+
+// validWebhookSignature reports whether signature is the hex HMAC-SHA256 of body under secret
+func validWebhookSignature(secret string, body []byte, signature string) bool {
+	got, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil || len(got) == 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// extractWebhookDocuments applies the mapping to a decoded payload; elements with neither a
+// URL nor content are skipped
+func extractWebhookDocuments(payload interface{}, mapping WebhookMapping) []WebhookIngestPayload {
+	items := []interface{}{payload}
+	if mapping.Items != "" {
+		list, _ := lookupJSONPath(payload, mapping.Items).([]interface{})
+		items = list
+	}
+
+	var documents []WebhookIngestPayload
+	for _, item := range items {
+		document := WebhookIngestPayload{
+			URL:      jsonPathString(item, mapping.URL),
+			Content:  jsonPathString(item, mapping.Content),
+			Filename: jsonPathString(item, mapping.Filename),
+		}
+		if document.URL == "" && document.Content == "" {
+			continue
+		}
+		documents = append(documents, document)
+	}
+	return documents
+}
+
+// lookupJSONPath follows a dot-separated path of object keys (or array indexes) through decoded JSON
+func lookupJSONPath(value interface{}, jsonPath string) interface{} {
+	if jsonPath == "" {
+		return nil
+	}
+	for _, key := range strings.Split(jsonPath, ".") {
+		switch node := value.(type) {
+		case map[string]interface{}:
+			value = node[key]
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil
+			}
+			value = node[index]
+		default:
+			return nil
+		}
+	}
+	return value
+}
+
+// jsonPathString returns the string at the path, or "" if it is missing or not a string
+func jsonPathString(value interface{}, jsonPath string) string {
+	s, _ := lookupJSONPath(value, jsonPath).(string)
+	return strings.TrimSpace(s)
+}
+
+// WebhookIngester runs queued webhook ingest jobs: it fetches the document when only a URL was
+// sent, then chunks and stores it through the hive service like a drone upload
+type WebhookIngester struct {
+	hive     *HiveService
+	chunker  *processor.Chunker
+	chunkers *processor.ChunkerRegistry // Picks a chunking strategy by file type when set, instead of chunker
+	client   *http.Client
+}
+
+// NewWebhookIngester creates an ingester that stores documents through the hive service
+func NewWebhookIngester(hive *HiveService) *WebhookIngester {
+	return &WebhookIngester{
+		hive:    hive,
+		chunker: processor.NewChunkerFromEnv(),
+		client:  httpclient.New(60 * time.Second),
+	}
+}
+
+// SetChunkerRegistry chunks each document with the strategy for its file type
+func (i *WebhookIngester) SetChunkerRegistry(chunkers *processor.ChunkerRegistry) {
+	i.chunkers = chunkers
+}
+
+// Handle processes a JobTypeWebhookIngest job
+func (i *WebhookIngester) Handle(ctx context.Context, job queue.Job) error {
+	var payload WebhookIngestPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid webhook ingest payload: %w", err)
+	}
+
+	content := payload.Content
+	if content == "" {
+		fetched, err := i.fetch(ctx, payload.URL, payload.Filename)
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s for webhook %s: %w", payload.URL, payload.Source, err)
+		}
+		content = fetched
+	}
+
+	sourcePath := payload.URL
+	if sourcePath == "" {
+		sourcePath = payload.Filename
+	}
+	if sourcePath == "" {
+		// Inline content with no name is keyed by its hash, so each distinct document stays separate
+		sum := sha256.Sum256([]byte(content))
+		sourcePath = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	filename := payload.Filename
+	if filename == "" && payload.URL != "" {
+		if parsed, err := url.Parse(payload.URL); err == nil {
+			filename = path.Base(parsed.Path)
+		}
+	}
+	if filename == "" || filename == "." || filename == "/" {
+		filename = sourcePath
+	}
+	clientID := "webhook:" + payload.Source
+	documentID := docid.New(clientID, sourcePath)
+
+	var chunks []string
+	var err error
+	if i.chunkers != nil {
+		chunks, err = i.chunkers.ChunkFile(filename, content)
+	} else {
+		chunks, err = i.chunker.ChunkText(content)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to chunk webhook document %s: %w", filename, err)
+	}
+	language := langdetect.DetectDocument(chunks)
+	for index, chunk := range chunks {
+		status, err := i.hive.Ingest(ctx, &proto.Chunk{
+			// A deterministic UUID per document and chunk, as the drone derives it: Qdrant only accepts UUID point IDs
+			Id:         docid.PointID(documentID, index),
+			DocumentId: documentID,
+			Content:    chunk,
+			Metadata: map[string]string{
+				"organization_id": payload.OrganizationID,
+				"client_id":       clientID,
+				"filename":        filename,
+				"file_path":       sourcePath,
+				"chunk_index":     strconv.Itoa(index),
+				"total_chunks":    strconv.Itoa(len(chunks)),
+				"language":        language,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if !status.Success {
+			return errors.New(status.Message)
+		}
+	}
+	log.Printf("[WEBHOOK] Ingested %s from %s into org %s (%d chunks)", filename, payload.Source, payload.OrganizationID, len(chunks))
+	return nil
+}
+
+// webhookDocumentTypes maps the content types of documents the parser can read to the file
+// extension it parses them by
+var webhookDocumentTypes = map[string]string{
+	"text/html":       ".html",
+	"application/pdf": ".pdf",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": ".docx",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":       ".xlsx",
+	"application/vnd.ms-excel": ".xls",
+	"message/rfc822":           ".eml",
+}
+
+// fetch downloads a document named by a webhook and extracts its text
+func (i *WebhookIngester) fetch(ctx context.Context, documentURL, filename string) (string, error) {
+	parsed, err := url.Parse(documentURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "", fmt.Errorf("unsupported document URL")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, documentURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("document URL returned status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxWebhookDocumentBytes))
+	if err != nil {
+		return "", err
+	}
+	if filename == "" {
+		filename = path.Base(parsed.Path)
+	}
+	return webhookDocumentText(body, resp.Header.Get("Content-Type"), filename)
+}
+
+// webhookDocumentText returns the text of a fetched document: plain text as is, and documents the
+// parser reads (HTML, PDF, Word, Excel, email) parsed by their content type, or by the file's
+// extension when the type is generic. Anything else is refused rather than ingested as garbage.
+func webhookDocumentText(body []byte, contentType, filename string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = ""
+	}
+	ext := webhookDocumentTypes[mediaType]
+	if ext == "" && (mediaType == "" || mediaType == "application/octet-stream") {
+		if fileExt := strings.ToLower(path.Ext(filename)); parser.IsSupportedFile(filename) {
+			ext = fileExt
+		}
+	}
+
+	switch {
+	case ext == ".txt" || ext == ".md" || (ext == "" && strings.HasPrefix(mediaType, "text/")):
+		if !utf8.Valid(body) {
+			return "", fmt.Errorf("document is not valid UTF-8 text")
+		}
+		return string(body), nil
+	case ext == "":
+		return "", fmt.Errorf("unsupported document content type %q", contentType)
+	}
+
+	// The parser reads files, so the document is parsed from a temporary copy
+	tmp, err := os.CreateTemp("", "hive-webhook-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(body); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	text, err := parser.ParseFile(tmp.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to extract text from %s: %w", filename, err)
+	}
+	return text, nil
+}
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/queue"
+	"github.com/the-hive/internal/vectordb"
+)
+
+// recordingQueue keeps enqueued jobs in memory
+type recordingQueue struct {
+	jobs []queue.Job
+}
+
+func (q *recordingQueue) Enqueue(ctx context.Context, job queue.Job) error {
+	q.jobs = append(q.jobs, job)
+	return nil
+}
+
+func (q *recordingQueue) Dequeue(ctx context.Context) (queue.Job, error) {
+	<-ctx.Done()
+	return queue.Job{}, ctx.Err()
+}
+
+func signWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func postWebhook(receiver *WebhookReceiver, source, signature string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/ingest/"+source, strings.NewReader(string(body)))
+	req.SetPathValue("source", source)
+	req.Header.Set(DefaultWebhookSignatureHeader, signature)
+	rr := httptest.NewRecorder()
+	receiver.HandleWebhook(rr, req)
+	return rr
+}
+
+func TestHandleWebhook_SignedPayloadEnqueuesIngest(t *testing.T) {
+	sources, err := ParseWebhookSources(`[{"name": "cms", "secret": "s3cret", "organization_id": "org-1"}]`)
+	if err != nil {
+		t.Fatalf("ParseWebhookSources: %v", err)
+	}
+	jobQueue := &recordingQueue{}
+	receiver := NewWebhookReceiver(sources, jobQueue, nil)
+
+	body := []byte(`{"filename": "policy.txt", "content": "Employees get 25 vacation days."}`)
+
+	if rr := postWebhook(receiver, "cms", signWebhook("wrong", body), body); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("bad signature: expected 401, got %d", rr.Code)
+	}
+	if len(jobQueue.jobs) != 0 {
+		t.Fatalf("bad signature enqueued %d job(s)", len(jobQueue.jobs))
+	}
+
+	rr := postWebhook(receiver, "cms", signWebhook("s3cret", body), body)
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(jobQueue.jobs) != 1 || jobQueue.jobs[0].Type != JobTypeWebhookIngest {
+		t.Fatalf("expected one %s job, got %+v", JobTypeWebhookIngest, jobQueue.jobs)
+	}
+	var payload WebhookIngestPayload
+	if err := json.Unmarshal(jobQueue.jobs[0].Payload, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.OrganizationID != "org-1" || payload.Source != "cms" || payload.Filename != "policy.txt" || !strings.Contains(payload.Content, "25 vacation days") {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+
+	// Running the job stores the document for the source's organization
+	t.Setenv("OPENAI_API_KEY", "")
+	db := newTestDB(t)
+	if _, err := db.Exec(`CREATE TABLE chunks (
+		id TEXT PRIMARY KEY,
+		document_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		chunk_index INTEGER NOT NULL,
+		organization_id TEXT
+	)`); err != nil {
+		t.Fatalf("Failed to create chunks table: %v", err)
+	}
+	vectorDB := vectordb.NewMemoryVectorDB()
+	ingester := NewWebhookIngester(NewHiveService(db, vectorDB, embeddings.NewMockEmbedder(8)))
+	if err := ingester.Handle(context.Background(), jobQueue.jobs[0]); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if count, _ := vectorDB.CountByOrganization(context.Background(), "org-1"); count == 0 {
+		t.Errorf("expected ingested vectors for org-1")
+	}
+	// Qdrant only accepts UUID point IDs
+	ids, _ := vectorDB.ListPointIDs(context.Background(), "org-1")
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			t.Errorf("expected a UUID point ID, got %q", id)
+		}
+	}
+}
+
+func TestWebhookIngester_FetchExtractsTextByContentType(t *testing.T) {
+	documents := map[string]struct{ contentType, body string }{
+		"/policy.txt":  {"text/plain; charset=utf-8", "Employees accrue 25 vacation days."},
+		"/policy.html": {"text/html", "<html><head><script>track()</script></head><body><p>Remote work needs approval.</p></body></html>"},
+		"/photo.png":   {"image/png", "\x89PNG\r\n\x1a\n"},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		doc := documents[r.URL.Path]
+		w.Header().Set("Content-Type", doc.contentType)
+		io.WriteString(w, doc.body)
+	}))
+	defer server.Close()
+	ingester := NewWebhookIngester(nil)
+
+	text, err := ingester.fetch(context.Background(), server.URL+"/policy.txt", "")
+	if err != nil || text != documents["/policy.txt"].body {
+		t.Errorf("expected plain text as is, got %q (%v)", text, err)
+	}
+	text, err = ingester.fetch(context.Background(), server.URL+"/policy.html", "")
+	if err != nil || !strings.Contains(text, "Remote work needs approval.") || strings.Contains(text, "<p>") || strings.Contains(text, "track()") {
+		t.Errorf("expected the HTML's text without markup or scripts, got %q (%v)", text, err)
+	}
+	if _, err := ingester.fetch(context.Background(), server.URL+"/photo.png", ""); err == nil || !strings.Contains(err.Error(), "unsupported document content type") {
+		t.Errorf("expected an image to be refused, got %v", err)
+	}
+}
+
+func TestHandleWebhook_SharePointShapeEnqueuesEachItem(t *testing.T) {
+	sources, err := ParseWebhookSources(`[{"name": "sp", "secret": "k", "organization_id": "org-2", "shape": "sharepoint"}]`)
+	if err != nil {
+		t.Fatalf("ParseWebhookSources: %v", err)
+	}
+	jobQueue := &recordingQueue{}
+	receiver := NewWebhookReceiver(sources, jobQueue, nil)
+
+	body := []byte(`{"value": [
+		{"resourceData": {"webUrl": "https://example.sharepoint.com/a.txt", "name": "a.txt"}},
+		{"resourceData": {"name": "no-url.txt"}},
+		{"resourceData": {"webUrl": "https://example.sharepoint.com/b.txt", "name": "b.txt"}}
+	]}`)
+	rr := postWebhook(receiver, "sp", signWebhook("k", body), body)
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(jobQueue.jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(jobQueue.jobs))
+	}
+	var payload WebhookIngestPayload
+	json.Unmarshal(jobQueue.jobs[1].Payload, &payload)
+	if payload.URL != "https://example.sharepoint.com/b.txt" || payload.OrganizationID != "org-2" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestWebhookIngester_KeepsUnnamedInlineDocumentsApart(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	db := newTestDB(t)
+	if _, err := db.Exec(`CREATE TABLE chunks (
+		id TEXT PRIMARY KEY,
+		document_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		chunk_index INTEGER NOT NULL,
+		organization_id TEXT
+	)`); err != nil {
+		t.Fatalf("Failed to create chunks table: %v", err)
+	}
+	vectorDB := vectordb.NewMemoryVectorDB()
+	ingester := NewWebhookIngester(NewHiveService(db, vectorDB, embeddings.NewMockEmbedder(8)))
+
+	// Neither push names a URL or filename
+	for _, content := range []string{"Employees get 25 vacation days.", "Expense reports are due monthly."} {
+		payloadJSON, _ := json.Marshal(WebhookIngestPayload{Source: "cms", OrganizationID: "org-1", Content: content})
+		if err := ingester.Handle(context.Background(), queue.Job{Type: JobTypeWebhookIngest, Payload: payloadJSON}); err != nil {
+			t.Fatalf("Handle: %v", err)
+		}
+	}
+
+	ids, _ := vectorDB.ListPointIDs(context.Background(), "org-1")
+	documents := map[string]bool{}
+	for _, id := range ids {
+		payload, _ := vectorDB.GetPayload(context.Background(), id)
+		documents[payload["document_id"]] = true
+	}
+	if len(ids) != 2 || len(documents) != 2 {
+		t.Errorf("Expected both inline documents kept as separate documents, got %d points in %d documents", len(ids), len(documents))
+	}
+}
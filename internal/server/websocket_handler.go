@@ -5,15 +5,21 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/redis/go-redis/v9"
+	"github.com/the-hive/internal/database"
 )
 
+// fallbackMailboxSize is the number of notifications kept in memory per offline client when Redis is unavailable
+const fallbackMailboxSize = 100
+
 var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool {
 		// Allow all origins for development
@@ -29,14 +35,36 @@ type NotificationMessage struct {
 	Level   string `json:"level"`
 }
 
+// NotificationStats reports notifications that could not be queued in Redis
+type NotificationStats struct {
+	Missed   int64 `json:"missed"`   // Notifications for offline clients that could not be queued in Redis
+	Dropped  int64 `json:"dropped"`  // Notifications evicted from a full in-memory mailbox
+	Buffered int   `json:"buffered"` // Notifications currently held in memory awaiting delivery
+}
+
+// ErrTooManyConnections is returned when an organization already has its maximum number of WebSocket connections
+var ErrTooManyConnections = errors.New("too many WebSocket connections for organization")
+
 // WebSocketManager manages WebSocket connections
+// Each client_id has at most one active connection; a new connection replaces the old one.
 type WebSocketManager struct {
 	clients     map[string]*websocket.Conn
+	clientOrgs  map[string]string // client_id -> organization of its active connection
+	orgCounts   map[string]int    // Active connections per organization
+	maxPerOrg   int               // 0 = unlimited
 	clientsMu   sync.RWMutex
 	redisClient *redis.Client
+	redisSource func() *redis.Client // Supplies the client once Redis is reachable when redisClient is nil
 	pingTicker  *time.Ticker
 	ctx         context.Context
 	cancel      context.CancelFunc
+	eventLogger *database.EventLogger
+
+	// In-memory fallback mailbox used when Redis is down (lost on restart)
+	fallback   map[string][][]byte
+	fallbackMu sync.Mutex
+	missed     int64
+	dropped    int64
 }
 
 // NewWebSocketManager creates a new WebSocket manager
@@ -44,7 +72,10 @@ func NewWebSocketManager(redisClient *redis.Client) *WebSocketManager {
 	ctx, cancel := context.WithCancel(context.Background())
 	wm := &WebSocketManager{
 		clients:     make(map[string]*websocket.Conn),
+		clientOrgs:  make(map[string]string),
+		orgCounts:   make(map[string]int),
 		redisClient: redisClient,
+		fallback:    make(map[string][][]byte),
 		pingTicker:  time.NewTicker(30 * time.Second),
 		ctx:         ctx,
 		cancel:      cancel,
@@ -56,6 +87,28 @@ func NewWebSocketManager(redisClient *redis.Client) *WebSocketManager {
 	return wm
 }
 
+// SetRedisClientSource supplies the Redis client when the manager was created without one because
+// Redis was down at startup (e.g. a reconnecting job queue's Client); source returns nil until connected
+func (wm *WebSocketManager) SetRedisClientSource(source func() *redis.Client) {
+	wm.clientsMu.Lock()
+	wm.redisSource = source
+	wm.clientsMu.Unlock()
+}
+
+// redis returns the Redis client for mailboxes, or nil while Redis is unavailable
+func (wm *WebSocketManager) redis() *redis.Client {
+	if wm.redisClient != nil {
+		return wm.redisClient
+	}
+	wm.clientsMu.RLock()
+	source := wm.redisSource
+	wm.clientsMu.RUnlock()
+	if source == nil {
+		return nil
+	}
+	return source()
+}
+
 // pingLoop sends ping messages to all connected clients
 func (wm *WebSocketManager) pingLoop() {
 	for {
@@ -64,10 +117,107 @@ func (wm *WebSocketManager) pingLoop() {
 			return
 		case <-wm.pingTicker.C:
 			wm.pingAllClients()
+			wm.flushFallbackToRedis()
 		}
 	}
 }
 
+// HandleNotificationStats handles GET /api/v1/notifications/stats
+func (wm *WebSocketManager) HandleNotificationStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wm.NotificationStats())
+}
+
+// SetMaxConnectionsPerOrg limits concurrent WebSocket connections per organization (0 = unlimited)
+func (wm *WebSocketManager) SetMaxConnectionsPerOrg(max int) {
+	wm.clientsMu.Lock()
+	defer wm.clientsMu.Unlock()
+	wm.maxPerOrg = max
+}
+
+// ConnectionCount returns the number of active connections for the organization
+func (wm *WebSocketManager) ConnectionCount(orgID string) int {
+	wm.clientsMu.RLock()
+	defer wm.clientsMu.RUnlock()
+	return wm.orgCounts[orgID]
+}
+
+// admitLocked reports whether clientID may connect for orgID. clientsMu must be held.
+// Replacing a client's existing connection in the same organization never needs a new slot.
+func (wm *WebSocketManager) admitLocked(clientID, orgID string) error {
+	if wm.maxPerOrg <= 0 {
+		return nil
+	}
+	if _, exists := wm.clients[clientID]; exists && wm.clientOrgs[clientID] == orgID {
+		return nil
+	}
+	if wm.orgCounts[orgID] >= wm.maxPerOrg {
+		return ErrTooManyConnections
+	}
+	return nil
+}
+
+// registerClient makes conn the client's active connection and returns the connection it replaced, if any
+func (wm *WebSocketManager) registerClient(clientID, orgID string, conn *websocket.Conn) (*websocket.Conn, error) {
+	wm.clientsMu.Lock()
+	defer wm.clientsMu.Unlock()
+
+	if err := wm.admitLocked(clientID, orgID); err != nil {
+		return nil, err
+	}
+
+	old, exists := wm.clients[clientID]
+	if exists {
+		wm.orgCounts[wm.clientOrgs[clientID]]--
+	}
+	wm.clients[clientID] = conn
+	wm.clientOrgs[clientID] = orgID
+	wm.orgCounts[orgID]++
+	return old, nil
+}
+
+// unregisterClient removes the client if conn is still its active connection
+func (wm *WebSocketManager) unregisterClient(clientID string, conn *websocket.Conn) bool {
+	wm.clientsMu.Lock()
+	defer wm.clientsMu.Unlock()
+
+	if current, ok := wm.clients[clientID]; !ok || current != conn {
+		return false
+	}
+	orgID := wm.clientOrgs[clientID]
+	delete(wm.clients, clientID)
+	delete(wm.clientOrgs, clientID)
+	if wm.orgCounts[orgID]--; wm.orgCounts[orgID] <= 0 {
+		delete(wm.orgCounts, orgID)
+	}
+	return true
+}
+
+// SetEventLogger sets the event logger used to record missed notifications
+func (wm *WebSocketManager) SetEventLogger(eventLogger *database.EventLogger) {
+	wm.eventLogger = eventLogger
+}
+
+// NotificationStats returns counts of notifications that missed the Redis mailbox
+func (wm *WebSocketManager) NotificationStats() NotificationStats {
+	wm.fallbackMu.Lock()
+	defer wm.fallbackMu.Unlock()
+
+	buffered := 0
+	for _, messages := range wm.fallback {
+		buffered += len(messages)
+	}
+	return NotificationStats{
+		Missed:   wm.missed,
+		Dropped:  wm.dropped,
+		Buffered: buffered,
+	}
+}
+
 // pingAllClients sends ping to all connected clients and removes dead connections
 func (wm *WebSocketManager) pingAllClients() {
 	wm.clientsMu.RLock()
@@ -85,9 +235,7 @@ func (wm *WebSocketManager) pingAllClients() {
 		if err := conn.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
 			log.Printf("Failed to ping client %s, removing connection: %v", clientID, err)
 			// Remove dead connection
-			wm.clientsMu.Lock()
-			delete(wm.clients, clientID)
-			wm.clientsMu.Unlock()
+			wm.unregisterClient(clientID, conn)
 			conn.Close()
 			continue
 		}
@@ -108,6 +256,22 @@ func (wm *WebSocketManager) HandleWebSocket(w http.ResponseWriter, r *http.Reque
 
 	// Note: API key authentication is handled by the auth middleware before this function is called
 
+	// Organization for connection limits (from the auth context, else as declared by the drone)
+	orgID := r.URL.Query().Get("organization_id")
+	if orgIDVal, ok := r.Context().Value("organization_id").(string); ok && orgIDVal != "" {
+		orgID = orgIDVal
+	}
+
+	// Reject before upgrading when the organization is already at its limit
+	wm.clientsMu.RLock()
+	admitErr := wm.admitLocked(clientID, orgID)
+	wm.clientsMu.RUnlock()
+	if admitErr != nil {
+		log.Printf("Rejecting WebSocket client %s: %v (org %s)", clientID, admitErr, orgID)
+		http.Error(w, admitErr.Error(), http.StatusTooManyRequests)
+		return
+	}
+
 	// Upgrade connection to WebSocket
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -116,26 +280,31 @@ func (wm *WebSocketManager) HandleWebSocket(w http.ResponseWriter, r *http.Reque
 	}
 	defer conn.Close()
 
-	log.Printf("WebSocket client connected: %s", clientID)
+	// Add client to map, replacing any existing connection for the same client
+	old, err := wm.registerClient(clientID, orgID, conn)
+	if err != nil {
+		// Another connection took the last slot between the check and the upgrade
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, err.Error()), time.Now().Add(time.Second))
+		return
+	}
+	if old != nil {
+		log.Printf("WebSocket client %s reconnected, closing previous connection", clientID)
+		old.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "replaced by a newer connection"), time.Now().Add(time.Second))
+		old.Close()
+	}
 
-	// Add client to map
-	wm.clientsMu.Lock()
-	wm.clients[clientID] = conn
-	wm.clientsMu.Unlock()
+	log.Printf("WebSocket client connected: %s", clientID)
 
-	// Remove client when connection closes
+	// Remove client when connection closes (unless a newer connection replaced it)
 	defer func() {
-		wm.clientsMu.Lock()
-		delete(wm.clients, clientID)
-		wm.clientsMu.Unlock()
-		log.Printf("WebSocket client disconnected: %s", clientID)
+		if wm.unregisterClient(clientID, conn) {
+			log.Printf("WebSocket client disconnected: %s", clientID)
+		}
 	}()
 
-	// Send any pending messages from Redis
-	if wm.redisClient != nil {
-		if err := wm.sendPendingMessages(clientID, conn); err != nil {
-			log.Printf("Failed to send pending messages to %s: %v", clientID, err)
-		}
+	// Send any pending messages from the in-memory fallback and Redis
+	if err := wm.sendPendingMessages(clientID, conn); err != nil {
+		log.Printf("Failed to send pending messages to %s: %v", clientID, err)
 	}
 
 	// Set up pong handler to reset read deadline when ping is received
@@ -202,30 +371,130 @@ func (wm *WebSocketManager) SendNotificationRaw(clientID string, notificationTyp
 	}
 
 	// Client is offline, push to Redis
-	if wm.redisClient != nil {
-		messageJSON, err := json.Marshal(notification)
-		if err != nil {
-			return err
-		}
+	messageJSON, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
 
-		mailboxKey := "mailbox:" + clientID
-		if err := wm.redisClient.LPush(context.Background(), mailboxKey, messageJSON).Err(); err != nil {
-			return err
+	if redisClient := wm.redis(); redisClient != nil {
+		err := wm.pushToRedis(redisClient, clientID, messageJSON)
+		if err == nil {
+			log.Printf("Queued notification for offline client %s in Redis", clientID)
+			return nil
 		}
+		log.Printf("Failed to queue notification for %s in Redis: %v", clientID, err)
+	}
 
-		// Set expiration on mailbox (e.g., 7 days)
-		wm.redisClient.Expire(context.Background(), mailboxKey, 7*24*60*60*1000000000) // 7 days in nanoseconds
+	// Redis is unavailable - keep the notification in memory and record the miss
+	wm.queueFallback(clientID, notification, messageJSON)
+	return nil
+}
 
-		log.Printf("Queued notification for offline client %s in Redis", clientID)
-		return nil
+// pushToRedis adds a message to the client's Redis mailbox
+func (wm *WebSocketManager) pushToRedis(redisClient *redis.Client, clientID string, messageJSON []byte) error {
+	mailboxKey := "mailbox:" + clientID
+	if err := redisClient.LPush(context.Background(), mailboxKey, messageJSON).Err(); err != nil {
+		return err
 	}
 
+	// Set expiration on mailbox (e.g., 7 days)
+	redisClient.Expire(context.Background(), mailboxKey, 7*24*60*60*1000000000) // 7 days in nanoseconds
 	return nil
 }
 
+// queueFallback holds a notification in the bounded in-memory mailbox, evicting the oldest when full
+func (wm *WebSocketManager) queueFallback(clientID string, notification NotificationMessage, messageJSON []byte) {
+	wm.fallbackMu.Lock()
+	wm.missed++
+	messages := append(wm.fallback[clientID], messageJSON)
+	dropped := 0
+	if len(messages) > fallbackMailboxSize {
+		dropped = len(messages) - fallbackMailboxSize
+		messages = messages[dropped:]
+		wm.dropped += int64(dropped)
+	}
+	wm.fallback[clientID] = messages
+	wm.fallbackMu.Unlock()
+
+	log.Printf("[WARN] Redis unavailable: holding %s notification for offline client %s in memory (%d buffered, %d dropped)", notification.Type, clientID, len(messages), dropped)
+
+	if wm.eventLogger != nil {
+		details := fmt.Sprintf("Redis unavailable; %s notification (%s) for client %s held in memory: %s", notification.Type, notification.Level, clientID, notification.Message)
+		if dropped > 0 {
+			details += fmt.Sprintf(" (%d older notification(s) dropped)", dropped)
+		}
+		if err := wm.eventLogger.LogEvent("NOTIFICATION_MISSED", clientID, details); err != nil {
+			log.Printf("Failed to record missed notification: %v", err)
+		}
+	}
+}
+
+// takeFallback removes and returns the client's in-memory notifications (oldest first)
+func (wm *WebSocketManager) takeFallback(clientID string) [][]byte {
+	wm.fallbackMu.Lock()
+	defer wm.fallbackMu.Unlock()
+	messages := wm.fallback[clientID]
+	delete(wm.fallback, clientID)
+	return messages
+}
+
+// flushFallbackToRedis moves in-memory notifications into Redis once it is reachable again
+func (wm *WebSocketManager) flushFallbackToRedis() {
+	redisClient := wm.redis()
+	if redisClient == nil {
+		return
+	}
+
+	wm.fallbackMu.Lock()
+	pending := len(wm.fallback)
+	wm.fallbackMu.Unlock()
+	if pending == 0 {
+		return
+	}
+
+	if err := redisClient.Ping(context.Background()).Err(); err != nil {
+		return
+	}
+
+	wm.fallbackMu.Lock()
+	clientIDs := make([]string, 0, len(wm.fallback))
+	for clientID := range wm.fallback {
+		clientIDs = append(clientIDs, clientID)
+	}
+	wm.fallbackMu.Unlock()
+
+	for _, clientID := range clientIDs {
+		messages := wm.takeFallback(clientID)
+		for i, messageJSON := range messages {
+			if err := wm.pushToRedis(redisClient, clientID, messageJSON); err != nil {
+				// Put the rest back and try again on the next tick
+				wm.fallbackMu.Lock()
+				wm.fallback[clientID] = append(messages[i:], wm.fallback[clientID]...)
+				wm.fallbackMu.Unlock()
+				log.Printf("Failed to flush in-memory notifications for %s to Redis: %v", clientID, err)
+				return
+			}
+		}
+		log.Printf("Moved %d in-memory notification(s) for %s to Redis", len(messages), clientID)
+	}
+}
+
 // sendPendingMessages sends any pending messages from Redis to the client
 func (wm *WebSocketManager) sendPendingMessages(clientID string, conn *websocket.Conn) error {
-	if wm.redisClient == nil {
+	// Messages held in memory while Redis was down are older than anything in Redis
+	messages := wm.takeFallback(clientID)
+	for i, messageJSON := range messages {
+		if err := conn.WriteMessage(websocket.TextMessage, messageJSON); err != nil {
+			wm.fallbackMu.Lock()
+			wm.fallback[clientID] = append(messages[i:], wm.fallback[clientID]...)
+			wm.fallbackMu.Unlock()
+			return err
+		}
+		log.Printf("Sent in-memory pending message to client %s", clientID)
+	}
+
+	redisClient := wm.redis()
+	if redisClient == nil {
 		return nil
 	}
 
@@ -234,7 +503,7 @@ func (wm *WebSocketManager) sendPendingMessages(clientID string, conn *websocket
 
 	// Pop all messages from the mailbox
 	for {
-		result, err := wm.redisClient.RPop(ctx, mailboxKey).Result()
+		result, err := redisClient.RPop(ctx, mailboxKey).Result()
 		if err == redis.Nil {
 			// No more messages
 			break
@@ -247,7 +516,7 @@ func (wm *WebSocketManager) sendPendingMessages(clientID string, conn *websocket
 		if err := conn.WriteMessage(websocket.TextMessage, []byte(result)); err != nil {
 			log.Printf("Failed to send pending message to client %s: %v", clientID, err)
 			// Put message back at the front of the queue
-			wm.redisClient.LPush(ctx, mailboxKey, result)
+			redisClient.LPush(ctx, mailboxKey, result)
 			return err
 		}
 
@@ -269,7 +538,9 @@ func (wm *WebSocketManager) Stop() {
 	for clientID, conn := range wm.clients {
 		conn.Close()
 		delete(wm.clients, clientID)
+		delete(wm.clientOrgs, clientID)
 	}
+	wm.orgCounts = make(map[string]int)
 	wm.clientsMu.Unlock()
 	
 	log.Printf("WebSocket manager stopped")
@@ -0,0 +1,202 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
+	"github.com/the-hive/internal/config"
+	"github.com/the-hive/internal/database"
+)
+
+func TestSendNotification_OfflineWithoutRedisRecordsMiss(t *testing.T) {
+	eventLogger, err := database.NewEventLogger(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Failed to create event logger: %v", err)
+	}
+
+	wm := NewWebSocketManager(nil)
+	defer wm.Stop()
+	wm.SetEventLogger(eventLogger)
+
+	if err := wm.SendNotificationRaw("drone-1", "ALERT", "Sensitive document detected: plan.pdf", "critical"); err != nil {
+		t.Fatalf("SendNotificationRaw returned error: %v", err)
+	}
+
+	stats := wm.NotificationStats()
+	if stats.Missed != 1 || stats.Buffered != 1 || stats.Dropped != 0 {
+		t.Errorf("Expected 1 missed and 1 buffered notification, got %+v", stats)
+	}
+
+	events, err := eventLogger.GetRecentEvents(10)
+	if err != nil {
+		t.Fatalf("Failed to read events: %v", err)
+	}
+	if len(events) != 1 || events[0].EventType != "NOTIFICATION_MISSED" || !strings.Contains(events[0].Details, "plan.pdf") {
+		t.Errorf("Expected a NOTIFICATION_MISSED event, got %+v", events)
+	}
+
+	// The buffered notification is delivered when the client connects
+	srv := httptest.NewServer(http.HandlerFunc(wm.HandleWebSocket))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"?client_id=drone-1", nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var received NotificationMessage
+	if err := conn.ReadJSON(&received); err != nil {
+		t.Fatalf("Failed to read pending notification: %v", err)
+	}
+	if received.Type != "ALERT" || !strings.Contains(received.Message, "plan.pdf") {
+		t.Errorf("Unexpected notification: %+v", received)
+	}
+	if stats := wm.NotificationStats(); stats.Buffered != 0 {
+		t.Errorf("Expected mailbox to be drained, got %+v", stats)
+	}
+}
+
+func TestSendNotification_FallbackMailboxIsBounded(t *testing.T) {
+	wm := NewWebSocketManager(nil)
+	defer wm.Stop()
+
+	for i := 0; i < fallbackMailboxSize+5; i++ {
+		wm.SendNotificationRaw("drone-1", "INFO", fmt.Sprintf("message %d", i), "info")
+	}
+
+	stats := wm.NotificationStats()
+	if stats.Buffered != fallbackMailboxSize || stats.Dropped != 5 || stats.Missed != fallbackMailboxSize+5 {
+		t.Errorf("Unexpected stats for overflowing mailbox: %+v", stats)
+	}
+
+	// The oldest messages are the ones evicted
+	messages := wm.takeFallback("drone-1")
+	var first NotificationMessage
+	json.Unmarshal(messages[0], &first)
+	if first.Message != "message 5" {
+		t.Errorf("Expected oldest retained message to be 'message 5', got %q", first.Message)
+	}
+}
+
+func TestFlushFallbackToRedis_UsesClientThatConnectsLater(t *testing.T) {
+	ctx := context.Background()
+	client, err := config.NewRedisClient(ctx)
+	if err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+	clientID := fmt.Sprintf("test-late-redis-%d", time.Now().UnixNano())
+	defer client.Del(ctx, "mailbox:"+clientID)
+
+	// Redis was down at startup, so the manager has no client of its own
+	wm := NewWebSocketManager(nil)
+	defer wm.Stop()
+	var connected *redis.Client
+	wm.SetRedisClientSource(func() *redis.Client { return connected })
+
+	wm.SendNotificationRaw(clientID, "ALERT", "held while Redis was down", "critical")
+	wm.flushFallbackToRedis()
+	if stats := wm.NotificationStats(); stats.Buffered != 1 {
+		t.Fatalf("Expected the notification to stay in memory before Redis connects, got %+v", stats)
+	}
+
+	connected = client
+	wm.flushFallbackToRedis()
+	if stats := wm.NotificationStats(); stats.Buffered != 0 {
+		t.Errorf("Expected the buffer to be flushed once Redis connected, got %+v", stats)
+	}
+	if n, err := client.LLen(ctx, "mailbox:"+clientID).Result(); err != nil || n != 1 {
+		t.Errorf("Expected the notification in the Redis mailbox, got %d, %v", n, err)
+	}
+}
+
+// dialWS connects to the test server as the given client
+func dialWS(t *testing.T, srv *httptest.Server, query string) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+	return websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"?"+query, nil)
+}
+
+func TestHandleWebSocket_SecondConnectionReplacesFirst(t *testing.T) {
+	wm := NewWebSocketManager(nil)
+	defer wm.Stop()
+	srv := httptest.NewServer(http.HandlerFunc(wm.HandleWebSocket))
+	defer srv.Close()
+
+	first, _, err := dialWS(t, srv, "client_id=drone-1&organization_id=org-a")
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer first.Close()
+	second, _, err := dialWS(t, srv, "client_id=drone-1&organization_id=org-a")
+	if err != nil {
+		t.Fatalf("Failed to connect second time: %v", err)
+	}
+	defer second.Close()
+
+	// The first connection is closed by the server
+	first.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, _, err := first.ReadMessage(); !websocket.IsCloseError(err, websocket.ClosePolicyViolation) {
+		t.Errorf("Expected the first connection to be closed as replaced, got %v", err)
+	}
+
+	if n := wm.ConnectionCount("org-a"); n != 1 {
+		t.Errorf("Expected one active connection for the org, got %d", n)
+	}
+
+	// Notifications go to the replacement connection
+	if err := wm.SendNotificationRaw("drone-1", "INFO", "hello", "info"); err != nil {
+		t.Fatalf("SendNotificationRaw failed: %v", err)
+	}
+	second.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var received NotificationMessage
+	if err := second.ReadJSON(&received); err != nil || received.Message != "hello" {
+		t.Errorf("Expected the notification on the new connection, got %+v, %v", received, err)
+	}
+}
+
+func TestHandleWebSocket_MaxConnectionsPerOrg(t *testing.T) {
+	wm := NewWebSocketManager(nil)
+	defer wm.Stop()
+	wm.SetMaxConnectionsPerOrg(2)
+	srv := httptest.NewServer(http.HandlerFunc(wm.HandleWebSocket))
+	defer srv.Close()
+
+	for _, id := range []string{"drone-1", "drone-2"} {
+		conn, _, err := dialWS(t, srv, "client_id="+id+"&organization_id=org-a")
+		if err != nil {
+			t.Fatalf("Failed to connect %s: %v", id, err)
+		}
+		defer conn.Close()
+	}
+
+	_, resp, err := dialWS(t, srv, "client_id=drone-3&organization_id=org-a")
+	if err == nil || resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 for a third connection, got %v", err)
+	}
+
+	// Other organizations and reconnects within the limit are unaffected
+	other, _, err := dialWS(t, srv, "client_id=drone-9&organization_id=org-b")
+	if err != nil {
+		t.Fatalf("Expected another org to connect: %v", err)
+	}
+	defer other.Close()
+	again, _, err := dialWS(t, srv, "client_id=drone-1&organization_id=org-a")
+	if err != nil {
+		t.Fatalf("Expected a reconnect to replace its old connection: %v", err)
+	}
+	defer again.Close()
+	if n := wm.ConnectionCount("org-a"); n != 2 {
+		t.Errorf("Expected 2 connections for org-a, got %d", n)
+	}
+}
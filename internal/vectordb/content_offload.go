@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package vectordb
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+)
+
+// ContentOffloadedKey marks a payload whose "content" is only a preview; the full text is in the ContentStore
+const ContentOffloadedKey = "content_offloaded"
+
+// ContentStore keeps full chunk content outside the vector payload (the SQLite chunks table)
+type ContentStore interface {
+	// SaveContent stores a point's full content; metadata carries its document_id, chunk_index and organization_id
+	SaveContent(ctx context.Context, id string, metadata map[string]string, content string) error
+	// LoadContent returns the full content of the given points, keyed by ID; missing points are omitted
+	LoadContent(ctx context.Context, ids []string) (map[string]string, error)
+}
+
+// PayloadPreviewCharsFromEnv reads PAYLOAD_CONTENT_PREVIEW_CHARS: above zero, chunk content is kept
+// only in SQLite with a preview of that many characters in the payload; unset or zero keeps it all
+func PayloadPreviewCharsFromEnv() int {
+	v := os.Getenv("PAYLOAD_CONTENT_PREVIEW_CHARS")
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		log.Printf("Invalid PAYLOAD_CONTENT_PREVIEW_CHARS %q, storing full content in the payload", v)
+		return 0
+	}
+	return n
+}
+
+// offloadContent saves content longer than previewChars to the store and returns a copy of the
+// metadata holding only a preview. Metadata is returned unchanged when offloading is off.
+func offloadContent(ctx context.Context, store ContentStore, previewChars int, id string, metadata map[string]string) (map[string]string, error) {
+	content := metadata["content"]
+	if store == nil || previewChars <= 0 {
+		return metadata, nil
+	}
+	runes := []rune(content)
+	if len(runes) <= previewChars {
+		return metadata, nil
+	}
+
+	if err := store.SaveContent(ctx, id, metadata, content); err != nil {
+		return nil, fmt.Errorf("failed to store content for point %s: %w", id, err)
+	}
+	trimmed := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		trimmed[k] = v
+	}
+	trimmed["content"] = string(runes[:previewChars])
+	trimmed[ContentOffloadedKey] = "true"
+	return trimmed, nil
+}
+
+// resolveContent replaces preview content with the full text for every offloaded match. A point whose
+// content can't be found keeps its preview, so results degrade rather than fail.
+func resolveContent(ctx context.Context, store ContentStore, matches []Match) error {
+	if store == nil {
+		return nil
+	}
+	var ids []string
+	for _, match := range matches {
+		if match.Metadata[ContentOffloadedKey] == "true" {
+			ids = append(ids, match.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	contents, err := store.LoadContent(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to load offloaded content: %w", err)
+	}
+	for i, match := range matches {
+		if match.Metadata[ContentOffloadedKey] != "true" {
+			continue
+		}
+		content, ok := contents[match.ID]
+		if !ok {
+			log.Printf("Warning: full content for point %s not found, using the payload preview", match.ID)
+			continue
+		}
+		metadata := make(map[string]string, len(match.Metadata))
+		for k, v := range match.Metadata {
+			metadata[k] = v
+		}
+		metadata["content"] = content
+		delete(metadata, ContentOffloadedKey)
+		matches[i].Metadata = metadata
+	}
+	return nil
+}
+
+// resolvePayloadContent is resolveContent for a single point's payload
+func resolvePayloadContent(ctx context.Context, store ContentStore, id string, payload map[string]string) (map[string]string, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	matches := []Match{{ID: id, Metadata: payload}}
+	if err := resolveContent(ctx, store, matches); err != nil {
+		return nil, err
+	}
+	return matches[0].Metadata, nil
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package vectordb
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// memoryPoint is a vector and payload held by MemoryVectorDB
+type memoryPoint struct {
+	vector   []float32
+	metadata map[string]string
+}
+
+// MemoryVectorDB is an in-process vector store with exact cosine search, for self-tests and
+// tools that run without Qdrant. It is not meant for production-sized collections.
+type MemoryVectorDB struct {
+	mu           sync.RWMutex
+	points       map[string]memoryPoint
+	contentStore ContentStore
+	previewChars int
+}
+
+// NewMemoryVectorDB creates an empty in-memory vector store
+func NewMemoryVectorDB() *MemoryVectorDB {
+	return &MemoryVectorDB{points: make(map[string]memoryPoint)}
+}
+
+// SetContentStore keeps chunk content longer than previewChars only in store, as QdrantVectorDB does
+func (m *MemoryVectorDB) SetContentStore(store ContentStore, previewChars int) {
+	m.contentStore = store
+	m.previewChars = previewChars
+}
+
+// Upsert stores or replaces a point
+func (m *MemoryVectorDB) Upsert(ctx context.Context, id string, vector []float32, metadata map[string]string) error {
+	return m.UpsertBatch(ctx, []PointInput{{ID: id, Vector: vector, Metadata: metadata}})
+}
+
+// UpsertBatch stores or replaces the points; none are stored if any point's content can't be offloaded
+func (m *MemoryVectorDB) UpsertBatch(ctx context.Context, points []PointInput) error {
+	stored := make(map[string]memoryPoint, len(points))
+	for _, p := range points {
+		metadata, err := offloadContent(ctx, m.contentStore, m.previewChars, p.ID, p.Metadata)
+		if err != nil {
+			return err
+		}
+		payload := make(map[string]string, len(metadata))
+		for k, v := range metadata {
+			payload[k] = v
+		}
+		stored[p.ID] = memoryPoint{vector: append([]float32(nil), p.Vector...), metadata: payload}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, point := range stored {
+		m.points[id] = point
+	}
+	return nil
+}
+
+// Search returns the topK points most similar to the query vector, restricted to the organization when one is given
+func (m *MemoryVectorDB) Search(ctx context.Context, queryVector []float32, topK int, organizationID string) ([]Match, error) {
+	return m.SearchFiltered(ctx, queryVector, topK, organizationID, nil)
+}
+
+// SearchFiltered is Search restricted to points whose payload fields equal the given values
+func (m *MemoryVectorDB) SearchFiltered(ctx context.Context, queryVector []float32, topK int, organizationID string, filters map[string]string) ([]Match, error) {
+	m.mu.RLock()
+	matches := make([]Match, 0, len(m.points))
+	for id, point := range m.points {
+		if organizationID != "" && point.metadata["organization_id"] != organizationID {
+			continue
+		}
+		if !payloadMatches(point.metadata, filters) {
+			continue
+		}
+		matches = append(matches, Match{
+			ID:         id,
+			DocumentID: point.metadata["document_id"],
+			Score:      float32(CosineSimilarity(queryVector, point.vector)),
+			Metadata:   point.metadata,
+		})
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if topK > 0 && len(matches) > topK {
+		matches = matches[:topK]
+	}
+	m.mu.RUnlock()
+
+	if err := resolveContent(ctx, m.contentStore, matches); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// payloadMatches reports whether the payload has every field value in filters
+func payloadMatches(payload, filters map[string]string) bool {
+	for key, value := range filters {
+		if payload[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Delete removes a point
+func (m *MemoryVectorDB) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.points, id)
+	return nil
+}
+
+// GetPointCount returns the number of stored points
+func (m *MemoryVectorDB) GetPointCount(ctx context.Context) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.points), nil
+}
+
+// CountByOrganization returns the number of points stored for the organization
+func (m *MemoryVectorDB) CountByOrganization(ctx context.Context, organizationID string) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	count := 0
+	for _, point := range m.points {
+		if point.metadata["organization_id"] == organizationID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ListPointIDs returns the IDs of the organization's points
+func (m *MemoryVectorDB) ListPointIDs(ctx context.Context, organizationID string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var ids []string
+	for id, point := range m.points {
+		if point.metadata["organization_id"] == organizationID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// GetPayload returns a copy of the point's payload, or nil if there is no such point
+func (m *MemoryVectorDB) GetPayload(ctx context.Context, id string) (map[string]string, error) {
+	m.mu.RLock()
+	point, ok := m.points[id]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+	payload := make(map[string]string, len(point.metadata))
+	for k, v := range point.metadata {
+		payload[k] = v
+	}
+	return resolvePayloadContent(ctx, m.contentStore, id, payload)
+}
+
+// UpdatePayload is a no-op; tags aren't searched in memory
+func (m *MemoryVectorDB) UpdatePayload(ctx context.Context, id string, tags []string) error {
+	return nil
+}
+
+// SetPayloadFields sets fields on an existing point's payload; a missing point is ignored
+func (m *MemoryVectorDB) SetPayloadFields(ctx context.Context, id string, fields map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	point, ok := m.points[id]
+	if !ok {
+		return nil
+	}
+	for key, value := range fields {
+		point.metadata[key] = value
+	}
+	return nil
+}
+
+// PurgeCollection removes every point
+func (m *MemoryVectorDB) PurgeCollection(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.points = make(map[string]memoryPoint)
+	return nil
+}
+
+// PurgeByOrganization removes the organization's points and returns how many were removed
+func (m *MemoryVectorDB) PurgeByOrganization(ctx context.Context, organizationID string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	removed := 0
+	for id, point := range m.points {
+		if point.metadata["organization_id"] == organizationID {
+			delete(m.points, id)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// DeleteByDocument removes the document's points (only the organization's, if one is given)
+// and returns how many were removed
+func (m *MemoryVectorDB) DeleteByDocument(ctx context.Context, documentID, organizationID string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	removed := 0
+	for id, point := range m.points {
+		if point.metadata["document_id"] != documentID {
+			continue
+		}
+		if organizationID != "" && point.metadata["organization_id"] != organizationID {
+			continue
+		}
+		delete(m.points, id)
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package vectordb
+
+import "math"
+
+// DefaultMMRLambda weights relevance against diversity (1 = pure relevance, 0 = pure diversity)
+const DefaultMMRLambda = 0.7
+
+// MMR re-selects k matches by Maximal Marginal Relevance:
+// each step picks the candidate maximizing lambda*relevance - (1-lambda)*max similarity to already selected matches.
+// Relevance is the search score. Candidates without vectors are never penalized for redundancy.
+// Candidates must be ordered by descending score; with fewer than k candidates all are returned.
+func MMR(candidates []Match, k int, lambda float64) []Match {
+	if k <= 0 {
+		return []Match{}
+	}
+	if len(candidates) <= k {
+		return candidates
+	}
+
+	selected := make([]Match, 0, k)
+	used := make([]bool, len(candidates))
+	// maxSim[i] is the highest similarity of candidate i to any selected match
+	maxSim := make([]float64, len(candidates))
+
+	for len(selected) < k {
+		best := -1
+		bestScore := math.Inf(-1)
+		for i, candidate := range candidates {
+			if used[i] {
+				continue
+			}
+			score := lambda*float64(candidate.Score) - (1-lambda)*maxSim[i]
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		used[best] = true
+		selected = append(selected, candidates[best])
+		for i, candidate := range candidates {
+			if used[i] {
+				continue
+			}
+			if sim := CosineSimilarity(candidate.Vector, candidates[best].Vector); sim > maxSim[i] {
+				maxSim[i] = sim
+			}
+		}
+	}
+
+	return selected
+}
+
+// CosineSimilarity returns the cosine similarity of a and b, or 0 if either is missing
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
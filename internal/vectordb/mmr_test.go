@@ -0,0 +1,48 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package vectordb
+
+import "testing"
+
+func matchIDs(matches []Match) []string {
+	ids := make([]string, len(matches))
+	for i, m := range matches {
+		ids[i] = m.ID
+	}
+	return ids
+}
+
+func TestMMR_SwapsRedundantChunkForDiverseOne(t *testing.T) {
+	candidates := []Match{
+		{ID: "a1", Score: 0.95, Vector: []float32{1, 0, 0}},
+		{ID: "a2", Score: 0.94, Vector: []float32{0.99, 0.01, 0}}, // Near-duplicate of a1
+		{ID: "b", Score: 0.80, Vector: []float32{0, 1, 0}},
+		{ID: "c", Score: 0.30, Vector: []float32{0, 0, 1}},
+	}
+
+	selected := MMR(candidates, 2, 0.5)
+	ids := matchIDs(selected)
+	if len(ids) != 2 || ids[0] != "a1" || ids[1] != "b" {
+		t.Errorf("Expected MMR to pick [a1 b], got %v", ids)
+	}
+
+	// With lambda 1 MMR is plain relevance ranking
+	if ids := matchIDs(MMR(candidates, 2, 1)); ids[0] != "a1" || ids[1] != "a2" {
+		t.Errorf("Expected lambda 1 to keep top-K order, got %v", ids)
+	}
+}
+
+func TestMMR_Bounds(t *testing.T) {
+	candidates := []Match{{ID: "a", Score: 0.9}, {ID: "b", Score: 0.8}}
+
+	if got := MMR(candidates, 5, 0.7); len(got) != 2 {
+		t.Errorf("Expected all candidates when fewer than k, got %v", matchIDs(got))
+	}
+	if got := MMR(candidates, 0, 0.7); len(got) != 0 {
+		t.Errorf("Expected no matches for k=0, got %v", matchIDs(got))
+	}
+	// Without vectors MMR falls back to score order
+	if ids := matchIDs(MMR(append(candidates, Match{ID: "c", Score: 0.1}), 2, 0.5)); ids[0] != "a" || ids[1] != "b" {
+		t.Errorf("Expected score order without vectors, got %v", ids)
+	}
+}
@@ -19,6 +19,11 @@ func (m *MockVectorDB) Upsert(ctx context.Context, id string, vector []float32,
 	return nil
 }
 
+// UpsertBatch is a no-op for mock
+func (m *MockVectorDB) UpsertBatch(ctx context.Context, points []PointInput) error {
+	return nil
+}
+
 // Search returns empty results for mock
 func (m *MockVectorDB) Search(ctx context.Context, queryVector []float32, topK int, organizationID string) ([]Match, error) {
 	return []Match{}, nil
@@ -48,3 +53,8 @@ func (m *MockVectorDB) PurgeCollection(ctx context.Context) error {
 func (m *MockVectorDB) PurgeByOrganization(ctx context.Context, organizationID string) (int, error) {
 	return 0, nil
 }
+
+// DeleteByDocument is a no-op for mock
+func (m *MockVectorDB) DeleteByDocument(ctx context.Context, documentID, organizationID string) (int, error) {
+	return 0, nil
+}
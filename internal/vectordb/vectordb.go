@@ -8,6 +8,9 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"os"
+	"sort"
+	"strings"
 
 	qdrant "github.com/qdrant/go-client/qdrant"
 	"google.golang.org/grpc"
@@ -19,17 +22,119 @@ type Match struct {
 	DocumentID string
 	Score      float32
 	Metadata   map[string]string
+	Vector     []float32 // Only populated by SearchWithVectors
 }
 
 // VectorDB describes the behaviour required by the Hive service.
 type VectorDB interface {
 	Upsert(ctx context.Context, id string, vector []float32, metadata map[string]string) error
+	UpsertBatch(ctx context.Context, points []PointInput) error // Store or update several points in one call
 	Search(ctx context.Context, queryVector []float32, topK int, organizationID string) ([]Match, error)
 	Delete(ctx context.Context, id string) error
 	GetPointCount(ctx context.Context) (int, error)
 	UpdatePayload(ctx context.Context, id string, tags []string) error
 	PurgeCollection(ctx context.Context) error // Delete all points from the collection
 	PurgeByOrganization(ctx context.Context, organizationID string) (int, error) // Delete all points for a specific organization
+	DeleteByDocument(ctx context.Context, documentID, organizationID string) (int, error) // Delete a document's points (organizationID optional)
+}
+
+// PointInput is a point to store with UpsertBatch
+type PointInput struct {
+	ID       string
+	Vector   []float32
+	Metadata map[string]string
+}
+
+// DefaultPayloadIndexFields are the payload fields filtered on by search and purge.
+// Override with QDRANT_PAYLOAD_INDEX_FIELDS (comma-separated).
+var DefaultPayloadIndexFields = []string{"organization_id", "document_id", "filetype", "client_id", "language"}
+
+// PayloadIndexer is implemented by vector databases that support payload field indexes.
+type PayloadIndexer interface {
+	EnsurePayloadIndexes(ctx context.Context) ([]string, error)
+}
+
+// VectorSearcher is implemented by vector databases that can return stored vectors with search results.
+type VectorSearcher interface {
+	SearchWithVectors(ctx context.Context, queryVector []float32, topK int, organizationID string) ([]Match, error)
+}
+
+// FilteredSearcher is implemented by vector databases that can restrict a search to points
+// whose payload fields equal the given values (e.g. {"language": "de"}).
+type FilteredSearcher interface {
+	SearchFiltered(ctx context.Context, queryVector []float32, topK int, organizationID string, filters map[string]string) ([]Match, error)
+}
+
+// PayloadReader is implemented by vector databases that can return a point's stored payload.
+type PayloadReader interface {
+	GetPayload(ctx context.Context, id string) (map[string]string, error)
+}
+
+// PayloadFieldSetter is implemented by vector databases that can set payload fields on an existing
+// point without re-uploading its vector (other fields are kept).
+type PayloadFieldSetter interface {
+	SetPayloadFields(ctx context.Context, id string, fields map[string]string) error
+}
+
+// PointLister is implemented by vector databases that can list the IDs of an organization's points
+// (used to reconcile them with the SQLite chunks table).
+type PointLister interface {
+	ListPointIDs(ctx context.Context, organizationID string) ([]string, error)
+}
+
+// CollectionNamer is implemented by vector databases that store points in a named collection.
+type CollectionNamer interface {
+	CollectionName() string
+}
+
+// OrganizationCounter is implemented by vector databases that can count an organization's points
+// without reading them (used to preview a purge).
+type OrganizationCounter interface {
+	CountByOrganization(ctx context.Context, organizationID string) (int, error)
+}
+
+// QdrantConfig configures the collection used by NewQdrantVectorDB
+type QdrantConfig struct {
+	CollectionName   string // Several Hive instances can share a Qdrant cluster with separate collections
+	Distance         string // Similarity metric of a new collection: cosine, dot, euclid or manhattan
+	DefaultDimension int    // Vector size of a new collection; an existing collection keeps its own
+}
+
+// Qdrant collection defaults
+const (
+	DefaultQdrantCollection = "the_hive"
+	DefaultQdrantDistance   = "cosine"
+	DefaultQdrantDimension  = 1536
+)
+
+// DefaultQdrantConfig returns the configuration used when nothing is set
+func DefaultQdrantConfig() QdrantConfig {
+	return QdrantConfig{
+		CollectionName:   DefaultQdrantCollection,
+		Distance:         DefaultQdrantDistance,
+		DefaultDimension: DefaultQdrantDimension,
+	}
+}
+
+// qdrantDistances maps the accepted distance names to Qdrant metrics
+var qdrantDistances = map[string]qdrant.Distance{
+	"cosine":    qdrant.Distance_Cosine,
+	"dot":       qdrant.Distance_Dot,
+	"euclid":    qdrant.Distance_Euclid,
+	"manhattan": qdrant.Distance_Manhattan,
+}
+
+// ParseDistance returns the Qdrant metric for a distance name (case-insensitive; empty means cosine)
+func ParseDistance(name string) (qdrant.Distance, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = DefaultQdrantDistance
+	}
+	distance, ok := qdrantDistances[name]
+	if !ok {
+		return qdrant.Distance_UnknownDistance, fmt.Errorf("unknown Qdrant distance %q (use cosine, dot, euclid or manhattan)", name)
+	}
+	return distance, nil
 }
 
 // QdrantVectorDB is a thin wrapper around the Qdrant service clients.
@@ -37,19 +142,40 @@ type QdrantVectorDB struct {
 	collectionsSvc qdrant.CollectionsClient
 	pointsSvc      qdrant.PointsClient
 	collection     string
+	distance       qdrant.Distance // Used when creating the collection
 	dimension      int
+	indexFields    []string // Payload fields that get a keyword index
+	requireOrg     bool     // Strict mode: searches without an organization ID fail instead of scanning all orgs
+	contentStore   ContentStore
+	previewChars   int // Above zero, payloads keep only this much content; the rest is in contentStore
 }
 
+// ErrOrganizationRequired is returned by Search in strict mode when no organization ID is given
+var ErrOrganizationRequired = errors.New("organization ID is required for search (strict organization mode)")
+
+// ErrDimensionMismatch is returned when a vector's length differs from the collection's vector size
+var ErrDimensionMismatch = errors.New("vector dimension does not match the collection")
+
 // NewQdrantVectorDB constructs a new wrapper and ensures the collection exists.
-// It accepts the gRPC connection to create service clients directly.
-func NewQdrantVectorDB(conn *grpc.ClientConn) (*QdrantVectorDB, error) {
+// It accepts the gRPC connection to create service clients directly; zero values in cfg take the defaults.
+func NewQdrantVectorDB(conn *grpc.ClientConn, cfg QdrantConfig) (*QdrantVectorDB, error) {
 	if conn == nil {
 		return nil, errors.New("gRPC connection is required")
 	}
 
-	collectionName := "the_hive"
-	// Default dimension - will be updated when first vector is inserted
-	defaultDim := 1536
+	distance, err := ParseDistance(cfg.Distance)
+	if err != nil {
+		return nil, err
+	}
+	collectionName := cfg.CollectionName
+	if collectionName == "" {
+		collectionName = DefaultQdrantCollection
+	}
+	// Only used to create the collection; an existing one reports its own size
+	defaultDim := cfg.DefaultDimension
+	if defaultDim <= 0 {
+		defaultDim = DefaultQdrantDimension
+	}
 
 	// Create service clients from the gRPC connection
 	collectionsSvc := qdrant.NewCollectionsClient(conn)
@@ -59,7 +185,10 @@ func NewQdrantVectorDB(conn *grpc.ClientConn) (*QdrantVectorDB, error) {
 		collectionsSvc: collectionsSvc,
 		pointsSvc:      pointsSvc,
 		collection:     collectionName,
+		distance:       distance,
 		dimension:      defaultDim,
+		indexFields:    payloadIndexFieldsFromEnv(),
+		requireOrg:     strictOrgModeFromEnv(),
 	}
 
 	// Ensure collection exists
@@ -70,7 +199,8 @@ func NewQdrantVectorDB(conn *grpc.ClientConn) (*QdrantVectorDB, error) {
 	return vdb, nil
 }
 
-// ensureCollection creates the collection if it doesn't exist.
+// ensureCollection creates the collection with dimension dim if it doesn't exist, and records the
+// collection's actual vector size (which an existing collection keeps whatever dim is).
 func (q *QdrantVectorDB) ensureCollection(ctx context.Context, dim int) error {
 	log.Printf("Ensuring Qdrant collection %s exists with dimension %d", q.collection, dim)
 
@@ -90,6 +220,10 @@ func (q *QdrantVectorDB) ensureCollection(ctx context.Context, dim int) error {
 	}
 
 	if !exists {
+		distance := q.distance
+		if distance == qdrant.Distance_UnknownDistance {
+			distance = qdrant.Distance_Cosine
+		}
 		// Create the collection
 		_, err = q.collectionsSvc.Create(ctx, &qdrant.CreateCollection{
 			CollectionName: q.collection,
@@ -97,7 +231,7 @@ func (q *QdrantVectorDB) ensureCollection(ctx context.Context, dim int) error {
 				Config: &qdrant.VectorsConfig_Params{
 					Params: &qdrant.VectorParams{
 						Size:     uint64(dim),
-						Distance: qdrant.Distance_Cosine,
+						Distance: distance,
 					},
 				},
 			},
@@ -105,28 +239,164 @@ func (q *QdrantVectorDB) ensureCollection(ctx context.Context, dim int) error {
 		if err != nil {
 			return fmt.Errorf("failed to create collection: %w", err)
 		}
-		log.Printf("Created Qdrant collection %s with dimension %d", q.collection, dim)
+		log.Printf("Created Qdrant collection %s with dimension %d and %s distance", q.collection, dim, distance)
+	} else {
+		info, err := q.collectionsSvc.Get(ctx, &qdrant.GetCollectionInfoRequest{CollectionName: q.collection})
+		if err != nil {
+			return fmt.Errorf("failed to read collection %s: %w", q.collection, err)
+		}
+		size := info.GetResult().GetConfig().GetParams().GetVectorsConfig().GetParams().GetSize()
+		if size == 0 {
+			return fmt.Errorf("collection %s has no single vector size (named vectors are not supported)", q.collection)
+		}
+		if int(size) != dim {
+			log.Printf("Qdrant collection %s exists with dimension %d (not %d)", q.collection, size, dim)
+		}
+		dim = int(size)
+	}
+
+	// Index filterable payload fields; a missing index only slows filtered search, so don't fail
+	if _, err := q.EnsurePayloadIndexes(ctx); err != nil {
+		log.Printf("Warning: failed to create payload indexes on %s: %v", q.collection, err)
 	}
 
 	q.dimension = dim
 	return nil
 }
 
+// EnsurePayloadIndexes creates a keyword index for each configured payload field.
+// Qdrant treats re-creating an existing index as a no-op, so this is safe to call on existing collections.
+// Returns the fields that were indexed.
+func (q *QdrantVectorDB) EnsurePayloadIndexes(ctx context.Context) ([]string, error) {
+	fieldType := qdrant.FieldType_FieldTypeKeyword
+	wait := true
+
+	indexed := make([]string, 0, len(q.indexFields))
+	for _, field := range q.indexFields {
+		_, err := q.pointsSvc.CreateFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
+			CollectionName: q.collection,
+			Wait:           &wait,
+			FieldName:      field,
+			FieldType:      &fieldType,
+		})
+		if err != nil {
+			return indexed, fmt.Errorf("failed to create payload index for %s: %w", field, err)
+		}
+		indexed = append(indexed, field)
+	}
+
+	log.Printf("Ensured payload indexes on %s: %v", q.collection, indexed)
+	return indexed, nil
+}
+
+// payloadIndexFieldsFromEnv returns the payload fields to index from QDRANT_PAYLOAD_INDEX_FIELDS,
+// falling back to DefaultPayloadIndexFields. organization_id is always indexed for tenant isolation.
+func payloadIndexFieldsFromEnv() []string {
+	fieldsStr := os.Getenv("QDRANT_PAYLOAD_INDEX_FIELDS")
+	if fieldsStr == "" {
+		return DefaultPayloadIndexFields
+	}
+
+	fields := []string{"organization_id"}
+	for _, field := range strings.Split(fieldsStr, ",") {
+		field = strings.TrimSpace(field)
+		if field != "" && field != "organization_id" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// strictOrgModeFromEnv reads SEARCH_ORG_MODE: "strict" rejects searches without an organization ID,
+// "permissive" (the default, for single-tenant installs) searches all organizations
+func strictOrgModeFromEnv() bool {
+	mode := strings.ToLower(strings.TrimSpace(os.Getenv("SEARCH_ORG_MODE")))
+	if mode != "" && mode != "strict" && mode != "permissive" {
+		log.Printf("Warning: unknown SEARCH_ORG_MODE %q, using permissive", mode)
+	}
+	return mode == "strict"
+}
+
+// Dimension returns the collection's vector size; vectors of any other length are rejected
+func (q *QdrantVectorDB) Dimension() int {
+	return q.dimension
+}
+
+// CollectionName returns the name of the Qdrant collection
+func (q *QdrantVectorDB) CollectionName() string {
+	return q.collection
+}
+
+// RequiresOrganization reports whether Search rejects queries without an organization ID (SEARCH_ORG_MODE=strict)
+func (q *QdrantVectorDB) RequiresOrganization() bool {
+	return q.requireOrg
+}
+
+// SetContentStore keeps chunk content longer than previewChars only in store, with a preview in the
+// payload. Search and GetPayload resolve the full content from the store.
+func (q *QdrantVectorDB) SetContentStore(store ContentStore, previewChars int) {
+	q.contentStore = store
+	q.previewChars = previewChars
+}
+
 // Upsert stores or updates a vector in Qdrant.
 // CRITICAL: organization_id must be included in metadata for multi-tenancy isolation
 func (q *QdrantVectorDB) Upsert(ctx context.Context, id string, vector []float32, metadata map[string]string) error {
-	if len(vector) == 0 {
-		return errors.New("vector cannot be empty")
+	return q.UpsertBatch(ctx, []PointInput{{ID: id, Vector: vector, Metadata: metadata}})
+}
+
+// UpsertBatch stores or updates the points in Qdrant with a single upsert call.
+// CRITICAL: organization_id must be included in each point's metadata for multi-tenancy isolation
+func (q *QdrantVectorDB) UpsertBatch(ctx context.Context, points []PointInput) error {
+	if len(points) == 0 {
+		return nil
+	}
+	dim := len(points[0].Vector)
+	for _, p := range points {
+		if len(p.Vector) == 0 {
+			return errors.New("vector cannot be empty")
+		}
+		if len(p.Vector) != dim {
+			return fmt.Errorf("vectors in a batch must have the same dimension (%d and %d)", dim, len(p.Vector))
+		}
+	}
+
+	// Never recreate the collection to fit: that would drop its config and mix incompatible vectors
+	if q.dimension > 0 && dim != q.dimension {
+		return fmt.Errorf("%w: got %d-dimension vectors, collection %s stores %d (was the embedder changed?)", ErrDimensionMismatch, dim, q.collection, q.dimension)
 	}
 
-	// Update dimension if needed and ensure collection exists with correct dimension
-	if q.dimension != len(vector) {
-		log.Printf("Updating collection dimension from %d to %d", q.dimension, len(vector))
-		// Note: Collection deletion/creation needs proper Qdrant API calls
-		// For now, update dimension and let ensureCollection handle it
-		if err := q.ensureCollection(ctx, len(vector)); err != nil {
+	structs := make([]*qdrant.PointStruct, 0, len(points))
+	for _, p := range points {
+		point, err := q.pointStruct(ctx, p.ID, p.Vector, p.Metadata)
+		if err != nil {
 			return err
 		}
+		structs = append(structs, point)
+	}
+
+	// Upsert the points
+	_, err := q.pointsSvc.Upsert(ctx, &qdrant.UpsertPoints{
+		CollectionName: q.collection,
+		Points:         structs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert %d point(s): %w", len(points), err)
+	}
+
+	// Log success for watchdog monitoring
+	for _, p := range points {
+		log.Printf("vector upsert success for chunk %s", p.ID)
+	}
+
+	return nil
+}
+
+// pointStruct converts a point to Qdrant's format, moving long content to the content store
+func (q *QdrantVectorDB) pointStruct(ctx context.Context, id string, vector []float32, metadata map[string]string) (*qdrant.PointStruct, error) {
+	metadata, err := offloadContent(ctx, q.contentStore, q.previewChars, id, metadata)
+	if err != nil {
+		return nil, err
 	}
 
 	// Convert metadata to Qdrant format
@@ -177,29 +447,51 @@ func (q *QdrantVectorDB) Upsert(ctx context.Context, id string, vector []float32
 		Payload: payload,
 	}
 
-	// Upsert the point
-	_, err := q.pointsSvc.Upsert(ctx, &qdrant.UpsertPoints{
-		CollectionName: q.collection,
-		Points:         []*qdrant.PointStruct{point},
-	})
-	if err != nil {
-		return fmt.Errorf("failed to upsert point: %w", err)
-	}
-
-	// Log success for watchdog monitoring
-	log.Printf("vector upsert success for chunk %s", id)
-
-	return nil
+	return point, nil
 }
 
 // Search performs a similarity search.
 // CRITICAL: organizationID must be provided for multi-tenancy isolation
 // If organizationID is empty, search will return results from all organizations (backward compatibility)
 func (q *QdrantVectorDB) Search(ctx context.Context, queryVector []float32, topK int, organizationID string) ([]Match, error) {
+	return q.search(ctx, queryVector, topK, organizationID, nil, false)
+}
+
+// SearchWithVectors performs a similarity search and includes each match's stored vector
+func (q *QdrantVectorDB) SearchWithVectors(ctx context.Context, queryVector []float32, topK int, organizationID string) ([]Match, error) {
+	return q.search(ctx, queryVector, topK, organizationID, nil, true)
+}
+
+// SearchFiltered performs a similarity search restricted to points whose payload matches every filter
+func (q *QdrantVectorDB) SearchFiltered(ctx context.Context, queryVector []float32, topK int, organizationID string, filters map[string]string) ([]Match, error) {
+	return q.search(ctx, queryVector, topK, organizationID, filters, false)
+}
+
+// keywordCondition matches points whose payload field equals the value
+func keywordCondition(key, value string) *qdrant.Condition {
+	return &qdrant.Condition{
+		ConditionOneOf: &qdrant.Condition_Field{
+			Field: &qdrant.FieldCondition{
+				Key: key,
+				Match: &qdrant.Match{
+					MatchValue: &qdrant.Match_Keyword{
+						Keyword: value,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (q *QdrantVectorDB) search(ctx context.Context, queryVector []float32, topK int, organizationID string, filters map[string]string, withVectors bool) ([]Match, error) {
 	if len(queryVector) == 0 {
 		return nil, errors.New("query vector cannot be empty")
 	}
 
+	if organizationID == "" && q.requireOrg {
+		return nil, ErrOrganizationRequired
+	}
+
 	if topK <= 0 {
 		topK = 10
 	}
@@ -210,30 +502,29 @@ func (q *QdrantVectorDB) Search(ctx context.Context, queryVector []float32, topK
 		Vector:         queryVector,
 		Limit:          uint64(topK),
 		WithPayload:    &qdrant.WithPayloadSelector{SelectorOptions: &qdrant.WithPayloadSelector_Enable{Enable: true}},
-		WithVectors:    &qdrant.WithVectorsSelector{SelectorOptions: &qdrant.WithVectorsSelector_Enable{Enable: false}},
+		WithVectors:    &qdrant.WithVectorsSelector{SelectorOptions: &qdrant.WithVectorsSelector_Enable{Enable: withVectors}},
 	}
 	
 	// CRITICAL: Apply organization filter for multi-tenancy isolation
+	var must []*qdrant.Condition
 	if organizationID != "" {
-		searchReq.Filter = &qdrant.Filter{
-			Must: []*qdrant.Condition{
-				{
-					ConditionOneOf: &qdrant.Condition_Field{
-						Field: &qdrant.FieldCondition{
-							Key: "organization_id",
-							Match: &qdrant.Match{
-								MatchValue: &qdrant.Match_Keyword{
-									Keyword: organizationID,
-								},
-							},
-						},
-					},
-				},
-			},
-		}
+		must = append(must, keywordCondition("organization_id", organizationID))
 	} else {
 		log.Printf("Warning: Search called without organizationID - results may include data from all organizations")
 	}
+	filterKeys := make([]string, 0, len(filters))
+	for key := range filters {
+		if key != "organization_id" {
+			filterKeys = append(filterKeys, key)
+		}
+	}
+	sort.Strings(filterKeys)
+	for _, key := range filterKeys {
+		must = append(must, keywordCondition(key, filters[key]))
+	}
+	if len(must) > 0 {
+		searchReq.Filter = &qdrant.Filter{Must: must}
+	}
 
 	// Perform search
 	searchResult, err := q.pointsSvc.Search(ctx, searchReq)
@@ -283,9 +574,13 @@ func (q *QdrantVectorDB) Search(ctx context.Context, queryVector []float32, topK
 			DocumentID: documentID,
 			Score:      scoredPoint.Score,
 			Metadata:   metadata,
+			Vector:     scoredPoint.GetVectors().GetVector().GetData(),
 		})
 	}
 
+	if err := resolveContent(ctx, q.contentStore, matches); err != nil {
+		return nil, err
+	}
 	return matches, nil
 }
 
@@ -313,37 +608,60 @@ func (q *QdrantVectorDB) GetPointCount(ctx context.Context) (int, error) {
 	return 0, nil
 }
 
-// UpdatePayload updates the payload (metadata) of an existing point
-func (q *QdrantVectorDB) UpdatePayload(ctx context.Context, id string, tags []string) error {
-	// Convert string ID to Qdrant PointId
-	var pointID *qdrant.PointId
+// CountByOrganization returns the exact number of points stored for the organization
+func (q *QdrantVectorDB) CountByOrganization(ctx context.Context, organizationID string) (int, error) {
+	if organizationID == "" {
+		return 0, errors.New("organizationID is required")
+	}
+	exact := true
+	resp, err := q.pointsSvc.Count(ctx, &qdrant.CountPoints{
+		CollectionName: q.collection,
+		Filter:         &qdrant.Filter{Must: []*qdrant.Condition{keywordCondition("organization_id", organizationID)}},
+		Exact:          &exact,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count points for organization %s: %w", organizationID, err)
+	}
+	return int(resp.GetResult().GetCount()), nil
+}
 
-	// Check if it's a valid UUID format (simplified check)
-	if len(id) > 20 {
-		// Assume UUID format
-		pointID = &qdrant.PointId{
-			PointIdOptions: &qdrant.PointId_Uuid{
-				Uuid: id,
-			},
+// ListPointIDs returns the IDs of every point stored for the organization
+func (q *QdrantVectorDB) ListPointIDs(ctx context.Context, organizationID string) ([]string, error) {
+	if organizationID == "" {
+		return nil, errors.New("organizationID is required")
+	}
+	limit := uint32(1000)
+	request := &qdrant.ScrollPoints{
+		CollectionName: q.collection,
+		Filter:         &qdrant.Filter{Must: []*qdrant.Condition{keywordCondition("organization_id", organizationID)}},
+		WithPayload:    &qdrant.WithPayloadSelector{SelectorOptions: &qdrant.WithPayloadSelector_Enable{Enable: false}},
+		WithVectors:    &qdrant.WithVectorsSelector{SelectorOptions: &qdrant.WithVectorsSelector_Enable{Enable: false}},
+		Limit:          &limit,
+	}
+
+	var ids []string
+	for {
+		resp, err := q.pointsSvc.Scroll(ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scroll points for organization %s: %w", organizationID, err)
 		}
-	} else {
-		// Try numeric
-		var numID uint64
-		if _, err := fmt.Sscanf(id, "%d", &numID); err == nil {
-			pointID = &qdrant.PointId{
-				PointIdOptions: &qdrant.PointId_Num{
-					Num: numID,
-				},
-			}
-		} else {
-			// Fallback: use as string UUID
-			pointID = &qdrant.PointId{
-				PointIdOptions: &qdrant.PointId_Uuid{
-					Uuid: id,
-				},
+		for _, point := range resp.Result {
+			if uuid := point.GetId().GetUuid(); uuid != "" {
+				ids = append(ids, uuid)
+			} else if point.GetId() != nil {
+				ids = append(ids, fmt.Sprintf("%d", point.GetId().GetNum()))
 			}
 		}
+		if resp.NextPageOffset == nil || len(resp.Result) == 0 {
+			return ids, nil
+		}
+		request.Offset = resp.NextPageOffset
 	}
+}
+
+// UpdatePayload updates the payload (metadata) of an existing point
+func (q *QdrantVectorDB) UpdatePayload(ctx context.Context, id string, tags []string) error {
+	pointID := parsePointID(id)
 
 	// Prepare tags payload
 	payload := make(map[string]*qdrant.Value)
@@ -378,6 +696,79 @@ func (q *QdrantVectorDB) UpdatePayload(ctx context.Context, id string, tags []st
 	return nil
 }
 
+// SetPayloadFields sets string fields on an existing point's payload, keeping its other fields
+func (q *QdrantVectorDB) SetPayloadFields(ctx context.Context, id string, fields map[string]string) error {
+	payload := make(map[string]*qdrant.Value, len(fields))
+	for key, value := range fields {
+		payload[key] = &qdrant.Value{Kind: &qdrant.Value_StringValue{StringValue: value}}
+	}
+	_, err := q.pointsSvc.SetPayload(ctx, &qdrant.SetPayloadPoints{
+		CollectionName: q.collection,
+		Payload:        payload,
+		PointsSelector: &qdrant.PointsSelector{PointsSelectorOneOf: &qdrant.PointsSelector_Points{Points: &qdrant.PointsIdsList{Ids: []*qdrant.PointId{parsePointID(id)}}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set payload fields: %w", err)
+	}
+	return nil
+}
+
+// parsePointID converts a string ID to a Qdrant point ID (UUID, or numeric for short IDs)
+func parsePointID(id string) *qdrant.PointId {
+	var pointID *qdrant.PointId
+
+	// Check if it's a valid UUID format (simplified check)
+	if len(id) > 20 {
+		// Assume UUID format
+		pointID = &qdrant.PointId{
+			PointIdOptions: &qdrant.PointId_Uuid{
+				Uuid: id,
+			},
+		}
+	} else {
+		// Try numeric
+		var numID uint64
+		if _, err := fmt.Sscanf(id, "%d", &numID); err == nil {
+			pointID = &qdrant.PointId{
+				PointIdOptions: &qdrant.PointId_Num{
+					Num: numID,
+				},
+			}
+		} else {
+			// Fallback: use as string UUID
+			pointID = &qdrant.PointId{
+				PointIdOptions: &qdrant.PointId_Uuid{
+					Uuid: id,
+				},
+			}
+		}
+	}
+	return pointID
+}
+
+// GetPayload returns the stored payload of a point as strings, or nil if the point doesn't exist
+func (q *QdrantVectorDB) GetPayload(ctx context.Context, id string) (map[string]string, error) {
+	resp, err := q.pointsSvc.Get(ctx, &qdrant.GetPoints{
+		CollectionName: q.collection,
+		Ids:            []*qdrant.PointId{parsePointID(id)},
+		WithPayload:    &qdrant.WithPayloadSelector{SelectorOptions: &qdrant.WithPayloadSelector_Enable{Enable: true}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get point: %w", err)
+	}
+	if len(resp.Result) == 0 {
+		return nil, nil
+	}
+
+	payload := make(map[string]string, len(resp.Result[0].Payload))
+	for key, value := range resp.Result[0].Payload {
+		if strValue := value.GetStringValue(); strValue != "" {
+			payload[key] = strValue
+		}
+	}
+	return resolvePayloadContent(ctx, q.contentStore, id, payload)
+}
+
 // Delete removes a vector from the collection.
 func (q *QdrantVectorDB) Delete(ctx context.Context, id string) error {
 	// Convert UUID string to Qdrant PointId
@@ -590,6 +981,65 @@ func (q *QdrantVectorDB) PurgeByOrganization(ctx context.Context, organizationID
 	return len(pointIDs), nil
 }
 
+// DeleteByDocument deletes every point whose document_id matches, restricted to the organization
+// when one is given, and returns how many were deleted. Callers don't need to know the point IDs,
+// so chunks left over from an earlier, longer version of the document are removed too.
+func (q *QdrantVectorDB) DeleteByDocument(ctx context.Context, documentID, organizationID string) (int, error) {
+	if documentID == "" {
+		return 0, errors.New("documentID is required")
+	}
+	must := []*qdrant.Condition{keywordCondition("document_id", documentID)}
+	if organizationID != "" {
+		must = append(must, keywordCondition("organization_id", organizationID))
+	}
+	limit := uint32(1000)
+	request := &qdrant.ScrollPoints{
+		CollectionName: q.collection,
+		Filter:         &qdrant.Filter{Must: must},
+		WithPayload:    &qdrant.WithPayloadSelector{SelectorOptions: &qdrant.WithPayloadSelector_Enable{Enable: false}},
+		WithVectors:    &qdrant.WithVectorsSelector{SelectorOptions: &qdrant.WithVectorsSelector_Enable{Enable: false}},
+		Limit:          &limit,
+	}
+
+	var pointIDs []*qdrant.PointId
+	for {
+		resp, err := q.pointsSvc.Scroll(ctx, request)
+		if err != nil {
+			return 0, fmt.Errorf("failed to scroll points for document %s: %w", documentID, err)
+		}
+		for _, point := range resp.Result {
+			if point.Id != nil {
+				pointIDs = append(pointIDs, point.Id)
+			}
+		}
+		if resp.NextPageOffset == nil || len(resp.Result) == 0 {
+			break
+		}
+		request.Offset = resp.NextPageOffset
+	}
+
+	// Delete in batches, as PurgeByOrganization does
+	batchSize := 1000
+	for i := 0; i < len(pointIDs); i += batchSize {
+		end := min(i+batchSize, len(pointIDs))
+		_, err := q.pointsSvc.Delete(ctx, &qdrant.DeletePoints{
+			CollectionName: q.collection,
+			Points: &qdrant.PointsSelector{
+				PointsSelectorOneOf: &qdrant.PointsSelector_Points{
+					Points: &qdrant.PointsIdsList{Ids: pointIDs[i:end]},
+				},
+			},
+		})
+		if err != nil {
+			return i, fmt.Errorf("failed to delete points %d-%d of document %s: %w", i, end, documentID, err)
+		}
+	}
+	if len(pointIDs) > 0 {
+		log.Printf("Deleted %d points for document %s from collection %s", len(pointIDs), documentID, q.collection)
+	}
+	return len(pointIDs), nil
+}
+
 // getMetadataKeys returns all keys from metadata map (helper for debugging)
 func getMetadataKeys(m map[string]string) []string {
 	keys := make([]string, 0, len(m))
@@ -0,0 +1,309 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package vectordb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	qdrant "github.com/qdrant/go-client/qdrant"
+	"google.golang.org/grpc"
+)
+
+// fakeCollections reports a fixed set of existing collections
+type fakeCollections struct {
+	qdrant.CollectionsClient
+	existing  []string
+	sizes     map[string]uint64 // Vector size of each existing collection
+	created   []string
+	distances []qdrant.Distance
+}
+
+func (f *fakeCollections) List(ctx context.Context, in *qdrant.ListCollectionsRequest, opts ...grpc.CallOption) (*qdrant.ListCollectionsResponse, error) {
+	resp := &qdrant.ListCollectionsResponse{}
+	for _, name := range f.existing {
+		resp.Collections = append(resp.Collections, &qdrant.CollectionDescription{Name: name})
+	}
+	return resp, nil
+}
+
+func (f *fakeCollections) Create(ctx context.Context, in *qdrant.CreateCollection, opts ...grpc.CallOption) (*qdrant.CollectionOperationResponse, error) {
+	f.created = append(f.created, in.CollectionName)
+	f.distances = append(f.distances, in.GetVectorsConfig().GetParams().GetDistance())
+	return &qdrant.CollectionOperationResponse{Result: true}, nil
+}
+
+func (f *fakeCollections) Get(ctx context.Context, in *qdrant.GetCollectionInfoRequest, opts ...grpc.CallOption) (*qdrant.GetCollectionInfoResponse, error) {
+	return &qdrant.GetCollectionInfoResponse{Result: &qdrant.CollectionInfo{Config: &qdrant.CollectionConfig{Params: &qdrant.CollectionParams{
+		VectorsConfig: &qdrant.VectorsConfig{Config: &qdrant.VectorsConfig_Params{Params: &qdrant.VectorParams{Size: f.sizes[in.CollectionName]}}},
+	}}}}, nil
+}
+
+// fakePoints records payload index creation, search, scroll and delete requests
+type fakePoints struct {
+	qdrant.PointsClient
+	indexed  []string
+	searches []*qdrant.SearchPoints
+	scrolls  []*qdrant.ScrollPoints
+	scrolled []*qdrant.RetrievedPoint // Returned by Scroll, one page
+	deleted  []string
+	upserts  []*qdrant.UpsertPoints
+}
+
+func (f *fakePoints) Upsert(ctx context.Context, in *qdrant.UpsertPoints, opts ...grpc.CallOption) (*qdrant.PointsOperationResponse, error) {
+	f.upserts = append(f.upserts, in)
+	return &qdrant.PointsOperationResponse{}, nil
+}
+
+func (f *fakePoints) Search(ctx context.Context, in *qdrant.SearchPoints, opts ...grpc.CallOption) (*qdrant.SearchResponse, error) {
+	f.searches = append(f.searches, in)
+	return &qdrant.SearchResponse{}, nil
+}
+
+func (f *fakePoints) Scroll(ctx context.Context, in *qdrant.ScrollPoints, opts ...grpc.CallOption) (*qdrant.ScrollResponse, error) {
+	f.scrolls = append(f.scrolls, in)
+	return &qdrant.ScrollResponse{Result: f.scrolled}, nil
+}
+
+func (f *fakePoints) Delete(ctx context.Context, in *qdrant.DeletePoints, opts ...grpc.CallOption) (*qdrant.PointsOperationResponse, error) {
+	for _, id := range in.GetPoints().GetPoints().GetIds() {
+		f.deleted = append(f.deleted, id.GetUuid())
+	}
+	return &qdrant.PointsOperationResponse{}, nil
+}
+
+func (f *fakePoints) CreateFieldIndex(ctx context.Context, in *qdrant.CreateFieldIndexCollection, opts ...grpc.CallOption) (*qdrant.PointsOperationResponse, error) {
+	f.indexed = append(f.indexed, in.FieldName)
+	return &qdrant.PointsOperationResponse{}, nil
+}
+
+func TestEnsureCollection_CreatesPayloadIndexes(t *testing.T) {
+	collections := &fakeCollections{}
+	points := &fakePoints{}
+	q := &QdrantVectorDB{
+		collectionsSvc: collections,
+		pointsSvc:      points,
+		collection:     "the_hive",
+		indexFields:    []string{"organization_id", "filetype"},
+	}
+
+	if err := q.ensureCollection(context.Background(), 8); err != nil {
+		t.Fatalf("ensureCollection failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(collections.created, []string{"the_hive"}) {
+		t.Errorf("Expected collection to be created, got %v", collections.created)
+	}
+	if !reflect.DeepEqual(points.indexed, []string{"organization_id", "filetype"}) {
+		t.Errorf("Expected indexes on configured fields, got %v", points.indexed)
+	}
+}
+
+func TestEnsureCollection_UsesConfiguredDistance(t *testing.T) {
+	distance, err := ParseDistance(" Dot ")
+	if err != nil {
+		t.Fatalf("ParseDistance failed: %v", err)
+	}
+	collections := &fakeCollections{}
+	q := &QdrantVectorDB{collectionsSvc: collections, pointsSvc: &fakePoints{}, collection: "team_b", distance: distance}
+
+	if err := q.ensureCollection(context.Background(), 8); err != nil {
+		t.Fatalf("ensureCollection failed: %v", err)
+	}
+	if !reflect.DeepEqual(collections.created, []string{"team_b"}) || !reflect.DeepEqual(collections.distances, []qdrant.Distance{qdrant.Distance_Dot}) {
+		t.Errorf("Expected team_b to be created with dot distance, got %v %v", collections.created, collections.distances)
+	}
+
+	if d, err := ParseDistance(""); err != nil || d != qdrant.Distance_Cosine {
+		t.Errorf("Expected cosine by default, got %v, %v", d, err)
+	}
+	if _, err := ParseDistance("hamming"); err == nil {
+		t.Error("Expected an unknown distance to be rejected")
+	}
+	if _, err := NewQdrantVectorDB(&grpc.ClientConn{}, QdrantConfig{Distance: "hamming"}); err == nil {
+		t.Error("Expected NewQdrantVectorDB to fail on an unknown distance")
+	}
+}
+
+func TestUpsert_RejectsVectorsOfAnotherDimension(t *testing.T) {
+	collections := &fakeCollections{existing: []string{"the_hive"}, sizes: map[string]uint64{"the_hive": 6}}
+	points := &fakePoints{}
+	q := &QdrantVectorDB{collectionsSvc: collections, pointsSvc: points, collection: "the_hive"}
+
+	// An existing collection keeps its size, whatever the configured default
+	if err := q.ensureCollection(context.Background(), 4); err != nil {
+		t.Fatalf("ensureCollection failed: %v", err)
+	}
+	if q.Dimension() != 6 || len(collections.created) != 0 {
+		t.Fatalf("Expected the collection's own dimension 6 and no new collection, got %d, %v", q.Dimension(), collections.created)
+	}
+
+	err := q.Upsert(context.Background(), "11111111-1111-1111-1111-111111111111", []float32{1, 2, 3, 4}, map[string]string{"organization_id": "org-a"})
+	if !errors.Is(err, ErrDimensionMismatch) {
+		t.Fatalf("Expected ErrDimensionMismatch, got %v", err)
+	}
+	if len(points.upserts) != 0 || len(collections.created) != 0 {
+		t.Error("Expected nothing to be stored or recreated for a mismatched vector")
+	}
+
+	if err := q.Upsert(context.Background(), "11111111-1111-1111-1111-111111111111", []float32{1, 2, 3, 4, 5, 6}, map[string]string{"organization_id": "org-a"}); err != nil {
+		t.Errorf("Expected a vector of the collection's dimension to be stored, got %v", err)
+	}
+}
+
+func TestEnsurePayloadIndexes_ExistingCollection(t *testing.T) {
+	collections := &fakeCollections{existing: []string{"the_hive"}}
+	points := &fakePoints{}
+	q := &QdrantVectorDB{
+		collectionsSvc: collections,
+		pointsSvc:      points,
+		collection:     "the_hive",
+		indexFields:    DefaultPayloadIndexFields,
+	}
+
+	indexed, err := q.EnsurePayloadIndexes(context.Background())
+	if err != nil {
+		t.Fatalf("EnsurePayloadIndexes failed: %v", err)
+	}
+	if len(collections.created) != 0 {
+		t.Errorf("Expected no collection to be created, got %v", collections.created)
+	}
+	if !reflect.DeepEqual(indexed, DefaultPayloadIndexFields) || !reflect.DeepEqual(points.indexed, DefaultPayloadIndexFields) {
+		t.Errorf("Expected default fields to be indexed, got %v", points.indexed)
+	}
+}
+
+func TestPayloadIndexFieldsFromEnv(t *testing.T) {
+	t.Setenv("QDRANT_PAYLOAD_INDEX_FIELDS", "client_id, tags_list,,")
+
+	fields := payloadIndexFieldsFromEnv()
+	want := []string{"organization_id", "client_id", "tags_list"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("payloadIndexFieldsFromEnv() = %v, want %v", fields, want)
+	}
+}
+
+func TestSearch_StrictOrganizationMode(t *testing.T) {
+	points := &fakePoints{}
+	q := &QdrantVectorDB{pointsSvc: points, collection: "the_hive", requireOrg: true}
+
+	_, err := q.Search(context.Background(), []float32{0.1, 0.2}, 5, "")
+	if !errors.Is(err, ErrOrganizationRequired) {
+		t.Fatalf("Expected ErrOrganizationRequired in strict mode, got %v", err)
+	}
+	if len(points.searches) != 0 {
+		t.Errorf("Strict mode must not reach Qdrant without an organization")
+	}
+
+	if _, err := q.Search(context.Background(), []float32{0.1, 0.2}, 5, "org-a"); err != nil {
+		t.Fatalf("Search with organization failed: %v", err)
+	}
+	if len(points.searches) != 1 || points.searches[0].Filter == nil {
+		t.Errorf("Expected an organization-filtered search, got %v", points.searches)
+	}
+}
+
+func TestSearch_PermissiveModeAllowsEmptyOrganization(t *testing.T) {
+	t.Setenv("SEARCH_ORG_MODE", "permissive")
+	points := &fakePoints{}
+	q := &QdrantVectorDB{pointsSvc: points, collection: "the_hive", requireOrg: strictOrgModeFromEnv()}
+
+	if _, err := q.Search(context.Background(), []float32{0.1, 0.2}, 5, ""); err != nil {
+		t.Fatalf("Expected permissive mode to search all organizations, got %v", err)
+	}
+	if len(points.searches) != 1 || points.searches[0].Filter != nil {
+		t.Errorf("Expected an unfiltered search, got %v", points.searches)
+	}
+
+	t.Setenv("SEARCH_ORG_MODE", "strict")
+	if !strictOrgModeFromEnv() {
+		t.Errorf("Expected SEARCH_ORG_MODE=strict to enable strict mode")
+	}
+}
+
+func TestSearchFiltered_AddsPayloadConditions(t *testing.T) {
+	points := &fakePoints{}
+	q := &QdrantVectorDB{pointsSvc: points, collection: "the_hive"}
+
+	if _, err := q.SearchFiltered(context.Background(), []float32{0.1, 0.2}, 5, "org-a", map[string]string{"language": "de"}); err != nil {
+		t.Fatalf("SearchFiltered failed: %v", err)
+	}
+	if len(points.searches) != 1 || points.searches[0].Filter == nil {
+		t.Fatalf("Expected a filtered search, got %v", points.searches)
+	}
+	var keys []string
+	for _, cond := range points.searches[0].Filter.Must {
+		field := cond.GetField()
+		keys = append(keys, field.Key+"="+field.Match.GetKeyword())
+	}
+	if want := []string{"organization_id=org-a", "language=de"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Filter conditions = %v, want %v", keys, want)
+	}
+}
+
+func TestDeleteByDocument_FiltersAndDeletesScrolledPoints(t *testing.T) {
+	points := &fakePoints{scrolled: []*qdrant.RetrievedPoint{
+		{Id: &qdrant.PointId{PointIdOptions: &qdrant.PointId_Uuid{Uuid: "chunk-0"}}},
+		{Id: &qdrant.PointId{PointIdOptions: &qdrant.PointId_Uuid{Uuid: "chunk-1"}}},
+	}}
+	q := &QdrantVectorDB{pointsSvc: points, collection: "the_hive"}
+
+	deleted, err := q.DeleteByDocument(context.Background(), "doc-1", "org-a")
+	if err != nil {
+		t.Fatalf("DeleteByDocument failed: %v", err)
+	}
+	if deleted != 2 || !reflect.DeepEqual(points.deleted, []string{"chunk-0", "chunk-1"}) {
+		t.Errorf("Expected both scrolled points to be deleted, got %d %v", deleted, points.deleted)
+	}
+	var keys []string
+	for _, cond := range points.scrolls[0].Filter.Must {
+		field := cond.GetField()
+		keys = append(keys, field.Key+"="+field.Match.GetKeyword())
+	}
+	if want := []string{"document_id=doc-1", "organization_id=org-a"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Filter conditions = %v, want %v", keys, want)
+	}
+
+	if _, err := q.DeleteByDocument(context.Background(), "", "org-a"); err == nil {
+		t.Error("Expected an empty document ID to be rejected")
+	}
+}
+
+func TestUpsertBatch_SendsAllPointsInOneCall(t *testing.T) {
+	points := &fakePoints{}
+	q := &QdrantVectorDB{pointsSvc: points, collection: "the_hive", dimension: 3}
+
+	batch := []PointInput{
+		{ID: "chunk-0", Vector: []float32{1, 0, 0}, Metadata: map[string]string{"document_id": "doc-1", "organization_id": "org-a", "chunk_index": "0"}},
+		{ID: "chunk-1", Vector: []float32{0, 1, 0}, Metadata: map[string]string{"document_id": "doc-1", "organization_id": "org-a", "chunk_index": "1"}},
+	}
+	if err := q.UpsertBatch(context.Background(), batch); err != nil {
+		t.Fatalf("UpsertBatch failed: %v", err)
+	}
+	if len(points.upserts) != 1 || len(points.upserts[0].Points) != 2 {
+		t.Fatalf("Expected one upsert call with both points, got %+v", points.upserts)
+	}
+	for i, point := range points.upserts[0].Points {
+		if point.Id.GetUuid() != batch[i].ID || point.Payload["chunk_index"].GetStringValue() != batch[i].Metadata["chunk_index"] {
+			t.Errorf("Expected point %d to be %s with its payload, got %v", i, batch[i].ID, point)
+		}
+	}
+
+	// The single-point Upsert goes through the same call
+	if err := q.Upsert(context.Background(), "chunk-2", []float32{0, 0, 1}, map[string]string{"organization_id": "org-a"}); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	if len(points.upserts) != 2 || points.upserts[1].Points[0].Id.GetUuid() != "chunk-2" {
+		t.Errorf("Expected Upsert to send one point, got %+v", points.upserts)
+	}
+
+	mixed := []PointInput{batch[0], {ID: "chunk-3", Vector: []float32{1, 0}}}
+	if err := q.UpsertBatch(context.Background(), mixed); err == nil {
+		t.Error("Expected vectors of different dimensions to be rejected")
+	}
+	if len(points.upserts) != 2 {
+		t.Errorf("Expected nothing sent for a rejected batch, got %d calls", len(points.upserts))
+	}
+}
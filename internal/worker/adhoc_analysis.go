@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/the-hive/internal/rules"
+)
+
+// RuleFinding is an active rule that matched ad-hoc content
+type RuleFinding struct {
+	RuleID      int64  `json:"rule_id"`
+	RuleQuery   string `json:"rule_query"`
+	MatchType   string `json:"match_type"` // keyword or single_doc
+	Explanation string `json:"explanation,omitempty"`
+	Evidence    string `json:"evidence,omitempty"`
+}
+
+// AdHocAnalysis is the result of checking content against an organization's active rules
+type AdHocAnalysis struct {
+	RulesChecked int           `json:"rules_checked"`
+	Matches      []RuleFinding `json:"matches"`
+	SkippedRules []int64       `json:"skipped_rules,omitempty"` // Cross-document rules, which compare against stored documents
+	FailedRules  []int64       `json:"failed_rules,omitempty"`  // Rules the AI couldn't answer
+}
+
+// AnalyzeContent checks content against the organization's active rules the way an ingested
+// document would be, but transiently: nothing is stored, no matches or events are recorded, no
+// notifications are sent and answers aren't cached.
+func (p *AnalystPool) AnalyzeContent(ctx context.Context, organizationID, content string) (*AdHocAnalysis, error) {
+	p.seedKeywordRules(organizationID)
+
+	activeRules, err := p.ruleStore.GetActiveRules(organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active rules: %w", err)
+	}
+
+	result := &AdHocAnalysis{Matches: []RuleFinding{}}
+	for _, rule := range activeRules {
+		if !rule.Active {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		finding, err := p.CheckRule(ctx, rule, content)
+		switch {
+		case errors.Is(err, ErrCrossDocumentRule):
+			result.SkippedRules = append(result.SkippedRules, rule.ID)
+			continue
+		case err != nil:
+			log.Printf("[ANALYST] Ad-hoc analysis of rule %d failed: %v", rule.ID, err)
+			result.FailedRules = append(result.FailedRules, rule.ID)
+			continue
+		}
+		result.RulesChecked++
+		if finding != nil {
+			result.Matches = append(result.Matches, *finding)
+		}
+	}
+	return result, nil
+}
+
+// ErrCrossDocumentRule is returned by CheckRule for rules that compare against other stored documents
+var ErrCrossDocumentRule = errors.New("cross-document rules can't be checked against a single text")
+
+// CheckRule checks one rule against content without storing, recording or notifying anything.
+// It returns the finding if the rule matches and nil if it doesn't.
+func (p *AnalystPool) CheckRule(ctx context.Context, rule rules.Rule, content string) (*RuleFinding, error) {
+	switch {
+	case rule.Kind == rules.KindKeyword:
+		evidence, found := keywordEvidence(content, rule.Query)
+		if !found {
+			return nil, nil
+		}
+		return &RuleFinding{
+			RuleID:      rule.ID,
+			RuleQuery:   rule.Query,
+			MatchType:   "keyword",
+			Explanation: fmt.Sprintf("Content contains the keyword %q", rule.Query),
+			Evidence:    evidence,
+		}, nil
+	case p.requiresCrossDocument(rule):
+		return nil, ErrCrossDocumentRule
+	}
+
+	answer, explanation, evidence, err := p.analyzeDocument(rule.Query, content)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ToUpper(strings.TrimSpace(answer)) != "YES" {
+		return nil, nil
+	}
+	return &RuleFinding{
+		RuleID:      rule.ID,
+		RuleQuery:   rule.Query,
+		MatchType:   "single_doc",
+		Explanation: explanation,
+		Evidence:    truncateString(evidence, 2000),
+	}, nil
+}
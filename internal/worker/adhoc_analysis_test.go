@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package worker
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/rules"
+)
+
+func TestAnalyzeContent_ReturnsMatchesWithoutRecordingThem(t *testing.T) {
+	db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "hive.db"), database.DefaultSQLiteOptions())
+	if err != nil {
+		t.Fatalf("OpenSQLite failed: %v", err)
+	}
+	defer db.Close()
+	store, err := rules.NewStore(db)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	ctx := context.Background()
+	aiRule, err := store.AddRule(ctx, "Does the contract allow termination without notice?", true, "", "org-a")
+	if err != nil {
+		t.Fatalf("AddRule failed: %v", err)
+	}
+	if _, err := store.AddRule(ctx, "Does this document contradict existing policies?", true, "", "org-a"); err != nil {
+		t.Fatalf("AddRule failed: %v", err)
+	}
+
+	sender := &recordingSender{}
+	events := &recordingEvents{}
+	pool := NewAnalystPool(store, sender, nil, nil, nil, events, events, 1)
+	pool.askQuestion = func(ctx context.Context, prompt string) (string, error) {
+		return "YES\nThe vendor may terminate at any time.", nil
+	}
+
+	result, err := pool.AnalyzeContent(ctx, "org-a", "CONFIDENTIAL: the vendor may terminate at any time without notice.")
+	if err != nil {
+		t.Fatalf("AnalyzeContent failed: %v", err)
+	}
+
+	matched := map[int64]string{}
+	for _, match := range result.Matches {
+		matched[match.RuleID] = match.MatchType
+	}
+	if matched[aiRule.ID] != "single_doc" {
+		t.Errorf("Expected the AI rule to match, got %+v", result.Matches)
+	}
+	keywordMatches := 0
+	for _, matchType := range matched {
+		if matchType == "keyword" {
+			keywordMatches++
+		}
+	}
+	if keywordMatches != 1 {
+		t.Errorf("Expected the seeded CONFIDENTIAL keyword rule to match, got %+v", result.Matches)
+	}
+	if len(result.SkippedRules) != 1 {
+		t.Errorf("Expected the cross-document rule to be skipped, got %v", result.SkippedRules)
+	}
+
+	if len(events.events) != 0 {
+		t.Errorf("Expected no matches or events to be recorded, got %v", events.events)
+	}
+	if len(sender.notifications) != 0 {
+		t.Errorf("Expected no notifications, got %v", sender.notifications)
+	}
+	if len(pool.analysisCache.entries) != 0 {
+		t.Errorf("Expected ad-hoc content not to be cached, got %d entries", len(pool.analysisCache.entries))
+	}
+}
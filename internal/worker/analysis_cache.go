@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package worker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAnalysisCacheTTL is how long a rule's answer for identical content is reused
+	DefaultAnalysisCacheTTL = 24 * time.Hour
+	// defaultAnalysisCacheEntries bounds the cache's memory use
+	defaultAnalysisCacheEntries = 10000
+)
+
+// analysisCacheKey identifies a rule's analysis of one exact piece of content
+type analysisCacheKey struct {
+	contentHash string
+	ruleID      int64
+}
+
+// analysisResult is a cached answer; query is the rule question it was asked with, so an edited
+// rule doesn't reuse answers to its old question
+type analysisResult struct {
+	query       string
+	answer      string
+	explanation string
+	evidence    string
+	expiresAt   time.Time
+}
+
+// analysisCache remembers analyst answers for (content hash, rule) so re-ingesting identical
+// content (drone retries, duplicate files) doesn't repeat the AI call
+type analysisCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[analysisCacheKey]analysisResult
+	now        func() time.Time // replaceable in tests
+}
+
+// newAnalysisCache creates a cache holding answers for ttl
+func newAnalysisCache(ttl time.Duration, maxEntries int) *analysisCache {
+	return &analysisCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[analysisCacheKey]analysisResult),
+		now:        time.Now,
+	}
+}
+
+// contentHash returns the cache's hash of document content
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// get returns the cached answer for the rule and content, if it is fresh and was asked with the same query
+func (c *analysisCache) get(hash string, ruleID int64, query string) (analysisResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := analysisCacheKey{contentHash: hash, ruleID: ruleID}
+	result, ok := c.entries[key]
+	if !ok {
+		return analysisResult{}, false
+	}
+	if result.query != query || !c.now().Before(result.expiresAt) {
+		delete(c.entries, key)
+		return analysisResult{}, false
+	}
+	return result, true
+}
+
+// put stores an answer, making room by dropping expired entries (or everything, if still full)
+func (c *analysisCache) put(hash string, ruleID int64, result analysisResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.entries) >= c.maxEntries {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, key)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			c.entries = make(map[analysisCacheKey]analysisResult)
+		}
+	}
+	result.expiresAt = now.Add(c.ttl)
+	c.entries[analysisCacheKey{contentHash: hash, ruleID: ruleID}] = result
+}
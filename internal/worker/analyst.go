@@ -3,13 +3,20 @@
 package worker
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/the-hive/internal/ai"
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/httpclient"
+	"github.com/the-hive/internal/processor"
 	"github.com/the-hive/internal/rules"
 	"github.com/the-hive/internal/vectordb"
 )
@@ -22,6 +29,32 @@ type AnalystJob struct {
 	ClientID      string
 	AllChunks     []string // Full document chunks for comprehensive analysis
 	OrganizationID string  // Organization ID for multi-tenancy isolation
+	DocumentID    string   // ID the document's chunks are stored under (docid.New), to tell it apart from related documents
+	KeywordRulesOnly bool  // Only check keyword rules (no AI calls), for documents sampled out of AI analysis
+}
+
+// documentID returns the ID the job's document is stored under, or its filename for jobs without one
+func (job AnalystJob) documentID() string {
+	if job.DocumentID != "" {
+		return job.DocumentID
+	}
+	if filename := job.Metadata["filename"]; filename != "" {
+		return filename
+	}
+	return job.FilePath
+}
+
+// isSameDocument reports whether a search match is a chunk of the job's own document
+func (job AnalystJob) isSameDocument(match vectordb.Match) bool {
+	return match.DocumentID == job.documentID()
+}
+
+// matchDocumentName returns the name shown to users for a search match's document
+func matchDocumentName(match vectordb.Match) string {
+	if filename := match.Metadata["filename"]; filename != "" {
+		return filename
+	}
+	return match.DocumentID
 }
 
 // NotificationSender is an interface for sending notifications
@@ -29,6 +62,12 @@ type NotificationSender interface {
 	SendNotification(clientID string, notificationType, message, level string) error
 }
 
+// OrgNotificationSender is a NotificationSender that routes each notification by the
+// organization's per-severity channel configuration, including channels that don't need a client
+type OrgNotificationSender interface {
+	SendOrgNotification(organizationID, clientID, notificationType, message, level string) error
+}
+
 // GraphStore interface for storing document relationships
 type GraphStore interface {
 	AddEdge(ctx context.Context, sourceDocID, targetDocID, relationshipType, description string) error
@@ -44,6 +83,32 @@ type RuleEventStore interface {
 	AddEvent(ctx context.Context, event interface{}) error
 }
 
+// AuditLogger interface for recording audit log entries
+type AuditLogger interface {
+	LogAction(clientIP string, action database.AuditAction, details string, organizationID string) error
+}
+
+// ContradictionAlertConfig controls what happens when a contradiction edge is created
+type ContradictionAlertConfig struct {
+	Enabled    bool   // Feature flag: notify, record a rule-event and an audit entry for each contradiction
+	WebhookURL string // Optional URL that receives a JSON POST for each contradiction
+}
+
+const (
+	// DefaultMaxAnalysisChars keeps a single analysis prompt well inside the model's context window
+	DefaultMaxAnalysisChars = 24000
+	// DefaultMaxAnalysisSegments bounds the AI calls made per rule for an oversized document
+	DefaultMaxAnalysisSegments = 8
+	// analysisSegmentOverlap is shared between neighbouring segments so a clause isn't cut in half
+	analysisSegmentOverlap = 500
+	// DefaultCrossDocTargets is how many related documents a cross-document rule is compared against
+	DefaultCrossDocTargets = 10
+	// DefaultAnalystEnqueueTimeout is how long Enqueue waits for room in a full analyst queue before dropping the job
+	DefaultAnalystEnqueueTimeout = 5 * time.Second
+	// analystQueueSize is how many jobs wait for an analyst worker
+	analystQueueSize = 100
+)
+
 // AnalystPool manages a pool of analyst workers
 type AnalystPool struct {
 	jobQueue         chan AnalystJob
@@ -56,6 +121,22 @@ type AnalystPool struct {
 	}
 	matchStore       RuleMatchStore // Store for rule match history
 	eventStore       RuleEventStore // Store for rule processing events
+	auditLogger      AuditLogger    // Optional audit log for analyst findings
+	contradictionAlerts ContradictionAlertConfig
+	askQuestion      func(ctx context.Context, prompt string) (string, error) // AI call (replaceable in tests)
+	maxAnalysisChars    int // Documents longer than this are analyzed in segments
+	maxAnalysisSegments int // Upper bound on AI calls per rule for a segmented document
+	crossDocTargets     int     // Most documents a cross-document rule is compared against
+	crossDocMinScore    float32 // Matches less similar than this aren't compared (0 compares all)
+	analysisCache       *analysisCache // Answers for identical content and rule; nil disables caching
+	sensitiveKeywords []string        // Seeded as keyword rules for each organization when configured
+	keywordsConfigured bool           // Otherwise the default rule template's keyword rules are seeded
+	failures          *JobFailureRecorder // Records dropped and failed jobs; nil only logs them
+	seedMu            sync.Mutex
+	seededOrgs        map[string]bool // Organizations whose keyword rules have been seeded
+	enqueueTimeout    time.Duration   // How long Enqueue waits for room in a full queue; 0 drops at once
+	stopMu            sync.RWMutex    // Held by senders so Stop doesn't close the queue under them
+	stopped           bool
 	workerCount      int
 	ctx              context.Context
 	cancel           context.CancelFunc
@@ -67,7 +148,7 @@ func NewAnalystPool(ruleStore *rules.Store, notificationSender NotificationSende
 }, matchStore RuleMatchStore, eventStore RuleEventStore, workerCount int) *AnalystPool {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &AnalystPool{
-		jobQueue:          make(chan AnalystJob, 100), // Buffered channel
+		jobQueue:          make(chan AnalystJob, analystQueueSize), // Buffered channel
 		ruleStore:         ruleStore,
 		notificationSender: notificationSender,
 		graphStore:        graphStore,
@@ -75,12 +156,121 @@ func NewAnalystPool(ruleStore *rules.Store, notificationSender NotificationSende
 		embedder:          embedder,
 		matchStore:        matchStore,
 		eventStore:        eventStore,
+		askQuestion:       askOpenAI,
+		maxAnalysisChars:    DefaultMaxAnalysisChars,
+		maxAnalysisSegments: DefaultMaxAnalysisSegments,
+		crossDocTargets:     DefaultCrossDocTargets,
+		analysisCache:       newAnalysisCache(DefaultAnalysisCacheTTL, defaultAnalysisCacheEntries),
+		seededOrgs:        make(map[string]bool),
+		enqueueTimeout:    DefaultAnalystEnqueueTimeout,
 		workerCount:       workerCount,
 		ctx:               ctx,
 		cancel:            cancel,
 	}
 }
 
+// askOpenAI is the default AI call used by the analyst
+func askOpenAI(ctx context.Context, prompt string) (string, error) {
+	answer, _, err := ai.AskQuestion(ctx, prompt)
+	return answer, err
+}
+
+// SetEnqueueTimeout sets how long Enqueue waits for room in a full queue before dropping a job (0 or less never waits)
+func (p *AnalystPool) SetEnqueueTimeout(timeout time.Duration) {
+	if timeout < 0 {
+		timeout = 0
+	}
+	p.enqueueTimeout = timeout
+}
+
+// SetAuditLogger sets the audit log used to record analyst findings
+func (p *AnalystPool) SetAuditLogger(auditLogger AuditLogger) {
+	p.auditLogger = auditLogger
+}
+
+// SetFailureRecorder records jobs that are dropped or fail
+func (p *AnalystPool) SetFailureRecorder(recorder *JobFailureRecorder) {
+	p.failures = recorder
+}
+
+// SetContradictionAlerts configures notifications for detected contradictions
+func (p *AnalystPool) SetContradictionAlerts(config ContradictionAlertConfig) {
+	p.contradictionAlerts = config
+}
+
+// SetAnalysisLimits sets the single-prompt size and the segment cap for oversized documents
+func (p *AnalystPool) SetAnalysisLimits(maxChars, maxSegments int) {
+	if maxChars > analysisSegmentOverlap {
+		p.maxAnalysisChars = maxChars
+	}
+	if maxSegments > 0 {
+		p.maxAnalysisSegments = maxSegments
+	}
+}
+
+// SetCrossDocScope limits cross-document rules to at most maxTargets related documents whose similarity
+// score is at least minScore, so unrelated documents don't cost an AI call or raise spurious alerts.
+// maxTargets of zero or less keeps the current limit.
+func (p *AnalystPool) SetCrossDocScope(maxTargets int, minScore float32) {
+	if maxTargets > 0 {
+		p.crossDocTargets = maxTargets
+	}
+	p.crossDocMinScore = minScore
+}
+
+// SetAnalysisCacheTTL sets how long rule answers for identical content are reused; 0 disables the cache
+func (p *AnalystPool) SetAnalysisCacheTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		p.analysisCache = nil
+		return
+	}
+	p.analysisCache = newAnalysisCache(ttl, defaultAnalysisCacheEntries)
+}
+
+// SetSensitiveKeywords sets the keywords seeded as keyword rules for each organization, instead of
+// the default rule template's keyword rules; an empty list disables seeding
+func (p *AnalystPool) SetSensitiveKeywords(keywords []string) {
+	p.seedMu.Lock()
+	defer p.seedMu.Unlock()
+	p.sensitiveKeywords = keywords
+	p.keywordsConfigured = true
+	p.seededOrgs = make(map[string]bool)
+}
+
+// seedKeywordRules makes sure the organization has the sensitive keyword rules (once per process), so
+// organizations created before the default rule template still alert on them
+func (p *AnalystPool) seedKeywordRules(organizationID string) {
+	p.seedMu.Lock()
+	defer p.seedMu.Unlock()
+	if p.seededOrgs[organizationID] {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	keywords := p.sensitiveKeywords
+	if !p.keywordsConfigured {
+		var err error
+		if keywords, err = p.ruleStore.DefaultKeywords(ctx); err != nil {
+			log.Printf("[ERROR] Failed to load default keyword rules: %v", err)
+			return
+		}
+	}
+	if len(keywords) == 0 {
+		p.seededOrgs[organizationID] = true
+		return
+	}
+	added, err := p.ruleStore.EnsureKeywordRules(ctx, organizationID, keywords)
+	if err != nil {
+		log.Printf("[ERROR] Failed to seed keyword rules for org %q: %v", organizationID, err)
+		return
+	}
+	if added > 0 {
+		log.Printf("[ANALYST] Seeded %d keyword rule(s) for org %q", added, organizationID)
+	}
+	p.seededOrgs[organizationID] = true
+}
+
 // Start starts the analyst worker pool
 func (p *AnalystPool) Start() {
 	for i := 0; i < p.workerCount; i++ {
@@ -91,21 +281,77 @@ func (p *AnalystPool) Start() {
 
 // Stop stops the analyst worker pool
 func (p *AnalystPool) Stop() {
+	// Cancelling first releases senders waiting for room, so the lock below isn't held up by them
 	p.cancel()
+	p.stopMu.Lock()
+	p.stopped = true
 	close(p.jobQueue)
+	p.stopMu.Unlock()
 	log.Printf("Stopped analyst worker pool")
 }
 
-// Enqueue adds a job to the queue (non-blocking)
+// Enqueue adds a job to the queue, waiting up to the enqueue timeout for room when it is full.
+// A job that still doesn't fit is dropped, logged as an error and recorded as a dropped job.
 func (p *AnalystPool) Enqueue(job AnalystJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.enqueueTimeout)
+	defer cancel()
+	p.EnqueueContext(ctx, job)
+}
+
+// EnqueueContext adds a job to the queue, waiting for room until ctx ends, and reports whether it was
+// queued. A job that doesn't fit is dropped, logged as an error and recorded as a dropped job.
+func (p *AnalystPool) EnqueueContext(ctx context.Context, job AnalystJob) bool {
 	log.Printf("[DEBUG] AnalystPool.Enqueue called for file: %s", job.FilePath)
+	p.stopMu.RLock()
+	defer p.stopMu.RUnlock()
+	if p.stopped {
+		p.recordDropped(job, "analyst pool stopped")
+		return false
+	}
+
+	start := time.Now()
 	select {
 	case p.jobQueue <- job:
 		log.Printf("[DEBUG] Analyst job successfully added to queue for file: %s", job.FilePath)
-		// Job enqueued successfully
+		return true
 	default:
-		log.Printf("[WARN] Analyst job queue full, dropping job for %s", job.FilePath)
 	}
+	select {
+	case p.jobQueue <- job:
+		log.Printf("[WARN] Analyst queue full, waited %s to queue file: %s", time.Since(start).Round(time.Millisecond), job.FilePath)
+		return true
+	case <-ctx.Done():
+		p.recordDropped(job, fmt.Sprintf("analyst job queue full for %s", time.Since(start).Round(time.Millisecond)))
+	case <-p.ctx.Done():
+		p.recordDropped(job, "analyst pool stopped")
+	}
+	return false
+}
+
+// TryEnqueue adds a job to the queue only if there is room right now, for callers that want
+// best-effort analysis; a job that doesn't fit is dropped and recorded like Enqueue's
+func (p *AnalystPool) TryEnqueue(job AnalystJob) bool {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return p.EnqueueContext(ctx, job)
+}
+
+// QueueDepth returns how many jobs are waiting for an analyst worker, and how many fit in the queue
+func (p *AnalystPool) QueueDepth() (depth, capacity int) {
+	return len(p.jobQueue), cap(p.jobQueue)
+}
+
+// recordDropped logs and records an analyst job that couldn't be queued
+func (p *AnalystPool) recordDropped(job AnalystJob, reason string) {
+	log.Printf("[ERROR] Dropped analysis of %s (org %s): %s", job.FilePath, job.OrganizationID, reason)
+	p.failures.Record(JobFailure{
+		Pool:           PoolAnalyst,
+		Outcome:        JobDropped,
+		JobType:        "analyze_document",
+		Subject:        job.FilePath,
+		OrganizationID: job.OrganizationID,
+		Error:          reason,
+	})
 }
 
 // worker processes jobs from the queue
@@ -134,6 +380,8 @@ func (p *AnalystPool) worker(id int) {
 func (p *AnalystPool) processJob(job AnalystJob) {
 	log.Printf("[DEBUG] processJob called for file: %s (content length: %d)", job.FilePath, len(job.Content))
 	
+	p.seedKeywordRules(job.OrganizationID)
+
 	// Get all active rules for this organization (multi-tenancy isolation)
 	var activeRules []rules.Rule
 	var err error
@@ -143,12 +391,30 @@ func (p *AnalystPool) processJob(job AnalystJob) {
 		activeRules, err = p.ruleStore.GetActiveRules()
 	}
 	if err != nil {
-		log.Printf("[ERROR] Failed to get active rules: %v", err)
+		p.failures.Record(JobFailure{
+			Pool:           PoolAnalyst,
+			Outcome:        JobFailed,
+			JobType:        "analyze_document",
+			Subject:        job.FilePath,
+			OrganizationID: job.OrganizationID,
+			Error:          fmt.Sprintf("failed to get active rules: %v", err),
+		})
 		return
 	}
 
 	log.Printf("[DEBUG] Retrieved %d active rules from store", len(activeRules))
 
+	// Keyword rules cost no AI call, so they still run on documents sampled out of AI analysis
+	if job.KeywordRulesOnly {
+		keywordRules := activeRules[:0:0]
+		for _, rule := range activeRules {
+			if rule.Kind == rules.KindKeyword {
+				keywordRules = append(keywordRules, rule)
+			}
+		}
+		activeRules = keywordRules
+	}
+
 	if len(activeRules) == 0 {
 		log.Printf("[ANALYST] No active rules to check for file %s", job.FilePath)
 		return // No rules to check
@@ -188,7 +454,7 @@ func (p *AnalystPool) processJob(job AnalystJob) {
 	}
 
 	// Check for contradictions with existing documents
-	if p.graphStore != nil && p.vectorDB != nil && p.embedder != nil {
+	if !job.KeywordRulesOnly && p.graphStore != nil && p.vectorDB != nil && p.embedder != nil {
 		p.checkContradictions(job, fullContent)
 	}
 
@@ -223,9 +489,11 @@ func (p *AnalystPool) processJob(job AnalystJob) {
 		}
 
 		// Determine if rule requires cross-document comparison
-		requiresCrossDoc := p.requiresCrossDocumentCheck(rule.Query)
+		requiresCrossDoc := p.requiresCrossDocument(rule)
 
-		if requiresCrossDoc {
+		if rule.Kind == rules.KindKeyword {
+			p.checkKeywordRule(rule, fullContent, job, filename)
+		} else if requiresCrossDoc {
 			// Check rule against all existing documents
 			p.checkRuleCrossDocument(rule, fullContent, job, filename)
 		} else {
@@ -293,10 +561,11 @@ func (p *AnalystPool) fallbackAnswer(question, content string) string {
 
 // checkContradictions checks if the new document contradicts existing documents
 func (p *AnalystPool) checkContradictions(job AnalystJob, snippet string) {
-	// Get document ID from metadata
-	sourceDocID := job.Metadata["filename"]
-	if sourceDocID == "" {
-		sourceDocID = job.FilePath
+	// Graph edges link document IDs; notifications name the documents
+	sourceDocID := job.documentID()
+	sourceName := job.Metadata["filename"]
+	if sourceName == "" {
+		sourceName = job.FilePath
 	}
 
 	// Generate embedding for the new document
@@ -324,10 +593,11 @@ func (p *AnalystPool) checkContradictions(job AnalystJob, snippet string) {
 
 	// Check each similar document for contradictions
 	for _, match := range matches {
-		targetDocID := match.DocumentID
-		if targetDocID == sourceDocID {
+		if job.isSameDocument(match) {
 			continue // Skip self
 		}
+		targetDocID := match.DocumentID
+		targetName := matchDocumentName(match)
 
 		// Get content from metadata
 		targetContent := match.Metadata["content"]
@@ -351,7 +621,7 @@ Document B:
 
 Answer with ONLY "YES" if they contradict, or "NO" if they do not contradict. If they contradict, provide a brief explanation in one sentence after "YES".`, snippet, targetContent)
 
-		answer, _, err := ai.AskQuestion(ctx, contradictionPrompt)
+		answer, err := p.askQuestion(ctx, contradictionPrompt)
 		if err != nil {
 			log.Printf("Failed to check contradiction: %v", err)
 			continue
@@ -370,14 +640,106 @@ Answer with ONLY "YES" if they contradict, or "NO" if they do not contradict. If
 			if err := p.graphStore.AddEdge(ctx, sourceDocID, targetDocID, "contradicts", description); err != nil {
 				log.Printf("Failed to store contradiction edge: %v", err)
 			} else {
-				log.Printf("Detected contradiction: %s contradicts %s", sourceDocID, targetDocID)
+				log.Printf("Detected contradiction: %s (%s) contradicts %s (%s)", sourceName, sourceDocID, targetName, targetDocID)
+				if p.contradictionAlerts.Enabled {
+					p.notifyContradiction(ctx, job, sourceName, targetName, targetDocID, description)
+				}
 			}
 		}
 	}
 }
 
+// notifyContradiction sends a notification, rule-event, audit entry and optional webhook for a contradiction.
+// Users see the documents' names; the webhook also gets their IDs.
+func (p *AnalystPool) notifyContradiction(ctx context.Context, job AnalystJob, sourceName, targetName, targetDocID, explanation string) {
+	message := fmt.Sprintf("⚠️ Contradiction: %s contradicts %s - %s", sourceName, targetName, explanation)
+
+	if _, err := p.notify(job, "CONTRADICTION", message, "warning"); err != nil {
+		log.Printf("Failed to send contradiction notification: %v", err)
+	}
+
+	if p.eventStore != nil {
+		err := p.eventStore.AddEvent(ctx, map[string]interface{}{
+			"RuleID":         0,
+			"RuleQuery":      "",
+			"Document":       sourceName,
+			"EventType":      "contradiction",
+			"Status":         "detected",
+			"Message":        message,
+			"ClientID":       job.ClientID,
+			"OrganizationID": job.OrganizationID,
+		})
+		if err != nil {
+			log.Printf("[ERROR] Failed to store contradiction event: %v", err)
+		}
+	}
+
+	if p.auditLogger != nil {
+		details := fmt.Sprintf("Document [%s] contradicts [%s]: %s", sourceName, targetName, explanation)
+		if err := p.auditLogger.LogAction("analyst", database.AuditActionContradiction, details, job.OrganizationID); err != nil {
+			log.Printf("Failed to log contradiction audit entry: %v", err)
+		}
+	}
+
+	if p.contradictionAlerts.WebhookURL != "" {
+		if err := postContradictionWebhook(ctx, p.contradictionAlerts.WebhookURL, map[string]string{
+			"event":           "contradiction_detected",
+			"source_document":    sourceName,
+			"target_document":    targetName,
+			"source_document_id": job.documentID(),
+			"target_document_id": targetDocID,
+			"explanation":     explanation,
+			"organization_id": job.OrganizationID,
+			"client_id":       job.ClientID,
+		}); err != nil {
+			log.Printf("Failed to deliver contradiction webhook: %v", err)
+		}
+	}
+}
+
+// postContradictionWebhook POSTs the contradiction payload as JSON to the configured webhook URL
+func postContradictionWebhook(ctx context.Context, webhookURL string, payload map[string]string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpclient.New(5 * time.Second).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
 
-// requiresCrossDocumentCheck determines if a rule needs cross-document comparison
+// requiresCrossDocument determines if a rule needs cross-document comparison. An explicit scope
+// decides; only auto-scoped rules fall back to the keyword heuristic.
+func (p *AnalystPool) requiresCrossDocument(rule rules.Rule) bool {
+	switch rule.Scope {
+	case rules.ScopeCrossDoc:
+		return true
+	case rules.ScopeSingleDoc:
+		return false
+	default:
+		return p.requiresCrossDocumentCheck(rule.Query)
+	}
+}
+
+// requiresCrossDocumentCheck determines if a rule's query reads like a cross-document comparison
 func (p *AnalystPool) requiresCrossDocumentCheck(query string) bool {
 	queryLower := strings.ToLower(query)
 	crossDocKeywords := []string{
@@ -404,8 +766,8 @@ func (p *AnalystPool) checkRuleSingleDocument(rule rules.Rule, content string, j
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	// Ask AI the question with full document content
-	answer, explanation, err := p.askAIWithExplanation(rule.Query, content, false, "")
+	// Ask AI the question with the document content (in segments if it is too large for one prompt)
+	answer, explanation, evidence, err := p.analyzeDocumentCached(rule, content)
 	if err != nil {
 		log.Printf("[ERROR] Job failed: Failed to ask AI for rule %d: %v", rule.ID, err)
 		return
@@ -416,7 +778,11 @@ func (p *AnalystPool) checkRuleSingleDocument(rule rules.Rule, content string, j
 		message := fmt.Sprintf("⚠️ Rule Hit: '%s' detected in %s", rule.Query, filename)
 
 		// Extract relevant chunks (first 3 chunks or all if less than 3)
+		// For segmented analysis, the matching segment is the evidence
 		matchedChunks := p.extractRelevantChunks(content, job.AllChunks)
+		if evidence != "" {
+			matchedChunks = []string{truncateString(evidence, 2000)}
+		}
 
 		// Store match in database
 		if p.matchStore != nil {
@@ -437,16 +803,88 @@ func (p *AnalystPool) checkRuleSingleDocument(rule rules.Rule, content string, j
 		}
 
 		// Send notification
-		if job.ClientID != "" && p.notificationSender != nil {
-			if err := p.notificationSender.SendNotification(job.ClientID, "ALERT", message, "warning"); err != nil {
-				log.Printf("Failed to send notification for rule %d: %v", rule.ID, err)
-			} else {
-				log.Printf("[ANALYST] Rule %d triggered for %s, notification sent to client %s", rule.ID, filename, job.ClientID)
-			}
+		if sent, err := p.notify(job, "ALERT", message, "warning"); err != nil {
+			log.Printf("Failed to send notification for rule %d: %v", rule.ID, err)
+		} else if sent {
+			log.Printf("[ANALYST] Rule %d triggered for %s, notification sent to client %s", rule.ID, filename, job.ClientID)
 		}
 	}
 }
 
+// checkKeywordRule matches a keyword rule against the document without an AI call
+// A document raises at most one alert per keyword rule, however many times the keyword appears
+func (p *AnalystPool) checkKeywordRule(rule rules.Rule, content string, job AnalystJob, filename string) {
+	evidence, found := keywordEvidence(content, rule.Query)
+	if !found {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if p.matchStore != nil {
+		match := map[string]interface{}{
+			"RuleID":         rule.ID,
+			"RuleQuery":      rule.Query,
+			"UploadedDoc":    filename,
+			"MatchedDoc":     "",
+			"MatchType":      "keyword",
+			"AIExplanation":  fmt.Sprintf("Document contains the keyword %q", rule.Query),
+			"MatchedChunks":  []string{evidence},
+			"ClientID":       job.ClientID,
+			"OrganizationID": job.OrganizationID,
+		}
+		if err := p.matchStore.AddMatch(ctx, match); err != nil {
+			log.Printf("Failed to store keyword rule match: %v", err)
+		}
+	}
+
+	message := fmt.Sprintf("Sensitive document detected: %s (%s)", filename, rule.Query)
+	if sent, err := p.notify(job, "ALERT", message, "critical"); err != nil {
+		log.Printf("Failed to send notification for keyword rule %d: %v", rule.ID, err)
+	} else if sent {
+		log.Printf("[ANALYST] Keyword rule %d triggered for %s, notification sent to client %s", rule.ID, filename, job.ClientID)
+	}
+}
+
+// notify sends a notification for the job. A routing sender gets every notification for the job's
+// organization; a plain sender only gets those for a connected client. It reports whether anything was sent.
+func (p *AnalystPool) notify(job AnalystJob, notificationType, message, level string) (bool, error) {
+	if p.notificationSender == nil {
+		return false, nil
+	}
+	if router, ok := p.notificationSender.(OrgNotificationSender); ok && job.OrganizationID != "" {
+		return true, router.SendOrgNotification(job.OrganizationID, job.ClientID, notificationType, message, level)
+	}
+	if job.ClientID == "" {
+		return false, nil
+	}
+	return true, p.notificationSender.SendNotification(job.ClientID, notificationType, message, level)
+}
+
+// keywordEvidence finds the keyword in the content (case-insensitively) and quotes the text around it
+func keywordEvidence(content, keyword string) (string, bool) {
+	lowerContent := strings.ToLower(content)
+	idx := strings.Index(lowerContent, strings.ToLower(keyword))
+	if idx < 0 {
+		return "", false
+	}
+
+	source := content
+	if len(lowerContent) != len(content) {
+		source = lowerContent
+	}
+	start := idx - 200
+	if start < 0 {
+		start = 0
+	}
+	end := idx + len(keyword) + 200
+	if end > len(source) {
+		end = len(source)
+	}
+	return strings.ToValidUTF8(source[start:end], ""), true
+}
+
 // checkRuleCrossDocument checks a rule by comparing uploaded document against all existing documents
 func (p *AnalystPool) checkRuleCrossDocument(rule rules.Rule, newDocContent string, job AnalystJob, filename string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
@@ -459,32 +897,47 @@ func (p *AnalystPool) checkRuleCrossDocument(rule rules.Rule, newDocContent stri
 		return
 	}
 
-	// Search for similar/relevant documents (top 10 for comprehensive comparison)
+	// Search for the most similar documents (up to crossDocTargets)
 	// Use organization_id from job metadata for multi-tenancy isolation
 	orgID := job.OrganizationID
 	if orgID == "" {
 		// Fallback: try to get from metadata
 		orgID = job.Metadata["organization_id"]
 	}
-	matches, err := p.vectorDB.Search(ctx, queryVector, 10, orgID)
+	matches, err := p.vectorDB.Search(ctx, queryVector, p.crossDocTargets, orgID)
 	if err != nil {
 		log.Printf("Failed to search for documents for cross-doc rule check: %v", err)
 		return
 	}
 
-	if len(matches) == 0 {
-		log.Printf("[ANALYST] No existing documents found for cross-document rule check")
+	// Only documents related closely enough are worth an AI comparison
+	targets := make([]vectordb.Match, 0, len(matches))
+	skipped := 0
+	for _, match := range matches {
+		if match.Score < p.crossDocMinScore {
+			skipped++
+			continue
+		}
+		targets = append(targets, match)
+	}
+
+	if len(targets) == 0 {
+		log.Printf("[ANALYST] No related documents found for cross-document rule check (%d below similarity %.2f)", skipped, p.crossDocMinScore)
 		// Still check the rule against the uploaded document only
 		p.checkRuleSingleDocument(rule, newDocContent, job, filename)
 		return
 	}
 
-	// Check rule against each existing document
-	for _, match := range matches {
-		targetDocID := match.DocumentID
-		if targetDocID == filename {
+	// Check rule against each related document
+	compared := 0
+	defer func() {
+		p.recordCrossDocScope(rule, job, filename, compared, skipped)
+	}()
+	for _, match := range targets {
+		if job.isSameDocument(match) {
 			continue // Skip self
 		}
+		targetName := matchDocumentName(match)
 
 		// Get full content from metadata
 		targetContent := match.Metadata["content"]
@@ -493,6 +946,7 @@ func (p *AnalystPool) checkRuleCrossDocument(rule rules.Rule, newDocContent stri
 		}
 
 		// Ask AI if the rule applies when comparing both documents
+		compared++
 		answer, explanation, err := p.askAIWithExplanation(rule.Query, newDocContent, true, targetContent)
 		if err != nil {
 			log.Printf("Failed to check cross-doc rule: %v", err)
@@ -501,12 +955,12 @@ func (p *AnalystPool) checkRuleCrossDocument(rule rules.Rule, newDocContent stri
 
 		// If AI answers YES, we have a cross-document match
 		if strings.ToUpper(strings.TrimSpace(answer)) == "YES" {
-			message := fmt.Sprintf("⚠️ Rule Hit: '%s' detected between %s and %s", rule.Query, filename, targetDocID)
+			message := fmt.Sprintf("⚠️ Rule Hit: '%s' detected between %s and %s", rule.Query, filename, targetName)
 
 			// Extract relevant chunks from both documents
 			matchedChunks := []string{
 				fmt.Sprintf("[%s] %s", filename, truncateString(newDocContent, 500)),
-				fmt.Sprintf("[%s] %s", targetDocID, truncateString(targetContent, 500)),
+				fmt.Sprintf("[%s] %s", targetName, truncateString(targetContent, 500)),
 			}
 
 			// Store match in database
@@ -515,7 +969,7 @@ func (p *AnalystPool) checkRuleCrossDocument(rule rules.Rule, newDocContent stri
 					"RuleID":        rule.ID,
 					"RuleQuery":     rule.Query,
 					"UploadedDoc":   filename,
-					"MatchedDoc":    targetDocID,
+					"MatchedDoc":    targetName,
 					"MatchType":     "cross_doc",
 					"AIExplanation": explanation,
 					"MatchedChunks": matchedChunks,
@@ -535,19 +989,17 @@ func (p *AnalystPool) checkRuleCrossDocument(rule rules.Rule, newDocContent stri
 					"Document":      filename,
 					"EventType":     "matched",
 					"Status":        "completed",
-					"Message":       fmt.Sprintf("Cross-document match with %s: %s", targetDocID, explanation),
+					"Message":       fmt.Sprintf("Cross-document match with %s: %s", targetName, explanation),
 					"ClientID":      job.ClientID,
 					"OrganizationID": job.OrganizationID,
 				})
 			}
 
 			// Send notification
-			if job.ClientID != "" && p.notificationSender != nil {
-				if err := p.notificationSender.SendNotification(job.ClientID, "ALERT", message, "critical"); err != nil {
-					log.Printf("Failed to send notification for cross-doc rule %d: %v", rule.ID, err)
-				} else {
-					log.Printf("[ANALYST] Cross-doc rule %d triggered: %s vs %s, notification sent", rule.ID, filename, targetDocID)
-				}
+			if sent, err := p.notify(job, "ALERT", message, "critical"); err != nil {
+				log.Printf("Failed to send notification for cross-doc rule %d: %v", rule.ID, err)
+			} else if sent {
+				log.Printf("[ANALYST] Cross-doc rule %d triggered: %s vs %s, notification sent", rule.ID, filename, targetName)
 			}
 		} else {
 			// Log event: Cross-doc rule did not match
@@ -558,7 +1010,7 @@ func (p *AnalystPool) checkRuleCrossDocument(rule rules.Rule, newDocContent stri
 					"Document":      filename,
 					"EventType":     "not_matched",
 					"Status":        "completed",
-					"Message":       fmt.Sprintf("No match with %s", targetDocID),
+					"Message":       fmt.Sprintf("No match with %s", targetName),
 					"OrganizationID": job.OrganizationID,
 					"ClientID":  job.ClientID,
 				})
@@ -567,6 +1019,92 @@ func (p *AnalystPool) checkRuleCrossDocument(rule rules.Rule, newDocContent stri
 	}
 }
 
+// recordCrossDocScope logs and records how many related documents a cross-document rule was compared
+// against and how many search matches were skipped as too dissimilar
+func (p *AnalystPool) recordCrossDocScope(rule rules.Rule, job AnalystJob, filename string, compared, skipped int) {
+	log.Printf("[ANALYST] Cross-doc rule %d on %s: compared %d documents, skipped %d below similarity %.2f", rule.ID, filename, compared, skipped, p.crossDocMinScore)
+	if p.eventStore == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := p.eventStore.AddEvent(ctx, map[string]interface{}{
+		"RuleID":         rule.ID,
+		"RuleQuery":      rule.Query,
+		"Document":       filename,
+		"EventType":      "compared",
+		"Status":         "completed",
+		"Message":        fmt.Sprintf("Compared with %d related documents, skipped %d below similarity %.2f", compared, skipped, p.crossDocMinScore),
+		"ClientID":       job.ClientID,
+		"OrganizationID": job.OrganizationID,
+	})
+	if err != nil {
+		log.Printf("[ERROR] Failed to store cross-doc comparison counts for rule %d: %v", rule.ID, err)
+	}
+}
+
+// analyzeDocumentCached is analyzeDocument, reusing the answer when this rule already analyzed identical content
+func (p *AnalystPool) analyzeDocumentCached(rule rules.Rule, content string) (answer, explanation, evidence string, err error) {
+	if p.analysisCache == nil {
+		return p.analyzeDocument(rule.Query, content)
+	}
+
+	hash := contentHash(content)
+	if cached, ok := p.analysisCache.get(hash, rule.ID, rule.Query); ok {
+		log.Printf("[ANALYST] Reusing cached answer for rule %d on identical content", rule.ID)
+		return cached.answer, cached.explanation, cached.evidence, nil
+	}
+
+	answer, explanation, evidence, err = p.analyzeDocument(rule.Query, content)
+	if err != nil {
+		return "", "", "", err
+	}
+	p.analysisCache.put(hash, rule.ID, analysisResult{query: rule.Query, answer: answer, explanation: explanation, evidence: evidence})
+	return answer, explanation, evidence, nil
+}
+
+// analyzeDocument asks the rule question about a document, splitting it into segments when it is too
+// large for a single prompt. The answer is YES if any segment matches; evidence is the matching segment
+// (empty when the document fit in one prompt).
+func (p *AnalystPool) analyzeDocument(question, content string) (answer, explanation, evidence string, err error) {
+	if len(content) <= p.maxAnalysisChars {
+		answer, explanation, err = p.askAIWithExplanation(question, content, false, "")
+		return answer, explanation, "", err
+	}
+
+	segments, _ := processor.NewChunkerWithSettings(p.maxAnalysisChars, analysisSegmentOverlap).ChunkText(content)
+	if len(segments) > p.maxAnalysisSegments {
+		log.Printf("[ANALYST] Document too large for full analysis: checking %d of %d segments", p.maxAnalysisSegments, len(segments))
+	}
+
+	var lastErr error
+	checked := 0
+	for i, segment := range segments {
+		if i >= p.maxAnalysisSegments {
+			break
+		}
+
+		segmentAnswer, segmentExplanation, segmentErr := p.askAIWithExplanation(question, segment, false, "")
+		if segmentErr != nil {
+			log.Printf("[ANALYST] Failed to analyze segment %d/%d: %v", i+1, len(segments), segmentErr)
+			lastErr = segmentErr
+			continue
+		}
+		checked++
+
+		if segmentAnswer == "YES" {
+			explanation = fmt.Sprintf("Segment %d of %d: %s", i+1, len(segments), segmentExplanation)
+			return "YES", explanation, segment, nil
+		}
+	}
+
+	if checked == 0 && lastErr != nil {
+		return "", "", "", lastErr
+	}
+	return "NO", "", "", nil
+}
+
 // askAIWithExplanation asks AI a question and returns both answer and explanation
 func (p *AnalystPool) askAIWithExplanation(question, content string, isCrossDoc bool, otherDocContent string) (answer, explanation string, err error) {
 	var prompt string
@@ -599,7 +1137,7 @@ If YES, provide a brief explanation on the second line explaining why.`, content
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	response, _, err := ai.AskQuestion(ctx, prompt)
+	response, err := p.askQuestion(ctx, prompt)
 	if err != nil {
 		return "", "", err
 	}
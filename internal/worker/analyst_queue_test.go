@@ -0,0 +1,95 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package worker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// droppedAnalystJobs returns how many analyst jobs the recorder counted as dropped
+func droppedAnalystJobs(recorder *JobFailureRecorder) int64 {
+	for _, count := range recorder.Counts() {
+		if count.Pool == PoolAnalyst && count.Outcome == JobDropped {
+			return count.Count
+		}
+	}
+	return 0
+}
+
+func TestAnalystPool_EnqueueWaitsForRoomBeforeDropping(t *testing.T) {
+	// Workers aren't started, so the queue only drains when the test reads from it
+	pool := NewAnalystPool(nil, nil, nil, nil, nil, nil, nil, 1)
+	recorder := NewJobFailureRecorder()
+	pool.SetFailureRecorder(recorder)
+
+	for i := 0; i < analystQueueSize; i++ {
+		if !pool.TryEnqueue(AnalystJob{FilePath: fmt.Sprintf("doc-%d.txt", i)}) {
+			t.Fatalf("Expected job %d to fit in the queue", i)
+		}
+	}
+	if depth, capacity := pool.QueueDepth(); depth != analystQueueSize || capacity != analystQueueSize {
+		t.Errorf("Expected a full queue of %d, got depth %d capacity %d", analystQueueSize, depth, capacity)
+	}
+
+	// Best effort drops at once when the queue is full
+	if pool.TryEnqueue(AnalystJob{FilePath: "best-effort.txt"}) {
+		t.Error("Expected TryEnqueue to drop the job when the queue is full")
+	}
+	if n := droppedAnalystJobs(recorder); n != 1 {
+		t.Errorf("Expected 1 dropped job recorded, got %d", n)
+	}
+
+	// Enqueue waits for a worker to make room instead of dropping
+	pool.SetEnqueueTimeout(5 * time.Second)
+	queued := make(chan struct{})
+	go func() {
+		defer close(queued)
+		pool.Enqueue(AnalystJob{FilePath: "waited.txt"})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	<-pool.jobQueue
+	select {
+	case <-queued:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Enqueue to queue the job once there was room")
+	}
+	if n := droppedAnalystJobs(recorder); n != 1 {
+		t.Errorf("Expected the waiting job to be queued, not dropped; %d dropped", n)
+	}
+
+	// Once the timeout passes with no room, the job is dropped and recorded
+	pool.SetEnqueueTimeout(20 * time.Millisecond)
+	start := time.Now()
+	pool.Enqueue(AnalystJob{FilePath: "timed-out.txt"})
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Expected Enqueue to wait for the timeout, returned after %s", elapsed)
+	}
+	if n := droppedAnalystJobs(recorder); n != 2 {
+		t.Errorf("Expected 2 dropped jobs recorded, got %d", n)
+	}
+	if recent := recorder.Recent(); len(recent) == 0 || recent[0].Subject != "timed-out.txt" {
+		t.Errorf("Expected the timed-out job to be the latest failure, got %+v", recent)
+	}
+
+	// Stopping releases a sender still waiting for room
+	result := make(chan bool)
+	go func() {
+		result <- pool.EnqueueContext(context.Background(), AnalystJob{FilePath: "during-stop.txt"})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	pool.Stop()
+	select {
+	case ok := <-result:
+		if ok {
+			t.Error("Expected the job to be dropped when the pool stops")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Stop to release the waiting sender")
+	}
+	if pool.TryEnqueue(AnalystJob{FilePath: "after-stop.txt"}) {
+		t.Error("Expected jobs after Stop to be dropped")
+	}
+}
@@ -0,0 +1,374 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/embeddings"
+	"github.com/the-hive/internal/rules"
+	"github.com/the-hive/internal/vectordb"
+)
+
+// recordingSender records notifications sent by the analyst
+type recordingSender struct {
+	mu            sync.Mutex
+	notifications []string
+	types         []string
+}
+
+func (s *recordingSender) SendNotification(clientID string, notificationType, message, level string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.types = append(s.types, notificationType)
+	s.notifications = append(s.notifications, message)
+	return nil
+}
+
+// recordingGraph records graph edges
+type recordingGraph struct {
+	edges []string
+}
+
+func (g *recordingGraph) AddEdge(ctx context.Context, sourceDocID, targetDocID, relationshipType, description string) error {
+	g.edges = append(g.edges, sourceDocID+"->"+targetDocID)
+	return nil
+}
+
+// recordingEvents records rule events and matches
+type recordingEvents struct {
+	mu     sync.Mutex
+	events []map[string]interface{}
+}
+
+func (e *recordingEvents) AddEvent(ctx context.Context, event interface{}) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, event.(map[string]interface{}))
+	return nil
+}
+
+func (e *recordingEvents) AddMatch(ctx context.Context, match interface{}) error {
+	return e.AddEvent(ctx, match)
+}
+
+// recordingAudit records audit entries
+type recordingAudit struct {
+	actions []database.AuditAction
+	details []string
+}
+
+func (a *recordingAudit) LogAction(clientIP string, action database.AuditAction, details string, organizationID string) error {
+	a.actions = append(a.actions, action)
+	a.details = append(a.details, details)
+	return nil
+}
+
+// staticVectorDB returns the same matches for every search
+type staticVectorDB struct {
+	vectordb.MockVectorDB
+	matches []vectordb.Match
+}
+
+func (s *staticVectorDB) Search(ctx context.Context, queryVector []float32, topK int, organizationID string) ([]vectordb.Match, error) {
+	if topK < len(s.matches) {
+		return s.matches[:topK], nil
+	}
+	return s.matches, nil
+}
+
+func newContradictionTestPool(sender *recordingSender, events *recordingEvents) *AnalystPool {
+	vdb := &staticVectorDB{matches: []vectordb.Match{
+		{ID: "p1", DocumentID: "pricing-2024.pdf", Metadata: map[string]string{"content": "The price is $10 per seat."}},
+	}}
+	pool := NewAnalystPool(nil, sender, &recordingGraph{}, vdb, embeddings.NewMockEmbedder(8), events, events, 1)
+	pool.askQuestion = func(ctx context.Context, prompt string) (string, error) {
+		return "YES The documents state different prices.", nil
+	}
+	return pool
+}
+
+func TestCheckContradictions_NotifiesWhenEnabled(t *testing.T) {
+	var webhookPayload map[string]string
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&webhookPayload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer webhook.Close()
+
+	sender := &recordingSender{}
+	events := &recordingEvents{}
+	audit := &recordingAudit{}
+	pool := newContradictionTestPool(sender, events)
+	pool.SetAuditLogger(audit)
+	pool.SetContradictionAlerts(ContradictionAlertConfig{Enabled: true, WebhookURL: webhook.URL})
+
+	job := AnalystJob{
+		FilePath:       "pricing-2025.pdf",
+		Metadata:       map[string]string{"filename": "pricing-2025.pdf"},
+		ClientID:       "client-1",
+		OrganizationID: "org-a",
+	}
+	pool.checkContradictions(job, "The price is $12 per seat.")
+
+	if len(sender.types) != 1 || sender.types[0] != "CONTRADICTION" {
+		t.Fatalf("Expected one CONTRADICTION notification, got %v", sender.types)
+	}
+	msg := sender.notifications[0]
+	if !strings.Contains(msg, "pricing-2025.pdf") || !strings.Contains(msg, "pricing-2024.pdf") || !strings.Contains(msg, "different prices") {
+		t.Errorf("Notification should name both documents and the explanation, got %q", msg)
+	}
+
+	if len(events.events) != 1 || events.events[0]["EventType"] != "contradiction" || events.events[0]["OrganizationID"] != "org-a" {
+		t.Errorf("Expected a contradiction rule-event for org-a, got %v", events.events)
+	}
+	if len(audit.actions) != 1 || audit.actions[0] != database.AuditActionContradiction {
+		t.Errorf("Expected a CONTRADICTION audit entry, got %v", audit.actions)
+	}
+	if webhookPayload["target_document"] != "pricing-2024.pdf" || webhookPayload["source_document"] != "pricing-2025.pdf" {
+		t.Errorf("Unexpected webhook payload: %v", webhookPayload)
+	}
+}
+
+func TestCheckContradictions_SilentWhenDisabled(t *testing.T) {
+	sender := &recordingSender{}
+	events := &recordingEvents{}
+	graph := &recordingGraph{}
+	pool := newContradictionTestPool(sender, events)
+	pool.graphStore = graph
+
+	job := AnalystJob{
+		FilePath: "pricing-2025.pdf",
+		Metadata: map[string]string{"filename": "pricing-2025.pdf"},
+	}
+	pool.checkContradictions(job, "The price is $12 per seat.")
+
+	if len(graph.edges) != 1 {
+		t.Errorf("Expected the contradiction edge to still be stored, got %v", graph.edges)
+	}
+	if len(sender.notifications) != 0 || len(events.events) != 0 {
+		t.Errorf("Expected no notifications or events with the flag off, got %v / %v", sender.notifications, events.events)
+	}
+}
+
+func TestCheckContradictions_SkipsOwnDocumentByID(t *testing.T) {
+	sender := &recordingSender{}
+	events := &recordingEvents{}
+	graph := &recordingGraph{}
+	vdb := &staticVectorDB{matches: []vectordb.Match{
+		{ID: "p0", DocumentID: "doc-2025", Metadata: map[string]string{"filename": "pricing-2025.pdf", "content": "The price is $12 per seat."}},
+		{ID: "p1", DocumentID: "doc-2024", Metadata: map[string]string{"filename": "pricing-2024.pdf", "content": "The price is $10 per seat."}},
+	}}
+	pool := NewAnalystPool(nil, sender, graph, vdb, embeddings.NewMockEmbedder(8), events, events, 1)
+	pool.askQuestion = func(ctx context.Context, prompt string) (string, error) {
+		return "YES The documents state different prices.", nil
+	}
+	pool.SetContradictionAlerts(ContradictionAlertConfig{Enabled: true})
+
+	job := AnalystJob{
+		DocumentID: "doc-2025",
+		ClientID:   "client-1",
+		FilePath:   "/data/pricing-2025.pdf",
+		Metadata:   map[string]string{"filename": "pricing-2025.pdf"},
+	}
+	pool.checkContradictions(job, "The price is $12 per seat.")
+
+	if len(graph.edges) != 1 {
+		t.Fatalf("Expected only the other document to be compared, got edges %v", graph.edges)
+	}
+	if len(sender.notifications) != 1 {
+		t.Fatalf("Expected one notification, got %v", sender.notifications)
+	}
+	msg := sender.notifications[0]
+	if !strings.Contains(msg, "pricing-2024.pdf") || strings.Contains(msg, "doc-2024") {
+		t.Errorf("Notification should name the related document by filename, got %q", msg)
+	}
+}
+
+// longDocument builds a document of roughly n sentences with the clause at the given sentence index
+func longDocument(n, clauseAt int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		if i == clauseAt {
+			sb.WriteString("The vendor may terminate without notice. ")
+			continue
+		}
+		sb.WriteString("This paragraph covers routine obligations of both parties. ")
+	}
+	return sb.String()
+}
+
+func TestCheckRuleSingleDocument_ChunkedAnalysisFindsLateSegment(t *testing.T) {
+	events := &recordingEvents{}
+	sender := &recordingSender{}
+	pool := NewAnalystPool(nil, sender, nil, nil, nil, events, nil, 1)
+	pool.SetAnalysisLimits(1000, 40)
+
+	var calls int
+	pool.askQuestion = func(ctx context.Context, prompt string) (string, error) {
+		calls++
+		if len(prompt) > 1500 {
+			t.Errorf("Prompt of %d chars exceeds the segment limit", len(prompt))
+		}
+		if strings.Contains(prompt, "terminate without notice") {
+			return "YES\nThe vendor can terminate without notice.", nil
+		}
+		return "NO", nil
+	}
+
+	content := longDocument(200, 180)
+	pool.checkRuleSingleDocument(rules.Rule{ID: 7, Query: "Can the contract be ended early?"}, content, AnalystJob{ClientID: "drone-1"}, "contract.pdf")
+
+	if calls < 2 {
+		t.Fatalf("Expected the oversized document to be analyzed in segments, got %d call(s)", calls)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("Expected one rule match, got %d", len(events.events))
+	}
+	match := events.events[0]
+	if !strings.HasPrefix(match["AIExplanation"].(string), "Segment ") {
+		t.Errorf("Expected the explanation to name the matching segment, got %q", match["AIExplanation"])
+	}
+	evidence := match["MatchedChunks"].([]string)
+	if len(evidence) != 1 || !strings.Contains(evidence[0], "terminate without notice") {
+		t.Errorf("Expected the matching segment as evidence, got %v", evidence)
+	}
+	if len(sender.notifications) != 1 {
+		t.Errorf("Expected a rule-hit notification, got %v", sender.notifications)
+	}
+}
+
+func TestCheckRuleCrossDocument_SkipsLowSimilarityDocuments(t *testing.T) {
+	vdb := &staticVectorDB{matches: []vectordb.Match{
+		{ID: "p1", DocumentID: "msa-2024.pdf", Score: 0.91, Metadata: map[string]string{"content": "Payment is due in 30 days."}},
+		{ID: "p2", DocumentID: "sow-2024.pdf", Score: 0.78, Metadata: map[string]string{"content": "Invoices are paid within 45 days."}},
+		{ID: "p3", DocumentID: "lunch-menu.pdf", Score: 0.21, Metadata: map[string]string{"content": "Tacos on Tuesday."}},
+		{ID: "p4", DocumentID: "holiday-calendar.pdf", Score: 0.12, Metadata: map[string]string{"content": "Office closed on the 25th."}},
+	}}
+	events := &recordingEvents{}
+	pool := NewAnalystPool(nil, &recordingSender{}, nil, vdb, embeddings.NewMockEmbedder(8), events, events, 1)
+	pool.SetCrossDocScope(10, 0.5)
+
+	var compared []string
+	pool.askQuestion = func(ctx context.Context, prompt string) (string, error) {
+		for _, match := range vdb.matches {
+			if strings.Contains(prompt, match.Metadata["content"]) {
+				compared = append(compared, match.DocumentID)
+			}
+		}
+		return "NO", nil
+	}
+
+	pool.checkRuleCrossDocument(rules.Rule{ID: 3, Query: "Do the payment terms conflict?"}, "Net 60 payment terms.", AnalystJob{ClientID: "drone-1", OrganizationID: "org-a"}, "contract.pdf")
+
+	if len(compared) != 2 || compared[0] != "msa-2024.pdf" || compared[1] != "sow-2024.pdf" {
+		t.Fatalf("Expected only the two related documents to be compared, got %v", compared)
+	}
+	var summary string
+	for _, event := range events.events {
+		if event["EventType"] == "compared" {
+			summary = event["Message"].(string)
+		}
+	}
+	if !strings.Contains(summary, "Compared with 2 related documents, skipped 2") {
+		t.Errorf("Expected the compared and skipped counts to be recorded, got %q", summary)
+	}
+
+	// The target cap bounds the comparisons even when every match is related
+	compared = nil
+	pool.SetCrossDocScope(1, 0)
+	pool.checkRuleCrossDocument(rules.Rule{ID: 3, Query: "Do the payment terms conflict?"}, "Net 60 payment terms.", AnalystJob{ClientID: "drone-1", OrganizationID: "org-a"}, "contract.pdf")
+	if len(compared) != 1 {
+		t.Errorf("Expected the comparison to be capped at 1 document, got %v", compared)
+	}
+}
+
+func TestAnalyzeDocument_BoundsSegmentCalls(t *testing.T) {
+	pool := NewAnalystPool(nil, nil, nil, nil, nil, nil, nil, 1)
+	pool.SetAnalysisLimits(1000, 3)
+
+	var calls int
+	pool.askQuestion = func(ctx context.Context, prompt string) (string, error) {
+		calls++
+		if strings.Contains(prompt, "terminate without notice") {
+			return "YES\nfound", nil
+		}
+		return "NO", nil
+	}
+
+	answer, _, _, err := pool.analyzeDocument("Can the contract be ended early?", longDocument(200, 180))
+	if err != nil {
+		t.Fatalf("analyzeDocument failed: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected segment calls to be capped at 3, got %d", calls)
+	}
+	if answer != "NO" {
+		t.Errorf("Expected NO when the match is beyond the segment cap, got %q", answer)
+	}
+}
+
+func TestCheckRuleSingleDocument_CachesIdenticalAnalysis(t *testing.T) {
+	events := &recordingEvents{}
+	pool := NewAnalystPool(nil, nil, nil, nil, nil, events, nil, 1)
+
+	var calls int
+	pool.askQuestion = func(ctx context.Context, prompt string) (string, error) {
+		calls++
+		return "YES\nThe vendor can terminate without notice.", nil
+	}
+
+	rule := rules.Rule{ID: 7, Query: "Can the contract be ended early?"}
+	content := "The vendor may terminate without notice."
+	pool.checkRuleSingleDocument(rule, content, AnalystJob{}, "contract.pdf")
+	pool.checkRuleSingleDocument(rule, content, AnalystJob{}, "contract-copy.pdf")
+
+	if calls != 1 {
+		t.Fatalf("Expected the second identical analysis to skip the AI call, got %d call(s)", calls)
+	}
+	if len(events.events) != 2 || events.events[1]["AIExplanation"] != "The vendor can terminate without notice." {
+		t.Errorf("Expected the cached answer to still record a match, got %v", events.events)
+	}
+
+	// Editing the rule's question invalidates its cached answers
+	rule.Query = "Can the vendor terminate early?"
+	pool.checkRuleSingleDocument(rule, content, AnalystJob{}, "contract.pdf")
+	if calls != 2 {
+		t.Errorf("Expected a changed rule query to ask the AI again, got %d call(s)", calls)
+	}
+
+	// Entries expire after the TTL
+	pool.analysisCache.now = func() time.Time { return time.Now().Add(DefaultAnalysisCacheTTL + time.Minute) }
+	pool.checkRuleSingleDocument(rule, content, AnalystJob{}, "contract.pdf")
+	if calls != 3 {
+		t.Errorf("Expected an expired answer to ask the AI again, got %d call(s)", calls)
+	}
+}
+
+func TestRequiresCrossDocument_HonorsExplicitScope(t *testing.T) {
+	pool := NewAnalystPool(nil, &recordingSender{}, nil, nil, nil, nil, nil, 1)
+	tests := []struct {
+		query string
+		scope string
+		want  bool
+	}{
+		{"Does this document contradict existing policies?", rules.ScopeAuto, true},
+		{"Does the contract allow early termination?", rules.ScopeAuto, false},
+		{"Does the contract allow early termination?", rules.ScopeCrossDoc, true},
+		{"Does this document contradict its own appendix?", rules.ScopeSingleDoc, false},
+		{"Does this document contradict existing policies?", "", true},
+	}
+	for _, tt := range tests {
+		if got := pool.requiresCrossDocument(rules.Rule{Query: tt.query, Scope: tt.scope}); got != tt.want {
+			t.Errorf("requiresCrossDocument(%q, scope=%q) = %v, want %v", tt.query, tt.scope, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/the-hive/internal/queue"
+)
+
+// Pools whose jobs are recorded when they don't complete
+const (
+	PoolAnalyst = "analyst"
+	PoolTagger  = "tagger"
+	PoolQueue   = "queue" // The Redis job queue run by StartWorkers and StartKeyedWorkers
+)
+
+// Why a job didn't complete
+const (
+	JobDropped     = "dropped"      // The pool's queue was full
+	JobFailed      = "failed"       // The handler returned an error
+	JobUnknownType = "unknown_type" // No handler for the job type
+)
+
+// ErrUnknownJobType is returned (wrapped) by a queue handler for a job type it doesn't handle
+var ErrUnknownJobType = errors.New("unknown job type")
+
+// recentJobFailures is how many of the latest failures are kept for the admin API
+const recentJobFailures = 50
+
+// JobFailure is the structured record of a job that was dropped, failed or had an unknown type
+type JobFailure struct {
+	Time           time.Time `json:"time"`
+	Pool           string    `json:"pool"`
+	Outcome        string    `json:"outcome"`
+	JobType        string    `json:"job_type"`
+	Subject        string    `json:"subject,omitempty"` // File, chunk or job key the job was for
+	OrganizationID string    `json:"organization_id,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// JobFailureCount is how many jobs of one pool ended with one outcome
+type JobFailureCount struct {
+	Pool    string `json:"pool"`
+	Outcome string `json:"outcome"`
+	Count   int64  `json:"count"`
+}
+
+// JobFailureRecorder logs every job that doesn't complete as one JSON line, counts it per pool and
+// outcome, and stores it as a rule event when an event store is set, so no job fails silently.
+// A nil recorder still logs.
+type JobFailureRecorder struct {
+	mu         sync.Mutex
+	counts     map[[2]string]int64 // [pool, outcome] -> count
+	recent     []JobFailure        // Latest failures, oldest first
+	eventStore RuleEventStore
+}
+
+// NewJobFailureRecorder creates a recorder that logs and counts job failures
+func NewJobFailureRecorder() *JobFailureRecorder {
+	return &JobFailureRecorder{counts: make(map[[2]string]int64)}
+}
+
+// SetEventStore stores each failure as a rule event as well
+func (r *JobFailureRecorder) SetEventStore(eventStore RuleEventStore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.eventStore = eventStore
+}
+
+// Record logs, counts and stores a job failure
+func (r *JobFailureRecorder) Record(failure JobFailure) {
+	if failure.Time.IsZero() {
+		failure.Time = time.Now().UTC()
+	}
+	if line, err := json.Marshal(failure); err == nil {
+		log.Printf("[JOB_FAILURE] %s", line)
+	} else {
+		log.Printf("[JOB_FAILURE] %s job %s in %s pool: %s", failure.Outcome, failure.JobType, failure.Pool, failure.Error)
+	}
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	r.counts[[2]string{failure.Pool, failure.Outcome}]++
+	r.recent = append(r.recent, failure)
+	if len(r.recent) > recentJobFailures {
+		r.recent = r.recent[len(r.recent)-recentJobFailures:]
+	}
+	eventStore := r.eventStore
+	r.mu.Unlock()
+
+	if eventStore == nil {
+		return
+	}
+	message := fmt.Sprintf("%s job %s in the %s pool", failure.JobType, failure.Outcome, failure.Pool)
+	if failure.Error != "" {
+		message += ": " + failure.Error
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := eventStore.AddEvent(ctx, map[string]interface{}{
+		"RuleID":         0,
+		"RuleQuery":      "",
+		"Document":       failure.Subject,
+		"EventType":      "job_" + failure.Outcome,
+		"Status":         "failed",
+		"Message":        message,
+		"OrganizationID": failure.OrganizationID,
+	})
+	if err != nil {
+		log.Printf("[ERROR] Failed to store job failure event: %v", err)
+	}
+}
+
+// Counts returns how many jobs ended with each outcome, per pool
+func (r *JobFailureRecorder) Counts() []JobFailureCount {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make([]JobFailureCount, 0, len(r.counts))
+	for key, count := range r.counts {
+		counts = append(counts, JobFailureCount{Pool: key[0], Outcome: key[1], Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Pool != counts[j].Pool {
+			return counts[i].Pool < counts[j].Pool
+		}
+		return counts[i].Outcome < counts[j].Outcome
+	})
+	return counts
+}
+
+// Recent returns the latest failures, newest first
+func (r *JobFailureRecorder) Recent() []JobFailure {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	recent := make([]JobFailure, len(r.recent))
+	for i, failure := range r.recent {
+		recent[len(r.recent)-1-i] = failure
+	}
+	return recent
+}
+
+// RecordFailures wraps a queue handler so every job it fails, or doesn't know the type of
+// (ErrUnknownJobType), is recorded. The error is still returned to the worker loop.
+func RecordFailures(handler HandlerFunc, recorder *JobFailureRecorder) HandlerFunc {
+	return func(ctx context.Context, job queue.Job) error {
+		err := handler(ctx, job)
+		if err == nil {
+			return nil
+		}
+		outcome := JobFailed
+		if errors.Is(err, ErrUnknownJobType) {
+			outcome = JobUnknownType
+		}
+		recorder.Record(JobFailure{
+			Pool:    PoolQueue,
+			Outcome: outcome,
+			JobType: job.Type,
+			Subject: job.Key,
+			Error:   err.Error(),
+		})
+		return err
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/queue"
+)
+
+func TestTaggerPool_DroppedJobIsRecorded(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	events := &recordingEvents{}
+	recorder := NewJobFailureRecorder()
+	recorder.SetEventStore(events)
+
+	// Workers aren't started, so the queue fills up and the next job is dropped
+	pool := NewTaggerPool(1)
+	pool.SetFailureRecorder(recorder)
+	for i := 0; i < cap(pool.jobQueue); i++ {
+		pool.Enqueue(TaggingJob{ChunkID: fmt.Sprintf("chunk-%d", i)})
+	}
+	pool.Enqueue(TaggingJob{ChunkID: "chunk-overflow"})
+
+	counts := recorder.Counts()
+	if len(counts) != 1 || counts[0] != (JobFailureCount{Pool: PoolTagger, Outcome: JobDropped, Count: 1}) {
+		t.Fatalf("Expected one dropped tagger job to be counted, got %+v", counts)
+	}
+
+	var line string
+	for _, l := range strings.Split(logs.String(), "\n") {
+		if i := strings.Index(l, "[JOB_FAILURE] "); i >= 0 {
+			line = l[i+len("[JOB_FAILURE] "):]
+		}
+	}
+	var logged JobFailure
+	if err := json.Unmarshal([]byte(line), &logged); err != nil {
+		t.Fatalf("Expected a JSON job failure log line, got %q: %v", logs.String(), err)
+	}
+	if logged.Pool != PoolTagger || logged.Outcome != JobDropped || logged.Subject != "chunk-overflow" || logged.Time.IsZero() {
+		t.Errorf("Expected the dropped job's details in the log line, got %+v", logged)
+	}
+
+	if len(events.events) != 1 || events.events[0]["EventType"] != "job_dropped" || events.events[0]["Document"] != "chunk-overflow" {
+		t.Errorf("Expected a job_dropped event for the chunk, got %+v", events.events)
+	}
+	if recent := recorder.Recent(); len(recent) != 1 || recent[0].Subject != "chunk-overflow" {
+		t.Errorf("Expected the dropped job in the recent failures, got %+v", recent)
+	}
+}
+
+func TestRecordFailures_CountsUnknownAndFailedJobs(t *testing.T) {
+	recorder := NewJobFailureRecorder()
+	handler := RecordFailures(func(ctx context.Context, job queue.Job) error {
+		switch job.Type {
+		case "ok":
+			return nil
+		case "broken":
+			return errors.New("boom")
+		default:
+			return fmt.Errorf("%w: %s", ErrUnknownJobType, job.Type)
+		}
+	}, recorder)
+
+	for _, jobType := range []string{"ok", "broken", "mystery", "mystery"} {
+		handler(context.Background(), queue.Job{Type: jobType, Key: "org-a"})
+	}
+
+	want := []JobFailureCount{
+		{Pool: PoolQueue, Outcome: JobFailed, Count: 1},
+		{Pool: PoolQueue, Outcome: JobUnknownType, Count: 2},
+	}
+	counts := recorder.Counts()
+	if len(counts) != len(want) || counts[0] != want[0] || counts[1] != want[1] {
+		t.Errorf("Expected %+v, got %+v", want, counts)
+	}
+	if recent := recorder.Recent(); len(recent) != 3 || recent[0].JobType != "mystery" || recent[2].Error != "boom" {
+		t.Errorf("Expected the three failures newest first, got %+v", recent)
+	}
+}
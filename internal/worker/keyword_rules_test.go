@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package worker
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/the-hive/internal/database"
+	"github.com/the-hive/internal/rules"
+)
+
+func newKeywordTestPool(t *testing.T, store *rules.Store, sender *recordingSender, aiCalls *int) *AnalystPool {
+	t.Helper()
+	events := &recordingEvents{}
+	pool := NewAnalystPool(store, sender, nil, nil, nil, events, events, 1)
+	pool.askQuestion = func(ctx context.Context, prompt string) (string, error) {
+		*aiCalls++
+		return "NO", nil
+	}
+	return pool
+}
+
+func TestProcessJob_KeywordRuleAlertsOncePerDocument(t *testing.T) {
+	db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "hive.db"), database.DefaultSQLiteOptions())
+	if err != nil {
+		t.Fatalf("OpenSQLite failed: %v", err)
+	}
+	defer db.Close()
+	store, err := rules.NewStore(db)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+
+	sender := &recordingSender{}
+	aiCalls := 0
+	pool := newKeywordTestPool(t, store, sender, &aiCalls)
+
+	content := "CONFIDENTIAL pricing for Q4. Page 2: confidential discounts. Page 3: Confidential terms."
+
+	// HTTP ingest hands the analyst the whole document
+	pool.processJob(AnalystJob{
+		FilePath:       "pricing.txt",
+		Content:        content,
+		Metadata:       map[string]string{"filename": "pricing.txt"},
+		ClientID:       "drone-1",
+		OrganizationID: "org-a",
+	})
+	// gRPC ingest hands it the assembled document plus its chunks
+	pool.processJob(AnalystJob{
+		FilePath:       "pricing-grpc.txt",
+		Content:        content,
+		Metadata:       map[string]string{"filename": "pricing-grpc.txt"},
+		ClientID:       "drone-1",
+		AllChunks:      strings.SplitAfter(content, "."),
+		OrganizationID: "org-a",
+	})
+
+	if len(sender.notifications) != 2 {
+		t.Fatalf("Expected exactly one alert per document, got %d: %v", len(sender.notifications), sender.notifications)
+	}
+	if !strings.Contains(sender.notifications[0], "pricing.txt") || !strings.Contains(sender.notifications[1], "pricing-grpc.txt") {
+		t.Errorf("Unexpected alerts: %v", sender.notifications)
+	}
+	if aiCalls != 0 {
+		t.Errorf("Keyword rules must not call the AI, got %d calls", aiCalls)
+	}
+
+	seeded, err := store.GetAllRules("org-a")
+	if err != nil {
+		t.Fatalf("GetAllRules failed: %v", err)
+	}
+	if len(seeded) != 1 || seeded[0].Kind != rules.KindKeyword || seeded[0].Query != "CONFIDENTIAL" {
+		t.Fatalf("Expected one seeded keyword rule, got %+v", seeded)
+	}
+
+	// Deactivating the seeded rule silences it, even after a restart re-runs seeding
+	if err := store.UpdateRule(context.Background(), seeded[0].ID, seeded[0].Query, false, seeded[0].Scope); err != nil {
+		t.Fatalf("UpdateRule failed: %v", err)
+	}
+	restarted := newKeywordTestPool(t, store, sender, &aiCalls)
+	restarted.processJob(AnalystJob{FilePath: "later.txt", Content: content, ClientID: "drone-1", OrganizationID: "org-a"})
+	if len(sender.notifications) != 2 {
+		t.Errorf("Expected no alert from a deactivated keyword rule, got %v", sender.notifications)
+	}
+	if all, _ := store.GetAllRules("org-a"); len(all) != 1 {
+		t.Errorf("Expected seeding to leave the deactivated rule alone, got %+v", all)
+	}
+}
+
+func TestProcessJob_KeywordRulesOnlySkipsAIRules(t *testing.T) {
+	db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "hive.db"), database.DefaultSQLiteOptions())
+	if err != nil {
+		t.Fatalf("OpenSQLite failed: %v", err)
+	}
+	defer db.Close()
+	store, err := rules.NewStore(db)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	if _, err := store.AddRule(context.Background(), "Does the document mention pricing?", true, "", "org-a"); err != nil {
+		t.Fatalf("AddRule failed: %v", err)
+	}
+
+	sender := &recordingSender{}
+	aiCalls := 0
+	pool := newKeywordTestPool(t, store, sender, &aiCalls)
+
+	// A document sampled out of AI analysis still raises keyword alerts
+	pool.processJob(AnalystJob{
+		FilePath:         "pricing.txt",
+		Content:          "CONFIDENTIAL pricing for Q4.",
+		ClientID:         "drone-1",
+		OrganizationID:   "org-a",
+		KeywordRulesOnly: true,
+	})
+
+	if len(sender.notifications) != 1 || !strings.Contains(sender.notifications[0], "CONFIDENTIAL") {
+		t.Errorf("Expected the keyword alert, got %v", sender.notifications)
+	}
+	if aiCalls != 0 {
+		t.Errorf("Expected no AI calls for a keyword-only job, got %d", aiCalls)
+	}
+}
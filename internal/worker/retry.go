@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/the-hive/internal/queue"
+)
+
+// RetryPolicy is how often a failing queue job is retried before it is dead-lettered
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts in total, including the first
+	BaseDelay   time.Duration // Wait before the first retry; doubled for each one after
+	MaxDelay    time.Duration // Cap on the wait between attempts; zero for no cap
+}
+
+// DefaultRetryPolicy runs a job up to 3 times, waiting 1s and then 2s between attempts
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 30 * time.Second}
+
+// RetryPolicyFromEnv reads JOB_MAX_ATTEMPTS and JOB_RETRY_BASE_DELAY, falling back to DefaultRetryPolicy
+func RetryPolicyFromEnv() RetryPolicy {
+	policy := DefaultRetryPolicy
+	if v := os.Getenv("JOB_MAX_ATTEMPTS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			policy.MaxAttempts = n
+		} else {
+			log.Printf("Invalid JOB_MAX_ATTEMPTS %q, using default %d", v, DefaultRetryPolicy.MaxAttempts)
+		}
+	}
+	if v := os.Getenv("JOB_RETRY_BASE_DELAY"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			policy.BaseDelay = d
+		} else {
+			log.Printf("Invalid JOB_RETRY_BASE_DELAY %q, using default %s", v, DefaultRetryPolicy.BaseDelay)
+		}
+	}
+	return policy
+}
+
+// delay returns the wait after the given failed attempt (1-based)
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// RetryJobs wraps a queue handler so a failing job is retried with exponential backoff, in the same
+// worker so keyed ordering holds. Once the attempts are used up the job is dead-lettered on q with
+// its error and attempt count, and the error is returned. Jobs of an unknown type (ErrUnknownJobType)
+// can never succeed and are dead-lettered without retrying.
+func RetryJobs(handler HandlerFunc, q queue.Queue, policy RetryPolicy) HandlerFunc {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return func(ctx context.Context, job queue.Job) error {
+		var err error
+		attempts := 0
+		for attempts < policy.MaxAttempts {
+			attempts++
+			if err = handler(ctx, job); err == nil {
+				return nil
+			}
+			if errors.Is(err, ErrUnknownJobType) || attempts == policy.MaxAttempts {
+				break
+			}
+
+			delay := policy.delay(attempts)
+			log.Printf("RetryJobs: job type=%s attempt %d/%d failed, retrying in %s: %v", job.Type, attempts, policy.MaxAttempts, delay, err)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return err
+			}
+		}
+
+		if dlErr := queue.DeadLetter(context.Background(), q, job, err, attempts); dlErr != nil {
+			log.Printf("RetryJobs: failed to dead-letter job type=%s: %v", job.Type, dlErr)
+		}
+		return fmt.Errorf("failed after %d attempts: %w", attempts, err)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/the-hive/internal/queue"
+)
+
+// deadLetterQueue is a memoryQueue that keeps dead-lettered jobs
+type deadLetterQueue struct {
+	memoryQueue
+	mu   sync.Mutex
+	dead []queue.DeadJob
+}
+
+func (q *deadLetterQueue) DeadLetter(ctx context.Context, job queue.Job, cause error, attempts int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.dead = append(q.dead, queue.DeadJob{Job: job, Error: cause.Error(), Attempts: attempts, FailedAt: time.Now()})
+	return nil
+}
+
+func (q *deadLetterQueue) DeadLetters(ctx context.Context, limit int) ([]queue.DeadJob, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]queue.DeadJob(nil), q.dead...), nil
+}
+
+func TestRetryJobs_PermanentFailureIsDeadLettered(t *testing.T) {
+	q := &deadLetterQueue{memoryQueue: memoryQueue{jobs: make(chan queue.Job, 10)}}
+	var attempts int32
+	handled := make(chan struct{})
+	handler := RetryJobs(func(ctx context.Context, job queue.Job) error {
+		atomic.AddInt32(&attempts, 1)
+		return errors.New("downstream unavailable")
+	}, q, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- StartWorkers(ctx, q, func(ctx context.Context, job queue.Job) error {
+			defer close(handled)
+			return handler(ctx, job)
+		}, 1)
+	}()
+	q.Enqueue(ctx, queue.Job{Type: "recalc_issue_priority", Key: "issue-7"})
+
+	select {
+	case <-handled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the job to be handled")
+	}
+	cancel()
+	<-done
+
+	if n := atomic.LoadInt32(&attempts); n != 3 {
+		t.Errorf("Expected 3 attempts, got %d", n)
+	}
+	dead, _ := q.DeadLetters(context.Background(), 0)
+	if len(dead) != 1 {
+		t.Fatalf("Expected the job on the dead-letter list, got %+v", dead)
+	}
+	if dead[0].Job.Key != "issue-7" || dead[0].Attempts != 3 || dead[0].Error != "downstream unavailable" {
+		t.Errorf("Expected the job with its error and attempt count, got %+v", dead[0])
+	}
+}
+
+func TestRetryJobs_RecoversAndSkipsUnknownTypes(t *testing.T) {
+	q := &deadLetterQueue{}
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	// A job that fails once then succeeds isn't dead-lettered
+	calls := 0
+	err := RetryJobs(func(ctx context.Context, job queue.Job) error {
+		if calls++; calls == 1 {
+			return errors.New("transient")
+		}
+		return nil
+	}, q, policy)(context.Background(), queue.Job{Type: "test_job"})
+	if err != nil || calls != 2 {
+		t.Errorf("Expected success on the second attempt, got err=%v after %d calls", err, calls)
+	}
+
+	// An unknown job type can never succeed, so it is dead-lettered without retrying
+	calls = 0
+	err = RetryJobs(func(ctx context.Context, job queue.Job) error {
+		calls++
+		return fmt.Errorf("%w: %s", ErrUnknownJobType, job.Type)
+	}, q, policy)(context.Background(), queue.Job{Type: "mystery"})
+	if !errors.Is(err, ErrUnknownJobType) || calls != 1 {
+		t.Errorf("Expected one attempt with ErrUnknownJobType kept, got err=%v after %d calls", err, calls)
+	}
+	if dead, _ := q.DeadLetters(context.Background(), 0); len(dead) != 1 || dead[0].Job.Type != "mystery" || dead[0].Attempts != 1 {
+		t.Errorf("Expected only the unknown job dead-lettered, got %+v", dead)
+	}
+}
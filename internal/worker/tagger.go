@@ -27,6 +27,8 @@ type TaggerPool struct {
 	workerCount int
 	ctx         context.Context
 	cancel      context.CancelFunc
+	vocabulary  []string            // Allowed tags; empty allows any tag
+	failures    *JobFailureRecorder // Records dropped and failed jobs; nil only logs them
 }
 
 // NewTaggerPool creates a new tagging worker pool
@@ -48,6 +50,35 @@ func (p *TaggerPool) Start() {
 	log.Printf("Started %d tagging workers", p.workerCount)
 }
 
+// SetVocabulary restricts tagging to the given tags (e.g. "#legal"); an empty vocabulary allows any tag
+func (p *TaggerPool) SetVocabulary(tags []string) {
+	vocabulary := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = normalizeTag(tag); tag != "#" {
+			vocabulary = append(vocabulary, tag)
+		}
+	}
+	p.vocabulary = vocabulary
+}
+
+// SetFailureRecorder records jobs that are dropped or fail
+func (p *TaggerPool) SetFailureRecorder(recorder *JobFailureRecorder) {
+	p.failures = recorder
+}
+
+// Vocabulary returns the tags tagging is restricted to
+func (p *TaggerPool) Vocabulary() []string {
+	return p.vocabulary
+}
+
+// Tag returns tags for the content using the current vocabulary (used by bulk re-tagging)
+func (p *TaggerPool) Tag(content string) ([]string, error) {
+	if len(content) > 2000 {
+		content = content[:2000]
+	}
+	return p.askAIForTags(content)
+}
+
 // Stop stops the tagging worker pool
 func (p *TaggerPool) Stop() {
 	p.cancel()
@@ -61,7 +92,13 @@ func (p *TaggerPool) Enqueue(job TaggingJob) {
 	case p.jobQueue <- job:
 		// Job enqueued successfully
 	default:
-		log.Printf("Warning: Tagging job queue full, dropping job for chunk %s", job.ChunkID)
+		p.failures.Record(JobFailure{
+			Pool:    PoolTagger,
+			Outcome: JobDropped,
+			JobType: "tag_chunk",
+			Subject: job.ChunkID,
+			Error:   "tagging job queue full",
+		})
 	}
 }
 
@@ -94,7 +131,13 @@ func (p *TaggerPool) processJob(job TaggingJob) {
 	// Ask AI for tags
 	tags, err := p.askAIForTags(snippet)
 	if err != nil {
-		log.Printf("[ERROR] Job failed: Failed to get tags for chunk %s: %v", job.ChunkID, err)
+		p.failures.Record(JobFailure{
+			Pool:    PoolTagger,
+			Outcome: JobFailed,
+			JobType: "tag_chunk",
+			Subject: job.ChunkID,
+			Error:   fmt.Sprintf("failed to get tags: %v", err),
+		})
 		return
 	}
 
@@ -108,7 +151,13 @@ func (p *TaggerPool) processJob(job TaggingJob) {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		if err := job.VectorDB.UpdatePayload(ctx, job.ChunkID, tags); err != nil {
-			log.Printf("Failed to update payload for chunk %s: %v", job.ChunkID, err)
+			p.failures.Record(JobFailure{
+				Pool:    PoolTagger,
+				Outcome: JobFailed,
+				JobType: "tag_chunk",
+				Subject: job.ChunkID,
+				Error:   fmt.Sprintf("failed to update payload: %v", err),
+			})
 		} else {
 			log.Printf("Tagged chunk %s with tags: %v", job.ChunkID, tags)
 		}
@@ -118,12 +167,16 @@ func (p *TaggerPool) processJob(job TaggingJob) {
 // askAIForTags asks the AI to generate tags for the document
 func (p *TaggerPool) askAIForTags(content string) ([]string, error) {
 	// Construct the prompt
-	prompt := fmt.Sprintf(`Analyze this document and return a JSON array of up to 5 relevant tags (e.g., #legal, #invoice, #urgent, #proposal). Return ONLY the JSON array, no other text.
+	tagChoice := "relevant tags (e.g., #legal, #invoice, #urgent, #proposal)"
+	if len(p.vocabulary) > 0 {
+		tagChoice = "relevant tags chosen ONLY from this list: " + strings.Join(p.vocabulary, ", ")
+	}
+	prompt := fmt.Sprintf(`Analyze this document and return a JSON array of up to 5 %s. Return ONLY the JSON array, no other text.
 
 Document content:
 %s
 
-Return format: ["#tag1", "#tag2", "#tag3"]`, content)
+Return format: ["#tag1", "#tag2", "#tag3"]`, tagChoice, content)
 
 	// Use the AI service to get tags
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -134,7 +187,7 @@ Return format: ["#tag1", "#tag2", "#tag3"]`, content)
 	if err != nil {
 		// Fallback: simple keyword-based tagging
 		log.Printf("AI service unavailable, using fallback tagging: %v", err)
-		return p.fallbackTags(content), nil
+		return p.filterVocabulary(p.fallbackTags(content)), nil
 	}
 
 	// Parse JSON response
@@ -148,17 +201,14 @@ Return format: ["#tag1", "#tag2", "#tag3"]`, content)
 
 	if err := json.Unmarshal([]byte(answer), &tags); err != nil {
 		log.Printf("Failed to parse AI response as JSON: %v, response: %s", err, answer)
-		return p.fallbackTags(content), nil
+		return p.filterVocabulary(p.fallbackTags(content)), nil
 	}
 
 	// Ensure tags start with #
 	for i, tag := range tags {
-		tag = strings.TrimSpace(tag)
-		if !strings.HasPrefix(tag, "#") {
-			tag = "#" + tag
-		}
-		tags[i] = tag
+		tags[i] = normalizeTag(tag)
 	}
+	tags = p.filterVocabulary(tags)
 
 	// Limit to 5 tags
 	if len(tags) > 5 {
@@ -168,6 +218,32 @@ Return format: ["#tag1", "#tag2", "#tag3"]`, content)
 	return tags, nil
 }
 
+// filterVocabulary drops tags outside the vocabulary (case-insensitive), keeping the vocabulary's spelling
+func (p *TaggerPool) filterVocabulary(tags []string) []string {
+	if len(p.vocabulary) == 0 {
+		return tags
+	}
+	filtered := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		for _, allowed := range p.vocabulary {
+			if strings.EqualFold(tag, allowed) {
+				filtered = append(filtered, allowed)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// normalizeTag trims the tag and ensures it starts with #
+func normalizeTag(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if !strings.HasPrefix(tag, "#") {
+		tag = "#" + tag
+	}
+	return tag
+}
+
 // fallbackTags provides simple keyword-based tagging when AI is unavailable
 func (p *TaggerPool) fallbackTags(content string) []string {
 	contentLower := strings.ToLower(content)
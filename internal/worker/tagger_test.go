@@ -0,0 +1,21 @@
+// Copyright (c) 2025 Northbound System
+// Author: Nicholas Skitch
+package worker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTaggerPool_VocabularyFiltersTags(t *testing.T) {
+	pool := NewTaggerPool(1)
+	pool.SetVocabulary([]string{" #Legal", "finance", ""})
+
+	if want := []string{"#Legal", "#finance"}; !reflect.DeepEqual(pool.Vocabulary(), want) {
+		t.Fatalf("Vocabulary() = %v, want %v", pool.Vocabulary(), want)
+	}
+	got := pool.filterVocabulary([]string{"#legal", "#urgent", "#FINANCE"})
+	if want := []string{"#Legal", "#finance"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("filterVocabulary() = %v, want %v", got, want)
+	}
+}
@@ -4,6 +4,7 @@ package worker
 
 import (
 	"context"
+	"hash/fnv"
 	"log"
 	"sync"
 
@@ -13,7 +14,20 @@ import (
 // HandlerFunc processes a job. It should return an error if processing fails.
 type HandlerFunc func(ctx context.Context, job queue.Job) error
 
+// KeyFunc returns the ordering key for a job. Jobs with the same key are processed serially.
+type KeyFunc func(job queue.Job) string
+
+// keyedWorkerBuffer is the number of jobs buffered per keyed worker before the dispatcher blocks
+const keyedWorkerBuffer = 16
+
+// JobKey is the default KeyFunc; it returns the job's Key field.
+func JobKey(job queue.Job) string {
+	return job.Key
+}
+
 // StartWorkers starts a pool of workers that process jobs from the queue.
+// Cancelling ctx stops workers from taking new jobs; jobs already being handled run to completion
+// and StartWorkers returns once they have. Each job is acknowledged after its handler returns.
 // ctx: context for cancellation (workers will stop when context is cancelled)
 // q: the queue to dequeue jobs from
 // handler: function to process each job
@@ -42,6 +56,8 @@ func StartWorkers(ctx context.Context, q queue.Queue, handler HandlerFunc, worke
 // workerLoop is the main loop for a single worker.
 func workerLoop(ctx context.Context, q queue.Queue, handler HandlerFunc, workerID int) {
 	log.Printf("workerLoop: workerID=%d started", workerID)
+	// Handlers aren't interrupted by shutdown; the caller bounds how long it waits for them
+	handlerCtx := context.WithoutCancel(ctx)
 
 	for {
 		// Check if context is cancelled
@@ -66,7 +82,9 @@ func workerLoop(ctx context.Context, q queue.Queue, handler HandlerFunc, workerI
 		log.Printf("workerLoop: workerID=%d processing job type=%s createdAt=%s", workerID, job.Type, job.CreatedAt.Format("2006-01-02T15:04:05Z07:00"))
 
 		// Process the job
-		if err := handler(ctx, job); err != nil {
+		err = handler(handlerCtx, job)
+		ackJob(q, job)
+		if err != nil {
 			log.Printf("workerLoop: workerID=%d handler error for job type=%s: %v", workerID, job.Type, err)
 			continue
 		}
@@ -74,3 +92,98 @@ func workerLoop(ctx context.Context, q queue.Queue, handler HandlerFunc, workerI
 		log.Printf("workerLoop: workerID=%d successfully processed job type=%s", workerID, job.Type)
 	}
 }
+
+// ackJob acknowledges a handled job. Failed jobs are acknowledged too: RetryJobs has already retried
+// and dead-lettered them, and only jobs interrupted by a stop are recovered.
+func ackJob(q queue.Queue, job queue.Job) {
+	if err := queue.Ack(context.Background(), q, job); err != nil {
+		log.Printf("ackJob: failed to acknowledge job type=%s: %v", job.Type, err)
+	}
+}
+
+// StartKeyedWorkers starts a pool of workers that preserves per-key ordering.
+// A single dispatcher dequeues jobs and routes each one to a worker chosen by hashing its key,
+// so jobs with the same key run serially in dequeue order while different keys run in parallel.
+// Jobs with an empty key are spread across workers round-robin.
+// As with StartWorkers, cancelling ctx stops dequeuing and the jobs already dequeued are finished first.
+func StartKeyedWorkers(ctx context.Context, q queue.Queue, handler HandlerFunc, workerCount int, keyFunc KeyFunc) error {
+	log.Printf("StartKeyedWorkers: workerCount=%d", workerCount)
+	if workerCount < 1 {
+		workerCount = 1
+	}
+	if keyFunc == nil {
+		keyFunc = JobKey
+	}
+
+	channels := make([]chan queue.Job, workerCount)
+	handlerCtx := context.WithoutCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Add(workerCount)
+
+	for i := 0; i < workerCount; i++ {
+		workerID := i + 1
+		jobs := make(chan queue.Job, keyedWorkerBuffer)
+		channels[i] = jobs
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				err := handler(handlerCtx, job)
+				ackJob(q, job)
+				if err != nil {
+					log.Printf("keyedWorker: workerID=%d handler error for job type=%s key=%s: %v", workerID, job.Type, job.Key, err)
+					continue
+				}
+				log.Printf("keyedWorker: workerID=%d successfully processed job type=%s key=%s", workerID, job.Type, job.Key)
+			}
+		}()
+	}
+
+	dispatchLoop(ctx, q, channels, keyFunc)
+
+	// Let workers finish jobs already routed to them
+	for _, jobs := range channels {
+		close(jobs)
+	}
+	wg.Wait()
+	log.Printf("StartKeyedWorkers: all workers stopped")
+	return nil
+}
+
+// dispatchLoop dequeues jobs and routes them to worker channels until the context is cancelled.
+func dispatchLoop(ctx context.Context, q queue.Queue, channels []chan queue.Job, keyFunc KeyFunc) {
+	next := 0
+	for {
+		job, err := q.Dequeue(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				log.Printf("dispatchLoop: context cancelled, stopping")
+				return
+			}
+			log.Printf("dispatchLoop: dequeue error: %v, continuing", err)
+			continue
+		}
+
+		var target int
+		if key := keyFunc(job); key != "" {
+			target = workerForKey(key, len(channels))
+		} else {
+			target = next
+			next = (next + 1) % len(channels)
+		}
+
+		select {
+		case channels[target] <- job:
+		case <-ctx.Done():
+			// Left unacknowledged, so an acknowledging queue recovers it on the next start
+			log.Printf("dispatchLoop: context cancelled while dispatching job type=%s", job.Type)
+			return
+		}
+	}
+}
+
+// workerForKey maps a key to a stable worker index.
+func workerForKey(key string, workerCount int) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(workerCount))
+}
@@ -147,3 +147,194 @@ func TestStartWorkers_HandlerError(t *testing.T) {
 		t.Errorf("StartWorkers returned error: %v", err)
 	}
 }
+
+// memoryQueue is an in-memory queue for tests that don't need Redis
+type memoryQueue struct {
+	jobs chan queue.Job
+}
+
+func (m *memoryQueue) Enqueue(ctx context.Context, job queue.Job) error {
+	m.jobs <- job
+	return nil
+}
+
+func (m *memoryQueue) Dequeue(ctx context.Context) (queue.Job, error) {
+	select {
+	case job := <-m.jobs:
+		return job, nil
+	case <-ctx.Done():
+		return queue.Job{}, ctx.Err()
+	}
+}
+
+func TestStartKeyedWorkers_SameKeyInOrder(t *testing.T) {
+	ctx := context.Background()
+	q := &memoryQueue{jobs: make(chan queue.Job, 100)}
+
+	// Interleave jobs for several keys
+	keys := []string{"doc-a", "doc-b", "doc-c"}
+	perKey := 20
+	for i := 0; i < perKey; i++ {
+		for _, key := range keys {
+			q.Enqueue(ctx, queue.Job{Type: "test_job", Key: key, Payload: []byte(strconv.Itoa(i))})
+		}
+	}
+
+	var mu sync.Mutex
+	processed := make(map[string][]int)
+	running := make(map[string]bool)
+	var overlap bool
+	var wg sync.WaitGroup
+	wg.Add(len(keys) * perKey)
+
+	handler := func(ctx context.Context, job queue.Job) error {
+		defer wg.Done()
+		mu.Lock()
+		if running[job.Key] {
+			overlap = true
+		}
+		running[job.Key] = true
+		mu.Unlock()
+
+		// Give a concurrent same-key job the chance to overlap if ordering were broken
+		time.Sleep(time.Millisecond)
+
+		index, _ := strconv.Atoi(string(job.Payload))
+		mu.Lock()
+		processed[job.Key] = append(processed[job.Key], index)
+		running[job.Key] = false
+		mu.Unlock()
+		return nil
+	}
+
+	workerCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- StartKeyedWorkers(workerCtx, q, handler, 4, JobKey)
+	}()
+
+	wg.Wait()
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("StartKeyedWorkers returned error: %v", err)
+	}
+
+	if overlap {
+		t.Errorf("Jobs with the same key ran concurrently")
+	}
+	for _, key := range keys {
+		got := processed[key]
+		if len(got) != perKey {
+			t.Fatalf("Expected %d jobs for %s, got %d", perKey, key, len(got))
+		}
+		for i, index := range got {
+			if index != i {
+				t.Errorf("Jobs for %s ran out of order: %v", key, got)
+				break
+			}
+		}
+	}
+}
+
+func TestWorkerForKey_Stable(t *testing.T) {
+	for _, key := range []string{"issue:1", "org:abc", "x"} {
+		first := workerForKey(key, 5)
+		for i := 0; i < 10; i++ {
+			if workerForKey(key, 5) != first {
+				t.Fatalf("workerForKey(%q) is not stable", key)
+			}
+		}
+		if first < 0 || first >= 5 {
+			t.Errorf("workerForKey(%q) = %d out of range", key, first)
+		}
+	}
+}
+
+// ackingQueue is a memoryQueue that tracks jobs dequeued but not yet acknowledged, like RedisQueue's processing list
+type ackingQueue struct {
+	memoryQueue
+	mu       sync.Mutex
+	inFlight map[string]bool
+}
+
+func (a *ackingQueue) Dequeue(ctx context.Context) (queue.Job, error) {
+	job, err := a.memoryQueue.Dequeue(ctx)
+	if err == nil {
+		a.mu.Lock()
+		a.inFlight[job.Key] = true
+		a.mu.Unlock()
+	}
+	return job, err
+}
+
+func (a *ackingQueue) Ack(ctx context.Context, job queue.Job) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.inFlight, job.Key)
+	return nil
+}
+
+func (a *ackingQueue) unacknowledged() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.inFlight)
+}
+
+func TestStartWorkers_ShutdownFinishesJobInFlight(t *testing.T) {
+	for name, start := range map[string]func(context.Context, queue.Queue, HandlerFunc) error{
+		"pool": func(ctx context.Context, q queue.Queue, handler HandlerFunc) error {
+			return StartWorkers(ctx, q, handler, 2)
+		},
+		"keyed": func(ctx context.Context, q queue.Queue, handler HandlerFunc) error {
+			return StartKeyedWorkers(ctx, q, handler, 2, JobKey)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			q := &ackingQueue{memoryQueue: memoryQueue{jobs: make(chan queue.Job, 1)}, inFlight: map[string]bool{}}
+			q.Enqueue(context.Background(), queue.Job{Type: "test_job", Key: "doc-a"})
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			var handlerErr error
+			handler := func(ctx context.Context, job queue.Job) error {
+				close(started)
+				<-release
+				handlerErr = ctx.Err()
+				return nil
+			}
+
+			workerCtx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() {
+				done <- start(workerCtx, q, handler)
+			}()
+
+			<-started
+			cancel() // Shut down while the job is being handled
+			select {
+			case <-done:
+				t.Fatal("Expected the workers to wait for the job in flight")
+			case <-time.After(50 * time.Millisecond):
+			}
+			if q.unacknowledged() != 1 {
+				t.Fatal("Expected the job in flight to stay unacknowledged, so a stop now would leave it to be recovered")
+			}
+
+			close(release)
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Errorf("Workers returned error: %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Workers didn't stop after the job finished")
+			}
+			if handlerErr != nil {
+				t.Errorf("Expected the handler's context to outlive shutdown, got %v", handlerErr)
+			}
+			if q.unacknowledged() != 0 {
+				t.Error("Expected the finished job to be acknowledged")
+			}
+		})
+	}
+}